@@ -2,6 +2,19 @@
 // This source code is licensed under the Apache 2.0 license found
 // in the LICENSE file in the root directory of this source tree.
 
+// Package docker provides utilities for running ephemeral database containers, for
+// example, in integration tests. Containers are configured using the functions that
+// are named after the database they run, and can be customized with a custom image,
+// health-check, wait strategies and a reuse policy.
+//
+//	cfg, err := docker.PostgreSQL("16",
+//		docker.Reuse("atlas-pg16"),
+//		docker.WaitFor(docker.WaitLog("database system is ready to accept connections")),
+//	)
+//	c, err := cfg.Run(ctx)
+//	defer c.Close()
+//	err = c.Wait(ctx, time.Minute)
+//	u, err := c.URL()
 package docker
 
 import (
@@ -47,7 +60,31 @@ type (
 		Database string
 		// Out is a custom writer to send docker cli output to.
 		Out io.Writer
-	}
+		// Name of the container. Optional, unless the container is reused.
+		Name string
+		// Reuse indicates a running container with the same name should be
+		// reused instead of starting a new one. Reused containers are kept
+		// running when closed, and are removed only when they are stopped.
+		Reuse bool
+		// Health configures the health-check of the container, if set.
+		Health *HealthConfig
+		// WaitFor holds the strategies to wait for before the service is
+		// considered ready. They are executed in order, before the database
+		// is pinged and its setup statements are executed.
+		WaitFor []WaitStrategy
+	}
+	// HealthConfig configures the health-check docker runs in the container.
+	HealthConfig struct {
+		// Cmd is the command to run to check the container health.
+		Cmd string
+		// Interval, Timeout and Retries are optional and passed
+		// to docker only if they are set. For example, 1s.
+		Interval, Timeout time.Duration
+		Retries           int
+	}
+	// A WaitStrategy reports when the service running in the container is ready.
+	// It is called with the context passed to Container.Wait, limited by its timeout.
+	WaitStrategy func(context.Context, *Container) error
 	// A Container is an instance of a created container.
 	Container struct {
 		Config // Config used to create this container
@@ -55,6 +92,9 @@ type (
 		ID string
 		// Port on the host this containers service is bound to.
 		Port string
+		// Reused indicates the container was already running
+		// when Run was called, and its setup was skipped.
+		Reused bool
 	}
 	// ConfigOption allows configuring Config with functional arguments.
 	ConfigOption func(*Config) error
@@ -224,6 +264,7 @@ func MySQL(version string, opts ...ConfigOption) (*Config, error) {
 	return NewConfig(
 		append(
 			[]ConfigOption{
+				Driver(DriverMySQL),
 				Image(hubUser, "mysql:"+version),
 				Userinfo(url.UserPassword("root", pass)),
 				Port("3306"),
@@ -236,7 +277,7 @@ func MySQL(version string, opts ...ConfigOption) (*Config, error) {
 
 // MariaDB returns a new Config for a MariaDB image.
 func MariaDB(version string, opts ...ConfigOption) (*Config, error) {
-	return MySQL(version, append([]ConfigOption{Image(hubUser, "mariadb:"+version)}, opts...)...)
+	return MySQL(version, append([]ConfigOption{Driver(DriverMariaDB), Image(hubUser, "mariadb:"+version)}, opts...)...)
 }
 
 // PostgreSQL returns a new Config for a PostgreSQL image.
@@ -244,6 +285,7 @@ func PostgreSQL(version string, opts ...ConfigOption) (*Config, error) {
 	return NewConfig(
 		append(
 			[]ConfigOption{
+				Driver(DriverPostgres),
 				Image("postgres:" + version),
 				Userinfo(url.UserPassword("postgres", pass)),
 				Port("5432"),
@@ -260,6 +302,7 @@ func SQLServer(version string, opts ...ConfigOption) (*Config, error) {
 	return NewConfig(
 		append(
 			[]ConfigOption{
+				Driver(DriverSQLServer),
 				Image("mcr.microsoft.com/mssql/server:" + version),
 				Userinfo(url.UserPassword("sa", passSQLServer)),
 				Port("1433"),
@@ -280,6 +323,7 @@ func ClickHouse(version string, opts ...ConfigOption) (*Config, error) {
 	return NewConfig(
 		append(
 			[]ConfigOption{
+				Driver(DriverClickHouse),
 				Image("clickhouse/clickhouse-server:" + version),
 				Userinfo(url.UserPassword("default", pass)),
 				Port("9000"),
@@ -290,6 +334,18 @@ func ClickHouse(version string, opts ...ConfigOption) (*Config, error) {
 	)
 }
 
+// Driver sets the driver used to connect to the container service. It is required
+// for running custom images that are not created by the functions of this package.
+// For example:
+//
+//	NewConfig(Driver("postgres"), Image("timescale/timescaledb:latest-pg16"), Port("5432"))
+func Driver(name string) ConfigOption {
+	return func(c *Config) error {
+		c.driver = name
+		return nil
+	}
+}
+
 // Image sets the docker image to use. For example:
 //
 //	Image("mysql")
@@ -369,23 +425,136 @@ func Setup(s ...string) ConfigOption {
 	}
 }
 
+// Name sets the name of the container. For example:
+//
+//	Name("atlas-dev")
+func Name(name string) ConfigOption {
+	return func(c *Config) error {
+		c.Name = name
+		return nil
+	}
+}
+
+// Reuse names the container, and configures it to be reused by
+// later runs while it is running. For example:
+//
+//	Reuse("atlas-dev-mysql8")
+func Reuse(name string) ConfigOption {
+	return func(c *Config) error {
+		if name == "" {
+			return errors.New("reused containers must be named")
+		}
+		c.Name, c.Reuse = name, true
+		return nil
+	}
+}
+
+// HealthCheck sets the health-check of the container, and waits
+// for the container to become healthy before it is used. For example:
+//
+//	HealthCheck(HealthConfig{Cmd: "pg_isready -U postgres", Interval: time.Second})
+func HealthCheck(h HealthConfig) ConfigOption {
+	return func(c *Config) error {
+		if h.Cmd == "" {
+			return errors.New("health-check command cannot be empty")
+		}
+		c.Health = &h
+		c.WaitFor = append(c.WaitFor, WaitHealthy())
+		return nil
+	}
+}
+
+// WaitFor adds strategies to wait for before the service is considered ready. For example:
+//
+//	WaitFor(WaitLog("ready for connections"))
+//	WaitFor(WaitExec("pg_isready", "-U", "postgres"))
+func WaitFor(s ...WaitStrategy) ConfigOption {
+	return func(c *Config) error {
+		c.WaitFor = append(c.WaitFor, s...)
+		return nil
+	}
+}
+
+// WaitHealthy returns a WaitStrategy that waits for the
+// health-check of the container to report it is healthy.
+func WaitHealthy() WaitStrategy {
+	return func(ctx context.Context, c *Container) error {
+		return poll(ctx, func() (bool, error) {
+			out, err := exec.CommandContext(ctx, "docker", "inspect", "--format", "{{.State.Health.Status}}", c.ID).Output() //nolint:gosec
+			if err != nil {
+				return false, nil
+			}
+			switch status := strings.TrimSpace(string(out)); status {
+			case "healthy":
+				return true, nil
+			case "unhealthy":
+				return false, fmt.Errorf("container %s is unhealthy", c.ID)
+			default:
+				return false, nil
+			}
+		})
+	}
+}
+
+// WaitLog returns a WaitStrategy that waits for the given text to appear in the container logs.
+func WaitLog(text string) WaitStrategy {
+	return func(ctx context.Context, c *Container) error {
+		return poll(ctx, func() (bool, error) {
+			out, err := exec.CommandContext(ctx, "docker", "logs", c.ID).CombinedOutput() //nolint:gosec
+			return err == nil && bytes.Contains(out, []byte(text)), nil
+		})
+	}
+}
+
+// WaitExec returns a WaitStrategy that waits for the given
+// command to exit successfully when executed in the container.
+func WaitExec(cmd ...string) WaitStrategy {
+	return func(ctx context.Context, c *Container) error {
+		return poll(ctx, func() (bool, error) {
+			args := append([]string{"exec", c.ID}, cmd...)
+			return exec.CommandContext(ctx, "docker", args...).Run() == nil, nil //nolint:gosec
+		})
+	}
+}
+
+// poll calls f until it reports true, returns an error or the context is done.
+func poll(ctx context.Context, f func() (bool, error)) error {
+	for {
+		switch ok, err := f(); {
+		case err != nil:
+			return err
+		case ok:
+			return nil
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 // Run pulls and starts a new docker container from the Config.
 func (c *Config) Run(ctx context.Context) (*Container, error) {
 	// Make sure the configuration is not missing critical values.
 	if err := c.validate(); err != nil {
 		return nil, err
 	}
+	if c.Reuse {
+		switch ct, err := c.running(ctx); {
+		case err != nil:
+			return nil, err
+		case ct != nil:
+			return ct, nil
+		}
+	}
 	// Get a free host TCP port the container can bind its exposed service port on.
 	p, err := freePort()
 	if err != nil {
 		return nil, fmt.Errorf("getting open port: %w", err)
 	}
 	// Run the container.
-	args := []string{"docker", "run", "--rm", "--detach"}
-	for _, e := range c.Env {
-		args = append(args, "-e", e)
-	}
-	args = append(args, "-p", fmt.Sprintf("%s:%s", p, c.Port), c.Image)
+	args := c.runArgs(p)
 	cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec
 	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
 	cmd.Stdout, cmd.Stderr = io.MultiWriter(c.Out, stdout), stderr
@@ -402,8 +571,69 @@ func (c *Config) Run(ctx context.Context) (*Container, error) {
 	}, nil
 }
 
-// Close stops and removes this container.
+// runArgs returns the "docker run" arguments for
+// binding the service port to the given host port.
+func (c *Config) runArgs(hostPort string) []string {
+	args := []string{"docker", "run", "--rm", "--detach"}
+	if c.Name != "" {
+		args = append(args, "--name", c.Name)
+	}
+	for _, e := range c.Env {
+		args = append(args, "-e", e)
+	}
+	if h := c.Health; h != nil {
+		args = append(args, "--health-cmd", h.Cmd)
+		if h.Interval > 0 {
+			args = append(args, "--health-interval", h.Interval.String())
+		}
+		if h.Timeout > 0 {
+			args = append(args, "--health-timeout", h.Timeout.String())
+		}
+		if h.Retries > 0 {
+			args = append(args, "--health-retries", strconv.Itoa(h.Retries))
+		}
+	}
+	return append(args, "-p", fmt.Sprintf("%s:%s", hostPort, c.Port), c.Image)
+}
+
+// running returns the running container with the configured name, if it exists.
+func (c *Config) running(ctx context.Context) (*Container, error) {
+	out, err := exec.CommandContext(ctx, "docker", "ps", "--quiet", "--filter", "name=^/"+c.Name+"$").Output() //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("listing containers: %w", err)
+	}
+	id := strings.TrimSpace(string(out))
+	if id == "" {
+		return nil, nil
+	}
+	out, err = exec.CommandContext(ctx, "docker", "port", id, c.Port).Output() //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("getting port of container %q: %w", c.Name, err)
+	}
+	p, err := hostPort(string(out))
+	if err != nil {
+		return nil, fmt.Errorf("getting port of container %q: %w", c.Name, err)
+	}
+	return &Container{Config: *c, ID: id, Port: p, Reused: true}, nil
+}
+
+// hostPort extracts the host port from the output of "docker port".
+// For example, "0.0.0.0:49153\n[::]:49153" returns "49153".
+func hostPort(out string) (string, error) {
+	line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	idx := strings.LastIndexByte(line, ':')
+	if idx == -1 || idx == len(line)-1 {
+		return "", fmt.Errorf("unexpected port mapping %q", line)
+	}
+	return line[idx+1:], nil
+}
+
+// Close stops and removes this container. Reused
+// containers are kept running for the next runs.
 func (c *Container) Close() error {
+	if c.Reuse {
+		return nil
+	}
 	return exec.Command("docker", "kill", c.ID).Run() //nolint:gosec
 }
 
@@ -415,8 +645,18 @@ func (c *Container) Wait(ctx context.Context, timeout time.Duration) error {
 	if timeout > time.Minute {
 		timeout = time.Minute
 	}
+	start := time.Now()
+	if len(c.WaitFor) > 0 {
+		wctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		for _, w := range c.WaitFor {
+			if err := w(wctx, c); err != nil {
+				return fmt.Errorf("waiting for container: %w", err)
+			}
+		}
+	}
 	var (
-		done   = time.After(timeout)
+		done   = time.After(timeout - time.Since(start))
 		u, err = c.URL()
 	)
 	if err != nil {
@@ -436,7 +676,7 @@ func (c *Container) Wait(ctx context.Context, timeout time.Duration) error {
 			if err = db.PingContext(ctx); err != nil {
 				continue
 			}
-			for _, s := range c.setup {
+			for _, s := range c.setupStmts() {
 				if _, err := db.ExecContext(ctx, s); err != nil {
 					err = errors.Join(err, db.Close())
 					return fmt.Errorf("%q: %w", s, err)
@@ -456,6 +696,15 @@ func (c *Container) Wait(ctx context.Context, timeout time.Duration) error {
 	}
 }
 
+// setupStmts returns the statements to execute once the service is ready.
+// Reused containers were already set up by the run that started them.
+func (c *Container) setupStmts() []string {
+	if c.Reused {
+		return nil
+	}
+	return c.setup
+}
+
 // URL returns a URL to connect to the Container.
 func (c *Container) URL() (*url.URL, error) {
 	host := "localhost"
@@ -510,7 +759,7 @@ func (c *Container) PingURL(u url.URL) string {
 // validate that no empty values are given.
 func (c *Config) validate() error {
 	if c == nil || c.Image == "" || c.Port == "" || c.Out == nil {
-		return fmt.Errorf("invalid configuration %q", c)
+		return fmt.Errorf("invalid configuration %+v", c)
 	}
 	return nil
 }
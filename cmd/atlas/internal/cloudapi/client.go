@@ -365,13 +365,13 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("unexpected error code %d: %s", e.StatusCode, e.Message)
 }
 
-// RedactedURL returns a URL string with the userinfo redacted.
+// RedactedURL returns a URL string with its credentials redacted.
 func RedactedURL(s string) (string, error) {
 	u, err := sqlclient.ParseURL(s)
 	if err != nil {
 		return "", err
 	}
-	return u.Redacted(), nil
+	return sqlclient.RedactURL(u), nil
 }
 
 // version of the CLI set by cmdapi.
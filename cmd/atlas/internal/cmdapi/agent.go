@@ -0,0 +1,264 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/spf13/cobra"
+)
+
+type agentFlags struct {
+	interval    time.Duration // Interval between drift checks.
+	once        bool          // Run the checks once and exit.
+	webhooks    []string      // URLs to post the results of the checks to.
+	metricsAddr string        // Address to expose the Prometheus metrics on.
+}
+
+// agentCmd represents the 'atlas agent' command.
+func agentCmd() *cobra.Command {
+	var (
+		flags agentFlags
+		cmd   = &cobra.Command{
+			Use:   "agent",
+			Short: "Periodically check the selected environments for schema drift.",
+			Long: `'atlas agent' runs in the foreground and periodically checks the databases of the selected
+environments for schema drift. A database drifted from its desired state if applying the schema
+defined by the "src" attribute (or the migration directory) of its environment requires changes.
+The "url" and "dev" attributes of the environment must be set.
+
+The results of every check are printed, posted as JSON to the given webhooks, and exposed as
+Prometheus metrics on the /metrics endpoint of the address set by the --metrics-addr flag.`,
+			Example: `  atlas agent --env prod
+  atlas agent --env prod --interval 10m --webhook "https://hooks.example.com/atlas"
+  atlas agent --env prod --metrics-addr ":9090"
+  atlas agent --env prod --once`,
+			Args: cobra.NoArgs,
+			RunE: RunE(func(cmd *cobra.Command, _ []string) error {
+				return agentRun(cmd, flags)
+			}),
+		}
+	)
+	cmd.Flags().SortFlags = false
+	cmd.Flags().DurationVar(&flags.interval, flagInterval, time.Hour, "interval between drift checks")
+	cmd.Flags().StringSliceVar(&flags.webhooks, flagWebhook, nil, "URLs to post the results of the drift checks to")
+	cmd.Flags().StringVar(&flags.metricsAddr, flagMetricsAddr, "", "address to expose the Prometheus metrics on (e.g. :9090)")
+	cmd.Flags().BoolVar(&flags.once, flagOnce, false, "run the drift checks once and exit")
+	cmd.MarkFlagsMutuallyExclusive(flagOnce, flagMetricsAddr)
+	addGlobalFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+func agentRun(cmd *cobra.Command, flags agentFlags) error {
+	if GlobalFlags.SelectedEnv == "" {
+		return errors.New("the --env flag is required to run the agent")
+	}
+	if flags.interval <= 0 {
+		return fmt.Errorf("invalid --%s: %s", flagInterval, flags.interval)
+	}
+	_, envs, err := EnvByName(cmd, GlobalFlags.SelectedEnv, GlobalFlags.Vars)
+	if err != nil {
+		return err
+	}
+	a := &agent{
+		cmd:      cmd,
+		envs:     envs,
+		webhooks: flags.webhooks,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}
+	ctx := cmd.Context()
+	if flags.metricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", a)
+		srv := &http.Server{Addr: flags.metricsAddr, Handler: mux}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				cmd.PrintErrf("Error: metrics server: %v\n", err)
+			}
+		}()
+		defer srv.Close()
+	}
+	// Unlike the other commands, failed checks are reported
+	// and do not stop the agent. Hence, usage is never printed.
+	cmd.SilenceUsage = true
+	for {
+		drifted := a.run(ctx, cmd.OutOrStdout())
+		if flags.once {
+			if drifted > 0 {
+				return fmt.Errorf("schema drift detected in %d environment(s)", drifted)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(flags.interval):
+		}
+	}
+}
+
+// agent runs drift checks against the environments it was configured with,
+// and keeps the results of the last run for the metrics endpoint.
+type agent struct {
+	cmd      *cobra.Command
+	envs     []*Env
+	webhooks []string
+	client   *http.Client
+	mu       sync.Mutex
+	last     []*cmdlog.SchemaDrift
+}
+
+// run checks all environments for drift, reports the results
+// and returns the number of environments that drifted.
+func (a *agent) run(ctx context.Context, w io.Writer) (drifted int) {
+	checks := make([]*cmdlog.SchemaDrift, 0, len(a.envs))
+	for _, env := range a.envs {
+		d := a.check(ctx, env)
+		switch {
+		case d.Error != "":
+			fmt.Fprintf(w, "%s env %q: drift check failed: %s\n", d.Time.Format(time.RFC3339), d.Env, d.Error)
+		case d.Drifted():
+			drifted++
+			fmt.Fprintf(w, "%s env %q: schema drift detected (%d changes)\n", d.Time.Format(time.RFC3339), d.Env, d.Changes)
+		default:
+			fmt.Fprintf(w, "%s env %q: no schema drift\n", d.Time.Format(time.RFC3339), d.Env)
+		}
+		for _, u := range a.webhooks {
+			if err := a.notify(ctx, u, d); err != nil {
+				fmt.Fprintf(w, "%s env %q: posting to webhook %s: %v\n", d.Time.Format(time.RFC3339), d.Env, sqlclient.Redact(u), err)
+			}
+		}
+		checks = append(checks, d)
+	}
+	a.mu.Lock()
+	a.last = checks
+	a.mu.Unlock()
+	return drifted
+}
+
+// check computes the changes required to sync the database
+// of the given environment with its desired state.
+func (a *agent) check(ctx context.Context, env *Env) *cmdlog.SchemaDrift {
+	return checkDrift(ctx, a.cmd, env, nil)
+}
+
+// checkDrift computes the changes required to sync the database of the
+// given environment with its desired state. Resources that match the
+// exclude patterns, or the ones configured on the env, are ignored.
+func checkDrift(ctx context.Context, cmd *cobra.Command, env *Env, exclude []string) *cmdlog.SchemaDrift {
+	d := &cmdlog.SchemaDrift{Env: env.Name, Time: time.Now().UTC()}
+	if err := driftDiff(ctx, cmd, env, d, exclude); err != nil {
+		d.Error = sqlclient.Redact(err.Error())
+	}
+	return d
+}
+
+func driftDiff(ctx context.Context, cmd *cobra.Command, env *Env, d *cmdlog.SchemaDrift, exclude []string) error {
+	switch {
+	case env.URL == "":
+		return errors.New(`the "url" attribute of the env is required`)
+	case env.DevURL == "":
+		return errors.New(`the "dev" attribute of the env is required`)
+	}
+	d.URL = sqlclient.Redact(env.URL)
+	srcs, err := env.Sources()
+	if err != nil {
+		return err
+	}
+	// Environments that are managed by versioned
+	// migrations are compared to their directory.
+	if len(srcs) == 0 && env.Migration != nil && env.Migration.Dir != "" {
+		srcs = []string{env.Migration.Dir}
+	}
+	if len(srcs) == 0 {
+		return errors.New(`the "src" attribute or the migration directory of the env is required`)
+	}
+	exclude = append(slices.Clone(env.Exclude), exclude...)
+	if env.Drift != nil {
+		exclude = append(exclude, env.Drift.Exclude...)
+	}
+	dev, err := openURL(ctx, env.DevURL)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+	from, err := stateReader(ctx, env, &stateReaderConfig{
+		urls:    []string{env.URL},
+		schemas: env.Schemas,
+		exclude: exclude,
+	})
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+	client, ok := from.Closer.(*sqlclient.Client)
+	if !ok {
+		return errors.New(`the "url" attribute of the env must be a database connection`)
+	}
+	to, err := stateReader(ctx, env, &stateReaderConfig{
+		urls:    srcs,
+		dev:     dev,
+		client:  client,
+		schemas: env.Schemas,
+		exclude: exclude,
+		vars:    env.Vars(),
+	})
+	if err != nil {
+		return err
+	}
+	defer to.Close()
+	diff, err := computeDiff(ctx, client, from, to, env.TypeOverrides(), diffOptions(cmd, env)...)
+	if err != nil {
+		return err
+	}
+	if d.Changes = len(diff.changes); d.Changes > 0 {
+		if d.SQL, err = cmdlog.NewSchemaDiff(ctx, client, diff.from, diff.to, diff.changes).MarshalSQL(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify posts the given drift check to the webhook URL.
+func (a *agent) notify(ctx context.Context, u string, d *cmdlog.SchemaDrift) error {
+	body, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ServeHTTP exposes the results of the last run in the Prometheus text-based exposition format.
+func (a *agent) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	io.WriteString(w, cmdlog.MarshalDriftPrometheus(a.last))
+}
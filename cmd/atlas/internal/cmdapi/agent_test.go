@@ -0,0 +1,84 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgent(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		cp  = filepath.Join(p, "atlas.hcl")
+		sp  = filepath.Join(p, "schema.hcl")
+		cfg = fmt.Sprintf(`
+env "synced" {
+  url = "%s"
+  dev = "%s"
+  src = "file://%s"
+}
+
+env "drifted" {
+  url = "%s"
+  dev = "%s"
+  src = "file://%s"
+}
+`, openSQLite(t, "create table users (id int not null)"), openSQLite(t, ""), sp,
+			openSQLite(t, "create table users (id int not null, name text)"), openSQLite(t, ""), sp)
+	)
+	require.NoError(t, os.WriteFile(cp, []byte(cfg), 0600))
+	require.NoError(t, os.WriteFile(sp, []byte(`
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+}
+`), 0600))
+	var posted []*cmdlog.SchemaDrift
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d := &cmdlog.SchemaDrift{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(d))
+		posted = append(posted, d)
+	}))
+	defer srv.Close()
+
+	s, err := runCmd(agentCmd(), "-c", "file://"+cp, "--env", "synced", "--once", "--webhook", srv.URL)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(s, "env \"synced\": no schema drift\n"), s)
+	require.Len(t, posted, 1)
+	require.Equal(t, "synced", posted[0].Env)
+	require.False(t, posted[0].Drifted())
+
+	s, err = runCmd(agentCmd(), "-c", "file://"+cp, "--env", "drifted", "--once", "--webhook", srv.URL)
+	require.EqualError(t, err, "schema drift detected in 1 environment(s)")
+	require.Contains(t, s, "env \"drifted\": schema drift detected (1 changes)\n")
+	require.Len(t, posted, 2)
+	require.True(t, posted[1].Drifted())
+	require.Contains(t, posted[1].SQL, "DROP TABLE `users`;")
+
+	a := &agent{last: posted}
+	rec := httptest.NewRecorder()
+	a.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), "# TYPE atlas_schema_drift gauge\n")
+	require.Contains(t, rec.Body.String(), `atlas_schema_drift{env="synced",url=`)
+	require.Contains(t, rec.Body.String(), `atlas_schema_drift_changes{env="drifted",url=`)
+
+	_, err = runCmd(agentCmd(), "--once")
+	require.EqualError(t, err, "the --env flag is required to run the agent")
+}
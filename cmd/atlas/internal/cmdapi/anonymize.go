@@ -0,0 +1,242 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// anonymizer replaces the names of an inspected schema with generic ones, in order
+// to share the schema in bug reports without exposing its details. Names are replaced
+// deterministically, in the order they are encountered (e.g., the first table is named
+// "table1"), and identical names are given the same replacement. Hence, references between
+// elements, and their usage in expressions (e.g., view definitions), are preserved.
+type anonymizer struct {
+	names map[string]string // Original names to their replacements.
+	count map[string]int    // Number of replacements given by kind.
+}
+
+// anonymize anonymizes the given realm in place. The types of columns are kept as is,
+// comments are removed, string literals are removed from expressions, and defaults that
+// hold string literals are dropped. Functions, procedures, triggers and other objects
+// (except enum types) are removed, as their definitions cannot be anonymized safely.
+func anonymize(r *schema.Realm) {
+	a := &anonymizer{names: make(map[string]string), count: make(map[string]int)}
+	// Names are replaced first, as expressions may
+	// reference elements that are defined later.
+	for _, s := range r.Schemas {
+		s.Name = a.rename("schema", s.Name)
+		for _, t := range s.Tables {
+			t.Name = a.rename("table", t.Name)
+			for _, c := range t.Columns {
+				c.Name = a.rename("column", c.Name)
+			}
+		}
+		for _, v := range s.Views {
+			v.Name = a.rename("view", v.Name)
+			for _, c := range v.Columns {
+				c.Name = a.rename("column", c.Name)
+			}
+		}
+	}
+	enums := make(map[*schema.EnumType]bool)
+	for _, s := range r.Schemas {
+		s.Attrs = noComment(s.Attrs)
+		for _, t := range s.Tables {
+			t.Attrs = a.attrs(t.Attrs)
+			for _, c := range t.Columns {
+				c.Attrs = a.attrs(c.Attrs)
+				c.Default = a.defaultExpr(c.Default)
+				if c.Type == nil {
+					continue
+				}
+				if e, ok := c.Type.Type.(*schema.EnumType); ok {
+					a.enum(e, enums)
+				}
+			}
+			if pk := t.PrimaryKey; pk != nil {
+				pk.Name = a.rename("pk", pk.Name)
+			}
+			for _, idx := range t.Indexes {
+				idx.Name = a.rename("index", idx.Name)
+				idx.Attrs = noComment(idx.Attrs)
+				a.parts(idx.Parts)
+			}
+			for _, fk := range t.ForeignKeys {
+				fk.Symbol = a.rename("fk", fk.Symbol)
+			}
+			t.Triggers, t.Deps = nil, relations(t.Deps)
+		}
+		for _, v := range s.Views {
+			v.Def = a.expr(v.Def)
+			v.Attrs = noComment(v.Attrs)
+			for _, c := range v.Columns {
+				c.Attrs = noComment(c.Attrs)
+			}
+			for _, idx := range v.Indexes {
+				idx.Name = a.rename("index", idx.Name)
+				a.parts(idx.Parts)
+			}
+			v.Triggers, v.Deps = nil, relations(v.Deps)
+		}
+		objs := s.Objects[:0]
+		for _, o := range s.Objects {
+			if e, ok := o.(*schema.EnumType); ok {
+				a.enum(e, enums)
+				objs = append(objs, e)
+			}
+		}
+		s.Funcs, s.Procs, s.Objects = nil, nil, objs
+	}
+	r.Attrs, r.Objects = nil, nil
+}
+
+// enum anonymizes the name and the values of the given enum type, if it was not seen before.
+func (a *anonymizer) enum(e *schema.EnumType, seen map[*schema.EnumType]bool) {
+	if seen[e] {
+		return
+	}
+	seen[e] = true
+	e.T = a.rename("enum", e.T)
+	for i := range e.Values {
+		e.Values[i] = "value" + strconv.Itoa(i+1)
+	}
+}
+
+// rename returns the replacement of the given name.
+func (a *anonymizer) rename(kind, name string) string {
+	if name == "" {
+		return ""
+	}
+	if n, ok := a.names[name]; ok {
+		return n
+	}
+	a.count[kind]++
+	n := kind + strconv.Itoa(a.count[kind])
+	a.names[name] = n
+	return n
+}
+
+// attrs anonymizes the checks and generated expressions
+// of a table or a column, and removes their comments.
+func (a *anonymizer) attrs(attrs []schema.Attr) []schema.Attr {
+	attrs = noComment(attrs)
+	for _, at := range attrs {
+		switch at := at.(type) {
+		case *schema.Check:
+			at.Name = a.rename("check", at.Name)
+			at.Expr = a.expr(at.Expr)
+		case *schema.GeneratedExpr:
+			at.Expr = a.expr(at.Expr)
+		}
+	}
+	return attrs
+}
+
+// parts anonymizes the expressions of the index parts.
+func (a *anonymizer) parts(parts []*schema.IndexPart) {
+	for _, p := range parts {
+		switch x := p.X.(type) {
+		case *schema.RawExpr:
+			x.X = a.expr(x.X)
+		case *schema.Literal:
+			x.V = a.expr(x.V)
+		}
+	}
+}
+
+// defaultExpr returns the anonymized default value, or nil
+// in case it holds string literals or quoted identifiers.
+func (a *anonymizer) defaultExpr(x schema.Expr) schema.Expr {
+	var s string
+	switch x := x.(type) {
+	case *schema.Literal:
+		s = x.V
+	case *schema.RawExpr:
+		s = x.X
+	default:
+		return nil
+	}
+	if strings.ContainsAny(s, "'\"`") {
+		return nil
+	}
+	if raw, ok := x.(*schema.RawExpr); ok {
+		raw.X = a.expr(s)
+	}
+	return x
+}
+
+// expr replaces the known names in the given SQL expression, and empties its string literals.
+func (a *anonymizer) expr(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\'':
+			// Skip string literals, including their escaped quotes.
+			j := i + 1
+			for j < len(s) && (s[j] != '\'' || j+1 < len(s) && s[j+1] == '\'') {
+				if s[j] == '\'' {
+					j++
+				}
+				j++
+			}
+			b.WriteString("''")
+			i = j + 1
+		case c == '"' || c == '`':
+			j := strings.IndexByte(s[i+1:], c)
+			if j == -1 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteByte(c)
+			b.WriteString(a.ident(s[i+1 : i+1+j]))
+			b.WriteByte(c)
+			i += j + 2
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(s) && (isIdentStart(s[j]) || s[j] >= '0' && s[j] <= '9' || s[j] == '$') {
+				j++
+			}
+			// Function names are kept as is.
+			if k := strings.IndexFunc(s[j:], func(r rune) bool { return r != ' ' }); k != -1 && s[j+k] == '(' {
+				b.WriteString(s[i:j])
+			} else {
+				b.WriteString(a.ident(s[i:j]))
+			}
+			i = j
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// ident returns the replacement of a known identifier, or the identifier itself.
+func (a *anonymizer) ident(s string) string {
+	if n, ok := a.names[s]; ok {
+		return n
+	}
+	return s
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+// relations returns the tables and views of the given dependencies.
+func relations(deps []schema.Object) []schema.Object {
+	kept := deps[:0]
+	for _, o := range deps {
+		switch o.(type) {
+		case *schema.Table, *schema.View:
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
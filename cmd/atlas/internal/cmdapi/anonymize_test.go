@@ -0,0 +1,63 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnonymize(t *testing.T) {
+	var (
+		status = &schema.EnumType{T: "user_status", Values: []string{"active", "banned"}}
+		users  = schema.NewTable("users").
+			SetComment("customers").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewStringColumn("email", "text").SetDefault(&schema.Literal{V: "'admin@example.com'"}),
+				schema.NewIntColumn("age", "int").SetDefault(&schema.Literal{V: "18"}),
+				schema.NewColumn("status").SetType(status),
+			).
+			AddChecks(schema.NewCheck().SetName("adult").SetExpr("age >= 18 AND email <> 'root'"))
+		posts = schema.NewTable("posts").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("user_id", "int"))
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]).SetName("users_pkey"))
+	users.AddIndexes(schema.NewIndex("users_lower_email").AddParts(schema.NewExprPart(&schema.RawExpr{X: "lower(email)"})))
+	posts.AddForeignKeys(schema.NewForeignKey("posts_user_fk").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	r := schema.NewRealm(
+		schema.New("app").
+			AddTables(users, posts).
+			AddViews(schema.NewView("adults", `SELECT "id", email FROM users WHERE age >= 18`)).
+			AddFuncs(&schema.Func{Name: "secret"}).
+			AddObjects(status),
+	)
+	anonymize(r)
+
+	s := r.Schemas[0]
+	require.Equal(t, "schema1", s.Name)
+	require.Equal(t, "table1", users.Name)
+	require.Equal(t, "table2", posts.Name)
+	// Comments are removed, and names and literals are replaced in expressions.
+	require.Equal(t, []schema.Attr{&schema.Check{Name: "check1", Expr: "column3 >= 18 AND column2 <> ''"}}, users.Attrs)
+	// Columns with the same name are given the same replacement.
+	require.Equal(t, []string{"column1", "column2", "column3", "column4"}, []string{users.Columns[0].Name, users.Columns[1].Name, users.Columns[2].Name, users.Columns[3].Name})
+	require.Equal(t, []string{"column1", "column5"}, []string{posts.Columns[0].Name, posts.Columns[1].Name})
+	require.Nil(t, users.Columns[1].Default, "string literals are dropped")
+	require.Equal(t, &schema.Literal{V: "18"}, users.Columns[2].Default)
+	require.Equal(t, &schema.EnumType{T: "enum1", Values: []string{"value1", "value2"}}, status)
+	require.Equal(t, "pk1", users.PrimaryKey.Name)
+	require.Equal(t, "index1", users.Indexes[0].Name)
+	require.Equal(t, &schema.RawExpr{X: "lower(column2)"}, users.Indexes[0].Parts[0].X)
+	require.Equal(t, "fk1", posts.ForeignKeys[0].Symbol)
+	require.Equal(t, users, posts.ForeignKeys[0].RefTable)
+	require.Equal(t, "view1", s.Views[0].Name)
+	require.Equal(t, `SELECT "column1", column2 FROM table1 WHERE column3 >= 18`, s.Views[0].Def)
+	require.Empty(t, s.Funcs)
+	require.Equal(t, []schema.Object{status}, s.Objects)
+}
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"ariga.io/atlas/cmd/atlas/internal/cmdapi/vercheck"
 	"ariga.io/atlas/cmd/atlas/internal/cmdext"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
@@ -57,7 +58,14 @@ var (
 	versionCmd = &cobra.Command{
 		Use:   "version",
 		Short: "Prints this Atlas CLI version information.",
-		Run: func(cmd *cobra.Command, _ []string) {
+		Long: `'atlas version' prints the version of the Atlas CLI. If the --check flag is set, it queries the
+version-check service for updates and security advisories of the current version. In air-gapped
+environments, the service can be replaced with an internal mirror using the ATLAS_VERCHECK_URL
+environment variable.`,
+		Example: `  atlas version
+  atlas version --check
+  ATLAS_VERCHECK_URL=https://vercheck.example.com atlas version --check`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
 			var (
 				f    = versionFmt
 				args []any
@@ -70,8 +78,14 @@ var (
 			v, u := parseV(version)
 			args = append(args, v, u, versionInfo)
 			cmd.Printf(f, args...)
+			if !versionCheck {
+				return nil
+			}
+			return checkVersion(cmd)
 		},
 	}
+	// versionCheck indicates if 'atlas version' should check for updates.
+	versionCheck bool
 
 	// license holds Atlas license. When built with cloud packages should be set by build flag
 	// "-X 'ariga.io/atlas/cmd/atlas/internal/cmdapi.license=${license}'"
@@ -132,9 +146,40 @@ func (e *AbortError) FormatError(cmd *cobra.Command) {
 	cmd.SetErrPrefix("Abort:")
 }
 
+// redactedError wraps errors that echo connection credentials
+// (e.g., drivers errors that print their DSN) with a masked message.
+type redactedError struct {
+	err error
+	msg string
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError masks the connection credentials found in the error message, if any.
+func redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if msg := sqlclient.Redact(err.Error()); msg != err.Error() {
+		return &redactedError{err: err, msg: msg}
+	}
+	return err
+}
+
 // RunE wraps the command cobra.Command.RunE function with additional postrun logic.
 func RunE(f func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) (err error) {
+		// User-level defaults are applied first, as they
+		// are overridden by the project file and the flags.
+		c, err := LoadUserConfig()
+		if err != nil {
+			return err
+		}
+		if err := c.apply(cmd); err != nil {
+			return err
+		}
 		if err = f(cmd, args); err != nil {
 			if err1 := (Aborter)(nil); errors.As(err, &err1) {
 				err = &AbortError{Err: err}
@@ -143,13 +188,17 @@ func RunE(f func(*cobra.Command, []string) error) func(*cobra.Command, []string)
 				ef.FormatError(cmd)
 			}
 		}
-		return err
+		return redactError(err)
 	}
 }
 
 func init() {
+	versionCmd.Flags().BoolVar(&versionCheck, flagCheck, false, "check for updates and security advisories of the current version")
 	Root.AddCommand(versionCmd)
 	Root.AddCommand(licenseCmd)
+	Root.AddCommand(debugCmd())
+	Root.AddCommand(agentCmd())
+	Root.AddCommand(configCmd())
 	// Register a global function to clean up the global
 	// flags regardless if the command passed or failed.
 	cobra.OnFinalize(func() {
@@ -172,6 +221,23 @@ func parseV(version string) (string, string) {
 	return version, u
 }
 
+// checkVersion queries the version-check service for updates
+// and security advisories of the current binary version.
+func checkVersion(cmd *cobra.Command) error {
+	if !semver.IsValid(version) {
+		return errors.New("cannot check for updates of a development build")
+	}
+	p, err := vercheck.New(vercheck.URL()).CheckNow(cmd.Context(), version)
+	if err != nil {
+		return fmt.Errorf("checking for updates: %w", err)
+	}
+	if p.Latest == nil && p.Advisory == nil {
+		cmd.Println("Atlas is up to date.")
+		return nil
+	}
+	return vercheck.Notify.Execute(cmd.OutOrStdout(), p)
+}
+
 // Version returns the current Atlas binary version.
 func Version() string {
 	return version
@@ -259,38 +325,87 @@ func (v *Vars) Type() string {
 }
 
 const (
+	flagAgainst        = "against"
 	flagAllowDirty     = "allow-dirty"
 	flagEdit           = "edit"
+	flagAnalyze        = "analyze"
+	flagAnonymize      = "anonymize"
 	flagAutoApprove    = "auto-approve"
 	flagBaseline       = "baseline"
+	flagCheck          = "check"
+	flagCheckPrivs     = "check-privileges"
+	flagCheckReserved  = "check-reserved"
+	flagCodegen        = "codegen"
 	flagConfig         = "config"
 	flagContext        = "context"
+	flagContinueOnErr  = "continue-on-error"
+	flagDepth          = "depth"
 	flagDevURL         = "dev-url"
 	flagDirURL         = "dir"
 	flagDirFormat      = "dir-format"
 	flagDryRun         = "dry-run"
 	flagEnv            = "env"
 	flagExclude        = "exclude"
+	flagExplain        = "explain"
 	flagFile           = "file"
+	flagFocus          = "focus"
 	flagFrom           = "from"
 	flagFromShort      = "f"
+	flagFromVersion    = "from-version"
+	flagFromTemplate   = "from-template"
 	flagFormat         = "format"
+	flagFacet          = "facet"
 	flagGitBase        = "git-base"
 	flagGitDir         = "git-dir"
+	flagHealthAddr     = "health-addr"
+	flagInteractive    = "interactive"
+	flagInterval       = "interval"
+	flagLabel          = "label"
 	flagLatest         = "latest"
+	flagLimitChangesTo = "limit-changes-to"
+	flagLockName       = "lock-name"
+	flagMaintainParts  = "maintain-partitions"
 	flagLockTimeout    = "lock-timeout"
 	flagLog            = "log"
+	flagMaxParallel    = "max-parallel"
+	flagMetricsAddr    = "metrics-addr"
+	flagName           = "name"
+	flagOnce           = "once"
 	flagPlan           = "plan"
+	flagRateLimit      = "rate-limit"
+	flagRevisionColumn = "revisions-column"
 	flagRevisionSchema = "revisions-schema"
+	flagRevisionTable  = "revisions-table"
 	flagSchema         = "schema"
+	flagSignKey        = "sign-key"
+	flagSnapshotDir    = "snapshot-dir"
 	flagSchemaShort    = "s"
 	flagTo             = "to"
+	flagTemplateDir    = "template-dir"
+	flagTemplateVar    = "template-var"
+	flagTenantQuery    = "tenant-query"
+	flagTolerate       = "tolerate-errors"
+	flagToVersion      = "to-version"
 	flagTxMode         = "tx-mode"
 	flagExecOrder      = "exec-order"
+	flagExecProxy      = "exec-proxy"
+	flagSimulate       = "simulate"
+	flagReplayCache    = "replay-cache"
+	flagNoCombine      = "no-combine"
+	flagTruncate       = "truncate"
+	flagUpsert         = "upsert"
 	flagURL            = "url"
+	flagVerifyKey      = "verify-key"
+	flagWait           = "wait"
+	flagWaitTimeout    = "wait-timeout"
+	flagWebhook        = "webhook"
 	flagURLShort       = "u"
 	flagVar            = "var"
 	flagQualifier      = "qualifier"
+	flagInspectTimeout = "inspect-timeout"
+	flagRetries        = "retries"
+	flagResumeFrom     = "resume-from"
+	flagConcurrency    = "concurrency"
 )
 
 func addGlobalFlags(set *pflag.FlagSet) {
@@ -299,10 +414,18 @@ func addGlobalFlags(set *pflag.FlagSet) {
 	set.StringVarP(&GlobalFlags.ConfigURL, flagConfig, "c", defaultConfigPath, "select config (project) file using URL format")
 }
 
+func addFlagAnalyze(set *pflag.FlagSet, target *bool) {
+	set.BoolVar(target, flagAnalyze, false, "refresh the statistics of the changed tables after applying")
+}
+
 func addFlagAutoApprove(set *pflag.FlagSet, target *bool) {
 	set.BoolVar(target, flagAutoApprove, false, "apply changes without prompting for approval")
 }
 
+func addFlagCheckPrivileges(set *pflag.FlagSet, target *bool) {
+	set.BoolVar(target, flagCheckPrivs, false, "verify the privileges required by the planned changes before applying them")
+}
+
 func addFlagDirFormat(set *pflag.FlagSet, target *string) {
 	set.StringVar(target, flagDirFormat, "atlas", "select migration file format")
 }
@@ -311,6 +434,31 @@ func addFlagLockTimeout(set *pflag.FlagSet, target *time.Duration) {
 	set.DurationVar(target, flagLockTimeout, 10*time.Second, "set how long to wait for the database lock")
 }
 
+func addFlagWaitTimeout(set *pflag.FlagSet, target *time.Duration) {
+	set.DurationVar(target, flagWaitTimeout, 0, "max time to wait for the database to accept connections (e.g. 2m)")
+}
+
+func addFlagLockName(set *pflag.FlagSet, target *string) {
+	set.StringVar(target, flagLockName, "", "set the name of the database lock used to serialize concurrent applies")
+}
+
+func addFlagSignKey(set *pflag.FlagSet, target *string) {
+	set.StringVar(target, flagSignKey, "", "path to a private key (PEM) to sign the migration directory with")
+}
+
+func addFlagsMultiTarget(set *pflag.FlagSet, target *multiTargetFlags) {
+	set.IntVar(&target.maxParallel, flagMaxParallel, 1, "maximum number of targets to execute in parallel")
+	set.DurationVar(&target.rateLimit, flagRateLimit, 0, "minimum interval between the start of two targets (e.g. 500ms)")
+	set.BoolVar(&target.continueOnErr, flagContinueOnErr, false, "continue executing the remaining targets in case of an error")
+}
+
+func addFlagsInspect(set *pflag.FlagSet, target *inspectFlags) {
+	set.DurationVar(&target.timeout, flagInspectTimeout, 0, "timeout for a single catalog query executed during inspection (e.g. 1m)")
+	set.IntVar(&target.retries, flagRetries, 0, "number of retries for a failed catalog query executed during inspection")
+	set.StringVar(&target.resumeFrom, flagResumeFrom, "", "path to an inspection snapshot file used for resuming an interrupted inspection")
+	set.IntVar(&target.concurrency, flagConcurrency, 0, "maximum number of schemas to inspect concurrently")
+}
+
 // addFlagURL adds a URL flag. If given, args[0] override the name, args[1] the shorthand, args[2] the default value.
 func addFlagDirURL(set *pflag.FlagSet, target *string, args ...string) {
 	name, short, val := flagDirURL, "", "file://migrations"
@@ -340,6 +488,14 @@ func addFlagDryRun(set *pflag.FlagSet, target *bool) {
 	set.BoolVar(target, flagDryRun, false, "print SQL without executing it")
 }
 
+func addFlagExplain(set *pflag.FlagSet, target *bool) {
+	set.BoolVar(target, flagExplain, false, "annotate each planned change with the differences that triggered it")
+}
+
+func addFlagNoCombine(set *pflag.FlagSet, target *bool) {
+	set.BoolVar(target, flagNoCombine, false, "do not combine changes of the same table into a single statement")
+}
+
 func addFlagExclude(set *pflag.FlagSet, target *[]string) {
 	set.StringSliceVar(
 		target,
@@ -349,6 +505,32 @@ func addFlagExclude(set *pflag.FlagSet, target *[]string) {
 	)
 }
 
+func addFlagLabels(set *pflag.FlagSet, target *[]string) {
+	set.StringSliceVar(
+		target,
+		flagLabel,
+		nil,
+		"list of labels (key or key=value) used to filter tables by the labels defined on them or on their columns",
+	)
+}
+
+// parseLabels parses the labels passed with the --label flag
+// into a selector. A label without a value matches any value.
+func parseLabels(labels []string) (map[string]string, error) {
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	sel := make(map[string]string, len(labels))
+	for _, l := range labels {
+		k, v, _ := strings.Cut(l, "=")
+		if k = strings.TrimSpace(k); k == "" {
+			return nil, fmt.Errorf("invalid label %q: expect key or key=value", l)
+		}
+		sel[k] = strings.TrimSpace(v)
+	}
+	return sel, nil
+}
+
 func addFlagLog(set *pflag.FlagSet, target *string) {
 	set.StringVar(target, flagLog, "", "Go template to use to format the output")
 	// Use MarkHidden instead of MarkDeprecated to avoid
@@ -364,6 +546,10 @@ func addFlagRevisionSchema(set *pflag.FlagSet, target *string) {
 	set.StringVar(target, flagRevisionSchema, "", "name of the schema the revisions table resides in")
 }
 
+func addFlagRevisionTable(set *pflag.FlagSet, target *string) {
+	set.StringVar(target, flagRevisionTable, "", "name of the revisions table (default atlas_schema_revisions)")
+}
+
 func addFlagSchemas(set *pflag.FlagSet, target *[]string) {
 	set.StringSliceVarP(
 		target,
@@ -465,6 +651,8 @@ type stateReaderConfig struct {
 	schemas     []string          // schemas to work on
 	exclude     []string          // exclude flag values
 	withPos     bool              // indicate if schema.Pos should be loaded.
+	inspect     *inspectFlags     // inspection flags of database connections, if set
+	wait        time.Duration     // max time to wait for database connections to be ready
 	vars        Vars
 }
 
@@ -511,6 +699,44 @@ func readerUseDev(env *Env, urls ...string) (bool, error) {
 	}
 }
 
+// waitBackoff is the initial delay between connection attempts. Overridden in tests.
+var waitBackoff = 500 * time.Millisecond
+
+// openWait opens a client to the given URL. If the connection cannot be established,
+// the attempts are retried with an exponential backoff until the database is ready
+// or the given timeout expires. A zero timeout means the client is opened only once.
+func openWait(ctx context.Context, timeout time.Duration, u string, open func(context.Context, string) (*sqlclient.Client, error)) (*sqlclient.Client, error) {
+	c, err := open(ctx, u)
+	if err == nil || timeout <= 0 {
+		return c, err
+	}
+	// Retrying does not help invalid URLs or unknown drivers.
+	if pu, perr := sqlclient.ParseURL(u); perr != nil || !sqlclient.HasDriver(pu.Scheme) {
+		return nil, err
+	}
+	var (
+		attempts = 1
+		delay    = waitBackoff
+		deadline = time.Now().Add(timeout)
+	)
+	for {
+		left := time.Until(deadline)
+		if left <= 0 {
+			return nil, fmt.Errorf("database is not ready after waiting %s (%d attempts): %w", timeout, attempts, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), err)
+		case <-time.After(min(delay, left)):
+		}
+		attempts++
+		if c, err = open(ctx, u); err == nil {
+			return c, nil
+		}
+		delay = min(2*delay, 10*time.Second)
+	}
+}
+
 // stateReader returns a migrate.StateReader that reads the state from the given urls.
 func stateReader(ctx context.Context, env *Env, config *stateReaderConfig) (*cmdext.StateReadCloser, error) {
 	excfg, err := config.Exported()
@@ -564,20 +790,30 @@ func stateReader(ctx context.Context, env *Env, config *stateReaderConfig) (*cmd
 			return rc, nil
 		}
 		// All other schemes are database (or docker) connections.
-		c, err := env.openClient(ctx, config.urls[0]) // call to selectScheme already checks for len > 0
+		u, sz, err := sanitizeURL(config.urls[0]) // call to selectScheme already checks for len > 0
+		if err != nil {
+			return nil, err
+		}
+		c, err := openWait(ctx, config.wait, u, env.openClient)
 		if err != nil {
 			return nil, err
 		}
 		var sr migrate.StateReader
-		switch c.URL.Schema {
-		case "":
+		switch {
+		case config.inspect.set():
+			sr = &chunkedInspect{client: c, flags: config.inspect, schemas: config.schemas, exclude: config.exclude}
+		case c.URL.Schema == "":
 			sr = migrate.RealmConn(c.Driver, &schema.InspectRealmOption{
-				Schemas: config.schemas,
-				Exclude: config.exclude,
+				Schemas:     config.schemas,
+				Exclude:     config.exclude,
+				Concurrency: config.inspect.concurrent(),
 			})
 		default:
 			sr = migrate.SchemaConn(c.Driver, c.URL.Schema, &schema.InspectOptions{Exclude: config.exclude})
 		}
+		if sz != nil {
+			sr = sz.reader(sr)
+		}
 		return &cmdext.StateReadCloser{
 			StateReader: sr,
 			Closer:      c,
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"slices"
 	"testing"
 	"text/template"
 	"time"
@@ -25,6 +26,8 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/external"
+	"ariga.io/atlas/sql/sqlcheck/protect"
 	"ariga.io/atlas/sql/sqlclient"
 
 	"github.com/spf13/cobra"
@@ -36,8 +39,14 @@ func init() {
 		schemaApplyCmd(),
 		schemaCleanCmd(),
 		schemaDiffCmd(),
+		schemaDriftCmd(),
 		schemaFmtCmd(),
+		schemaGraphCmd(),
+		schemaPortabilityCmd(),
 		schemaInspectCmd(),
+		schemaLintCmd(),
+		schemaSeedCmd(),
+		schemaSnapshotCmd(),
 		unsupportedCommand("schema", "test"),
 		unsupportedCommand("schema", "plan"),
 		unsupportedCommand("schema", "push"),
@@ -47,15 +56,16 @@ func init() {
 	migrateCmd.AddCommand(
 		migrateApplyCmd(),
 		migrateDiffCmd(),
+		migrateDownCmd(),
 		migrateHashCmd(),
 		migrateImportCmd(),
 		migrateLintCmd(),
 		migrateNewCmd(),
+		migrateRetypeCmd(),
 		migrateSetCmd(),
 		migrateStatusCmd(),
 		migrateValidateCmd(),
 		unsupportedCommand("migrate", "checkpoint"),
-		unsupportedCommand("migrate", "down"),
 		unsupportedCommand("migrate", "rebase"),
 		unsupportedCommand("migrate", "rm"),
 		unsupportedCommand("migrate", "edit"),
@@ -106,11 +116,13 @@ Or, visit the website to see all installation options:
 type (
 	// Project represents an atlas.hcl project config file.
 	Project struct {
-		Envs  []*Env `spec:"env"`  // List of environments
-		Lint  *Lint  `spec:"lint"` // Optional global lint policy
-		Diff  *Diff  `spec:"diff"` // Optional global diff policy
-		Test  *Test  `spec:"test"` // Optional test configuration
-		cloud *cmdext.AtlasConfig
+		Envs            []*Env `spec:"env"`              // List of environments
+		Lint            *Lint  `spec:"lint"`             // Optional global lint policy
+		Diff            *Diff  `spec:"diff"`             // Optional global diff policy
+		Test            *Test  `spec:"test"`             // Optional test configuration
+		Flags           *Flags `spec:"flags"`            // Optional global flag defaults
+		RequiredVersion string `spec:"required_version"` // Optional version constraints of the Atlas binary
+		cloud           *cmdext.AtlasConfig
 	}
 )
 
@@ -121,11 +133,14 @@ const (
 
 // maySuggestUpgrade informs the user about the limitations of the community edition to stderr
 // at most once a week. The user can disable this message by setting the ATLAS_NO_UPGRADE_SUGGESTIONS
-// environment variable.
+// environment variable, or the no_upgrade_suggestions key of the user configuration.
 func maySuggestUpgrade(cmd *cobra.Command) {
 	if os.Getenv(envSkipUpgradeSuggestions) != "" || testing.Testing() {
 		return
 	}
+	if c, err := LoadUserConfig(); err != nil || c.NoUpgradeSuggestions {
+		return
+	}
 	state := cmdstate.File[LocalState]{Name: localStateFile}
 	prev, err := state.Read()
 	if err != nil {
@@ -166,12 +181,22 @@ func migrateLintRun(cmd *cobra.Command, _ []string, flags migrateLintFlags, env
 	if err != nil {
 		return err
 	}
-	var detect migratelint.ChangeDetector
+	if err := checkDirVersion(cmd, dir, dev); err != nil {
+		return err
+	}
+	var (
+		detect   migratelint.ChangeDetector
+		versions = flags.fromV != "" || flags.toV != ""
+	)
 	switch {
-	case flags.latest == 0 && flags.gitBase == "":
-		return fmt.Errorf("--%s or --%s is required", flagLatest, flagGitBase)
+	case flags.latest == 0 && flags.gitBase == "" && !versions:
+		return fmt.Errorf("--%s, --%s or a version range (--%s, --%s) is required", flagLatest, flagGitBase, flagFromVersion, flagToVersion)
 	case flags.latest > 0 && flags.gitBase != "":
 		return fmt.Errorf("--%s and --%s are mutually exclusive", flagLatest, flagGitBase)
+	case versions && (flags.latest > 0 || flags.gitBase != ""):
+		return fmt.Errorf("--%s and --%s cannot be used with --%s or --%s", flagFromVersion, flagToVersion, flagLatest, flagGitBase)
+	case versions:
+		detect = migratelint.VersionChanges(dir, flags.fromV, flags.toV)
 	case flags.latest > 0:
 		detect = migratelint.LatestChanges(dir, int(flags.latest))
 	case flags.gitBase != "":
@@ -186,13 +211,17 @@ func migrateLintRun(cmd *cobra.Command, _ []string, flags migrateLintFlags, env
 		}
 	}
 	format := migratelint.DefaultTemplate
-	if f := flags.logFormat; f != "" {
+	switch f := flags.logFormat; f {
+	case "":
+	case migratelint.FormatGitHub:
+		format = migratelint.GitHubTemplate
+	default:
 		format, err = template.New("format").Funcs(migratelint.TemplateFuncs).Parse(f)
 		if err != nil {
 			return fmt.Errorf("parse format: %w", err)
 		}
 	}
-	az, err := sqlcheck.AnalyzerFor(dev.Name, env.Lint.Remain())
+	az, err := lintAnalyzers(dev, env)
 	if err != nil {
 		return err
 	}
@@ -205,6 +234,12 @@ func migrateLintRun(cmd *cobra.Command, _ []string, flags migrateLintFlags, env
 			W: cmd.OutOrStdout(),
 		},
 		Analyzers: az,
+		Exclude:   flags.exclude,
+	}
+	if flags.replayCache != "" {
+		// The scope of the lint command is distinguished from
+		// the one of the diff command, that replays all files.
+		r.ReplayCache = replayCache(flags.replayCache, dev, "lint", flags.dirURL, flags.devURL)
 	}
 	err = r.Run(cmd.Context())
 	// Print the error in case it was not printed before.
@@ -213,6 +248,34 @@ func migrateLintRun(cmd *cobra.Command, _ []string, flags migrateLintFlags, env
 	return err
 }
 
+// lintAnalyzers returns the analyzers configured for the given dev database and environment.
+func lintAnalyzers(dev *sqlclient.Client, env *Env) ([]sqlcheck.Analyzer, error) {
+	az, err := sqlcheck.AnalyzerFor(dev.Name, env.Lint.Remain())
+	if err != nil {
+		return nil, err
+	}
+	cz, err := sqlcheck.CustomAnalyzers(env.Lint.Remain())
+	if err != nil {
+		return nil, err
+	}
+	az = append(az, cz...)
+	ez, err := external.New(env.Lint.Remain())
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range ez {
+		az = append(az, e)
+	}
+	pz, err := protect.New(env.Lint.Remain(), env.Protect)
+	if err != nil {
+		return nil, err
+	}
+	if len(pz.Patterns) > 0 || len(pz.Labels) > 0 {
+		az = append(az, pz)
+	}
+	return az, nil
+}
+
 func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, env *Env) error {
 	ctx := cmd.Context()
 	dev, err := sqlclient.Open(ctx, flags.devURL)
@@ -236,9 +299,17 @@ func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, e
 	if err != nil {
 		return err
 	}
+	sign, err := dirSigner(dir, flags.signKey)
+	if err != nil {
+		return err
+	}
 	if flags.edit {
 		dir = &editDir{dir}
 	}
+	if flags.check {
+		check := &syncCheckDir{Dir: dir}
+		dir, sign = check, func() error { return check.report(cmd) }
+	}
 	var name, indent string
 	if len(args) > 0 {
 		name = args[0]
@@ -262,7 +333,10 @@ func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, e
 			Dev:     dev,
 			Options: diffOpts,
 		})
-		return maskNoPlan(cmd, err)
+		if err != nil {
+			return maskNoPlan(cmd, err)
+		}
+		return sign()
 	}
 	// Get a state reader for the desired state.
 	desired, err := stateReader(ctx, env, &stateReaderConfig{
@@ -270,6 +344,7 @@ func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, e
 		dev:     dev,
 		client:  dev,
 		schemas: flags.schemas,
+		exclude: flags.exclude,
 		vars:    env.Vars(),
 	})
 	if err != nil {
@@ -280,6 +355,17 @@ func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, e
 		migrate.PlanFormat(f),
 		migrate.PlanWithIndent(indent),
 		migrate.PlanWithDiffOptions(diffOpts...),
+		migrate.PlanWithProtect(env.Protect...),
+		migrate.PlanWithExclude(flags.exclude...),
+	}
+	if flags.replayCache != "" {
+		opts = append(opts, migrate.PlanWithReplayCache(replayCache(flags.replayCache, dev, slices.Concat([]string{flags.dirURL, flags.devURL}, flags.schemas, flags.exclude)...)))
+	}
+	if flags.noCombine {
+		opts = append(opts, migrate.PlanWithNoCombine())
+	}
+	if flags.checkReserved {
+		opts = append(opts, migrate.PlanWithCheckReserved())
 	}
 	if dev.URL.Schema != "" {
 		// Disable tables qualifier in schema-mode.
@@ -300,7 +386,10 @@ func migrateDiffRun(cmd *cobra.Command, args []string, flags migrateDiffFlags, e
 	case err != nil:
 		return maskNoPlan(cmd, err)
 	default:
-		return pl.WritePlan(plan)
+		if err := pl.WritePlan(plan); err != nil {
+			return err
+		}
+		return sign()
 	}
 }
 
@@ -351,7 +440,7 @@ func schemaApplyRun(cmd *cobra.Command, flags schemaApplyFlags, env *Env) error
 		}
 	}
 	if flags.devURL != "" {
-		if dev, err = sqlclient.Open(ctx, flags.devURL); err != nil {
+		if dev, err = openWait(ctx, flags.waitTimeout, flags.devURL, openURL); err != nil {
 			return err
 		}
 		defer dev.Close()
@@ -360,6 +449,7 @@ func schemaApplyRun(cmd *cobra.Command, flags schemaApplyFlags, env *Env) error
 		urls:    []string{flags.url},
 		schemas: flags.schemas,
 		exclude: flags.exclude,
+		wait:    flags.waitTimeout,
 	})
 	if err != nil {
 		return err
@@ -369,6 +459,19 @@ func schemaApplyRun(cmd *cobra.Command, flags schemaApplyFlags, env *Env) error
 	if !ok {
 		return errors.New("--url must be a database connection")
 	}
+	// Serialize with other applies that share the same lock
+	// name before the current state of the database is inspected.
+	if flags.lockName != "" {
+		unlock, err := client.Driver.Lock(ctx, flags.lockName, flags.lockTimeout)
+		if err != nil {
+			return fmt.Errorf("acquiring database lock: %w", err)
+		}
+		// If unlocking fails notify the user about it.
+		defer func() { cobra.CheckErr(unlock()) }()
+	}
+	if flags.maintain {
+		return maintainPartitions(cmd, env, client, flags)
+	}
 	to, err := stateReader(ctx, env, &stateReaderConfig{
 		urls:    flags.toURLs,
 		dev:     dev,
@@ -381,10 +484,39 @@ func schemaApplyRun(cmd *cobra.Command, flags schemaApplyFlags, env *Env) error
 		return err
 	}
 	defer to.Close()
-	diff, err := computeDiff(ctx, client, from, to, diffOptions(cmd, env)...)
+	diff, err := computeDiff(ctx, client, from, to, env.TypeOverrides(), diffOptions(cmd, env)...)
 	if err != nil {
 		return err
 	}
+	diff.changes = limitChanges(diff.changes, flags.limitTo, from.Schema)
+	// Destructive changes are applied only in the apply windows of the environment.
+	if !flags.dryRun && len(env.ApplyWindows) > 0 {
+		ok, err := destructiveChanges(ctx, env, diff.changes)
+		if err != nil {
+			return err
+		}
+		if ok {
+			switch waited, err := waitApplyWindow(cmd, env, flags.wait); {
+			case err != nil:
+				return err
+			// Plan again, as the database might have changed while waiting.
+			case waited:
+				if diff, err = computeDiff(ctx, client, from, to, env.TypeOverrides(), diffOptions(cmd, env)...); err != nil {
+					return err
+				}
+				diff.changes = limitChanges(diff.changes, flags.limitTo, from.Schema)
+			}
+		}
+	}
+	if err := migrate.CheckProtected(diff.changes, env.Protect); err != nil {
+		return err
+	}
+	if err := env.CheckDropSchema(diff.changes); err != nil {
+		return err
+	}
+	if err := mayCheckPrivileges(ctx, client, diff.changes, flags.checkPrivs); err != nil {
+		return err
+	}
 	maySuggestUpgrade(cmd)
 	// Returning at this stage should
 	// not trigger the help message.
@@ -396,29 +528,39 @@ func schemaApplyRun(cmd *cobra.Command, flags schemaApplyFlags, env *Env) error
 		var (
 			applied int
 			plan    *migrate.Plan
+			stats   []*cmdlog.StmtStats
 			cause   *cmdlog.StmtError
 			out     = cmd.OutOrStdout()
 		)
-		if plan, err = client.PlanChanges(ctx, "", changes, planOptions(client)...); err != nil {
+		if plan, err = client.PlanChanges(ctx, "", changes, flags.planOptions(client)...); err != nil {
 			return err
 		}
-		if err = applyChanges(ctx, client, changes, flags.txMode); err == nil {
+		if flags.explain {
+			cmdlog.ExplainPlan(client.Driver)(plan)
+		}
+		if stats, err = applyChanges(ctx, cmd, client, changes, flags); err == nil {
 			applied = len(plan.Changes)
 		} else if i, ok := err.(interface{ Applied() int }); ok && i.Applied() < len(plan.Changes) {
 			applied, cause = i.Applied(), &cmdlog.StmtError{Stmt: plan.Changes[i.Applied()].Cmd, Text: err.Error()}
 		} else {
 			cause = &cmdlog.StmtError{Text: err.Error()}
 		}
-		err1 := format.Execute(out, cmdlog.NewSchemaApply(ctx, cmdlog.NewEnv(client, nil), plan.Changes[:applied], plan.Changes[applied:], cause))
-		return errors.Join(err, err1)
+		report := cmdlog.NewSchemaApply(ctx, cmdlog.NewEnv(client, nil), plan.Changes[:applied], plan.Changes[applied:], cause)
+		report.Stats = stats
+		return errors.Join(err, format.Execute(out, report))
 	default:
-		switch err := summary(cmd, client, changes, format); {
+		var edit []func(*migrate.Plan)
+		if flags.explain {
+			edit = append(edit, cmdlog.ExplainPlan(client.Driver))
+		}
+		switch err := summary(cmd, client, changes, format, flags.planOptions(client), edit...); {
 		case err != nil:
 			return err
 		case flags.dryRun:
 			return nil
 		case flags.autoApprove:
-			return applyChanges(ctx, client, changes, flags.txMode)
+			_, err := applyChanges(ctx, cmd, client, changes, flags)
+			return err
 		default:
 			return promptApply(cmd, flags, diff, client, dev)
 		}
@@ -437,7 +579,7 @@ func applySchemaClean(cmd *cobra.Command, client *sqlclient.Client, drop []schem
 		cmd.Println("Nothing to drop")
 		return nil
 	}
-	if err := summary(cmd, client, drop, cmdlog.SchemaPlanTemplate); err != nil {
+	if err := summary(cmd, client, drop, cmdlog.SchemaPlanTemplate, planOptions(client)); err != nil {
 		return err
 	}
 	if flags.autoApprove || promptUser(cmd) {
@@ -448,11 +590,14 @@ func applySchemaClean(cmd *cobra.Command, client *sqlclient.Client, drop []schem
 	return nil
 }
 
-func summary(cmd *cobra.Command, c *sqlclient.Client, changes []schema.Change, t *template.Template) error {
-	p, err := c.PlanChanges(cmd.Context(), "", changes, planOptions(c)...)
+func summary(cmd *cobra.Command, c *sqlclient.Client, changes []schema.Change, t *template.Template, opts []migrate.PlanOption, edit ...func(*migrate.Plan)) error {
+	p, err := c.PlanChanges(cmd.Context(), "", changes, opts...)
 	if err != nil {
 		return err
 	}
+	for i := range edit {
+		edit[i](p)
+	}
 	return t.Execute(
 		cmd.OutOrStdout(),
 		cmdlog.NewSchemaPlan(cmd.Context(), cmdlog.NewEnv(c, nil), p.Changes, nil),
@@ -460,8 +605,12 @@ func summary(cmd *cobra.Command, c *sqlclient.Client, changes []schema.Change, t
 }
 
 func promptApply(cmd *cobra.Command, flags schemaApplyFlags, diff *diff, client, _ *sqlclient.Client) error {
-	if !flags.dryRun && (flags.autoApprove || promptUser(cmd)) {
-		return applyChanges(cmd.Context(), client, diff.changes, flags.txMode)
+	if flags.interactive {
+		return applyInteractive(cmd, client, diff.changes, flags)
+	}
+	if !flags.dryRun && (flags.autoApprove || promptUser(cmd) && confirmDropSchemas(cmd, diff.changes)) {
+		_, err := applyChanges(cmd.Context(), cmd, client, diff.changes, flags)
+		return err
 	}
 	return nil
 }
@@ -484,3 +633,8 @@ func diffOptions(_ *cobra.Command, env *Env) []schema.DiffOption {
 func (*Env) openClient(ctx context.Context, u string) (*sqlclient.Client, error) {
 	return sqlclient.Open(ctx, u)
 }
+
+// openURL opens a client to the given URL. Used for opening dev-database connections.
+func openURL(ctx context.Context, u string) (*sqlclient.Client, error) {
+	return sqlclient.Open(ctx, u)
+}
@@ -0,0 +1,418 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/fatih/color"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/exp/maps"
+)
+
+const (
+	// userConfigPath is the path of the user-level configuration file,
+	// relative to the user configuration directory.
+	userConfigPath = "atlas/config.hcl"
+	// envNoUpdateNotifier and envNoColor are the environment
+	// variables that take precedence over the user configuration.
+	envNoUpdateNotifier = "ATLAS_NO_UPDATE_NOTIFIER"
+	envNoColor          = "NO_COLOR"
+)
+
+// Keys of the user-level configuration.
+const (
+	configDevURL               = "dev_url"
+	configNoColor              = "no_color"
+	configNoUpdateNotifier     = "no_update_notifier"
+	configNoUpgradeSuggestions = "no_upgrade_suggestions"
+)
+
+// configKeys holds the types of the user-level configuration keys.
+var configKeys = map[string]cty.Type{
+	configDevURL:               cty.String,
+	configNoColor:              cty.Bool,
+	configNoUpdateNotifier:     cty.Bool,
+	configNoUpgradeSuggestions: cty.Bool,
+}
+
+// UserConfig holds the user-level defaults, read from ~/.config/atlas/config.hcl (or
+// $XDG_CONFIG_HOME/atlas/config.hcl). These defaults are layered under the project file
+// and the command flags, i.e., values set on the selected environment or by flags take
+// precedence over the ones defined in the user configuration.
+type UserConfig struct {
+	DevURL               string // Default dev-database URL, e.g., docker://postgres/16/dev.
+	NoColor              bool   // Disable colored output.
+	NoUpdateNotifier     bool   // Disable checking for version updates.
+	NoUpgradeSuggestions bool   // Disable upgrade suggestions of the community edition.
+}
+
+// UserConfigPath returns the path of the user-level configuration file.
+func UserConfigPath() (string, error) {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, userConfigPath), nil
+	}
+	return homedir.Expand(filepath.Join("~", ".config", userConfigPath))
+}
+
+// LoadUserConfig reads the user-level configuration. An empty configuration
+// is returned in case the configuration file does not exist.
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	vs, err := readUserConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &UserConfig{}
+	if v, ok := vs[configDevURL]; ok {
+		c.DevURL = v.AsString()
+	}
+	c.NoColor = vs[configNoColor].True()
+	c.NoUpdateNotifier = vs[configNoUpdateNotifier].True()
+	c.NoUpgradeSuggestions = vs[configNoUpgradeSuggestions].True()
+	return c, nil
+}
+
+// readUserConfig reads and validates the attributes defined in the given configuration file.
+func readUserConfig(path string) (map[string]cty.Value, error) {
+	vs := map[string]cty.Value{
+		configNoColor:              cty.False,
+		configNoUpdateNotifier:     cty.False,
+		configNoUpgradeSuggestions: cty.False,
+	}
+	buf, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return vs, nil
+	case err != nil:
+		return nil, err
+	}
+	f, diags := hclsyntax.ParseConfig(buf, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	attrs, diags := f.Body.JustAttributes()
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	for k, a := range attrs {
+		v, diags := a.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		if v, err = configValue(k, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		vs[k] = v
+	}
+	return vs, nil
+}
+
+// configValue validates the value of the given configuration key.
+func configValue(k string, v cty.Value) (cty.Value, error) {
+	t, ok := configKeys[k]
+	if !ok {
+		keys := maps.Keys(configKeys)
+		slices.Sort(keys)
+		return cty.NilVal, fmt.Errorf("unknown configuration key %q. Expect one of: %s", k, strings.Join(keys, ", "))
+	}
+	if v.IsNull() || !v.IsKnown() || !v.Type().Equals(t) {
+		return cty.NilVal, fmt.Errorf("expect %s value for configuration key %q", t.FriendlyName(), k)
+	}
+	return v, nil
+}
+
+// apply sets the user-level defaults of the given command. Unlike maySetFlag, flag values
+// are not marked as changed, so environment values set later by the command take precedence.
+func (c *UserConfig) apply(cmd *cobra.Command) error {
+	if c.NoColor {
+		color.NoColor = true
+	}
+	if f := cmd.Flags().Lookup(flagDevURL); f != nil && !f.Changed && c.DevURL != "" {
+		if err := f.Value.Set(c.DevURL); err != nil {
+			return fmt.Errorf("setting flag %q from user configuration: %w", flagDevURL, err)
+		}
+	}
+	return nil
+}
+
+// configCmd represents the subcommand 'atlas config'.
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and set user-level configuration defaults.",
+		Long: `'atlas config' manages the user-level defaults stored in ~/.config/atlas/config.hcl
+(or $XDG_CONFIG_HOME/atlas/config.hcl). These defaults apply to all projects, and are layered
+under the project file (atlas.hcl) and the command flags, that take precedence over them.
+
+Supported keys:
+  dev_url                 default URL of the dev database, e.g., "docker://postgres/16/dev"
+  no_color                disable colored output
+  no_update_notifier      disable checking for version updates
+  no_upgrade_suggestions  disable the upgrade suggestions of the community edition`,
+	}
+	cmd.AddCommand(
+		configListCmd(),
+		configSetCmd(),
+		configUnsetCmd(),
+		configDoctorCmd(),
+	)
+	return cmd
+}
+
+// configListCmd represents the 'atlas config list' subcommand.
+func configListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the values defined in the user configuration.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			path, err := UserConfigPath()
+			if err != nil {
+				return err
+			}
+			vs, err := readUserConfig(path)
+			if err != nil {
+				return err
+			}
+			keys := maps.Keys(configKeys)
+			slices.Sort(keys)
+			for _, k := range keys {
+				if v, ok := vs[k]; ok {
+					cmd.Printf("%s = %s\n", k, configString(v))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// configSetCmd represents the 'atlas config set' subcommand.
+func configSetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set KEY VALUE",
+		Short: "Set a value in the user configuration.",
+		Example: `  atlas config set dev_url "docker://postgres/16/dev?search_path=public"
+  atlas config set no_update_notifier true`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			k, v := args[0], cty.StringVal(args[1])
+			if configKeys[k] == cty.Bool {
+				b, err := strconv.ParseBool(args[1])
+				if err != nil {
+					return fmt.Errorf("expect bool value for configuration key %q", k)
+				}
+				v = cty.BoolVal(b)
+			}
+			if _, err := configValue(k, v); err != nil {
+				return err
+			}
+			return writeUserConfig(func(b *hclwrite.Body) {
+				b.SetAttributeValue(k, v)
+			})
+		},
+	}
+}
+
+// configUnsetCmd represents the 'atlas config unset' subcommand.
+func configUnsetCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset KEY",
+		Short: "Remove a value from the user configuration.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, ok := configKeys[args[0]]; !ok {
+				_, err := configValue(args[0], cty.NilVal)
+				return err
+			}
+			return writeUserConfig(func(b *hclwrite.Body) {
+				b.RemoveAttribute(args[0])
+			})
+		},
+	}
+}
+
+// writeUserConfig applies the given edit on the user configuration file. Comments
+// and the formatting of existing attributes are kept as is.
+func writeUserConfig(edit func(*hclwrite.Body)) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	buf, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	f, diags := hclwrite.ParseConfig(buf, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return diags
+	}
+	edit(f.Body())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, f.Bytes(), 0644)
+}
+
+// configDoctorCmd represents the 'atlas config doctor' subcommand.
+func configDoctorCmd() *cobra.Command {
+	var (
+		devURL string
+		cmd    = &cobra.Command{
+			Use:   "doctor",
+			Short: "Print the effective configuration and its sources.",
+			Long: `'atlas config doctor' prints the effective value of each configuration key, resolved from
+the command flags, the environment variables, the selected project environment and the user
+configuration, and the source each value was taken from.`,
+			Example: `  atlas config doctor
+  atlas config doctor --env local`,
+			Args: cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				return configDoctorRun(cmd, devURL)
+			},
+		}
+	)
+	addGlobalFlags(cmd.Flags())
+	addFlagDevURL(cmd.Flags(), &devURL)
+	return cmd
+}
+
+// configSetting describes the resolved value of a configuration key.
+type configSetting struct {
+	key, value, source string
+}
+
+func configDoctorRun(cmd *cobra.Command, devURL string) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	user, err := readUserConfig(path)
+	if err != nil {
+		return err
+	}
+	var env *Env
+	if GlobalFlags.SelectedEnv != "" {
+		_, envs, err := EnvByName(cmd, GlobalFlags.SelectedEnv, GlobalFlags.Vars)
+		if err != nil {
+			return err
+		}
+		if len(envs) != 1 {
+			return fmt.Errorf("multi-environment %q is not supported", GlobalFlags.SelectedEnv)
+		}
+		env = envs[0]
+	}
+	var (
+		fromUser = func(k string) (configSetting, bool) {
+			switch v, ok := user[k]; {
+			case !ok:
+			case v.Type() == cty.String:
+				return configSetting{key: k, value: v.AsString(), source: "user config"}, true
+			case v.True():
+				return configSetting{key: k, value: "true", source: "user config"}, true
+			}
+			return configSetting{}, false
+		}
+		fromEnvVar = func(k, name string) (configSetting, bool) {
+			if v := os.Getenv(name); v != "" {
+				return configSetting{key: k, value: "true", source: fmt.Sprintf("environment variable %s", name)}, true
+			}
+			return configSetting{}, false
+		}
+		resolve = func(k string, layers ...func() (configSetting, bool)) configSetting {
+			for _, l := range layers {
+				if s, ok := l(); ok {
+					return s
+				}
+			}
+			s := configSetting{key: k, source: "default"}
+			if configKeys[k] == cty.Bool {
+				s.value = "false"
+			}
+			return s
+		}
+		settings = []configSetting{
+			resolve(configDevURL,
+				func() (configSetting, bool) {
+					return configSetting{key: configDevURL, value: devURL, source: "flag --dev-url"}, cmd.Flags().Changed(flagDevURL)
+				},
+				func() (configSetting, bool) {
+					if env == nil || env.DevURL == "" {
+						return configSetting{}, false
+					}
+					return configSetting{key: configDevURL, value: env.DevURL, source: fmt.Sprintf("project env %q", env.Name)}, true
+				},
+				func() (configSetting, bool) { return fromUser(configDevURL) },
+			),
+			resolve(configNoColor,
+				func() (configSetting, bool) { return fromEnvVar(configNoColor, envNoColor) },
+				func() (configSetting, bool) { return fromUser(configNoColor) },
+			),
+			resolve(configNoUpdateNotifier,
+				func() (configSetting, bool) { return fromEnvVar(configNoUpdateNotifier, envNoUpdateNotifier) },
+				func() (configSetting, bool) { return fromUser(configNoUpdateNotifier) },
+			),
+			resolve(configNoUpgradeSuggestions,
+				func() (configSetting, bool) {
+					return fromEnvVar(configNoUpgradeSuggestions, envSkipUpgradeSuggestions)
+				},
+				func() (configSetting, bool) { return fromUser(configNoUpgradeSuggestions) },
+			),
+		}
+	)
+	cmd.Printf("User config:    %s (%s)\n", path, fileStatus(path))
+	project := strings.TrimPrefix(GlobalFlags.ConfigURL, "file://")
+	cmd.Printf("Project config: %s (%s)\n", project, fileStatus(project))
+	if env != nil {
+		cmd.Printf("Environment:    %s\n", env.Name)
+	}
+	cmd.Println()
+	for _, s := range settings {
+		v := s.value
+		if v == "" {
+			v = "<empty>"
+		} else if s.key == configDevURL {
+			v = sqlclient.Redact(v)
+		}
+		cmd.Printf("%-24s %-40s %s\n", s.key, v, s.source)
+	}
+	return nil
+}
+
+// configString returns the HCL representation of the configuration value.
+func configString(v cty.Value) string {
+	if v.Type() == cty.Bool {
+		return strconv.FormatBool(v.True())
+	}
+	return strconv.Quote(v.AsString())
+}
+
+// fileStatus reports if the given file exists.
+func fileStatus(path string) string {
+	switch _, err := os.Stat(path); {
+	case err == nil:
+		return "found"
+	case errors.Is(err, os.ErrNotExist):
+		return "not found"
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_SetList(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path := filepath.Join(dir, "atlas", "config.hcl")
+
+	s, err := runCmd(configListCmd())
+	require.NoError(t, err)
+	require.Equal(t, "no_color = false\nno_update_notifier = false\nno_upgrade_suggestions = false\n", s)
+
+	_, err = runCmd(configSetCmd(), "dev_url", "sqlite://dev?mode=memory")
+	require.NoError(t, err)
+	_, err = runCmd(configSetCmd(), "no_color", "true")
+	require.NoError(t, err)
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "dev_url  = \"sqlite://dev?mode=memory\"\nno_color = true\n", string(buf))
+	c, err := LoadUserConfig()
+	require.NoError(t, err)
+	require.Equal(t, &UserConfig{DevURL: "sqlite://dev?mode=memory", NoColor: true}, c)
+
+	// Comments are kept on edit.
+	require.NoError(t, os.WriteFile(path, append([]byte("# Defaults for all projects.\n"), buf...), 0644))
+	_, err = runCmd(configUnsetCmd(), "no_color")
+	require.NoError(t, err)
+	buf, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "# Defaults for all projects.\ndev_url = \"sqlite://dev?mode=memory\"\n", string(buf))
+	s, err = runCmd(configListCmd())
+	require.NoError(t, err)
+	require.Equal(t, "dev_url = \"sqlite://dev?mode=memory\"\nno_color = false\nno_update_notifier = false\nno_upgrade_suggestions = false\n", s)
+
+	_, err = runCmd(configSetCmd(), "color", "true")
+	require.EqualError(t, err, `unknown configuration key "color". Expect one of: dev_url, no_color, no_update_notifier, no_upgrade_suggestions`)
+	_, err = runCmd(configSetCmd(), "no_color", "yes")
+	require.EqualError(t, err, `expect bool value for configuration key "no_color"`)
+	_, err = runCmd(configUnsetCmd(), "color")
+	require.ErrorContains(t, err, `unknown configuration key "color"`)
+
+	require.NoError(t, os.WriteFile(path, []byte("no_color = \"true\"\n"), 0644))
+	_, err = LoadUserConfig()
+	require.EqualError(t, err, path+`: expect bool value for configuration key "no_color"`)
+}
+
+func TestConfig_Layers(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(envNoUpdateNotifier, "")
+	_, err := runCmd(configSetCmd(), "dev_url", "sqlite://user?mode=memory")
+	require.NoError(t, err)
+	_, err = runCmd(configSetCmd(), "no_update_notifier", "true")
+	require.NoError(t, err)
+
+	// The dev_url is used by commands as the default of the --dev-url flag.
+	var (
+		u   = openSQLite(t, "")
+		src = filepath.Join(t.TempDir(), "schema.sql")
+	)
+	require.NoError(t, os.WriteFile(src, []byte("create table users (id int);"), 0600))
+	s, err := runCmd(schemaApplyCmd(), "-u", u, "--to", "file://"+src, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, s, "CREATE TABLE `users`")
+
+	project := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(project, []byte(`
+env "local" {
+  dev = "sqlite://env?mode=memory"
+}
+env "empty" {}
+`), 0600))
+	s, err = runCmd(configDoctorCmd(), "-c", "file://"+project, "--env", "local")
+	require.NoError(t, err)
+	require.Contains(t, s, "User config:    "+filepath.Join(dir, "atlas", "config.hcl")+" (found)\n")
+	require.Contains(t, s, "Project config: "+project+" (found)\n")
+	require.Contains(t, s, "Environment:    local\n")
+	require.Regexp(t, `dev_url\s+sqlite://env\?mode=memory\s+project env "local"\n`, s)
+	require.Regexp(t, `no_update_notifier\s+true\s+user config\n`, s)
+	require.Regexp(t, `no_upgrade_suggestions\s+false\s+default\n`, s)
+
+	s, err = runCmd(configDoctorCmd(), "-c", "file://"+project, "--env", "empty")
+	require.NoError(t, err)
+	require.Regexp(t, `dev_url\s+sqlite://user\?mode=memory\s+user config\n`, s)
+
+	s, err = runCmd(configDoctorCmd(), "-c", "file://"+project, "--env", "local", "--dev-url", "sqlite://flag?mode=memory")
+	require.NoError(t, err)
+	require.Regexp(t, `dev_url\s+sqlite://flag\?mode=memory\s+flag --dev-url\n`, s)
+}
@@ -0,0 +1,168 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/spf13/cobra"
+)
+
+// The states of 'migrate apply' as reported by its health endpoints.
+const (
+	applyStatePending   = "pending"
+	applyStateRunning   = "running"
+	applyStateSucceeded = "succeeded"
+	applyStateFailed    = "failed"
+)
+
+type (
+	// applyHealth tracks the progress of 'migrate apply' and exposes it over HTTP, to be used
+	// by the liveness and readiness probes of orchestration platforms (e.g., Kubernetes):
+	//
+	//	/livez   responds with 200 unless the migration failed.
+	//	/readyz  responds with 200 once all pending migrations were applied.
+	//	/status  responds with the progress of the migration in JSON format.
+	applyHealth struct {
+		srv    *http.Server
+		mu     sync.Mutex
+		status applyStatus
+	}
+
+	// applyStatus describes the progress of 'migrate apply'.
+	applyStatus struct {
+		State   string     `json:"State"`             // One of: pending, running, succeeded or failed.
+		Start   time.Time  `json:"Start"`             // Start time of the command.
+		End     *time.Time `json:"End,omitempty"`     // End time, set once the command is done.
+		Current string     `json:"Current,omitempty"` // Version of the file being applied.
+		Pending int        `json:"Pending"`           // Number of files to apply.
+		Applied int        `json:"Applied"`           // Number of files applied.
+		Error   string     `json:"Error,omitempty"`   // Error, set if the migration failed.
+	}
+
+	// healthLogger records the progress of the execution
+	// before passing the log entries to the underlying logger.
+	healthLogger struct {
+		migrate.Logger
+		h *applyHealth
+	}
+)
+
+// newApplyHealth returns a new applyHealth in its pending state.
+func newApplyHealth() *applyHealth {
+	return &applyHealth{status: applyStatus{State: applyStatePending, Start: time.Now().UTC()}}
+}
+
+// serveApplyHealth starts serving the health endpoints of 'migrate apply' on the given address.
+func serveApplyHealth(cmd *cobra.Command, addr string) (*applyHealth, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("health server: %w", err)
+	}
+	h := newApplyHealth()
+	h.srv = &http.Server{Handler: h.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := h.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			cmd.PrintErrf("Error: health server: %v\n", err)
+		}
+	}()
+	return h, nil
+}
+
+// handler returns the HTTP handler of the health endpoints.
+func (h *applyHealth) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, _ *http.Request) {
+		s := h.get()
+		h.write(w, s, s.State != applyStateFailed)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		s := h.get()
+		h.write(w, s, s.State == applyStateSucceeded)
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.get())
+	})
+	return mux
+}
+
+// write responds with the state of the migration, and with
+// the "service unavailable" status code in case it is not ok.
+func (*applyHealth) write(w http.ResponseWriter, s applyStatus, ok bool) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	io.WriteString(w, s.State+"\n")
+}
+
+// get returns a copy of the current status.
+func (h *applyHealth) get() applyStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.status
+}
+
+// logger returns a migrate.Logger that records the
+// progress of the execution before passing it to l.
+func (h *applyHealth) logger(l migrate.Logger) migrate.Logger {
+	if h == nil {
+		return l
+	}
+	return &healthLogger{Logger: l, h: h}
+}
+
+// Log implements the migrate.Logger interface.
+func (l *healthLogger) Log(e migrate.LogEntry) {
+	l.h.mu.Lock()
+	s := &l.h.status
+	switch e := e.(type) {
+	case migrate.LogExecution:
+		s.State = applyStateRunning
+		s.Pending += len(e.Files)
+	case migrate.LogFile:
+		// A new file means the previous one was applied.
+		if s.Current != "" {
+			s.Applied++
+		}
+		s.Current = e.File.Version()
+	case migrate.LogDone:
+		if s.Current != "" {
+			s.Applied++
+			s.Current = ""
+		}
+	}
+	l.h.mu.Unlock()
+	l.Logger.Log(e)
+}
+
+// close records the terminal status of the
+// migration and stops serving the endpoints.
+func (h *applyHealth) close(err error) error {
+	h.mu.Lock()
+	end := time.Now().UTC()
+	h.status.End = &end
+	if err != nil {
+		h.status.State, h.status.Error = applyStateFailed, sqlclient.Redact(err.Error())
+	} else {
+		h.status.State, h.status.Current = applyStateSucceeded, ""
+	}
+	h.mu.Unlock()
+	if h.srv == nil {
+		return nil
+	}
+	return h.srv.Close()
+}
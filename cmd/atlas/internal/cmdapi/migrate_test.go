@@ -6,8 +6,12 @@ package cmdapi
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
 	"database/sql"
 	"encoding/json"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
@@ -23,11 +27,13 @@ import (
 	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
 	migrate2 "ariga.io/atlas/cmd/atlas/internal/migrate"
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/postgres"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
 	"ariga.io/atlas/sql/sqlite"
 	_ "ariga.io/atlas/sql/sqlite"
 	_ "ariga.io/atlas/sql/sqlite/sqlitecheck"
+	"ariga.io/atlas/sql/sqltool"
 
 	"github.com/fatih/color"
 	"github.com/google/uuid"
@@ -431,6 +437,55 @@ env "local" {
 		require.NoError(t, err)
 	})
 
+	t.Run("MultiTarget", func(t *testing.T) {
+		p := t.TempDir()
+		h := `
+variable "urls" {
+  type = list(string)
+}
+
+env "local" {
+  for_each = toset(var.urls)
+  url = each.value
+  dev = "sqlite://ci?mode=memory&cache=shared&_fk=1"
+  migration {
+    dir = "file://testdata/sqlite"
+  }
+}
+`
+		path := filepath.Join(p, "atlas.hcl")
+		require.NoError(t, os.WriteFile(path, []byte(h), 0600))
+		run := func(args ...string) (string, error) {
+			cmd := migrateCmd()
+			cmd.AddCommand(migrateApplyCmd())
+			return runCmd(cmd, append([]string{
+				"apply",
+				"-c", "file://" + path,
+				"--env", "local",
+				"--var", "urls=invalid://target",
+				"--var", fmt.Sprintf("urls=sqlite://file:%s?cache=shared&_fk=1", filepath.Join(p, "test1.db")),
+				"--var", fmt.Sprintf("urls=sqlite://file:%s?cache=shared&_fk=1", filepath.Join(p, "test2.db")),
+			}, args...)...)
+		}
+
+		// Fail-fast.
+		s, err := run("--rate-limit", "1ms")
+		require.EqualError(t, err, `sql/sqlclient: unknown driver "invalid". See: https://atlasgo.io/url`)
+		require.Contains(t, s, "Targets: 3 total, 0 succeeded, 1 failed, 2 skipped")
+		require.Contains(t, s, `  - invalid://target: Error: sql/sqlclient: unknown driver "invalid"`)
+		_, err = os.Stat(filepath.Join(p, "test1.db"))
+		require.True(t, os.IsNotExist(err))
+
+		// Continue on error.
+		s, err = run("--max-parallel", "2", "--continue-on-error")
+		require.EqualError(t, err, `sql/sqlclient: unknown driver "invalid". See: https://atlasgo.io/url`)
+		require.Contains(t, s, "Targets: 3 total, 2 succeeded, 1 failed, 0 skipped")
+		require.Equal(t, 2, strings.Count(s, "Migrating to version 20220318104615 (2 migrations in total)"), "execution per environment")
+
+		_, err = run("--max-parallel", "0")
+		require.EqualError(t, err, "invalid --max-parallel value: 0. Expect a positive number")
+	})
+
 	t.Run("FromDataSrc", func(t *testing.T) {
 		var (
 			h = `
@@ -598,6 +653,54 @@ env "prod" {
 			require.Contains(t, s, fmt.Sprintf("create table users_%s2 (c text);", e))
 		}
 	})
+
+	t.Run("TenantQuery", func(t *testing.T) {
+		var (
+			p   = t.TempDir()
+			u   = fmt.Sprintf("sqlite://file:%s?cache=shared&_fk=1", filepath.Join(p, "tenants.db"))
+			dbs = []string{filepath.Join(p, "test1.db"), filepath.Join(p, "test2.db")}
+		)
+		db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?cache=shared&_fk=1", filepath.Join(p, "tenants.db")))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		_, err = db.Exec("CREATE TABLE `tenants` (`url` TEXT)")
+		require.NoError(t, err)
+		for _, d := range append(dbs, dbs[0]) {
+			_, err = db.Exec("INSERT INTO `tenants` (`url`) VALUES (?)", fmt.Sprintf("sqlite://file:%s?cache=shared&_fk=1", d))
+			require.NoError(t, err)
+		}
+		run := func(args ...string) (string, error) {
+			cmd := migrateCmd()
+			cmd.AddCommand(migrateApplyCmd())
+			return runCmd(cmd, append([]string{"apply", "--url", u, "--dir", "file://testdata/sqlite"}, args...)...)
+		}
+		s, err := run("--tenant-query", "SELECT url FROM tenants", "--max-parallel", "2")
+		require.NoError(t, err)
+		require.Equal(t, 2, strings.Count(s, "Migrating to version 20220318104615 (2 migrations in total)"), "execution per tenant")
+		require.Contains(t, s, "Targets: 2 total, 2 succeeded, 0 failed, 0 skipped")
+		for _, d := range dbs {
+			_, err = os.Stat(d)
+			require.NoError(t, err)
+		}
+		// Revisions are tracked per tenant.
+		s, err = run("--tenant-query", "SELECT url FROM tenants")
+		require.NoError(t, err)
+		require.Equal(t, 2, strings.Count(s, "No migration files to execute"))
+
+		// Only the first column of multi-column queries is used.
+		s, err = run("--tenant-query", "SELECT url, 'ignored', 1 FROM tenants")
+		require.NoError(t, err)
+		require.Equal(t, 2, strings.Count(s, "No migration files to execute"))
+
+		s, err = run("--tenant-query", "SELECT url FROM tenants WHERE false")
+		require.NoError(t, err)
+		require.Equal(t, "No tenants were returned by the tenant query\n", s)
+
+		_, err = run("--tenant-query", "SELECT 'main'")
+		require.EqualError(t, err, `binding tenant "main" to the database URL: sql/sqlclient: driver does not support changing connected schema`)
+		_, err = run("--tenant-query", "SELECT unknown FROM tenants")
+		require.ErrorContains(t, err, "querying tenants: ")
+	})
 }
 
 func TestMigrate_ApplyTxMode(t *testing.T) {
@@ -709,6 +812,129 @@ func TestMigrate_ApplyTxModeDirective(t *testing.T) {
 	require.Equal(t, s, `unknown txmode "unknown" found in file directive "20220925094021_second.sql"`)
 }
 
+func TestMigrate_SessionDirective(t *testing.T) {
+	f := migrate.NewLocalFile("1.sql", []byte("-- atlas:session search_path=app,public; statement_timeout=0\n-- atlas:session lock_timeout = '1s'\n\nCREATE TABLE t(c int);\n"))
+	settings, err := sessionFor(f)
+	require.NoError(t, err)
+	require.Equal(t, [][2]string{{"search_path", "app,public"}, {"statement_timeout", "0"}, {"lock_timeout", "'1s'"}}, settings)
+
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:session statement_timeout\n\nCREATE TABLE t(c int);\n"))
+	_, err = sessionFor(f)
+	require.EqualError(t, err, `invalid session setting "statement_timeout" found in file directive "1.sql". Expect: name=value`)
+
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:session a;b=1\n\nCREATE TABLE t(c int);\n"))
+	_, err = sessionFor(f)
+	require.EqualError(t, err, `invalid session setting "a" found in file directive "1.sql". Expect: name=value`)
+
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:session a-b=1\n\nCREATE TABLE t(c int);\n"))
+	_, err = sessionFor(f)
+	require.EqualError(t, err, `invalid session parameter name "a-b" found in file directive "1.sql"`)
+
+	// Session directives are supported only by PostgreSQL.
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1.sql"), []byte("-- atlas:session statement_timeout=0\n\nCREATE TABLE t(c int);\n"), 0600))
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	_, err = runCmd(
+		migrateApplyCmd(),
+		"--dir", "file://"+p,
+		"--url", openSQLite(t, ""),
+	)
+	require.EqualError(t, err, `session directive found in file "1.sql" is supported only by PostgreSQL`)
+
+	// Settings are scoped to the transaction that executes the file, and the
+	// values they had before the file are restored after its execution.
+	db, err := sql.Open("sqlite3", "file:session?mode=memory")
+	require.NoError(t, err)
+	defer db.Close()
+	var (
+		drv = &execRecorder{db: db, values: map[string]string{"search_path": `"$user", public`, "statement_timeout": "5s"}}
+		mux = &tx{mode: txModeFile, c: &sqlclient.Client{Name: postgres.DriverName}}
+	)
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:session search_path=app,public; statement_timeout=0\n\nCREATE TABLE t(c int);\n"))
+	reset, err := mux.setSession(context.Background(), drv, f)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"SELECT current_setting($1, true)",
+		"SET LOCAL search_path = app,public",
+		"SELECT current_setting($1, true)",
+		"SET LOCAL statement_timeout = 0",
+	}, drv.stmts)
+	require.NoError(t, reset(context.Background()))
+	require.Equal(t, []string{"SELECT set_config($1, $2, true)", "SELECT set_config($1, $2, true)"}, drv.stmts[4:])
+	require.Equal(t, [][]any{{"search_path", `"$user", public`}, {"statement_timeout", "5s"}}, drv.args[4:])
+
+	// Files executed outside a transaction cannot set session parameters.
+	f = migrate.NewLocalFile("1.sql", []byte("-- atlas:txmode none\n-- atlas:session statement_timeout=0\n\nCREATE TABLE t(c int);\n"))
+	_, err = mux.setSession(context.Background(), drv, f)
+	require.EqualError(t, err, `session directive found in file "1.sql" cannot be used with txmode "none"`)
+}
+
+// execRecorder is a migrate.Driver that records the executed statements.
+// Queries are recorded as well, and answered with the configured values.
+type execRecorder struct {
+	migrate.Driver
+	stmts  []string
+	args   [][]any
+	db     *sql.DB           // database used for answering queries.
+	values map[string]string // query results, keyed by the first argument.
+}
+
+func (r *execRecorder) ExecContext(_ context.Context, query string, args ...any) (sql.Result, error) {
+	r.stmts = append(r.stmts, query)
+	r.args = append(r.args, args)
+	return nil, nil
+}
+
+func (r *execRecorder) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	r.stmts = append(r.stmts, query)
+	r.args = append(r.args, args)
+	return r.db.QueryContext(ctx, "SELECT ?", r.values[args[0].(string)])
+}
+
+func TestMigrate_VersionFile(t *testing.T) {
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1.sql"), []byte("CREATE TABLE t(c int);\n"), 0600))
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	require.NoError(t, migrate.WriteVersionFile(dir, &migrate.VersionFile{Atlas: "v0.14.0"}))
+	require.Equal(t, "20.4", dialectVersion("postgres", "200004"))
+	require.Equal(t, "9.6.5", dialectVersion("postgres", "90605"))
+	require.Equal(t, "9.4.26", dialectVersion("postgres", "90426"))
+	require.Equal(t, "8.0.30", dialectVersion("mysql", "8.0.30"))
+
+	prev := version
+	t.Cleanup(func() { version = prev })
+	// Development builds are not checked.
+	version = ""
+	s, err := runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", openSQLite(t, ""))
+	require.NoError(t, err)
+	require.NotContains(t, s, "Warning")
+
+	// Incompatible versions are reported as warnings by default.
+	version = "v0.13.0"
+	s, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", openSQLite(t, ""))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(s, "Warning: atlas version v0.13.0 is lower than v0.14.0 pinned in atlas.version\n"), s)
+
+	// Unless the "error" policy is set.
+	require.NoError(t, migrate.WriteVersionFile(dir, &migrate.VersionFile{Atlas: "v0.14.0", Policy: migrate.VersionPolicyError}))
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", openSQLite(t, ""))
+	require.EqualError(t, err, "atlas version v0.13.0 is lower than v0.14.0 pinned in atlas.version")
+	_, err = runCmd(migrateLintCmd(), "--dir", "file://"+p, "--dev-url", openSQLite(t, ""), "--latest", "1")
+	require.EqualError(t, err, "atlas version v0.13.0 is lower than v0.14.0 pinned in atlas.version")
+
+	version = "v0.14.2"
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", openSQLite(t, ""))
+	require.NoError(t, err)
+}
+
 func TestMigrate_ApplyExecOrder(t *testing.T) {
 	p := t.TempDir()
 	db := fmt.Sprintf("sqlite://file:%s?cache=shared&_fk=1", filepath.Join(p, "test.db"))
@@ -798,6 +1024,146 @@ func TestMigrate_ApplyExecOrder(t *testing.T) {
 	}
 }
 
+func TestMigrate_ApplyExecProxy(t *testing.T) {
+	var (
+		u     = openSQLite(t, "")
+		proxy = filepath.Join(t.TempDir(), "pending.sql")
+		apply = func() error {
+			_, err := runCmd(
+				migrateApplyCmd(),
+				"--dir", "file://testdata/sqlitetx",
+				"--url", u,
+				"--exec-proxy", "file://"+proxy,
+			)
+			return err
+		}
+	)
+	err := apply()
+	require.EqualError(t, err, "sql/migrate: statement \"CREATE TABLE `users` (`id` integer NOT NULL, `name` text NULL, PRIMARY KEY (`id`));\" of file \"20220925092817_initial.sql\" is pending external execution. Use 'migrate set' to resolve revision \"20220925092817\" once it was executed")
+	buf, err := os.ReadFile(proxy)
+	require.NoError(t, err)
+	require.Equal(t, "-- version: 20220925092817, file: 20220925092817_initial.sql\nCREATE TABLE `users` (`id` integer NOT NULL, `name` text NULL, PRIMARY KEY (`id`));\n", string(buf))
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'users'").Scan(&n))
+	require.Zero(t, n, "statements should not be executed")
+	var rerr string
+	require.NoError(t, db.QueryRow("SELECT `error` FROM `atlas_schema_revisions` WHERE `version` = '20220925092817'").Scan(&rerr))
+	require.Equal(t, migrate.ErrStmtPending.Error(), rerr)
+
+	// Pending statements are not sent twice.
+	require.Error(t, apply())
+	buf2, err := os.ReadFile(proxy)
+	require.NoError(t, err)
+	require.Equal(t, buf, buf2)
+
+	// Once the statement was executed, the revision is resolved and the next file is sent.
+	_, err = db.Exec(strings.TrimPrefix(string(buf), "-- version: 20220925092817, file: 20220925092817_initial.sql\n"))
+	require.NoError(t, err)
+	_, err = runCmd(migrateSetCmd(), "--dir", "file://testdata/sqlitetx", "--url", u, "20220925092817")
+	require.NoError(t, err)
+	err = apply()
+	require.ErrorContains(t, err, "statement \"CREATE TABLE `friendships`")
+}
+
+func TestMigrate_ApplySimulate(t *testing.T) {
+	u := openSQLite(t, "")
+	s, err := runCmd(
+		migrateApplyCmd(),
+		"--dir", "file://testdata/sqlitetx",
+		"--url", u,
+		"--simulate",
+		"2",
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, "Simulating migration to version 20220925094021 (2 migrations in total):")
+	require.Regexp(t, "INSERT INTO `users` \\(`id`\\) VALUES \\(1\\), \\(2\\);\n\\s+2 rows affected \\(.+\\)", s)
+	require.Contains(t, s, "changes rolled back (simulation)")
+
+	// Statements and revisions were rolled back.
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name IN ('users', 'friendships')").Scan(&n))
+	require.Zero(t, n)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM `atlas_schema_revisions`").Scan(&n))
+	require.Zero(t, n)
+
+	// Simulation cannot be combined with a dry-run.
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://testdata/sqlitetx", "--url", u, "--simulate", "--dry-run")
+	require.ErrorContains(t, err, "[dry-run simulate] were all set")
+	// Files that are executed outside a transaction cannot be simulated.
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://testdata/sqlitetx3", "--url", u, "--simulate")
+	require.ErrorContains(t, err, "cannot be simulated with txmode \"none\"")
+}
+
+func TestMigrate_ApplyTolerateErrors(t *testing.T) {
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_init.sql"), []byte("CREATE TABLE t1 (c int);\nCREATE TABLE t2 (c int);\n"), 0600))
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+
+	// Simulate a drifted database.
+	u := openSQLite(t, "")
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE t1 (c int)")
+	require.NoError(t, err)
+
+	s, err := runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", u, "--allow-dirty")
+	require.Error(t, err)
+	require.Contains(t, s, "table t1 already exists")
+
+	s, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", u, "--allow-dirty", "--tolerate-errors", "already exists")
+	require.NoError(t, err)
+	require.Contains(t, s, "warning: table t1 already exists")
+	require.Contains(t, s, "-- 1 warning")
+	var n int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 't2'").Scan(&n))
+	require.Equal(t, 1, n)
+	var applied int
+	require.NoError(t, db.QueryRow("SELECT `applied` FROM `atlas_schema_revisions` WHERE `version` = '1'").Scan(&applied))
+	require.Equal(t, 2, applied)
+}
+
+func TestMigrate_ApplyNDJSON(t *testing.T) {
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_init.sql"), []byte("CREATE TABLE t1 (c int);\nCREATE TABLE t2 (c int);\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "2_fail.sql"), []byte("CREATE TABLE t1 (c int);\n"), 0600))
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+
+	s, err := runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", openSQLite(t, ""), "--format", "ndjson")
+	require.Error(t, err)
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	require.Len(t, lines, 3)
+	var events []cmdlog.StmtEvent
+	for _, l := range lines {
+		var e cmdlog.StmtEvent
+		require.NoError(t, json.Unmarshal([]byte(l), &e))
+		events = append(events, e)
+	}
+	require.Equal(t, "1_init.sql", events[0].File)
+	require.Equal(t, 0, events[0].Index)
+	require.Equal(t, "CREATE TABLE t1 (c int);", events[0].SQL)
+	require.Empty(t, events[0].Error)
+	require.Equal(t, 1, events[1].Index)
+	require.Equal(t, "CREATE TABLE t2 (c int);", events[1].SQL)
+	require.Equal(t, "2", events[2].Version)
+	require.Equal(t, 0, events[2].Index)
+	require.Contains(t, events[2].Error, "table t1 already exists")
+}
+
 func TestMigrate_ApplyBaseline(t *testing.T) {
 	t.Run("FromFlags", func(t *testing.T) {
 		p := t.TempDir()
@@ -876,6 +1242,118 @@ env "local" {
 	})
 }
 
+func TestMigrate_DiffCheck(t *testing.T) {
+	var (
+		p    = t.TempDir()
+		to   = filepath.Join(t.TempDir(), "schema.sql")
+		args = []string{"--dir", "file://" + p, "--dev-url", openSQLite(t, ""), "--to", "file://" + to}
+	)
+	require.NoError(t, os.WriteFile(to, []byte("create table users (id int);"), 0600))
+	s, err := runCmd(migrateDiffCmd(), append([]string{"--check"}, args...)...)
+	require.EqualError(t, err, "migration directory is not synced with the desired state")
+	require.Contains(t, s, "The migration directory is not synced with the desired state.")
+	require.Contains(t, s, "CREATE TABLE `users` (`id` int NULL);")
+	// No files were written to the directory.
+	files, err := os.ReadDir(p)
+	require.NoError(t, err)
+	require.Empty(t, files)
+
+	_, err = runCmd(migrateDiffCmd(), append([]string{"init"}, args...)...)
+	require.NoError(t, err)
+	s, err = runCmd(migrateDiffCmd(), append([]string{"--check"}, args...)...)
+	require.NoError(t, err)
+	require.Equal(t, "The migration directory is synced with the desired state, no changes to be made\n", s)
+
+	_, err = runCmd(migrateDiffCmd(), append([]string{"--check", "--edit"}, args...)...)
+	require.ErrorContains(t, err, "if any flags in the group [check edit] are set none of the others can be")
+}
+
+func TestMigrate_DiffReplayCache(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		p     = t.TempDir()
+		cache = t.TempDir()
+		dev   = openSQLite(t, "")
+		to    = filepath.Join(t.TempDir(), "schema.hcl")
+		args  = []string{"--dir", "file://" + p, "--dev-url", dev, "--to", "file://" + to, "--replay-cache", cache}
+	)
+	err := os.WriteFile(to, []byte(`
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "name" {
+    type = text
+    null = true
+  }
+  primary_key {
+    columns = [column.id]
+  }
+  index "users_name" {
+    unique  = true
+    columns = [column.name]
+  }
+}
+`), 0600)
+	require.NoError(t, err)
+	_, err = runCmd(migrateDiffCmd(), append([]string{"init"}, args...)...)
+	require.NoError(t, err)
+	files, err := filepath.Glob(filepath.Join(cache, "*.snap"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	// The replayed state is cached, and the directory is in sync.
+	s, err := runCmd(migrateDiffCmd(), append([]string{"synced"}, args...)...)
+	require.NoError(t, err)
+	require.Equal(t, "The migration directory is synced with the desired state, no changes to be made\n", s)
+	// Loaded from cache.
+	s, err = runCmd(migrateDiffCmd(), append([]string{"synced"}, args...)...)
+	require.NoError(t, err)
+	require.Equal(t, "The migration directory is synced with the desired state, no changes to be made\n", s)
+
+	// Override the cached state of the current directory to ensure it is used instead of replaying it.
+	c, err := sqlclient.Open(ctx, dev)
+	require.NoError(t, err)
+	defer c.Close()
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	key, err := migrate.ReplayCacheKey(dir, "")
+	require.NoError(t, err)
+	rc := &migrate2.ReplayCache{Path: files[0], Dev: c.Name, Marshaler: c, Evaluator: c}
+	require.NoError(t, rc.Store(ctx, key, schema.NewRealm(schema.New("main"))))
+	_, err = runCmd(migrateDiffCmd(), append([]string{"cached"}, args...)...)
+	require.NoError(t, err)
+	sqls, err := filepath.Glob(filepath.Join(p, "*_cached.sql"))
+	require.NoError(t, err)
+	require.Len(t, sqls, 1, "expect the cached (empty) state to be diffed")
+}
+
+func TestMigrate_LintReplayCache(t *testing.T) {
+	var (
+		p     = t.TempDir()
+		cache = t.TempDir()
+		dev   = openSQLite(t, "")
+	)
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1.sql"), []byte("CREATE TABLE t(c int);"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "2.sql"), []byte("DROP TABLE t;"), 0600))
+	for range 2 {
+		s, err := runCmd(
+			migrateLintCmd(),
+			"--dir", "file://"+p,
+			"--dev-url", dev,
+			"--latest", "1",
+			"--replay-cache", cache,
+		)
+		require.Error(t, err)
+		require.Contains(t, s, `Dropping table "t"`)
+	}
+	// The state of the base file is cached, and loaded on the second run.
+	files, err := filepath.Glob(filepath.Join(cache, "*.snap"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
 func TestMigrate_Diff(t *testing.T) {
 	p := t.TempDir()
 	to := hclURL(t)
@@ -1264,6 +1742,129 @@ func TestMigrate_StatusJSON(t *testing.T) {
 	require.Equal(t, `"sqlite3"`, s)
 }
 
+func TestMigrate_StatusPrometheus(t *testing.T) {
+	u := fmt.Sprintf("sqlite://file:%s?_fk=1", filepath.Join(t.TempDir(), "test.db"))
+	s, err := runCmd(
+		migrateStatusCmd(),
+		"--dir", "file://testdata/sqlite",
+		"-u", u,
+		"--format", "prometheus",
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, "# TYPE atlas_pending_migrations gauge\n")
+	require.Regexp(t, `\natlas_pending_migrations\{driver="sqlite3",url="sqlite://file:.+",dir="file://testdata/sqlite\?format=atlas"\} 2\n`, s)
+	require.NotContains(t, s, "atlas_last_applied_timestamp")
+
+	_, err = runCmd(
+		migrateApplyCmd(),
+		"--dir", "file://testdata/sqlite",
+		"--url", u,
+	)
+	require.NoError(t, err)
+	s, err = runCmd(
+		migrateStatusCmd(),
+		"--dir", "file://testdata/sqlite",
+		"-u", u,
+		"--format", "prometheus",
+	)
+	require.NoError(t, err)
+	require.Regexp(t, `\natlas_pending_migrations\{.+\} 0\n`, s)
+	require.Regexp(t, `\natlas_applied_migrations\{.+\} 2\n`, s)
+	require.Regexp(t, `\natlas_last_applied_timestamp\{.+\} \d+\n`, s)
+	require.Regexp(t, `\natlas_dirty\{.+\} 0\n`, s)
+}
+
+func TestMigrate_Down(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		u   = openSQLite(t, "")
+		dir = "file://" + p + "?format=golang-migrate"
+	)
+	for name, data := range map[string]string{
+		"1_users.up.sql":   "CREATE TABLE `users` (`id` integer NOT NULL, PRIMARY KEY (`id`));\n",
+		"1_users.down.sql": "DROP TABLE `users`;\n",
+		"2_posts.up.sql":   "CREATE TABLE `posts` (`id` integer NOT NULL, PRIMARY KEY (`id`));\n",
+		"2_posts.down.sql": "DROP TABLE `posts`;\n",
+		"3_t.up.sql":       "CREATE TABLE `t` (`id` integer NOT NULL);\n",
+		"3_t.down.sql":     "DROP TABLE `t`;\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(p, name), []byte(data), 0644))
+	}
+	_, err := runCmd(migrateHashCmd(), "--dir", dir)
+	require.NoError(t, err)
+	_, err = runCmd(migrateApplyCmd(), "--dir", dir, "--url", u)
+	require.NoError(t, err)
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	tables := func() (n int) {
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name IN ('users', 'posts', 't')").Scan(&n))
+		return n
+	}
+	revisions := func() (n int) {
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM `atlas_schema_revisions`").Scan(&n))
+		return n
+	}
+	require.Equal(t, 3, tables())
+
+	// Dry-run does not execute the statements.
+	s, err := runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, s, "Reverting to version 2 from 3 (1 migrations in total):")
+	require.Contains(t, s, "reverting version 3 (3_t.down.sql)")
+	require.Contains(t, s, "DROP TABLE `t`")
+	require.Contains(t, s, "1 migration to revert (dry run)")
+	require.Equal(t, 3, tables())
+	require.Equal(t, 3, revisions())
+
+	// Revert the last file using its down file.
+	s, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u)
+	require.NoError(t, err)
+	require.Contains(t, s, "1 migration reverted")
+	require.Equal(t, 2, tables())
+	require.Equal(t, 2, revisions())
+
+	// Revert to an unknown version.
+	_, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--to-version", "3")
+	require.EqualError(t, err, `revision "3" was not found in the revisions table`)
+	_, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--to-version", "1", "1")
+	require.EqualError(t, err, "the amount argument cannot be used with --to-version")
+
+	// Without down files, the reverse statements are computed on the dev database.
+	require.NoError(t, os.Remove(filepath.Join(p, "2_posts.down.sql")))
+	_, err = runCmd(migrateHashCmd(), "--dir", dir)
+	require.NoError(t, err)
+	_, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--to-version", "1")
+	require.EqualError(t, err, "no down files were found for the reverted migrations. Use --dev-url to compute the reverse statements")
+	s, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--to-version", "1", "--dev-url", "sqlite://dev?mode=memory")
+	require.NoError(t, err)
+	require.Contains(t, s, "reverting to version 1 (computed on the dev database)")
+	require.Contains(t, s, "DROP TABLE `posts`")
+	require.Equal(t, 1, tables())
+	require.Equal(t, 1, revisions())
+
+	// Reverts are serialized with other applies using the same lock.
+	c, err := sqlclient.Open(context.Background(), u)
+	require.NoError(t, err)
+	unlock, err := c.Lock(context.Background(), "custom", time.Minute)
+	require.NoError(t, err)
+	_, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--lock-name", "custom", "10")
+	require.ErrorContains(t, err, "acquiring database lock")
+	require.Equal(t, 1, revisions())
+	require.NoError(t, unlock())
+	require.NoError(t, c.Close())
+
+	// Revert all files.
+	s, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u, "--lock-name", "custom", "10")
+	require.NoError(t, err)
+	require.Contains(t, s, "Reverting to the initial state from 1 (1 migrations in total):")
+	require.Zero(t, tables())
+	require.Zero(t, revisions())
+	s, err = runCmd(migrateDownCmd(), "--dir", dir, "--url", u)
+	require.NoError(t, err)
+	require.Equal(t, "No migration files to revert\n", s)
+}
+
 func TestMigrate_Set(t *testing.T) {
 	u := fmt.Sprintf("sqlite://file:%s?_fk=1", filepath.Join(t.TempDir(), "test.db"))
 	_, err := runCmd(
@@ -1395,7 +1996,8 @@ func TestMigrate_New(t *testing.T) {
 	require.Zero(t, s)
 	require.NoError(t, err)
 	require.FileExists(t, filepath.Join(p, v+"_liquibase.sql"))
-	require.Equal(t, 2, countFiles(t, p))
+	require.FileExists(t, filepath.Join(p, sqltool.LiquibaseChangelogFileName))
+	require.Equal(t, 3, countFiles(t, p))
 
 	p = t.TempDir()
 	s, err = runCmd(migrateNewCmd(), "dbmate", "--dir", "file://"+p+"?format="+migrate2.FormatDBMate)
@@ -1424,6 +2026,102 @@ func TestMigrate_New(t *testing.T) {
 		require.Equal(t, "contents\n", string(b))
 		require.Equal(t, "atlas.sum", files[1].Name())
 	})
+
+	t.Run("FromTemplate", func(t *testing.T) {
+		p := t.TempDir()
+		tdir := filepath.Join("testdata", "templates")
+		_, err := runCmd(migrateNewCmd(), "--dir", "file://"+p, "--from-template", "soft_delete", "--template-dir", tdir, "--template-var", "table=users")
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(p, v+"_soft_delete.sql"))
+		require.NoError(t, err)
+		require.Equal(t, "-- Add soft-delete support to the \"users\" table.\nALTER TABLE `users` ADD COLUMN `deleted_at` timestamp NULL;\nCREATE INDEX `users_deleted_at` ON `users` (`deleted_at`);\n", string(b))
+
+		_, err = runCmd(migrateNewCmd(), "pets_audit", "--dir", "file://"+p, "--from-template", "add_audit_columns", "--template-dir", tdir, "--template-var", "table=pets")
+		require.NoError(t, err)
+		b, err = os.ReadFile(filepath.Join(p, v+"_pets_audit.sql"))
+		require.NoError(t, err)
+		require.Contains(t, string(b), "ALTER TABLE `pets` ADD COLUMN `created_at`")
+		require.Equal(t, 3, countFiles(t, p))
+
+		_, err = runCmd(migrateNewCmd(), "--dir", "file://"+p, "--from-template", "trigger_pair", "--template-dir", tdir, "--template-var", "table=users")
+		require.ErrorContains(t, err, `executing template "trigger_pair"`)
+		_, err = runCmd(migrateNewCmd(), "--dir", "file://"+p, "--from-template", "soft_delete", "--template-dir", tdir, "--template-var", "users")
+		require.EqualError(t, err, `invalid template variable "users", expected format: name=value`)
+		_, err = runCmd(migrateNewCmd(), "--dir", "file://"+p, "--from-template", "unknown", "--template-dir", tdir)
+		require.EqualError(t, err, `template "unknown" was not found in "testdata/templates"`)
+		_, err = runCmd(migrateNewCmd(), "--dir", "file://"+p, "--from-template", "../soft_delete", "--template-dir", tdir)
+		require.EqualError(t, err, `invalid template name "../soft_delete"`)
+		require.Equal(t, 3, countFiles(t, p))
+	})
+}
+
+func TestMigrate_Retype(t *testing.T) {
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_init.sql"), []byte("CREATE TABLE `users` (`id` integer NOT NULL, `age` text NOT NULL DEFAULT '0', PRIMARY KEY (`id`));\n"), 0600))
+	dir, err := migrate.NewLocalDir(p)
+	require.NoError(t, err)
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+
+	// Indexed columns cannot be swapped.
+	_, err = runCmd(
+		migrateRetypeCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--table", "users", "--column", "id", "--type", "bigint",
+	)
+	require.EqualError(t, err, `column "id" is part of the primary key of table "users" and cannot be swapped`)
+	_, err = runCmd(
+		migrateRetypeCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--table", "users", "--column", "age", "--type", "int",
+		"--batch-size", "2",
+	)
+	require.EqualError(t, err, "--rows is required when --batch-size is set")
+
+	s, err := runCmd(
+		migrateRetypeCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--table", "users", "--column", "age", "--type", "int",
+		"--using", "CAST({{ .Column }} AS int)",
+		"--batch-size", "2", "--rows", "3",
+	)
+	require.NoError(t, err)
+	require.Empty(t, s)
+	files, err := dir.Files()
+	require.NoError(t, err)
+	require.Len(t, files, 5)
+	for i, n := range []string{"add", "backfill", "swap", "drop"} {
+		require.True(t, strings.HasSuffix(files[i+1].Name(), "_retype_users_age_"+n+".sql"), files[i+1].Name())
+	}
+	require.Equal(t, `-- Backfill "age_new" column (batch 1/2)
+UPDATE `+"`users` SET `age_new` = CAST(`age` AS int) WHERE `age_new` IS NULL AND `age` IS NOT NULL AND `id` IN (SELECT `id` FROM (SELECT `id` FROM `users` WHERE `age_new` IS NULL AND `age` IS NOT NULL LIMIT 2) AS batch);"+`
+-- Backfill "age_new" column (batch 2/2)
+UPDATE `+"`users` SET `age_new` = CAST(`age` AS int) WHERE `age_new` IS NULL AND `age` IS NOT NULL AND `id` IN (SELECT `id` FROM (SELECT `id` FROM `users` WHERE `age_new` IS NULL AND `age` IS NOT NULL LIMIT 2) AS batch);"+`
+`, string(files[2].Bytes()))
+
+	// Apply the sequence on a database with data.
+	u := openSQLite(t, "")
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", u, "1")
+	require.NoError(t, err)
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("INSERT INTO `users` (`id`, `age`) VALUES (1, '10'), (2, '20'), (3, '30')")
+	require.NoError(t, err)
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", u, "--allow-dirty")
+	require.NoError(t, err)
+	var (
+		typ   string
+		total int
+	)
+	require.NoError(t, db.QueryRow("SELECT `type` FROM pragma_table_info('users') WHERE `name` = 'age'").Scan(&typ))
+	require.Equal(t, "INT", typ)
+	require.NoError(t, db.QueryRow("SELECT SUM(`age`) FROM `users` WHERE typeof(`age`) = 'integer'").Scan(&total))
+	require.Equal(t, 60, total)
 }
 
 func TestMigrate_Validate(t *testing.T) {
@@ -1477,6 +2175,74 @@ func TestMigrate_Validate(t *testing.T) {
 	require.Contains(t, s, "L3: 2_second.sql was added")
 }
 
+func TestMigrate_ValidateAgainst(t *testing.T) {
+	ctx := context.Background()
+	p := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1_initial.sql"), []byte("create table t1 (c1 int);"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "2_second.sql"), []byte("create table t2 (c2 int);"), 0644))
+	_, err := runCmd(migrateHashCmd(), "--dir", "file://"+p)
+	require.NoError(t, err)
+	target := openSQLite(t, "")
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "--url", target, "1")
+	require.NoError(t, err)
+
+	// A dev database is required.
+	_, err = runCmd(migrateValidateCmd(), "--dir", "file://"+p, "--against", target)
+	require.EqualError(t, err, "--dev-url is required when --against is set")
+
+	s, err := runCmd(migrateValidateCmd(), "--dir", "file://"+p, "--dev-url", openSQLite(t, ""), "--against", target)
+	require.NoError(t, err)
+	require.Equal(t, `PASS Directory Integrity: File atlas.sum is valid
+PASS Lint New Files: Analyzed 1 new migration files (0 diagnostics)
+PASS Schema Equivalence: Target database schema matches the migration directory at version 1
+Validation passed
+`, s)
+
+	// Drift on the target database.
+	c, err := sqlclient.Open(ctx, target)
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.ExecContext(ctx, "create table drift (c int)")
+	require.NoError(t, err)
+	s, err = runCmd(
+		migrateValidateCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--against", target,
+		"--format", "{{ json . }}",
+	)
+	require.Error(t, err)
+	var rep cmdlog.MigrateValidate
+	require.NoError(t, json.Unmarshal([]byte(s), &rep))
+	require.False(t, rep.Passed)
+	require.Len(t, rep.Checks, 3)
+	require.True(t, rep.Checks[0].Passed)
+	require.True(t, rep.Checks[1].Passed)
+	require.False(t, rep.Checks[2].Passed)
+	require.Equal(t, cmdlog.CheckSchemaMatch, rep.Checks[2].Name)
+	require.Equal(t, "Target database schema differs from the migration directory at version 1 (1 changes)", rep.Checks[2].Text)
+	require.Contains(t, rep.Checks[2].Details, "DROP TABLE `drift`")
+
+	// Excluded resources are ignored.
+	_, err = runCmd(
+		migrateValidateCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--against", target,
+		"--exclude", "drift",
+	)
+	require.NoError(t, err)
+
+	// Integrity errors are reported as part of the verdict.
+	require.NoError(t, os.WriteFile(filepath.Join(p, "3_third.sql"), []byte("create table t3 (c3 int);"), 0644))
+	s, err = runCmd(migrateValidateCmd(), "--dir", "file://"+p, "--dev-url", openSQLite(t, ""), "--against", target)
+	require.Error(t, err)
+	require.Equal(t, `FAIL Directory Integrity: File atlas.sum is invalid
+  ERROR: checksum mismatch
+Validation failed
+`, s)
+}
+
 func TestMigrate_Hash(t *testing.T) {
 	s, err := runCmd(migrateHashCmd(), "--dir", "file://testdata/mysql")
 	require.Zero(t, s)
@@ -1515,6 +2281,130 @@ func TestMigrate_Hash(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestMigrate_Sign(t *testing.T) {
+	var (
+		p       = t.TempDir()
+		keys    = t.TempDir()
+		pub, pk = writeSignKeys(t, keys, "key")
+	)
+	require.NoError(t, os.WriteFile(filepath.Join(p, "1.sql"), []byte("CREATE TABLE t(c int);"), 0600))
+	s, err := runCmd(migrateHashCmd(), "--dir", "file://"+p, "--sign-key", pk)
+	require.NoError(t, err)
+	require.Empty(t, s)
+	require.FileExists(t, filepath.Join(p, migrate2.SignatureFileName))
+
+	s, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "-u", openSQLite(t, ""), "--verify-key", pub)
+	require.NoError(t, err)
+	require.Contains(t, s, "Migrating to version 1")
+
+	// Signature does not match a different key.
+	other, _ := writeSignKeys(t, keys, "other")
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "-u", openSQLite(t, ""), "--verify-key", other)
+	require.ErrorIs(t, err, migrate2.ErrInvalidSignature)
+
+	// Re-hashing the directory without signing it invalidates the signature.
+	require.NoError(t, os.WriteFile(filepath.Join(p, "2.sql"), []byte("CREATE TABLE t2(c int);"), 0600))
+	_, err = runCmd(migrateHashCmd(), "--dir", "file://"+p)
+	require.NoError(t, err)
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "-u", openSQLite(t, ""), "--verify-key", pub)
+	require.ErrorIs(t, err, migrate2.ErrInvalidSignature)
+
+	// Unsigned directories are rejected.
+	require.NoError(t, os.Remove(filepath.Join(p, migrate2.SignatureFileName)))
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://"+p, "-u", openSQLite(t, ""), "--verify-key", pub)
+	require.EqualError(t, err, "migration directory is not signed: atlas.sum.sig was not found")
+
+	// Invalid keys fail before the directory is changed.
+	_, err = runCmd(migrateHashCmd(), "--dir", "file://"+p, "--sign-key", filepath.Join(keys, "unknown.key"))
+	require.Error(t, err)
+	require.NoFileExists(t, filepath.Join(p, migrate2.SignatureFileName))
+}
+
+func writeSignKeys(t *testing.T, dir, name string) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPath := filepath.Join(dir, name+".pub")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}), 0600))
+	b, err = x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	privPath := filepath.Join(dir, name+".key")
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: b}), 0600))
+	return pubPath, privPath
+}
+
+func TestMigrate_RevisionsTable(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		db  = filepath.Join(p, "test.db")
+		u   = fmt.Sprintf("sqlite://file:%s?cache=shared&_fk=1", db)
+		cfg = filepath.Join(p, "atlas.hcl")
+	)
+	require.NoError(t, os.WriteFile(cfg, []byte(fmt.Sprintf(`
+variable "deploy_id" {
+  type = string
+}
+
+env "local" {
+  url = %q
+  migration {
+    dir             = "file://testdata/sqlite"
+    revisions_table = "deployments"
+    revisions_columns = {
+      deploy_id = var.deploy_id
+      labels    = "a,b"
+    }
+  }
+}
+`, u)), 0600))
+	cmd := migrateCmd()
+	cmd.AddCommand(migrateApplyCmd())
+	s, err := runCmd(cmd, "apply", "-c", "file://"+cfg, "--env", "local", "--var", "deploy_id=42", "1")
+	require.NoError(t, err)
+	require.Contains(t, s, "Migrating to version 20220318104614")
+
+	sdb, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?cache=shared&_fk=1", db))
+	require.NoError(t, err)
+	defer sdb.Close()
+	var id, labels string
+	require.NoError(t, sdb.QueryRow("SELECT deploy_id, labels FROM deployments WHERE version = '20220318104614'").Scan(&id, &labels))
+	require.Equal(t, "42", id)
+	require.Equal(t, "a,b", labels)
+	var n int
+	require.NoError(t, sdb.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'atlas_schema_revisions'").Scan(&n))
+	require.Zero(t, n)
+
+	// Flags take precedence over the project file.
+	s, err = runCmd(migrateApplyCmd(), "--dir", "file://testdata/sqlite", "-u", u, "--revisions-table", "deployments", "--revisions-column", "deploy_id=43")
+	require.NoError(t, err)
+	require.Contains(t, s, "Migrating to version 20220318104615 from 20220318104614")
+	require.NoError(t, sdb.QueryRow("SELECT deploy_id FROM deployments WHERE version = '20220318104615'").Scan(&id))
+	require.Equal(t, "43", id)
+
+	s, err = runCmd(migrateStatusCmd(), "--dir", "file://testdata/sqlite", "-u", u, "--revisions-table", "deployments")
+	require.NoError(t, err)
+	require.Contains(t, s, "Already at latest version")
+
+	// Commands that do not configure the additional columns keep them.
+	_, err = runCmd(migrateSetCmd(), "--dir", "file://testdata/sqlite", "-u", u, "--revisions-table", "deployments", "20220318104614")
+	require.NoError(t, err)
+	require.NoError(t, sdb.QueryRow("SELECT deploy_id, labels FROM deployments WHERE version = '20220318104614'").Scan(&id, &labels))
+	require.Equal(t, "42", id)
+	require.Equal(t, "a,b", labels)
+	_, err = runCmd(migrateSetCmd(), "--dir", "file://testdata/sqlite", "-u", u, "--revisions-table", "deployments", "20220318104615")
+	require.NoError(t, err)
+	var deployID, setLabels sql.NullString
+	require.NoError(t, sdb.QueryRow("SELECT deploy_id, labels FROM deployments WHERE version = '20220318104615'").Scan(&deployID, &setLabels))
+	require.False(t, deployID.Valid)
+	require.False(t, setLabels.Valid)
+	require.NoError(t, sdb.QueryRow("SELECT labels FROM deployments WHERE version = '20220318104614'").Scan(&labels))
+	require.Equal(t, "a,b", labels)
+
+	_, err = runCmd(migrateApplyCmd(), "--dir", "file://testdata/sqlite", "-u", u, "--revisions-column", "deploy_id")
+	require.EqualError(t, err, `invalid revisions column "deploy_id", expected format: name=value`)
+}
+
 func TestMigrate_Lint(t *testing.T) {
 	p := t.TempDir()
 	s, err := runCmd(
@@ -1562,6 +2452,69 @@ func TestMigrate_Lint(t *testing.T) {
 	require.Error(t, err)
 	require.Equal(t, "2.sql", s)
 
+	// Changes of excluded resources are not analyzed.
+	for _, exclude := range []string{"main.t", "*.t[type=table]"} {
+		s, err = runCmd(
+			migrateLintCmd(),
+			"--dir", "file://"+p,
+			"--dev-url", openSQLite(t, ""),
+			"--latest", "1",
+			"--exclude", exclude,
+		)
+		require.NoError(t, err)
+		require.NotContains(t, s, "DS102")
+	}
+	s, err = runCmd(
+		migrateLintCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--latest", "1",
+		"--exclude", "main.*,!main.t",
+	)
+	require.Error(t, err)
+	require.Contains(t, s, "DS102")
+
+	// Lint a version range.
+	err = os.WriteFile(filepath.Join(p, "3.sql"), []byte("CREATE TABLE t2(c int);"), 0600)
+	require.NoError(t, err)
+	s, err = runCmd(
+		migrateLintCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--from-version", "2",
+		"--to-version", "2",
+		"--format", "{{ range .Files }}{{ .Name }} {{ end }}",
+	)
+	require.Error(t, err)
+	require.Equal(t, "2.sql ", s)
+	s, err = runCmd(
+		migrateLintCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--from-version", "3",
+		"--format", "{{ range .Files }}{{ .Name }} {{ end }}",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "3.sql ", s)
+	s, err = runCmd(
+		migrateLintCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--from-version", "3",
+		"--format", "github",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "::notice title=Atlas lint summary::1 version ok, 1 schema change\n", s)
+	_, err = runCmd(
+		migrateLintCmd(),
+		"--dir", "file://"+p,
+		"--dev-url", openSQLite(t, ""),
+		"--from-version", "2",
+		"--latest", "1",
+	)
+	require.EqualError(t, err, "--from-version and --to-version cannot be used with --latest or --git-base")
+	require.NoError(t, os.Remove(filepath.Join(p, "3.sql")))
+
 	t.Run("FromConfig", func(t *testing.T) {
 		cfg := filepath.Join(p, "atlas.hcl")
 		err := os.WriteFile(cfg, []byte(`
@@ -1630,6 +2583,53 @@ lint {
 `, s)
 	})
 
+	t.Run("CustomAnalyzers", func(t *testing.T) {
+		var (
+			cfg    = filepath.Join(t.TempDir(), "atlas.hcl")
+			script = filepath.Join(t.TempDir(), "lint.sh")
+		)
+		require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+echo '{"Reports":[{"Text":"custom check","Diagnostics":[{"Pos":0,"Text":"Statement rejected by custom linter","Code":"EX101"}]}]}'
+`), 0700))
+		require.NoError(t, os.WriteFile(cfg, []byte(fmt.Sprintf(`
+lint {
+  latest = 1
+  external "custom" {
+    command = [%q]
+    error   = true
+  }
+}
+`, script)), 0600))
+		cmd := migrateCmd()
+		cmd.AddCommand(migrateLintCmd())
+		s, err := runCmd(
+			cmd, "lint",
+			"--dir", "file://"+p,
+			"--dev-url", openSQLite(t, ""),
+			"-c", "file://"+cfg,
+		)
+		require.Error(t, err)
+		require.Contains(t, s, `    -- custom check:
+      -- L1: Statement rejected by custom linter`)
+
+		cfg = filepath.Join(t.TempDir(), "atlas.hcl")
+		require.NoError(t, os.WriteFile(cfg, []byte(`
+lint {
+  latest = 1
+  analyzer "unknown" {}
+}
+`), 0600))
+		cmd = migrateCmd()
+		cmd.AddCommand(migrateLintCmd())
+		_, err = runCmd(
+			cmd, "lint",
+			"--dir", "file://"+p,
+			"--dev-url", openSQLite(t, ""),
+			"-c", "file://"+cfg,
+		)
+		require.EqualError(t, err, `sql/sqlcheck: analyzer "unknown" was not registered`)
+	})
+
 	// Change files to golang-migrate format.
 	require.NoError(t, os.Rename(filepath.Join(p, "1.sql"), filepath.Join(p, "1.up.sql")))
 	require.NoError(t, os.Rename(filepath.Join(p, "2.sql"), filepath.Join(p, "1.down.sql")))
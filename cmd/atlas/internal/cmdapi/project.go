@@ -11,10 +11,14 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 
 	"ariga.io/atlas/cmd/atlas/internal/cloudapi"
+	"ariga.io/atlas/cmd/atlas/internal/cmdapi/vercheck"
 	"ariga.io/atlas/cmd/atlas/internal/cmdext"
 	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
 	"ariga.io/atlas/schemahcl"
@@ -26,6 +30,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	"golang.org/x/mod/semver"
 )
 
 type (
@@ -48,6 +53,29 @@ type (
 		// resources on inspection.
 		Exclude []string `spec:"exclude"`
 
+		// LockName is the name of the database lock that is acquired by
+		// 'schema apply' and 'migrate apply'. Pipelines that share the same
+		// name are serialized when targeting the same database.
+		LockName string `spec:"lock_name"`
+
+		// Analyze indicates if the statistics of the changed tables should be
+		// refreshed (e.g., using ANALYZE) after 'schema apply' is executed.
+		Analyze bool `spec:"analyze"`
+
+		// CheckPrivileges indicates if 'schema apply' should verify that the connected
+		// user has the privileges required by the planned changes before applying them.
+		CheckPrivileges bool `spec:"check_privileges"`
+
+		// Protect defines a list of glob patterns for schemas and tables
+		// (e.g., "public.audit_*") that must not be dropped or modified
+		// by the planner. Migration files that change them fail linting.
+		Protect []string `spec:"protect"`
+
+		// ApplyWindows defines the maintenance windows in which 'schema apply'
+		// is allowed to apply destructive changes. If empty, changes can be
+		// applied at any time.
+		ApplyWindows []*ApplyWindow `spec:"apply_window"`
+
 		// Schema containing the schema configuration of the env.
 		Schema *Schema `spec:"schema"`
 
@@ -60,12 +88,18 @@ type (
 		// Lint policy of the environment.
 		Lint *Lint `spec:"lint"`
 
+		// Drift configures the drift checks of the environment.
+		Drift *Drift `spec:"drift"`
+
 		// Format of the environment.
 		Format Format `spec:"format"`
 
 		// Test configuration of the environment.
 		Test *Test `spec:"test"`
 
+		// Flags defines the default values of the command flags.
+		Flags *Flags `spec:"flags"`
+
 		schemahcl.DefaultExtension
 		cloud  *cmdext.AtlasConfig
 		config *Project
@@ -78,9 +112,18 @@ type (
 		Format          string   `spec:"format"`
 		Baseline        string   `spec:"baseline"`
 		ExecOrder       string   `spec:"exec_order"`
+		ExecProxy       string   `spec:"exec_proxy"`
 		LockTimeout     string   `spec:"lock_timeout"`
 		RevisionsSchema string   `spec:"revisions_schema"`
+		RevisionsTable  string   `spec:"revisions_table"`
+		ReplayCache     string   `spec:"replay_cache"`
+		VerifyKey       string   `spec:"verify_key"`
+		TolerateErrors  []string `spec:"tolerate_errors"`
 		Repo            *Repo    `spec:"repo"`
+		// RevisionsColumns defines additional columns of the revisions table,
+		// and the values to set on the revisions written by 'migrate apply'.
+		// For example, the deployment identifier or the git commit SHA.
+		RevisionsColumns map[string]cty.Value `spec:"revisions_columns"`
 	}
 
 	// Schema represents a schema in the registry.
@@ -118,9 +161,24 @@ type (
 	Diff struct {
 		// SkipChanges configures the skip changes policy.
 		SkipChanges *SkipChanges `spec:"skip"`
+		// DropSchema configures the policy for schemas that are missing from the
+		// desired state. One of: "cascade" (default) drops them with all their
+		// objects, "skip" keeps them, and "error" fails the planning.
+		DropSchema string `spec:"drop_schema"`
+		// TypeOverrides configures how columns of custom or unknown types are compared.
+		TypeOverrides []*TypeOverride `spec:"type_override"`
 		schemahcl.DefaultExtension
 	}
 
+	// Drift represents the configuration of the drift checks run by
+	// 'schema drift' and 'agent' against the database of an environment.
+	Drift struct {
+		// Exclude defines a list of glob patterns of resources that are
+		// ignored by drift checks, in addition to the "exclude" attribute
+		// of the environment. For example, tables managed by other tools.
+		Exclude []string `spec:"exclude"`
+	}
+
 	// Test represents the test configuration of a project or environment.
 	Test struct {
 		// Schema represents the 'schema test' configuration.
@@ -135,6 +193,23 @@ type (
 		} `spec:"migrate"`
 	}
 
+	// Flags represents the default values of command flags, defined globally
+	// or per environment. Attributes apply to all commands that have a flag
+	// with the same name (underscores are replaced by dashes), and nested
+	// blocks scope the defaults to a specific command. For example:
+	//
+	//	flags {
+	//	  dry_run = true
+	//	  schema {
+	//	    apply {
+	//	      auto_approve = true
+	//	    }
+	//	  }
+	//	}
+	Flags struct {
+		schemahcl.DefaultExtension
+	}
+
 	// SkipChanges represents the skip changes policy.
 	SkipChanges struct {
 		AddSchema        bool `spec:"add_schema"`
@@ -196,6 +271,8 @@ type (
 			Diff string `spec:"diff"`
 			// Push configures the formatting for 'schema push'.
 			Push string `spec:"push"`
+			// Lint configures the formatting for 'schema lint'.
+			Lint string `spec:"lint"`
 		} `spec:"schema"`
 		schemahcl.DefaultExtension
 	}
@@ -411,6 +488,15 @@ func (d *Diff) Extend(global *Diff) *Diff {
 	if d.SkipChanges == nil {
 		d.SkipChanges = global.SkipChanges
 	}
+	if d.DropSchema == "" {
+		d.DropSchema = global.DropSchema
+	}
+	// Global overrides apply to types that are not overridden by the environment.
+	for _, o := range global.TypeOverrides {
+		if !slices.ContainsFunc(d.TypeOverrides, func(t *TypeOverride) bool { return strings.EqualFold(t.Name, o.Name) }) {
+			d.TypeOverrides = append(d.TypeOverrides, o)
+		}
+	}
 	return d
 }
 
@@ -420,6 +506,9 @@ func (d *Diff) Options() (opts []schema.DiffOption) {
 	opts = append(opts, func(opts *schema.DiffOptions) {
 		opts.Extra = d.DefaultExtension
 	})
+	if d.DropSchema == DropSchemaSkip {
+		opts = append(opts, schema.DiffSkipChanges(&schema.DropSchema{}))
+	}
 	if d.SkipChanges == nil {
 		return
 	}
@@ -448,6 +537,34 @@ func (d *Diff) Options() (opts []schema.DiffOption) {
 	return opts
 }
 
+// The policies for dropping schemas that are missing from the desired state.
+const (
+	DropSchemaCascade = "cascade"
+	DropSchemaSkip    = "skip"
+	DropSchemaError   = "error"
+)
+
+// CheckDropSchema returns an error in case the changes drop a schema,
+// and the "drop_schema" policy of the environment does not allow it.
+func (e *Env) CheckDropSchema(changes []schema.Change) error {
+	if e == nil || e.Diff == nil {
+		return nil
+	}
+	switch p := e.Diff.DropSchema; p {
+	case "", DropSchemaCascade, DropSchemaSkip:
+		return nil
+	case DropSchemaError:
+		for _, c := range changes {
+			if d, ok := c.(*schema.DropSchema); ok {
+				return fmt.Errorf("dropping schema %q is not allowed by the diff policy (drop_schema = %q)", d.S.Name, p)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown drop_schema policy %q. Expect one of: %s, %s or %s", p, DropSchemaCascade, DropSchemaSkip, DropSchemaError)
+	}
+}
+
 // DiffOptions returns the diff options configured for the environment,
 // or nil if no environment or diff policy were set.
 func (e *Env) DiffOptions() []schema.DiffOption {
@@ -485,6 +602,46 @@ func (e *Env) Sources() ([]string, error) {
 	}
 }
 
+// setRevisionColumns sets the revisions-column flag from the revisions_columns
+// attribute, in case it was not set explicitly by the user.
+func (m *Migration) setRevisionColumns(cmd *cobra.Command) error {
+	f := cmd.Flag(flagRevisionColumn)
+	if f == nil || f.Changed || len(m.RevisionsColumns) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(m.RevisionsColumns))
+	for name := range m.RevisionsColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, err := flagValue(&schemahcl.Attr{K: name, V: m.RevisionsColumns[name]})
+		if err != nil {
+			return fmt.Errorf("migration.revisions_columns: %w", err)
+		}
+		// Values are quoted, as slice flags are parsed as CSV.
+		if err := cmd.Flags().Set(flagRevisionColumn, `"`+strings.ReplaceAll(name+"="+v, `"`, `""`)+`"`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setTolerateErrors sets the tolerate-errors flag from the
+// migration block, if it was not set explicitly by the user.
+func (m *Migration) setTolerateErrors(cmd *cobra.Command) error {
+	if f := cmd.Flag(flagTolerate); f == nil || f.Changed {
+		return nil
+	}
+	for _, p := range m.TolerateErrors {
+		// Values are quoted, as slice flags are parsed as CSV.
+		if err := cmd.Flags().Set(flagTolerate, `"`+strings.ReplaceAll(p, `"`, `""`)+`"`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Vars returns the extra attributes stored in the Env as a map[string]cty.Value.
 func (e *Env) Vars() map[string]cty.Value {
 	m := make(map[string]cty.Value, len(e.Extra.Attrs))
@@ -503,6 +660,106 @@ func (e *Env) Vars() map[string]cty.Value {
 	return m
 }
 
+// values collects the flag values defined for the given command into vs. Command-specific
+// values take precedence. Attributes that do not match a flag of any command in their scope
+// (e.g., a typo in the flag name) are reported as errors instead of being ignored.
+func (f *Flags) values(cmd *cobra.Command, vs map[string]string) error {
+	if f == nil {
+		return nil
+	}
+	var (
+		r     = &f.Extra
+		scope = cmd.Root()
+		// Skip the root command (i.e., "atlas").
+		path = strings.Fields(cmd.CommandPath())[1:]
+	)
+	for i := 0; ; i++ {
+		for _, a := range r.Attrs {
+			name := strings.ReplaceAll(a.K, "_", "-")
+			if !hasFlag(scope, name) {
+				return fmt.Errorf("unknown flag %q defined in the flags block for command %q", a.K, strings.Join(path, " "))
+			}
+			v, err := flagValue(a)
+			if err != nil {
+				return err
+			}
+			vs[name] = v
+		}
+		if i == len(path) {
+			return nil
+		}
+		c, ok := r.Resource(path[i])
+		if !ok {
+			return nil
+		}
+		r = c
+		for _, sub := range scope.Commands() {
+			if sub.Name() == path[i] {
+				scope = sub
+				break
+			}
+		}
+	}
+}
+
+// hasFlag reports if the given command, or any of its subcommands, has a flag with the given name.
+func hasFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flag(name) != nil {
+		return true
+	}
+	for _, c := range cmd.Commands() {
+		if hasFlag(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagValue returns the string representation of the attribute as a flag value.
+func flagValue(a *schemahcl.Attr) (string, error) {
+	switch t := a.V.Type(); {
+	case t == cty.String:
+		return a.V.AsString(), nil
+	case t == cty.Bool:
+		b, err := a.Bool()
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatBool(b), nil
+	case t == cty.Number:
+		return a.V.AsBigFloat().Text('f', -1), nil
+	case t.IsListType() || t.IsTupleType():
+		vs, err := a.Strings()
+		if err != nil {
+			return "", fmt.Errorf("flags.%s: %w", a.K, err)
+		}
+		return strings.Join(vs, ","), nil
+	default:
+		return "", fmt.Errorf("flags.%s: unsupported value type %s", a.K, t.FriendlyName())
+	}
+}
+
+// setFlagDefaults sets the default values of the command flags defined globally in
+// the project file and on the environment. Values set on the environment take
+// precedence over the global ones, and flags set explicitly by the user are kept.
+func (e *Env) setFlagDefaults(cmd *cobra.Command) error {
+	vs := make(map[string]string)
+	if e.config != nil {
+		if err := e.config.Flags.values(cmd, vs); err != nil {
+			return err
+		}
+	}
+	if err := e.Flags.values(cmd, vs); err != nil {
+		return err
+	}
+	for k, v := range vs {
+		if err := maySetFlag(cmd, k, v); err != nil {
+			return fmt.Errorf("setting flag %q from project file: %w", k, err)
+		}
+	}
+	return nil
+}
+
 // Extend allows extending environment blocks with
 // a global one. For example:
 //
@@ -559,6 +816,9 @@ func EnvByName(cmd *cobra.Command, name string, vars map[string]cty.Value) (*Pro
 	if err := maySetLoginContext(cmd, project); err != nil {
 		return nil, nil, err
 	}
+	if err := project.checkVersion(); err != nil {
+		return nil, nil, err
+	}
 	if err := project.Lint.remainedLog(); err != nil {
 		return nil, nil, err
 	}
@@ -675,6 +935,22 @@ func parseConfig(ctx context.Context, path, env string, vars map[string]cty.Valu
 	return p, nil
 }
 
+// checkVersion verifies the Atlas binary satisfies the version constraints of the project
+// (required_version), if defined. The check is done locally, and development builds skip it.
+func (p *Project) checkVersion() error {
+	if p.RequiredVersion == "" || !semver.IsValid(version) {
+		return nil
+	}
+	switch ok, err := vercheck.Satisfies(version, p.RequiredVersion); {
+	case err != nil:
+		return fmt.Errorf("parsing required_version: %w", err)
+	case !ok:
+		return fmt.Errorf("atlas version %s does not satisfy required_version %q defined in atlas.hcl", version, p.RequiredVersion)
+	default:
+		return nil
+	}
+}
+
 func init() {
 	cloudapi.SetVersion(version, flavor)
 	schemahcl.Register(blockEnv, &Env{})
@@ -0,0 +1,399 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	cmdmigrate "ariga.io/atlas/cmd/atlas/internal/migrate"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagTable     = "table"
+	flagColumn    = "column"
+	flagType      = "type"
+	flagUsing     = "using"
+	flagBatchSize = "batch-size"
+	flagRows      = "rows"
+)
+
+type migrateRetypeFlags struct {
+	dirURL, dirFormat string
+	devURL            string
+	table, column     string
+	typ               string
+	using             string // template of the value expression used for backfilling.
+	batchSize, rows   int
+	lockTimeout       time.Duration
+}
+
+// migrateRetypeCmd represents the 'atlas migrate retype' subcommand.
+func migrateRetypeCmd() *cobra.Command {
+	var (
+		flags migrateRetypeFlags
+		cmd   = &cobra.Command{
+			Use:   "retype [flags] [name]",
+			Short: "Create a sequence of migration files for changing a column type without a locking table rewrite.",
+			Long: `The 'atlas migrate retype' command uses the dev-database to calculate the current state of the migration
+directory, and creates a sequence of migration files for changing the type of a column without a single locking ALTER:
+
+  1. Add a new nullable column with the desired type.
+  2. Backfill the new column from the existing one, optionally in batches.
+  3. Backfill the rows that were missed, and swap the columns using renames.
+  4. Drop the old column.
+
+Between the first and the third files, the application is expected to write to both columns (dual-write).
+The value used for backfilling can be customized using a template. For example: 'CAST({{ .Column }} AS int)'.`,
+			Example: `  atlas migrate retype --dev-url "docker://postgres/15/dev?search_path=public" --table users --column age --type int
+  atlas migrate retype --env dev --table users --column age --type bigint --batch-size 10000 --rows 1000000
+  atlas migrate retype --env dev --table users --column age --type int --using 'CAST({{ .Column }} AS int)' users_age`,
+			Args: cobra.MaximumNArgs(1),
+			PreRunE: func(cmd *cobra.Command, _ []string) error {
+				if err := migrateFlagsFromConfig(cmd); err != nil {
+					return err
+				}
+				if err := dirFormatBC(flags.dirFormat, &flags.dirURL); err != nil {
+					return err
+				}
+				return checkDir(cmd, flags.dirURL, false)
+			},
+			RunE: RunE(func(cmd *cobra.Command, args []string) error {
+				return migrateRetypeRun(cmd, args, flags)
+			}),
+		}
+	)
+	cmd.Flags().SortFlags = false
+	addFlagDevURL(cmd.Flags(), &flags.devURL)
+	addFlagDirURL(cmd.Flags(), &flags.dirURL)
+	addFlagDirFormat(cmd.Flags(), &flags.dirFormat)
+	cmd.Flags().StringVar(&flags.table, flagTable, "", "the table of the column, optionally qualified with its schema")
+	cmd.Flags().StringVar(&flags.column, flagColumn, "", "the column to change its type")
+	cmd.Flags().StringVar(&flags.typ, flagType, "", "the desired column type")
+	cmd.Flags().StringVar(&flags.using, flagUsing, "{{ .Column }}", "template of the expression used for backfilling the new column")
+	cmd.Flags().IntVar(&flags.batchSize, flagBatchSize, 0, "number of rows to backfill in each statement. Zero means all rows at once")
+	cmd.Flags().IntVar(&flags.rows, flagRows, 0, "estimated number of rows in the table. Required when backfilling in batches")
+	addFlagLockTimeout(cmd.Flags(), &flags.lockTimeout)
+	cobra.CheckErr(cmd.MarkFlagRequired(flagDevURL))
+	cobra.CheckErr(cmd.MarkFlagRequired(flagTable))
+	cobra.CheckErr(cmd.MarkFlagRequired(flagColumn))
+	cobra.CheckErr(cmd.MarkFlagRequired(flagType))
+	return cmd
+}
+
+func migrateRetypeRun(cmd *cobra.Command, args []string, flags migrateRetypeFlags) error {
+	switch {
+	case flags.batchSize < 0 || flags.rows < 0:
+		return fmt.Errorf("--%s and --%s must not be negative", flagBatchSize, flagRows)
+	case flags.batchSize > 0 && flags.rows == 0:
+		return fmt.Errorf("--%s is required when --%s is set", flagRows, flagBatchSize)
+	}
+	using, err := template.New("using").Parse(flags.using)
+	if err != nil {
+		return fmt.Errorf("parse backfill template: %w", err)
+	}
+	ctx := cmd.Context()
+	dev, err := sqlclient.Open(ctx, flags.devURL)
+	if err != nil {
+		return err
+	}
+	defer dev.Close()
+	// Acquire a lock.
+	unlock, err := dev.Lock(ctx, "atlas_migrate_retype", flags.lockTimeout)
+	if err != nil {
+		return fmt.Errorf("acquiring database lock: %w", err)
+	}
+	// If unlocking fails notify the user about it.
+	defer func() { cobra.CheckErr(unlock()) }()
+	u, err := url.Parse(flags.dirURL)
+	if err != nil {
+		return err
+	}
+	dir, err := cmdmigrate.DirURL(ctx, u, false)
+	if err != nil {
+		return err
+	}
+	f, err := cmdmigrate.Formatter(u)
+	if err != nil {
+		return err
+	}
+	ex, err := migrate.NewExecutor(dev.Driver, dir, migrate.NopRevisionReadWriter{})
+	if err != nil {
+		return err
+	}
+	sr := migrate.RealmConn(dev.Driver, nil)
+	if dev.URL.Schema != "" {
+		sr = migrate.SchemaConn(dev.Driver, "", nil)
+	}
+	current, err := ex.Replay(ctx, sr)
+	if err != nil {
+		return err
+	}
+	r := &retyper{dev: dev, flags: flags, using: using}
+	plans, err := r.plans(ctx, current)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("retype_%s_%s", strings.ReplaceAll(flags.table, ".", "_"), flags.column)
+	if len(args) > 0 {
+		name = args[0]
+	}
+	var (
+		now = time.Now().UTC()
+		pl  = migrate.NewPlanner(dev.Driver, dir, migrate.PlanFormat(f))
+	)
+	for i, p := range plans {
+		// Files are versioned one second apart to keep their order.
+		p.Version = now.Add(time.Duration(i) * time.Second).Format("20060102150405")
+		p.Name = fmt.Sprintf("%s_%s", name, p.Name)
+		if err := pl.WritePlan(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retyper plans the migration steps for changing a column type.
+type retyper struct {
+	dev   *sqlclient.Client
+	flags migrateRetypeFlags
+	using *template.Template
+}
+
+// plans returns the "add", "backfill", "swap" and "drop" plans for the column.
+func (r *retyper) plans(ctx context.Context, current *schema.Realm) ([]*migrate.Plan, error) {
+	t, err := r.table(current)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := t.Column(r.flags.column)
+	if !ok {
+		return nil, fmt.Errorf("column %q was not found in table %q", r.flags.column, t.Name)
+	}
+	if err := r.check(current, t, c); err != nil {
+		return nil, err
+	}
+	p, ok := r.dev.Driver.(schema.TypeParser)
+	if !ok {
+		return nil, fmt.Errorf("driver %T does not support parsing types", r.dev.Driver)
+	}
+	typ, err := p.ParseType(r.flags.typ)
+	if err != nil {
+		return nil, fmt.Errorf("parse type %q: %w", r.flags.typ, err)
+	}
+	var (
+		name    = c.Name
+		oldName = c.Name + "_old"
+		newName = c.Name + "_new"
+	)
+	for _, n := range []string{oldName, newName} {
+		if _, ok := t.Column(n); ok {
+			return nil, fmt.Errorf("column %q already exists in table %q", n, t.Name)
+		}
+	}
+	// Step 1: add the new column as nullable, as its values are not set yet.
+	nc := &schema.Column{Name: newName, Type: &schema.ColumnType{Type: typ, Raw: r.flags.typ, Null: true}}
+	t.AddColumns(nc)
+	add, err := r.plan(ctx, "add", &schema.ModifyTable{T: t, Changes: []schema.Change{&schema.AddColumn{C: nc}}})
+	if err != nil {
+		return nil, err
+	}
+	// Step 2: backfill the new column.
+	backfill := &migrate.Plan{Name: "backfill"}
+	stmts, err := r.backfill(t, c, nc)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range stmts {
+		comment := fmt.Sprintf("backfill %q column", nc.Name)
+		if len(stmts) > 1 {
+			comment = fmt.Sprintf("%s (batch %d/%d)", comment, i+1, len(stmts))
+		}
+		backfill.Changes = append(backfill.Changes, &migrate.Change{Cmd: s, Comment: comment})
+	}
+	// Step 3: backfill rows that were missed since the previous step, and swap the columns.
+	catchup, err := r.backfillStmt(t, c, nc, 0)
+	if err != nil {
+		return nil, err
+	}
+	oc, onc := *c, *nc
+	c.Name, nc.Name = oldName, name
+	changes := []schema.Change{
+		&schema.ModifyTable{T: t, Changes: []schema.Change{
+			&schema.RenameColumn{From: &oc, To: c},
+			&schema.RenameColumn{From: &onc, To: nc},
+		}},
+	}
+	if !c.Type.Null || c.Default != nil {
+		from, ct := *nc, *nc.Type
+		ct.Null = c.Type.Null
+		nc.Type, nc.Default = &ct, c.Default
+		modify := &schema.ModifyColumn{From: &from, To: nc}
+		if !ct.Null {
+			modify.Change |= schema.ChangeNull
+		}
+		if nc.Default != nil {
+			modify.Change |= schema.ChangeDefault
+		}
+		changes = append(changes, &schema.ModifyTable{T: t, Changes: []schema.Change{modify}})
+	}
+	swap, err := r.plan(ctx, "swap", changes...)
+	if err != nil {
+		return nil, err
+	}
+	swap.Changes = append([]*migrate.Change{{Cmd: catchup, Comment: fmt.Sprintf("backfill rows that were missed in %q column", onc.Name)}}, swap.Changes...)
+	// Step 4: drop the old column.
+	t.Columns = slices.DeleteFunc(t.Columns, func(x *schema.Column) bool { return x == c })
+	drop, err := r.plan(ctx, "drop", &schema.ModifyTable{T: t, Changes: []schema.Change{&schema.DropColumn{C: c}}})
+	if err != nil {
+		return nil, err
+	}
+	return []*migrate.Plan{add, backfill, swap, drop}, nil
+}
+
+// table returns the table to be modified from the current state.
+func (r *retyper) table(current *schema.Realm) (*schema.Table, error) {
+	sName, tName := "", r.flags.table
+	if i := strings.IndexByte(tName, '.'); i != -1 {
+		sName, tName = tName[:i], tName[i+1:]
+	}
+	var found []*schema.Table
+	for _, s := range current.Schemas {
+		if sName != "" && s.Name != sName {
+			continue
+		}
+		if t, ok := s.Table(tName); ok {
+			found = append(found, t)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("table %q was not found in the migration directory state", r.flags.table)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("table %q was found in %d schemas, qualify it with its schema name", r.flags.table, len(found))
+	}
+}
+
+// check reports an error if the column cannot be swapped, as
+// indexes and constraints are bound to the existing column.
+func (r *retyper) check(current *schema.Realm, t *schema.Table, c *schema.Column) error {
+	if t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c }) {
+		return fmt.Errorf("column %q is part of the primary key of table %q and cannot be swapped", c.Name, t.Name)
+	}
+	if len(c.Indexes) > 0 {
+		return fmt.Errorf("column %q is part of index %q and cannot be swapped. Drop the index first, and recreate it after the type change", c.Name, c.Indexes[0].Name)
+	}
+	if len(c.ForeignKeys) > 0 {
+		return fmt.Errorf("column %q is part of foreign key %q and cannot be swapped", c.Name, c.ForeignKeys[0].Symbol)
+	}
+	for _, s := range current.Schemas {
+		for _, t := range s.Tables {
+			for _, fk := range t.ForeignKeys {
+				if slices.Contains(fk.RefColumns, c) {
+					return fmt.Errorf("column %q is referenced by foreign key %q of table %q and cannot be swapped", c.Name, fk.Symbol, t.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// plan plans the given changes using the dev-database driver.
+func (r *retyper) plan(ctx context.Context, name string, changes ...schema.Change) (*migrate.Plan, error) {
+	var opts []migrate.PlanOption
+	if r.dev.URL.Schema != "" {
+		// Disable tables qualifier in schema-mode.
+		opts = append(opts, func(o *migrate.PlanOptions) {
+			o.SchemaQualifier = new(string)
+		})
+	}
+	p, err := r.dev.Driver.PlanChanges(ctx, name, changes, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("plan %s step: %w", name, err)
+	}
+	return p, nil
+}
+
+// backfill returns the statements for backfilling the new column from the existing one.
+func (r *retyper) backfill(t *schema.Table, from, to *schema.Column) ([]string, error) {
+	if r.flags.batchSize == 0 {
+		s, err := r.backfillStmt(t, from, to, 0)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+	if t.PrimaryKey == nil || slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == nil }) {
+		return nil, fmt.Errorf("backfilling in batches requires table %q to have a primary key of columns", t.Name)
+	}
+	n := (r.flags.rows + r.flags.batchSize - 1) / r.flags.batchSize
+	stmts := make([]string, n)
+	for i := range stmts {
+		s, err := r.backfillStmt(t, from, to, r.flags.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		stmts[i] = s
+	}
+	return stmts, nil
+}
+
+// backfillStmt returns a statement that backfills up to size rows, or all rows if size is zero.
+func (r *retyper) backfillStmt(t *schema.Table, from, to *schema.Column, size int) (string, error) {
+	var (
+		b     strings.Builder
+		table = r.tableIdent(t)
+		cond  = fmt.Sprintf("%s IS NULL AND %s IS NOT NULL", r.ident(to.Name), r.ident(from.Name))
+	)
+	if err := r.using.Execute(&b, struct{ Table, Column string }{Table: table, Column: r.ident(from.Name)}); err != nil {
+		return "", fmt.Errorf("execute backfill template: %w", err)
+	}
+	stmt := fmt.Sprintf("UPDATE %s SET %s = %s WHERE %s", table, r.ident(to.Name), b.String(), cond)
+	if size == 0 {
+		return stmt, nil
+	}
+	pk := make([]string, len(t.PrimaryKey.Parts))
+	for i, p := range t.PrimaryKey.Parts {
+		pk[i] = r.ident(p.C.Name)
+	}
+	key := strings.Join(pk, ", ")
+	if len(pk) > 1 {
+		key = "(" + key + ")"
+	}
+	// The batch is selected using a derived table, as some databases
+	// do not support LIMIT in subqueries of the updated table.
+	return fmt.Sprintf("%s AND %s IN (SELECT %s FROM (SELECT %s FROM %s WHERE %s LIMIT %d) AS batch)",
+		stmt, key, strings.Join(pk, ", "), strings.Join(pk, ", "), table, cond, size), nil
+}
+
+// tableIdent returns the table identifier, qualified with its schema in realm-mode.
+func (r *retyper) tableIdent(t *schema.Table) string {
+	if r.dev.URL.Schema == "" && t.Schema != nil && t.Schema.Name != "" {
+		return r.ident(t.Schema.Name) + "." + r.ident(t.Name)
+	}
+	return r.ident(t.Name)
+}
+
+// ident quotes the given identifier according to the dev-database dialect.
+func (r *retyper) ident(s string) string {
+	if r.dev.Name == mysql.DriverName || r.dev.Name == sqlite.DriverName {
+		return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,156 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Query parameters of database URLs that control the sanitization of their
+// inspected state. For example, reading the desired state from a staging
+// database, without its temporary tables and comments:
+//
+//	atlas schema apply -u "mysql://prod/app" --to "mysql://staging/app?sanitize=true&sanitize_exclude=audit_*"
+const (
+	sanitizeParam        = "sanitize"
+	sanitizeExcludeParam = "sanitize_exclude"
+)
+
+// sanitizeExclude holds the default patterns of temporary tables that are excluded
+// from sanitized states. For example, tables created by online schema-change tools
+// like gh-ost (_t_gho, _t_ghc, _t_del) or pt-online-schema-change (_t_new, _t_old).
+var sanitizeExclude = []string{"tmp_*", "temp_*", "_*_gho", "_*_ghc", "_*_del", "_*_new", "_*_old"}
+
+// sanitizer removes environment-specific details from an inspected state,
+// in order to use it as a desired state of other databases.
+type sanitizer struct {
+	exclude []string // glob patterns of tables and views to exclude
+}
+
+// sanitizeURL extracts the sanitization parameters from the given URL, and returns it
+// without them. A nil sanitizer is returned in case sanitization was not requested.
+func sanitizeURL(s string) (string, *sanitizer, error) {
+	u, err := url.Parse(s)
+	if err != nil || u.RawQuery == "" {
+		return s, nil, nil
+	}
+	q := u.Query()
+	if !q.Has(sanitizeParam) && !q.Has(sanitizeExcludeParam) {
+		return s, nil, nil
+	}
+	var sz *sanitizer
+	if v := q.Get(sanitizeParam); v != "" {
+		on, err := strconv.ParseBool(v)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid value for %q parameter: %q", sanitizeParam, v)
+		}
+		if on {
+			sz = &sanitizer{exclude: slices.Clone(sanitizeExclude)}
+		}
+	}
+	if v := q.Get(sanitizeExcludeParam); v != "" {
+		if sz == nil {
+			return "", nil, fmt.Errorf("%q parameter requires %s=true", sanitizeExcludeParam, sanitizeParam)
+		}
+		for _, p := range strings.Split(v, ",") {
+			if _, err := path.Match(p, ""); err != nil {
+				return "", nil, fmt.Errorf("invalid pattern %q in %q parameter: %w", p, sanitizeExcludeParam, err)
+			}
+			sz.exclude = append(sz.exclude, p)
+		}
+	}
+	// Remove the parameters from the raw query to keep the
+	// encoding and the order of the other parameters as is.
+	var kept []string
+	for _, kv := range strings.Split(u.RawQuery, "&") {
+		if k, _, _ := strings.Cut(kv, "="); k != sanitizeParam && k != sanitizeExcludeParam {
+			kept = append(kept, kv)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String(), sz, nil
+}
+
+// reader wraps the given StateReader with sanitization.
+func (s *sanitizer) reader(sr migrate.StateReader) migrate.StateReader {
+	return migrate.StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
+		r, err := sr.ReadState(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.realm(r)
+		return r, nil
+	})
+}
+
+// realm sanitizes the given realm in place.
+func (s *sanitizer) realm(r *schema.Realm) {
+	for _, sc := range r.Schemas {
+		sc.Attrs = noComment(sc.Attrs)
+		tables := sc.Tables[:0]
+		for _, t := range sc.Tables {
+			if s.excluded(t.Name) {
+				continue
+			}
+			t.Attrs = noComment(t.Attrs)
+			for _, c := range t.Columns {
+				c.Attrs = noComment(c.Attrs)
+			}
+			for _, idx := range t.Indexes {
+				idx.Attrs = noComment(idx.Attrs)
+			}
+			tables = append(tables, t)
+		}
+		sc.Tables = tables
+		views := sc.Views[:0]
+		for _, v := range sc.Views {
+			if s.excluded(v.Name) {
+				continue
+			}
+			v.Attrs = noComment(v.Attrs)
+			for _, c := range v.Columns {
+				c.Attrs = noComment(c.Attrs)
+			}
+			views = append(views, v)
+		}
+		sc.Views = views
+		for _, f := range sc.Funcs {
+			f.Attrs = noComment(f.Attrs)
+		}
+		for _, p := range sc.Procs {
+			p.Attrs = noComment(p.Attrs)
+		}
+	}
+}
+
+// excluded reports if the given table or view name matches one of the exclude patterns.
+func (s *sanitizer) excluded(name string) bool {
+	for _, p := range s.exclude {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// noComment returns the attributes without the comment attribute.
+func noComment(attrs []schema.Attr) []schema.Attr {
+	kept := attrs[:0]
+	for _, a := range attrs {
+		if _, ok := a.(*schema.Comment); !ok {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
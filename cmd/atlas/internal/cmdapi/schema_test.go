@@ -5,9 +5,11 @@
 package cmdapi
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -15,11 +17,15 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlclient"
+	"github.com/1lann/promptui"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/require"
 )
 
@@ -56,6 +62,45 @@ func TestSchema_Diff(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, "-- Create \"t1\" table\nCREATE TABLE `t1` (\n  `id` int NULL\n);\n", s)
 
+	// Explain the planned changes.
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, "create table t1 (id int); create table t2 (id int);"),
+		"--to", openSQLite(t, "create table t1 (id int, name text);"),
+		"--explain",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, "-- Disable the enforcement of foreign-keys constraints\nPRAGMA foreign_keys = off;\n-- Add column \"name\" to table: \"t1\"\n--   column \"name\" is missing in the current state\nALTER TABLE `t1` ADD COLUMN `name` text NULL;\n-- Drop \"t2\" table\n--   table \"t2\" is not defined in the desired state\nDROP TABLE `t2`;\n-- Enable back the enforcement of foreign-keys constraints\nPRAGMA foreign_keys = on;\n", s)
+
+	// JSON output with stable identifiers. Objects are unqualified in schema scope.
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, "create table t1 (id int); create table t2 (id int);"),
+		"--to", openSQLite(t, "create table t1 (id int, name text);"),
+		"--format", `{{ json . }}`,
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, `{"changes":[{"op":"modify","type":"table","id":"t1","changes":[{"op":"add","type":"column","id":"t1.name","attrs":{"null":true,"type":"text"}}]},{"op":"drop","type":"table","id":"t2"}]}`, s)
+
+	// JSON shorthand.
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, "create table t1 (id int);"),
+		"--to", openSQLite(t, "create table t1 (id text not null);"),
+		"--format", "json",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, `{"changes":[{"op":"modify","type":"table","id":"t1","changes":[{"op":"modify","type":"column","id":"t1.id","attrs":{"null":{"current":true,"desired":false},"type":{"current":"int","desired":"text"}}}]}]}`+"\n", s)
+
+	// Sanitized desired state skips temporary tables.
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, ""),
+		"--to", openSQLite(t, "create table t1 (id int); create table _t1_gho (id int); create table scratch (id int);")+"&sanitize=true&sanitize_exclude=scratch",
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, "-- Create \"t1\" table\nCREATE TABLE `t1` (`id` int NULL);\n", s)
+
 	// No changes.
 	s, err = runCmd(
 		schemaDiffCmd(),
@@ -459,6 +504,103 @@ schema "main" {
 	})
 }
 
+func TestSchema_Drift(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		cp  = filepath.Join(p, "atlas.hcl")
+		sp  = filepath.Join(p, "schema.hcl")
+		md  = filepath.Join(p, "migrations")
+		cfg = fmt.Sprintf(`
+env "synced" {
+  url = "%s"
+  dev = "%s"
+  src = "file://%s"
+}
+
+env "drifted" {
+  url = "%s"
+  dev = "%s"
+  src = "file://%s"
+}
+
+env "ignored" {
+  url = "%s"
+  dev = "%s"
+  src = "file://%s"
+  drift {
+    exclude = ["audit"]
+  }
+}
+
+env "versioned" {
+  url = "%s"
+  dev = "%s"
+  migration {
+    dir = "file://%s"
+  }
+}
+`,
+			openSQLite(t, "create table users (id int not null)"), openSQLite(t, ""), sp,
+			openSQLite(t, "create table users (id int not null); create table audit (id int)"), openSQLite(t, ""), sp,
+			openSQLite(t, "create table users (id int not null); create table audit (id int)"), openSQLite(t, ""), sp,
+			openSQLite(t, ""), openSQLite(t, ""), md,
+		)
+	)
+	require.NoError(t, os.WriteFile(cp, []byte(cfg), 0600))
+	require.NoError(t, os.WriteFile(sp, []byte(`
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+}
+`), 0600))
+	require.NoError(t, os.Mkdir(md, 0700))
+	dir, err := migrate.NewLocalDir(md)
+	require.NoError(t, err)
+	require.NoError(t, dir.WriteFile("1.sql", []byte("create table t1 (c int);")))
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+
+	run := func(args ...string) (string, error) {
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaDriftCmd())
+		return runCmd(cmd, append([]string{"drift", "-c", "file://" + cp}, args...)...)
+	}
+	_, err = run()
+	require.EqualError(t, err, "the --env flag is required to check for schema drift")
+
+	s, err := run("--env", "synced")
+	require.NoError(t, err)
+	require.Equal(t, "No schema drift detected in env \"synced\"\n", s)
+
+	s, err = run("--env", "drifted")
+	require.EqualError(t, err, "schema drift detected in 1 environment(s)")
+	require.Equal(t, `Schema drift detected in env "drifted" (1 changes):
+-- Disable the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = off;
+-- Drop "audit" table
+DROP TABLE `+"`audit`"+`;
+-- Enable back the enforcement of foreign-keys constraints
+PRAGMA foreign_keys = on;
+`, s)
+
+	// Resources can be ignored using the flag or the env config.
+	s, err = run("--env", "drifted", "--exclude", "audit")
+	require.NoError(t, err)
+	require.Equal(t, "No schema drift detected in env \"drifted\"\n", s)
+	s, err = run("--env", "ignored")
+	require.NoError(t, err)
+	require.Equal(t, "No schema drift detected in env \"ignored\"\n", s)
+
+	// The migration directory is used if no "src" is set.
+	s, err = run("--env", "versioned", "--format", "{{ .Changes }}")
+	require.EqualError(t, err, "schema drift detected in 1 environment(s)")
+	require.Equal(t, "1", s)
+}
+
 func TestSchema_Apply(t *testing.T) {
 	const drvName = "checknormalizer"
 	// If no dev-database is given, there must not be a call to Driver.Normalize.
@@ -588,6 +730,61 @@ func TestSchema_ApplyLog(t *testing.T) {
 		require.Equal(t, out.Pending[0], out.Error.Stmt)
 		require.Contains(t, out.Error.Text, `UNIQUE constraint failed: t2.id`)
 	})
+
+	t.Run("Stats", func(t *testing.T) {
+		db := openSQLite(t, "create table t1 (id int);")
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaApplyCmd())
+		s, err := runCmd(
+			cmd, "apply",
+			"-u", db,
+			"--to", openSQLite(t, "create table t1 (id int); create index t1_id on t1 (id); create table t2 (id int);"),
+			"--auto-approve",
+			"--format", "{{ json .Stats }}",
+		)
+		require.NoError(t, err)
+		var stats []struct {
+			Stmt              string
+			Class             string
+			Estimated, Actual time.Duration
+		}
+		require.NoError(t, json.Unmarshal([]byte(s), &stats))
+		require.Len(t, stats, 2)
+		require.Equal(t, "scan", stats[0].Class)
+		require.Equal(t, "CREATE INDEX `t1_id` ON `t1` (`id`)", strings.TrimRight(stats[0].Stmt, ";"))
+		require.Equal(t, "metadata", stats[1].Class)
+		require.Equal(t, "CREATE TABLE `t2` (\n  `id` int NULL\n)", strings.TrimRight(stats[1].Stmt, ";"))
+		for _, st := range stats {
+			require.Equal(t, migrate.DefaultEstimator.Base, st.Estimated)
+			require.Positive(t, st.Actual)
+		}
+	})
+}
+
+func TestApplyProgress(t *testing.T) {
+	var (
+		b bytes.Buffer
+		p = &applyProgress{
+			w: &b,
+			ests: []*migrate.Estimate{
+				{Class: migrate.StmtClassMetadata, Duration: 10 * time.Millisecond},
+				{Class: migrate.StmtClassRewrite, Rows: 1000000, Duration: 2 * time.Second},
+			},
+		}
+	)
+	p.report(0)
+	require.Equal(t, "\r\033[K[                              ] 1/2 executing metadata statement (~10ms), ETA 2s", b.String())
+	b.Reset()
+	p.report(1)
+	require.Equal(t, "\r\033[K[===============               ] 2/2 executing rewrite statement on ~1000000 rows (~2s), ETA 2s", b.String())
+	b.Reset()
+	p.done()
+	require.Equal(t, "\r\033[K[==============================] 2/2\n", b.String())
+
+	// Progress is not reported without a writer.
+	p.w = nil
+	p.report(0)
+	p.done()
 }
 
 func TestSchema_ApplySchemaMismatch(t *testing.T) {
@@ -610,6 +807,191 @@ schema "hello" {}
 	require.EqualError(t, err, `mismatched HCL and database schemas: "main" <> "hello"`)
 }
 
+type privilegeCheckerDriver struct {
+	migrate.Driver
+	privs []*migrate.Privilege
+}
+
+func (d *privilegeCheckerDriver) MissingPrivileges(_ context.Context, privs []*migrate.Privilege) ([]*migrate.Privilege, error) {
+	d.privs = privs
+	return privs[:1], nil
+}
+
+func TestSchema_ApplyCheckPrivileges(t *testing.T) {
+	var (
+		drv = &privilegeCheckerDriver{}
+		src = filepath.Join(t.TempDir(), "schema.sql")
+	)
+	sqlclient.Register(
+		"privchecker",
+		sqlclient.OpenerFunc(func(ctx context.Context, u *url.URL) (*sqlclient.Client, error) {
+			u.Scheme = "sqlite"
+			c, err := sqlclient.OpenURL(ctx, u)
+			if err != nil {
+				return nil, err
+			}
+			drv.Driver = c.Driver
+			c.Driver = drv
+			return c, nil
+		}),
+	)
+	require.NoError(t, os.WriteFile(src, []byte("CREATE TABLE t1 (c int);\nCREATE TABLE t2 (c int);"), 0600))
+	_, err := runCmd(
+		schemaApplyCmd(),
+		"-u", "privchecker://file?mode=memory",
+		"--to", "file://"+src,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--check-privileges",
+		"--auto-approve",
+	)
+	require.EqualError(t, err, "missing privileges for applying the changes:\n\tCREATE on table \"t1\"")
+	require.Len(t, drv.privs, 2)
+
+	// Drivers that do not support checking privileges.
+	_, err = runCmd(
+		schemaApplyCmd(),
+		"-u", openSQLite(t, ""),
+		"--to", "file://"+src,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--check-privileges",
+		"--auto-approve",
+	)
+	require.EqualError(t, err, `driver "sqlite3" does not support checking privileges`)
+}
+
+func TestSchema_ApplyLimitChanges(t *testing.T) {
+	var (
+		u   = openSQLite(t, "create table users (id int); create table posts (id int);")
+		src = filepath.Join(t.TempDir(), "schema.sql")
+	)
+	require.NoError(t, os.WriteFile(src, []byte(`
+create table users (id int, email text, name text);
+create index users_email on users (email);
+create table posts (id int, title text);
+create table comments (id int);
+`), 0600))
+	apply := func(limit string) string {
+		s, err := runCmd(
+			schemaApplyCmd(),
+			"-u", u,
+			"--to", "file://"+src,
+			"--dev-url", "sqlite://dev?mode=memory",
+			"--limit-changes-to", limit,
+			"--dry-run",
+		)
+		require.NoError(t, err)
+		return s
+	}
+	s := apply("main.comments")
+	require.Contains(t, s, "CREATE TABLE `comments`")
+	require.NotContains(t, s, "ALTER TABLE")
+	// Elements can be qualified by their table name, or their schema name.
+	s = apply("users.email,main.users_email")
+	require.Contains(t, s, "ALTER TABLE `users` ADD COLUMN `email` text NULL")
+	require.Contains(t, s, "CREATE INDEX `users_email` ON `users` (`email`)")
+	require.NotContains(t, s, "`name`")
+	require.NotContains(t, s, "`posts`")
+	require.NotContains(t, s, "`comments`")
+	// Changes that do not touch the listed objects are deferred.
+	require.Equal(t, "Schema is synced, no changes to be made\n", apply("main.unknown"))
+}
+
+func TestSchema_ApplyInteractive(t *testing.T) {
+	var (
+		u    = openSQLite(t, "create table users (id int);")
+		src  = filepath.Join(t.TempDir(), "schema.sql")
+		prev = promptStmt
+	)
+	t.Cleanup(func() { promptStmt = prev })
+	answers := []string{answerSkip, answerApply}
+	promptStmt = func(*cobra.Command) (string, error) {
+		a := answers[0]
+		answers = answers[1:]
+		return a, nil
+	}
+	require.NoError(t, os.WriteFile(src, []byte(`
+create table users (id int, email text);
+create table posts (id int);
+`), 0600))
+	s, err := runCmd(
+		schemaApplyCmd(),
+		"-u", u,
+		"--to", "file://"+src,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--interactive",
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, "-- Statement 1 of 2:\n   -- add column \"email\" to table: \"users\"\n   ALTER TABLE `users` ADD COLUMN `email` text NULL\n")
+	require.Contains(t, s, "-- Statement 2 of 2:\n   -- create \"posts\" table\n   CREATE TABLE `posts` (\n     `id` int NULL\n   )\n")
+	require.True(t, strings.HasSuffix(s, "-- Skipped 1 of 2 statements. To apply them later, execute:\nALTER TABLE `users` ADD COLUMN `email` text NULL;\n"), s)
+
+	// Only the skipped statement is planned on the next run.
+	s, err = runCmd(
+		schemaApplyCmd(),
+		"-u", u,
+		"--to", "file://"+src,
+		"--dev-url", "sqlite://dev?mode=memory",
+		"--dry-run",
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, "ALTER TABLE `users` ADD COLUMN `email` text NULL")
+	require.NotContains(t, s, "`posts`")
+
+	_, err = runCmd(schemaApplyCmd(), "-u", u, "--to", "file://"+src, "--interactive", "--auto-approve")
+	require.EqualError(t, err, "if any flags in the group [interactive auto-approve] are set none of the others can be; [auto-approve interactive] were all set")
+}
+
+func TestSchema_ApplyMaintainPartitions(t *testing.T) {
+	var (
+		u   = openSQLite(t, "create table users (id int);")
+		src = filepath.Join(t.TempDir(), "schema.sql")
+	)
+	require.NoError(t, os.WriteFile(src, []byte("create table users (id int);"), 0600))
+	_, err := runCmd(schemaApplyCmd(), "-u", u, "--to", "file://"+src, "--maintain-partitions", "--limit-changes-to", "main.users")
+	require.EqualError(t, err, "--maintain-partitions cannot be used with --edit, --plan or --limit-changes-to")
+	_, err = runCmd(schemaApplyCmd(), "-u", u, "--to", "file://"+src, "--maintain-partitions", "--dry-run")
+	require.EqualError(t, err, "--maintain-partitions is not supported by the sqlite3 driver")
+}
+
+func TestSchema_ApplyUserVersion(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		src = filepath.Join(p, "schema.hcl")
+		u   = openSQLite(t, "")
+	)
+	err := os.WriteFile(src, []byte(`
+schema "main" {
+  user_version = 5
+}
+`), 0600)
+	require.NoError(t, err)
+	s, err := runCmd(
+		schemaApplyCmd(),
+		"-u", u,
+		"-f", src,
+		"--dev-url", "sqlite://dev?mode=memory&_pragma=foreign_keys(1)",
+		"--auto-approve",
+	)
+	require.NoError(t, err)
+	require.Contains(t, s, "PRAGMA user_version = 5;")
+
+	db, err := sql.Open("sqlite3", strings.TrimPrefix(u, "sqlite://"))
+	require.NoError(t, err)
+	defer db.Close()
+	var v int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&v))
+	require.Equal(t, 5, v)
+
+	s, err = runCmd(
+		schemaApplyCmd(),
+		"-u", u,
+		"-f", src,
+		"--auto-approve",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Schema is synced, no changes to be made\n", s)
+}
+
 func TestSchema_ApplySkip(t *testing.T) {
 	var (
 		p   = t.TempDir()
@@ -707,6 +1089,156 @@ diff {
 	)
 }
 
+func TestSchema_ApplyWindow(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		cfg = filepath.Join(p, "atlas.hcl")
+		src = filepath.Join(p, "schema.hcl")
+	)
+	err := os.WriteFile(src, []byte(`
+schema "main" {}
+
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+}
+`), 0600)
+	require.NoError(t, err)
+	err = os.WriteFile(cfg, []byte(`
+env "local" {
+  src = "file://`+src+`"
+  dev = "sqlite://dev?mode=memory&_fk=1"
+  apply_window {
+    schedule = "0 22 * * *"
+    duration = "2h"
+  }
+}
+`), 0600)
+	require.NoError(t, err)
+	now := time.Date(2024, 5, 6, 21, 59, 59, 990*int(time.Millisecond), time.UTC)
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+	apply := func(url string, args ...string) (string, error) {
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaApplyCmd())
+		return runCmd(cmd, append([]string{
+			"apply",
+			"-u", url,
+			"-c", "file://" + cfg,
+			"--env", "local",
+			"--auto-approve",
+			"--format", "{{ json .Changes }}",
+		}, args...)...)
+	}
+
+	// Non-destructive changes are applied outside the window.
+	s, err := apply(openSQLite(t, ""))
+	require.NoError(t, err)
+	require.Equal(t, "{\"Applied\":[\"CREATE TABLE `users` (\\n  `id` int NOT NULL\\n)\"]}", strings.ReplaceAll(s, ";", ""))
+
+	// Destructive changes are refused outside the window.
+	_, err = apply(openSQLite(t, "create table pets (id int);"))
+	require.EqualError(t, err, `destructive changes cannot be applied outside the apply window of env "local". The next window opens at 2024-05-06T22:00:00Z, use --wait to wait for it`)
+	_, err = apply(openSQLite(t, "create table pets (id int);"), "--wait", "5ms")
+	require.Error(t, err)
+	_, err = apply(openSQLite(t, "create table users (id int not null); create index users_id on users (id);"))
+	require.ErrorContains(t, err, "destructive changes cannot be applied outside the apply window")
+
+	// Wait for the window to open.
+	s, err = apply(openSQLite(t, "create table pets (id int);"), "--wait", "1m")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(s, "Waiting for the apply window to open at 2024-05-06T22:00:00Z\n"), s)
+	require.Contains(t, s, "DROP TABLE `pets`")
+
+	// Destructive changes are applied within the window.
+	now = now.Add(time.Hour)
+	s, err = apply(openSQLite(t, "create table pets (id int);"))
+	require.NoError(t, err)
+	require.Contains(t, s, "DROP TABLE `pets`")
+
+	// Planning is allowed outside the window.
+	now = now.Add(3 * time.Hour)
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaApplyCmd())
+	s, err = runCmd(cmd, "apply", "-u", openSQLite(t, "create table pets (id int);"), "-c", "file://"+cfg, "--env", "local", "--dry-run")
+	require.NoError(t, err)
+	require.Contains(t, s, "DROP TABLE `pets`")
+}
+
+func TestSchema_ApplyLockName(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		p     = t.TempDir()
+		cfg   = filepath.Join(p, "atlas.hcl")
+		src   = filepath.Join(p, "schema.hcl")
+		dir   = filepath.Join(p, "migrations")
+		dbURL = openSQLite(t, "")
+	)
+	err := os.WriteFile(src, []byte(`
+schema "main" {}
+
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+}
+`), 0600)
+	require.NoError(t, err)
+	require.NoError(t, os.Mkdir(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "1.sql"), []byte("CREATE TABLE users (id int NOT NULL);"), 0600))
+	d, err := migrate.NewLocalDir(dir)
+	require.NoError(t, err)
+	sum, err := d.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(d, sum))
+	err = os.WriteFile(cfg, []byte(`
+env "local" {
+  src       = "file://`+src+`"
+  dev       = "sqlite://dev?mode=memory&_fk=1"
+  lock_name = "app-schema"
+  migration {
+    dir = "file://`+dir+`"
+  }
+}
+`), 0600)
+	require.NoError(t, err)
+	schemaApply := func() (string, error) {
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaApplyCmd())
+		return runCmd(cmd, "apply", "-u", dbURL, "-c", "file://"+cfg, "--env", "local", "--auto-approve")
+	}
+	migrateApply := func() (string, error) {
+		cmd := migrateCmd()
+		cmd.AddCommand(migrateApplyCmd())
+		return runCmd(cmd, "apply", "-u", dbURL, "-c", "file://"+cfg, "--env", "local")
+	}
+
+	// Both commands acquire the configured lock, and are
+	// blocked while it is held by another pipeline.
+	c, err := sqlclient.Open(ctx, dbURL)
+	require.NoError(t, err)
+	defer c.Close()
+	unlock, err := c.Lock(ctx, "app-schema", time.Minute)
+	require.NoError(t, err)
+	_, err = schemaApply()
+	require.ErrorContains(t, err, `acquiring database lock: sql/sqlite: lock on "app-schema_`)
+	_, err = migrateApply()
+	require.ErrorContains(t, err, `acquiring database lock: sql/sqlite: lock on "app-schema_`)
+
+	// The lock is released by the other pipeline.
+	require.NoError(t, unlock())
+	s, err := schemaApply()
+	require.NoError(t, err)
+	require.Contains(t, s, "CREATE TABLE `users`")
+	// The lock is released by 'schema apply' as well.
+	unlock, err = c.Lock(ctx, "app-schema", time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+}
+
 func TestSchema_ApplySources(t *testing.T) {
 	var (
 		p   = t.TempDir()
@@ -969,7 +1501,407 @@ func TestSchema_InspectLog(t *testing.T) {
 		"--format", "{{ json . }}",
 	)
 	require.NoError(t, err)
-	require.Equal(t, `{"schemas":[{"name":"main","tables":[{"name":"t1","columns":[{"name":"id","type":"INTEGER","null":true}],"primary_key":{"parts":[{"column":"id"}]}},{"name":"t2","columns":[{"name":"name","type":"TEXT","null":true}]}]}]}`, s)
+	require.Equal(t, `{"schemas":[{"id":"main","name":"main","tables":[{"id":"main.t1","name":"t1","columns":[{"id":"main.t1.id","name":"id","type":"INTEGER","null":true}],"primary_key":{"id":"main.t1#primary_key","parts":[{"column":"id"}]},"checksum":"f7bc0673dd5613872ffae9f68b1e227738217e6d8087bbda1feeece98a18f7ab"},{"id":"main.t2","name":"t2","columns":[{"id":"main.t2.name","name":"name","type":"TEXT","null":true}],"checksum":"03ec6cee18e509d94ef5f0d8cc8a90ba026989cf0361aa580243b2d4de9adc3d"}]}]}`, s)
+}
+
+func TestSchema_Graph(t *testing.T) {
+	db := openSQLite(t, "create table users (id integer primary key);create table posts (id integer primary key, author_id int constraint author_fk references users(id));create table tags (id integer primary key);")
+	run := func(args ...string) (string, error) {
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaGraphCmd())
+		return runCmd(cmd, append([]string{"graph", "-u", db}, args...)...)
+	}
+	s, err := run()
+	require.NoError(t, err)
+	require.Equal(t, `digraph schema {
+  rankdir=LR;
+  node [shape=box];
+  n0 [label="main.users"];
+  n1 [label="main.posts"];
+  n2 [label="main.tags"];
+  n1 -> n0 [label="author_fk"];
+}
+`, s)
+
+	s, err = run("--focus", "table=users", "--depth", "1", "--format", "{{ json . }}")
+	require.NoError(t, err)
+	require.Equal(t, `{"nodes":[{"id":"main.users","type":"table"},{"id":"main.posts","type":"table"}],"edges":[{"from":"main.posts","to":"main.users","type":"foreign_key","label":"author_fk"}]}`, s)
+
+	_, err = run("--depth", "1")
+	require.EqualError(t, err, "--depth requires --focus to be set")
+	_, err = run("--focus", "table=unknown")
+	require.EqualError(t, err, `no objects match the focus filter "table=unknown"`)
+}
+
+func TestSchema_Portability(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "schema.hcl")
+	require.NoError(t, os.WriteFile(p, []byte(`
+schema "app" {}
+table "users" {
+  schema = schema.app
+  column "id" {
+    type     = int
+    unsigned = true
+  }
+  column "born" {
+    type    = date
+    default = "0000-00-00"
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+`), 0600))
+	run := func(args ...string) (string, error) {
+		cmd := schemaCmd()
+		cmd.AddCommand(schemaPortabilityCmd())
+		return runCmd(cmd, append([]string{"portability"}, args...)...)
+	}
+	// HCL schemas are evaluated without a dev-database.
+	s, err := run("--from", "mysql", "--to", "postgres", "file://"+p)
+	require.NoError(t, err)
+	require.Equal(t, `Found 2 portability issues from mysql to postgres:
+
+  -- app.users.id: unsigned integers are not supported by postgres
+     Suggestion: use bigint with a CHECK (id >= 0) constraint
+  -- app.users.born: zero date default '0000-00-00' is not a valid value in postgres
+     Suggestion: use NULL as the default value, or a valid date (e.g. '1970-01-01')
+`, s)
+
+	s, err = run("--from", "mysql", "--to", "mariadb", "-u", "file://"+p)
+	require.NoError(t, err)
+	require.Equal(t, "No portability issues found from mysql to mariadb\n", s)
+
+	s, err = run("--from", "sqlite", "--to", "postgres", "-u", openSQLite(t, "create table t (id int);"), "--format", "{{ json . }}")
+	require.NoError(t, err)
+	require.Equal(t, `{"from":"sqlite","to":"postgres"}`, s)
+
+	_, err = run("--from", "oracle", "--to", "postgres", "file://"+p)
+	require.EqualError(t, err, `unknown dialect "oracle". Expect one of: mysql, mariadb, postgres or sqlite`)
+	_, err = run("--from", "mysql", "--to", "postgres")
+	require.EqualError(t, err, `required flag(s) "url" not set`)
+}
+
+func TestSchema_ConfirmDropSchemas(t *testing.T) {
+	var (
+		out     bytes.Buffer
+		cmd     = &cobra.Command{}
+		changes = []schema.Change{&schema.AddTable{T: schema.NewTable("t")}, &schema.DropSchema{S: schema.New("app")}}
+	)
+	cmd.SetOut(&out)
+	require.True(t, confirmDropSchemas(cmd, changes[:1]), "no schemas are dropped")
+	cmd.SetIn(strings.NewReader("app\n"))
+	require.True(t, confirmDropSchemas(cmd, changes))
+	cmd.SetIn(strings.NewReader("other\n"))
+	require.False(t, confirmDropSchemas(cmd, changes))
+	require.Contains(t, out.String(), `The given name does not match schema "app". Aborting.`)
+}
+
+func TestSchema_SelectChanges(t *testing.T) {
+	var (
+		out     bytes.Buffer
+		answers []string
+		cmd     = &cobra.Command{}
+		changes = []*migrate.Change{
+			{Cmd: "CREATE TABLE t1 (id int)"},
+			{Cmd: "CREATE TABLE t2 (id int)", Comment: "create t2"},
+			{Cmd: "CREATE TABLE t3 (id int)"},
+			{Cmd: "CREATE TABLE t4 (id int)"},
+		}
+		prev = promptStmt
+	)
+	t.Cleanup(func() { promptStmt = prev })
+	promptStmt = func(*cobra.Command) (string, error) {
+		if len(answers) == 0 {
+			return "", promptui.ErrEOF
+		}
+		a := answers[0]
+		answers = answers[1:]
+		return a, nil
+	}
+	cmd.SetOut(&out)
+	answers = []string{answerApply, answerSkip, answerApplyAll}
+	selected, skipped := selectChanges(cmd, changes)
+	require.Equal(t, []*migrate.Change{changes[0], changes[2], changes[3]}, selected)
+	require.Equal(t, []*migrate.Change{changes[1]}, skipped)
+	require.Contains(t, out.String(), "-- Statement 2 of 4:\n   -- create t2\n   CREATE TABLE t2 (id int)\n")
+	require.NotContains(t, out.String(), "-- Statement 4 of 4:")
+
+	answers = []string{answerSkipAll}
+	selected, skipped = selectChanges(cmd, changes)
+	require.Empty(t, selected)
+	require.Equal(t, changes, skipped)
+
+	// Interrupting the prompt aborts the selection.
+	answers = []string{answerApply}
+	selected, skipped = selectChanges(cmd, changes)
+	require.Empty(t, selected)
+	require.Equal(t, changes, skipped)
+}
+
+func TestSchema_InspectResume(t *testing.T) {
+	var (
+		db   = openSQLite(t, "create table t1 (id integer primary key);")
+		snap = filepath.Join(t.TempDir(), "snapshot.hcl")
+		run  = func() (string, error) {
+			cmd := schemaCmd()
+			cmd.AddCommand(schemaInspectCmd())
+			return runCmd(
+				cmd, "inspect",
+				"-u", db,
+				"--inspect-timeout", "1m",
+				"--retries", "2",
+				"--resume-from", snap,
+				"--format", "{{ json . }}",
+			)
+		}
+	)
+	s, err := run()
+	require.NoError(t, err)
+	require.Equal(t, `{"schemas":[{"id":"main","name":"main","tables":[{"id":"main.t1","name":"t1","columns":[{"id":"main.t1.id","name":"id","type":"INTEGER","null":true}],"primary_key":{"id":"main.t1#primary_key","parts":[{"column":"id"}]},"checksum":"f7bc0673dd5613872ffae9f68b1e227738217e6d8087bbda1feeece98a18f7ab"}]}]}`, s)
+	require.NoFileExists(t, snap, "snapshot is removed once the inspection is completed")
+
+	// Record a snapshot of an interrupted inspection.
+	c, err := sqlclient.Open(context.Background(), db)
+	require.NoError(t, err)
+	i := &chunkedInspect{client: c, flags: &inspectFlags{resumeFrom: snap}}
+	r, err := i.ReadState(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, i.writeSnapshot(r))
+	b, err := os.ReadFile(snap)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(b), inspectSnapshotHeader+`{"url":"sqlite://`))
+	require.Contains(t, string(b), `table "t1"`)
+
+	// Schemas recorded in the snapshot are not inspected again.
+	_, err = c.ExecContext(context.Background(), "create table t2 (name text)")
+	require.NoError(t, err)
+	s, err = run()
+	require.NoError(t, err)
+	require.NotContains(t, s, "t2")
+	require.NoFileExists(t, snap)
+	s, err = run()
+	require.NoError(t, err)
+	require.Contains(t, s, `"name":"t2"`)
+
+	// Snapshots of other targets are not resumed.
+	i.schemas = []string{"main"}
+	require.NoError(t, i.writeSnapshot(r))
+	_, err = run()
+	require.ErrorContains(t, err, `was recorded for url "sqlite://`)
+	require.ErrorContains(t, err, `and schemas ["main"], but the current inspection is of url`)
+	require.FileExists(t, snap)
+	require.NoError(t, os.WriteFile(snap, []byte(`schema "main" {}`), 0644))
+	_, err = run()
+	require.ErrorContains(t, err, "is missing its header")
+	require.NoError(t, c.Close())
+}
+
+func TestSchema_InspectRetryQuery(t *testing.T) {
+	prev := inspectRetryBackoff
+	inspectRetryBackoff = time.Millisecond
+	t.Cleanup(func() { inspectRetryBackoff = prev })
+	c, err := sqlclient.Open(context.Background(), openSQLite(t, "create table t1 (id int);"))
+	require.NoError(t, err)
+	defer c.Close()
+	f := &failingQuerier{ExecQuerier: c.DB, fails: 2}
+	q := &retryQuerier{ExecQuerier: f, flags: &inspectFlags{timeout: time.Minute, retries: 2}}
+	defer q.release()
+	rows, err := q.QueryContext(context.Background(), "SELECT name FROM sqlite_master")
+	require.NoError(t, err)
+	require.True(t, rows.Next())
+	require.NoError(t, rows.Close())
+	require.Equal(t, 3, f.calls, "query is retried until it succeeds")
+	require.Len(t, q.cancels, 1)
+
+	f.calls, f.fails = 0, 3
+	_, err = q.QueryContext(context.Background(), "SELECT name FROM sqlite_master")
+	require.EqualError(t, err, "query failed")
+	require.Equal(t, 3, f.calls, "query is executed at most 1+retries times")
+	q.release()
+	require.Empty(t, q.cancels)
+}
+
+// failingQuerier fails the first queries it executes.
+type failingQuerier struct {
+	schema.ExecQuerier
+	calls, fails int
+}
+
+func (q *failingQuerier) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	if q.calls++; q.calls <= q.fails {
+		return nil, errors.New("query failed")
+	}
+	return q.ExecQuerier.QueryContext(ctx, query, args...)
+}
+
+func TestSchema_Snapshot(t *testing.T) {
+	var (
+		db   = openSQLite(t, "create table t1 (id int);")
+		dir  = t.TempDir()
+		save = func(args ...string) (string, error) {
+			cmd := schemaCmd()
+			cmd.AddCommand(schemaSnapshotCmd())
+			return runCmd(cmd, append([]string{"snapshot", "save", "-u", db, "--snapshot-dir", dir}, args...)...)
+		}
+	)
+	s, err := save("--name", "2024-01-01T100000Z")
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("Snapshot %q was saved to %s\n", "2024-01-01T100000Z", filepath.Join(dir, "2024-01-01T100000Z.hcl")), s)
+	_, err = save("--name", "2024-01-01T100000Z")
+	require.EqualError(t, err, `snapshot "2024-01-01T100000Z" already exists`)
+
+	c, err := sqlclient.Open(context.Background(), db)
+	require.NoError(t, err)
+	_, err = c.ExecContext(context.Background(), "create table t2 (id int)")
+	require.NoError(t, err)
+	require.NoError(t, c.Close())
+	_, err = save("--name", "2024-01-02T100000Z")
+	require.NoError(t, err)
+
+	// Diff the database against its schema at a point in time.
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", "snapshot://2024-01-01?dir="+dir,
+		"--to", db,
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "-- Create \"t2\" table\nCREATE TABLE `t2` (`id` int NULL);\n", s)
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", "snapshot://latest?dir="+dir,
+		"--to", db,
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "Schemas are synced, no changes to be made.\n", s)
+
+	_, err = runCmd(
+		schemaDiffCmd(),
+		"--from", "snapshot://2023?dir="+dir,
+		"--to", db,
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.EqualError(t, err, fmt.Sprintf("snapshot %q was not found in %q", "2023", dir))
+}
+
+func TestSchema_InspectConcurrency(t *testing.T) {
+	db := openSQLite(t, "create table t1 (id integer primary key);")
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaInspectCmd())
+	s, err := runCmd(cmd, "inspect", "-u", db, "--concurrency", "4", "--format", "{{ sql . }}")
+	require.NoError(t, err)
+	require.Contains(t, s, "CREATE TABLE `t1`")
+
+	f := &inspectFlags{concurrency: 4}
+	require.False(t, f.set(), "concurrency does not enable chunked inspection")
+	require.Equal(t, 4, f.concurrent())
+	require.Zero(t, (*inspectFlags)(nil).concurrent())
+}
+
+func TestSchema_InspectCodegen(t *testing.T) {
+	var (
+		tmpl = t.TempDir()
+		out  = filepath.Join(t.TempDir(), "models")
+	)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpl, "go.tmpl"), []byte(`type {{ pascal (singular .Name) }} struct {
+{{- range .Columns }}
+	{{ pascal .Name }} {{ if eq (kind .) "int" }}int{{ else }}string{{ end }}
+{{- end }}
+}
+`), 0600))
+	s, err := runCmd(
+		schemaInspectCmd(),
+		"-u", openSQLite(t, "create table users(id int, name text)"),
+		"--codegen", fmt.Sprintf("template-dir://%s?out=%s", tmpl, out),
+	)
+	require.NoError(t, err)
+	require.Empty(t, s)
+	b, err := os.ReadFile(filepath.Join(out, "users.go"))
+	require.NoError(t, err)
+	require.Equal(t, "type User struct {\n\tId int\n\tName string\n}\n", string(b))
+
+	_, err = runCmd(
+		schemaInspectCmd(),
+		"-u", openSQLite(t, ""),
+		"--codegen", "mysql://"+tmpl,
+	)
+	require.EqualError(t, err, `unsupported codegen url scheme "mysql". Expect one of: file or template-dir`)
+}
+
+func TestSchema_InspectCSV(t *testing.T) {
+	u := openSQLite(t, `
+create table users (id int primary key, name text not null default 'a8m', bio text);
+create unique index users_name on users (name);
+create table posts (id int primary key, author_id int references users(id) on delete cascade);
+`)
+	s, err := runCmd(schemaInspectCmd(), "-u", u, "--format", "csv")
+	require.NoError(t, err)
+	require.Equal(t, `schema,table,column,type,nullable,default,comment
+main,users,id,INT,true,,
+main,users,name,TEXT,false,'a8m',
+main,users,bio,TEXT,true,,
+main,posts,id,INT,true,,
+main,posts,author_id,INT,true,,
+`, s)
+
+	s, err = runCmd(schemaInspectCmd(), "-u", u, "--format", "tsv", "--facet", "indexes")
+	require.NoError(t, err)
+	require.Equal(t, "schema\ttable\tindex\tprimary\tunique\tparts\tcomment\nmain\tusers\tPRIMARY\ttrue\ttrue\tid\t\nmain\tusers\tusers_name\tfalse\ttrue\tname\t\nmain\tposts\tPRIMARY\ttrue\ttrue\tid\t\n", s)
+
+	s, err = runCmd(schemaInspectCmd(), "-u", u, "--format", "csv", "--facet", "fks")
+	require.NoError(t, err)
+	require.Equal(t, "schema,table,constraint,columns,ref_schema,ref_table,ref_columns,on_update,on_delete\nmain,posts,0,author_id,main,users,id,NO ACTION,CASCADE\n", s)
+
+	_, err = runCmd(schemaInspectCmd(), "-u", u, "--format", "csv", "--facet", "views")
+	require.EqualError(t, err, `unknown facet "views". Expect one of: tables, columns, indexes or fks`)
+	_, err = runCmd(schemaInspectCmd(), "-u", u, "--facet", "tables")
+	require.EqualError(t, err, "--facet can only be used with --format csv or --format tsv")
+}
+
+func TestSchema_InspectDiagram(t *testing.T) {
+	u := openSQLite(t, `
+create table users (id int primary key, name text not null);
+create table posts (id int primary key, author_id int references users(id));
+`)
+	s, err := runCmd(schemaInspectCmd(), "-u", u, "--format", "mermaid")
+	require.NoError(t, err)
+	require.Equal(t, `erDiagram
+    users {
+      int id PK
+      text name
+    }
+    posts {
+      int id PK
+      int author_id FK
+    }
+    posts }o--o| users : 0
+`, s)
+
+	s, err = runCmd(schemaInspectCmd(), "-u", u, "--format", "dot")
+	require.NoError(t, err)
+	require.Equal(t, `digraph schema {
+  rankdir=LR;
+  node [shape=plaintext];
+  n0 [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey"><b>users</b></td></tr><tr><td port="p0" align="left">id INT PK</td></tr><tr><td port="p1" align="left">name TEXT</td></tr></table>>];
+  n1 [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey"><b>posts</b></td></tr><tr><td port="p0" align="left">id INT PK</td></tr><tr><td port="p1" align="left">author_id INT FK</td></tr></table>>];
+  n1:p1 -> n0:p0 [label="0"];
+}
+`, s)
+}
+
+func TestSchema_InspectAnonymize(t *testing.T) {
+	s, err := runCmd(
+		schemaInspectCmd(),
+		"-u", openSQLite(t, "create table users (id int primary key, email text default 'a8m@atlasgo.io'); create table posts (id int, author_id int references users(id));"),
+		"--anonymize",
+	)
+	require.NoError(t, err)
+	require.NotContains(t, s, "users")
+	require.NotContains(t, s, "a8m")
+	require.Contains(t, s, `table "table1"`)
+	require.Contains(t, s, `ref_columns = [table.table1.column.column1]`)
 }
 
 func TestSchema_InspectFile(t *testing.T) {
@@ -1023,6 +1955,145 @@ table "users" {
 	require.Equal(t, "-- Create \"users\" table\nCREATE TABLE `users` (`id` int NOT NULL);\n", s)
 }
 
+func TestSchema_Labels(t *testing.T) {
+	var (
+		p  = t.TempDir()
+		sp = filepath.Join(p, "schema.hcl")
+	)
+	require.NoError(t, os.WriteFile(sp, []byte(`
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "email" {
+    type = text
+    labels = {
+      pii = "true"
+    }
+  }
+}
+table "posts" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  labels = {
+    owner = "content"
+  }
+}
+`), 0600))
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaInspectCmd())
+	s, err := runCmd(
+		cmd, "inspect",
+		"--url", "file://"+sp,
+		"--dev-url", openSQLite(t, ""),
+		"--label", "pii=true",
+		"--format", "{{ sql . }}",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "-- Create \"users\" table\nCREATE TABLE `users` (`id` int NOT NULL, `email` text NOT NULL);\n", s)
+
+	s, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, ""),
+		"--to", "file://"+sp,
+		"--dev-url", openSQLite(t, ""),
+		"--label", "owner",
+	)
+	require.NoError(t, err)
+	require.Equal(t, "-- Create \"posts\" table\nCREATE TABLE `posts` (`id` int NOT NULL);\n", s)
+
+	_, err = runCmd(
+		schemaDiffCmd(),
+		"--from", openSQLite(t, ""),
+		"--to", "file://"+sp,
+		"--dev-url", openSQLite(t, ""),
+		"--label", "=true",
+	)
+	require.EqualError(t, err, `invalid label "=true": expect key or key=value`)
+}
+
+func TestSchema_Lint(t *testing.T) {
+	var (
+		p   = t.TempDir()
+		cp  = filepath.Join(p, "atlas.hcl")
+		sp  = filepath.Join(p, "schema.hcl")
+		cfg = fmt.Sprintf(`
+env "local" {
+  src = "file://%s"
+  dev = "%s"
+  lint {
+    design {
+      error       = true
+      varchar_max = 255
+    }
+  }
+}
+`, sp, openSQLite(t, ""))
+	)
+	require.NoError(t, os.WriteFile(cp, []byte(cfg), 0600))
+	require.NoError(t, os.WriteFile(sp, []byte(`
+schema "main" {}
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "bio" {
+    type = varchar(1024)
+  }
+  primary_key {
+    columns = [column.id]
+  }
+}
+table "pets" {
+  schema = schema.main
+  column "owner_id" {
+    type = int
+  }
+  foreign_key "owner" {
+    columns     = [column.owner_id]
+    ref_columns = [table.users.column.id]
+  }
+}
+`), 0600))
+	cmd := schemaCmd()
+	cmd.AddCommand(schemaLintCmd())
+	s, err := runCmd(cmd, "lint", "-c", "file://"+cp, "--env", "local")
+	require.EqualError(t, err, "schema design issues detected")
+	require.Equal(t, `schema design issues detected
+  -- SD104: Column "bio" of table "users" has a size of 1024, which exceeds the maximum of 255
+  -- SD101: Table "pets" has no primary key
+  -- SD102: Foreign key "owner" of table "pets" is not covered by an index on column(s) owner_id
+Error: schema design issues detected
+`, s)
+
+	// Clean schema.
+	s, err = runCmd(
+		schemaLintCmd(),
+		"--url", openSQLite(t, "create table t1 (id int primary key);"),
+		"--dev-url", openSQLite(t, ""),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "No issues were found in the schema.\n", s)
+
+	// JSON output.
+	s, err = runCmd(
+		schemaLintCmd(),
+		"--url", openSQLite(t, "create table t1 (id int);"),
+		"--dev-url", openSQLite(t, ""),
+		"--format", "{{ json .Reports }}",
+	)
+	require.NoError(t, err)
+	var reports []sqlcheck.Report
+	require.NoError(t, json.Unmarshal([]byte(s), &reports))
+	require.Len(t, reports, 1)
+	require.Equal(t, "SD101", reports[0].Diagnostics[0].Code)
+}
+
 func TestFmt(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
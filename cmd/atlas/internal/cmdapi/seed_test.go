@@ -0,0 +1,91 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Seed(t *testing.T) {
+	var (
+		p  = t.TempDir()
+		db = openSQLite(t, `
+create table users (id int primary key, name text not null, bio text);
+create table posts (id int primary key, author_id int not null references users(id), title text not null);
+`)
+	)
+	// Posts are read first, but inserted after the users they reference.
+	require.NoError(t, os.WriteFile(filepath.Join(p, "posts.csv"), []byte("id,author_id,title\n1,1,hello\n2,2,world\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "users.hcl"), []byte(`
+table "users" {
+  row {
+    id   = 1
+    name = "a8m"
+    bio  = null
+  }
+  row {
+    id   = 2
+    name = "rotemtam"
+  }
+}
+`), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "zz.sql"), []byte("UPDATE users SET bio = 'atlas' WHERE id = 2;\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(p, "README.md"), []byte("ignored"), 0600))
+
+	s, err := runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+p)
+	require.NoError(t, err)
+	require.Equal(t, "Seeded 2 rows into table \"users\"\nSeeded 2 rows into table \"posts\"\nExecuted 1 statements of file \"zz.sql\"\n", s)
+	requireSeeded(t, db, "SELECT id || ',' || author_id || ',' || title FROM posts ORDER BY id", "1,1,hello", "2,2,world")
+	requireSeeded(t, db, "SELECT id || ',' || name || ',' || coalesce(bio, 'NULL') FROM users ORDER BY id", "1,a8m,NULL", "2,rotemtam,atlas")
+
+	// Seeding again fails on the primary keys, and the transaction is rolled back.
+	_, err = runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+filepath.Join(p, "users.hcl"))
+	require.ErrorContains(t, err, `inserting row 1 of table "users"`)
+
+	// Upsert updates the conflicting rows.
+	up := filepath.Join(t.TempDir(), "users.csv")
+	require.NoError(t, os.WriteFile(up, []byte("id,name,bio\n2,rotem,\n3,masseg,dev\n"), 0600))
+	_, err = runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+up, "--upsert")
+	require.NoError(t, err)
+	requireSeeded(t, db, "SELECT id || ',' || name || ',' || coalesce(bio, 'NULL') FROM users ORDER BY id", "1,a8m,NULL", "2,rotem,NULL", "3,masseg,dev")
+
+	// Truncate deletes the rows of the seeded tables in reverse order.
+	_, err = runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+filepath.Join(p, "users.hcl"), "--from", "file://"+filepath.Join(p, "posts.csv"), "--truncate")
+	require.NoError(t, err)
+	requireSeeded(t, db, "SELECT id || ',' || name || ',' || coalesce(bio, 'NULL') FROM users ORDER BY id", "1,a8m,NULL", "2,rotemtam,NULL")
+
+	p = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(p, "comments.csv"), []byte("id\n1\n"), 0600))
+	_, err = runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+filepath.Join(p, "comments.csv"))
+	require.EqualError(t, err, `seeded table "comments" was not found in the database`)
+	require.NoError(t, os.WriteFile(filepath.Join(p, "users.csv"), []byte("id,email\n1,a@b.c\n"), 0600))
+	_, err = runCmd(schemaSeedCmd(), "-u", db, "--from", "file://"+filepath.Join(p, "users.csv"))
+	require.EqualError(t, err, `column "email" was not found in table "users"`)
+}
+
+func requireSeeded(t *testing.T, u, query string, rows ...string) {
+	t.Helper()
+	c, err := sqlclient.Open(context.Background(), u)
+	require.NoError(t, err)
+	defer c.Close()
+	rs, err := c.QueryContext(context.Background(), query)
+	require.NoError(t, err)
+	defer rs.Close()
+	var got []string
+	for rs.Next() {
+		var s string
+		require.NoError(t, rs.Scan(&s))
+		got = append(got, s)
+	}
+	require.NoError(t, rs.Err())
+	require.Equal(t, rows, got)
+}
@@ -0,0 +1,2 @@
+-- Add audit columns to the "{{ .table }}" table.
+ALTER TABLE `{{ .table }}` ADD COLUMN `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, ADD COLUMN `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP;
@@ -0,0 +1,3 @@
+-- Add soft-delete support to the "{{ .table }}" table.
+ALTER TABLE `{{ .table }}` ADD COLUMN `deleted_at` timestamp NULL;
+CREATE INDEX `{{ .table }}_deleted_at` ON `{{ .table }}` (`deleted_at`);
@@ -0,0 +1,3 @@
+-- Keep the "{{ .column }}" column of the "{{ .table }}" table in sync on inserts and updates.
+CREATE TRIGGER `{{ .table }}_{{ .column }}_insert` BEFORE INSERT ON `{{ .table }}` FOR EACH ROW SET NEW.`{{ .column }}` = {{ .expr }};
+CREATE TRIGGER `{{ .table }}_{{ .column }}_update` BEFORE UPDATE ON `{{ .table }}` FOR EACH ROW SET NEW.`{{ .column }}` = {{ .expr }};
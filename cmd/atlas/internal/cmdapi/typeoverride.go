@@ -0,0 +1,125 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// TypeOverride defines how columns of a custom or unknown type (e.g., a domain
+// or a type created by an extension) are compared when diffing schemas. Type
+// overrides are defined in the diff policy, and apply to both sides of the diff.
+// For example, in order to compare citext columns as text, and ignore their
+// type modifiers:
+//
+//	diff {
+//	  type_override "citext" {
+//	    as   = "text"
+//	    diff = "ignore_modifiers"
+//	  }
+//	}
+type TypeOverride struct {
+	// Name of the type, without its modifiers (e.g., "citext").
+	Name string `spec:"name,name"`
+	// As defines the type the overridden type is compared as.
+	// If empty, the type is compared by its name.
+	As string `spec:"as"`
+	// Diff defines the comparison mode. One of: "exact" (default)
+	// compares the type modifiers, and "ignore_modifiers" ignores them.
+	Diff string `spec:"diff"`
+}
+
+// Type override comparison modes.
+const (
+	TypeDiffExact           = "exact"
+	TypeDiffIgnoreModifiers = "ignore_modifiers"
+)
+
+// TypeOverrides returns the type overrides defined in the diff policy of the environment.
+func (e *Env) TypeOverrides() []*TypeOverride {
+	if e == nil || e.Diff == nil {
+		return nil
+	}
+	return e.Diff.TypeOverrides
+}
+
+// overrideTypes removes the type changes of the modified columns, whose types are
+// equal after applying the type overrides. Columns that have no other changes
+// besides their type are removed, and so are tables left with no changes.
+func overrideTypes(changes []schema.Change, f schema.TypeFormatter, overrides []*TypeOverride) ([]schema.Change, error) {
+	if len(overrides) == 0 {
+		return changes, nil
+	}
+	byName := make(map[string]*TypeOverride, len(overrides))
+	for _, o := range overrides {
+		switch o.Diff {
+		case "", TypeDiffExact, TypeDiffIgnoreModifiers:
+		default:
+			return nil, fmt.Errorf("type_override %q: unknown diff mode %q. Expect one of: %s or %s", o.Name, o.Diff, TypeDiffExact, TypeDiffIgnoreModifiers)
+		}
+		byName[strings.ToLower(o.Name)] = o
+	}
+	filtered := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		m, ok := c.(*schema.ModifyTable)
+		if !ok {
+			filtered = append(filtered, c)
+			continue
+		}
+		tc := make([]schema.Change, 0, len(m.Changes))
+		for _, c := range m.Changes {
+			if mc, ok := c.(*schema.ModifyColumn); ok && mc.Change.Is(schema.ChangeType) &&
+				overriddenType(mc.From.Type, f, byName) == overriddenType(mc.To.Type, f, byName) {
+				if mc.Change &^= schema.ChangeType; mc.Change == schema.NoChange {
+					continue
+				}
+			}
+			tc = append(tc, c)
+		}
+		if m.Changes = tc; len(m.Changes) > 0 {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered, nil
+}
+
+// overriddenType returns the comparable representation of the
+// column type, after applying the type override that matches it.
+func overriddenType(t *schema.ColumnType, f schema.TypeFormatter, overrides map[string]*TypeOverride) string {
+	if t == nil {
+		return ""
+	}
+	var raw string
+	switch u := t.Type.(type) {
+	case *schema.UnsupportedType:
+		raw = u.T
+	default:
+		if f != nil {
+			raw, _ = f.FormatType(t.Type)
+		}
+		if raw == "" {
+			raw = t.Raw
+		}
+	}
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	name, mods := raw, ""
+	if i := strings.IndexByte(raw, '('); i != -1 {
+		name, mods = strings.TrimSpace(raw[:i]), raw[i:]
+	}
+	o, ok := overrides[name]
+	if !ok {
+		return raw
+	}
+	if o.As != "" {
+		name = strings.ToLower(o.As)
+	}
+	if o.Diff == TypeDiffIgnoreModifiers {
+		mods = ""
+	}
+	return name + mods
+}
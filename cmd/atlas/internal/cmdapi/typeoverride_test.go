@@ -0,0 +1,120 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrideTypes(t *testing.T) {
+	var (
+		unknown = func(s string) *schema.ColumnType {
+			return &schema.ColumnType{Type: &schema.UnsupportedType{T: s}, Raw: s}
+		}
+		text    = &schema.ColumnType{Type: &schema.StringType{T: "text"}, Raw: "text"}
+		changes = func() []schema.Change {
+			return []schema.Change{
+				&schema.ModifyTable{
+					T: schema.NewTable("users"),
+					Changes: []schema.Change{
+						// Type change only.
+						&schema.ModifyColumn{
+							From:   schema.NewColumn("a").SetType(&schema.UnsupportedType{T: "citext(10)"}),
+							To:     schema.NewColumn("a").SetType(&schema.UnsupportedType{T: "citext(20)"}),
+							Change: schema.ChangeType,
+						},
+						// Type and nullability change.
+						&schema.ModifyColumn{
+							From:   &schema.Column{Name: "b", Type: unknown("citext")},
+							To:     &schema.Column{Name: "b", Type: text},
+							Change: schema.ChangeType | schema.ChangeNull,
+						},
+					},
+				},
+				&schema.AddTable{T: schema.NewTable("posts")},
+			}
+		}
+	)
+	// No overrides.
+	got, err := overrideTypes(changes(), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Len(t, got[0].(*schema.ModifyTable).Changes, 2)
+
+	// Modifiers are compared by default.
+	got, err = overrideTypes(changes(), nil, []*TypeOverride{{Name: "CITEXT"}})
+	require.NoError(t, err)
+	require.Len(t, got[0].(*schema.ModifyTable).Changes, 2)
+
+	got, err = overrideTypes(changes(), nil, []*TypeOverride{{Name: "citext", Diff: TypeDiffIgnoreModifiers}})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	m := got[0].(*schema.ModifyTable)
+	require.Len(t, m.Changes, 1)
+	require.Equal(t, "b", m.Changes[0].(*schema.ModifyColumn).To.Name)
+	require.Equal(t, schema.ChangeType|schema.ChangeNull, m.Changes[0].(*schema.ModifyColumn).Change)
+
+	// Types are compared as the overriding type.
+	got, err = overrideTypes(changes(), nil, []*TypeOverride{{Name: "citext", As: "text", Diff: TypeDiffIgnoreModifiers}})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	m = got[0].(*schema.ModifyTable)
+	require.Len(t, m.Changes, 1)
+	require.Equal(t, schema.ChangeNull, m.Changes[0].(*schema.ModifyColumn).Change)
+
+	// Tables with no changes left are dropped.
+	got, err = overrideTypes(changes()[:1], nil, []*TypeOverride{{Name: "citext", As: "text", Diff: TypeDiffIgnoreModifiers}})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	got[0].(*schema.ModifyTable).Changes[0].(*schema.ModifyColumn).Change = schema.ChangeType
+	got, err = overrideTypes(got, nil, []*TypeOverride{{Name: "citext", As: "text"}})
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	_, err = overrideTypes(changes(), nil, []*TypeOverride{{Name: "citext", Diff: "unknown"}})
+	require.EqualError(t, err, `type_override "citext": unknown diff mode "unknown". Expect one of: exact or ignore_modifiers`)
+
+	// Environments inherit the global overrides.
+	d := (&Diff{TypeOverrides: []*TypeOverride{{Name: "citext", As: "text"}}}).Extend(&Diff{
+		TypeOverrides: []*TypeOverride{{Name: "CITEXT"}, {Name: "ltree", Diff: TypeDiffIgnoreModifiers}},
+	})
+	require.Len(t, d.TypeOverrides, 2)
+	require.Equal(t, "text", d.TypeOverrides[0].As)
+	require.Equal(t, "ltree", d.TypeOverrides[1].Name)
+}
+
+func TestEnvByName_TypeOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "atlas.hcl")
+	require.NoError(t, os.WriteFile(path, []byte(`
+diff {
+  type_override "ltree" {
+    diff = "ignore_modifiers"
+  }
+}
+env "local" {
+  diff {
+    type_override "citext" {
+      as   = "text"
+      diff = "ignore_modifiers"
+    }
+  }
+}
+`), 0600))
+	GlobalFlags.ConfigURL = "file://" + path
+	_, envs, err := EnvByName(&cobra.Command{}, "local", nil)
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	require.Equal(t, []*TypeOverride{
+		{Name: "citext", As: "text", Diff: TypeDiffIgnoreModifiers},
+		{Name: "ltree", Diff: TypeDiffIgnoreModifiers},
+	}, envs[0].TypeOverrides())
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package vercheck
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// Satisfies reports if the version satisfies the given constraints. The constraints are
+// a comma-separated list of conditions, all of which must be met. Conditions use the
+// Terraform syntax, and the supported operators are: =, !=, >, >=, <, <= and ~> (allows
+// only the rightmost version component to increment). For example:
+//
+//	">= 0.30.0, < 1.0.0"
+//	"~> 0.30"    // >= 0.30.0, < 1.0.0
+//	"~> 0.30.1"  // >= 0.30.1, < 0.31.0
+func Satisfies(ver, constraints string) (bool, error) {
+	if !semver.IsValid(ver) {
+		return false, fmt.Errorf("invalid version %q", ver)
+	}
+	for _, c := range strings.Split(constraints, ",") {
+		op, v, err := parseConstraint(c)
+		if err != nil {
+			return false, err
+		}
+		var ok bool
+		switch cmp := semver.Compare(ver, v); op {
+		case "=":
+			ok = cmp == 0
+		case "!=":
+			ok = cmp != 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		case "~>":
+			b := pessimisticBound(v)
+			ok = cmp >= 0 && (b == "" || semver.Compare(ver, b) < 0)
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// parseConstraint parses a single constraint into its operator and
+// canonical version. A missing operator defaults to equality.
+func parseConstraint(c string) (string, string, error) {
+	c = strings.TrimSpace(c)
+	op := "="
+	for _, o := range []string{"~>", ">=", "<=", "!=", ">", "<", "="} {
+		if strings.HasPrefix(c, o) {
+			op, c = o, strings.TrimSpace(c[len(o):])
+			break
+		}
+	}
+	v := c
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return "", "", fmt.Errorf("invalid version constraint %q", c)
+	}
+	// The ~> operator relies on the number of the given components.
+	if op == "~>" {
+		return op, v, nil
+	}
+	return op, semver.Canonical(v), nil
+}
+
+// pessimisticBound returns the exclusive upper bound of the ~> operator. For example,
+// "v0.30" returns "v1.0.0" and "v0.30.1" returns "v0.31.0". An empty string is returned
+// for versions with a single component, as they allow any later version.
+func pessimisticBound(v string) string {
+	core := strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(core, "-+"); i != -1 {
+		core = core[:i]
+	}
+	parts := strings.Split(core, ".")
+	if len(parts) < 2 {
+		return ""
+	}
+	i := len(parts) - 2
+	x, _ := strconv.Atoi(parts[i])
+	parts = append(parts[:i], strconv.Itoa(x+1))
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return "v" + strings.Join(parts, ".")
+}
@@ -12,14 +12,32 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"text/template"
 	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/cmdstate"
 )
 
-// StateFileName is the name of the file where the vercheck state is stored.
-const StateFileName = "release.json"
+const (
+	// StateFileName is the name of the file where the vercheck state is stored.
+	StateFileName = "release.json"
+	// DefaultURL is the default endpoint of the vercheck service.
+	DefaultURL = "https://vercheck.ariga.io"
+	// EnvURL is the environment variable for replacing the vercheck endpoint,
+	// e.g., with an internal mirror in air-gapped environments. Mirrors are
+	// expected to serve the same paths as the vercheck service.
+	EnvURL = "ATLAS_VERCHECK_URL"
+)
+
+// URL returns the endpoint of the vercheck service. The
+// EnvURL variable takes precedence over the default one.
+func URL() string {
+	if u := os.Getenv(EnvURL); u != "" {
+		return u
+	}
+	return DefaultURL
+}
 
 // New returns a new VerChecker for the endpoint.
 func New(endpoint string) *VerChecker {
@@ -77,6 +95,13 @@ func (v *VerChecker) Check(ctx context.Context, ver string) (*Payload, error) {
 	if err := v.verifyTime(); err != nil {
 		return nil, err
 	}
+	return v.CheckNow(ctx, ver)
+}
+
+// CheckNow is like Check, but it queries the service regardless
+// of the last time it was run. It is used by explicit checks,
+// e.g., 'atlas version --check'.
+func (v *VerChecker) CheckNow(ctx context.Context, ver string) (*Payload, error) {
 	endpoint, err := url.JoinPath(v.endpoint, "atlas", ver)
 	if err != nil {
 		return nil, err
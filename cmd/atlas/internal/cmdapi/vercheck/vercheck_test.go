@@ -183,3 +183,56 @@ This version contains a vulnerability, please upgrade.`,
 		})
 	}
 }
+
+func TestSatisfies(t *testing.T) {
+	for _, tt := range []struct {
+		ver, constraints string
+		want             bool
+	}{
+		{"v0.30.0", "0.30.0", true},
+		{"v0.30.0", "= v0.30.0", true},
+		{"v0.30.1", "0.30.0", false},
+		{"v0.30.1", "!= 0.30.0", true},
+		{"v0.30.1", ">= 0.30.0, < 1.0.0", true},
+		{"v1.0.0", ">= 0.30.0, < 1.0.0", false},
+		{"v0.29.9", "> 0.29.9", false},
+		{"v0.29.9", "<= 0.29.9", true},
+		{"v0.31.2", "~> 0.30", true},
+		{"v1.0.0", "~> 0.30", false},
+		{"v0.30.5", "~> 0.30.1", true},
+		{"v0.31.0", "~> 0.30.1", false},
+		{"v0.30.0", "~> 0.30.1", false},
+		{"v2.1.0", "~> 1", true},
+	} {
+		got, err := Satisfies(tt.ver, tt.constraints)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got, "%s %s", tt.ver, tt.constraints)
+	}
+	_, err := Satisfies("v0.30.0", ">= x.y")
+	require.EqualError(t, err, `invalid version constraint "x.y"`)
+	_, err = Satisfies("development", ">= 0.30")
+	require.EqualError(t, err, `invalid version "development"`)
+}
+
+func TestVerCheck_CheckNow(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"latest":null,"advisory":{"text":"upgrade"}}`))
+	}))
+	defer srv.Close()
+	cmdstate.TestingHome(t)
+	t.Setenv(EnvURL, srv.URL)
+	require.Equal(t, srv.URL, URL())
+	vc := New(URL())
+	_, err := vc.Check(context.Background(), "v0.1.2")
+	require.NoError(t, err)
+	// Skipped, as 24 hours have not passed.
+	_, err = vc.Check(context.Background(), "v0.1.2")
+	require.ErrorIs(t, err, errSkip)
+	// Explicit checks are not skipped.
+	p, err := vc.CheckNow(context.Background(), "v0.1.2")
+	require.NoError(t, err)
+	require.Equal(t, &Advisory{Text: "upgrade"}, p.Advisory)
+	require.Equal(t, 2, calls)
+}
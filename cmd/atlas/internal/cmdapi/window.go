@@ -0,0 +1,314 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/condrop"
+	"ariga.io/atlas/sql/sqlcheck/destructive"
+	"ariga.io/atlas/sql/sqlcheck/narrowing"
+
+	"github.com/spf13/cobra"
+)
+
+// ApplyWindow describes a maintenance window in which destructive
+// changes are allowed to be applied on the environment database.
+// For example, every weekday at 22:00 (UTC) for 2 hours:
+//
+//	apply_window {
+//	  schedule = "0 22 * * 1-5"
+//	  duration = "2h"
+//	}
+type ApplyWindow struct {
+	// Schedule is a cron expression in the standard 5-fields format
+	// (minute, hour, day of month, month, day of week), describing
+	// when the window opens.
+	Schedule string `spec:"schedule"`
+	// Duration defines how long the window stays open (e.g., "30m", "2h").
+	Duration string `spec:"duration"`
+	// Timezone is the IANA name of the location the schedule
+	// is evaluated in (e.g., "Europe/Berlin"). Defaults to UTC.
+	Timezone string `spec:"timezone"`
+}
+
+// timeNow returns the current time. Overridden in tests.
+var timeNow = time.Now
+
+// maxWindowLookahead bounds the search for the next opening of a window.
+const maxWindowLookahead = 366 * 24 * time.Hour
+
+// Open reports if the window is open at the given time. If it is closed,
+// the time it opens next is returned. A zero time is returned in case the
+// window does not open in the upcoming year.
+func (w *ApplyWindow) Open(now time.Time) (bool, time.Time, error) {
+	c, d, loc, err := w.parse()
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	now = now.In(loc)
+	// The window is open if it was started in the last duration.
+	for t := now.Truncate(time.Minute); now.Sub(t) < d; t = t.Add(-time.Minute) {
+		if c.match(t) {
+			return true, time.Time{}, nil
+		}
+	}
+	for t := now.Truncate(time.Minute).Add(time.Minute); t.Sub(now) < maxWindowLookahead; t = t.Add(time.Minute) {
+		if c.match(t) {
+			return false, t, nil
+		}
+	}
+	return false, time.Time{}, nil
+}
+
+// parse parses the window attributes.
+func (w *ApplyWindow) parse() (*cronSchedule, time.Duration, *time.Location, error) {
+	c, err := parseCron(w.Schedule)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("apply_window: invalid schedule %q: %w", w.Schedule, err)
+	}
+	d, err := time.ParseDuration(w.Duration)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("apply_window: invalid duration %q: %w", w.Duration, err)
+	}
+	if d < time.Minute {
+		return nil, 0, nil, fmt.Errorf("apply_window: duration %q must be at least one minute", w.Duration)
+	}
+	loc := time.UTC
+	if w.Timezone != "" {
+		if loc, err = time.LoadLocation(w.Timezone); err != nil {
+			return nil, 0, nil, fmt.Errorf("apply_window: invalid timezone %q: %w", w.Timezone, err)
+		}
+	}
+	return c, d, loc, nil
+}
+
+// applyWindowOpen reports if one of the apply windows of the environment
+// is open at the given time. If all windows are closed, the earliest time
+// one of them opens is returned. Environments without windows are always open.
+func (e *Env) applyWindowOpen(now time.Time) (bool, time.Time, error) {
+	if e == nil || len(e.ApplyWindows) == 0 {
+		return true, time.Time{}, nil
+	}
+	var next time.Time
+	for _, w := range e.ApplyWindows {
+		open, t, err := w.Open(now)
+		switch {
+		case err != nil:
+			return false, time.Time{}, err
+		case open:
+			return true, time.Time{}, nil
+		case !t.IsZero() && (next.IsZero() || t.Before(next)):
+			next = t
+		}
+	}
+	return false, next, nil
+}
+
+// waitApplyWindow checks that one of the apply windows of the environment is open,
+// or waits up to the given duration for it to open. It reports if it had to wait.
+func waitApplyWindow(cmd *cobra.Command, env *Env, wait time.Duration) (bool, error) {
+	now := timeNow()
+	open, next, err := env.applyWindowOpen(now)
+	switch {
+	case err != nil:
+		return false, err
+	case open:
+		return false, nil
+	case next.IsZero():
+		return false, fmt.Errorf("destructive changes cannot be applied outside the apply window of env %q, and no window opens in the upcoming year", env.Name)
+	case next.Sub(now) > wait:
+		return false, fmt.Errorf("destructive changes cannot be applied outside the apply window of env %q. The next window opens at %s, use --wait to wait for it", env.Name, next.Format(time.RFC3339))
+	}
+	cmd.Printf("Waiting for the apply window to open at %s\n", next.Format(time.RFC3339))
+	select {
+	case <-cmd.Context().Done():
+		return false, cmd.Context().Err()
+	case <-time.After(next.Sub(now)):
+		return true, nil
+	}
+}
+
+// destructiveChanges reports if the given changes may cause data loss. The changes are
+// classified by the lint analyzers that detect destructive changes, dropped constraints
+// and narrowed column types, configured by the lint block of the environment. Dropped
+// indexes, which these analyzers do not report, are considered destructive as well.
+func destructiveChanges(ctx context.Context, env *Env, changes []schema.Change) (bool, error) {
+	ds, err := destructive.New(env.Lint.Remain())
+	if err != nil {
+		return false, err
+	}
+	cd, err := condrop.New(env.Lint.Remain())
+	if err != nil {
+		return false, err
+	}
+	nr, err := narrowing.New(env.Lint.Remain())
+	if err != nil {
+		return false, err
+	}
+	var (
+		reported bool
+		f        = &sqlcheck.File{}
+		pass     = &sqlcheck.Pass{
+			File: f,
+			Reporter: sqlcheck.ReportWriterFunc(func(sqlcheck.Report) {
+				reported = true
+			}),
+		}
+	)
+	for _, c := range changes {
+		f.Changes = append(f.Changes, &sqlcheck.Change{Changes: schema.Changes{c}, Stmt: &migrate.Stmt{}})
+	}
+	for _, az := range []sqlcheck.Analyzer{ds, cd, nr, sqlcheck.AnalyzerFunc(dropIndex)} {
+		// Analyzers configured to fail on
+		// diagnostics return an error after reporting.
+		if err := az.Analyze(ctx, pass); err != nil && !reported {
+			return false, err
+		}
+		if reported {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// dropIndex reports indexes that are dropped from existing tables.
+func dropIndex(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			if !ok {
+				continue
+			}
+			for _, c := range m.Changes {
+				if d, ok := c.(*schema.DropIndex); ok && p.File.IndexSpan(m.T, d.I)&sqlcheck.SpanAdded == 0 {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Pos:  sc.Stmt.Pos,
+						Text: fmt.Sprintf("Dropping index %q on table %q", d.I.Name, m.T.Name),
+					})
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "index deletion detected", Diagnostics: diags})
+	}
+	return nil
+}
+
+// cronSchedule is a parsed cron expression. Each field holds
+// the bitset of the values matched by the expression.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	// Reports if the day fields were restricted (not "*").
+	domSet, dowSet bool
+}
+
+// cronFields describes the bounds of the cron expression fields.
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// parseCron parses a standard 5-fields cron expression. Each field may hold
+// "*", a value, a range ("1-5"), a step ("*/15", "0-30/10") or a list of them.
+func parseCron(s string) (*cronSchedule, error) {
+	parts := strings.Fields(s)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("expected %d fields, got %d", len(cronFields), len(parts))
+	}
+	var bits [5]uint64
+	for i, p := range parts {
+		for _, r := range strings.Split(p, ",") {
+			b, err := parseCronRange(r, cronFields[i].min, cronFields[i].max)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", cronFields[i].name, err)
+			}
+			bits[i] |= b
+		}
+	}
+	// Sunday can be written both as 0 and 7.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domSet: parts[2] != "*",
+		dowSet: parts[4] != "*",
+	}, nil
+}
+
+// parseCronRange parses a single range of a cron field.
+func parseCronRange(r string, lower, upper int) (uint64, error) {
+	var (
+		err      error
+		step     = 1
+		min, max = lower, upper
+	)
+	if i := strings.IndexByte(r, '/'); i != -1 {
+		if step, err = strconv.Atoi(r[i+1:]); err != nil || step <= 0 {
+			return 0, fmt.Errorf("invalid step %q", r[i+1:])
+		}
+		r = r[:i]
+	}
+	switch i := strings.IndexByte(r, '-'); {
+	case r == "*":
+	case i != -1:
+		if min, err = strconv.Atoi(r[:i]); err != nil {
+			return 0, fmt.Errorf("invalid value %q", r[:i])
+		}
+		if max, err = strconv.Atoi(r[i+1:]); err != nil {
+			return 0, fmt.Errorf("invalid value %q", r[i+1:])
+		}
+	default:
+		if min, err = strconv.Atoi(r); err != nil {
+			return 0, fmt.Errorf("invalid value %q", r)
+		}
+		// A single value with a step (e.g., "5/15") means "starting at".
+		if step == 1 {
+			max = min
+		}
+	}
+	if min < lower || max > upper || min > max {
+		return 0, fmt.Errorf("range %d-%d is out of bounds [%d, %d]", min, max, lower, upper)
+	}
+	var b uint64
+	for v := min; v <= max; v += step {
+		b |= 1 << v
+	}
+	return b, nil
+}
+
+// match reports if the schedule matches the given time (in minute resolution).
+func (c *cronSchedule) match(t time.Time) bool {
+	if c.minute&(1<<t.Minute()) == 0 || c.hour&(1<<t.Hour()) == 0 || c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom, dow := c.dom&(1<<t.Day()) != 0, c.dow&(1<<int(t.Weekday())) != 0
+	// Following cron semantics, if both day fields are restricted,
+	// the time matches if either of them matches.
+	if c.domSet && c.dowSet {
+		return dom || dow
+	}
+	return dom && dow
+}
@@ -0,0 +1,148 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyWindow_Open(t *testing.T) {
+	at := func(s string) time.Time {
+		v, err := time.Parse(time.RFC3339, s)
+		require.NoError(t, err)
+		return v
+	}
+	// Weekdays at 22:00 for 2 hours.
+	w := &ApplyWindow{Schedule: "0 22 * * 1-5", Duration: "2h"}
+	for now, want := range map[string]bool{
+		"2024-05-06T22:00:00Z": true,  // Monday, at opening.
+		"2024-05-06T23:59:59Z": true,  // Monday, before closing.
+		"2024-05-07T00:00:00Z": false, // Tuesday, closed.
+		"2024-05-06T21:59:00Z": false, // Monday, before opening.
+		"2024-05-11T22:30:00Z": false, // Saturday.
+	} {
+		open, _, err := w.Open(at(now))
+		require.NoError(t, err)
+		require.Equal(t, want, open, now)
+	}
+	open, next, err := w.Open(at("2024-05-10T23:30:00Z"))
+	require.NoError(t, err)
+	require.True(t, open)
+	require.True(t, next.IsZero())
+	// Next window after Friday's is on Monday.
+	open, next, err = w.Open(at("2024-05-11T00:00:00Z"))
+	require.NoError(t, err)
+	require.False(t, open)
+	require.True(t, at("2024-05-13T22:00:00Z").Equal(next))
+
+	// Schedules are evaluated in the window timezone.
+	w = &ApplyWindow{Schedule: "0 2 * * *", Duration: "30m", Timezone: "Asia/Jerusalem"}
+	open, _, err = w.Open(at("2024-05-06T23:10:00Z"))
+	require.NoError(t, err)
+	require.True(t, open)
+
+	// Restricted day-of-month and day-of-week match either.
+	w = &ApplyWindow{Schedule: "*/15 0 1 * 0", Duration: "1m"}
+	open, _, err = w.Open(at("2024-05-01T00:15:00Z")) // Wednesday, 1st.
+	require.NoError(t, err)
+	require.True(t, open)
+	open, _, err = w.Open(at("2024-05-05T00:30:00Z")) // Sunday.
+	require.NoError(t, err)
+	require.True(t, open)
+	open, next, err = w.Open(at("2024-05-02T00:20:00Z"))
+	require.NoError(t, err)
+	require.False(t, open)
+	require.True(t, at("2024-05-05T00:00:00Z").Equal(next))
+
+	for _, w := range []*ApplyWindow{
+		{Schedule: "0 22 * *", Duration: "1h"},
+		{Schedule: "60 * * * *", Duration: "1h"},
+		{Schedule: "0 5-1 * * *", Duration: "1h"},
+		{Schedule: "*/0 * * * *", Duration: "1h"},
+		{Schedule: "0 * * * *", Duration: "1s"},
+		{Schedule: "0 * * * *", Duration: "1h", Timezone: "Mars/Olympus"},
+	} {
+		_, _, err := w.Open(time.Now())
+		require.Error(t, err, w)
+	}
+
+	env := &Env{ApplyWindows: []*ApplyWindow{
+		{Schedule: "0 22 * * *", Duration: "1h"},
+		{Schedule: "0 6 * * *", Duration: "1h"},
+	}}
+	open, next, err = env.applyWindowOpen(at("2024-05-06T12:00:00Z"))
+	require.NoError(t, err)
+	require.False(t, open)
+	require.True(t, at("2024-05-06T22:00:00Z").Equal(next))
+	open, _, err = env.applyWindowOpen(at("2024-05-06T06:10:00Z"))
+	require.NoError(t, err)
+	require.True(t, open)
+	open, _, err = (&Env{}).applyWindowOpen(time.Now())
+	require.NoError(t, err)
+	require.True(t, open)
+}
+
+func TestDestructiveChanges(t *testing.T) {
+	var (
+		s     = schema.New("public")
+		id    = schema.NewIntColumn("id", "int")
+		name  = schema.NewStringColumn("name", "varchar", schema.StringSize(255))
+		idx   = schema.NewIndex("users_name").AddColumns(name)
+		users = schema.NewTable("users").AddColumns(id, name).AddIndexes(idx)
+		pets  = schema.NewTable("pets").AddColumns(schema.NewIntColumn("owner_id", "int"))
+		fk    = schema.NewForeignKey("owner").AddColumns(pets.Columns[0]).SetRefTable(users).AddRefColumns(id)
+		env   = &Env{Lint: &Lint{}}
+	)
+	s.AddTables(users, pets)
+	pets.AddForeignKeys(fk)
+	for _, tt := range []struct {
+		changes []schema.Change
+		want    bool
+	}{
+		{
+			changes: []schema.Change{&schema.AddTable{T: schema.NewTable("t")}},
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.AddColumn{C: schema.NewIntColumn("age", "int")}}}},
+		},
+		{
+			changes: []schema.Change{&schema.DropTable{T: pets}},
+			want:    true,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropColumn{C: name}}}},
+			want:    true,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: users, Changes: []schema.Change{&schema.DropIndex{I: idx}}}},
+			want:    true,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: pets, Changes: []schema.Change{&schema.DropForeignKey{F: fk}}}},
+			want:    true,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: users, Changes: []schema.Change{
+				&schema.ModifyColumn{From: name, To: schema.NewStringColumn("name", "varchar", schema.StringSize(100)), Change: schema.ChangeType},
+			}}},
+			want: true,
+		},
+		{
+			changes: []schema.Change{&schema.ModifyTable{T: users, Changes: []schema.Change{
+				&schema.ModifyColumn{From: name, To: schema.NewStringColumn("name", "varchar", schema.StringSize(500)), Change: schema.ChangeType},
+			}}},
+		},
+	} {
+		got, err := destructiveChanges(context.Background(), env, tt.changes)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, got)
+	}
+}
@@ -568,8 +568,9 @@ func (f StateLoaderFunc) LoadState(ctx context.Context, opts *StateReaderConfig)
 var (
 	// States is a global registry for external state loaders.
 	States = registry{
-		"ent": EntLoader{},
-		"mem": memLoader,
+		"ent":              EntLoader{},
+		"mem":              memLoader,
+		SchemaTypeSnapshot: SnapshotLoader{},
 	}
 	memLoader       = MemLoader{states: make(map[string]StateLoader)}
 	errNotSchemaURL = errors.New("missing schema in --dev-url. See: https://atlasgo.io/url")
@@ -261,13 +261,23 @@ func stateReaderHCL(_ context.Context, config *StateReaderConfig, paths []string
 		StateReader: migrate.StateReaderFunc(func(ctx context.Context) (*schema.Realm, error) {
 			// Normalize once, only on dev database connection.
 			if nr, ok := client.Driver.(schema.Normalizer); ok && !normalized && config.Dev != nil {
+				defined := realm
 				switch {
 				// Empty schema file.
 				case len(realm.Schemas) == 0:
 				case config.Dev.URL.Schema != "":
-					realm.Schemas[0], err = nr.NormalizeSchema(ctx, realm.Schemas[0])
+					s := realm.Schemas[0]
+					if realm.Schemas[0], err = nr.NormalizeSchema(ctx, s); err == nil {
+						copyLabels(realm.Schemas[0], s)
+					}
 				default:
-					realm, err = nr.NormalizeRealm(ctx, realm)
+					if realm, err = nr.NormalizeRealm(ctx, realm); err == nil {
+						for _, s := range defined.Schemas {
+							if s1, ok := realm.Schema(s.Name); ok {
+								copyLabels(s1, s)
+							}
+						}
+					}
 				}
 				if err != nil {
 					return nil, err
@@ -288,6 +298,27 @@ func stateReaderHCL(_ context.Context, config *StateReaderConfig, paths []string
 	}, nil
 }
 
+// copyLabels copies the labels of the tables and columns defined in the src
+// schema to their normalized form in dst, as labels are not stored in the database.
+func copyLabels(dst, src *schema.Schema) {
+	for _, t := range src.Tables {
+		t1, ok := dst.Table(t.Name)
+		if !ok {
+			continue
+		}
+		if l := schema.LabelsOf(t.Attrs); l != nil {
+			schema.ReplaceOrAppend(&t1.Attrs, l)
+		}
+		for _, c := range t.Columns {
+			if l := schema.LabelsOf(c.Attrs); l != nil {
+				if c1, ok := t1.Column(c.Name); ok {
+					schema.ReplaceOrAppend(&c1.Attrs, l)
+				}
+			}
+		}
+	}
+}
+
 // FilesExt returns the file extension of the given URLs.
 // Note, all URL must have the same extension.
 func FilesExt(urls []*url.URL) (string, error) {
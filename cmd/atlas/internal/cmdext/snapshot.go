@@ -0,0 +1,121 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// SchemaTypeSnapshot is the URL scheme of schema snapshots. For example:
+	//
+	//	snapshot://2024-01-01              // Latest snapshot taken on 2024-01-01.
+	//	snapshot://latest?dir=snapshots    // Latest snapshot in the "snapshots" directory.
+	SchemaTypeSnapshot = "snapshot"
+	// DefaultSnapshotDir is the directory snapshots are stored in, if not configured otherwise.
+	DefaultSnapshotDir = ".atlas/snapshots"
+	// SnapshotLayout is the time layout of snapshot names. Names are sorted
+	// chronologically, and can be selected by their date (or time) prefix.
+	SnapshotLayout = "2006-01-02T150405Z"
+)
+
+// SnapshotName returns the name of a snapshot taken at the given time.
+func SnapshotName(t time.Time) string {
+	return t.UTC().Format(SnapshotLayout)
+}
+
+// WriteSnapshot writes the snapshot with the given name and content to the directory,
+// and returns the path to the created file. Existing snapshots are not overridden.
+func WriteSnapshot(dir, name string, b []byte) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || name == "latest" {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	path := filepath.Join(dir, name+FileTypeHCL)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return "", fmt.Errorf("snapshot %q already exists", name)
+		}
+		return "", fmt.Errorf("creating snapshot: %w", err)
+	}
+	if _, err := f.Write(b); err != nil {
+		return "", errors.Join(fmt.Errorf("writing snapshot: %w", err), f.Close())
+	}
+	return path, f.Close()
+}
+
+// Snapshots returns the names of the snapshots stored in the directory, sorted by their names.
+func Snapshots(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("reading snapshot directory: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if n, ok := strings.CutSuffix(e.Name(), FileTypeHCL); ok && !e.IsDir() {
+			names = append(names, n)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// ResolveSnapshot returns the path to the snapshot with the given name. In case there
+// is no exact match, the latest snapshot prefixed with the name is returned. Hence,
+// "2024-01-01" selects the last snapshot taken on that day, and "latest" selects the
+// last snapshot in the directory.
+func ResolveSnapshot(dir, name string) (string, error) {
+	names, err := Snapshots(dir)
+	if err != nil {
+		return "", err
+	}
+	if name == "latest" {
+		name = ""
+	}
+	if name != "" && slices.Contains(names, name) {
+		return filepath.Join(dir, name+FileTypeHCL), nil
+	}
+	for i := len(names) - 1; i >= 0; i-- {
+		if strings.HasPrefix(names[i], name) {
+			return filepath.Join(dir, names[i]+FileTypeHCL), nil
+		}
+	}
+	if name == "" {
+		return "", fmt.Errorf("no snapshots found in %q", dir)
+	}
+	return "", fmt.Errorf("snapshot %q was not found in %q", name, dir)
+}
+
+// SnapshotLoader is a StateLoader for loading schema snapshots. Snapshots are stored
+// in HCL, and therefore, like HCL schemas, are normalized on the dev database.
+type SnapshotLoader struct{}
+
+// LoadState returns a migrate.StateReader that reads the schema from a snapshot.
+func (SnapshotLoader) LoadState(ctx context.Context, config *StateReaderConfig) (*StateReadCloser, error) {
+	if len(config.URLs) != 1 {
+		return nil, errors.New(`"snapshot://" requires exactly one URL`)
+	}
+	u := config.URLs[0]
+	dir := DefaultSnapshotDir
+	if d := u.Query().Get("dir"); d != "" {
+		dir = d
+	}
+	path, err := ResolveSnapshot(dir, u.Host)
+	if err != nil {
+		return nil, err
+	}
+	return stateReaderHCL(ctx, config, []string{path})
+}
@@ -0,0 +1,50 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdext
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	_, err := ResolveSnapshot(dir, "latest")
+	require.EqualError(t, err, "no snapshots found in \""+dir+"\"")
+
+	for _, n := range []string{
+		SnapshotName(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)),
+		SnapshotName(time.Date(2024, 1, 1, 18, 30, 0, 0, time.UTC)),
+		SnapshotName(time.Date(2024, 2, 1, 8, 0, 0, 0, time.UTC)),
+		"v1",
+		"v10",
+	} {
+		_, err := WriteSnapshot(dir, n, []byte(`schema "main" {}`))
+		require.NoError(t, err)
+	}
+	_, err = WriteSnapshot(dir, "v1", nil)
+	require.EqualError(t, err, `snapshot "v1" already exists`)
+	_, err = WriteSnapshot(dir, "../v1", nil)
+	require.EqualError(t, err, `invalid snapshot name "../v1"`)
+
+	for n, want := range map[string]string{
+		"2024-01-01":         "2024-01-01T183000Z",
+		"2024-01-01T10":      "2024-01-01T100000Z",
+		"2024-02":            "2024-02-01T080000Z",
+		"2024":               "2024-02-01T080000Z",
+		"v1":                 "v1",
+		"latest":             "v10",
+		"2024-01-01T100000Z": "2024-01-01T100000Z",
+	} {
+		p, err := ResolveSnapshot(dir, n)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(dir, want+".hcl"), p, n)
+	}
+	_, err = ResolveSnapshot(dir, "2023")
+	require.EqualError(t, err, "snapshot \"2023\" was not found in \""+dir+"\"")
+}
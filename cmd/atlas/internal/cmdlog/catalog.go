@@ -0,0 +1,154 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// Facets of the inspected schema that can be exported as flat rows.
+const (
+	FacetTables      = "tables"
+	FacetColumns     = "columns"
+	FacetIndexes     = "indexes"
+	FacetForeignKeys = "fks"
+)
+
+// facetHeaders holds the header row of each facet.
+var facetHeaders = map[string][]string{
+	FacetTables:      {"schema", "table", "comment"},
+	FacetColumns:     {"schema", "table", "column", "type", "nullable", "default", "comment"},
+	FacetIndexes:     {"schema", "table", "index", "primary", "unique", "parts", "comment"},
+	FacetForeignKeys: {"schema", "table", "constraint", "columns", "ref_schema", "ref_table", "ref_columns", "on_update", "on_delete"},
+}
+
+// WriteInspectRows writes the rows of the given facet of the inspected schema (e.g., its
+// columns) to w, separated by the given delimiter (e.g., ',' for CSV or '\t' for TSV). The
+// first row holds the column names, and the rest are written as the realm is traversed,
+// to allow streaming large schemas into data catalogs and spreadsheets.
+func WriteInspectRows(w io.Writer, i *SchemaInspect, facet string, comma rune) error {
+	header, ok := facetHeaders[facet]
+	if !ok {
+		return fmt.Errorf("unknown facet %q. Expect one of: %s, %s, %s or %s", facet, FacetTables, FacetColumns, FacetIndexes, FacetForeignKeys)
+	}
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	var f schema.TypeFormatter
+	if i.client != nil {
+		f, _ = i.client.Driver.(schema.TypeFormatter)
+	}
+	for _, s := range i.Realm.Schemas {
+		for _, t := range s.Tables {
+			var rows [][]string
+			switch facet {
+			case FacetTables:
+				rows = append(rows, []string{s.Name, t.Name, comment(t.Attrs)})
+			case FacetColumns:
+				for _, c := range t.Columns {
+					rows = append(rows, []string{s.Name, t.Name, c.Name, columnType(f, c), strconv.FormatBool(c.Type != nil && c.Type.Null), exprValue(c.Default), comment(c.Attrs)})
+				}
+			case FacetIndexes:
+				if pk := t.PrimaryKey; pk != nil {
+					rows = append(rows, []string{s.Name, t.Name, pk.Name, "true", "true", rowParts(pk.Parts), comment(pk.Attrs)})
+				}
+				for _, idx := range t.Indexes {
+					rows = append(rows, []string{s.Name, t.Name, idx.Name, "false", strconv.FormatBool(idx.Unique), rowParts(idx.Parts), comment(idx.Attrs)})
+				}
+			case FacetForeignKeys:
+				for _, fk := range t.ForeignKeys {
+					rs := s.Name
+					if fk.RefTable.Schema != nil {
+						rs = fk.RefTable.Schema.Name
+					}
+					rows = append(rows, []string{s.Name, t.Name, fk.Symbol, rowColumns(fk.Columns), rs, fk.RefTable.Name, rowColumns(fk.RefColumns), string(fk.OnUpdate), string(fk.OnDelete)})
+				}
+			}
+			if err := cw.WriteAll(rows); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvInspect returns the rows of the given facet of the inspected schema in CSV format.
+func csvInspect(i *SchemaInspect, facet string) (string, error) {
+	var b strings.Builder
+	if err := WriteInspectRows(&b, i, facet, ','); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// tsvInspect returns the rows of the given facet of the inspected schema in TSV format.
+func tsvInspect(i *SchemaInspect, facet string) (string, error) {
+	var b strings.Builder
+	if err := WriteInspectRows(&b, i, facet, '\t'); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// columnType returns the type of the column as defined in the database.
+func columnType(f schema.TypeFormatter, c *schema.Column) string {
+	switch {
+	case c.Type == nil:
+		return ""
+	case c.Type.Raw != "" || f == nil || c.Type.Type == nil:
+		return c.Type.Raw
+	}
+	t, err := f.FormatType(c.Type.Type)
+	if err != nil {
+		return c.Type.Raw
+	}
+	return t
+}
+
+// exprValue returns the textual value of the expression, or an empty string if it is nil.
+func exprValue(x schema.Expr) string {
+	switch x := x.(type) {
+	case nil:
+		return ""
+	case *schema.Literal:
+		return x.V
+	case *schema.RawExpr:
+		return x.X
+	default:
+		return fmt.Sprintf("%+v", x)
+	}
+}
+
+// rowParts returns the comma-separated columns and expressions of the index parts.
+func rowParts(parts []*schema.IndexPart) string {
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		switch {
+		case p.C != nil:
+			names = append(names, p.C.Name)
+		case p.X != nil:
+			names = append(names, exprValue(p.X))
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// rowColumns returns the comma-separated names of the columns.
+func rowColumns(columns []*schema.Column) string {
+	names := make([]string, 0, len(columns))
+	for _, c := range columns {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, ",")
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"net/url"
 	"reflect"
@@ -25,6 +26,7 @@ import (
 	"ariga.io/atlas/cmd/atlas/internal/migrate/ent/revision"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlclient"
 
 	"github.com/fatih/color"
@@ -89,6 +91,16 @@ type (
 		Stmt string `json:"Stmt,omitempty"` // SQL statement that failed.
 		Text string `json:"Text,omitempty"` // Error message as returned by the database.
 	}
+
+	// StmtStats holds the estimated and the actual execution duration of an applied
+	// statement. Reporting both allows calibrating the estimates of future executions.
+	StmtStats struct {
+		Stmt      string            `json:"Stmt"`           // SQL statement that was executed.
+		Class     migrate.StmtClass `json:"Class"`          // Class of the statement (e.g., rewrite).
+		Rows      int64             `json:"Rows,omitempty"` // Estimated rows of the affected table.
+		Estimated time.Duration     `json:"Estimated"`      // Estimated execution duration.
+		Actual    time.Duration     `json:"Actual"`         // Actual execution duration.
+	}
 )
 
 // MarshalJSON implements json.Marshaler.
@@ -117,7 +129,7 @@ func NewEnv(c *sqlclient.Client, dirURL *url.URL) Env {
 		URL:    c.URL,
 	}
 	if dirURL != nil {
-		e.Dir = dirURL.Redacted()
+		e.Dir = sqlclient.RedactURL(dirURL)
 	}
 	return e
 }
@@ -127,6 +139,9 @@ var (
 	StatusTemplateFuncs = WithColorFuncs(template.FuncMap{
 		"json":       jsonEncode,
 		"json_merge": jsonMerge,
+		"prometheus": func(report *MigrateStatus) (string, error) {
+			return report.MarshalPrometheus()
+		},
 		"default": func(report *MigrateStatus) (string, error) {
 			var buf bytes.Buffer
 			t, err := template.New("report").
@@ -163,6 +178,10 @@ Last migration attempt had errors:
 
 	// MigrateStatusTemplate holds the default template of the 'migrate status' command.
 	MigrateStatusTemplate = template.Must(template.New("report").Funcs(StatusTemplateFuncs).Parse("{{ default . }}"))
+
+	// MigrateStatusPrometheusTemplate holds the template of the 'migrate status' command
+	// for reporting the status as metrics in the Prometheus text-based exposition format.
+	MigrateStatusPrometheusTemplate = template.Must(template.New("report").Funcs(StatusTemplateFuncs).Parse("{{ prometheus . }}"))
 )
 
 // MigrateStatus contains a summary of the migration status of a database.
@@ -194,6 +213,115 @@ func (r *MigrateStatus) FromCheckpoint() bool {
 	return ok && ck.IsCheckpoint()
 }
 
+// MarshalPrometheus returns the status report as metrics in the Prometheus text-based
+// exposition format, to be collected by the textfile collector of the node-exporter.
+func (r *MigrateStatus) MarshalPrometheus() (string, error) {
+	var (
+		b      strings.Builder
+		u      string
+		labels = make([]string, 0, 3)
+	)
+	if r.URL != nil && r.URL.URL != nil {
+		u = r.URL.Redacted()
+	}
+	for _, l := range [][2]string{{"driver", r.Driver}, {"url", u}, {"dir", r.Dir}} {
+		if l[1] != "" {
+			labels = append(labels, fmt.Sprintf(`%s="%s"`, l[0], promEscaper.Replace(l[1])))
+		}
+	}
+	metric := func(name, help string, v int64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s", name, help, name, name)
+		if len(labels) > 0 {
+			fmt.Fprintf(&b, "{%s}", strings.Join(labels, ","))
+		}
+		fmt.Fprintf(&b, " %d\n", v)
+	}
+	metric("atlas_pending_migrations", "Number of migration files pending to be applied.", int64(len(r.Pending)))
+	metric("atlas_out_of_order_migrations", "Number of migration files pending to be applied out of order.", int64(len(r.OutOfOrder)))
+	metric("atlas_applied_migrations", "Number of migration files applied to the database.", int64(len(r.Applied)))
+	if n := len(r.Applied); n > 0 {
+		metric("atlas_last_applied_timestamp", "Unix timestamp of the last migration file applied to the database.", r.Applied[n-1].ExecutedAt.Unix())
+	}
+	var dirty int64
+	if r.Total > 0 {
+		dirty = 1
+	}
+	metric("atlas_dirty", "Whether the last migration file was partially applied due to an error.", dirty)
+	return b.String(), nil
+}
+
+// SchemaDrift contains the result of a drift check, run by 'atlas agent' or 'schema drift',
+// between the schema of a database and its desired state.
+type SchemaDrift struct {
+	Env     string    `json:"Env"`             // Name of the environment.
+	URL     string    `json:"URL,omitempty"`   // Redacted URL of the database.
+	Time    time.Time `json:"Time"`            // Time the check was started.
+	Changes int       `json:"Changes"`         // Number of changes required to sync the database.
+	SQL     string    `json:"SQL,omitempty"`   // SQL statements that sync the database.
+	Error   string    `json:"Error,omitempty"` // Error of the check, if failed.
+}
+
+// Drifted reports if the schema of the database drifted from its desired state.
+func (d *SchemaDrift) Drifted() bool { return d.Changes > 0 }
+
+var (
+	// SchemaDriftFuncs are global functions available in drift report templates.
+	SchemaDriftFuncs = WithColorFuncs(template.FuncMap{
+		"json": jsonEncode,
+	})
+	// SchemaDriftTemplate holds the default template of the 'schema drift' command.
+	SchemaDriftTemplate = template.Must(template.
+				New("schema_drift").
+				Funcs(SchemaDriftFuncs).
+				Parse(`{{- if .Error -}}
+Drift check of env {{ printf "%q" .Env }} failed: {{ .Error }}
+{{ else if .Drifted -}}
+Schema drift detected in env {{ printf "%q" .Env }} ({{ .Changes }} changes):
+{{ .SQL }}
+{{- else -}}
+No schema drift detected in env {{ printf "%q" .Env }}
+{{ end -}}
+`))
+)
+
+// MarshalDriftPrometheus returns the given drift checks as metrics
+// in the Prometheus text-based exposition format.
+func MarshalDriftPrometheus(checks []*SchemaDrift) string {
+	var b strings.Builder
+	metric := func(name, help string, v func(*SchemaDrift) int64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, c := range checks {
+			labels := []string{fmt.Sprintf(`env="%s"`, promEscaper.Replace(c.Env))}
+			if c.URL != "" {
+				labels = append(labels, fmt.Sprintf(`url="%s"`, promEscaper.Replace(c.URL)))
+			}
+			fmt.Fprintf(&b, "%s{%s} %d\n", name, strings.Join(labels, ","), v(c))
+		}
+	}
+	boolean := func(b bool) int64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	metric("atlas_schema_drift", "Whether the schema of the database drifted from its desired state.", func(c *SchemaDrift) int64 {
+		return boolean(c.Drifted())
+	})
+	metric("atlas_schema_drift_changes", "Number of changes required to sync the database with its desired state.", func(c *SchemaDrift) int64 {
+		return int64(c.Changes)
+	})
+	metric("atlas_schema_drift_error", "Whether the last drift check failed.", func(c *SchemaDrift) int64 {
+		return boolean(c.Error != "")
+	})
+	metric("atlas_schema_drift_timestamp", "Unix timestamp of the last drift check.", func(c *SchemaDrift) int64 {
+		return c.Time.Unix()
+	})
+	return b.String()
+}
+
+// promEscaper escapes label values in the Prometheus exposition format.
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
 // StatusReporter is used to gather information about migration status.
 type StatusReporter struct {
 	// Client configures the connection to the database to file a MigrateStatus for.
@@ -204,6 +332,8 @@ type StatusReporter struct {
 	Dir migrate.Dir
 	// Schema name the revision table resides in.
 	Schema string
+	// Table is the name of the revision table. Defaults to atlas_schema_revisions.
+	Table string
 }
 
 // Report creates and writes a MigrateStatus.
@@ -211,11 +341,15 @@ func (r *StatusReporter) Report(ctx context.Context) (*MigrateStatus, error) {
 	rep := &MigrateStatus{Env: NewEnv(r.Client, r.DirURL)}
 	// Check if there already is a revision table in the defined schema.
 	// Inspect schema and check if the table does already exist.
-	sch, err := r.Client.InspectSchema(ctx, r.Schema, &schema.InspectOptions{Tables: []string{revision.Table}})
+	table := revision.Table
+	if r.Table != "" {
+		table = r.Table
+	}
+	sch, err := r.Client.InspectSchema(ctx, r.Schema, &schema.InspectOptions{Tables: []string{table}})
 	if err != nil && !schema.IsNotExistError(err) {
 		return nil, err
 	}
-	if schema.IsNotExistError(err) || func() bool { _, ok := sch.Table(revision.Table); return !ok }() {
+	if schema.IsNotExistError(err) || func() bool { _, ok := sch.Table(table); return !ok }() {
 		// Either schema or table does not exist.
 		if rep.Available, err = migrate.FilesFromLastCheckpoint(r.Dir); err != nil {
 			return nil, err
@@ -223,7 +357,7 @@ func (r *StatusReporter) Report(ctx context.Context) (*MigrateStatus, error) {
 		rep.Pending = rep.Available
 	} else {
 		// Both exist, fetch their data.
-		rrw, err := cmdmigrate.RevisionsForClient(ctx, r.Client, r.Schema)
+		rrw, err := cmdmigrate.RevisionsForClient(ctx, r.Client, r.Schema, cmdmigrate.WithTable(r.Table))
 		if err != nil {
 			return nil, err
 		}
@@ -384,6 +518,195 @@ func (r *RevisionOp) ColoredVersion() string {
 	return c + " " + r.Version
 }
 
+// MigrateDownTemplate holds the default template of the 'migrate down' command.
+var MigrateDownTemplate = template.Must(template.
+	New("down").
+	Funcs(ApplyTemplateFuncs).
+	Parse(`{{- if not .Reverted -}}
+{{- println "No migration files to revert" }}
+{{- else -}}
+{{- println .Header }}
+{{- range $s := .Steps }}
+	{{- println }}
+	{{- println " " (yellow "--") $s.Name }}
+	{{- range $s.Stmts }}
+		{{- println "   " (cyan "->") (indent_ln . 7) }}
+	{{- end }}
+	{{- with $s.Error }}
+		{{- println "   " (redBgWhiteFg .Text) }}
+	{{- else }}
+		{{- if not $.DryRun }}
+			{{- printf "  %s ok (%s)\n" (yellow "--") (yellow ($s.End.Sub $s.Start).String) }}
+		{{- end }}
+	{{- end }}
+{{- else }}
+	{{- println }}
+	{{- with .Error }}
+		{{- println "   " (redBgWhiteFg .) }}
+	{{- end }}
+{{- end }}
+{{- println }}
+{{- println " " (cyan "-------------------------") }}
+{{- println " " (.Summary "  ") }}
+{{- end -}}
+`))
+
+type (
+	// MigrateDown contains a summary of a 'migrate down' execution on a database.
+	MigrateDown struct {
+		ctx context.Context
+		Env
+		Current  string              `json:"Current,omitempty"`  // Current migration version
+		Target   string              `json:"Target,omitempty"`   // Target migration version, empty for initial state
+		Reverted []*migrate.Revision `json:"Reverted,omitempty"` // Revisions to revert
+		Steps    []*DownStep         `json:"Steps,omitempty"`    // Steps executed to revert the revisions
+		DryRun   bool                `json:"DryRun,omitempty"`   // Statements were not executed
+		Start    time.Time
+		End      time.Time
+		// Error is set even then, if it was not caused by a statement,
+		// but by Atlas, e.g. when computing the reverse statements.
+		Error string `json:"Error,omitempty"`
+	}
+
+	// DownStep is a group of statements that reverts one or more revisions.
+	DownStep struct {
+		Name  string   // Describes what the step reverts.
+		Stmts []string // Statements to execute.
+		Start time.Time
+		End   time.Time
+		Error *StmtError `json:"Error,omitempty"`
+	}
+)
+
+// NewMigrateDown returns a MigrateDown.
+func NewMigrateDown(ctx context.Context, client *sqlclient.Client, dirURL *url.URL) *MigrateDown {
+	return &MigrateDown{
+		ctx:   ctx,
+		Env:   NewEnv(client, dirURL),
+		Start: time.Now(),
+	}
+}
+
+// Header returns a header of the revert log.
+func (d *MigrateDown) Header() string {
+	var b strings.Builder
+	b.WriteString("Reverting ")
+	if d.Target == "" {
+		b.WriteString("to the initial state")
+	} else {
+		b.WriteString("to version ")
+		b.WriteString(ColorCyan(d.Target))
+	}
+	b.WriteString(" from ")
+	b.WriteString(ColorCyan(d.Current))
+	b.WriteString(" (")
+	b.WriteString(strconv.Itoa(len(d.Reverted)))
+	b.WriteString(" migrations in total):")
+	return b.String()
+}
+
+// Summary returns a footer of the revert log.
+func (d *MigrateDown) Summary(ident string) string {
+	var stmts int
+	for _, s := range d.Steps {
+		stmts += len(s.Stmts)
+	}
+	lines := []string{d.End.Sub(d.Start).String()}
+	switch n := len(d.Reverted); {
+	case d.DryRun:
+		lines = append(lines, fmt.Sprintf("%d migration%s to revert (dry run)", n, plural(n)))
+	case d.Error != "":
+		lines = append(lines, fmt.Sprintf("%d migration%s failed to revert", n, plural(n)))
+	default:
+		lines = append(lines, fmt.Sprintf("%d migration%s reverted", n, plural(n)))
+	}
+	if stmts > 0 {
+		lines = append(lines, fmt.Sprintf("%d sql statement%s", stmts, plural(stmts)))
+	}
+	var b strings.Builder
+	for i, l := range lines {
+		b.WriteString(ColorYellow("--"))
+		b.WriteByte(' ')
+		b.WriteString(l)
+		if i < len(lines)-1 {
+			b.WriteByte('\n')
+			b.WriteString(ident)
+		}
+	}
+	return b.String()
+}
+
+var (
+	// ValidateTemplateFuncs are global functions available in validate report templates.
+	ValidateTemplateFuncs = WithColorFuncs(template.FuncMap{
+		"json":       jsonEncode,
+		"json_merge": jsonMerge,
+	})
+
+	// MigrateValidateTemplate holds the default template of the 'migrate validate --against' command.
+	MigrateValidateTemplate = template.Must(template.New("validate").
+				Funcs(ValidateTemplateFuncs).
+				Parse(`
+{{- range .Checks }}
+  {{- if .Passed }}{{ green "PASS" }}{{ else }}{{ red "FAIL" }}{{ end }} {{ .Name }}: {{ .Text }}
+  {{- range .Details }}
+  {{ yellow "--" }} {{ . }}
+  {{- end }}
+  {{- with .Error }}
+  {{ red "ERROR:" }} {{ . }}
+  {{- end }}
+{{ end }}
+{{- if .Passed }}{{ green "Validation passed" }}{{ else }}{{ red "Validation failed" }}{{ end }}
+`))
+)
+
+// Names of the checks executed by 'migrate validate --against'.
+const (
+	CheckDirIntegrity = "Directory Integrity"
+	CheckLintNewFiles = "Lint New Files"
+	CheckSchemaMatch  = "Schema Equivalence"
+)
+
+type (
+	// MigrateValidate contains the consolidated verdict of validating
+	// a migration directory against a target database.
+	MigrateValidate struct {
+		Env
+		Target *sqlclient.URL   `json:"Target,omitempty"` // URL of the target database.
+		Passed bool             `json:"Passed"`           // Passed reports if all checks passed.
+		Checks []*ValidateCheck `json:"Checks,omitempty"` // Checks that were executed.
+	}
+
+	// ValidateCheck describes the result of a single validation check.
+	ValidateCheck struct {
+		Name    string   `json:"Name"`              // Name of the check.
+		Passed  bool     `json:"Passed"`            // Passed reports if the check passed.
+		Text    string   `json:"Text,omitempty"`    // Summary of the check result.
+		Details []string `json:"Details,omitempty"` // Details of the check findings.
+		Error   string   `json:"Error,omitempty"`   // Error that caused the check to fail.
+	}
+)
+
+// NewMigrateValidate returns a MigrateValidate.
+func NewMigrateValidate(env Env, target *sqlclient.URL) *MigrateValidate {
+	return &MigrateValidate{Env: env, Target: target, Passed: true}
+}
+
+// Pass records a check that passed.
+func (r *MigrateValidate) Pass(name, text string, details ...string) {
+	r.Checks = append(r.Checks, &ValidateCheck{Name: name, Passed: true, Text: text, Details: details})
+}
+
+// Fail records a check that failed. The given error is optional.
+func (r *MigrateValidate) Fail(name, text string, err error, details ...string) {
+	c := &ValidateCheck{Name: name, Text: text, Details: details}
+	if err != nil {
+		c.Error = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+	r.Passed = false
+}
+
 var (
 	// ApplyTemplateFuncs are global functions available in apply report templates.
 	ApplyTemplateFuncs = WithColorFuncs(template.FuncMap{
@@ -425,8 +748,14 @@ var (
 		{{- continue }} {{- /* No statements were applied. */}}
 	{{- end }}
 	{{- println " " (yellow "--") "migrating version" (cyan $f.File.Version) }}
-	{{- range $f.Applied }}
-		{{- println "   " (cyan "->") (indent_ln . 7) }}
+	{{- range $j, $s := $f.Applied }}
+		{{- println "   " (cyan "->") (indent_ln $s 7) }}
+		{{- with $f.Effect $j }}
+			{{- println "      " (yellow .String) }}
+		{{- end }}
+	{{- end }}
+	{{- range $f.Warnings }}
+		{{- println "   " (yellow "warning:") .Text }}
 	{{- end }}
 	{{- with .Error }}
 		{{- println "   " (redBgWhiteFg .Text) }}
@@ -455,8 +784,11 @@ type (
 		Applied []*AppliedFile `json:"Applied,omitempty"` // Applied files
 		Current string         `json:"Current,omitempty"` // Current migration version
 		Target  string         `json:"Target,omitempty"`  // Target migration version
-		Start   time.Time
-		End     time.Time
+		// Simulated indicates the files were executed in a
+		// transaction that was rolled back at the end.
+		Simulated bool `json:"Simulated,omitempty"`
+		Start     time.Time
+		End       time.Time
 		// Error is set even then, if it was not caused by a statement in a migration file,
 		// but by Atlas, e.g. when committing or rolling back a transaction.
 		Error string `json:"Error,omitempty"`
@@ -465,15 +797,62 @@ type (
 	// AppliedFile is part of an MigrateApply containing information about an applied file in a migration attempt.
 	AppliedFile struct {
 		migrate.File
-		Start   time.Time
-		End     time.Time
-		Skipped int           // Amount of skipped SQL statements in a partially applied file.
-		Applied []string      // SQL statements applied with success
-		Checks  []*FileChecks // Assertion checks
-		Error   *StmtError
+		Start    time.Time
+		End      time.Time
+		Skipped  int           // Amount of skipped SQL statements in a partially applied file.
+		Applied  []string      // SQL statements applied with success
+		Checks   []*FileChecks // Assertion checks
+		Error    *StmtError
+		Warnings []*StmtError // Tolerated statement errors
+		// Effects of the applied statements in a simulated execution,
+		// ordered as Applied. Entries without a recorded effect are nil.
+		Effects []*StmtEffect `json:"Effects,omitempty"`
+	}
+
+	// StmtEffect describes the effect of a statement executed in a simulated apply.
+	StmtEffect struct {
+		RowsAffected int64         // Number of rows affected by the statement, or -1 if unknown.
+		Duration     time.Duration // Execution time of the statement.
 	}
 )
 
+// Effect returns the effect of the i-th applied statement, if it was recorded.
+func (f *AppliedFile) Effect(i int) *StmtEffect {
+	if i < len(f.Effects) {
+		return f.Effects[i]
+	}
+	return nil
+}
+
+// String implements fmt.Stringer.
+func (e *StmtEffect) String() string {
+	if e.RowsAffected < 0 {
+		return fmt.Sprintf("(%s)", e.Duration)
+	}
+	s := "s"
+	if e.RowsAffected == 1 {
+		s = ""
+	}
+	return fmt.Sprintf("%d row%s affected (%s)", e.RowsAffected, s, e.Duration)
+}
+
+// LogEffect records the effect of the given statement, in case it is
+// the last statement logged for the file that is currently executed.
+func (a *MigrateApply) LogEffect(stmt string, rows int64, d time.Duration) {
+	if len(a.Applied) == 0 {
+		return
+	}
+	f := a.Applied[len(a.Applied)-1]
+	i := len(f.Applied) - 1
+	if i < 0 || f.Applied[i] != stmt || i < len(f.Effects) {
+		return
+	}
+	for len(f.Effects) < i {
+		f.Effects = append(f.Effects, nil)
+	}
+	f.Effects = append(f.Effects, &StmtEffect{RowsAffected: rows, Duration: d})
+}
+
 // NewMigrateApply returns an MigrateApply.
 func NewMigrateApply(ctx context.Context, client *sqlclient.Client, dirURL *url.URL) *MigrateApply {
 	return &MigrateApply{
@@ -552,6 +931,12 @@ func (a *MigrateApply) Log(e migrate.LogEntry) {
 			a.End = time.Now()
 			a.Error = e.Error.Error()
 		}
+	case migrate.LogWarning:
+		f := a.Applied[len(a.Applied)-1]
+		f.Warnings = append(f.Warnings, &StmtError{
+			Stmt: e.SQL,
+			Text: e.Error.Error(),
+		})
 	case migrate.LogDone:
 		n := time.Now()
 		if l := len(a.Applied); l > 0 {
@@ -567,7 +952,11 @@ func (a *MigrateApply) Header() string {
 		return "No migration files to execute"
 	}
 	var b strings.Builder
-	b.WriteString("Migrating to version ")
+	if a.Simulated {
+		b.WriteString("Simulating migration to version ")
+	} else {
+		b.WriteString("Migrating to version ")
+	}
 	b.WriteString(ColorCyan(a.Target))
 	if a.Current != "" {
 		b.WriteString(" from ")
@@ -585,9 +974,11 @@ func (a *MigrateApply) Summary(ident string) string {
 		passedC, failedC int
 		passedS, failedS int
 		passedF, failedF int
-		lines            = make([]string, 0, 3)
+		warnings         int
+		lines            = make([]string, 0, 4)
 	)
 	for _, f := range a.Applied {
+		warnings += len(f.Warnings)
 		// For each check file, count the
 		// number of failed assertions.
 		for _, cf := range f.Checks {
@@ -640,6 +1031,13 @@ func (a *MigrateApply) Summary(ident string) string {
 	case failedS > 0:
 		lines = append(lines, fmt.Sprintf("%d sql statement%s with errors", failedS, plural(failedS)))
 	}
+	// Tolerated errors.
+	if warnings > 0 {
+		lines = append(lines, fmt.Sprintf("%d warning%s", warnings, plural(warnings)))
+	}
+	if a.Simulated {
+		lines = append(lines, "changes rolled back (simulation)")
+	}
 	var b strings.Builder
 	for i, l := range lines {
 		b.WriteString(ColorYellow("--"))
@@ -681,14 +1079,16 @@ func (a *MigrateApply) MarshalJSON() ([]byte, error) {
 // MarshalJSON implements json.Marshaler.
 func (f *AppliedFile) MarshalJSON() ([]byte, error) {
 	type local struct {
-		Name        string     `json:"Name,omitempty"`
-		Version     string     `json:"Version,omitempty"`
-		Description string     `json:"Description,omitempty"`
-		Start       time.Time  `json:"Start,omitempty"`
-		End         time.Time  `json:"End,omitempty"`
-		Skipped     int        `json:"Skipped,omitempty"`
-		Stmts       []string   `json:"Applied,omitempty"`
-		Error       *StmtError `json:"Error,omitempty"`
+		Name        string        `json:"Name,omitempty"`
+		Version     string        `json:"Version,omitempty"`
+		Description string        `json:"Description,omitempty"`
+		Start       time.Time     `json:"Start,omitempty"`
+		End         time.Time     `json:"End,omitempty"`
+		Skipped     int           `json:"Skipped,omitempty"`
+		Stmts       []string      `json:"Applied,omitempty"`
+		Error       *StmtError    `json:"Error,omitempty"`
+		Warnings    []*StmtError  `json:"Warnings,omitempty"`
+		Effects     []*StmtEffect `json:"Effects,omitempty"`
 	}
 	return json.Marshal(local{
 		Name:        f.Name(),
@@ -699,9 +1099,103 @@ func (f *AppliedFile) MarshalJSON() ([]byte, error) {
 		Skipped:     f.Skipped,
 		Stmts:       f.Applied,
 		Error:       f.Error,
+		Warnings:    f.Warnings,
+		Effects:     f.Effects,
 	})
 }
 
+// FormatNDJSON is a reserved log format of 'migrate apply' that streams
+// one JSON event per executed statement, instead of executing a template.
+const FormatNDJSON = "ndjson"
+
+type (
+	// StmtEvent is a machine-readable event that is written
+	// for each statement executed by 'migrate apply'.
+	StmtEvent struct {
+		Time     time.Time     // Start time of the statement.
+		File     string        // Name of the migration file.
+		Version  string        // Version of the migration file.
+		Index    int           // Index of the statement in the file, or -1 for non-statement errors.
+		SQL      string        // SQL statement.
+		Duration time.Duration // Execution time of the statement.
+		Error    string        `json:"Error,omitempty"`
+		Warning  string        `json:"Warning,omitempty"` // Tolerated error.
+	}
+
+	// StmtEventLogger is a migrate.Logger that writes a StmtEvent for each
+	// executed statement as a single JSON line (NDJSON) to its writer.
+	StmtEventLogger struct {
+		migrate.Logger // Underlying logger.
+		enc            *json.Encoder
+		file           migrate.File
+		next           int        // Index of the next statement in the file.
+		last           *StmtEvent // Statement in execution, if any.
+	}
+)
+
+// NewStmtEventLogger returns a StmtEventLogger that writes to w,
+// and passes all log entries to the underlying logger l.
+func NewStmtEventLogger(w io.Writer, l migrate.Logger) *StmtEventLogger {
+	if l == nil {
+		l = migrate.NopLogger{}
+	}
+	return &StmtEventLogger{Logger: l, enc: json.NewEncoder(w)}
+}
+
+// Log implements migrate.Logger.
+func (l *StmtEventLogger) Log(e migrate.LogEntry) {
+	l.Logger.Log(e)
+	switch e := e.(type) {
+	case migrate.LogFile:
+		l.flush()
+		l.file, l.next = e.File, e.Skip
+	case migrate.LogStmt:
+		l.flush()
+		l.last = &StmtEvent{
+			Time:  time.Now(),
+			Index: l.next,
+			SQL:   e.SQL,
+		}
+		if l.file != nil {
+			l.last.File, l.last.Version = l.file.Name(), l.file.Version()
+		}
+		l.next++
+	case migrate.LogWarning:
+		if l.last != nil && l.last.SQL == e.SQL {
+			l.last.Warning = e.Error.Error()
+		}
+	case migrate.LogError:
+		// Errors that were not caused by the executed
+		// statement, are written as file-level events.
+		if l.last == nil || l.last.SQL != e.SQL {
+			l.flush()
+			l.last = &StmtEvent{Time: time.Now(), Index: -1}
+			if l.file != nil {
+				l.last.File, l.last.Version = l.file.Name(), l.file.Version()
+			}
+		}
+		l.last.Error = e.Error.Error()
+		l.flush()
+	case migrate.LogChecks, migrate.LogDone:
+		l.flush()
+	}
+}
+
+// flush writes the statement in execution, if there is one. Note that the
+// statement duration is measured until the next log entry is received.
+func (l *StmtEventLogger) flush() {
+	if l.last == nil {
+		return
+	}
+	if l.last.Index >= 0 {
+		l.last.Duration = time.Since(l.last.Time)
+	}
+	// Encoding errors cannot be reported to the executor,
+	// and should not fail the execution of the migration.
+	_ = l.enc.Encode(l.last)
+	l.last = nil
+}
+
 // SchemaPlanTemplate holds the default template of the 'schema apply --dry-run' command.
 var SchemaPlanTemplate = template.Must(template.
 	New("plan").
@@ -754,10 +1248,14 @@ type SchemaInspect struct {
 var (
 	// InspectTemplateFuncs are global functions available in inspect report templates.
 	InspectTemplateFuncs = template.FuncMap{
-		"base64url": base64url,
-		"sql":       sqlInspect,
-		"json":      jsonEncode,
-		"mermaid":   mermaid,
+		"base64url":  base64url,
+		"sql":        sqlInspect,
+		"json":       jsonEncode,
+		"mermaid":    mermaid,
+		"dot":        dotInspect,
+		"jsonschema": jsonSchemaInspect,
+		"csv":        csvInspect,
+		"tsv":        tsvInspect,
 	}
 
 	// SchemaInspectTemplate holds the default template of the 'schema inspect' command.
@@ -788,14 +1286,16 @@ func (s *SchemaInspect) MarshalHCL() (string, error) {
 
 // RedactedURL returns the inspected url redacted.
 func (s *SchemaInspect) RedactedURL() (string, error) {
-	u, err := url.Parse(s.URL)
+	u, err := sqlclient.ParseURL(s.URL)
 	if err != nil {
 		return "", err
 	}
-	return u.Redacted(), nil
+	return sqlclient.RedactURL(u), nil
 }
 
-// MarshalJSON implements json.Marshaler.
+// MarshalJSON implements json.Marshaler. Objects are encoded in the order of
+// the inspected realm, and each object holds its stable identifier ("id").
+// See qualifiedID for the identifiers format.
 func (s *SchemaInspect) MarshalJSON() ([]byte, error) {
 	type (
 		Attrs struct {
@@ -804,6 +1304,7 @@ func (s *SchemaInspect) MarshalJSON() ([]byte, error) {
 			Collate string `json:"collate,omitempty"`
 		}
 		Column struct {
+			ID   string `json:"id"`
 			Name string `json:"name"`
 			Type string `json:"type,omitempty"`
 			Null bool   `json:"null,omitempty"`
@@ -815,27 +1316,33 @@ func (s *SchemaInspect) MarshalJSON() ([]byte, error) {
 			Expr   string `json:"expr,omitempty"`
 		}
 		Index struct {
+			ID     string      `json:"id"`
 			Name   string      `json:"name,omitempty"`
 			Unique bool        `json:"unique,omitempty"`
 			Parts  []IndexPart `json:"parts,omitempty"`
 		}
 		ForeignKey struct {
+			ID         string   `json:"id"`
 			Name       string   `json:"name"`
 			Columns    []string `json:"columns,omitempty"`
 			References struct {
+				ID      string   `json:"id"`
 				Table   string   `json:"table"`
 				Columns []string `json:"columns,omitempty"`
 			} `json:"references"`
 		}
 		Table struct {
+			ID          string       `json:"id"`
 			Name        string       `json:"name"`
 			Columns     []Column     `json:"columns,omitempty"`
 			Indexes     []Index      `json:"indexes,omitempty"`
 			PrimaryKey  *Index       `json:"primary_key,omitempty"`
 			ForeignKeys []ForeignKey `json:"foreign_keys,omitempty"`
+			Checksum    string       `json:"checksum,omitempty"`
 			Attrs
 		}
 		Schema struct {
+			ID     string  `json:"id"`
 			Name   string  `json:"name"`
 			Tables []Table `json:"tables,omitempty"`
 			Attrs
@@ -859,13 +1366,14 @@ func (s *SchemaInspect) MarshalJSON() ([]byte, error) {
 		}
 	)
 	for _, s1 := range s.Realm.Schemas {
-		s2 := Schema{Name: s1.Name}
+		s2 := Schema{ID: s1.Name, Name: s1.Name}
 		setAttrs(s1.Attrs, &s2.Attrs)
 		for _, t1 := range s1.Tables {
-			t2 := Table{Name: t1.Name}
+			t2 := Table{ID: qualifiedID(s1, t1.Name), Name: t1.Name, Checksum: schema.ChecksumOf(t1)}
 			setAttrs(t1.Attrs, &t2.Attrs)
 			for _, c1 := range t1.Columns {
 				c2 := Column{
+					ID:   columnID(t2.ID, c1.Name),
 					Name: c1.Name,
 					Type: c1.Type.Raw,
 					Null: c1.Type.Null,
@@ -893,20 +1401,21 @@ func (s *SchemaInspect) MarshalJSON() ([]byte, error) {
 			}
 			for _, idx1 := range t1.Indexes {
 				t2.Indexes = append(t2.Indexes, Index{
+					ID:     memberID(t2.ID, idx1.Name),
 					Name:   idx1.Name,
 					Unique: idx1.Unique,
 					Parts:  idxParts(idx1),
 				})
 			}
 			if t1.PrimaryKey != nil {
-				t2.PrimaryKey = &Index{Parts: idxParts(t1.PrimaryKey)}
+				t2.PrimaryKey = &Index{ID: primaryKeyID(t2.ID), Parts: idxParts(t1.PrimaryKey)}
 			}
 			for _, fk1 := range t1.ForeignKeys {
-				fk2 := ForeignKey{Name: fk1.Symbol}
+				fk2 := ForeignKey{ID: memberID(t2.ID, fk1.Symbol), Name: fk1.Symbol}
 				for _, c1 := range fk1.Columns {
 					fk2.Columns = append(fk2.Columns, c1.Name)
 				}
-				fk2.References.Table = fk1.RefTable.Name
+				fk2.References.ID, fk2.References.Table = tableID(fk1.RefTable), fk1.RefTable.Name
 				for _, c1 := range fk1.RefColumns {
 					fk2.References.Columns = append(fk2.References.Columns, c1.Name)
 				}
@@ -940,12 +1449,16 @@ type SchemaDiff struct {
 	client   *sqlclient.Client
 	From, To *schema.Realm
 	Changes  []schema.Change
+	// Explain indicates if the SQL representation of the diff should
+	// include the differences that triggered each of the changes.
+	Explain bool
 }
 
 var (
 	// SchemaDiffFuncs are global functions available in diff report templates.
 	SchemaDiffFuncs = template.FuncMap{
-		"sql": sqlDiff,
+		"sql":  sqlDiff,
+		"json": jsonEncode,
 	}
 	// SchemaDiffTemplate holds the default template of the 'schema diff' command.
 	SchemaDiffTemplate = template.Must(template.
@@ -957,6 +1470,11 @@ var (
 Schemas are synced, no changes to be made.
 {{ end -}}
 `))
+	// SchemaDiffJSONTemplate holds the template of the 'schema diff --format json' command.
+	SchemaDiffJSONTemplate = template.Must(template.
+				New("schema_diff_json").
+				Funcs(SchemaDiffFuncs).
+				Parse("{{ json . }}\n"))
 )
 
 // NewSchemaDiff returns a SchemaDiff.
@@ -979,6 +1497,9 @@ func (s *SchemaDiff) MarshalSQL(indent ...string) (string, error) {
 }
 
 func sqlDiff(diff *SchemaDiff, indent ...string) (string, error) {
+	if diff.Explain {
+		return fmtPlan(diff.ctx, diff.client, diff.Changes, indent, ExplainPlan(diff.client.Driver))
+	}
 	return fmtPlan(diff.ctx, diff.client, diff.Changes, indent)
 }
 
@@ -1010,6 +1531,44 @@ func fmtPlan(ctx context.Context, client *sqlclient.Client, changes schema.Chang
 	return string(f.Bytes()), nil
 }
 
+// SchemaLint contains a summary of the 'schema lint' command.
+type SchemaLint struct {
+	// URL of the linted schema.
+	URL     string            `json:"URL,omitempty"`
+	Reports []sqlcheck.Report `json:"Reports,omitempty"`
+	// Error holds the error returned by one of the analyzers, if any.
+	Error string `json:"Error,omitempty"`
+}
+
+var (
+	// SchemaLintFuncs are global functions available in lint report templates.
+	SchemaLintFuncs = template.FuncMap{
+		"json": jsonEncode,
+	}
+	// SchemaLintTemplate holds the default template of the 'schema lint' command.
+	SchemaLintTemplate = template.Must(template.
+				New("schema_lint").
+				Funcs(SchemaLintFuncs).
+				Parse(`{{- range $r := .Reports }}
+  {{- println $r.Text }}
+  {{- range $d := $r.Diagnostics }}
+    {{- printf "  -- %s: %s\n" $d.Code $d.Text }}
+  {{- end }}
+{{- else }}
+  {{- println "No issues were found in the schema." }}
+{{- end -}}
+`))
+)
+
+// DiagnosticsCount returns the total number of diagnostics in the report.
+func (s *SchemaLint) DiagnosticsCount() int {
+	var n int
+	for _, r := range s.Reports {
+		n += len(r.Diagnostics)
+	}
+	return n
+}
+
 func mermaid(i *SchemaInspect, _ ...string) (string, error) {
 	ft, ok := i.client.Driver.(interface {
 		FormatType(schema.Type) (string, error)
@@ -1093,6 +1652,66 @@ func mermaid(i *SchemaInspect, _ ...string) (string, error) {
 	return b.String(), nil
 }
 
+// dotInspect formats the inspected schema as an entity-relationship diagram in the Graphviz
+// DOT language. Tables are rendered as HTML-like nodes with a row per column, and foreign
+// keys as edges between the ports of their columns.
+func dotInspect(i *SchemaInspect, _ ...string) (string, error) {
+	var (
+		b       strings.Builder
+		f       schema.TypeFormatter
+		ids     = make(map[*schema.Table]string)
+		ports   = make(map[*schema.Column]string)
+		qualify = len(i.Realm.Schemas) > 1
+	)
+	if i.client != nil {
+		f, _ = i.client.Driver.(schema.TypeFormatter)
+	}
+	b.WriteString("digraph schema {\n  rankdir=LR;\n  node [shape=plaintext];\n")
+	for _, s := range i.Realm.Schemas {
+		for _, t := range s.Tables {
+			ids[t] = "n" + strconv.Itoa(len(ids))
+			name := t.Name
+			if qualify {
+				name = s.Name + "." + t.Name
+			}
+			fmt.Fprintf(&b, `  %s [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey"><b>%s</b></td></tr>`, ids[t], html.EscapeString(name))
+			for j, c := range t.Columns {
+				ports[c] = "p" + strconv.Itoa(j)
+				row := strings.TrimSpace(c.Name + " " + columnType(f, c))
+				var pkfk []string
+				if t.PrimaryKey != nil && slices.ContainsFunc(t.PrimaryKey.Parts, func(p *schema.IndexPart) bool { return p.C == c }) {
+					pkfk = append(pkfk, "PK")
+				}
+				if len(c.ForeignKeys) > 0 {
+					pkfk = append(pkfk, "FK")
+				}
+				if len(pkfk) > 0 {
+					row += " " + strings.Join(pkfk, ",")
+				}
+				fmt.Fprintf(&b, `<tr><td port="%s" align="left">%s</td></tr>`, ports[c], html.EscapeString(row))
+			}
+			b.WriteString("</table>>];\n")
+		}
+	}
+	for _, s := range i.Realm.Schemas {
+		for _, t := range s.Tables {
+			for _, fk := range t.ForeignKeys {
+				to, ok := ids[fk.RefTable]
+				if !ok || len(fk.Columns) == 0 || len(fk.RefColumns) == 0 || ports[fk.RefColumns[0]] == "" {
+					continue // Referenced table was not inspected.
+				}
+				fmt.Fprintf(&b, "  %s:%s -> %s:%s", ids[t], ports[fk.Columns[0]], to, ports[fk.RefColumns[0]])
+				if fk.Symbol != "" {
+					fmt.Fprintf(&b, " [label=%s]", strconv.Quote(fk.Symbol))
+				}
+				b.WriteString(";\n")
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
 func jsonEncode(v any, args ...string) (string, error) {
 	var (
 		b   []byte
@@ -17,6 +17,8 @@ type SchemaApply struct {
 	ctx context.Context `json:"-"`
 	Env
 	Changes Changes `json:"Changes,omitempty"`
+	// Stats of the applied statements.
+	Stats []*StmtStats `json:"Stats,omitempty"`
 	// General error that occurred during execution.
 	// e.g., when committing or rolling back a transaction.
 	Error string `json:"Error,omitempty"`
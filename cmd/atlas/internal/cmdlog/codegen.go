@@ -0,0 +1,137 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/go-openapi/inflect"
+)
+
+type (
+	// CodegenTable is the data passed to code generation templates.
+	// Templates are executed once per table of the inspected schema.
+	CodegenTable struct {
+		*schema.Table
+		Driver string // Driver name.
+		client *sqlclient.Client
+	}
+
+	// CodegenFile is a file rendered by a code generation template.
+	CodegenFile struct {
+		Name    string // Relative path of the file.
+		Content []byte // Rendered content.
+	}
+)
+
+// SQLType returns the SQL type of the given column, as formatted by the driver.
+func (t *CodegenTable) SQLType(c *schema.Column) string {
+	if c.Type == nil {
+		return ""
+	}
+	if t.client == nil || c.Type.Type == nil {
+		return c.Type.Raw
+	}
+	if f, ok := t.client.Driver.(schema.TypeFormatter); ok {
+		if s, err := f.FormatType(c.Type.Type); err == nil {
+			return s
+		}
+	}
+	return c.Type.Raw
+}
+
+// CodegenTemplateFuncs are global functions available in code generation templates.
+var CodegenTemplateFuncs = template.FuncMap{
+	"json":     jsonEncode,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"pascal":   inflect.Camelize,
+	"camel":    inflect.CamelizeDownFirst,
+	"snake":    inflect.Underscore,
+	"singular": inflect.Singularize,
+	"plural":   inflect.Pluralize,
+	"kind":     typeKind,
+}
+
+// typeKind returns the kind of the given column type, to allow templates
+// mapping database types to the types of the generated language.
+func typeKind(c *schema.Column) string {
+	if c.Type == nil {
+		return "unknown"
+	}
+	switch c.Type.Type.(type) {
+	case *schema.BoolType:
+		return "bool"
+	case *schema.IntegerType:
+		return "int"
+	case *schema.FloatType:
+		return "float"
+	case *schema.DecimalType:
+		return "decimal"
+	case *schema.StringType:
+		return "string"
+	case *schema.BinaryType:
+		return "binary"
+	case *schema.TimeType:
+		return "time"
+	case *schema.JSONType:
+		return "json"
+	case *schema.EnumType:
+		return "enum"
+	case *schema.UUIDType:
+		return "uuid"
+	case *schema.SpatialType:
+		return "spatial"
+	default:
+		return "unknown"
+	}
+}
+
+// RenderCodegen executes the code generation templates for every table in the
+// realm. The output file of template "<name>.tmpl" for table "users" is named
+// "users.<name>". For example, "model.go.tmpl" is rendered to "users.model.go".
+// In case the realm contains multiple schemas, files are grouped by schema
+// directories.
+func RenderCodegen(t *template.Template, c *sqlclient.Client, r *schema.Realm) ([]*CodegenFile, error) {
+	var (
+		files  []*CodegenFile
+		driver string
+		ts     = t.Templates()
+	)
+	if c != nil {
+		driver = c.Name
+	}
+	slices.SortFunc(ts, func(a, b *template.Template) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	for _, tt := range ts {
+		name, ok := strings.CutSuffix(tt.Name(), ".tmpl")
+		if !ok {
+			continue
+		}
+		for _, s := range r.Schemas {
+			for _, st := range s.Tables {
+				var b bytes.Buffer
+				if err := tt.Execute(&b, &CodegenTable{Table: st, Driver: driver, client: c}); err != nil {
+					return nil, fmt.Errorf("execute template %q on table %q: %w", tt.Name(), st.Name, err)
+				}
+				f := &CodegenFile{Name: st.Name + "." + name, Content: b.Bytes()}
+				if len(r.Schemas) > 1 {
+					f.Name = path.Join(s.Name, f.Name)
+				}
+				files = append(files, f)
+			}
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,542 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// ExplainPlan returns a function that annotates the comment of each planned change
+// with the differences between the current and the desired states that triggered it.
+// For example:
+//
+//	-- Modify "users" table
+//	--   column "id" type: current int, desired bigint
+//	ALTER TABLE `users` MODIFY COLUMN `id` bigint NOT NULL;
+func ExplainPlan(drv migrate.Driver) func(*migrate.Plan) {
+	e := newExplainer(drv)
+	return func(p *migrate.Plan) {
+		seen := make(map[schema.Change]bool)
+		for _, c := range p.Changes {
+			// Changes that were planned into multiple
+			// statements are explained only once.
+			if c.Source == nil || seen[c.Source] {
+				continue
+			}
+			seen[c.Source] = true
+			lines := e.explain(c.Source)
+			if len(lines) == 0 {
+				continue
+			}
+			if c.Comment == "" {
+				c.Comment = "planned because:"
+			}
+			for _, l := range lines {
+				c.Comment += "\n--   " + l
+			}
+		}
+	}
+}
+
+// explainer explains why schema changes were planned.
+type explainer struct {
+	fmtType func(schema.Type) (string, error)
+}
+
+// newExplainer returns an explainer that formats column types using the given driver, if it supports it.
+func newExplainer(drv migrate.Driver) *explainer {
+	e := &explainer{}
+	if ft, ok := drv.(interface {
+		FormatType(schema.Type) (string, error)
+	}); ok {
+		e.fmtType = ft.FormatType
+	}
+	return e
+}
+
+// explain returns the differences that triggered the given change.
+func (e *explainer) explain(c schema.Change) []string {
+	switch c := c.(type) {
+	case *schema.AddSchema:
+		return []string{missing("schema", c.S.Name)}
+	case *schema.DropSchema:
+		return []string{undefined("schema", c.S.Name)}
+	case *schema.ModifySchema:
+		return e.explainAll(c.Changes)
+	case *schema.AddTable:
+		return []string{missing("table", c.T.Name)}
+	case *schema.DropTable:
+		return []string{undefined("table", c.T.Name)}
+	case *schema.RenameTable:
+		return []string{renamed("table", c.From.Name, c.To.Name)}
+	case *schema.ModifyTable:
+		return e.explainAll(c.Changes)
+	case *schema.AddView:
+		return []string{missing("view", c.V.Name)}
+	case *schema.DropView:
+		return []string{undefined("view", c.V.Name)}
+	case *schema.RenameView:
+		return []string{renamed("view", c.From.Name, c.To.Name)}
+	case *schema.ModifyView:
+		var lines []string
+		if c.From.Def != c.To.Def {
+			lines = append(lines, changed("view", c.From.Name, "definition", snippet(c.From.Def, c.To.Def)))
+		}
+		return append(lines, e.explainAll(c.Changes)...)
+	case *schema.AddFunc:
+		return []string{missing("function", c.F.Name)}
+	case *schema.DropFunc:
+		return []string{undefined("function", c.F.Name)}
+	case *schema.RenameFunc:
+		return []string{renamed("function", c.From.Name, c.To.Name)}
+	case *schema.ModifyFunc:
+		lines := e.explainRoutine("function", c.From.Name, c.From.Args, c.To.Args, c.From.Body, c.To.Body, c.From.Lang, c.To.Lang)
+		if c.From.Ret != nil && c.To.Ret != nil {
+			if from, to := e.typeString(c.From.Ret), e.typeString(c.To.Ret); from != to {
+				lines = append(lines, changed("function", c.From.Name, "return type", from, to))
+			}
+		}
+		return append(lines, e.explainAll(c.Changes)...)
+	case *schema.AddProc:
+		return []string{missing("procedure", c.P.Name)}
+	case *schema.DropProc:
+		return []string{undefined("procedure", c.P.Name)}
+	case *schema.RenameProc:
+		return []string{renamed("procedure", c.From.Name, c.To.Name)}
+	case *schema.ModifyProc:
+		lines := e.explainRoutine("procedure", c.From.Name, c.From.Args, c.To.Args, c.From.Body, c.To.Body, c.From.Lang, c.To.Lang)
+		return append(lines, e.explainAll(c.Changes)...)
+	case *schema.AddTrigger:
+		return []string{missing("trigger", c.T.Name)}
+	case *schema.DropTrigger:
+		return []string{undefined("trigger", c.T.Name)}
+	case *schema.RenameTrigger:
+		return []string{renamed("trigger", c.From.Name, c.To.Name)}
+	case *schema.ModifyTrigger:
+		var lines []string
+		if c.From.Body != c.To.Body {
+			lines = append(lines, changed("trigger", c.From.Name, "body", snippet(c.From.Body, c.To.Body)))
+		}
+		return append(lines, e.explainAll(c.Changes)...)
+	case *schema.AddObject:
+		typ, name := objectName(c.O)
+		return []string{missing(typ, name)}
+	case *schema.DropObject:
+		typ, name := objectName(c.O)
+		return []string{undefined(typ, name)}
+	case *schema.ModifyObject:
+		typ, name := objectName(c.From)
+		return fieldChanges(typ, name, c.From, c.To)
+	case *schema.AddColumn:
+		return []string{missing("column", c.C.Name)}
+	case *schema.DropColumn:
+		return []string{undefined("column", c.C.Name)}
+	case *schema.RenameColumn:
+		return []string{renamed("column", c.From.Name, c.To.Name)}
+	case *schema.ModifyColumn:
+		return e.explainColumn(c)
+	case *schema.AddIndex:
+		return []string{missing("index", c.I.Name)}
+	case *schema.DropIndex:
+		return []string{undefined("index", c.I.Name)}
+	case *schema.RenameIndex:
+		return []string{renamed("index", c.From.Name, c.To.Name)}
+	case *schema.ModifyIndex:
+		return e.explainIndex(c)
+	case *schema.AddForeignKey:
+		return []string{missing("foreign key", c.F.Symbol)}
+	case *schema.DropForeignKey:
+		return []string{undefined("foreign key", c.F.Symbol)}
+	case *schema.ModifyForeignKey:
+		return explainForeignKey(c)
+	case *schema.AddCheck:
+		return []string{missing("check", c.C.Name)}
+	case *schema.DropCheck:
+		return []string{undefined("check", c.C.Name)}
+	case *schema.ModifyCheck:
+		var lines []string
+		if c.From.Expr != c.To.Expr {
+			lines = append(lines, changed("check", c.From.Name, "expression", snippet(c.From.Expr, c.To.Expr)))
+		}
+		return append(lines, attrChanges("check", c.From.Name, c.From.Attrs, c.To.Attrs)...)
+	case *schema.AddAttr:
+		return []string{fmt.Sprintf("%s is missing in the current state (desired %s)", attrName(c.A), attrValue(c.A))}
+	case *schema.DropAttr:
+		return []string{fmt.Sprintf("%s is not defined in the desired state (current %s)", attrName(c.A), attrValue(c.A))}
+	case *schema.ModifyAttr:
+		return []string{fmt.Sprintf("%s: current %s, desired %s", attrName(c.To), attrValue(c.From), attrValue(c.To))}
+	default:
+		return nil
+	}
+}
+
+// explainAll explains the given changes.
+func (e *explainer) explainAll(changes []schema.Change) (lines []string) {
+	for _, c := range changes {
+		lines = append(lines, e.explain(c)...)
+	}
+	return lines
+}
+
+// explainColumn explains a column modification.
+func (e *explainer) explainColumn(c *schema.ModifyColumn) []string {
+	var (
+		lines    []string
+		name     = c.From.Name
+		from, to = c.From, c.To
+	)
+	if c.Change.Is(schema.ChangeType) {
+		lines = append(lines, changed("column", name, "type", e.typeString(from.Type.Type), e.typeString(to.Type.Type)))
+	}
+	if c.Change.Is(schema.ChangeNull) {
+		lines = append(lines, changed("column", name, "nullability", nullString(from.Type.Null), nullString(to.Type.Null)))
+	}
+	if c.Change.Is(schema.ChangeDefault) {
+		lines = append(lines, changed("column", name, "default", exprString(from.Default), exprString(to.Default)))
+	}
+	for _, k := range []struct {
+		kind   schema.ChangeKind
+		aspect string
+		attr   schema.Attr
+	}{
+		{schema.ChangeGenerated, "generated expression", &schema.GeneratedExpr{}},
+		{schema.ChangeComment, "comment", &schema.Comment{}},
+		{schema.ChangeCharset, "charset", &schema.Charset{}},
+		{schema.ChangeCollate, "collation", &schema.Collation{}},
+	} {
+		if c.Change.Is(k.kind) {
+			t := reflect.TypeOf(k.attr)
+			lines = append(lines, changed("column", name, k.aspect, attrValue(findAttr(from.Attrs, t)), attrValue(findAttr(to.Attrs, t))))
+		}
+	}
+	if c.Change.Is(schema.ChangeAttr) {
+		lines = append(lines, attrChanges("column", name, from.Attrs, to.Attrs)...)
+	}
+	return lines
+}
+
+// explainIndex explains an index modification.
+func (e *explainer) explainIndex(c *schema.ModifyIndex) []string {
+	var (
+		lines    []string
+		name     = c.From.Name
+		from, to = c.From, c.To
+	)
+	if c.Change.Is(schema.ChangeUnique) {
+		lines = append(lines, changed("index", name, "uniqueness", strconv.FormatBool(from.Unique), strconv.FormatBool(to.Unique)))
+	}
+	if c.Change.Is(schema.ChangeParts) {
+		lines = append(lines, changed("index", name, "parts", partsString(from.Parts), partsString(to.Parts)))
+	}
+	if c.Change.Is(schema.ChangeComment) || c.Change.Is(schema.ChangeAttr) {
+		lines = append(lines, attrChanges("index", name, from.Attrs, to.Attrs)...)
+	}
+	return lines
+}
+
+// explainForeignKey explains a foreign-key modification.
+func explainForeignKey(c *schema.ModifyForeignKey) []string {
+	var (
+		lines    []string
+		name     = c.From.Symbol
+		from, to = c.From, c.To
+	)
+	if c.Change.Is(schema.ChangeColumn) {
+		lines = append(lines, changed("foreign key", name, "columns", columnsString(from.Columns), columnsString(to.Columns)))
+	}
+	if c.Change.Is(schema.ChangeRefTable) && from.RefTable != nil && to.RefTable != nil {
+		lines = append(lines, changed("foreign key", name, "referenced table", quote(from.RefTable.Name), quote(to.RefTable.Name)))
+	}
+	if c.Change.Is(schema.ChangeRefColumn) {
+		lines = append(lines, changed("foreign key", name, "referenced columns", columnsString(from.RefColumns), columnsString(to.RefColumns)))
+	}
+	if c.Change.Is(schema.ChangeUpdateAction) {
+		lines = append(lines, changed("foreign key", name, "on update", string(from.OnUpdate), string(to.OnUpdate)))
+	}
+	if c.Change.Is(schema.ChangeDeleteAction) {
+		lines = append(lines, changed("foreign key", name, "on delete", string(from.OnDelete), string(to.OnDelete)))
+	}
+	return lines
+}
+
+// explainRoutine explains a function or procedure modification.
+func (e *explainer) explainRoutine(typ, name string, fromArgs, toArgs []*schema.FuncArg, fromBody, toBody, fromLang, toLang string) []string {
+	var lines []string
+	if from, to := e.argsString(fromArgs), e.argsString(toArgs); from != to {
+		lines = append(lines, changed(typ, name, "arguments", from, to))
+	}
+	if fromLang != toLang {
+		lines = append(lines, changed(typ, name, "language", quote(fromLang), quote(toLang)))
+	}
+	if fromBody != toBody {
+		lines = append(lines, changed(typ, name, "body", snippet(fromBody, toBody)))
+	}
+	return lines
+}
+
+func (e *explainer) typeString(t schema.Type) string {
+	if t == nil {
+		return "none"
+	}
+	if e.fmtType != nil {
+		if s, err := e.fmtType(t); err == nil {
+			return s
+		}
+	}
+	// Most types hold their name in the T field.
+	if v := reflect.Indirect(reflect.ValueOf(t)); v.Kind() == reflect.Struct {
+		if f := v.FieldByName("T"); f.IsValid() && f.Kind() == reflect.String {
+			return f.String()
+		}
+	}
+	return fmt.Sprintf("%+v", t)
+}
+
+func (e *explainer) argsString(args []*schema.FuncArg) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		parts[i] = strings.TrimSpace(a.Name + " " + e.typeString(a.Type))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// changed formats a change of an element aspect. If a single
+// value is given, it is assumed to be already formatted.
+func changed(typ, name, aspect string, values ...string) string {
+	if len(values) == 1 {
+		return fmt.Sprintf("%s %q %s: %s", typ, name, aspect, values[0])
+	}
+	return fmt.Sprintf("%s %q %s: current %s, desired %s", typ, name, aspect, values[0], values[1])
+}
+
+func missing(typ, name string) string {
+	return fmt.Sprintf("%s %q is missing in the current state", typ, name)
+}
+
+func undefined(typ, name string) string {
+	return fmt.Sprintf("%s %q is not defined in the desired state", typ, name)
+}
+
+func renamed(typ, from, to string) string {
+	return fmt.Sprintf("%s %q is named %q in the desired state", typ, from, to)
+}
+
+// snippetSize is the max size of text shown around the first difference of two strings.
+const snippetSize = 40
+
+// snippet formats the first difference between two (possibly long) texts.
+func snippet(from, to string) string {
+	i := 0
+	for i < len(from) && i < len(to) && from[i] == to[i] {
+		i++
+	}
+	cut := func(s string) string {
+		start, end := max(0, i-snippetSize/2), min(len(s), i+snippetSize/2)
+		v := s[start:end]
+		if start > 0 {
+			v = "..." + v
+		}
+		if end < len(s) {
+			v += "..."
+		}
+		return strconv.Quote(v)
+	}
+	return fmt.Sprintf("current %s, desired %s (differ at offset %d)", cut(from), cut(to), i)
+}
+
+// attrChanges returns the attributes that were changed between the two lists.
+func attrChanges(typ, name string, from, to []schema.Attr) []string {
+	var (
+		lines []string
+		seen  = make(map[reflect.Type]bool)
+	)
+	for _, a := range append(append([]schema.Attr{}, from...), to...) {
+		t := reflect.TypeOf(a)
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		f, t1 := findAttr(from, t), findAttr(to, t)
+		if reflect.DeepEqual(f, t1) {
+			continue
+		}
+		lines = append(lines, changed(typ, name, attrName(a), attrValue(f), attrValue(t1)))
+	}
+	return lines
+}
+
+func findAttr(attrs []schema.Attr, t reflect.Type) schema.Attr {
+	for _, a := range attrs {
+		if reflect.TypeOf(a) == t {
+			return a
+		}
+	}
+	return nil
+}
+
+func attrName(a schema.Attr) string {
+	switch a.(type) {
+	case *schema.Comment:
+		return "comment"
+	case *schema.Charset:
+		return "charset"
+	case *schema.Collation:
+		return "collation"
+	case *schema.Check:
+		return "check"
+	default:
+		return typeName(a)
+	}
+}
+
+func attrValue(a schema.Attr) string {
+	switch a := a.(type) {
+	case nil:
+		return "none"
+	case *schema.Comment:
+		return quote(a.Text)
+	case *schema.Charset:
+		return quote(a.V)
+	case *schema.Collation:
+		return quote(a.V)
+	case *schema.Check:
+		return quote(a.Expr)
+	case *schema.GeneratedExpr:
+		return quote(a.Expr)
+	default:
+		return valueString(reflect.ValueOf(a))
+	}
+}
+
+// fieldChanges returns the exported fields that were changed between two objects.
+func fieldChanges(typ, name string, from, to any) []string {
+	fv, tv := reflect.Indirect(reflect.ValueOf(from)), reflect.Indirect(reflect.ValueOf(to))
+	if fv.Kind() != reflect.Struct || fv.Type() != tv.Type() {
+		return []string{fmt.Sprintf("%s %q was changed", typ, name)}
+	}
+	var lines []string
+	for i := 0; i < fv.NumField(); i++ {
+		f := fv.Type().Field(i)
+		// Skip unexported fields and references to other objects.
+		if !f.IsExported() || f.Name == "Deps" || f.Name == "Refs" {
+			continue
+		}
+		if x, y := fv.Field(i).Interface(), tv.Field(i).Interface(); !reflect.DeepEqual(x, y) {
+			lines = append(lines, changed(typ, name, strings.ToLower(f.Name), valueString(fv.Field(i)), valueString(tv.Field(i))))
+		}
+	}
+	return lines
+}
+
+// objectName returns the type and the name of the given object.
+func objectName(o schema.Object) (string, string) {
+	var name string
+	if v := reflect.Indirect(reflect.ValueOf(o)); v.Kind() == reflect.Struct {
+		if f := v.FieldByName("Name"); f.IsValid() && f.Kind() == reflect.String {
+			name = f.String()
+		}
+	}
+	return typeName(o), name
+}
+
+// typeName returns the lower-cased name of the value type.
+func typeName(v any) string {
+	return strings.ToLower(reflect.Indirect(reflect.ValueOf(v)).Type().Name())
+}
+
+// valueString formats simple values, and summarizes complex ones.
+func valueString(v reflect.Value) string {
+	switch v = reflect.Indirect(v); v.Kind() {
+	case reflect.Invalid:
+		return "none"
+	case reflect.String:
+		return quote(v.String())
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface())
+	case reflect.Slice:
+		return fmt.Sprintf("%d item(s)", v.Len())
+	case reflect.Interface:
+		if x, ok := v.Interface().(schema.Expr); ok {
+			return exprString(x)
+		}
+		return valueString(v.Elem())
+	case reflect.Struct:
+		// Structs holding a single value (e.g., attributes) are
+		// formatted by their value. Other structs are summarized.
+		var fields []reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() && !f.Anonymous {
+				fields = append(fields, v.Field(i))
+			}
+		}
+		if len(fields) == 1 {
+			return valueString(fields[0])
+		}
+	}
+	return "(" + v.Type().Name() + ")"
+}
+
+func exprString(x schema.Expr) string {
+	switch x := x.(type) {
+	case nil:
+		return "none"
+	case *schema.Literal:
+		return x.V
+	case *schema.RawExpr:
+		return x.X
+	default:
+		return fmt.Sprintf("%+v", x)
+	}
+}
+
+func nullString(null bool) string {
+	if null {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+func partsString(parts []*schema.IndexPart) string {
+	return "(" + strings.Join(partsList(parts), ", ") + ")"
+}
+
+func partsList(parts []*schema.IndexPart) []string {
+	s := make([]string, len(parts))
+	for i, p := range parts {
+		switch {
+		case p.C != nil:
+			s[i] = p.C.Name
+		case p.X != nil:
+			s[i] = exprString(p.X)
+		}
+		if p.Desc {
+			s[i] += " DESC"
+		}
+	}
+	return s
+}
+
+func columnsString(columns []*schema.Column) string {
+	return "(" + strings.Join(columnsList(columns), ", ") + ")"
+}
+
+func columnsList(columns []*schema.Column) []string {
+	s := make([]string, len(columns))
+	for i, c := range columns {
+		s[i] = c.Name
+	}
+	return s
+}
+
+func quote(s string) string {
+	return strconv.Quote(s)
+}
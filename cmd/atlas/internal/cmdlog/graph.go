@@ -0,0 +1,309 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// SchemaGraph contains a summary of the 'schema graph' command. The nodes are the
+	// schema objects (tables, views, functions, etc.), and the edges are the dependencies
+	// between them. The nodes are identified by the stable identifiers used in the JSON
+	// outputs of other commands (e.g., "public.users").
+	SchemaGraph struct {
+		Nodes []*GraphNode `json:"nodes"`
+		Edges []*GraphEdge `json:"edges,omitempty"`
+	}
+
+	// GraphNode represents a schema object in the graph.
+	GraphNode struct {
+		ID   string `json:"id"`   // Object identifier.
+		Type string `json:"type"` // Object type. For example, table or view.
+		name string // Unqualified name of the object.
+	}
+
+	// GraphEdge represents a dependency between two objects. An edge from A to B
+	// means A depends on B. Hence, changing B might affect A.
+	GraphEdge struct {
+		From  string `json:"from"`            // Identifier of the dependent object.
+		To    string `json:"to"`              // Identifier of the dependency.
+		Type  string `json:"type"`            // One of: foreign_key or depends_on.
+		Label string `json:"label,omitempty"` // Constraint name of foreign keys.
+		from  *GraphNode
+		to    *GraphNode
+	}
+)
+
+// Graph edge types.
+const (
+	EdgeForeignKey = "foreign_key"
+	EdgeDependsOn  = "depends_on"
+)
+
+var (
+	// GraphTemplateFuncs are global functions available in graph templates.
+	GraphTemplateFuncs = template.FuncMap{
+		"dot":     graphDOT,
+		"mermaid": graphMermaid,
+		"json":    jsonEncode,
+	}
+
+	// SchemaGraphTemplate holds the default template of the 'schema graph' command.
+	SchemaGraphTemplate = template.Must(template.New("graph").
+				Funcs(GraphTemplateFuncs).
+				Parse(`{{ dot . }}`))
+)
+
+// NewSchemaGraph returns the dependency graph of the given realm. Objects depend on the
+// tables referenced by their foreign keys, on the objects they were found to depend on
+// during inspection (e.g., the tables used by a view), and on the types of their columns.
+func NewSchemaGraph(r *schema.Realm) *SchemaGraph {
+	var (
+		g     = &SchemaGraph{}
+		nodes = make(map[schema.Object]*GraphNode)
+		node  = func(o schema.Object, typ, id, name string) {
+			n := &GraphNode{ID: id, Type: typ, name: name}
+			nodes[o] = n
+			g.Nodes = append(g.Nodes, n)
+		}
+	)
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			node(t, "table", tableID(t), t.Name)
+		}
+		for _, v := range s.Views {
+			typ := "view"
+			if v.Materialized() {
+				typ = "materialized_view"
+			}
+			node(v, typ, viewID(v), v.Name)
+		}
+		for _, f := range s.Funcs {
+			node(f, "function", qualifiedID(f.Schema, f.Name), f.Name)
+		}
+		for _, p := range s.Procs {
+			node(p, "procedure", qualifiedID(p.Schema, p.Name), p.Name)
+		}
+		for _, o := range s.Objects {
+			typ, name := objectName(o)
+			node(o, typ, name, name)
+		}
+	}
+	// lookup returns the node of the given object. Objects of other states (e.g., loaded
+	// from another source) are matched by their identifiers.
+	lookup := func(o schema.Object) *GraphNode {
+		if n, ok := nodes[o]; ok {
+			return n
+		}
+		var typ, id string
+		switch o := o.(type) {
+		case *schema.Table:
+			typ, id = "table", tableID(o)
+		case *schema.View:
+			typ, id = "view", viewID(o)
+		case *schema.Func:
+			typ, id = "function", qualifiedID(o.Schema, o.Name)
+		case *schema.Proc:
+			typ, id = "procedure", qualifiedID(o.Schema, o.Name)
+		default:
+			return nil
+		}
+		i := slices.IndexFunc(g.Nodes, func(n *GraphNode) bool {
+			return n.ID == id && strings.HasSuffix(n.Type, typ)
+		})
+		if i == -1 {
+			return nil
+		}
+		return g.Nodes[i]
+	}
+	edge := func(from *GraphNode, o schema.Object, typ, label string) {
+		to := lookup(o)
+		if from == nil || to == nil || from == to {
+			return
+		}
+		if slices.ContainsFunc(g.Edges, func(e *GraphEdge) bool {
+			return e.from == from && e.to == to && e.Type == typ && e.Label == label
+		}) {
+			return
+		}
+		g.Edges = append(g.Edges, &GraphEdge{From: from.ID, To: to.ID, Type: typ, Label: label, from: from, to: to})
+	}
+	deps := func(from *GraphNode, deps []schema.Object) {
+		for _, d := range deps {
+			edge(from, d, EdgeDependsOn, "")
+		}
+	}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			n := nodes[t]
+			for _, fk := range t.ForeignKeys {
+				edge(n, fk.RefTable, EdgeForeignKey, fk.Symbol)
+			}
+			for _, c := range t.Columns {
+				if c.Type == nil {
+					continue
+				}
+				if o, ok := schema.UnderlyingType(c.Type.Type).(schema.Object); ok {
+					edge(n, o, EdgeDependsOn, "")
+				}
+			}
+			deps(n, t.Deps)
+			for _, tr := range t.Triggers {
+				deps(n, tr.Deps)
+			}
+		}
+		for _, v := range s.Views {
+			n := nodes[v]
+			deps(n, v.Deps)
+			for _, tr := range v.Triggers {
+				deps(n, tr.Deps)
+			}
+		}
+		for _, f := range s.Funcs {
+			deps(nodes[f], f.Deps)
+		}
+		for _, p := range s.Procs {
+			deps(nodes[p], p.Deps)
+		}
+	}
+	return g
+}
+
+// Focus returns the subgraph of the objects matching the given filters, and the objects
+// connected to them (in both directions) up to the given depth. A zero or negative depth
+// means no limit. Filters are formatted as "type=name" (e.g., "table=users") or "name",
+// where names can be qualified with their schema names (e.g., "public.users").
+func (g *SchemaGraph) Focus(filters []string, depth int) (*SchemaGraph, error) {
+	dist := make(map[*GraphNode]int)
+	var queue []*GraphNode
+	for _, f := range filters {
+		typ, name, ok := strings.Cut(f, "=")
+		if !ok {
+			typ, name = "", f
+		}
+		var matched bool
+		for _, n := range g.Nodes {
+			if (typ == "" || n.Type == typ) && (n.ID == name || n.name == name) {
+				if _, ok := dist[n]; !ok {
+					dist[n] = 0
+					queue = append(queue, n)
+				}
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no objects match the focus filter %q", f)
+		}
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if depth > 0 && dist[n] >= depth {
+			continue
+		}
+		for _, e := range g.Edges {
+			var next *GraphNode
+			switch n {
+			case e.from:
+				next = e.to
+			case e.to:
+				next = e.from
+			default:
+				continue
+			}
+			if _, ok := dist[next]; !ok {
+				dist[next] = dist[n] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	sub := &SchemaGraph{}
+	for _, n := range g.Nodes {
+		if _, ok := dist[n]; ok {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		_, ok1 := dist[e.from]
+		_, ok2 := dist[e.to]
+		if ok1 && ok2 {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub, nil
+}
+
+// label returns the display label of the node.
+func (n *GraphNode) label() string {
+	if n.Type == "table" {
+		return n.ID
+	}
+	return fmt.Sprintf("%s (%s)", n.ID, strings.ReplaceAll(n.Type, "_", " "))
+}
+
+// graphDOT formats the graph in the Graphviz DOT language.
+func graphDOT(g *SchemaGraph) string {
+	var (
+		b   strings.Builder
+		ids = make(map[*GraphNode]string, len(g.Nodes))
+	)
+	b.WriteString("digraph schema {\n  rankdir=LR;\n  node [shape=box];\n")
+	for i, n := range g.Nodes {
+		ids[n] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(&b, "  %s [label=%s", ids[n], strconv.Quote(n.label()))
+		if n.Type != "table" {
+			b.WriteString(", style=rounded")
+		}
+		b.WriteString("];\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s", ids[e.from], ids[e.to])
+		switch {
+		case e.Type == EdgeDependsOn:
+			b.WriteString(" [style=dashed]")
+		case e.Label != "":
+			fmt.Fprintf(&b, " [label=%s]", strconv.Quote(e.Label))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// graphMermaid formats the graph as a Mermaid flowchart.
+func graphMermaid(g *SchemaGraph) string {
+	var (
+		b   strings.Builder
+		ids = make(map[*GraphNode]string, len(g.Nodes))
+		esc = strings.NewReplacer(`"`, "#quot;").Replace
+	)
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n] = "n" + strconv.Itoa(i)
+		if n.Type == "table" {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n], esc(n.label()))
+		} else {
+			fmt.Fprintf(&b, "  %s(\"%s\")\n", ids[n], esc(n.label()))
+		}
+	}
+	for _, e := range g.Edges {
+		switch {
+		case e.Type == EdgeDependsOn:
+			fmt.Fprintf(&b, "  %s -.-> %s\n", ids[e.from], ids[e.to])
+		case e.Label != "":
+			fmt.Fprintf(&b, "  %s -- \"%s\" --> %s\n", ids[e.from], esc(e.Label), ids[e.to])
+		default:
+			fmt.Fprintf(&b, "  %s --> %s\n", ids[e.from], ids[e.to])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// jsonSchemaDialect is the JSON Schema dialect of the generated documents.
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+type (
+	// jsonSchema describes a JSON Schema document or one of its subschemas.
+	jsonSchema struct {
+		Schema          string     `json:"$schema,omitempty"`
+		Defs            *jsonProps `json:"$defs,omitempty"`
+		Title           string     `json:"title,omitempty"`
+		Description     string     `json:"description,omitempty"`
+		Type            any        `json:"type,omitempty"`
+		Format          string     `json:"format,omitempty"`
+		ContentEncoding string     `json:"contentEncoding,omitempty"`
+		MaxLength       int        `json:"maxLength,omitempty"`
+		Minimum         *int       `json:"minimum,omitempty"`
+		Enum            []any      `json:"enum,omitempty"`
+		Properties      *jsonProps `json:"properties,omitempty"`
+		Required        []string   `json:"required,omitempty"`
+		Additional      *bool      `json:"additionalProperties,omitempty"`
+	}
+	// jsonProps is an ordered set of named subschemas.
+	jsonProps struct {
+		names   []string
+		schemas []*jsonSchema
+	}
+)
+
+// add appends a named subschema to the set.
+func (p *jsonProps) add(name string, s *jsonSchema) {
+	p.names = append(p.names, name)
+	p.schemas = append(p.schemas, s)
+}
+
+// MarshalJSON implements json.Marshaler. Unlike maps, the
+// subschemas are encoded in the order they were added.
+func (p *jsonProps) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, n := range p.names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		k, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(p.schemas[i])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// jsonSchemaInspect returns a JSON Schema document describing the rows of the inspected
+// tables. Each table is defined as a subschema under "$defs", and tables are qualified
+// with their schema name in case the realm contains more than one schema.
+func jsonSchemaInspect(i *SchemaInspect, indent ...string) (string, error) {
+	var (
+		doc     = &jsonSchema{Schema: jsonSchemaDialect, Defs: &jsonProps{}}
+		qualify = len(i.Realm.Schemas) > 1
+	)
+	for _, s := range i.Realm.Schemas {
+		for _, t := range s.Tables {
+			name := t.Name
+			if qualify {
+				name = s.Name + "." + t.Name
+			}
+			doc.Defs.add(name, tableJSONSchema(t))
+		}
+	}
+	return jsonEncode(doc, indent...)
+}
+
+// tableJSONSchema returns the JSON Schema of the given table rows.
+func tableJSONSchema(t *schema.Table) *jsonSchema {
+	s := &jsonSchema{
+		Title:       t.Name,
+		Type:        "object",
+		Description: comment(t.Attrs),
+		Properties:  &jsonProps{},
+		Additional:  new(bool),
+	}
+	for _, c := range t.Columns {
+		s.Properties.add(c.Name, columnJSONSchema(c))
+		if c.Type != nil && !c.Type.Null {
+			s.Required = append(s.Required, c.Name)
+		}
+	}
+	return s
+}
+
+// columnJSONSchema returns the JSON Schema of the given column values.
+func columnJSONSchema(c *schema.Column) *jsonSchema {
+	s := &jsonSchema{Description: comment(c.Attrs)}
+	if c.Type == nil {
+		return s
+	}
+	switch t := c.Type.Type.(type) {
+	case *schema.BoolType:
+		s.Type = "boolean"
+	case *schema.IntegerType:
+		s.Type = "integer"
+		if t.Unsigned {
+			s.Minimum = new(int)
+		}
+	case *schema.DecimalType, *schema.FloatType:
+		s.Type = "number"
+	case *schema.StringType:
+		s.Type, s.MaxLength = "string", t.Size
+	case *schema.EnumType:
+		s.Type = "string"
+		for _, v := range t.Values {
+			s.Enum = append(s.Enum, v)
+		}
+		if c.Type.Null {
+			s.Enum = append(s.Enum, nil)
+		}
+	case *schema.BinaryType:
+		s.Type, s.ContentEncoding = "string", "base64"
+	case *schema.UUIDType:
+		s.Type, s.Format = "string", "uuid"
+	case *schema.TimeType:
+		s.Type, s.Format = "string", timeFormat(t.T)
+	default:
+		// JSON, spatial and driver-specific types
+		// are not restricted to a specific JSON type.
+		return s
+	}
+	if c.Type.Null {
+		s.Type = []string{s.Type.(string), "null"}
+	}
+	return s
+}
+
+// timeFormat returns the JSON Schema format of the given time type.
+func timeFormat(t string) string {
+	switch t = strings.ToLower(t); {
+	case strings.HasPrefix(t, "timestamp"), strings.HasPrefix(t, "datetime"):
+		return "date-time"
+	case strings.HasPrefix(t, "date"):
+		return "date"
+	case strings.HasPrefix(t, "time"):
+		return "time"
+	default:
+		return "date-time"
+	}
+}
+
+// comment returns the comment of the given attributes, if exists.
+func comment(attrs []schema.Attr) string {
+	for _, a := range attrs {
+		if c, ok := a.(*schema.Comment); ok {
+			return c.Text
+		}
+	}
+	return ""
+}
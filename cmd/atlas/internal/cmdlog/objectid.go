@@ -0,0 +1,421 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Objects in JSON outputs are identified by stable identifiers, which are derived only
+// from object names. Hence, the same object gets the same identifier across runs, and
+// the outputs of different runs can be diffed. The identifiers are formatted as follows:
+//
+//	public                     schema
+//	public.users               table or view. Unqualified if the schema is unknown.
+//	public.users.id            column
+//	public.users#users_name    index, foreign key, check or trigger
+//	public.users#primary_key   primary key
+//	public.users@comment       attribute
+//
+// Functions and procedures are qualified like tables, and other objects (e.g., enums)
+// are identified by their names.
+func qualifiedID(s *schema.Schema, name string) string {
+	if s == nil || s.Name == "" {
+		return name
+	}
+	return s.Name + "." + name
+}
+
+func tableID(t *schema.Table) string { return qualifiedID(t.Schema, t.Name) }
+
+func viewID(v *schema.View) string { return qualifiedID(v.Schema, v.Name) }
+
+func columnID(owner, name string) string { return owner + "." + name }
+
+func memberID(owner, name string) string { return owner + "#" + name }
+
+func primaryKeyID(owner string) string { return memberID(owner, "primary_key") }
+
+func attrID(owner string, a schema.Attr) string { return owner + "@" + attrName(a) }
+
+// triggerID returns the identifier of a table or view trigger.
+func triggerID(t *schema.Trigger) string {
+	switch {
+	case t.Table != nil:
+		return memberID(tableID(t.Table), t.Name)
+	case t.View != nil:
+		return memberID(viewID(t.View), t.Name)
+	default:
+		return t.Name
+	}
+}
+
+// MarshalJSON implements json.Marshaler. The changes are encoded in their planning order,
+// and each change holds its operation, the type and the identifier of the changed object:
+//
+//	{
+//	  "changes": [
+//	    {"op": "add", "type": "table", "id": "public.users"},
+//	    {"op": "modify", "type": "table", "id": "public.posts", "changes": [
+//	      {"op": "rename", "type": "column", "id": "public.posts.title", "from": "public.posts.name"},
+//	      {"op": "modify", "type": "column", "id": "public.posts.id", "attrs": {
+//	        "type": {"current": "int", "desired": "bigint"}
+//	      }}
+//	    ]}
+//	  ]
+//	}
+//
+// Added and dropped columns, indexes, foreign keys and checks hold their (desired or
+// current) attributes, and modified ones hold the current and desired values of the
+// attributes that were changed.
+func (s *SchemaDiff) MarshalJSON() ([]byte, error) {
+	var (
+		v struct {
+			Changes []*changeJSON `json:"changes,omitempty"`
+		}
+		drv migrate.Driver
+	)
+	if s.client != nil {
+		drv = s.client.Driver
+	}
+	v.Changes = newExplainer(drv).encodeChanges("", s.Changes)
+	return json.Marshal(v)
+}
+
+// changeJSON is the JSON representation of a schema change.
+type changeJSON struct {
+	Op      string         `json:"op"`                // One of: add, drop, modify or rename.
+	Type    string         `json:"type"`              // Object type. For example, table or column.
+	ID      string         `json:"id"`                // Object identifier.
+	From    string         `json:"from,omitempty"`    // Previous identifier of renamed objects.
+	Attrs   map[string]any `json:"attrs,omitempty"`   // Attributes of the object. See attrs for details.
+	Changes []*changeJSON  `json:"changes,omitempty"` // Changes of nested objects.
+}
+
+// attrDiff is the JSON representation of a changed attribute.
+type attrDiff struct {
+	Current any `json:"current"`
+	Desired any `json:"desired"`
+}
+
+// encodeChanges encodes the changes of the object identified by owner.
+func (e *explainer) encodeChanges(owner string, changes []schema.Change) []*changeJSON {
+	var enc []*changeJSON
+	for _, c := range changes {
+		if j := e.encodeChange(owner, c); j != nil {
+			j.Attrs = e.attrs(c)
+			enc = append(enc, j)
+		}
+	}
+	return enc
+}
+
+// encodeChange encodes a single change. Nil is returned for unknown changes.
+func (e *explainer) encodeChange(owner string, c schema.Change) *changeJSON {
+	switch c := c.(type) {
+	case *schema.AddSchema:
+		return &changeJSON{Op: "add", Type: "schema", ID: c.S.Name}
+	case *schema.DropSchema:
+		return &changeJSON{Op: "drop", Type: "schema", ID: c.S.Name}
+	case *schema.ModifySchema:
+		return &changeJSON{Op: "modify", Type: "schema", ID: c.S.Name, Changes: e.encodeChanges(c.S.Name, c.Changes)}
+	case *schema.AddTable:
+		return &changeJSON{Op: "add", Type: "table", ID: tableID(c.T)}
+	case *schema.DropTable:
+		return &changeJSON{Op: "drop", Type: "table", ID: tableID(c.T)}
+	case *schema.ModifyTable:
+		id := tableID(c.T)
+		return &changeJSON{Op: "modify", Type: "table", ID: id, Changes: e.encodeChanges(id, c.Changes)}
+	case *schema.RenameTable:
+		return &changeJSON{Op: "rename", Type: "table", ID: tableID(c.To), From: tableID(c.From)}
+	case *schema.AddView:
+		return &changeJSON{Op: "add", Type: "view", ID: viewID(c.V)}
+	case *schema.DropView:
+		return &changeJSON{Op: "drop", Type: "view", ID: viewID(c.V)}
+	case *schema.ModifyView:
+		id := viewID(c.To)
+		return &changeJSON{Op: "modify", Type: "view", ID: id, Changes: e.encodeChanges(id, c.Changes)}
+	case *schema.RenameView:
+		return &changeJSON{Op: "rename", Type: "view", ID: viewID(c.To), From: viewID(c.From)}
+	case *schema.AddFunc:
+		return &changeJSON{Op: "add", Type: "function", ID: qualifiedID(c.F.Schema, c.F.Name)}
+	case *schema.DropFunc:
+		return &changeJSON{Op: "drop", Type: "function", ID: qualifiedID(c.F.Schema, c.F.Name)}
+	case *schema.ModifyFunc:
+		id := qualifiedID(c.To.Schema, c.To.Name)
+		return &changeJSON{Op: "modify", Type: "function", ID: id, Changes: e.encodeChanges(id, c.Changes)}
+	case *schema.RenameFunc:
+		return &changeJSON{Op: "rename", Type: "function", ID: qualifiedID(c.To.Schema, c.To.Name), From: qualifiedID(c.From.Schema, c.From.Name)}
+	case *schema.AddProc:
+		return &changeJSON{Op: "add", Type: "procedure", ID: qualifiedID(c.P.Schema, c.P.Name)}
+	case *schema.DropProc:
+		return &changeJSON{Op: "drop", Type: "procedure", ID: qualifiedID(c.P.Schema, c.P.Name)}
+	case *schema.ModifyProc:
+		id := qualifiedID(c.To.Schema, c.To.Name)
+		return &changeJSON{Op: "modify", Type: "procedure", ID: id, Changes: e.encodeChanges(id, c.Changes)}
+	case *schema.RenameProc:
+		return &changeJSON{Op: "rename", Type: "procedure", ID: qualifiedID(c.To.Schema, c.To.Name), From: qualifiedID(c.From.Schema, c.From.Name)}
+	case *schema.AddObject:
+		typ, name := objectName(c.O)
+		return &changeJSON{Op: "add", Type: typ, ID: name}
+	case *schema.DropObject:
+		typ, name := objectName(c.O)
+		return &changeJSON{Op: "drop", Type: typ, ID: name}
+	case *schema.ModifyObject:
+		typ, name := objectName(c.To)
+		return &changeJSON{Op: "modify", Type: typ, ID: name}
+	case *schema.RenameObject:
+		typ, name := objectName(c.To)
+		_, from := objectName(c.From)
+		return &changeJSON{Op: "rename", Type: typ, ID: name, From: from}
+	case *schema.AddTrigger:
+		return &changeJSON{Op: "add", Type: "trigger", ID: triggerID(c.T)}
+	case *schema.DropTrigger:
+		return &changeJSON{Op: "drop", Type: "trigger", ID: triggerID(c.T)}
+	case *schema.ModifyTrigger:
+		id := triggerID(c.To)
+		return &changeJSON{Op: "modify", Type: "trigger", ID: id, Changes: e.encodeChanges(id, c.Changes)}
+	case *schema.RenameTrigger:
+		return &changeJSON{Op: "rename", Type: "trigger", ID: triggerID(c.To), From: triggerID(c.From)}
+	case *schema.AddColumn:
+		return &changeJSON{Op: "add", Type: "column", ID: columnID(owner, c.C.Name)}
+	case *schema.DropColumn:
+		return &changeJSON{Op: "drop", Type: "column", ID: columnID(owner, c.C.Name)}
+	case *schema.ModifyColumn:
+		return &changeJSON{Op: "modify", Type: "column", ID: columnID(owner, c.To.Name)}
+	case *schema.RenameColumn:
+		return &changeJSON{Op: "rename", Type: "column", ID: columnID(owner, c.To.Name), From: columnID(owner, c.From.Name)}
+	case *schema.AddIndex:
+		return &changeJSON{Op: "add", Type: "index", ID: memberID(owner, c.I.Name)}
+	case *schema.DropIndex:
+		return &changeJSON{Op: "drop", Type: "index", ID: memberID(owner, c.I.Name)}
+	case *schema.ModifyIndex:
+		return &changeJSON{Op: "modify", Type: "index", ID: memberID(owner, c.To.Name)}
+	case *schema.RenameIndex:
+		return &changeJSON{Op: "rename", Type: "index", ID: memberID(owner, c.To.Name), From: memberID(owner, c.From.Name)}
+	case *schema.AddPrimaryKey:
+		return &changeJSON{Op: "add", Type: "primary_key", ID: primaryKeyID(owner)}
+	case *schema.DropPrimaryKey:
+		return &changeJSON{Op: "drop", Type: "primary_key", ID: primaryKeyID(owner)}
+	case *schema.ModifyPrimaryKey:
+		return &changeJSON{Op: "modify", Type: "primary_key", ID: primaryKeyID(owner)}
+	case *schema.AddForeignKey:
+		return &changeJSON{Op: "add", Type: "foreign_key", ID: memberID(owner, c.F.Symbol)}
+	case *schema.DropForeignKey:
+		return &changeJSON{Op: "drop", Type: "foreign_key", ID: memberID(owner, c.F.Symbol)}
+	case *schema.ModifyForeignKey:
+		return &changeJSON{Op: "modify", Type: "foreign_key", ID: memberID(owner, c.To.Symbol)}
+	case *schema.AddCheck:
+		return &changeJSON{Op: "add", Type: "check", ID: memberID(owner, c.C.Name)}
+	case *schema.DropCheck:
+		return &changeJSON{Op: "drop", Type: "check", ID: memberID(owner, c.C.Name)}
+	case *schema.ModifyCheck:
+		return &changeJSON{Op: "modify", Type: "check", ID: memberID(owner, c.To.Name)}
+	case *schema.AddAttr:
+		return &changeJSON{Op: "add", Type: "attribute", ID: attrID(owner, c.A)}
+	case *schema.DropAttr:
+		return &changeJSON{Op: "drop", Type: "attribute", ID: attrID(owner, c.A)}
+	case *schema.ModifyAttr:
+		return &changeJSON{Op: "modify", Type: "attribute", ID: attrID(owner, c.To)}
+	default:
+		return nil
+	}
+}
+
+// attrs returns the attributes of the object changed by c. Nil is returned
+// for changes of objects that their attributes are not encoded.
+func (e *explainer) attrs(c schema.Change) map[string]any {
+	switch c := c.(type) {
+	case *schema.AddColumn:
+		return e.columnAttrs(c.C)
+	case *schema.DropColumn:
+		return e.columnAttrs(c.C)
+	case *schema.ModifyColumn:
+		return e.columnDiff(c)
+	case *schema.AddIndex:
+		return indexAttrs(c.I)
+	case *schema.DropIndex:
+		return indexAttrs(c.I)
+	case *schema.ModifyIndex:
+		return indexDiff(c.From, c.To, c.Change)
+	case *schema.AddPrimaryKey:
+		return withAttrs(map[string]any{"parts": partsList(c.P.Parts)}, c.P.Attrs)
+	case *schema.DropPrimaryKey:
+		return withAttrs(map[string]any{"parts": partsList(c.P.Parts)}, c.P.Attrs)
+	case *schema.ModifyPrimaryKey:
+		return indexDiff(c.From, c.To, c.Change)
+	case *schema.AddForeignKey:
+		return foreignKeyAttrs(c.F)
+	case *schema.DropForeignKey:
+		return foreignKeyAttrs(c.F)
+	case *schema.ModifyForeignKey:
+		return foreignKeyDiff(c)
+	case *schema.AddCheck:
+		return withAttrs(map[string]any{"expr": c.C.Expr}, c.C.Attrs)
+	case *schema.DropCheck:
+		return withAttrs(map[string]any{"expr": c.C.Expr}, c.C.Attrs)
+	case *schema.ModifyCheck:
+		m := make(map[string]any)
+		if c.From.Expr != c.To.Expr {
+			m["expr"] = attrDiff{Current: c.From.Expr, Desired: c.To.Expr}
+		}
+		return withAttrsDiff(m, c.From.Attrs, c.To.Attrs)
+	case *schema.AddAttr:
+		return map[string]any{"value": attrJSON(c.A)}
+	case *schema.DropAttr:
+		return map[string]any{"value": attrJSON(c.A)}
+	case *schema.ModifyAttr:
+		return map[string]any{"value": attrDiff{Current: attrJSON(c.From), Desired: attrJSON(c.To)}}
+	default:
+		return nil
+	}
+}
+
+func (e *explainer) columnAttrs(c *schema.Column) map[string]any {
+	m := map[string]any{"type": e.typeString(c.Type.Type), "null": c.Type.Null}
+	if c.Default != nil {
+		m["default"] = exprString(c.Default)
+	}
+	return withAttrs(m, c.Attrs)
+}
+
+func (e *explainer) columnDiff(c *schema.ModifyColumn) map[string]any {
+	m := make(map[string]any)
+	if c.Change.Is(schema.ChangeType) {
+		m["type"] = attrDiff{Current: e.typeString(c.From.Type.Type), Desired: e.typeString(c.To.Type.Type)}
+	}
+	if c.Change.Is(schema.ChangeNull) {
+		m["null"] = attrDiff{Current: c.From.Type.Null, Desired: c.To.Type.Null}
+	}
+	if c.Change.Is(schema.ChangeDefault) {
+		m["default"] = attrDiff{Current: defaultJSON(c.From.Default), Desired: defaultJSON(c.To.Default)}
+	}
+	if c.Change.Is(schema.ChangeGenerated) {
+		t := reflect.TypeOf(&schema.GeneratedExpr{})
+		m["generatedexpr"] = attrDiff{Current: attrJSON(findAttr(c.From.Attrs, t)), Desired: attrJSON(findAttr(c.To.Attrs, t))}
+	}
+	return withAttrsDiff(m, c.From.Attrs, c.To.Attrs)
+}
+
+func indexAttrs(idx *schema.Index) map[string]any {
+	return withAttrs(map[string]any{"unique": idx.Unique, "parts": partsList(idx.Parts)}, idx.Attrs)
+}
+
+func indexDiff(from, to *schema.Index, k schema.ChangeKind) map[string]any {
+	m := make(map[string]any)
+	if k.Is(schema.ChangeUnique) {
+		m["unique"] = attrDiff{Current: from.Unique, Desired: to.Unique}
+	}
+	if k.Is(schema.ChangeParts) {
+		m["parts"] = attrDiff{Current: partsList(from.Parts), Desired: partsList(to.Parts)}
+	}
+	return withAttrsDiff(m, from.Attrs, to.Attrs)
+}
+
+func foreignKeyAttrs(fk *schema.ForeignKey) map[string]any {
+	m := map[string]any{
+		"columns":     columnsList(fk.Columns),
+		"ref_columns": columnsList(fk.RefColumns),
+	}
+	if fk.RefTable != nil {
+		m["ref_table"] = tableID(fk.RefTable)
+	}
+	if fk.OnUpdate != "" {
+		m["on_update"] = string(fk.OnUpdate)
+	}
+	if fk.OnDelete != "" {
+		m["on_delete"] = string(fk.OnDelete)
+	}
+	return m
+}
+
+func foreignKeyDiff(c *schema.ModifyForeignKey) map[string]any {
+	m := make(map[string]any)
+	if c.Change.Is(schema.ChangeColumn) {
+		m["columns"] = attrDiff{Current: columnsList(c.From.Columns), Desired: columnsList(c.To.Columns)}
+	}
+	if c.Change.Is(schema.ChangeRefTable) && c.From.RefTable != nil && c.To.RefTable != nil {
+		m["ref_table"] = attrDiff{Current: tableID(c.From.RefTable), Desired: tableID(c.To.RefTable)}
+	}
+	if c.Change.Is(schema.ChangeRefColumn) {
+		m["ref_columns"] = attrDiff{Current: columnsList(c.From.RefColumns), Desired: columnsList(c.To.RefColumns)}
+	}
+	if c.Change.Is(schema.ChangeUpdateAction) {
+		m["on_update"] = attrDiff{Current: string(c.From.OnUpdate), Desired: string(c.To.OnUpdate)}
+	}
+	if c.Change.Is(schema.ChangeDeleteAction) {
+		m["on_delete"] = attrDiff{Current: string(c.From.OnDelete), Desired: string(c.To.OnDelete)}
+	}
+	return m
+}
+
+// encodedAttrs lists the attributes that are encoded for all objects.
+var encodedAttrs = []schema.Attr{&schema.Comment{}, &schema.Charset{}, &schema.Collation{}}
+
+// withAttrs sets the encoded attributes found in attrs to m.
+func withAttrs(m map[string]any, attrs []schema.Attr) map[string]any {
+	for _, a := range encodedAttrs {
+		if v := findAttr(attrs, reflect.TypeOf(a)); v != nil {
+			m[attrName(a)] = attrJSON(v)
+		}
+	}
+	return m
+}
+
+// withAttrsDiff sets the encoded attributes that were changed between from and to to m.
+func withAttrsDiff(m map[string]any, from, to []schema.Attr) map[string]any {
+	for _, a := range encodedAttrs {
+		typ := reflect.TypeOf(a)
+		if x, y := findAttr(from, typ), findAttr(to, typ); !reflect.DeepEqual(x, y) {
+			m[attrName(a)] = attrDiff{Current: attrJSON(x), Desired: attrJSON(y)}
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// attrJSON returns the JSON value of an attribute. Attributes holding
+// a single value are encoded by their value, and others are summarized.
+func attrJSON(a schema.Attr) any {
+	switch a := a.(type) {
+	case nil:
+		return nil
+	case *schema.Comment:
+		return a.Text
+	case *schema.Charset:
+		return a.V
+	case *schema.Collation:
+		return a.V
+	case *schema.Check:
+		return a.Expr
+	case *schema.GeneratedExpr:
+		return a.Expr
+	}
+	if v := reflect.Indirect(reflect.ValueOf(a)); v.Kind() == reflect.Struct && v.NumField() > 0 {
+		var fields []reflect.Value
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.IsExported() && !f.Anonymous {
+				fields = append(fields, v.Field(i))
+			}
+		}
+		if len(fields) == 1 && fields[0].Kind() != reflect.Interface && fields[0].Kind() != reflect.Ptr {
+			return fields[0].Interface()
+		}
+	}
+	return valueString(reflect.ValueOf(a))
+}
+
+func defaultJSON(x schema.Expr) any {
+	if x == nil {
+		return nil
+	}
+	return exprString(x)
+}
@@ -0,0 +1,323 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package cmdlog
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+)
+
+type (
+	// SchemaPortability contains a summary of the 'schema portability' command. It lists the
+	// constructs of a schema, defined in one database dialect, that do not translate as-is to
+	// another dialect, with their suggested equivalents.
+	SchemaPortability struct {
+		From   string              `json:"from"` // Dialect the schema is defined in.
+		To     string              `json:"to"`   // Dialect the schema is translated to.
+		Issues []*PortabilityIssue `json:"issues,omitempty"`
+	}
+
+	// PortabilityIssue describes a construct that does not translate to the target dialect.
+	PortabilityIssue struct {
+		ID         string `json:"id"`                   // Object identifier.
+		Kind       string `json:"kind"`                 // Issue kind. For example, unsigned_integer.
+		Message    string `json:"message"`              // Issue description.
+		Suggestion string `json:"suggestion,omitempty"` // Suggested equivalent in the target dialect.
+	}
+)
+
+// Portability issue kinds.
+const (
+	PortabilityUnsigned     = "unsigned_integer"
+	PortabilityZeroDate     = "zero_date"
+	PortabilityEnum         = "enum"
+	PortabilityType         = "type"
+	PortabilityCollation    = "collation"
+	PortabilityOnUpdate     = "on_update"
+	PortabilityPartialIndex = "partial_index"
+	PortabilityTextIndex    = "text_index"
+)
+
+var (
+	// PortabilityTemplateFuncs are global functions available in portability report templates.
+	PortabilityTemplateFuncs = template.FuncMap{
+		"json": jsonEncode,
+	}
+
+	// SchemaPortabilityTemplate holds the default template of the 'schema portability' command.
+	SchemaPortabilityTemplate = template.Must(template.New("portability").
+					Funcs(PortabilityTemplateFuncs).
+					Parse(`{{- with .Issues -}}
+Found {{ len . }} portability issues from {{ $.From }} to {{ $.To }}:
+{{ range . }}
+  -- {{ .ID }}: {{ .Message }}
+{{- with .Suggestion }}
+     Suggestion: {{ . }}
+{{- end }}
+{{- end }}
+{{ else -}}
+No portability issues found from {{ .From }} to {{ .To }}
+{{ end -}}
+`))
+)
+
+// NewSchemaPortability returns the portability report of the given realm, defined in the
+// "from" dialect, when translated to the "to" dialect. The supported dialects are: mysql,
+// mariadb, postgres and sqlite.
+func NewSchemaPortability(from, to string, r *schema.Realm) *SchemaPortability {
+	p := &SchemaPortability{From: from, To: to}
+	if family(from) == family(to) {
+		return p
+	}
+	for _, s := range r.Schemas {
+		p.collation(s.Name, s.Attrs, nil)
+		for _, t := range s.Tables {
+			id := tableID(t)
+			p.collation(id, t.Attrs, s.Attrs)
+			for _, c := range t.Columns {
+				p.column(columnID(id, c.Name), c)
+				p.collation(columnID(id, c.Name), c.Attrs, t.Attrs)
+			}
+			if pk := t.PrimaryKey; pk != nil {
+				p.index(primaryKeyID(id), t, pk)
+			}
+			for _, idx := range t.Indexes {
+				p.index(memberID(id, idx.Name), t, idx)
+			}
+		}
+	}
+	return p
+}
+
+// family returns the dialect family of the given dialect.
+func family(d string) string {
+	if d == "mariadb" {
+		return "mysql"
+	}
+	return d
+}
+
+func (p *SchemaPortability) add(id, kind, msg, suggestion string) {
+	p.Issues = append(p.Issues, &PortabilityIssue{ID: id, Kind: kind, Message: msg, Suggestion: suggestion})
+}
+
+// toMySQL reports if the schema is translated to MySQL (or MariaDB).
+func (p *SchemaPortability) toMySQL() bool { return family(p.To) == "mysql" }
+
+// column reports the issues of the given column.
+func (p *SchemaPortability) column(id string, c *schema.Column) {
+	if c.Type == nil || c.Type.Type == nil {
+		return
+	}
+	// Wrapping types are checked before their underlying types.
+	switch t := c.Type.Type.(type) {
+	case *postgres.ArrayType:
+		if p.To != "postgres" {
+			p.add(id, PortabilityType, fmt.Sprintf("array types are not supported by %s", p.To), "use json")
+		}
+		return
+	case *postgres.DomainType:
+		if p.To != "postgres" {
+			p.add(id, PortabilityType, fmt.Sprintf("domain %q is not supported by %s", t.T, p.To), "use the underlying type of the domain with a CHECK constraint")
+		}
+	}
+	switch t := schema.UnderlyingType(c.Type.Type).(type) {
+	case *schema.IntegerType:
+		if t.Unsigned && !p.toMySQL() {
+			p.add(id, PortabilityUnsigned, fmt.Sprintf("unsigned integers are not supported by %s", p.To), unsignedSuggestion(p.To, t.T, c.Name))
+		}
+	case *schema.TimeType:
+		switch strings.ToLower(t.T) {
+		case mysql.TypeYear:
+			if !p.toMySQL() {
+				p.add(id, PortabilityType, fmt.Sprintf("type year is not supported by %s", p.To), "use smallint with a CHECK constraint on the year range")
+			}
+		case postgres.TypeTimestampTZ, postgres.TypeTimestampWTZ, postgres.TypeTimeTZ, postgres.TypeTimeWTZ:
+			if p.To == "sqlite" {
+				p.add(id, PortabilityType, "time zones are not stored by sqlite", "use text holding ISO-8601 values with a UTC offset")
+			} else if p.toMySQL() {
+				p.add(id, PortabilityType, fmt.Sprintf("time zones are not stored by %s", p.To), "use timestamp (stored in UTC), and keep the time zone in a separate column if needed")
+			}
+		}
+		if v, ok := zeroDate(c.Default); ok && !p.toMySQL() {
+			p.add(id, PortabilityZeroDate, fmt.Sprintf("zero date default '%s' is not a valid value in %s", v, p.To), "use NULL as the default value, or a valid date (e.g. '1970-01-01')")
+		}
+	case *schema.EnumType:
+		values := "'" + strings.Join(t.Values, "', '") + "'"
+		switch {
+		case p.To == "postgres" && t.Schema == nil:
+			p.add(id, PortabilityEnum, "enum columns are defined using named types in postgres", fmt.Sprintf("create an enum type with the values (%s), or use varchar with a CHECK (%s IN (%s)) constraint", values, c.Name, values))
+		case p.To == "sqlite":
+			p.add(id, PortabilityEnum, "enum types are not supported by sqlite", fmt.Sprintf("use text with a CHECK (%s IN (%s)) constraint", c.Name, values))
+		case p.toMySQL() && t.Schema != nil:
+			p.add(id, PortabilityEnum, fmt.Sprintf("enum type %q is defined inline on each column in %s", t.T, p.To), fmt.Sprintf("use enum(%s), and keep the values of all columns in sync", values))
+		}
+	case *mysql.SetType:
+		if !p.toMySQL() {
+			suggestion := "use text holding comma-separated values"
+			if p.To == "postgres" {
+				suggestion = "use an array of text (text[]), or a junction table"
+			}
+			p.add(id, PortabilityType, fmt.Sprintf("type set is not supported by %s", p.To), suggestion)
+		}
+	case *schema.UUIDType:
+		switch {
+		case p.To == "mysql":
+			p.add(id, PortabilityType, "type uuid is not supported by mysql", "use binary(16), or char(36)")
+		case p.To == "sqlite":
+			p.add(id, PortabilityType, "type uuid is not supported by sqlite", "use text, or blob")
+		}
+	case *schema.SpatialType:
+		switch {
+		case p.To == "postgres":
+			p.add(id, PortabilityType, "spatial types require the PostGIS extension in postgres", "install PostGIS, and use geometry")
+		case p.To == "sqlite":
+			p.add(id, PortabilityType, "spatial types are not supported by sqlite", "use blob, or the SpatiaLite extension")
+		}
+	case *schema.StringType:
+		if p.toMySQL() && t.Size == 0 && (t.T == postgres.TypeVarChar || t.T == postgres.TypeCharVar) {
+			p.add(id, PortabilityType, fmt.Sprintf("varchar columns require a length in %s", p.To), "use varchar(255), or text")
+		}
+	case *schema.UnsupportedType:
+		p.add(id, PortabilityType, fmt.Sprintf("type %q has no known equivalent in %s", t.T, p.To), "")
+	}
+	if u, ok := attrOf[*mysql.OnUpdate](c.Attrs); ok && !p.toMySQL() {
+		p.add(id, PortabilityOnUpdate, fmt.Sprintf("ON UPDATE %s is not supported by %s", u.A, p.To), fmt.Sprintf("use a trigger that sets %s on UPDATE", c.Name))
+	}
+}
+
+// collation reports the collations that have no equivalent in the target dialect.
+// Collations that are inherited from the parent attributes are reported once.
+func (p *SchemaPortability) collation(id string, attrs, parent []schema.Attr) {
+	c, ok := attrOf[*schema.Collation](attrs)
+	if !ok {
+		return
+	}
+	if pc, ok := attrOf[*schema.Collation](parent); ok && pc.V == c.V {
+		return
+	}
+	switch {
+	case family(p.From) == "mysql" && p.To == "postgres" && ciCollation(c.V):
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q compares strings case-insensitively, while postgres collations are case-sensitive", c.V), "use citext, or a nondeterministic ICU collation")
+	case family(p.From) == "mysql" && p.To == "sqlite" && ciCollation(c.V):
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q has no equivalent in sqlite", c.V), "use COLLATE NOCASE")
+	case family(p.From) == "mysql":
+		var suggestion string
+		switch {
+		case !strings.HasSuffix(strings.ToLower(c.V), "_bin"):
+		case p.To == "postgres":
+			suggestion = `use the "C" collation for binary comparisons`
+		case p.To == "sqlite":
+			suggestion = "use COLLATE BINARY"
+		}
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q has no equivalent in %s", c.V, p.To), suggestion)
+	case p.toMySQL():
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q has no equivalent in %s", c.V, p.To), "use a MySQL collation (e.g. utf8mb4_bin for binary comparisons)")
+	case p.To == "sqlite":
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q has no equivalent in sqlite", c.V), "use one of the built-in collations: BINARY, NOCASE or RTRIM")
+	case p.To == "postgres":
+		p.add(id, PortabilityCollation, fmt.Sprintf("collation %q has no equivalent in postgres", c.V), "")
+	}
+}
+
+// index reports the issues of the given index.
+func (p *SchemaPortability) index(id string, t *schema.Table, idx *schema.Index) {
+	if _, ok := attrOf[*postgres.IndexPredicate](idx.Attrs); ok && p.toMySQL() {
+		p.add(id, PortabilityPartialIndex, fmt.Sprintf("partial indexes are not supported by %s", p.To), "drop the WHERE clause, or index a generated column instead")
+	}
+	for _, part := range idx.Parts {
+		c := part.C
+		if c == nil || c.Type == nil {
+			continue
+		}
+		st, ok := schema.UnderlyingType(c.Type.Type).(*schema.StringType)
+		if !ok {
+			continue
+		}
+		switch {
+		case p.toMySQL() && family(p.From) != "mysql" && strings.EqualFold(st.T, "text"):
+			p.add(id, PortabilityTextIndex, fmt.Sprintf("indexing text column %q requires a prefix length in %s", c.Name, p.To), fmt.Sprintf("use varchar(N) for column %q, or set a prefix length on the index part", c.Name))
+		// Unique constraints on strings compare values using the column collation, which is
+		// case-insensitive by default in MySQL, and case-sensitive by default elsewhere.
+		case idx.Unique && family(p.From) == "mysql" && ciCollation(columnCollation(t, c)):
+			p.add(id, PortabilityCollation, fmt.Sprintf("uniqueness of column %q is case-insensitive in %s, but case-sensitive in %s", c.Name, p.From, p.To), fmt.Sprintf("enforce uniqueness on lower(%s), or use a case-insensitive type or collation", c.Name))
+		case idx.Unique && p.toMySQL() && columnCollation(t, c) == "":
+			p.add(id, PortabilityCollation, fmt.Sprintf("uniqueness of column %q is case-sensitive in %s, but case-insensitive by default in %s", c.Name, p.From, p.To), "use a case-sensitive collation (e.g. utf8mb4_bin)")
+		}
+	}
+}
+
+// columnCollation returns the collation of the column, inherited from its
+// table or schema. An empty string is returned if no collation is defined.
+func columnCollation(t *schema.Table, c *schema.Column) string {
+	attrs := [][]schema.Attr{c.Attrs, t.Attrs}
+	if t.Schema != nil {
+		attrs = append(attrs, t.Schema.Attrs)
+	}
+	for _, as := range attrs {
+		if cl, ok := attrOf[*schema.Collation](as); ok {
+			return cl.V
+		}
+	}
+	return ""
+}
+
+// ciCollation reports if the given MySQL collation is case-insensitive. An empty
+// collation stands for the server default, which is case-insensitive.
+func ciCollation(c string) bool {
+	return c == "" || strings.HasSuffix(strings.ToLower(c), "_ci")
+}
+
+// zeroDate reports if the given default value is a MySQL zero date.
+func zeroDate(x schema.Expr) (string, bool) {
+	var v string
+	switch x := x.(type) {
+	case *schema.Literal:
+		v = x.V
+	case *schema.RawExpr:
+		v = x.X
+	default:
+		return "", false
+	}
+	v = strings.Trim(v, `'"`)
+	return v, strings.HasPrefix(v, "0000-00-00")
+}
+
+// unsignedSuggestion returns the suggested equivalent of an unsigned integer type.
+func unsignedSuggestion(to, t, name string) string {
+	check := fmt.Sprintf("with a CHECK (%s >= 0) constraint", name)
+	if to == "sqlite" {
+		if strings.EqualFold(t, mysql.TypeBigInt) {
+			return "values above 9223372036854775807 do not fit in integer, use numeric or text"
+		}
+		return "use integer " + check
+	}
+	switch strings.ToLower(t) {
+	case mysql.TypeTinyInt:
+		return "use smallint " + check
+	case mysql.TypeSmallInt:
+		return "use integer " + check
+	case mysql.TypeBigInt:
+		return "use numeric(20) " + check
+	default:
+		return "use bigint " + check
+	}
+}
+
+// attrOf returns the first attribute of type T.
+func attrOf[T schema.Attr](attrs []schema.Attr) (T, bool) {
+	for _, a := range attrs {
+		if a, ok := a.(T); ok {
+			return a, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -61,6 +61,8 @@ type (
 		inters *inters
 		// schemaConfig contains alternative names for all tables.
 		schemaConfig SchemaConfig
+		// tableConfig contains alternative names for all tables.
+		tableConfig TableConfig
 	}
 	// Option function to configure the client.
 	Option func(*config)
@@ -361,6 +363,21 @@ func AlternateSchema(schemaConfig SchemaConfig) Option {
 	}
 }
 
+// TableConfig represents alternative table names for all
+// tables that can be passed at runtime. Empty names mean
+// the tables defined in ent/schema are used.
+type TableConfig struct {
+	Revision string // Revision table.
+}
+
+// AlternateTable allows alternate table names to be
+// passed into ent operations.
+func AlternateTable(tableConfig TableConfig) Option {
+	return func(c *config) {
+		c.tableConfig = tableConfig
+	}
+}
+
 // ExecContext allows calling the underlying ExecContext method of the driver if it is supported by it.
 // See, database/sql#DB.ExecContext for more information.
 func (c *config) ExecContext(ctx context.Context, query string, args ...any) (stdsql.Result, error) {
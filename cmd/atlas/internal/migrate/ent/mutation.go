@@ -54,6 +54,8 @@ type RevisionMutation struct {
 	partial_hashes       *[]string
 	appendpartial_hashes []string
 	operator_version     *string
+	etag                 *int
+	addetag              *int
 	clearedFields        map[string]struct{}
 	done                 bool
 	oldValue             func(context.Context) (*Revision, error)
@@ -695,6 +697,62 @@ func (m *RevisionMutation) ResetOperatorVersion() {
 	m.operator_version = nil
 }
 
+// SetEtag sets the "etag" field.
+func (m *RevisionMutation) SetEtag(i int) {
+	m.etag = &i
+	m.addetag = nil
+}
+
+// Etag returns the value of the "etag" field in the mutation.
+func (m *RevisionMutation) Etag() (r int, exists bool) {
+	v := m.etag
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// OldEtag returns the old "etag" field's value of the Revision entity.
+// If the Revision object wasn't provided to the builder, the object is fetched from the database.
+// An error is returned if the mutation operation is not UpdateOne, or the database query fails.
+func (m *RevisionMutation) OldEtag(ctx context.Context) (v int, err error) {
+	if !m.op.Is(OpUpdateOne) {
+		return v, errors.New("OldEtag is only allowed on UpdateOne operations")
+	}
+	if m.id == nil || m.oldValue == nil {
+		return v, errors.New("OldEtag requires an ID field in the mutation")
+	}
+	oldValue, err := m.oldValue(ctx)
+	if err != nil {
+		return v, fmt.Errorf("querying old value for OldEtag: %w", err)
+	}
+	return oldValue.Etag, nil
+}
+
+// AddEtag adds i to the "etag" field.
+func (m *RevisionMutation) AddEtag(i int) {
+	if m.addetag != nil {
+		*m.addetag += i
+	} else {
+		m.addetag = &i
+	}
+}
+
+// AddedEtag returns the value that was added to the "etag" field in this mutation.
+func (m *RevisionMutation) AddedEtag() (r int, exists bool) {
+	v := m.addetag
+	if v == nil {
+		return
+	}
+	return *v, true
+}
+
+// ResetEtag resets all changes to the "etag" field.
+func (m *RevisionMutation) ResetEtag() {
+	m.etag = nil
+	m.addetag = nil
+}
+
 // Where appends a list predicates to the RevisionMutation builder.
 func (m *RevisionMutation) Where(ps ...predicate.Revision) {
 	m.predicates = append(m.predicates, ps...)
@@ -729,7 +787,7 @@ func (m *RevisionMutation) Type() string {
 // order to get all numeric fields that were incremented/decremented, call
 // AddedFields().
 func (m *RevisionMutation) Fields() []string {
-	fields := make([]string, 0, 11)
+	fields := make([]string, 0, 12)
 	if m.description != nil {
 		fields = append(fields, revision.FieldDescription)
 	}
@@ -763,6 +821,9 @@ func (m *RevisionMutation) Fields() []string {
 	if m.operator_version != nil {
 		fields = append(fields, revision.FieldOperatorVersion)
 	}
+	if m.etag != nil {
+		fields = append(fields, revision.FieldEtag)
+	}
 	return fields
 }
 
@@ -793,6 +854,8 @@ func (m *RevisionMutation) Field(name string) (ent.Value, bool) {
 		return m.PartialHashes()
 	case revision.FieldOperatorVersion:
 		return m.OperatorVersion()
+	case revision.FieldEtag:
+		return m.Etag()
 	}
 	return nil, false
 }
@@ -824,6 +887,8 @@ func (m *RevisionMutation) OldField(ctx context.Context, name string) (ent.Value
 		return m.OldPartialHashes(ctx)
 	case revision.FieldOperatorVersion:
 		return m.OldOperatorVersion(ctx)
+	case revision.FieldEtag:
+		return m.OldEtag(ctx)
 	}
 	return nil, fmt.Errorf("unknown Revision field %s", name)
 }
@@ -910,6 +975,13 @@ func (m *RevisionMutation) SetField(name string, value ent.Value) error {
 		}
 		m.SetOperatorVersion(v)
 		return nil
+	case revision.FieldEtag:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.SetEtag(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Revision field %s", name)
 }
@@ -930,6 +1002,9 @@ func (m *RevisionMutation) AddedFields() []string {
 	if m.addexecution_time != nil {
 		fields = append(fields, revision.FieldExecutionTime)
 	}
+	if m.addetag != nil {
+		fields = append(fields, revision.FieldEtag)
+	}
 	return fields
 }
 
@@ -946,6 +1021,8 @@ func (m *RevisionMutation) AddedField(name string) (ent.Value, bool) {
 		return m.AddedTotal()
 	case revision.FieldExecutionTime:
 		return m.AddedExecutionTime()
+	case revision.FieldEtag:
+		return m.AddedEtag()
 	}
 	return nil, false
 }
@@ -983,6 +1060,13 @@ func (m *RevisionMutation) AddField(name string, value ent.Value) error {
 		}
 		m.AddExecutionTime(v)
 		return nil
+	case revision.FieldEtag:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("unexpected type %T for field %s", value, name)
+		}
+		m.AddEtag(v)
+		return nil
 	}
 	return fmt.Errorf("unknown Revision numeric field %s", name)
 }
@@ -1064,6 +1148,9 @@ func (m *RevisionMutation) ResetField(name string) error {
 	case revision.FieldOperatorVersion:
 		m.ResetOperatorVersion()
 		return nil
+	case revision.FieldEtag:
+		m.ResetEtag()
+		return nil
 	}
 	return fmt.Errorf("unknown Revision field %s", name)
 }
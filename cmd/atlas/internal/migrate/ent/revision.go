@@ -45,7 +45,9 @@ type Revision struct {
 	PartialHashes []string `json:"partial_hashes,omitempty"`
 	// OperatorVersion holds the value of the "operator_version" field.
 	OperatorVersion string `json:"operator_version,omitempty"`
-	selectValues    sql.SelectValues
+	// Etag holds the value of the "etag" field.
+	Etag         int `json:"etag,omitempty"`
+	selectValues sql.SelectValues
 }
 
 // scanValues returns the types for scanning values from sql.Rows.
@@ -55,7 +57,7 @@ func (*Revision) scanValues(columns []string) ([]any, error) {
 		switch columns[i] {
 		case revision.FieldPartialHashes:
 			values[i] = new([]byte)
-		case revision.FieldType, revision.FieldApplied, revision.FieldTotal, revision.FieldExecutionTime:
+		case revision.FieldType, revision.FieldApplied, revision.FieldTotal, revision.FieldExecutionTime, revision.FieldEtag:
 			values[i] = new(sql.NullInt64)
 		case revision.FieldID, revision.FieldDescription, revision.FieldError, revision.FieldErrorStmt, revision.FieldHash, revision.FieldOperatorVersion:
 			values[i] = new(sql.NullString)
@@ -150,6 +152,12 @@ func (r *Revision) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				r.OperatorVersion = value.String
 			}
+		case revision.FieldEtag:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field etag", values[i])
+			} else if value.Valid {
+				r.Etag = int(value.Int64)
+			}
 		default:
 			r.selectValues.Set(columns[i], values[i])
 		}
@@ -218,6 +226,9 @@ func (r *Revision) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("operator_version=")
 	builder.WriteString(r.OperatorVersion)
+	builder.WriteString(", ")
+	builder.WriteString("etag=")
+	builder.WriteString(fmt.Sprintf("%v", r.Etag))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -38,6 +38,8 @@ const (
 	FieldPartialHashes = "partial_hashes"
 	// FieldOperatorVersion holds the string denoting the operator_version field in the database.
 	FieldOperatorVersion = "operator_version"
+	// FieldEtag holds the string denoting the etag field in the database.
+	FieldEtag = "etag"
 	// Table holds the table name of the revision in the database.
 	Table = "atlas_schema_revisions"
 )
@@ -56,6 +58,7 @@ var Columns = []string{
 	FieldHash,
 	FieldPartialHashes,
 	FieldOperatorVersion,
+	FieldEtag,
 }
 
 // ValidColumn reports if the column name is valid (part of the table columns).
@@ -79,6 +82,10 @@ var (
 	DefaultTotal int
 	// TotalValidator is a validator for the "total" field. It is called by the builders before save.
 	TotalValidator func(int) error
+	// DefaultEtag holds the default value on creation for the "etag" field.
+	DefaultEtag int
+	// EtagValidator is a validator for the "etag" field. It is called by the builders before save.
+	EtagValidator func(int) error
 )
 
 // OrderOption defines the ordering options for the Revision queries.
@@ -138,3 +145,8 @@ func ByHash(opts ...sql.OrderTermOption) OrderOption {
 func ByOperatorVersion(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldOperatorVersion, opts...).ToFunc()
 }
+
+// ByEtag orders the results by the etag field.
+func ByEtag(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldEtag, opts...).ToFunc()
+}
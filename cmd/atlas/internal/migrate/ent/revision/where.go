@@ -121,6 +121,11 @@ func OperatorVersion(v string) predicate.Revision {
 	return predicate.Revision(sql.FieldEQ(FieldOperatorVersion, v))
 }
 
+// Etag applies equality check predicate on the "etag" field. It's identical to EtagEQ.
+func Etag(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldEQ(FieldEtag, v))
+}
+
 // DescriptionEQ applies the EQ predicate on the "description" field.
 func DescriptionEQ(v string) predicate.Revision {
 	return predicate.Revision(sql.FieldEQ(FieldDescription, v))
@@ -704,6 +709,46 @@ func OperatorVersionContainsFold(v string) predicate.Revision {
 	return predicate.Revision(sql.FieldContainsFold(FieldOperatorVersion, v))
 }
 
+// EtagEQ applies the EQ predicate on the "etag" field.
+func EtagEQ(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldEQ(FieldEtag, v))
+}
+
+// EtagNEQ applies the NEQ predicate on the "etag" field.
+func EtagNEQ(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldNEQ(FieldEtag, v))
+}
+
+// EtagIn applies the In predicate on the "etag" field.
+func EtagIn(vs ...int) predicate.Revision {
+	return predicate.Revision(sql.FieldIn(FieldEtag, vs...))
+}
+
+// EtagNotIn applies the NotIn predicate on the "etag" field.
+func EtagNotIn(vs ...int) predicate.Revision {
+	return predicate.Revision(sql.FieldNotIn(FieldEtag, vs...))
+}
+
+// EtagGT applies the GT predicate on the "etag" field.
+func EtagGT(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldGT(FieldEtag, v))
+}
+
+// EtagGTE applies the GTE predicate on the "etag" field.
+func EtagGTE(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldGTE(FieldEtag, v))
+}
+
+// EtagLT applies the LT predicate on the "etag" field.
+func EtagLT(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldLT(FieldEtag, v))
+}
+
+// EtagLTE applies the LTE predicate on the "etag" field.
+func EtagLTE(v int) predicate.Revision {
+	return predicate.Revision(sql.FieldLTE(FieldEtag, v))
+}
+
 // And groups predicates with the AND operator between them.
 func And(predicates ...predicate.Revision) predicate.Revision {
 	return predicate.Revision(sql.AndPredicates(predicates...))
@@ -134,6 +134,20 @@ func (rc *RevisionCreate) SetOperatorVersion(s string) *RevisionCreate {
 	return rc
 }
 
+// SetEtag sets the "etag" field.
+func (rc *RevisionCreate) SetEtag(i int) *RevisionCreate {
+	rc.mutation.SetEtag(i)
+	return rc
+}
+
+// SetNillableEtag sets the "etag" field if the given value is not nil.
+func (rc *RevisionCreate) SetNillableEtag(i *int) *RevisionCreate {
+	if i != nil {
+		rc.SetEtag(*i)
+	}
+	return rc
+}
+
 // SetID sets the "id" field.
 func (rc *RevisionCreate) SetID(s string) *RevisionCreate {
 	rc.mutation.SetID(s)
@@ -187,6 +201,10 @@ func (rc *RevisionCreate) defaults() {
 		v := revision.DefaultTotal
 		rc.mutation.SetTotal(v)
 	}
+	if _, ok := rc.mutation.Etag(); !ok {
+		v := revision.DefaultEtag
+		rc.mutation.SetEtag(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -225,6 +243,14 @@ func (rc *RevisionCreate) check() error {
 	if _, ok := rc.mutation.OperatorVersion(); !ok {
 		return &ValidationError{Name: "operator_version", err: errors.New(`ent: missing required field "Revision.operator_version"`)}
 	}
+	if _, ok := rc.mutation.Etag(); !ok {
+		return &ValidationError{Name: "etag", err: errors.New(`ent: missing required field "Revision.etag"`)}
+	}
+	if v, ok := rc.mutation.Etag(); ok {
+		if err := revision.EtagValidator(v); err != nil {
+			return &ValidationError{Name: "etag", err: fmt.Errorf(`ent: validator failed for field "Revision.etag": %w`, err)}
+		}
+	}
 	return nil
 }
 
@@ -257,6 +283,9 @@ func (rc *RevisionCreate) createSpec() (*Revision, *sqlgraph.CreateSpec) {
 		_spec = sqlgraph.NewCreateSpec(revision.Table, sqlgraph.NewFieldSpec(revision.FieldID, field.TypeString))
 	)
 	_spec.Schema = rc.schemaConfig.Revision
+	if t := rc.tableConfig.Revision; t != "" {
+		_spec.Table = t
+	}
 	_spec.OnConflict = rc.conflict
 	if id, ok := rc.mutation.ID(); ok {
 		_node.ID = id
@@ -306,6 +335,10 @@ func (rc *RevisionCreate) createSpec() (*Revision, *sqlgraph.CreateSpec) {
 		_spec.SetField(revision.FieldOperatorVersion, field.TypeString, value)
 		_node.OperatorVersion = value
 	}
+	if value, ok := rc.mutation.Etag(); ok {
+		_spec.SetField(revision.FieldEtag, field.TypeInt, value)
+		_node.Etag = value
+	}
 	return _node, _spec
 }
 
@@ -358,6 +391,18 @@ type (
 	}
 )
 
+// SetDescription sets the "description" field.
+func (u *RevisionUpsert) SetDescription(v string) *RevisionUpsert {
+	u.Set(revision.FieldDescription, v)
+	return u
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *RevisionUpsert) UpdateDescription() *RevisionUpsert {
+	u.SetExcluded(revision.FieldDescription)
+	return u
+}
+
 // SetType sets the "type" field.
 func (u *RevisionUpsert) SetType(v migrate.RevisionType) *RevisionUpsert {
 	u.Set(revision.FieldType, v)
@@ -412,6 +457,18 @@ func (u *RevisionUpsert) AddTotal(v int) *RevisionUpsert {
 	return u
 }
 
+// SetExecutedAt sets the "executed_at" field.
+func (u *RevisionUpsert) SetExecutedAt(v time.Time) *RevisionUpsert {
+	u.Set(revision.FieldExecutedAt, v)
+	return u
+}
+
+// UpdateExecutedAt sets the "executed_at" field to the value that was provided on create.
+func (u *RevisionUpsert) UpdateExecutedAt() *RevisionUpsert {
+	u.SetExcluded(revision.FieldExecutedAt)
+	return u
+}
+
 // SetExecutionTime sets the "execution_time" field.
 func (u *RevisionUpsert) SetExecutionTime(v time.Duration) *RevisionUpsert {
 	u.Set(revision.FieldExecutionTime, v)
@@ -508,6 +565,24 @@ func (u *RevisionUpsert) UpdateOperatorVersion() *RevisionUpsert {
 	return u
 }
 
+// SetEtag sets the "etag" field.
+func (u *RevisionUpsert) SetEtag(v int) *RevisionUpsert {
+	u.Set(revision.FieldEtag, v)
+	return u
+}
+
+// UpdateEtag sets the "etag" field to the value that was provided on create.
+func (u *RevisionUpsert) UpdateEtag() *RevisionUpsert {
+	u.SetExcluded(revision.FieldEtag)
+	return u
+}
+
+// AddEtag adds v to the "etag" field.
+func (u *RevisionUpsert) AddEtag(v int) *RevisionUpsert {
+	u.Add(revision.FieldEtag, v)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create except the ID field.
 // Using this option is equivalent to using:
 //
@@ -525,12 +600,6 @@ func (u *RevisionUpsertOne) UpdateNewValues() *RevisionUpsertOne {
 		if _, exists := u.create.mutation.ID(); exists {
 			s.SetIgnore(revision.FieldID)
 		}
-		if _, exists := u.create.mutation.Description(); exists {
-			s.SetIgnore(revision.FieldDescription)
-		}
-		if _, exists := u.create.mutation.ExecutedAt(); exists {
-			s.SetIgnore(revision.FieldExecutedAt)
-		}
 	}))
 	return u
 }
@@ -562,6 +631,20 @@ func (u *RevisionUpsertOne) Update(set func(*RevisionUpsert)) *RevisionUpsertOne
 	return u
 }
 
+// SetDescription sets the "description" field.
+func (u *RevisionUpsertOne) SetDescription(v string) *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetDescription(v)
+	})
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *RevisionUpsertOne) UpdateDescription() *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateDescription()
+	})
+}
+
 // SetType sets the "type" field.
 func (u *RevisionUpsertOne) SetType(v migrate.RevisionType) *RevisionUpsertOne {
 	return u.Update(func(s *RevisionUpsert) {
@@ -625,6 +708,20 @@ func (u *RevisionUpsertOne) UpdateTotal() *RevisionUpsertOne {
 	})
 }
 
+// SetExecutedAt sets the "executed_at" field.
+func (u *RevisionUpsertOne) SetExecutedAt(v time.Time) *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetExecutedAt(v)
+	})
+}
+
+// UpdateExecutedAt sets the "executed_at" field to the value that was provided on create.
+func (u *RevisionUpsertOne) UpdateExecutedAt() *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateExecutedAt()
+	})
+}
+
 // SetExecutionTime sets the "execution_time" field.
 func (u *RevisionUpsertOne) SetExecutionTime(v time.Duration) *RevisionUpsertOne {
 	return u.Update(func(s *RevisionUpsert) {
@@ -737,6 +834,27 @@ func (u *RevisionUpsertOne) UpdateOperatorVersion() *RevisionUpsertOne {
 	})
 }
 
+// SetEtag sets the "etag" field.
+func (u *RevisionUpsertOne) SetEtag(v int) *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetEtag(v)
+	})
+}
+
+// AddEtag adds v to the "etag" field.
+func (u *RevisionUpsertOne) AddEtag(v int) *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.AddEtag(v)
+	})
+}
+
+// UpdateEtag sets the "etag" field to the value that was provided on create.
+func (u *RevisionUpsertOne) UpdateEtag() *RevisionUpsertOne {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateEtag()
+	})
+}
+
 // Exec executes the query.
 func (u *RevisionUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -920,12 +1038,6 @@ func (u *RevisionUpsertBulk) UpdateNewValues() *RevisionUpsertBulk {
 			if _, exists := b.mutation.ID(); exists {
 				s.SetIgnore(revision.FieldID)
 			}
-			if _, exists := b.mutation.Description(); exists {
-				s.SetIgnore(revision.FieldDescription)
-			}
-			if _, exists := b.mutation.ExecutedAt(); exists {
-				s.SetIgnore(revision.FieldExecutedAt)
-			}
 		}
 	}))
 	return u
@@ -958,6 +1070,20 @@ func (u *RevisionUpsertBulk) Update(set func(*RevisionUpsert)) *RevisionUpsertBu
 	return u
 }
 
+// SetDescription sets the "description" field.
+func (u *RevisionUpsertBulk) SetDescription(v string) *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetDescription(v)
+	})
+}
+
+// UpdateDescription sets the "description" field to the value that was provided on create.
+func (u *RevisionUpsertBulk) UpdateDescription() *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateDescription()
+	})
+}
+
 // SetType sets the "type" field.
 func (u *RevisionUpsertBulk) SetType(v migrate.RevisionType) *RevisionUpsertBulk {
 	return u.Update(func(s *RevisionUpsert) {
@@ -1021,6 +1147,20 @@ func (u *RevisionUpsertBulk) UpdateTotal() *RevisionUpsertBulk {
 	})
 }
 
+// SetExecutedAt sets the "executed_at" field.
+func (u *RevisionUpsertBulk) SetExecutedAt(v time.Time) *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetExecutedAt(v)
+	})
+}
+
+// UpdateExecutedAt sets the "executed_at" field to the value that was provided on create.
+func (u *RevisionUpsertBulk) UpdateExecutedAt() *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateExecutedAt()
+	})
+}
+
 // SetExecutionTime sets the "execution_time" field.
 func (u *RevisionUpsertBulk) SetExecutionTime(v time.Duration) *RevisionUpsertBulk {
 	return u.Update(func(s *RevisionUpsert) {
@@ -1133,6 +1273,27 @@ func (u *RevisionUpsertBulk) UpdateOperatorVersion() *RevisionUpsertBulk {
 	})
 }
 
+// SetEtag sets the "etag" field.
+func (u *RevisionUpsertBulk) SetEtag(v int) *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.SetEtag(v)
+	})
+}
+
+// AddEtag adds v to the "etag" field.
+func (u *RevisionUpsertBulk) AddEtag(v int) *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.AddEtag(v)
+	})
+}
+
+// UpdateEtag sets the "etag" field to the value that was provided on create.
+func (u *RevisionUpsertBulk) UpdateEtag() *RevisionUpsertBulk {
+	return u.Update(func(s *RevisionUpsert) {
+		s.UpdateEtag()
+	})
+}
+
 // Exec executes the query.
 func (u *RevisionUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
@@ -48,6 +48,9 @@ func (rd *RevisionDelete) sqlExec(ctx context.Context) (int, error) {
 	_spec := sqlgraph.NewDeleteSpec(revision.Table, sqlgraph.NewFieldSpec(revision.FieldID, field.TypeString))
 	_spec.Node.Schema = rd.schemaConfig.Revision
 	ctx = internal.NewSchemaConfigContext(ctx, rd.schemaConfig)
+	if t := rd.tableConfig.Revision; t != "" {
+		_spec.Node.Table = t
+	}
 	if ps := rd.mutation.predicates; len(ps) > 0 {
 		_spec.Predicate = func(selector *sql.Selector) {
 			for i := range ps {
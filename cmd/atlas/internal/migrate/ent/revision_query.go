@@ -350,6 +350,9 @@ func (rq *RevisionQuery) sqlAll(ctx context.Context, hooks ...queryHook) ([]*Rev
 	}
 	_spec.Node.Schema = rq.schemaConfig.Revision
 	ctx = internal.NewSchemaConfigContext(ctx, rq.schemaConfig)
+	if t := rq.tableConfig.Revision; t != "" {
+		_spec.Node.Table = t
+	}
 	for i := range hooks {
 		hooks[i](ctx, _spec)
 	}
@@ -366,6 +369,9 @@ func (rq *RevisionQuery) sqlCount(ctx context.Context) (int, error) {
 	_spec := rq.querySpec()
 	_spec.Node.Schema = rq.schemaConfig.Revision
 	ctx = internal.NewSchemaConfigContext(ctx, rq.schemaConfig)
+	if t := rq.tableConfig.Revision; t != "" {
+		_spec.Node.Table = t
+	}
 	_spec.Node.Columns = rq.ctx.Fields
 	if len(rq.ctx.Fields) > 0 {
 		_spec.Unique = rq.ctx.Unique != nil && *rq.ctx.Unique
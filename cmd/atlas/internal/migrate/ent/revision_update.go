@@ -35,6 +35,20 @@ func (ru *RevisionUpdate) Where(ps ...predicate.Revision) *RevisionUpdate {
 	return ru
 }
 
+// SetDescription sets the "description" field.
+func (ru *RevisionUpdate) SetDescription(s string) *RevisionUpdate {
+	ru.mutation.SetDescription(s)
+	return ru
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (ru *RevisionUpdate) SetNillableDescription(s *string) *RevisionUpdate {
+	if s != nil {
+		ru.SetDescription(*s)
+	}
+	return ru
+}
+
 // SetType sets the "type" field.
 func (ru *RevisionUpdate) SetType(mt migrate.RevisionType) *RevisionUpdate {
 	ru.mutation.ResetType()
@@ -98,6 +112,20 @@ func (ru *RevisionUpdate) AddTotal(i int) *RevisionUpdate {
 	return ru
 }
 
+// SetExecutedAt sets the "executed_at" field.
+func (ru *RevisionUpdate) SetExecutedAt(t time.Time) *RevisionUpdate {
+	ru.mutation.SetExecutedAt(t)
+	return ru
+}
+
+// SetNillableExecutedAt sets the "executed_at" field if the given value is not nil.
+func (ru *RevisionUpdate) SetNillableExecutedAt(t *time.Time) *RevisionUpdate {
+	if t != nil {
+		ru.SetExecutedAt(*t)
+	}
+	return ru
+}
+
 // SetExecutionTime sets the "execution_time" field.
 func (ru *RevisionUpdate) SetExecutionTime(t time.Duration) *RevisionUpdate {
 	ru.mutation.ResetExecutionTime()
@@ -205,6 +233,27 @@ func (ru *RevisionUpdate) SetNillableOperatorVersion(s *string) *RevisionUpdate
 	return ru
 }
 
+// SetEtag sets the "etag" field.
+func (ru *RevisionUpdate) SetEtag(i int) *RevisionUpdate {
+	ru.mutation.ResetEtag()
+	ru.mutation.SetEtag(i)
+	return ru
+}
+
+// SetNillableEtag sets the "etag" field if the given value is not nil.
+func (ru *RevisionUpdate) SetNillableEtag(i *int) *RevisionUpdate {
+	if i != nil {
+		ru.SetEtag(*i)
+	}
+	return ru
+}
+
+// AddEtag adds i to the "etag" field.
+func (ru *RevisionUpdate) AddEtag(i int) *RevisionUpdate {
+	ru.mutation.AddEtag(i)
+	return ru
+}
+
 // Mutation returns the RevisionMutation object of the builder.
 func (ru *RevisionUpdate) Mutation() *RevisionMutation {
 	return ru.mutation
@@ -249,6 +298,11 @@ func (ru *RevisionUpdate) check() error {
 			return &ValidationError{Name: "total", err: fmt.Errorf(`ent: validator failed for field "Revision.total": %w`, err)}
 		}
 	}
+	if v, ok := ru.mutation.Etag(); ok {
+		if err := revision.EtagValidator(v); err != nil {
+			return &ValidationError{Name: "etag", err: fmt.Errorf(`ent: validator failed for field "Revision.etag": %w`, err)}
+		}
+	}
 	return nil
 }
 
@@ -264,6 +318,9 @@ func (ru *RevisionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 			}
 		}
 	}
+	if value, ok := ru.mutation.Description(); ok {
+		_spec.SetField(revision.FieldDescription, field.TypeString, value)
+	}
 	if value, ok := ru.mutation.GetType(); ok {
 		_spec.SetField(revision.FieldType, field.TypeUint, value)
 	}
@@ -282,6 +339,9 @@ func (ru *RevisionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := ru.mutation.AddedTotal(); ok {
 		_spec.AddField(revision.FieldTotal, field.TypeInt, value)
 	}
+	if value, ok := ru.mutation.ExecutedAt(); ok {
+		_spec.SetField(revision.FieldExecutedAt, field.TypeTime, value)
+	}
 	if value, ok := ru.mutation.ExecutionTime(); ok {
 		_spec.SetField(revision.FieldExecutionTime, field.TypeInt64, value)
 	}
@@ -317,8 +377,17 @@ func (ru *RevisionUpdate) sqlSave(ctx context.Context) (n int, err error) {
 	if value, ok := ru.mutation.OperatorVersion(); ok {
 		_spec.SetField(revision.FieldOperatorVersion, field.TypeString, value)
 	}
+	if value, ok := ru.mutation.Etag(); ok {
+		_spec.SetField(revision.FieldEtag, field.TypeInt, value)
+	}
+	if value, ok := ru.mutation.AddedEtag(); ok {
+		_spec.AddField(revision.FieldEtag, field.TypeInt, value)
+	}
 	_spec.Node.Schema = ru.schemaConfig.Revision
 	ctx = internal.NewSchemaConfigContext(ctx, ru.schemaConfig)
+	if t := ru.tableConfig.Revision; t != "" {
+		_spec.Node.Table = t
+	}
 	if n, err = sqlgraph.UpdateNodes(ctx, ru.driver, _spec); err != nil {
 		if _, ok := err.(*sqlgraph.NotFoundError); ok {
 			err = &NotFoundError{revision.Label}
@@ -339,6 +408,20 @@ type RevisionUpdateOne struct {
 	mutation *RevisionMutation
 }
 
+// SetDescription sets the "description" field.
+func (ruo *RevisionUpdateOne) SetDescription(s string) *RevisionUpdateOne {
+	ruo.mutation.SetDescription(s)
+	return ruo
+}
+
+// SetNillableDescription sets the "description" field if the given value is not nil.
+func (ruo *RevisionUpdateOne) SetNillableDescription(s *string) *RevisionUpdateOne {
+	if s != nil {
+		ruo.SetDescription(*s)
+	}
+	return ruo
+}
+
 // SetType sets the "type" field.
 func (ruo *RevisionUpdateOne) SetType(mt migrate.RevisionType) *RevisionUpdateOne {
 	ruo.mutation.ResetType()
@@ -402,6 +485,20 @@ func (ruo *RevisionUpdateOne) AddTotal(i int) *RevisionUpdateOne {
 	return ruo
 }
 
+// SetExecutedAt sets the "executed_at" field.
+func (ruo *RevisionUpdateOne) SetExecutedAt(t time.Time) *RevisionUpdateOne {
+	ruo.mutation.SetExecutedAt(t)
+	return ruo
+}
+
+// SetNillableExecutedAt sets the "executed_at" field if the given value is not nil.
+func (ruo *RevisionUpdateOne) SetNillableExecutedAt(t *time.Time) *RevisionUpdateOne {
+	if t != nil {
+		ruo.SetExecutedAt(*t)
+	}
+	return ruo
+}
+
 // SetExecutionTime sets the "execution_time" field.
 func (ruo *RevisionUpdateOne) SetExecutionTime(t time.Duration) *RevisionUpdateOne {
 	ruo.mutation.ResetExecutionTime()
@@ -509,6 +606,27 @@ func (ruo *RevisionUpdateOne) SetNillableOperatorVersion(s *string) *RevisionUpd
 	return ruo
 }
 
+// SetEtag sets the "etag" field.
+func (ruo *RevisionUpdateOne) SetEtag(i int) *RevisionUpdateOne {
+	ruo.mutation.ResetEtag()
+	ruo.mutation.SetEtag(i)
+	return ruo
+}
+
+// SetNillableEtag sets the "etag" field if the given value is not nil.
+func (ruo *RevisionUpdateOne) SetNillableEtag(i *int) *RevisionUpdateOne {
+	if i != nil {
+		ruo.SetEtag(*i)
+	}
+	return ruo
+}
+
+// AddEtag adds i to the "etag" field.
+func (ruo *RevisionUpdateOne) AddEtag(i int) *RevisionUpdateOne {
+	ruo.mutation.AddEtag(i)
+	return ruo
+}
+
 // Mutation returns the RevisionMutation object of the builder.
 func (ruo *RevisionUpdateOne) Mutation() *RevisionMutation {
 	return ruo.mutation
@@ -566,6 +684,11 @@ func (ruo *RevisionUpdateOne) check() error {
 			return &ValidationError{Name: "total", err: fmt.Errorf(`ent: validator failed for field "Revision.total": %w`, err)}
 		}
 	}
+	if v, ok := ruo.mutation.Etag(); ok {
+		if err := revision.EtagValidator(v); err != nil {
+			return &ValidationError{Name: "etag", err: fmt.Errorf(`ent: validator failed for field "Revision.etag": %w`, err)}
+		}
+	}
 	return nil
 }
 
@@ -598,6 +721,9 @@ func (ruo *RevisionUpdateOne) sqlSave(ctx context.Context) (_node *Revision, err
 			}
 		}
 	}
+	if value, ok := ruo.mutation.Description(); ok {
+		_spec.SetField(revision.FieldDescription, field.TypeString, value)
+	}
 	if value, ok := ruo.mutation.GetType(); ok {
 		_spec.SetField(revision.FieldType, field.TypeUint, value)
 	}
@@ -616,6 +742,9 @@ func (ruo *RevisionUpdateOne) sqlSave(ctx context.Context) (_node *Revision, err
 	if value, ok := ruo.mutation.AddedTotal(); ok {
 		_spec.AddField(revision.FieldTotal, field.TypeInt, value)
 	}
+	if value, ok := ruo.mutation.ExecutedAt(); ok {
+		_spec.SetField(revision.FieldExecutedAt, field.TypeTime, value)
+	}
 	if value, ok := ruo.mutation.ExecutionTime(); ok {
 		_spec.SetField(revision.FieldExecutionTime, field.TypeInt64, value)
 	}
@@ -651,8 +780,17 @@ func (ruo *RevisionUpdateOne) sqlSave(ctx context.Context) (_node *Revision, err
 	if value, ok := ruo.mutation.OperatorVersion(); ok {
 		_spec.SetField(revision.FieldOperatorVersion, field.TypeString, value)
 	}
+	if value, ok := ruo.mutation.Etag(); ok {
+		_spec.SetField(revision.FieldEtag, field.TypeInt, value)
+	}
+	if value, ok := ruo.mutation.AddedEtag(); ok {
+		_spec.AddField(revision.FieldEtag, field.TypeInt, value)
+	}
 	_spec.Node.Schema = ruo.schemaConfig.Revision
 	ctx = internal.NewSchemaConfigContext(ctx, ruo.schemaConfig)
+	if t := ruo.tableConfig.Revision; t != "" {
+		_spec.Node.Table = t
+	}
 	_node = &Revision{config: ruo.config}
 	_spec.Assign = _node.assignValues
 	_spec.ScanValues = _node.scanValues
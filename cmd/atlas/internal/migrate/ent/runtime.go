@@ -34,4 +34,10 @@ func init() {
 	revision.DefaultTotal = revisionDescTotal.Default.(int)
 	// revision.TotalValidator is a validator for the "total" field. It is called by the builders before save.
 	revision.TotalValidator = revisionDescTotal.Validators[0].(func(int) error)
+	// revisionDescEtag is the schema descriptor for etag field.
+	revisionDescEtag := revisionFields[12].Descriptor()
+	// revision.DefaultEtag holds the default value on creation for the etag field.
+	revision.DefaultEtag = revisionDescEtag.Default.(int)
+	// revision.EtagValidator is a validator for the "etag" field. It is called by the builders before save.
+	revision.EtagValidator = revisionDescEtag.Validators[0].(func(int) error)
 }
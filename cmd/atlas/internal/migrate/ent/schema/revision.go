@@ -29,8 +29,7 @@ func (Revision) Fields() []ent.Field {
 		field.String("id").
 			StorageKey("version").
 			Immutable(),
-		field.String("description").
-			Immutable(),
+		field.String("description"),
 		field.Uint("type").
 			GoType(migrate.RevisionType(0)).
 			Default(uint(migrate.RevisionTypeExecute)),
@@ -40,8 +39,7 @@ func (Revision) Fields() []ent.Field {
 		field.Int("total").
 			NonNegative().
 			Default(0),
-		field.Time("executed_at").
-			Immutable(),
+		field.Time("executed_at"),
 		field.Int64("execution_time").
 			GoType(time.Duration(0)),
 		field.Text("error").
@@ -52,6 +50,9 @@ func (Revision) Fields() []ent.Field {
 		field.Strings("partial_hashes").
 			Optional(),
 		field.String("operator_version"),
+		field.Int("etag").
+			NonNegative().
+			Default(0),
 	}
 }
 
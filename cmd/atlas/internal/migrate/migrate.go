@@ -13,9 +13,13 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 
 	"ariga.io/atlas/cmd/atlas/internal/migrate/ent"
+	entmigrate "ariga.io/atlas/cmd/atlas/internal/migrate/ent/migrate"
 	"ariga.io/atlas/cmd/atlas/internal/migrate/ent/revision"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/mysql"
@@ -24,8 +28,10 @@ import (
 	"ariga.io/atlas/sql/sqltool"
 
 	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
 	"entgo.io/ent/dialect/sql"
 	entschema "entgo.io/ent/dialect/sql/schema"
+	"entgo.io/ent/schema/field"
 	"github.com/google/uuid"
 )
 
@@ -42,9 +48,11 @@ type (
 	}
 	// EntRevisions provides implementation for the migrate.RevisionReadWriter interface.
 	EntRevisions struct {
-		ac     *sqlclient.Client // underlying Atlas client
-		ec     *ent.Client       // underlying Ent client
-		schema string            // name of the schema the revision table resides in
+		ac      *sqlclient.Client // underlying Atlas client
+		ec      *ent.Client       // underlying Ent client
+		schema  string            // name of the schema the revision table resides in
+		table   string            // name of the revision table, if not the default
+		columns map[string]string // additional columns and their values
 	}
 
 	// Option allows to configure EntRevisions by using functional arguments.
@@ -60,7 +68,8 @@ func (r *EntRevisions) Dialect() string {
 }
 
 // RevisionsForClient creates a new RevisionReadWriter for the given sqlclient.Client.
-func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string) (RevisionReadWriter, error) {
+// The options are applied only in case the driver does not provide its own RevisionReadWriter.
+func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string, opts ...Option) (RevisionReadWriter, error) {
 	// If the driver supports the RevisionReadWriter interface, use it.
 	if drv, ok := ac.Driver.(interface {
 		RevisionsReadWriter(context.Context, string) (migrate.RevisionReadWriter, error)
@@ -74,7 +83,7 @@ func RevisionsForClient(ctx context.Context, ac *sqlclient.Client, schema string
 		}
 		return nil, fmt.Errorf("unexpected revision read-writer type: %T", rrw)
 	}
-	return NewEntRevisions(ctx, ac, WithSchema(schema))
+	return NewEntRevisions(ctx, ac, append([]Option{WithSchema(schema)}, opts...)...)
 }
 
 // NewEntRevisions creates a new EntRevisions with the given sqlclient.Client.
@@ -86,7 +95,11 @@ func NewEntRevisions(ctx context.Context, ac *sqlclient.Client, opts ...Option)
 		}
 	}
 	// Create the connection with the underlying migrate.Driver to have it inside a possible transaction.
-	entopts := []ent.Option{ent.Driver(sql.NewDriver(r.Dialect(), sql.Conn{ExecQuerier: r.ac.Driver}))}
+	entopts := []ent.Option{
+		ent.Driver(sql.NewDriver(r.Dialect(), sql.Conn{ExecQuerier: r.ac.Driver})),
+		// Tell Ent to operate on the configured table, if not the default.
+		ent.AlternateTable(ent.TableConfig{Revision: r.table}),
+	}
 	// SQLite does not support multiple schema, therefore schema-config is only needed for other dialects.
 	if r.Dialect() != dialect.SQLite {
 		// Make sure the schema to store the revisions table in does exist.
@@ -119,9 +132,46 @@ func WithSchema(s string) Option {
 	}
 }
 
+// WithTable configures the name of the revision table. An empty
+// string means the default name (atlas_schema_revisions) is used.
+func WithTable(t string) Option {
+	return func(r *EntRevisions) error {
+		r.table = t
+		return nil
+	}
+}
+
+// WithColumns configures additional columns to be added to the revision table,
+// and the values they are set to when a revision is written. For example, the
+// identifier of the deployment that applied the revision, or its commit SHA.
+func WithColumns(columns map[string]string) Option {
+	return func(r *EntRevisions) error {
+		for name := range columns {
+			if name == "" {
+				return errors.New("revision column name cannot be empty")
+			}
+			for _, c := range entmigrate.AtlasSchemaRevisionsColumns {
+				if strings.EqualFold(c.Name, name) {
+					return fmt.Errorf("revision column %q conflicts with a builtin column", name)
+				}
+			}
+		}
+		r.columns = columns
+		return nil
+	}
+}
+
+// Table returns the name of the revision table.
+func (r *EntRevisions) Table() string {
+	if r.table != "" {
+		return r.table
+	}
+	return revision.Table
+}
+
 // Ident returns the table identifier.
 func (r *EntRevisions) Ident() *migrate.TableIdent {
-	return &migrate.TableIdent{Name: revision.Table, Schema: r.schema}
+	return &migrate.TableIdent{Name: r.Table(), Schema: r.schema}
 }
 
 // ReadRevision reads a revision from the revisions table.
@@ -175,15 +225,74 @@ func (r *EntRevisions) CurrentRevision(ctx context.Context) (*migrate.Revision,
 }
 
 // WriteRevision writes a revision to the revisions table.
+//
+// Writes are compare-and-set operations on the etag of the revision: an existing
+// row is updated only if its etag matches the one the given revision was read with.
+// In case the row was modified by another process in the meantime, the write is
+// rejected with a migrate.RevisionConflictError. On success, the etag is advanced.
 func (r *EntRevisions) WriteRevision(ctx context.Context, rev *migrate.Revision) error {
 	if rev.Version == revisionID {
 		return errors.New("writing the revision-table identifier is not allowed")
 	}
-	return r.ec.Revision.Create().
-		SetRevision(rev).
-		OnConflict(sql.ConflictColumns(revision.FieldID)).
-		UpdateNewValues().
-		Exec(ctx)
+	n, err := r.ec.Revision.Update().
+		Where(revision.ID(rev.Version), revision.Etag(rev.Etag)).
+		SetDescription(rev.Description).
+		SetType(rev.Type).
+		SetApplied(rev.Applied).
+		SetTotal(rev.Total).
+		SetExecutedAt(rev.ExecutedAt).
+		SetExecutionTime(rev.ExecutionTime).
+		SetError(rev.Error).
+		SetErrorStmt(rev.ErrorStmt).
+		SetHash(rev.Hash).
+		SetPartialHashes(rev.PartialHashes).
+		SetOperatorVersion(rev.OperatorVersion).
+		AddEtag(1).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		// No row was updated. Either the revision does not exist, or
+		// it was modified since it was read. The insert below fails in
+		// the latter case, as the version is the primary key.
+		err := r.ec.Revision.Create().
+			SetRevision(rev).
+			SetEtag(rev.Etag + 1).
+			Exec(ctx)
+		if ent.IsConstraintError(err) {
+			return migrate.RevisionConflictError{Version: rev.Version, Etag: rev.Etag}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	rev.Etag++
+	return r.writeColumns(ctx, rev.Version)
+}
+
+// writeColumns sets the values of the additional columns of the given revision.
+func (r *EntRevisions) writeColumns(ctx context.Context, v string) error {
+	if len(r.columns) == 0 {
+		return nil
+	}
+	u := sql.Dialect(r.Dialect()).Update(r.Table())
+	if r.Dialect() != dialect.SQLite && r.schema != "" {
+		u.Schema(r.schema)
+	}
+	names := make([]string, 0, len(r.columns))
+	for name := range r.columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		u.Set(name, r.columns[name])
+	}
+	query, args := u.Where(sql.EQ(revision.FieldID, v)).Query()
+	if _, err := r.ac.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("write revision columns: %w", err)
+	}
+	return nil
 }
 
 // DeleteRevision deletes a revision from the revisions table.
@@ -232,7 +341,7 @@ func (r *EntRevisions) Migrate(ctx context.Context) (err error) {
 			}()
 		}
 	}
-	return c.Schema.Create(ctx, entschema.WithDropColumn(true), entschema.WithDiffHook(func(next entschema.Differ) entschema.Differ {
+	return entmigrate.Create(ctx, c.Schema, []*entschema.Table{r.entTable()}, entschema.WithDropColumn(true), entschema.WithDiffHook(func(next entschema.Differ) entschema.Differ {
 		return entschema.DiffFunc(func(current, desired *schema.Schema) ([]schema.Change, error) {
 			changes, err := next.Diff(current, desired)
 			if err != nil {
@@ -243,13 +352,26 @@ func (r *EntRevisions) Migrate(ctx context.Context) (err error) {
 			for i := range changes {
 				switch cs := changes[i].(type) {
 				case *schema.AddTable:
-					if cs.T.Name == revision.Table {
+					if cs.T.Name == r.Table() {
 						return schema.Changes{cs}, nil
 					}
 				case *schema.ModifyTable:
-					if cs.T.Name == revision.Table {
-						return schema.Changes{cs}, nil
+					if cs.T.Name != r.Table() {
+						continue
+					}
+					// Additional columns are not dropped, as they might be populated by
+					// other executions (e.g., a previous 'migrate apply' that configured
+					// them), and dropping them loses the deployment metadata they hold.
+					cs.Changes = slices.DeleteFunc(cs.Changes, func(c schema.Change) bool {
+						d, ok := c.(*schema.DropColumn)
+						return ok && !slices.ContainsFunc(entmigrate.AtlasSchemaRevisionsTable.Columns, func(c *entschema.Column) bool {
+							return c.Name == d.C.Name
+						})
+					})
+					if len(cs.Changes) == 0 {
+						return nil, nil
 					}
+					return schema.Changes{cs}, nil
 				}
 			}
 			return nil, nil
@@ -257,6 +379,27 @@ func (r *EntRevisions) Migrate(ctx context.Context) (err error) {
 	}))
 }
 
+// entTable returns the Ent definition of the revision table, renamed
+// and extended with the additional columns, if configured.
+func (r *EntRevisions) entTable() *entschema.Table {
+	if r.table == "" && len(r.columns) == 0 {
+		return entmigrate.AtlasSchemaRevisionsTable
+	}
+	t := *entmigrate.AtlasSchemaRevisionsTable
+	t.Name = r.Table()
+	t.Annotation = &entsql.Annotation{Table: t.Name}
+	t.Columns = slices.Clone(t.Columns)
+	names := make([]string, 0, len(r.columns))
+	for name := range r.columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		t.Columns = append(t.Columns, &entschema.Column{Name: name, Type: field.TypeString, Nullable: true})
+	}
+	return &t
+}
+
 // revisionID holds the column "id" ("version") of the revision that holds the identifier of the
 // connected revisions table. The "." prefix ensures the is it lower than any other revisions.
 const revisionID = ".atlas_cloud_identifier"
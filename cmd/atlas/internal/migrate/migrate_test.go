@@ -6,9 +6,16 @@ package migrate
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -40,6 +47,33 @@ func TestFormatter(t *testing.T) {
 	require.Equal(t, sqltool.FlywayFormatter, f)
 }
 
+func TestStmtExecutor(t *testing.T) {
+	_, err := StmtExecutor("ftp://example.com")
+	require.EqualError(t, err, `unsupported executor url scheme "ftp"`)
+
+	var (
+		reqs   []StmtRequest
+		status = http.StatusAccepted
+		srv    = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req StmtRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			reqs = append(reqs, req)
+			w.WriteHeader(status)
+		}))
+		f    = migrate.NewLocalFile("1_init.sql", []byte("CREATE TABLE t(c int);"))
+		stmt = &migrate.Stmt{Text: "CREATE TABLE t(c int);"}
+	)
+	defer srv.Close()
+	x, err := StmtExecutor(srv.URL)
+	require.NoError(t, err)
+	require.ErrorIs(t, x.ExecStmt(context.Background(), f, stmt), migrate.ErrStmtPending)
+	require.Equal(t, []StmtRequest{{Version: "1", Description: "init", File: "1_init.sql", Stmt: stmt.Text}}, reqs)
+	status = http.StatusNoContent
+	require.NoError(t, x.ExecStmt(context.Background(), f, stmt))
+	status = http.StatusForbidden
+	require.EqualError(t, x.ExecStmt(context.Background(), f, stmt), "unexpected status code 403 from executor")
+}
+
 func TestRevisionsForClient(t *testing.T) {
 	ctx := context.Background()
 	c, err := sqlclient.Open(ctx, "sqlite://?mode=memory")
@@ -73,6 +107,60 @@ func TestNewEntRevisions(t *testing.T) {
 	runRevisionsTests(ctx, t, c.Driver, r)
 }
 
+func TestEntRevisions_Table(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, "sqlite://?mode=memory")
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = NewEntRevisions(ctx, c, WithColumns(map[string]string{"hash": "x"}))
+	require.EqualError(t, err, `revision column "hash" conflicts with a builtin column`)
+
+	r, err := NewEntRevisions(ctx, c, WithTable("deployments"), WithColumns(map[string]string{"deploy_id": "42", "git_sha": "abc"}))
+	require.NoError(t, err)
+	require.Equal(t, &migrate.TableIdent{Name: "deployments"}, r.Ident())
+	require.NoError(t, r.Migrate(ctx))
+	s, err := c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 1)
+	tb, ok := s.Table("deployments")
+	require.True(t, ok)
+	_, ok = tb.Column("deploy_id")
+	require.True(t, ok)
+
+	rev := &migrate.Revision{Version: "1", Description: "init", ExecutedAt: time.Now(), Hash: "hash"}
+	require.NoError(t, r.WriteRevision(ctx, rev))
+	rev.Applied = 1
+	require.NoError(t, r.WriteRevision(ctx, rev))
+	revs, err := r.ReadRevisions(ctx)
+	require.NoError(t, err)
+	require.Len(t, revs, 1)
+	require.Equal(t, 1, revs[0].Applied)
+	var id, sha string
+	require.NoError(t, c.DB.QueryRowContext(ctx, "SELECT deploy_id, git_sha FROM deployments WHERE version = '1'").Scan(&id, &sha))
+	require.Equal(t, "42", id)
+	require.Equal(t, "abc", sha)
+	cur, err := r.CurrentRevision(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "1", cur.Version)
+	id, err = r.ID(ctx, "v0.10.1")
+	require.NoError(t, err)
+	require.NotEmpty(t, id)
+	require.NoError(t, r.DeleteRevision(ctx, "1"))
+	_, err = r.ReadRevision(ctx, "1")
+	require.ErrorIs(t, err, migrate.ErrRevisionNotExist)
+
+	// Values that match the default table name are written as is.
+	rev = &migrate.Revision{Version: "2", Description: revision.Table, ExecutedAt: time.Now(), Hash: "hash"}
+	require.NoError(t, r.WriteRevision(ctx, rev))
+	rev, err = r.ReadRevision(ctx, "2")
+	require.NoError(t, err)
+	require.Equal(t, revision.Table, rev.Description)
+	s, err = c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	_, ok = s.Table(revision.Table)
+	require.False(t, ok, "default table should not be created")
+}
+
 func TestDirURL(t *testing.T) {
 	localDir := t.TempDir()
 	tests := []struct {
@@ -164,6 +252,34 @@ func runRevisionsTests(ctx context.Context, t *testing.T, drv migrate.Driver, r
 	require.NoError(t, err)
 	require.Equal(t, "2", cur.Version)
 
+	// Writes based on a stale revision are rejected.
+	stale, err := r.ReadRevision(ctx, "2")
+	require.NoError(t, err)
+	fresh := *stale
+	fresh.Applied = 1
+	require.NoError(t, r.WriteRevision(ctx, &fresh))
+	require.Equal(t, stale.Etag+1, fresh.Etag)
+	stale.Applied = 2
+	err = r.WriteRevision(ctx, stale)
+	require.ErrorAs(t, err, &migrate.RevisionConflictError{})
+	require.EqualError(t, err, `sql/migrate: revision "2" was modified concurrently (expected etag 2): another process may be executing migrations`)
+	cur, err = r.ReadRevision(ctx, "2")
+	require.NoError(t, err)
+	require.Equal(t, 1, cur.Applied)
+	require.Equal(t, fresh.Etag, cur.Etag)
+
+	// Re-writing a revision updates all its mutable fields.
+	etag := cur.Etag
+	cur.Description = "changed"
+	cur.ExecutedAt = cur.ExecutedAt.Add(time.Hour)
+	require.NoError(t, r.WriteRevision(ctx, cur))
+	require.Equal(t, etag+1, cur.Etag)
+	rewritten, err := r.ReadRevision(ctx, "2")
+	require.NoError(t, err)
+	require.Equal(t, "changed", rewritten.Description)
+	require.True(t, cur.ExecutedAt.Equal(rewritten.ExecutedAt))
+	require.Equal(t, etag+1, rewritten.Etag)
+
 	revs, err := r.ReadRevisions(ctx)
 	require.NoError(t, err)
 	require.Len(t, revs, 2)
@@ -224,3 +340,103 @@ func (m *mockDriver) RevisionsReadWriter(context.Context, string) (migrate.Revis
 func (*mockrrw) CurrentRevision(context.Context) (*migrate.Revision, error) { return nil, nil }
 func (*mockrrw) Migrate(context.Context) error                              { return nil }
 func (*mockrrw) ID(context.Context, string) (string, error)                 { return "", nil }
+
+func TestSignDir(t *testing.T) {
+	var dir migrate.MemDir
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t(c int);")))
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	require.ErrorIs(t, SignDir(&dir, key), migrate.ErrChecksumNotFound)
+
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(&dir, sum))
+	require.EqualError(t, VerifyDir(&dir, key.Public()), "migration directory is not signed: atlas.sum.sig was not found")
+	require.NoError(t, SignDir(&dir, key))
+	require.NoError(t, VerifyDir(&dir, key.Public()))
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	require.ErrorIs(t, VerifyDir(&dir, other.Public()), ErrInvalidSignature)
+
+	require.NoError(t, dir.WriteFile("2.sql", []byte("CREATE TABLE t2(c int);")))
+	sum, err = dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(&dir, sum))
+	require.ErrorIs(t, VerifyDir(&dir, key.Public()), ErrInvalidSignature)
+}
+
+func TestReplayCache(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, "sqlite://?mode=memory")
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.ExecContext(ctx, "CREATE TABLE t(c int NOT NULL)")
+	require.NoError(t, err)
+	r, err := c.InspectRealm(ctx, nil)
+	require.NoError(t, err)
+
+	cache := &ReplayCache{Path: filepath.Join(t.TempDir(), "cache", "dev.snap"), Dev: "sqlite3", Marshaler: c, Evaluator: c}
+	got, err := cache.Load(ctx, "h1:sum")
+	require.NoError(t, err)
+	require.Nil(t, got, "cache file does not exist")
+	require.NoError(t, cache.Store(ctx, "h1:sum", r))
+
+	got, err = cache.Load(ctx, "h1:other")
+	require.NoError(t, err)
+	require.Nil(t, got, "stale key")
+	got, err = cache.Load(ctx, "h1:sum")
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	changes, err := c.RealmDiff(r, got)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// States stored by a different dev-database are stale.
+	other := &ReplayCache{Path: cache.Path, Dev: "sqlite3@other", Marshaler: c, Evaluator: c}
+	got, err = other.Load(ctx, "h1:sum")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	// Files with unknown format are ignored.
+	require.NoError(t, os.WriteFile(cache.Path, []byte("unknown"), 0644))
+	got, err = cache.Load(ctx, "h1:sum")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestReplayCache_Replay(t *testing.T) {
+	ctx := context.Background()
+	dir := &migrate.MemDir{}
+	require.NoError(t, dir.WriteFile("1_init.sql", []byte("CREATE TABLE users (id int NOT NULL, name text DEFAULT 'a8m', PRIMARY KEY (id));\n")))
+	require.NoError(t, dir.WriteFile("2_posts.sql", []byte("CREATE TABLE posts (id int NOT NULL, author_id int NULL, PRIMARY KEY (id), CONSTRAINT author FOREIGN KEY (author_id) REFERENCES users (id));\nCREATE INDEX author ON posts (author_id);\n")))
+	sum, err := dir.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(dir, sum))
+	dev, err := sqlclient.Open(ctx, "sqlite://replay?mode=memory")
+	require.NoError(t, err)
+	defer dev.Close()
+	ex, err := migrate.NewExecutor(dev.Driver, dir, migrate.NopRevisionReadWriter{})
+	require.NoError(t, err)
+	cache := &ReplayCache{Path: filepath.Join(t.TempDir(), "dev.snap"), Dev: "sqlite3", Marshaler: dev, Evaluator: dev}
+
+	// Replay the directory and store its state in the cache.
+	replayed, err := ex.Replay(ctx, migrate.RealmConn(dev.Driver, nil), migrate.ReplayWithCache(cache))
+	require.NoError(t, err)
+	require.Len(t, replayed.Schemas, 1)
+	require.Len(t, replayed.Schemas[0].Tables, 2)
+	require.FileExists(t, cache.Path)
+
+	// Reload the state from the cache, without replaying the directory.
+	cached, err := ex.Replay(ctx, migrate.RealmConn(dev.Driver, nil), migrate.ReplayWithCache(cache))
+	require.NoError(t, err)
+	require.NotSame(t, replayed, cached)
+	changes, err := dev.RealmDiff(replayed, cached)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	want, err := dev.MarshalSpec(replayed)
+	require.NoError(t, err)
+	got, err := dev.MarshalSpec(cached)
+	require.NoError(t, err)
+	require.Equal(t, string(want), string(got))
+}
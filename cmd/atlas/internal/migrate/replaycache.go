@@ -0,0 +1,135 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+)
+
+// snapshotMagic prefixes the replay cache files, and
+// its last byte holds the version of the file format.
+var snapshotMagic = []byte("ATLSNAP\x02")
+
+// ReplayCache implements migrate.ReplayCache by storing the last replayed state of a
+// migration directory in a local file. The file starts with a binary header that holds
+// the state key (derived from the atlas.sum hash) and the dev-database identifier,
+// followed by the state encoded as a gzip-compressed HCL document. Hence, loading a
+// cached state only requires verifying its header and decoding the document, and no
+// statements are executed on the database.
+//
+// Note, the state is encoded using the HCL codec of the dev-database. Therefore, only
+// what is representable in HCL is stored, and inspection-only attributes (e.g., the
+// CREATE statements or the index origins recorded by SQLite) are not restored on load.
+// To ensure the cached state is decoded the same way it was encoded, states stored by
+// a different dev-database driver or version are considered stale.
+type ReplayCache struct {
+	Path      string              // Path of the cache file.
+	Dev       string              // Dev-database identifier, e.g., driver name and version.
+	Marshaler schemahcl.Marshaler // Codec of the dev-database.
+	Evaluator schemahcl.Evaluator
+}
+
+var _ migrate.ReplayCache = (*ReplayCache)(nil)
+
+// Load implements migrate.ReplayCache. A nil realm is returned in case the cache file
+// does not exist, or the state it holds was stored with a different key or dev-database.
+func (c *ReplayCache) Load(_ context.Context, key string) (*schema.Realm, error) {
+	f, err := os.Open(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	magic := make([]byte, len(snapshotMagic))
+	// Files in unknown formats are considered stale.
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, snapshotMagic) {
+		return nil, nil
+	}
+	for _, want := range []string{key, c.Dev} {
+		n, err := binary.ReadUvarint(r)
+		if err != nil || n != uint64(len(want)) {
+			return nil, nil
+		}
+		stored := make([]byte, n)
+		if _, err := io.ReadFull(r, stored); err != nil || string(stored) != want {
+			return nil, nil
+		}
+	}
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading replay cache %q: %w", c.Path, err)
+	}
+	defer zr.Close()
+	doc, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("reading replay cache %q: %w", c.Path, err)
+	}
+	p := hclparse.NewParser()
+	if _, diags := p.ParseHCL(doc, filepath.Base(c.Path)); diags.HasErrors() {
+		return nil, fmt.Errorf("parsing replay cache %q: %w", c.Path, diags)
+	}
+	realm := &schema.Realm{}
+	if err := c.Evaluator.Eval(p, realm, nil); err != nil {
+		return nil, fmt.Errorf("decoding replay cache %q: %w", c.Path, err)
+	}
+	return realm, nil
+}
+
+// Store implements migrate.ReplayCache. The cache file is replaced
+// atomically, and holds only the state of the last replay.
+func (c *ReplayCache) Store(_ context.Context, key string, r *schema.Realm) error {
+	doc, err := c.Marshaler.MarshalSpec(r)
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	b.Write(snapshotMagic)
+	for _, h := range []string{key, c.Dev} {
+		b.Write(binary.AppendUvarint(nil, uint64(len(h))))
+		b.WriteString(h)
+	}
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(doc); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), c.Path)
+}
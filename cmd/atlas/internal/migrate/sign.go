@@ -0,0 +1,154 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"ariga.io/atlas/sql/migrate"
+)
+
+// SignatureFileName is the name of the file holding the signature of a migration directory.
+// The signature is computed over the atlas.sum file, which holds the hashes of all migration
+// files in the directory. Therefore, signing it signs the contents of the directory.
+//
+// The file holds a base64-encoded signature, which is compatible with the signatures created
+// by "cosign sign-blob". Hence, the directory can be signed outside of Atlas as well:
+//
+//	cosign sign-blob --key cosign.key migrations/atlas.sum --output-signature migrations/atlas.sum.sig
+const SignatureFileName = migrate.HashFileName + ".sig"
+
+// ErrInvalidSignature is returned by VerifyDir if the signature does not match the directory.
+var ErrInvalidSignature = errors.New("migration directory signature is invalid: files were changed after they were signed")
+
+// SignDir signs the integrity file of the migration directory using
+// the given key, and writes the signature to the SignatureFileName.
+func SignDir(dir migrate.Dir, key crypto.Signer) error {
+	sum, err := fs.ReadFile(dir, migrate.HashFileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return migrate.ErrChecksumNotFound
+		}
+		return err
+	}
+	opts := crypto.Hash(0)
+	// Ed25519 keys sign the message itself, and other keys sign its digest.
+	if _, ok := key.Public().(ed25519.PublicKey); !ok {
+		h := sha256.Sum256(sum)
+		sum, opts = h[:], crypto.SHA256
+	}
+	sig, err := key.Sign(rand.Reader, sum, opts)
+	if err != nil {
+		return fmt.Errorf("signing migration directory: %w", err)
+	}
+	return dir.WriteFile(SignatureFileName, []byte(base64.StdEncoding.EncodeToString(sig)+"\n"))
+}
+
+// VerifyDir verifies the signature of the migration directory using the given public key.
+// Note that VerifyDir does not check that the integrity file matches the directory contents,
+// which should be done by calling migrate.Validate.
+func VerifyDir(dir migrate.Dir, pub crypto.PublicKey) error {
+	sum, err := fs.ReadFile(dir, migrate.HashFileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return migrate.ErrChecksumNotFound
+		}
+		return err
+	}
+	b, err := fs.ReadFile(dir, SignatureFileName)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("migration directory is not signed: %s was not found", SignatureFileName)
+		}
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return fmt.Errorf("decoding %s: %w", SignatureFileName, err)
+	}
+	h := sha256.Sum256(sum)
+	switch pub := pub.(type) {
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, sum, sig) {
+			return ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, h[:], sig) {
+			return ErrInvalidSignature
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, h[:], sig) != nil {
+			return ErrInvalidSignature
+		}
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+	return nil
+}
+
+// ReadSigningKey reads a PEM-encoded private key from the given path. Supported
+// keys are unencrypted ECDSA, Ed25519 and RSA keys in PKCS #8 or SEC 1 formats.
+func ReadSigningKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	if block.Type == "EC PRIVATE KEY" {
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing private key %q: %w", path, err)
+	}
+	s, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return s, nil
+}
+
+// ReadVerifyKey reads a PEM-encoded public key (PKIX format) from the given path.
+func ReadVerifyKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key %q: %w", path, err)
+	}
+	return key, nil
+}
+
+// readPEM reads the first PEM block from the given path. The path may be prefixed with "file://".
+func readPEM(path string) (*pem.Block, error) {
+	path = strings.TrimPrefix(path, "file://")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading key: %w", err)
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("key %q is not PEM-encoded", path)
+	}
+	if strings.Contains(block.Type, "ENCRYPTED") {
+		return nil, fmt.Errorf("encrypted key %q is not supported", path)
+	}
+	return block, nil
+}
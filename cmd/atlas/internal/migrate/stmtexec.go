@@ -0,0 +1,130 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"ariga.io/atlas/sql/migrate"
+)
+
+type (
+	// FileStmtExecutor appends the statements to a local file instead of executing them.
+	// The statements are reported as pending, as they are expected to be executed by
+	// a DBA using separate tooling.
+	FileStmtExecutor struct {
+		Path string
+		mu   sync.Mutex
+		last string // Last file the statements were written for.
+	}
+
+	// HTTPStmtExecutor sends the statements to an HTTP endpoint instead of executing them.
+	// Each statement is sent in a separate POST request with a JSON body. Endpoints should
+	// respond with 200 or 204 if the statement was executed, or with 202 if the statement
+	// was accepted for execution (e.g., a ticket was opened for a DBA).
+	HTTPStmtExecutor struct {
+		URL    string
+		Client *http.Client
+	}
+
+	// StmtRequest is the JSON body sent by the HTTPStmtExecutor.
+	StmtRequest struct {
+		Version     string `json:"version"`
+		Description string `json:"description,omitempty"`
+		File        string `json:"file"`
+		Stmt        string `json:"stmt"`
+		Pos         int    `json:"pos"`
+	}
+)
+
+// StmtExecutor returns a migrate.StmtExecutor to proxy statements to, based on the URL scheme:
+//
+//	file://path/to/file.sql        appends the statements to the given file.
+//	https://example.com/endpoint   sends the statements to the given HTTP endpoint.
+func StmtExecutor(u string) (migrate.StmtExecutor, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, fmt.Errorf("parse executor url %q: %w", u, err)
+	}
+	switch parsed.Scheme {
+	case "file":
+		p := filepath.Join(parsed.Host, parsed.Path)
+		if p == "" {
+			return nil, fmt.Errorf("missing file path in executor url %q", u)
+		}
+		return &FileStmtExecutor{Path: p}, nil
+	case "http", "https":
+		return &HTTPStmtExecutor{URL: u, Client: http.DefaultClient}, nil
+	default:
+		return nil, fmt.Errorf("unsupported executor url scheme %q", parsed.Scheme)
+	}
+}
+
+// ExecStmt implements migrate.StmtExecutor.
+func (e *FileStmtExecutor) ExecStmt(_ context.Context, f migrate.File, stmt *migrate.Stmt) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	w, err := os.OpenFile(e.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	var b bytes.Buffer
+	if e.last != f.Name() {
+		e.last = f.Name()
+		fmt.Fprintf(&b, "-- version: %s, file: %s\n", f.Version(), f.Name())
+	}
+	b.WriteString(stmt.Text)
+	b.WriteByte('\n')
+	if _, err := w.Write(b.Bytes()); err != nil {
+		return err
+	}
+	return migrate.ErrStmtPending
+}
+
+// ExecStmt implements migrate.StmtExecutor.
+func (e *HTTPStmtExecutor) ExecStmt(ctx context.Context, f migrate.File, stmt *migrate.Stmt) error {
+	body, err := json.Marshal(&StmtRequest{
+		Version:     f.Version(),
+		Description: f.Desc(),
+		File:        f.Name(),
+		Stmt:        stmt.Text,
+		Pos:         stmt.Pos,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusAccepted:
+		return migrate.ErrStmtPending
+	default:
+		err := fmt.Errorf("unexpected status code %d from executor", resp.StatusCode)
+		if msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10)); len(bytes.TrimSpace(msg)) > 0 {
+			err = fmt.Errorf("%w: %s", err, bytes.TrimSpace(msg))
+		}
+		return err
+	}
+}
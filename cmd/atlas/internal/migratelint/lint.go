@@ -192,10 +192,63 @@ func (d *latestChange) DetectChanges(context.Context) ([]migrate.File, []migrate
 	return files[:len(files)-d.n], files[len(files)-d.n:], nil
 }
 
+// versionChange implements the ChangeDetector by selecting the files in a version range.
+type versionChange struct {
+	from, to string      // inclusive version bounds. Empty bounds are open.
+	dir      migrate.Dir // migration directory to load migration files from.
+}
+
+// VersionChanges implements the ChangeDetector interface by selecting the files between the
+// two given versions (inclusive) as new. Files before the range are considered the base, and
+// files after it are ignored. An empty from (or to) version selects the files from the first
+// (or up to the last) file in the directory. It is useful for analyzing exactly the files of
+// a release window in long migration directories.
+func VersionChanges(dir migrate.Dir, from, to string) ChangeDetector {
+	return &versionChange{from: from, to: to, dir: dir}
+}
+
+// DetectChanges implements the ChangeDetector interface.
+func (d *versionChange) DetectChanges(context.Context) ([]migrate.File, []migrate.File, error) {
+	files, err := d.dir.Files()
+	if err != nil {
+		return nil, nil, fmt.Errorf("internal/ci: reading migration directory: %w", err)
+	}
+	start, end := 0, len(files)
+	if d.from != "" {
+		if start = fileIndex(files, d.from); start == -1 {
+			return nil, nil, fmt.Errorf("migration file with version %q was not found", d.from)
+		}
+	}
+	if d.to != "" {
+		if end = fileIndex(files, d.to); end == -1 {
+			return nil, nil, fmt.Errorf("migration file with version %q was not found", d.to)
+		}
+		end++
+	}
+	if start >= end {
+		return nil, nil, fmt.Errorf("version %q is greater than version %q", d.from, d.to)
+	}
+	return files[:start], files[start:end], nil
+}
+
+// fileIndex returns the index of the file with the given version, or -1 if it was not found.
+func fileIndex(files []migrate.File, version string) int {
+	for i, f := range files {
+		if f.Version() == version {
+			return i
+		}
+	}
+	return -1
+}
+
 // DevLoader implements the ChangesLoader interface using a dev-driver.
 type DevLoader struct {
 	// Dev environment used as a sandbox instantiated to the starting point (e.g. base branch).
 	Dev *sqlclient.Client
+	// Cache is an optional cache for the state of the base files. If the state is found in
+	// the cache, it is applied on the dev environment as one plan, instead of executing the
+	// base files one by one. Note, the data inserted by the base files is not restored.
+	Cache migrate.ReplayCache
 }
 
 // LoadChanges implements the ChangesLoader interface.
@@ -279,6 +332,46 @@ func (d *DevLoader) base(ctx context.Context, base []migrate.File) (*schema.Real
 	}); i != -1 {
 		base = base[i:]
 	}
+	if d.Cache == nil || len(base) == 0 {
+		return d.exec(ctx, base)
+	}
+	sum, err := migrate.NewHashFile(base)
+	if err != nil {
+		return nil, err
+	}
+	key := "h1:" + sum.Sum()
+	cached, err := d.Cache.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("load base state from cache: %w", err)
+	}
+	if cached == nil {
+		current, err := d.exec(ctx, base)
+		if err != nil {
+			return nil, err
+		}
+		if err := d.Cache.Store(ctx, key, current); err != nil {
+			return nil, fmt.Errorf("store base state in cache: %w", err)
+		}
+		return current, nil
+	}
+	current, err := d.inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := d.Dev.RealmDiff(current, cached)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.Dev.ApplyChanges(ctx, changes); err != nil {
+		return nil, fmt.Errorf("applying cached base state: %w", err)
+	}
+	// The cached state does not hold inspection-only attributes. Hence,
+	// the dev environment is inspected to compare the next files with.
+	return d.inspect(ctx)
+}
+
+// exec executes the base files on the dev environment and returns its state.
+func (d *DevLoader) exec(ctx context.Context, base []migrate.File) (*schema.Realm, error) {
 	for _, f := range base {
 		stmts, err := d.stmts(ctx, f, false)
 		if err != nil {
@@ -15,6 +15,8 @@ import (
 
 	"ariga.io/atlas/cmd/atlas/internal/migratelint"
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
 	_ "ariga.io/atlas/sql/sqlite"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -130,3 +132,88 @@ func TestLatestChanges(t *testing.T) {
 	require.Equal(t, files[:1], base)
 	require.Equal(t, files[1:], feat)
 }
+
+func TestVersionChanges(t *testing.T) {
+	dir := &migrate.MemDir{}
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t1 (id INT)")))
+	require.NoError(t, dir.WriteFile("2.sql", []byte("CREATE TABLE t2 (id INT)")))
+	require.NoError(t, dir.WriteFile("3.sql", []byte("CREATE TABLE t3 (id INT)")))
+	files, err := dir.Files()
+	require.NoError(t, err)
+
+	base, feat, err := migratelint.VersionChanges(dir, "2", "2").DetectChanges(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, files[:1], base)
+	require.Equal(t, files[1:2], feat)
+
+	base, feat, err = migratelint.VersionChanges(dir, "2", "").DetectChanges(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, files[:1], base)
+	require.Equal(t, files[1:], feat)
+
+	base, feat, err = migratelint.VersionChanges(dir, "", "2").DetectChanges(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, base)
+	require.Equal(t, files[:2], feat)
+
+	_, _, err = migratelint.VersionChanges(dir, "4", "").DetectChanges(context.Background())
+	require.EqualError(t, err, `migration file with version "4" was not found`)
+	_, _, err = migratelint.VersionChanges(dir, "3", "1").DetectChanges(context.Background())
+	require.EqualError(t, err, `version "3" is greater than version "1"`)
+}
+
+func TestDevLoader_ReplayCache(t *testing.T) {
+	ctx := context.Background()
+	dev, err := sqlclient.Open(ctx, "sqlite://devloader?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	defer dev.Close()
+	dir := &migrate.MemDir{}
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t1 (id INT);")))
+	require.NoError(t, dir.WriteFile("2.sql", []byte("CREATE TABLE t2 (id INT);")))
+	files, err := dir.Files()
+	require.NoError(t, err)
+	var (
+		cache = replayCache{}
+		l     = &migratelint.DevLoader{Dev: dev, Cache: cache}
+	)
+	diff, err := l.LoadChanges(ctx, files[:1], files[1:])
+	require.NoError(t, err)
+	require.Len(t, cache, 1)
+	_, ok := diff.From.Schemas[0].Table("t1")
+	require.True(t, ok)
+
+	// The cached state is applied on the dev database, instead of executing the base files.
+	for k := range cache {
+		cache[k] = schema.NewRealm(schema.New("main").AddTables(schema.NewTable("cached").AddColumns(schema.NewIntColumn("id", "int"))))
+	}
+	diff, err = l.LoadChanges(ctx, files[:1], files[1:])
+	require.NoError(t, err)
+	_, ok = diff.From.Schemas[0].Table("cached")
+	require.True(t, ok)
+	_, ok = diff.From.Schemas[0].Table("t1")
+	require.False(t, ok)
+	_, ok = diff.To.Schemas[0].Table("t2")
+	require.True(t, ok)
+
+	// Changing the base files invalidates the cached state.
+	require.NoError(t, dir.WriteFile("1.sql", []byte("CREATE TABLE t3 (id INT);")))
+	files, err = dir.Files()
+	require.NoError(t, err)
+	diff, err = l.LoadChanges(ctx, files[:1], files[1:])
+	require.NoError(t, err)
+	require.Len(t, cache, 2)
+	_, ok = diff.From.Schemas[0].Table("t3")
+	require.True(t, ok)
+}
+
+// replayCache is an in-memory migrate.ReplayCache.
+type replayCache map[string]*schema.Realm
+
+func (c replayCache) Load(_ context.Context, key string) (*schema.Realm, error) {
+	return c[key], nil
+}
+
+func (c replayCache) Store(_ context.Context, key string, r *schema.Realm) error {
+	c[key] = r
+	return nil
+}
@@ -11,12 +11,16 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 	"text/template"
 	"time"
 
+	"ariga.io/atlas/cmd/atlas/lintreport"
 	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlclient"
 
@@ -39,6 +43,14 @@ type Runner struct {
 	// Analyzers defines the analysis to run on each migration file.
 	Analyzers []sqlcheck.Analyzer
 
+	// Exclude defines a list of glob patterns used to filter the changes
+	// of resources from the analysis. See schema.Matcher for more info.
+	Exclude []string
+
+	// ReplayCache is an optional cache for the state of the base files.
+	// See DevLoader.Cache for more info.
+	ReplayCache migrate.ReplayCache
+
 	// ReportWriter writes the summary report.
 	ReportWriter ReportWriter
 
@@ -135,7 +147,7 @@ func (r *Runner) summary(ctx context.Context) error {
 	r.sum.TotalFiles = len(feat)
 
 	// Load files into changes.
-	l := &DevLoader{Dev: r.Dev}
+	l := &DevLoader{Dev: r.Dev, Cache: r.ReplayCache}
 	diff, err := l.LoadChanges(ctx, base, feat)
 	if err != nil {
 		if fr := (&FileError{}); errors.As(err, &fr) {
@@ -152,7 +164,16 @@ func (r *Runner) summary(ctx context.Context) error {
 
 // analyze runs the analysis on the given files.
 func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
+	m, err := schema.NewMatcher(r.Exclude)
+	if err != nil {
+		return err
+	}
 	for _, f := range files {
+		if len(r.Exclude) > 0 {
+			if err := excludeChanges(m, f); err != nil {
+				return err
+			}
+		}
 		var (
 			es []string
 			nl = nolintRules(f)
@@ -162,6 +183,7 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 			continue
 		}
 		for _, az := range r.Analyzers {
+			n := len(fr.Reports)
 			err := func(az sqlcheck.Analyzer) (rerr error) {
 				defer func() {
 					if rc := recover(); rc != nil {
@@ -182,6 +204,10 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 			// skip emitting its error.
 			if err != nil && !nl.skipped {
 				es = append(es, err.Error())
+				// Reports written by a failing analyzer are errors.
+				for i := n; i < len(fr.Reports); i++ {
+					fr.failed = append(fr.failed, i)
+				}
 			}
 		}
 		fr.Error = strings.Join(es, "; ")
@@ -195,6 +221,24 @@ func (r *Runner) analyze(ctx context.Context, files []*sqlcheck.File) error {
 	return nil
 }
 
+// excludeChanges filters the changes of the excluded resources from the file.
+func excludeChanges(m *schema.Matcher, f *sqlcheck.File) (err error) {
+	changes := make([]*sqlcheck.Change, 0, len(f.Changes))
+	for _, c := range f.Changes {
+		n := len(c.Changes)
+		if c.Changes, err = m.ExcludeChanges(c.Changes); err != nil {
+			return err
+		}
+		// Skip statements whose changes were all excluded.
+		if n == 0 || len(c.Changes) > 0 {
+			changes = append(changes, c)
+		}
+	}
+	f.Changes = changes
+	f.Sum, err = m.ExcludeChanges(f.Sum)
+	return err
+}
+
 var (
 	// TemplateFuncs are global functions available in templates.
 	TemplateFuncs = template.FuncMap{
@@ -240,6 +284,7 @@ var (
 		"red":          color.HiRedString,
 		"redBgWhiteFg": color.New(color.FgHiWhite, color.BgHiRed).SprintFunc(),
 		"yellow":       color.YellowString,
+		"github":       github,
 		"colorize": func(cc, text string) string {
 			switch cc {
 			case "cyan":
@@ -368,8 +413,17 @@ var (
 	JSONTemplate = template.Must(template.New("json").
 			Funcs(TemplateFuncs).
 			Parse("{{ json . }}"))
+	// GitHubTemplate writes the diagnostics as GitHub Actions workflow commands,
+	// to be displayed as annotations on the analyzed files of the pull request.
+	GitHubTemplate = template.Must(template.New("github").
+			Funcs(TemplateFuncs).
+			Parse("{{ github . }}"))
 )
 
+// FormatGitHub is a reserved log format of 'migrate lint' that is
+// a shorthand for the GitHubTemplate.
+const FormatGitHub = "github"
+
 type (
 	// A SummaryReport contains a summary of the analysis of all files.
 	// It is used as an input to templates to report the CI results.
@@ -437,6 +491,7 @@ type (
 		Start          time.Time  `json:"-"` // Start time of the analysis.
 		End            time.Time  `json:"-"` // End time of the analysis.
 		*sqlcheck.File `json:"-"` // Underlying file.
+		failed         []int      // Indexes of reports written by failing analyzers.
 	}
 
 	// ReportWriter is a type of report writer that writes a summary of analysis reports.
@@ -581,6 +636,163 @@ func (r *SummaryReport) NonFileReports() (rs []*StepReport) {
 	return rs
 }
 
+// LintReport returns the machine-readable representation of the summary.
+// Unlike the SummaryReport, its structure is stable across Atlas versions.
+func (r *SummaryReport) LintReport() *lintreport.Report {
+	rep := &lintreport.Report{
+		Version: lintreport.Version,
+		Driver:  r.Env.Driver,
+		Dir:     r.Env.Dir,
+		Files:   make([]*lintreport.File, 0, len(r.Files)),
+	}
+	for _, f := range r.Files {
+		rep.Files = append(rep.Files, &lintreport.File{
+			Name:        f.Name,
+			Error:       f.Error,
+			Diagnostics: f.diagnostics(),
+		})
+	}
+	for _, s := range r.NonFileReports() {
+		rep.Diagnostics = append(rep.Diagnostics, s.Result.diagnostics()...)
+	}
+	return rep
+}
+
+// diagnostics returns the diagnostics of the file in their machine-readable form.
+func (f *FileReport) diagnostics() []*lintreport.Diagnostic {
+	ds := make([]*lintreport.Diagnostic, 0, len(f.Reports))
+	for i, r := range f.Reports {
+		sev := lintreport.SeverityWarning
+		if slices.Contains(f.failed, i) {
+			sev = lintreport.SeverityError
+		}
+		for _, d := range r.Diagnostics {
+			ld := &lintreport.Diagnostic{
+				Severity: sev,
+				Code:     d.Code,
+				Text:     d.Text,
+				Report:   r.Text,
+				Pos:      d.Pos,
+			}
+			if f.File != nil && d.Pos >= 0 && d.Pos <= len(f.Text) {
+				ld.Line = f.Line(d.Pos)
+			}
+			ds = append(ds, ld)
+		}
+	}
+	return ds
+}
+
+// github formats the summary as GitHub Actions workflow commands. Diagnostics of failing
+// analyzers and file errors are reported as errors, and the rest as warnings. The commands
+// are followed by a summary of the analysis, reported with the highest severity found.
+func github(r *SummaryReport) string {
+	if len(r.Files) == 0 && len(r.NonFileReports()) == 0 {
+		return ""
+	}
+	var (
+		b   strings.Builder
+		rep = r.LintReport()
+	)
+	for _, f := range rep.Files {
+		file := githubPath(r.Env.Dir, f.Name)
+		for _, d := range f.Diagnostics {
+			githubCommand(&b, d.Severity, file, d.Line, githubTitle(d), d.Text)
+		}
+		// Errors of failing analyzers are already reported by their diagnostics.
+		if f.Error != "" && len(f.BySeverity(lintreport.SeverityError)) == 0 {
+			githubCommand(&b, lintreport.SeverityError, file, 0, "", f.Error)
+		}
+	}
+	for _, d := range rep.Diagnostics {
+		githubCommand(&b, d.Severity, "", 0, githubTitle(d), d.Text)
+	}
+	parts := make([]string, 0, 3)
+	if s := r.VersionStatuses(); s != "" {
+		parts = append(parts, s)
+	}
+	for _, c := range []struct {
+		n int
+		s string
+	}{
+		{r.TotalChanges(), "schema change"},
+		{r.DiagnosticsCount(), "diagnostic"},
+	} {
+		switch c.n {
+		case 0:
+		case 1:
+			parts = append(parts, "1 "+c.s)
+		default:
+			parts = append(parts, fmt.Sprintf("%d %ss", c.n, c.s))
+		}
+	}
+	level := "notice"
+	switch {
+	case rep.HasErrors():
+		level = "error"
+	case r.DiagnosticsCount() > 0:
+		level = "warning"
+	}
+	fmt.Fprintf(&b, "::%s title=%s::%s\n", level, githubProperty("Atlas lint summary"), githubData(strings.Join(parts, ", ")))
+	return b.String()
+}
+
+// githubCommand writes a single workflow command for the given severity.
+func githubCommand(b *strings.Builder, s lintreport.Severity, file string, line int, title, msg string) {
+	level := "warning"
+	if s == lintreport.SeverityError {
+		level = "error"
+	}
+	var props []string
+	if file != "" {
+		props = append(props, "file="+githubProperty(file))
+		if line > 0 {
+			props = append(props, fmt.Sprintf("line=%d", line))
+		}
+	}
+	if title != "" {
+		props = append(props, "title="+githubProperty(title))
+	}
+	b.WriteString("::" + level)
+	if len(props) > 0 {
+		b.WriteString(" " + strings.Join(props, ","))
+	}
+	b.WriteString("::" + githubData(msg) + "\n")
+}
+
+// githubTitle returns the annotation title of a diagnostic.
+func githubTitle(d *lintreport.Diagnostic) string {
+	switch {
+	case d.Code != "" && d.Report != "":
+		return d.Code + ": " + d.Report
+	case d.Code != "":
+		return d.Code
+	default:
+		return d.Report
+	}
+}
+
+// githubPath returns the path of the file relative to the working
+// directory, as GitHub expects the annotated files to be.
+func githubPath(dir, name string) string {
+	p := filepath.Join(dir, name)
+	if filepath.IsAbs(p) {
+		if wd, err := os.Getwd(); err == nil {
+			if rel, err := filepath.Rel(wd, p); err == nil && !strings.HasPrefix(rel, "..") {
+				p = rel
+			}
+		}
+	}
+	return filepath.ToSlash(p)
+}
+
+var (
+	// githubData escapes the message of workflow commands.
+	githubData = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace
+	// githubProperty escapes the property values of workflow commands.
+	githubProperty = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace
+)
+
 // NewFileReport returns a new FileReport.
 func NewFileReport(f *sqlcheck.File) *FileReport {
 	return &FileReport{Name: f.Name(), Text: string(f.Bytes()), Start: time.Now(), File: f}
@@ -8,10 +8,13 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"encoding/json"
+	"errors"
 	"testing"
 	"text/template"
 
 	"ariga.io/atlas/cmd/atlas/internal/migratelint"
+	"ariga.io/atlas/cmd/atlas/lintreport"
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlclient"
@@ -255,9 +258,135 @@ schema "main" {
 `, b.String())
 }
 
+func TestSummaryReport_LintReport(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, "sqlite://lintreport?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	var (
+		warn = &testAnalyzer{
+			reports: []sqlcheck.Report{
+				{Text: "Report 1", Diagnostics: []sqlcheck.Diagnostic{{Pos: 0, Text: "Diagnostic 1", Code: "TS101"}}},
+			},
+		}
+		fail = &testAnalyzer{
+			reports: []sqlcheck.Report{
+				{Text: "Report 2", Diagnostics: []sqlcheck.Diagnostic{{Pos: 28, Text: "Diagnostic 2", Code: "TS102"}}},
+			},
+			err: errors.New("Report 2"),
+		}
+		sum *migratelint.SummaryReport
+		r   = &migratelint.Runner{
+			Dir: &migrate.MemDir{},
+			Dev: c,
+			ChangeDetector: testDetector{
+				base: []migrate.File{
+					migrate.NewLocalFile("1.sql", []byte("CREATE TABLE users (id INT);")),
+				},
+				feat: []migrate.File{
+					migrate.NewLocalFile("2.sql", []byte("CREATE TABLE pets (id INT);\nDROP TABLE users;")),
+				},
+			},
+			Analyzers: []sqlcheck.Analyzer{warn},
+			ReportWriter: reportWriterFunc(func(r *migratelint.SummaryReport) error {
+				sum = r
+				return nil
+			}),
+		}
+	)
+	require.NoError(t, r.Run(ctx))
+	rep := sum.LintReport()
+	require.Equal(t, lintreport.Version, rep.Version)
+	require.Equal(t, "sqlite3", rep.Driver)
+	require.False(t, rep.HasErrors())
+	require.Equal(t, []*lintreport.File{
+		{
+			Name: "2.sql",
+			Diagnostics: []*lintreport.Diagnostic{
+				{Severity: lintreport.SeverityWarning, Code: "TS101", Text: "Diagnostic 1", Report: "Report 1", Pos: 0, Line: 1},
+			},
+		},
+	}, rep.Files)
+
+	// Reports of failing analyzers are errors.
+	r.Analyzers = append(r.Analyzers, fail)
+	require.Error(t, r.Run(ctx))
+	rep = sum.LintReport()
+	require.True(t, rep.HasErrors())
+	f := rep.ByFile()["2.sql"]
+	require.Equal(t, "Report 2", f.Error)
+	require.Len(t, f.BySeverity(lintreport.SeverityWarning), 1)
+	require.Equal(t, []*lintreport.Diagnostic{
+		{Severity: lintreport.SeverityError, Code: "TS102", Text: "Diagnostic 2", Report: "Report 2", Pos: 28, Line: 2},
+	}, f.BySeverity(lintreport.SeverityError))
+
+	// The report is encoded according to its documented schema.
+	b, err := json.Marshal(rep)
+	require.NoError(t, err)
+	decoded, err := lintreport.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, rep, decoded)
+}
+
+func TestGitHubTemplate(t *testing.T) {
+	ctx := context.Background()
+	c, err := sqlclient.Open(ctx, "sqlite://github?mode=memory&cache=shared&_fk=1")
+	require.NoError(t, err)
+	var (
+		warn = &testAnalyzer{
+			reports: []sqlcheck.Report{
+				{Text: "Report 1", Diagnostics: []sqlcheck.Diagnostic{{Pos: 0, Text: "Diagnostic 1,\n100%", Code: "TS101"}}},
+			},
+		}
+		fail = &testAnalyzer{
+			reports: []sqlcheck.Report{
+				{Text: "Report 2", Diagnostics: []sqlcheck.Diagnostic{{Pos: 28, Text: "Diagnostic 2", Code: "TS102"}}},
+			},
+			err: errors.New("Report 2"),
+		}
+		b bytes.Buffer
+		r = &migratelint.Runner{
+			Dir: &migrate.MemDir{},
+			Dev: c,
+			ChangeDetector: testDetector{
+				base: []migrate.File{
+					migrate.NewLocalFile("1.sql", []byte("CREATE TABLE users (id INT);")),
+				},
+				feat: []migrate.File{
+					migrate.NewLocalFile("2.sql", []byte("CREATE TABLE pets (id INT);\nDROP TABLE users;")),
+				},
+			},
+			Analyzers: []sqlcheck.Analyzer{warn},
+			ReportWriter: &migratelint.TemplateWriter{
+				T: migratelint.GitHubTemplate,
+				W: &b,
+			},
+		}
+	)
+	require.NoError(t, r.Run(ctx))
+	require.Equal(t, `::warning file=2.sql,line=1,title=TS101%3A Report 1::Diagnostic 1,%0A100%25
+::warning title=Atlas lint summary::1 version with warnings, 2 schema changes, 1 diagnostic
+`, b.String())
+
+	// Reports of failing analyzers are errors.
+	b.Reset()
+	r.Analyzers = append(r.Analyzers, fail)
+	require.Error(t, r.Run(ctx))
+	require.Equal(t, `::warning file=2.sql,line=1,title=TS101%3A Report 1::Diagnostic 1,%0A100%25
+::error file=2.sql,line=2,title=TS102%3A Report 2::Diagnostic 2
+::error title=Atlas lint summary::1 version with errors, 2 schema changes, 2 diagnostics
+`, b.String())
+}
+
+type reportWriterFunc func(*migratelint.SummaryReport) error
+
+func (f reportWriterFunc) WriteReport(r *migratelint.SummaryReport) error {
+	return f(r)
+}
+
 type testAnalyzer struct {
 	passes  []*sqlcheck.Pass
 	reports []sqlcheck.Report
+	err     error
 }
 
 func (t *testAnalyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
@@ -265,7 +394,7 @@ func (t *testAnalyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 	for _, r := range t.reports {
 		p.Reporter.WriteReport(r)
 	}
-	return nil
+	return t.err
 }
 
 type testDetector struct {
@@ -0,0 +1,141 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package lintreport defines the machine-readable report of 'atlas migrate lint'.
+// Unlike the text output of the command, the structure of the report is versioned
+// and described by a JSON Schema (see JSONSchema), and therefore, CI integrations
+// can rely on it across Atlas releases. The report can be emitted as follows:
+//
+//	atlas migrate lint --env dev --format '{{ json .LintReport }}'
+//
+// Backward-incompatible changes to the report structure increase its Version.
+package lintreport
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// Version is the version of the report structure.
+const Version = 1
+
+// JSONSchema holds the JSON Schema document describing the report.
+//
+//go:embed schema.json
+var JSONSchema string
+
+type (
+	// Report is the result of linting a migration directory.
+	Report struct {
+		Version     int           `json:"Version"`               // Version of the report structure.
+		Driver      string        `json:"Driver,omitempty"`      // Driver name of the dev database.
+		Dir         string        `json:"Dir,omitempty"`         // Path to the migration directory.
+		Files       []*File       `json:"Files"`                 // Analyzed files.
+		Diagnostics []*Diagnostic `json:"Diagnostics,omitempty"` // Diagnostics that are not related to a specific file.
+	}
+
+	// File holds the diagnostics reported for a single migration file.
+	File struct {
+		Name        string        `json:"Name"`            // Name of the file.
+		Error       string        `json:"Error,omitempty"` // File specific error, such as a checksum mismatch.
+		Diagnostics []*Diagnostic `json:"Diagnostics"`     // Diagnostics of the file.
+	}
+
+	// Diagnostic is a finding of an analyzer in a specific position of a file.
+	Diagnostic struct {
+		Severity Severity `json:"Severity"`       // Severity of the diagnostic.
+		Code     string   `json:"Code,omitempty"` // Code of the check. For example, DS102.
+		Text     string   `json:"Text"`           // Diagnostic text.
+		Report   string   `json:"Report"`         // Text of the report the diagnostic is grouped by.
+		Pos      int      `json:"Pos"`            // Position of the statement in the file.
+		Line     int      `json:"Line,omitempty"` // Line of the statement in the file, if known.
+	}
+
+	// Severity describes how a diagnostic affects the linting result.
+	Severity string
+)
+
+// Severity levels.
+const (
+	// SeverityError is set on diagnostics of analyzers that were configured
+	// to fail the linting (e.g., destructive changes), and on file errors.
+	SeverityError Severity = "ERROR"
+	// SeverityWarning is set on diagnostics that do not fail the linting.
+	SeverityWarning Severity = "WARNING"
+)
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Severity) UnmarshalText(b []byte) error {
+	switch v := Severity(b); v {
+	case SeverityError, SeverityWarning:
+		*s = v
+		return nil
+	default:
+		return fmt.Errorf("lintreport: unknown severity %q", b)
+	}
+}
+
+// Decode decodes a report from its JSON representation. An error is
+// returned if the report was written by an incompatible version.
+func Decode(b []byte) (*Report, error) {
+	var r Report
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, fmt.Errorf("lintreport: decode report: %w", err)
+	}
+	if r.Version != Version {
+		return nil, fmt.Errorf("lintreport: unsupported report version %d, expect %d", r.Version, Version)
+	}
+	return &r, nil
+}
+
+// HasErrors reports if the linting failed. That is, if one of the
+// files has an error, or one of the diagnostics has an error severity.
+func (r *Report) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	for _, f := range r.Files {
+		if f.HasErrors() {
+			return true
+		}
+	}
+	return false
+}
+
+// ByFile returns the files of the report, keyed by their names.
+func (r *Report) ByFile() map[string]*File {
+	m := make(map[string]*File, len(r.Files))
+	for _, f := range r.Files {
+		m[f.Name] = f
+	}
+	return m
+}
+
+// HasErrors reports if the file has an error, or one of
+// its diagnostics has an error severity.
+func (f *File) HasErrors() bool {
+	if f.Error != "" {
+		return true
+	}
+	for _, d := range f.Diagnostics {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// BySeverity returns the diagnostics of the file with the given severity.
+func (f *File) BySeverity(s Severity) []*Diagnostic {
+	var ds []*Diagnostic
+	for _, d := range f.Diagnostics {
+		if d.Severity == s {
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
@@ -0,0 +1,101 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package lintreport_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/cmd/atlas/lintreport"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	r := &lintreport.Report{
+		Version: lintreport.Version,
+		Files: []*lintreport.File{
+			{
+				Name: "1.sql",
+				Diagnostics: []*lintreport.Diagnostic{
+					{Severity: lintreport.SeverityWarning, Code: "MF103", Text: "Adding a non-nullable column", Report: "data dependent changes detected", Pos: 0, Line: 1},
+				},
+			},
+			{Name: "2.sql", Diagnostics: []*lintreport.Diagnostic{}},
+		},
+	}
+	require.False(t, r.HasErrors())
+	require.Len(t, r.ByFile(), 2)
+	require.Empty(t, r.ByFile()["2.sql"].Diagnostics)
+
+	r.Files[1].Diagnostics = append(r.Files[1].Diagnostics, &lintreport.Diagnostic{
+		Severity: lintreport.SeverityError, Code: "DS102", Text: `Dropping table "users"`, Report: "destructive changes detected", Pos: 10, Line: 2,
+	})
+	require.True(t, r.HasErrors())
+	require.False(t, r.ByFile()["1.sql"].HasErrors())
+	require.True(t, r.ByFile()["2.sql"].HasErrors())
+	require.Len(t, r.Files[1].BySeverity(lintreport.SeverityError), 1)
+	require.Empty(t, r.Files[1].BySeverity(lintreport.SeverityWarning))
+
+	r.Files[1].Diagnostics = []*lintreport.Diagnostic{}
+	r.Files[1].Error = "checksum mismatch"
+	require.True(t, r.HasErrors())
+
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+	require.Equal(t, `{"Version":1,"Files":[{"Name":"1.sql","Diagnostics":[{"Severity":"WARNING","Code":"MF103","Text":"Adding a non-nullable column","Report":"data dependent changes detected","Pos":0,"Line":1}]},{"Name":"2.sql","Error":"checksum mismatch","Diagnostics":[]}]}`, string(b))
+	d, err := lintreport.Decode(b)
+	require.NoError(t, err)
+	require.Equal(t, r, d)
+
+	_, err = lintreport.Decode([]byte(`{"Version":2}`))
+	require.EqualError(t, err, "lintreport: unsupported report version 2, expect 1")
+	_, err = lintreport.Decode([]byte(`{"Version":1,"Files":[{"Name":"1.sql","Diagnostics":[{"Severity":"FATAL"}]}]}`))
+	require.EqualError(t, err, `lintreport: decode report: lintreport: unknown severity "FATAL"`)
+}
+
+// The JSON Schema must describe all fields of the report.
+func TestJSONSchema(t *testing.T) {
+	var doc struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Required   []string                   `json:"required"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lintreport.JSONSchema), &doc))
+	requireFields(t, lintreport.Report{}, doc.Properties, doc.Required)
+	requireFields(t, lintreport.File{}, doc.Defs["File"].Properties, doc.Defs["File"].Required)
+	requireFields(t, lintreport.Diagnostic{}, doc.Defs["Diagnostic"].Properties, doc.Defs["Diagnostic"].Required)
+}
+
+func requireFields(t *testing.T, v any, props map[string]json.RawMessage, required []string) {
+	var (
+		names []string
+		req   []string
+		typ   = reflect.TypeOf(v)
+	)
+	for i := 0; i < typ.NumField(); i++ {
+		tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")
+		names = append(names, tag[0])
+		if len(tag) == 1 {
+			req = append(req, tag[0])
+		}
+	}
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(names)
+	sort.Strings(keys)
+	sort.Strings(req)
+	sort.Strings(required)
+	require.Equal(t, names, keys, "properties of %s", typ.Name())
+	require.Equal(t, req, required, "required properties of %s", typ.Name())
+}
@@ -13,12 +13,14 @@ import (
 	"syscall"
 	"time"
 
+	_ "ariga.io/atlas/cmd/atlas/docker"
 	"ariga.io/atlas/cmd/atlas/internal/cmdapi"
 	"ariga.io/atlas/cmd/atlas/internal/cmdapi/vercheck"
 	"ariga.io/atlas/cmd/atlas/internal/cmdlog"
-	_ "ariga.io/atlas/cmd/atlas/internal/docker"
+	_ "ariga.io/atlas/sql/mssql"
 	_ "ariga.io/atlas/sql/mysql"
 	_ "ariga.io/atlas/sql/mysql/mysqlcheck"
+	_ "ariga.io/atlas/sql/oracle"
 	_ "ariga.io/atlas/sql/postgres"
 	_ "ariga.io/atlas/sql/postgres/postgrescheck"
 	_ "ariga.io/atlas/sql/sqlite"
@@ -29,6 +31,9 @@ import (
 	_ "github.com/libsql/libsql-client-go/libsql"
 	"github.com/mattn/go-isatty"
 	_ "github.com/mattn/go-sqlite3"
+	_ "github.com/microsoft/go-mssqldb"
+	_ "github.com/microsoft/go-mssqldb/azuread"
+	_ "github.com/sijms/go-ora/v2"
 	"github.com/spf13/cobra"
 	"golang.org/x/mod/semver"
 )
@@ -54,8 +59,9 @@ func main() {
 	cobra.CheckErr(err)
 	ctx, done := initialize(ctx)
 	update := checkForUpdate(ctx)
-	err = cmdapi.Root.ExecuteContext(ctx)
-	if u := update(); u != "" {
+	cmd, err := cmdapi.Root.ExecuteContextC(ctx)
+	cmdapi.RecordDebug(cmd, err)
+	if u := update(); u != "" && !versionChecked(cmd) {
 		_ = cmdlog.WarnOnce(os.Stderr, cmdlog.ColorCyan(u))
 	}
 	done(err)
@@ -64,11 +70,8 @@ func main() {
 	}
 }
 
-const (
-	// envNoUpdate when enabled it cancels checking for update
-	envNoUpdate = "ATLAS_NO_UPDATE_NOTIFIER"
-	vercheckURL = "https://vercheck.ariga.io"
-)
+// envNoUpdate when enabled it cancels checking for update
+const envNoUpdate = "ATLAS_NO_UPDATE_NOTIFIER"
 
 func noText() string { return "" }
 
@@ -78,6 +81,9 @@ func checkForUpdate(ctx context.Context) func() string {
 	if v := os.Getenv(envNoUpdate); v != "" {
 		return noText
 	}
+	if c, err := cmdapi.LoadUserConfig(); err == nil && c.NoUpdateNotifier {
+		return noText
+	}
 	// Skip if the current binary version isn't set (dev mode).
 	if !semver.IsValid(version) {
 		return noText
@@ -93,6 +99,16 @@ func checkForUpdate(ctx context.Context) func() string {
 	}
 }
 
+// versionChecked reports if the executed command already checked
+// for updates explicitly, i.e., 'atlas version --check'.
+func versionChecked(cmd *cobra.Command) bool {
+	if cmd == nil || cmd.Name() != "version" {
+		return false
+	}
+	f := cmd.Flags().Lookup("check")
+	return f != nil && f.Changed
+}
+
 // bgCheck checks for version updates and security advisories for Atlas in the background.
 func bgCheck(ctx context.Context, version string, vc *vercheck.VerChecker) func() string {
 	done := make(chan struct{})
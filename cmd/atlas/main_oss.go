@@ -8,6 +8,8 @@ package main
 
 import (
 	"context"
+
+	"ariga.io/atlas/cmd/atlas/internal/cmdapi/vercheck"
 )
 
 func extendContext(ctx context.Context) (context.Context, error) {
@@ -15,7 +17,7 @@ func extendContext(ctx context.Context) (context.Context, error) {
 }
 
 func vercheckEndpoint(context.Context) string {
-	return vercheckURL
+	return vercheck.URL()
 }
 
 // initialize is a no-op for the OSS version.
@@ -0,0 +1,20 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrivers ensures a database/sql driver is registered
+// for each of the Atlas drivers imported by the CLI.
+func TestDrivers(t *testing.T) {
+	for _, name := range []string{"mysql", "postgres", "sqlite3", "libsql", "oracle", "sqlserver", "azuresql"} {
+		require.Contains(t, sql.Drivers(), name)
+	}
+}
@@ -32,5 +32,32 @@ func init() {
 			Regex:   "Cockroach",
 			Ports:   []string{"26257:26257"},
 		},
+		Job{
+			Version: "oracle23",
+			Image:   "gvenzl/oracle-free:23-slim",
+			Regex:   "Oracle",
+			Env: []string{
+				"ORACLE_PASSWORD: pass",
+				"APP_USER: atlas",
+				"APP_USER_PASSWORD: pass",
+			},
+			Ports: []string{"1521:1521"},
+			Options: []string{
+				"--health-cmd healthcheck.sh",
+				"--health-interval 10s",
+				"--health-timeout 5s",
+				"--health-retries 10",
+			},
+		},
+		Job{
+			Version: "mssql22",
+			Image:   "mcr.microsoft.com/mssql/server:2022-latest",
+			Regex:   "MSSQL",
+			Env: []string{
+				`ACCEPT_EULA: "Y"`,
+				"MSSQL_SA_PASSWORD: P@ssw0rd0995",
+			},
+			Ports: []string{"1433:1433"},
+		},
 	)
 }
@@ -276,6 +276,18 @@ func testCLIMigrateApplyBC(t T, dialect string) {
 	require.NoError(t, err)
 	require.Equal(t, "No migration files to execute\n", string(out))
 
+	// The check is done against the configured revisions table. No
+	// table with this name exists in the old default schema.
+	out, err = exec.Command(
+		execPath(t),
+		"migrate", "status",
+		"--dir", "file://testdata/migrations/"+dialect,
+		"--url", t.url("bc_test"),
+		"--revisions-table", "bc_revisions",
+	).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NotContains(t, string(out), "We couldn't find a revision table in the connected schema but found one in")
+
 	// Providing the flag to the schema instead will work as well.
 	t.migrate(
 		&schema.DropSchema{S: schema.New("bc_test")},
@@ -34,8 +34,9 @@ type (
 		typedblk         map[string]map[string]BlockFunc
 		// Optional context to pass to dynamic block handlers,
 		// such as data-sources, type-blocks, etc.
-		ctx     context.Context
-		withPos bool
+		ctx          context.Context
+		withPos      bool
+		withComments bool
 	}
 	// Option configures a Config.
 	Option func(*Config)
@@ -106,6 +107,16 @@ func WithPos() Option {
 	}
 }
 
+// WithComments attaches the comments that precede blocks and attributes in the
+// source files to the returned Resources and Attrs, allowing them to be written
+// back on marshaling. Combined with WithPos, the original ordering of blocks and
+// attributes is preserved as well.
+func WithComments() Option {
+	return func(c *Config) {
+		c.withComments = true
+	}
+}
+
 // WithVariables registers a list of variables to be injected into the context.
 func WithVariables(vars map[string]cty.Value) Option {
 	return func(c *Config) {
@@ -315,6 +326,10 @@ type EvalOptions struct {
 	// Validator is the schema validator to be used during evaluation.
 	// It defaults to the State (Driver) config.
 	Validator SchemaValidator
+
+	// comments holds the leading comments of the evaluated files,
+	// indexed by file name and the line they are attached to.
+	comments map[string]map[int][]string
 }
 
 // EvalFiles evaluates the files in the provided paths using the input variables and
@@ -353,8 +368,14 @@ func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) e
 	if ctx.Variables == nil {
 		ctx.Variables = make(map[string]cty.Value)
 	}
+	if s.config.withComments {
+		opts.comments = make(map[string]map[int][]string, len(files))
+	}
 	for name, file := range files {
 		fileNames = append(fileNames, name)
+		if opts.comments != nil {
+			opts.comments[name] = leadingComments(file.Bytes, name)
+		}
 		if err := s.setInputVals(ctx, file.Body, opts.Variables); err != nil {
 			return err
 		}
@@ -432,13 +453,29 @@ func (s *State) EvalOptions(parsed *hclparse.Parser, v any, opts *EvalOptions) e
 		vr = &nopValidator{}
 		opts.Validator = vr
 	}
+	// Named blocks that are defined in more than one file are
+	// layered. i.e., merged into the block of the first file.
+	defined := make(map[[3]string]*Resource)
 	for _, name := range fileNames {
 		file := files[name]
 		r, err := s.resource(ctx, opts, file, reg)
 		if err != nil {
 			return err
 		}
-		spec.Children = append(spec.Children, r.Children...)
+		var added []*Resource
+		for _, c := range r.Children {
+			if prev, ok := defined[c.key()]; ok && c.Name != "" {
+				prev.merge(c)
+				continue
+			}
+			spec.Children = append(spec.Children, c)
+			added = append(added, c)
+		}
+		for _, c := range added {
+			if _, ok := defined[c.key()]; !ok && c.Name != "" {
+				defined[c.key()] = c
+			}
+		}
 		spec.Attrs = append(spec.Attrs, r.Attrs...)
 	}
 	// Validators can fail fast or accumulate errors.
@@ -529,7 +566,7 @@ func (s *State) resource(ctx *hcl.EvalContext, opts *EvalOptions, file *hcl.File
 	if err != nil {
 		return nil, err
 	}
-	attrs, err := s.toAttrs(ctx, opts, body.Attributes, nil)
+	attrs, _, err := s.toAttrs(ctx, opts, body.Attributes, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -604,8 +641,10 @@ func (s *State) mayScopeContext(ctx *hcl.EvalContext, scope []string) *hcl.EvalC
 	return nctx.NewChild()
 }
 
-func (s *State) toAttrs(ctx *hcl.EvalContext, opts *EvalOptions, hclAttrs hclsyntax.Attributes, scope []string) ([]*Attr, error) {
-	attrs := make([]*Attr, 0, len(hclAttrs))
+// toAttrs evaluates the given HCL attributes. Attributes that are explicitly set to null
+// are omitted from the returned attributes, and their keys are returned as unset keys.
+func (s *State) toAttrs(ctx *hcl.EvalContext, opts *EvalOptions, hclAttrs hclsyntax.Attributes, scope []string) (attrs []*Attr, unset []string, _ error) {
+	attrs = make([]*Attr, 0, len(hclAttrs))
 	for _, hclAttr := range hclAttrs {
 		var (
 			scope = append(scope, hclAttr.Name)
@@ -613,23 +652,26 @@ func (s *State) toAttrs(ctx *hcl.EvalContext, opts *EvalOptions, hclAttrs hclsyn
 		)
 		value, diag := hclAttr.Expr.Value(nctx)
 		if diag.HasErrors() {
-			return nil, s.typeError(diag, scope)
+			return nil, nil, s.typeError(diag, scope)
 		}
-		// Setting an attribute as null means omission.
+		// Setting an attribute as null means omission. When schema fragments are
+		// merged, it also unsets the attribute defined by the previous fragments.
 		if value.IsNull() {
+			unset = append(unset, hclAttr.Name)
 			continue
 		}
 		if err := opts.Validator.ValidateAttribute(ctx, hclAttr, value); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		at := &Attr{K: hclAttr.Name}
 		if s.config.withPos || opts.RecordPos {
 			at.SetRange(&hclAttr.SrcRange)
 		}
+		at.comments = opts.commentsAt(hclAttr.SrcRange)
 		switch t := value.Type(); {
 		case isRef(value):
 			if !value.Type().HasAttribute("__ref") {
-				return nil, fmt.Errorf("%s: invalid reference used in %s", hclAttr.SrcRange, hclAttr.Name)
+				return nil, nil, fmt.Errorf("%s: invalid reference used in %s", hclAttr.SrcRange, hclAttr.Name)
 			}
 			at.V = cty.CapsuleVal(ctyRefType, &Ref{V: value.GetAttr("__ref").AsString()})
 		case (t.IsTupleType() || t.IsListType() || t.IsSetType()) && value.LengthInt() > 0:
@@ -641,12 +683,12 @@ func (s *State) toAttrs(ctx *hcl.EvalContext, opts *EvalOptions, hclAttrs hclsyn
 				_, v := it.Element()
 				if isRef(v) {
 					if !v.Type().HasAttribute("__ref") {
-						return nil, fmt.Errorf("%s: invalid reference used in %s", hclAttr.SrcRange, hclAttr.Name)
+						return nil, nil, fmt.Errorf("%s: invalid reference used in %s", hclAttr.SrcRange, hclAttr.Name)
 					}
 					v = cty.CapsuleVal(ctyRefType, &Ref{V: v.GetAttr("__ref").AsString()})
 				}
 				if vt != cty.NilType && vt != v.Type() {
-					return nil, fmt.Errorf("%s: mixed list types used in %q attribute", hclAttr.SrcRange, hclAttr.Name)
+					return nil, nil, fmt.Errorf("%s: mixed list types used in %q attribute", hclAttr.SrcRange, hclAttr.Name)
 				}
 				vt = v.Type()
 				values = append(values, v)
@@ -660,7 +702,8 @@ func (s *State) toAttrs(ctx *hcl.EvalContext, opts *EvalOptions, hclAttrs hclsyn
 	sort.Slice(attrs, func(i, j int) bool {
 		return attrs[i].K < attrs[j].K
 	})
-	return attrs, nil
+	sort.Strings(unset)
+	return attrs, unset, nil
 }
 
 // typeError improves diagnostic reporting in case of parse error.
@@ -740,6 +783,7 @@ func (s *State) toResource(ctx *hcl.EvalContext, opts *EvalOptions, block *hclsy
 	if s.config.withPos || opts.RecordPos {
 		spec.SetRange(&block.TypeRange)
 	}
+	spec.comments = opts.commentsAt(block.TypeRange)
 	switch len(block.Labels) {
 	case 0:
 	case 1:
@@ -751,11 +795,11 @@ func (s *State) toResource(ctx *hcl.EvalContext, opts *EvalOptions, block *hclsy
 		return nil, fmt.Errorf("too many labels for block: %s", block.Labels)
 	}
 	ctx = s.mayScopeContext(ctx, scope)
-	attrs, err := s.toAttrs(ctx, opts, block.Body.Attributes, scope)
+	attrs, unset, err := s.toAttrs(ctx, opts, block.Body.Attributes, scope)
 	if err != nil {
 		return nil, err
 	}
-	spec.Attrs = attrs
+	spec.Attrs, spec.unset = attrs, unset
 	for _, blk := range block.Body.Blocks {
 		cdec := dec.child(blk.Type)
 		ctx, err := setLocalVars(ctx.NewChild(), blk.Body, cdec)
@@ -781,20 +825,8 @@ func (s *State) encode(r *Resource) ([]byte, error) {
 		f    = hclwrite.NewFile()
 		body = f.Body()
 	)
-	// If the resource has a Type then it is rendered as an HCL block.
-	if r.Type != "" {
-		blk := body.AppendNewBlock(r.Type, labels(r))
-		body = blk.Body()
-	}
-	for _, attr := range r.Attrs {
-		if err := s.writeAttr(attr, body); err != nil {
-			return nil, err
-		}
-	}
-	for _, res := range r.Children {
-		if err := s.writeResource(res, body); err != nil {
-			return nil, err
-		}
+	if err := s.writeResource(r, body); err != nil {
+		return nil, err
 	}
 	var buf bytes.Buffer
 	_, err := f.WriteTo(&buf)
@@ -804,22 +836,128 @@ func (s *State) encode(r *Resource) ([]byte, error) {
 func (s *State) writeResource(b *Resource, body *hclwrite.Body) error {
 	// Anonymous resources are treated as embedded blocks.
 	if b.Type != "" {
+		writeComments(b.comments, body)
 		blk := body.AppendNewBlock(b.Type, labels(b))
 		body = blk.Body()
 	}
-	for _, attr := range b.Attrs {
-		if err := s.writeAttr(attr, body); err != nil {
-			return err
+	for _, e := range bodyElems(b) {
+		var err error
+		switch e := e.(type) {
+		case *Attr:
+			writeComments(e.comments, body)
+			err = s.writeAttr(e, body)
+		case *Resource:
+			err = s.writeResource(e, body)
 		}
-	}
-	for _, b := range b.Children {
-		if err := s.writeResource(b, body); err != nil {
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// bodyElems returns the attributes and children of the resource in the order they
+// should be written. Attributes are written before blocks, unless all elements hold
+// positions of the same source file. In this case, the original order is preserved.
+func bodyElems(r *Resource) []any {
+	var (
+		file  string
+		elems = make([]any, 0, len(r.Attrs)+len(r.Children))
+		pos   = make([]int, 0, len(r.Attrs)+len(r.Children))
+	)
+	add := func(e any, rg *hcl.Range) {
+		elems = append(elems, e)
+		switch {
+		case pos == nil:
+		case rg == nil, len(pos) > 0 && rg.Filename != file:
+			pos = nil
+		default:
+			file = rg.Filename
+			pos = append(pos, rg.Start.Byte)
+		}
+	}
+	for _, a := range r.Attrs {
+		add(a, a.rang)
+	}
+	for _, c := range r.Children {
+		add(c, c.rang)
+	}
+	if pos != nil {
+		sort.Stable(byPos{elems: elems, pos: pos})
+	}
+	return elems
+}
+
+// byPos sorts body elements by their source position.
+type byPos struct {
+	elems []any
+	pos   []int
+}
+
+func (p byPos) Len() int           { return len(p.elems) }
+func (p byPos) Less(i, j int) bool { return p.pos[i] < p.pos[j] }
+func (p byPos) Swap(i, j int) {
+	p.elems[i], p.elems[j] = p.elems[j], p.elems[i]
+	p.pos[i], p.pos[j] = p.pos[j], p.pos[i]
+}
+
+// writeComments appends the given comment lines to the body.
+func writeComments(comments []string, body *hclwrite.Body) {
+	for _, c := range comments {
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			&hclwrite.Token{Type: hclsyntax.TokenComment, Bytes: []byte(c)},
+			&hclwrite.Token{Type: hclsyntax.TokenNewline, Bytes: []byte{'\n'}},
+		})
+	}
+}
+
+// leadingComments scans the given source and returns the comments that are placed
+// directly above (without blank lines) a block or an attribute, indexed by the line
+// they are attached to. Trailing comments (e.g., `a = 1 # comment`) are ignored.
+func leadingComments(src []byte, filename string) map[int][]string {
+	var (
+		lineStart = true
+		pending   []string
+		comments  = make(map[int][]string)
+	)
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	for _, t := range tokens {
+		switch t.Type {
+		case hclsyntax.TokenComment:
+			c := strings.TrimRight(string(t.Bytes), " \t\r\n")
+			switch {
+			case lineStart:
+				pending = append(pending, c)
+			case len(pending) > 0:
+				pending = nil
+			}
+			// Line comments consume the newline that follows them.
+			lineStart = bytes.HasSuffix(t.Bytes, []byte{'\n'})
+		case hclsyntax.TokenNewline:
+			// A blank line detaches the comments from the next element.
+			if lineStart {
+				pending = nil
+			}
+			lineStart = true
+		default:
+			if len(pending) > 0 {
+				comments[t.Range.Start.Line] = pending
+				pending = nil
+			}
+			lineStart = false
+		}
+	}
+	return comments
+}
+
+// commentsAt returns the comments attached to the line of the given range.
+func (o *EvalOptions) commentsAt(r hcl.Range) []string {
+	if o.comments == nil {
+		return nil
+	}
+	return o.comments[r.Filename][r.Start.Line]
+}
+
 func labels(r *Resource) []string {
 	var l []string
 	if r.Qualifier != "" {
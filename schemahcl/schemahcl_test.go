@@ -12,9 +12,11 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
@@ -424,6 +426,79 @@ func TestMultiFile(t *testing.T) {
 	}, test.People[1])
 }
 
+func TestMultiFile_Layering(t *testing.T) {
+	type (
+		Column struct {
+			Name    string    `spec:",name"`
+			Type    string    `spec:"type"`
+			Default cty.Value `spec:"default"`
+			Comment string    `spec:"comment"`
+		}
+		Table struct {
+			Name    string    `spec:",name"`
+			Comment string    `spec:"comment"`
+			Columns []*Column `spec:"column"`
+		}
+	)
+	var (
+		test struct {
+			Tables []*Table `spec:"table"`
+		}
+		parser = hclparse.NewParser()
+	)
+	_, diag := parser.ParseHCL([]byte(`
+table "users" {
+  comment = "users table"
+  column "id" {
+    type    = "int"
+    default = 1
+    comment = "identifier"
+  }
+  column "name" {
+    type = "text"
+  }
+}
+table "posts" {}
+`), "1_base.hcl")
+	require.False(t, diag.HasErrors())
+	_, diag = parser.ParseHCL([]byte(`
+table "users" {
+  comment = null
+  column "id" {
+    default = null
+  }
+  column "name" {
+    type = "varchar"
+  }
+  column "email" {
+    type = "text"
+  }
+}
+`), "2_override.hcl")
+	require.False(t, diag.HasErrors())
+	require.NoError(t, New().Eval(parser, &test, nil))
+	require.Len(t, test.Tables, 2)
+	users := test.Tables[0]
+	require.Equal(t, "users", users.Name)
+	require.Empty(t, users.Comment)
+	require.Len(t, users.Columns, 3)
+	require.Equal(t, "id", users.Columns[0].Name)
+	require.Equal(t, "int", users.Columns[0].Type)
+	require.Equal(t, "identifier", users.Columns[0].Comment)
+	require.True(t, users.Columns[0].Default.IsNull(), "default should be unset")
+	require.Equal(t, "varchar", users.Columns[1].Type)
+	require.Equal(t, "email", users.Columns[2].Name)
+	require.Equal(t, "posts", test.Tables[1].Name)
+
+	// Blocks with the same name in the same file are not merged.
+	test.Tables = nil
+	require.NoError(t, New().EvalBytes([]byte(`
+table "users" {}
+table "users" {}
+`), &test, nil))
+	require.Len(t, test.Tables, 2)
+}
+
 func TestForEachResources(t *testing.T) {
 	type (
 		Env struct {
@@ -1131,6 +1206,74 @@ mixed3 = ["string1", "string1"]
 `, string(buf))
 }
 
+func Test_WithComments(t *testing.T) {
+	var (
+		doc struct {
+			DefaultExtension
+		}
+		b = []byte(`# The users table.
+// Managed by the platform team.
+table "users" {
+  schema = "public" # trailing
+  column "id" {
+    type = "int"
+  }
+
+  # The name column.
+  column "name" {
+    null = true
+    type = "text"
+  }
+  /* The comment attribute. */
+  comment = "users"
+}
+`)
+	)
+	err := New(WithPos(), WithComments()).EvalBytes(b, &doc, nil)
+	require.NoError(t, err)
+	tbl := doc.Extra.Children[0]
+	require.Equal(t, []string{"# The users table.", "// Managed by the platform team."}, tbl.Comments())
+	require.Equal(t, []string{"# The name column."}, tbl.Children[1].Comments())
+	require.Empty(t, tbl.Children[0].Comments())
+	c, ok := tbl.Attr("comment")
+	require.True(t, ok)
+	require.Equal(t, []string{"/* The comment attribute. */"}, c.Comments())
+	s, ok := tbl.Attr("schema")
+	require.True(t, ok)
+	require.Empty(t, s.Comments())
+
+	buf, err := Marshal(&doc)
+	require.NoError(t, err)
+	require.Equal(t, `# The users table.
+// Managed by the platform team.
+table "users" {
+  schema = "public"
+  column "id" {
+    type = "int"
+  }
+  # The name column.
+  column "name" {
+    null = true
+    type = "text"
+  }
+  /* The comment attribute. */
+  comment = "users"
+}
+`, string(buf))
+
+	// Comments are attached programmatically.
+	doc.Extra.Children[0].SetComment("Table users.")
+	s.SetComment("The schema.", "// Defined in schema.hcl.")
+	buf, err = Marshal(&doc)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(buf), `# Table users.
+table "users" {
+  # The schema.
+  // Defined in schema.hcl.
+  schema = "public"
+`), string(buf))
+}
+
 func Test_WithPos(t *testing.T) {
 	var (
 		doc struct {
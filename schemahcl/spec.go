@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"math/big"
 	"reflect"
+	"slices"
 	"strings"
 
 	"ariga.io/atlas/sql/schema"
@@ -26,13 +27,16 @@ type (
 		Attrs     []*Attr
 		Children  []*Resource
 		rang      *hcl.Range
+		comments  []string
+		unset     []string // Attributes explicitly set to null.
 	}
 
 	// Attr is an attribute of a Resource.
 	Attr struct {
-		K    string
-		V    cty.Value
-		rang *hcl.Range
+		K        string
+		V        cty.Value
+		rang     *hcl.Range
+		comments []string
 	}
 
 	// Ref implements Value and represents a reference to another Resource.
@@ -414,6 +418,30 @@ func (r *Resource) Range() *hcl.Range {
 	return r.rang
 }
 
+// SetComment sets the comment lines that are written above the attribute when
+// it is marshaled. Lines that do not start with a comment marker ("#", "//" or
+// "/*") are prefixed with "# ".
+func (a *Attr) SetComment(lines ...string) {
+	a.comments = commentLines(lines)
+}
+
+// Comments returns the comment lines attached to the attribute.
+func (a *Attr) Comments() []string {
+	return a.comments
+}
+
+// SetComment sets the comment lines that are written above the resource when
+// it is marshaled. Lines that do not start with a comment marker ("#", "//" or
+// "/*") are prefixed with "# ".
+func (r *Resource) SetComment(lines ...string) {
+	r.comments = commentLines(lines)
+}
+
+// Comments returns the comment lines attached to the resource.
+func (r *Resource) Comments() []string {
+	return r.comments
+}
+
 // Resource returns the first child Resource by its type and reports whether it was found.
 func (r *Resource) Resource(t string) (*Resource, bool) {
 	if r == nil {
@@ -473,6 +501,24 @@ func (f MarshalerFunc) MarshalSpec(v any) ([]byte, error) {
 	return f(v)
 }
 
+func commentLines(lines []string) []string {
+	cs := make([]string, 0, len(lines))
+	for _, l := range lines {
+		// Block comments are kept as-is, including their line breaks.
+		if l = strings.TrimRight(l, " \t\r\n"); strings.HasPrefix(l, "/*") {
+			cs = append(cs, l)
+			continue
+		}
+		for _, l := range strings.Split(l, "\n") {
+			if l = strings.TrimSpace(l); !strings.HasPrefix(l, "#") && !strings.HasPrefix(l, "//") {
+				l = strings.TrimSpace("# " + l)
+			}
+			cs = append(cs, l)
+		}
+	}
+	return cs
+}
+
 func attrVal(attrs []*Attr, name string) (*Attr, bool) {
 	for _, attr := range attrs {
 		if attr.K == name {
@@ -482,6 +528,35 @@ func attrVal(attrs []*Attr, name string) (*Attr, bool) {
 	return nil, false
 }
 
+// key returns the key that identifies the resource among its siblings.
+func (r *Resource) key() [3]string {
+	return [3]string{r.Type, r.Qualifier, r.Name}
+}
+
+// merge layers the given resource on top of r. Attributes of the given resource
+// replace the ones of r, and attributes that are explicitly set to null remove
+// them. Named child resources are merged recursively, and others are appended.
+func (r *Resource) merge(l *Resource) {
+	for _, a := range l.Attrs {
+		r.SetAttr(a)
+	}
+	for _, k := range l.unset {
+		r.Attrs = slices.DeleteFunc(r.Attrs, func(a *Attr) bool {
+			return a.K == k
+		})
+	}
+	for _, c := range l.Children {
+		i := slices.IndexFunc(r.Children, func(c1 *Resource) bool {
+			return c.Name != "" && c1.key() == c.key()
+		})
+		if i == -1 {
+			r.Children = append(r.Children, c)
+		} else {
+			r.Children[i].merge(c)
+		}
+	}
+}
+
 func replaceOrAppendAttr(attrs []*Attr, attr *Attr) []*Attr {
 	for i, v := range attrs {
 		if v.K == attr.K {
@@ -339,6 +339,9 @@ func Table(spec *sqlspec.Table, parent *schema.Schema, convertColumn ConvertTabl
 	if err := convertCommentFromSpec(spec, &t.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertLabels(spec.Extra, &t.Attrs); err != nil {
+		return nil, fmt.Errorf("table %q: %w", spec.Name, err)
+	}
 	return t, nil
 }
 
@@ -404,6 +407,12 @@ func Column(spec *sqlspec.Column, conv ConvertTypeFunc) (*schema.Column, error)
 	if err := convertCommentFromSpec(spec, &out.Attrs); err != nil {
 		return nil, err
 	}
+	if err := convertDiffIgnore(spec.Extra, &out.Attrs); err != nil {
+		return nil, fmt.Errorf("column %q: %w", spec.Name, err)
+	}
+	if err := convertLabels(spec.Extra, &out.Attrs); err != nil {
+		return nil, fmt.Errorf("column %q: %w", spec.Name, err)
+	}
 	return out, err
 }
 
@@ -693,6 +702,9 @@ func FromTable(t *schema.Table, colFn TableColumnSpecFunc, pkFn PrimaryKeySpecFu
 		spec.Extra.Children = append(spec.Extra.Children, &schemahcl.Resource{Attrs: []*schemahcl.Attr{deps}})
 	}
 	convertCommentFromSchema(t.Attrs, &spec.Extra.Attrs)
+	if a := fromLabels(t.Attrs); a != nil {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, a)
+	}
 	return spec, nil
 }
 
@@ -893,6 +905,12 @@ func FromColumn(c *schema.Column, columnTypeSpec ColumnTypeSpecFunc) (*sqlspec.C
 		spec.Default = lv
 	}
 	convertCommentFromSchema(c.Attrs, &spec.Extra.Attrs)
+	if a := fromLabels(c.Attrs); a != nil {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, a)
+	}
+	if r := fromDiffIgnore(c.Attrs); r != nil {
+		spec.Extra.Children = append(spec.Extra.Children, r)
+	}
 	return spec, nil
 }
 
@@ -1280,6 +1298,110 @@ func convertCommentFromSchema(src []schema.Attr, target *[]*schemahcl.Attr) {
 	}
 }
 
+// DiffIgnoreVars holds the HCL variables for the column
+// changes that can be ignored by the differ. For example:
+//
+//	diff {
+//	  ignore = [default]
+//	}
+var DiffIgnoreVars = []string{"default", "type", "nullable", "comment"}
+
+// diffIgnoreKinds maps the DiffIgnoreVars to their change kinds.
+var diffIgnoreKinds = map[string]schema.ChangeKind{
+	"default":  schema.ChangeDefault,
+	"type":     schema.ChangeType,
+	"nullable": schema.ChangeNull,
+	"comment":  schema.ChangeComment,
+}
+
+// convertDiffIgnore converts the "diff" block of a spec element into a schema.DiffIgnore attribute.
+func convertDiffIgnore(r schemahcl.Resource, attrs *[]schema.Attr) error {
+	d, ok := r.Resource("diff")
+	if !ok {
+		return nil
+	}
+	a, ok := d.Attr("ignore")
+	if !ok {
+		return nil
+	}
+	vs, err := a.Strings()
+	if err != nil {
+		return err
+	}
+	var ig schema.DiffIgnore
+	for _, v := range vs {
+		k, ok := diffIgnoreKinds[v]
+		if !ok {
+			return fmt.Errorf("unexpected diff.ignore value %q, expected one of: %s", v, strings.Join(DiffIgnoreVars, ", "))
+		}
+		ig.Changes |= k
+	}
+	if ig.Changes != schema.NoChange {
+		*attrs = append(*attrs, &ig)
+	}
+	return nil
+}
+
+// fromDiffIgnore returns the "diff" block for the schema.DiffIgnore attribute, if exists.
+func fromDiffIgnore(attrs []schema.Attr) *schemahcl.Resource {
+	var ig schema.DiffIgnore
+	if !sqlx.Has(attrs, &ig) || ig.Changes == schema.NoChange {
+		return nil
+	}
+	var elems []*schemahcl.EnumString
+	for _, v := range DiffIgnoreVars {
+		if ig.Changes.Is(diffIgnoreKinds[v]) {
+			elems = append(elems, &schemahcl.EnumString{E: v})
+		}
+	}
+	return &schemahcl.Resource{
+		Type:  "diff",
+		Attrs: []*schemahcl.Attr{schemahcl.StringEnumsAttr("ignore", elems...)},
+	}
+}
+
+// convertLabels converts the "labels" attribute of a spec element into a schema.Labels attribute.
+// For example:
+//
+//	labels = {
+//	  owner = "payments"
+//	  pii   = "true"
+//	}
+func convertLabels(r schemahcl.Resource, attrs *[]schema.Attr) error {
+	a, ok := r.Attr("labels")
+	if !ok {
+		return nil
+	}
+	if t := a.V.Type(); !t.IsObjectType() && !t.IsMapType() {
+		return fmt.Errorf("labels: expect a map of strings, got %s", t.FriendlyName())
+	}
+	l := &schema.Labels{V: make(map[string]string)}
+	for it := a.V.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if v.IsNull() || v.Type() != cty.String {
+			return fmt.Errorf("labels: expect string value for label %q", k.AsString())
+		}
+		l.V[k.AsString()] = v.AsString()
+	}
+	if len(l.V) > 0 {
+		schema.ReplaceOrAppend(attrs, l)
+	}
+	return nil
+}
+
+// fromLabels returns the "labels" attribute for the schema.Labels attribute, if exists.
+func fromLabels(attrs []schema.Attr) *schemahcl.Attr {
+	l := schema.LabelsOf(attrs)
+	if l == nil || len(l.V) == 0 {
+		return nil
+	}
+	vs := make(map[string]cty.Value, len(l.V))
+	for k, v := range l.V {
+		vs[k] = cty.StringVal(v)
+	}
+	return &schemahcl.Attr{K: "labels", V: cty.ObjectVal(vs)}
+}
+
 // ReferenceVars holds the HCL variables
 // for foreign keys' referential-actions.
 var ReferenceVars = []string{
@@ -214,6 +214,8 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 			changes = opts.AddOrSkip(changes, &schema.DropTable{T: t1})
 		case err != nil:
 			return nil, err
+		// Tables with identical checksums, computed on inspection, are skipped.
+		case sameChecksum(t1.Attrs, t2.Attrs):
 		default:
 			if change, err := d.tableDiff(t1, t2, opts); err != nil {
 				return nil, err
@@ -247,6 +249,9 @@ func (d *Diff) schemaDiff(from, to *schema.Schema, opts *schema.DiffOptions) ([]
 			changes = opts.AddOrSkip(changes, &schema.DropView{V: v1})
 			continue
 		}
+		if sameChecksum(v1.Attrs, v2.Attrs) {
+			continue
+		}
 		if change, err := d.viewDiff(v1, v2, opts); err != nil {
 			return nil, err
 		} else {
@@ -295,6 +300,12 @@ func (d *Diff) TableDiff(from, to *schema.Table, options ...schema.DiffOption) (
 }
 
 // tableDiff implements the table diffing but skips the table name check.
+// sameChecksum reports if both objects have the same checksum cached on inspection.
+func sameChecksum(from, to []schema.Attr) bool {
+	var c1, c2 schema.Checksum
+	return Has(from, &c1) && Has(to, &c2) && c1.V == c2.V
+}
+
 func (d *Diff) tableDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
 	// tableDiff can be called with non-identical
 	// names without affecting the diff process.
@@ -397,6 +408,9 @@ func (d *Diff) columnDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]s
 		if err != nil {
 			return nil, err
 		}
+		if m, ok := change.(*schema.ModifyColumn); ok {
+			change = ignoreColumnChanges(m)
+		}
 		if change != NoChange {
 			all = append(all, change)
 		}
@@ -422,6 +436,43 @@ func (d *Diff) columnDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]s
 	return changes, nil
 }
 
+// ignoreColumnChanges masks the column changes that are ignored by the desired
+// state. In case the column is still modified, the ignored properties are taken
+// from the current state to ensure they are not changed by the migration.
+func ignoreColumnChanges(m *schema.ModifyColumn) schema.Change {
+	var ig schema.DiffIgnore
+	if !Has(m.To.Attrs, &ig) || !m.Change.Is(ig.Changes) {
+		return m
+	}
+	if m.Change &^= ig.Changes; m.Change == schema.NoChange {
+		return NoChange
+	}
+	to := *m.To
+	to.Type = &schema.ColumnType{Type: m.To.Type.Type, Raw: m.To.Type.Raw, Null: m.To.Type.Null}
+	if ig.Changes.Is(schema.ChangeDefault) {
+		to.Default = m.From.Default
+	}
+	if ig.Changes.Is(schema.ChangeNull) {
+		to.Type.Null = m.From.Type.Null
+	}
+	if ig.Changes.Is(schema.ChangeType) {
+		to.Type.Type, to.Type.Raw = m.From.Type.Type, m.From.Type.Raw
+	}
+	if ig.Changes.Is(schema.ChangeComment) {
+		to.Attrs = make([]schema.Attr, 0, len(m.To.Attrs))
+		for _, a := range m.To.Attrs {
+			if _, ok := a.(*schema.Comment); !ok {
+				to.Attrs = append(to.Attrs, a)
+			}
+		}
+		if c := (&schema.Comment{}); Has(m.From.Attrs, c) {
+			to.Attrs = append(to.Attrs, c)
+		}
+	}
+	m.To = &to
+	return m
+}
+
 // pkDiff returns the schema changes (if any) for migrating table
 // primary-key from current state to the desired state.
 func (d *Diff) pkDiff(from, to *schema.Table, opts *schema.DiffOptions) (changes []schema.Change) {
@@ -310,6 +310,124 @@ func isDropped(changes []schema.Change, t *schema.Table) bool {
 	return false
 }
 
+// IdentChecker validates the identifiers of the objects that are created
+// or renamed by schema changes, before the changes are planned.
+type IdentChecker struct {
+	// MaxLen is the maximum length of identifiers. Zero means no limit.
+	MaxLen int
+	// Len returns the length of the identifier as counted by the
+	// database. If nil, the number of bytes is used.
+	Len func(string) int
+	// Unit of the length used in error messages (e.g., bytes).
+	Unit string
+	// Reserved reports if the identifier is a reserved keyword of the dialect.
+	// If nil, reserved keywords are not checked.
+	Reserved func(string) bool
+}
+
+// CheckChanges returns an error describing all identifiers in the given changes
+// that exceed the maximum length, or are reserved keywords. Objects that were loaded
+// from files (e.g., HCL) are reported along with their position in the file.
+func (c *IdentChecker) CheckChanges(changes []schema.Change) error {
+	var errs []error
+	check := func(attrs []schema.Attr, name, kind, of string) {
+		if name == "" {
+			return
+		}
+		var loc string
+		if p := (schema.Pos{}); Has(attrs, &p) && p.Filename != "" {
+			loc = fmt.Sprintf("%s:%d:%d: ", p.Filename, p.Start.Line, p.Start.Column)
+		}
+		loc += fmt.Sprintf("%s %q", kind, name)
+		if of != "" {
+			loc += " of " + of
+		}
+		n := len(name)
+		if c.Len != nil {
+			n = c.Len(name)
+		}
+		if c.MaxLen > 0 && n > c.MaxLen {
+			errs = append(errs, fmt.Errorf("%s exceeds the maximum identifier length of %d %s (%d)", loc, c.MaxLen, c.Unit, n))
+		}
+		if c.Reserved != nil && c.Reserved(name) {
+			errs = append(errs, fmt.Errorf("%s is named after a reserved keyword", loc))
+		}
+	}
+	for _, ch := range changes {
+		switch ch := ch.(type) {
+		case *schema.AddSchema:
+			check(ch.S.Attrs, ch.S.Name, "schema", "")
+		case *schema.AddTable:
+			t, of := ch.T, fmt.Sprintf("table %q", ch.T.Name)
+			check(t.Attrs, t.Name, "table", "")
+			for _, c := range t.Columns {
+				check(c.Attrs, c.Name, "column", of)
+			}
+			if t.PrimaryKey != nil {
+				check(t.PrimaryKey.Attrs, t.PrimaryKey.Name, "primary key", of)
+			}
+			for _, idx := range t.Indexes {
+				check(idx.Attrs, idx.Name, "index", of)
+			}
+			for _, fk := range t.ForeignKeys {
+				check(fk.Attrs, fk.Symbol, "foreign key", of)
+			}
+			for _, a := range t.Attrs {
+				if ck, ok := a.(*schema.Check); ok {
+					check(ck.Attrs, ck.Name, "check", of)
+				}
+			}
+		case *schema.RenameTable:
+			check(ch.To.Attrs, ch.To.Name, "table", "")
+		case *schema.ModifyTable:
+			of := fmt.Sprintf("table %q", ch.T.Name)
+			for _, mc := range ch.Changes {
+				switch mc := mc.(type) {
+				case *schema.AddColumn:
+					check(mc.C.Attrs, mc.C.Name, "column", of)
+				case *schema.RenameColumn:
+					check(mc.To.Attrs, mc.To.Name, "column", of)
+				case *schema.AddIndex:
+					check(mc.I.Attrs, mc.I.Name, "index", of)
+				case *schema.RenameIndex:
+					check(mc.To.Attrs, mc.To.Name, "index", of)
+				case *schema.AddPrimaryKey:
+					check(mc.P.Attrs, mc.P.Name, "primary key", of)
+				case *schema.AddForeignKey:
+					check(mc.F.Attrs, mc.F.Symbol, "foreign key", of)
+				case *schema.AddCheck:
+					check(mc.C.Attrs, mc.C.Name, "check", of)
+				}
+			}
+		case *schema.AddView:
+			check(ch.V.Attrs, ch.V.Name, "view", "")
+		case *schema.RenameView:
+			check(ch.To.Attrs, ch.To.Name, "view", "")
+		case *schema.AddFunc:
+			check(ch.F.Attrs, ch.F.Name, "function", "")
+		case *schema.RenameFunc:
+			check(ch.To.Attrs, ch.To.Name, "function", "")
+		case *schema.AddProc:
+			check(ch.P.Attrs, ch.P.Name, "procedure", "")
+		case *schema.RenameProc:
+			check(ch.To.Attrs, ch.To.Name, "procedure", "")
+		case *schema.AddTrigger:
+			check(ch.T.Attrs, ch.T.Name, "trigger", "")
+		case *schema.RenameTrigger:
+			check(ch.To.Attrs, ch.To.Name, "trigger", "")
+		case *schema.AddObject:
+			if o, ok := ch.O.(schema.SpecTypeNamer); ok {
+				check(nil, o.SpecName(), o.SpecType(), "")
+			}
+		case *schema.RenameObject:
+			if o, ok := ch.To.(schema.SpecTypeNamer); ok {
+				check(nil, o.SpecName(), o.SpecType(), "")
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // CheckChangesScope checks that changes can be applied
 // on a schema scope (connection).
 func CheckChangesScope(opts migrate.PlanOptions, changes []schema.Change) error {
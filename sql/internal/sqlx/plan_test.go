@@ -6,6 +6,7 @@ package sqlx
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/migrate"
@@ -154,6 +155,42 @@ func TestCheckChangesScope(t *testing.T) {
 	require.EqualError(t, err, "found 2 schemas when migration plan is scoped to one: [\"s1\" \"s2\"]")
 }
 
+func TestIdentChecker_CheckChanges(t *testing.T) {
+	var (
+		long  = strings.Repeat("a", 64)
+		users = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn(long, "int"))
+		pos = &schema.Pos{Filename: "schema.hcl"}
+		c   = &IdentChecker{MaxLen: 63, Unit: "bytes"}
+	)
+	pos.Start.Line, pos.Start.Column = 3, 5
+	users.Columns[1].AddAttrs(pos)
+	users.AddIndexes(schema.NewIndex(long).AddColumns(users.Columns[0]))
+	changes := []schema.Change{
+		&schema.AddTable{T: users},
+		&schema.ModifyTable{
+			T: schema.NewTable("pets"),
+			Changes: []schema.Change{
+				&schema.RenameColumn{From: schema.NewColumn("name"), To: schema.NewColumn(long)},
+				&schema.AddCheck{C: schema.NewCheck().SetName("user")},
+			},
+		},
+		&schema.AddObject{O: &schema.EnumType{T: "user"}},
+	}
+	err := c.CheckChanges(changes)
+	require.EqualError(t, err, strings.Join([]string{
+		`schema.hcl:3:5: column "` + long + `" of table "users" exceeds the maximum identifier length of 63 bytes (64)`,
+		`index "` + long + `" of table "users" exceeds the maximum identifier length of 63 bytes (64)`,
+		`column "` + long + `" of table "pets" exceeds the maximum identifier length of 63 bytes (64)`,
+	}, "\n"))
+
+	// Reserved keywords are checked only if configured.
+	c.MaxLen, c.Reserved = 0, func(s string) bool { return s == "user" }
+	err = c.CheckChanges(changes)
+	require.EqualError(t, err, `check "user" of table "pets" is named after a reserved keyword`+"\n"+`enum "user" is named after a reserved keyword`)
+	require.NoError(t, (&IdentChecker{}).CheckChanges(changes))
+}
+
 func TestSameTable(t *testing.T) {
 	t1 := schema.NewTable("t1")
 	require.True(t, SameTable(t1, t1))
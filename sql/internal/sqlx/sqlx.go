@@ -185,6 +185,28 @@ func TypedSchemaFKs[T ScanStringer](s *schema.Schema, rows *sql.Rows, attr ...*F
 	return nil
 }
 
+// RealmChecksums caches the checksums of the tables and views of an inspected realm.
+// It is called by drivers at the end of the inspection, after the excluded resources
+// were filtered. For example:
+//
+//	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
+func RealmChecksums(r *schema.Realm, err error) (*schema.Realm, error) {
+	if err != nil {
+		return nil, err
+	}
+	schema.SetChecksums(r.Schemas...)
+	return r, nil
+}
+
+// SchemaChecksums is like RealmChecksums, but for an inspected schema.
+func SchemaChecksums(s *schema.Schema, err error) (*schema.Schema, error) {
+	if err != nil {
+		return nil, err
+	}
+	schema.SetChecksums(s)
+	return s, nil
+}
+
 // LinkSchemaTables links foreign-key stub tables/columns to actual elements.
 func LinkSchemaTables(schemas []*schema.Schema) {
 	byName := make(map[string]map[string]*schema.Table)
@@ -19,6 +19,7 @@ import (
 	"regexp"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"text/template"
@@ -271,7 +272,9 @@ const (
 	directiveDelimiter = "delimiter"
 	// atlas:checkpoint directive.
 	directiveCheckpoint = "checkpoint"
-	directivePrefixSQL  = "-- "
+	// atlas:tolerate directive.
+	directiveTolerate  = "tolerate"
+	directivePrefixSQL = "-- "
 )
 
 var reDirective = regexp.MustCompile(`^([ -~]*)atlas:(\w+)(?: +([ -~]*))*`)
@@ -708,6 +711,177 @@ func (f HashFile) SumByName(n string) (string, error) {
 	return "", errors.New("checksum not found")
 }
 
+// VersionFileName of the migration directory version pinning file.
+const VersionFileName = "atlas.version"
+
+// Version policies control how version incompatibilities are reported.
+const (
+	VersionPolicyWarn  = "warn"
+	VersionPolicyError = "error"
+)
+
+// VersionFile records the minimum Atlas CLI version and the minimum dialect
+// versions a migration directory was authored against. Its format is line-based,
+// where each line holds a name and a version, and lines starting with '#' are comments:
+//
+//	atlas v0.14.0
+//	postgres 15
+//	policy error
+type VersionFile struct {
+	Atlas    string            // Minimum Atlas CLI version.
+	Dialects map[string]string // Minimum dialect versions, keyed by driver name.
+	Policy   string            // Either VersionPolicyWarn (default) or VersionPolicyError.
+}
+
+// VersionError is returned by VersionFile.Check in case the
+// given version is lower than the one pinned in the file.
+type VersionError struct {
+	Name    string // atlas or a dialect name.
+	Pinned  string // version pinned in the file.
+	Current string // version in use.
+}
+
+// Error implements the error interface.
+func (err *VersionError) Error() string {
+	return fmt.Sprintf("%s version %s is lower than %s pinned in %s", err.Name, err.Current, err.Pinned, VersionFileName)
+}
+
+// ReadVersionFile reads the version pinning file from the given Dir.
+// A nil VersionFile is returned in case the file does not exist.
+func ReadVersionFile(dir Dir) (*VersionFile, error) {
+	b, err := fs.ReadFile(dir, VersionFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := &VersionFile{}
+	if err := f.UnmarshalText(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// WriteVersionFile writes the given VersionFile to the Dir. If the file does not exist, it is created.
+func WriteVersionFile(dir Dir, f *VersionFile) error {
+	b, err := f.MarshalText()
+	if err != nil {
+		return err
+	}
+	return dir.WriteFile(VersionFileName, b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f *VersionFile) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	if f.Atlas != "" {
+		fmt.Fprintf(&buf, "atlas %s\n", f.Atlas)
+	}
+	names := make([]string, 0, len(f.Dialects))
+	for n := range f.Dialects {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(&buf, "%s %s\n", n, f.Dialects[n])
+	}
+	if f.Policy != "" {
+		fmt.Fprintf(&buf, "policy %s\n", f.Policy)
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *VersionFile) UnmarshalText(b []byte) error {
+	sc := bufio.NewScanner(bytes.NewReader(b))
+	for i := 1; sc.Scan(); i++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			return fmt.Errorf("%s:%d: expect a name and a version, got %q", VersionFileName, i, line)
+		}
+		switch name, v := parts[0], parts[1]; {
+		case name == "policy":
+			if v != VersionPolicyWarn && v != VersionPolicyError {
+				return fmt.Errorf("%s:%d: unknown policy %q", VersionFileName, i, v)
+			}
+			f.Policy = v
+		case !validVersion(v):
+			return fmt.Errorf("%s:%d: invalid version %q", VersionFileName, i, v)
+		case name == "atlas":
+			f.Atlas = v
+		default:
+			if f.Dialects == nil {
+				f.Dialects = make(map[string]string)
+			}
+			f.Dialects[name] = v
+		}
+	}
+	return sc.Err()
+}
+
+// Check checks the given version of atlas or a dialect against the one pinned
+// in the file. A VersionError is returned in case it is lower than the pinned one.
+// Versions that are not pinned, or cannot be parsed, are ignored.
+func (f *VersionFile) Check(name, version string) error {
+	pinned := f.Dialects[name]
+	if name == "atlas" {
+		pinned = f.Atlas
+	}
+	if pinned == "" || !validVersion(version) || compareVersions(version, pinned) >= 0 {
+		return nil
+	}
+	return &VersionError{Name: name, Pinned: pinned, Current: version}
+}
+
+// validVersion reports if the given string is a dot-separated
+// numeric version, optionally prefixed with 'v' and suffixed
+// with a pre-release or build identifier (e.g., v0.14.0-canary).
+func validVersion(v string) bool {
+	return versionRe.MatchString(v)
+}
+
+var versionRe = regexp.MustCompile(`^v?\d+(\.\d+)*([-+].*)?$`)
+
+// compareVersions compares two valid versions by their numeric parts.
+// Missing parts are treated as zero. i.e., "15" and "15.0" are equal.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	v = strings.TrimPrefix(v, "v")
+	if i := strings.IndexAny(v, "-+"); i != -1 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
+
 // Reason for a checksum mismatch.
 const (
 	ReasonAdded Reason = iota + 1
@@ -87,6 +87,37 @@ var (
 	initialUp []byte
 )
 
+func TestVersionFile(t *testing.T) {
+	d := &migrate.MemDir{}
+	f, err := migrate.ReadVersionFile(d)
+	require.NoError(t, err)
+	require.Nil(t, f, "missing file")
+
+	require.NoError(t, d.WriteFile(migrate.VersionFileName, []byte("# Authored against:\natlas v0.14.0\n\npostgres 15\npolicy error\n")))
+	f, err = migrate.ReadVersionFile(d)
+	require.NoError(t, err)
+	require.Equal(t, &migrate.VersionFile{Atlas: "v0.14.0", Dialects: map[string]string{"postgres": "15"}, Policy: migrate.VersionPolicyError}, f)
+	b, err := f.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "atlas v0.14.0\npostgres 15\npolicy error\n", string(b))
+
+	require.NoError(t, f.Check("atlas", "v0.14.0"))
+	require.NoError(t, f.Check("atlas", "v0.15.1-abcdef-canary"))
+	require.NoError(t, f.Check("atlas", "development"), "unparsable versions are ignored")
+	require.NoError(t, f.Check("mysql", "5.6"), "unpinned dialect")
+	require.NoError(t, f.Check("postgres", "15.4"))
+	require.NoError(t, f.Check("postgres", "16"))
+	err = f.Check("atlas", "v0.13.5")
+	require.EqualError(t, err, "atlas version v0.13.5 is lower than v0.14.0 pinned in atlas.version")
+	var verr *migrate.VersionError
+	require.ErrorAs(t, f.Check("postgres", "14.9"), &verr)
+	require.Equal(t, &migrate.VersionError{Name: "postgres", Pinned: "15", Current: "14.9"}, verr)
+
+	for _, c := range []string{"atlas", "atlas 1.x", "policy ignore", "mysql 8 0"} {
+		require.Error(t, (&migrate.VersionFile{}).UnmarshalText([]byte(c)), c)
+	}
+}
+
 func TestValidate(t *testing.T) {
 	// Add the sum file form the testdata/migrate dir without any files in it - should fail.
 	p := t.TempDir()
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"slices"
 	"strings"
 	"time"
@@ -56,9 +57,56 @@ type (
 
 		// The Source that caused this change, or nil.
 		Source schema.Change
+
+		// NoTx indicates the statement cannot be executed inside a transaction
+		// block, for example, CREATE INDEX CONCURRENTLY in PostgreSQL. Hence,
+		// it is executed in a separate stage. See Plan.Stages for details.
+		NoTx bool
+	}
+
+	// A Stage is a group of consecutive changes in a plan that are executed together.
+	Stage struct {
+		// Changes of the stage.
+		Changes []*Change
+
+		// Transactional reports if the stage can be wrapped in a transaction.
+		Transactional bool
 	}
 )
 
+// Stages splits the changes of a transactional plan into execution stages. Changes
+// that cannot be executed inside a transaction are isolated into stages of their own,
+// and the rest are grouped into transactional stages, in their original order. For
+// example, the changes [c1, c2, c3(NoTx), c4] are split into [c1, c2], [c3] and [c4].
+//
+// Plans that are not transactional (e.g., MySQL DDLs that cause an implicit commit)
+// are returned as a single non-transactional stage.
+func (p *Plan) Stages() []*Stage {
+	if len(p.Changes) == 0 {
+		return nil
+	}
+	if !p.Transactional {
+		return []*Stage{{Changes: p.Changes}}
+	}
+	var (
+		stages []*Stage
+		last   *Stage
+	)
+	for _, c := range p.Changes {
+		switch {
+		case c.NoTx:
+			last = nil
+			stages = append(stages, &Stage{Changes: []*Change{c}})
+		case last == nil:
+			last = &Stage{Changes: []*Change{c}, Transactional: true}
+			stages = append(stages, last)
+		default:
+			last.Changes = append(last.Changes, c)
+		}
+	}
+	return stages
+}
+
 // ReverseStmts returns the reverse statements of a Change, if any.
 func (c *Change) ReverseStmts() (cmd []string, err error) {
 	switch r := c.Reverse.(type) {
@@ -111,6 +159,14 @@ type (
 		// This is useful to indicate to the driver whether the context is a live database, an empty one, or the
 		// versioned migration workflow.
 		Mode PlanMode
+		// NoCombine instructs the driver to plan each table change in its own statement,
+		// instead of combining the changes of a table into one statement (e.g., ALTER TABLE).
+		// Drivers that do not combine changes ignore this option.
+		NoCombine bool
+		// CheckReserved instructs the driver to fail planning in case one of the
+		// created or renamed objects is named after a reserved keyword of the database.
+		// Drivers that do not keep a list of reserved keywords ignore this option.
+		CheckReserved bool
 	}
 
 	// PlanMode defines the plan mode to use.
@@ -199,8 +255,10 @@ type (
 		fmt      Formatter           // how to format a plan to migration files
 		sum      bool                // whether to create a sum file for the migration directory
 		exclude  []string            // exclude resources from planning that match the patterns
+		protect  []string            // refuse planning changes to resources that match the patterns
 		planOpts []PlanOption        // plan options
 		diffOpts []schema.DiffOption // diff options
+		cache    ReplayCache         // optional cache for the replayed state
 	}
 
 	// PlannerOption allows managing a Planner using functional arguments.
@@ -235,6 +293,7 @@ type (
 		Hash            string        `json:"-"`                   // Hash of migration file.
 		PartialHashes   []string      `json:"-"`                   // PartialHashes is the hashes of applied statements.
 		OperatorVersion string        `json:"OperatorVersion"`     // OperatorVersion that executed this migration.
+		Etag            int           `json:"-"`                   // Etag is incremented on every write and guards against concurrent updates.
 	}
 
 	// RevisionType defines the type of the revision record in the history table.
@@ -250,12 +309,30 @@ type (
 		baselineVer string             // Start the first migration after the given baseline version.
 		allowDirty  bool               // Allow start working on a non-clean database.
 		operator    string             // Revision.OperatorVersion
+		stmtx       StmtExecutor       // Optional executor the statements are proxied to.
+		tolerate    []string           // Statement errors to tolerate.
 	}
 
 	// ExecutorOption allows configuring an Executor using functional arguments.
 	ExecutorOption func(*Executor) error
+
+	// StmtExecutor executes the statements of migration files instead of the database driver.
+	// It allows proxying the execution to external systems, such as an HTTP endpoint or a
+	// message queue, for organizations where DDLs are executed by DBAs using separate tools.
+	StmtExecutor interface {
+		// ExecStmt executes the given statement of the migration file. Implementations
+		// that submit the statement for execution by an external system should return
+		// ErrStmtPending. In this case, the revision is recorded as pending, and the execution
+		// of the file stops until its revision is resolved (e.g., using 'migrate set'). Then,
+		// the statement is recorded as applied, and the execution continues from the next one.
+		ExecStmt(ctx context.Context, f File, stmt *Stmt) error
+	}
 )
 
+// ErrStmtPending is returned by StmtExecutor implementations to indicate that
+// the statement was submitted for execution by an external system.
+var ErrStmtPending = errors.New("sql/migrate: statement is pending external execution")
+
 const (
 	// RevisionTypeUnknown represents an unknown revision type.
 	// This type is unexpected and exists here to only ensure
@@ -346,6 +423,26 @@ func PlanWithMode(m PlanMode) PlannerOption {
 	}
 }
 
+// PlanWithNoCombine instructs the driver to plan each table change in its own
+// statement. It is useful for reviewing the generated migration files.
+func PlanWithNoCombine() PlannerOption {
+	return func(p *Planner) {
+		p.planOpts = append(p.planOpts, func(o *PlanOptions) {
+			o.NoCombine = true
+		})
+	}
+}
+
+// PlanWithCheckReserved instructs the driver to fail planning in case
+// objects are named after reserved keywords, even though they are quoted.
+func PlanWithCheckReserved() PlannerOption {
+	return func(p *Planner) {
+		p.planOpts = append(p.planOpts, func(o *PlanOptions) {
+			o.CheckReserved = true
+		})
+	}
+}
+
 // PlanWithDiffOptions allows setting custom diff options.
 func PlanWithDiffOptions(opts ...schema.DiffOption) PlannerOption {
 	return func(p *Planner) {
@@ -376,6 +473,23 @@ func PlanWithExclude(patterns ...string) PlannerOption {
 	}
 }
 
+// PlanWithReplayCache allows setting a cache for the current state of the
+// migration directory. See ReplayWithCache for more info.
+func PlanWithReplayCache(c ReplayCache) PlannerOption {
+	return func(p *Planner) {
+		p.cache = c
+	}
+}
+
+// PlanWithProtect allows setting protection patterns for the planner. Planning
+// fails in case a resource that matches the patterns is dropped or modified.
+// See CheckProtected for more info.
+func PlanWithProtect(patterns ...string) PlannerOption {
+	return func(p *Planner) {
+		p.protect = patterns
+	}
+}
+
 var (
 	// WithFormatter calls PlanFormat.
 	// Deprecated: use PlanFormat instead.
@@ -436,6 +550,9 @@ func (p *Planner) plan(ctx context.Context, name string, to StateReader, realmSc
 	if len(changes) == 0 {
 		return nil, ErrNoPlan
 	}
+	if err := CheckProtected(changes, p.protect); err != nil {
+		return nil, err
+	}
 	return p.drv.PlanChanges(ctx, name, changes, p.planOpts...)
 }
 
@@ -488,6 +605,10 @@ func (p *Planner) current(ctx context.Context, realmScope bool) (*schema.Realm,
 	if err != nil {
 		return nil, err
 	}
+	var opts []ReplayOption
+	if p.cache != nil {
+		opts = append(opts, ReplayWithCache(p.cache))
+	}
 	return from.Replay(ctx, func() StateReader {
 		if realmScope {
 			return RealmConn(p.drv, &schema.InspectRealmOption{
@@ -499,7 +620,7 @@ func (p *Planner) current(ctx context.Context, realmScope bool) (*schema.Realm,
 		return SchemaConn(p.drv, "", &schema.InspectOptions{
 			Exclude: p.exclude,
 		})
-	}())
+	}(), opts...)
 }
 
 // WritePlan writes the given Plan to the Dir based on the configured Formatter.
@@ -573,6 +694,21 @@ func (e MissingMigrationError) Error() string {
 	)
 }
 
+// RevisionConflictError is returned by a RevisionReadWriter if a revision was
+// modified by another process since it was read, and the write was rejected.
+type RevisionConflictError struct {
+	Version string // Version of the revision.
+	Etag    int    // Etag the write was based on.
+}
+
+// Error implements error.
+func (e RevisionConflictError) Error() string {
+	return fmt.Sprintf(
+		"sql/migrate: revision %q was modified concurrently (expected etag %d): another process may be executing migrations",
+		e.Version, e.Etag,
+	)
+}
+
 // NewExecutor creates a new Executor with default values.
 func NewExecutor(drv Driver, dir Dir, rrw RevisionReadWriter, opts ...ExecutorOption) (*Executor, error) {
 	if drv == nil {
@@ -658,6 +794,31 @@ func WithExecOrder(o ExecOrder) ExecutorOption {
 	}
 }
 
+// WithStmtExecutor sets the StmtExecutor the statements are proxied to
+// instead of being executed on the database.
+func WithStmtExecutor(x StmtExecutor) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.stmtx = x
+		return nil
+	}
+}
+
+// WithTolerateErrors sets the statement errors to tolerate when executing migration
+// files. A pattern matches an error if it is equal to its SQLSTATE code (if exposed
+// by the database driver) or if it is contained in its message, case-insensitive.
+// For example, "42P07" or "already exists". Tolerated errors are reported to the
+// Logger as LogWarning entries, and their statements are recorded as applied.
+//
+// Patterns can be also defined per file or per statement using the "atlas:tolerate"
+// directive. Note that some databases (e.g., PostgreSQL) abort the transaction on
+// error, and therefore tolerating errors requires executing the file without one.
+func WithTolerateErrors(patterns ...string) ExecutorOption {
+	return func(ex *Executor) error {
+		ex.tolerate = append(ex.tolerate, patterns...)
+		return nil
+	}
+}
+
 // WithOperatorVersion sets the operator version to save on the revisions
 // when executing migration files.
 func WithOperatorVersion(v string) ExecutorOption {
@@ -832,6 +993,19 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 			Hash:        hash,
 		}
 	}
+	// A statement that was submitted for external execution is not submitted again.
+	// Once the revision is resolved by the user, the statement is recorded as applied,
+	// and the execution continues from the statement that follows it.
+	if r.Error == ErrStmtPending.Error() {
+		if !r.Type.Has(RevisionTypeResolved) || r.Applied >= len(stmts) {
+			err = &ExecPendingError{File: m.Name(), Version: r.Version, Stmt: r.ErrorStmt}
+			e.log.Log(LogError{Error: err})
+			return err
+		}
+		r.PartialHashes = append(r.PartialHashes, "h1:"+sums[r.Applied])
+		r.Applied++
+		r.Type, r.Error, r.ErrorStmt = RevisionTypeExecute, "", ""
+	}
 	// Save once to mark as started in the database.
 	if err = e.writeRevision(ctx, r); err != nil {
 		e.log.Log(LogError{Error: err})
@@ -865,7 +1039,21 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 	}
 	for _, stmt := range stmts[r.Applied:] {
 		e.log.Log(LogStmt{SQL: stmt.Text, Stmt: stmt})
-		if _, err = e.drv.ExecContext(ctx, stmt.Text); err != nil {
+		err = e.execStmt(ctx, m, stmt)
+		if err != nil && !errors.Is(err, ErrStmtPending) && e.tolerated(m, stmt, err) {
+			e.log.Log(LogWarning{SQL: stmt.Text, Error: err})
+			err = nil
+		}
+		// The statements that follow a pending statement may depend on it.
+		// Hence, the execution stops until the revision is resolved.
+		if errors.Is(err, ErrStmtPending) {
+			r.ErrorStmt = stmt.Text
+			r.Error = ErrStmtPending.Error()
+			err = &ExecPendingError{File: m.Name(), Version: r.Version, Stmt: stmt.Text}
+			e.log.Log(LogError{Error: err})
+			return err
+		}
+		if err != nil {
 			e.log.Log(LogError{SQL: stmt.Text, Error: err})
 			r.done()
 			r.ErrorStmt = stmt.Text
@@ -891,6 +1079,41 @@ func (e *Executor) Execute(ctx context.Context, m File) (err error) {
 	return
 }
 
+// execStmt executes the statement on the database, or proxies it to the configured StmtExecutor.
+func (e *Executor) execStmt(ctx context.Context, f File, stmt *Stmt) error {
+	if e.stmtx != nil {
+		return e.stmtx.ExecStmt(ctx, f, stmt)
+	}
+	_, err := e.drv.ExecContext(ctx, stmt.Text)
+	return err
+}
+
+// tolerated reports if the statement error matches one of the patterns configured
+// on the executor, or defined by the "atlas:tolerate" directives of the file or the
+// statement.
+func (e *Executor) tolerated(f File, stmt *Stmt, err error) bool {
+	patterns := append(make([]string, 0, len(e.tolerate)), e.tolerate...)
+	if d, ok := f.(interface{ Directive(string) []string }); ok {
+		patterns = append(patterns, d.Directive(directiveTolerate)...)
+	}
+	patterns = append(patterns, stmt.Directive(directiveTolerate)...)
+	var (
+		code string
+		msg  = strings.ToLower(err.Error())
+	)
+	var s interface{ SQLState() string }
+	if errors.As(err, &s) {
+		code = s.SQLState()
+	}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p != "" && (strings.EqualFold(p, code) || strings.Contains(msg, strings.ToLower(p))) {
+			return true
+		}
+	}
+	return false
+}
+
 func (e *Executor) writeRevision(ctx context.Context, r *Revision) error {
 	r.ExecutedAt = time.Now()
 	r.OperatorVersion = e.operator
@@ -915,6 +1138,18 @@ func (e WriteRevisionError) Unwrap() error {
 	return e.Err
 }
 
+// ExecPendingError is returned if a statement of a migration file was submitted for
+// execution by an external system, and the file is pending its execution.
+type ExecPendingError struct {
+	File    string
+	Version string
+	Stmt    string // The pending statement.
+}
+
+func (e ExecPendingError) Error() string {
+	return fmt.Sprintf("sql/migrate: statement %q of file %q is pending external execution. Use 'migrate set' to resolve revision %q once it was executed", e.Stmt, e.File, e.Version)
+}
+
 // HistoryChangedError is returned if between two execution attempts already applied statements of a file have changed.
 type HistoryChangedError struct {
 	File string
@@ -1037,10 +1272,22 @@ func (e *Executor) exec(ctx context.Context, files []File) error {
 
 type (
 	replayConfig struct {
-		version string // to which version to replay (inclusive)
+		version string      // to which version to replay (inclusive)
+		cache   ReplayCache // optional cache for the replayed state
 	}
 	// ReplayOption configures a migration directory replay behavior.
 	ReplayOption func(*replayConfig)
+
+	// ReplayCache wraps the methods for caching the state of a migration directory
+	// replay. The key of the state is derived from the directory checksum (atlas.sum)
+	// and the replayed version, and it changes when the directory content changes.
+	ReplayCache interface {
+		// Load returns the cached state of the given key. A nil
+		// realm is returned in case the key is not in the cache.
+		Load(ctx context.Context, key string) (*schema.Realm, error)
+		// Store stores the state of the given key in the cache.
+		Store(ctx context.Context, key string, r *schema.Realm) error
+	}
 )
 
 // ReplayToVersion configures the last version to apply when replaying the migration directory.
@@ -1050,12 +1297,60 @@ func ReplayToVersion(v string) ReplayOption {
 	}
 }
 
+// ReplayWithCache configures a cache for the replayed state. If the state of the
+// migration directory is found in the cache, it is returned without replaying the
+// directory on the database. Otherwise, the replayed state is stored in the cache.
+func ReplayWithCache(cache ReplayCache) ReplayOption {
+	return func(c *replayConfig) {
+		c.cache = cache
+	}
+}
+
+// ReplayCacheKey returns the cache key of the migration directory state at the given version.
+// An empty version indicates the state after replaying all files in the directory.
+func ReplayCacheKey(dir Dir, version string) (string, error) {
+	sum, err := dir.Checksum()
+	if err != nil {
+		return "", fmt.Errorf("sql/migrate: compute directory checksum: %w", err)
+	}
+	key := "h1:" + sum.Sum()
+	if version != "" {
+		key += "@" + version
+	}
+	return key, nil
+}
+
 // Replay the migration directory and invoke the state to get back the inspection result.
 func (e *Executor) Replay(ctx context.Context, r StateReader, opts ...ReplayOption) (_ *schema.Realm, err error) {
 	c := &replayConfig{}
 	for _, opt := range opts {
 		opt(c)
 	}
+	if c.cache == nil {
+		return e.replay(ctx, r, c)
+	}
+	key, err := ReplayCacheKey(e.dir, c.version)
+	if err != nil {
+		return nil, err
+	}
+	switch cached, err := c.cache.Load(ctx, key); {
+	case err != nil:
+		return nil, fmt.Errorf("sql/migrate: load replayed state from cache: %w", err)
+	case cached != nil:
+		return cached, nil
+	}
+	current, err := e.replay(ctx, r, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.Store(ctx, key, current); err != nil {
+		return nil, fmt.Errorf("sql/migrate: store replayed state in cache: %w", err)
+	}
+	return current, nil
+}
+
+// replay the migration directory and invoke the state to get back the inspection result.
+func (e *Executor) replay(ctx context.Context, r StateReader, c *replayConfig) (_ *schema.Realm, err error) {
 	// Clean up after ourselves.
 	restore, err := e.drv.(Snapshoter).Snapshot(ctx)
 	if err != nil {
@@ -1104,6 +1399,38 @@ type (
 		CheckClean(context.Context, *TableIdent) error
 	}
 
+	// StatsRefresher wraps the single RefreshStats method.
+	StatsRefresher interface {
+		// RefreshStats refreshes the planner statistics of the given tables (e.g., using ANALYZE).
+		// It is commonly called after large changes were applied, as query plans tend to regress
+		// until the statistics are refreshed.
+		RefreshStats(context.Context, []*schema.Table) error
+	}
+
+	// TableSizer wraps the single TableSizes method.
+	TableSizer interface {
+		// TableSizes returns the estimated number of rows stored in the given tables.
+		// Tables that do not exist in the database are omitted from the result.
+		TableSizes(context.Context, []*schema.Table) (map[*schema.Table]int64, error)
+	}
+
+	// PrivilegeChecker wraps the single MissingPrivileges method.
+	PrivilegeChecker interface {
+		// MissingPrivileges returns the privileges from the given list that are not granted to the
+		// connected user. It is commonly called before applying changes, in order to fail early instead
+		// of failing in the middle of the execution.
+		MissingPrivileges(context.Context, []*Privilege) ([]*Privilege, error)
+	}
+
+	// Privilege describes a privilege that is required for applying
+	// a change on a database object. For example, ALTER on a table.
+	Privilege struct {
+		Action string // One of: CREATE, ALTER or DROP.
+		Type   string // One of: schema, table, view, function or procedure.
+		Schema string // Schema name. Empty if the object resides in the connected schema.
+		Name   string // Object name. Empty for schema privileges.
+	}
+
 	// NotCleanError is returned when the connected dev-db is not in a clean state (aka it has schemas and tables).
 	// This check is done to ensure no data is lost by overriding it when working on the dev-db.
 	NotCleanError struct {
@@ -1131,6 +1458,389 @@ func (e *NotCleanError) Error() string {
 	return "sql/migrate: connected database is not clean: " + e.Reason
 }
 
+// ChangedTables returns the tables that were created, modified or renamed by
+// the given changes. Dropped tables are excluded, as there is nothing to refresh.
+func ChangedTables(changes []schema.Change) []*schema.Table {
+	var (
+		tables []*schema.Table
+		seen   = make(map[*schema.Table]bool)
+	)
+	add := func(t *schema.Table) {
+		if t != nil && !seen[t] {
+			seen[t] = true
+			tables = append(tables, t)
+		}
+	}
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			add(c.T)
+		case *schema.ModifyTable:
+			add(c.T)
+		case *schema.RenameTable:
+			add(c.To)
+		}
+	}
+	return tables
+}
+
+// List of privilege actions.
+const (
+	PrivilegeCreate = "CREATE"
+	PrivilegeAlter  = "ALTER"
+	PrivilegeDrop   = "DROP"
+)
+
+// String implements fmt.Stringer.
+func (p *Privilege) String() string {
+	switch {
+	case p.Type == "schema":
+		return fmt.Sprintf("%s on schema %q", p.Action, p.Schema)
+	case p.Schema == "":
+		return fmt.Sprintf("%s on %s %q", p.Action, p.Type, p.Name)
+	default:
+		return fmt.Sprintf("%s on %s %q.%q", p.Action, p.Type, p.Schema, p.Name)
+	}
+}
+
+// RequiredPrivileges returns the privileges required for applying the given changes.
+// Creating an object requires the CREATE privilege on it, and its schema is resolved
+// by the driver. Triggers are checked as changes of the table or view they belong to.
+// Objects that reside in schemas created by the changes are skipped, as they are
+// owned by the user applying them.
+func RequiredPrivileges(changes []schema.Change) []*Privilege {
+	var (
+		privs   []*Privilege
+		seen    = make(map[Privilege]bool)
+		created = make(map[*schema.Schema]bool)
+	)
+	for _, c := range changes {
+		if c, ok := c.(*schema.AddSchema); ok {
+			created[c.S] = true
+		}
+	}
+	add := func(action, typ string, s *schema.Schema, name string) {
+		p := Privilege{Action: action, Type: typ, Name: name}
+		if s != nil {
+			p.Schema = s.Name
+		}
+		if typ != "schema" && created[s] || seen[p] {
+			return
+		}
+		seen[p] = true
+		privs = append(privs, &p)
+	}
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			add(PrivilegeCreate, "schema", c.S, "")
+		case *schema.DropSchema:
+			add(PrivilegeDrop, "schema", c.S, "")
+		case *schema.ModifySchema:
+			add(PrivilegeAlter, "schema", c.S, "")
+		case *schema.AddTable:
+			add(PrivilegeCreate, "table", c.T.Schema, c.T.Name)
+		case *schema.DropTable:
+			add(PrivilegeDrop, "table", c.T.Schema, c.T.Name)
+		case *schema.ModifyTable:
+			add(PrivilegeAlter, "table", c.T.Schema, c.T.Name)
+		case *schema.RenameTable:
+			add(PrivilegeAlter, "table", c.From.Schema, c.From.Name)
+		case *schema.AddView:
+			add(PrivilegeCreate, "view", c.V.Schema, c.V.Name)
+		case *schema.DropView:
+			add(PrivilegeDrop, "view", c.V.Schema, c.V.Name)
+		case *schema.ModifyView:
+			add(PrivilegeAlter, "view", c.From.Schema, c.From.Name)
+		case *schema.RenameView:
+			add(PrivilegeAlter, "view", c.From.Schema, c.From.Name)
+		case *schema.AddFunc:
+			add(PrivilegeCreate, "function", c.F.Schema, c.F.Name)
+		case *schema.DropFunc:
+			add(PrivilegeDrop, "function", c.F.Schema, c.F.Name)
+		case *schema.ModifyFunc:
+			add(PrivilegeAlter, "function", c.From.Schema, c.From.Name)
+		case *schema.RenameFunc:
+			add(PrivilegeAlter, "function", c.From.Schema, c.From.Name)
+		case *schema.AddProc:
+			add(PrivilegeCreate, "procedure", c.P.Schema, c.P.Name)
+		case *schema.DropProc:
+			add(PrivilegeDrop, "procedure", c.P.Schema, c.P.Name)
+		case *schema.ModifyProc:
+			add(PrivilegeAlter, "procedure", c.From.Schema, c.From.Name)
+		case *schema.RenameProc:
+			add(PrivilegeAlter, "procedure", c.From.Schema, c.From.Name)
+		case *schema.AddTrigger:
+			addTriggerOwner(add, c.T)
+		case *schema.DropTrigger:
+			addTriggerOwner(add, c.T)
+		case *schema.ModifyTrigger:
+			addTriggerOwner(add, c.From)
+		case *schema.RenameTrigger:
+			addTriggerOwner(add, c.From)
+		}
+	}
+	return privs
+}
+
+// addTriggerOwner adds the ALTER privilege on the table or view the trigger belongs to.
+func addTriggerOwner(add func(string, string, *schema.Schema, string), t *schema.Trigger) {
+	switch {
+	case t.Table != nil:
+		add(PrivilegeAlter, "table", t.Table.Schema, t.Table.Name)
+	case t.View != nil:
+		add(PrivilegeAlter, "view", t.View.Schema, t.View.Name)
+	}
+}
+
+// StmtClass classifies planned statements by the amount of
+// work the database does to execute them.
+type StmtClass uint
+
+// List of statement classes, ordered by their cost.
+const (
+	// StmtClassMetadata describes statements that change only the catalog
+	// (e.g., creating a table), and are expected to complete immediately.
+	StmtClassMetadata StmtClass = iota
+	// StmtClassScan describes statements that read all table rows
+	// (e.g., building an index or validating a constraint).
+	StmtClassScan
+	// StmtClassRewrite describes statements that rewrite all
+	// table rows (e.g., changing the type of a column).
+	StmtClassRewrite
+)
+
+// String implements fmt.Stringer.
+func (c StmtClass) String() string {
+	switch c {
+	case StmtClassScan:
+		return "scan"
+	case StmtClassRewrite:
+		return "rewrite"
+	default:
+		return "metadata"
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c StmtClass) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// ClassifyChange returns the class of the statements planned for the given change.
+// The classification is conservative and does not take into account optimizations
+// done by specific databases or versions, such as instant column changes.
+func ClassifyChange(c schema.Change) StmtClass {
+	switch c := c.(type) {
+	case *schema.ModifyTable:
+		class := StmtClassMetadata
+		for _, c := range c.Changes {
+			class = max(class, ClassifyChange(c))
+		}
+		return class
+	case *schema.AddPrimaryKey, *schema.ModifyPrimaryKey:
+		return StmtClassRewrite
+	case *schema.ModifyColumn:
+		switch k := c.Change; {
+		case k.Is(schema.ChangeType), k.Is(schema.ChangeCharset), k.Is(schema.ChangeCollate), k.Is(schema.ChangeGenerated):
+			return StmtClassRewrite
+		case k.Is(schema.ChangeNull) && c.To.Type != nil && !c.To.Type.Null:
+			return StmtClassScan
+		}
+	case *schema.AddIndex, *schema.ModifyIndex, *schema.AddForeignKey, *schema.ModifyForeignKey, *schema.AddCheck, *schema.ModifyCheck:
+		return StmtClassScan
+	}
+	return StmtClassMetadata
+}
+
+// changeTable returns the table affected by the given change, if it can be resolved.
+func changeTable(c schema.Change) *schema.Table {
+	switch c := c.(type) {
+	case *schema.ModifyTable:
+		return c.T
+	case *schema.AddIndex:
+		return c.I.Table
+	case *schema.ModifyIndex:
+		return c.To.Table
+	case *schema.AddForeignKey:
+		return c.F.Table
+	case *schema.ModifyForeignKey:
+		return c.To.Table
+	case *schema.AddPrimaryKey:
+		return c.P.Table
+	case *schema.ModifyPrimaryKey:
+		return c.To.Table
+	}
+	return nil
+}
+
+type (
+	// Estimator estimates the duration of planned statements based on their class and
+	// the number of rows in the affected tables. The per-row durations are rough defaults
+	// and can be calibrated using the actual durations reported by previous executions.
+	Estimator struct {
+		Base       time.Duration // Base duration of every statement.
+		RowScan    time.Duration // Duration to scan a single row.
+		RowRewrite time.Duration // Duration to rewrite a single row.
+	}
+
+	// Estimate describes the estimated cost of executing a planned statement.
+	Estimate struct {
+		Class    StmtClass     // Class of the statement.
+		Rows     int64         // Number of rows in the affected table.
+		Duration time.Duration // Estimated execution duration.
+	}
+)
+
+// DefaultEstimator is the Estimator used by default.
+var DefaultEstimator = &Estimator{
+	Base:       10 * time.Millisecond,
+	RowScan:    500 * time.Nanosecond,
+	RowRewrite: 2 * time.Microsecond,
+}
+
+// Estimate estimates the execution cost of each change in the given plan. Table
+// sizes are read from the database, in case the driver implements TableSizer.
+func (e *Estimator) Estimate(ctx context.Context, drv Driver, plan *Plan) ([]*Estimate, error) {
+	var (
+		tables []*schema.Table
+		seen   = make(map[*schema.Table]bool)
+		ests   = make([]*Estimate, len(plan.Changes))
+	)
+	for i, c := range plan.Changes {
+		ests[i] = &Estimate{Class: ClassifyChange(c.Source), Duration: e.Base}
+		if t := changeTable(c.Source); t != nil && ests[i].Class != StmtClassMetadata && !seen[t] {
+			seen[t] = true
+			tables = append(tables, t)
+		}
+	}
+	s, ok := drv.(TableSizer)
+	if !ok || len(tables) == 0 {
+		return ests, nil
+	}
+	sizes, err := s.TableSizes(ctx, tables)
+	if err != nil {
+		return nil, fmt.Errorf("sql/migrate: read table sizes: %w", err)
+	}
+	for i, c := range plan.Changes {
+		t := changeTable(c.Source)
+		if t == nil {
+			continue
+		}
+		switch est := ests[i]; est.Class {
+		case StmtClassScan:
+			est.Rows = sizes[t]
+			est.Duration += time.Duration(est.Rows) * e.RowScan
+		case StmtClassRewrite:
+			est.Rows = sizes[t]
+			est.Duration += time.Duration(est.Rows) * e.RowRewrite
+		}
+	}
+	return ests, nil
+}
+
+// ProtectedError is returned by CheckProtected in case a change drops or
+// modifies a resource that is protected by one of the given patterns.
+type ProtectedError struct {
+	Change  schema.Change // protected change
+	Action  string        // drop, modify or rename
+	Type    string        // schema or table
+	Name    string        // qualified resource name
+	Pattern string        // matched pattern
+}
+
+func (e *ProtectedError) Error() string {
+	return fmt.Sprintf("sql/migrate: cannot %s protected %s %q (matched pattern %q)", e.Action, e.Type, e.Name, e.Pattern)
+}
+
+// CheckProtected returns a ProtectedError in case one of the given changes drops
+// or modifies a schema or a table that matches one of the protection patterns.
+// A pattern is either a schema glob (e.g. "audit"), that protects the schema and
+// all of its tables, or a table glob qualified with its schema (e.g. "public.audit_*").
+func CheckProtected(changes []schema.Change, patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	globs := make([][]string, len(patterns))
+	for i, p := range patterns {
+		if globs[i] = strings.Split(p, "."); len(globs[i]) > 2 {
+			return fmt.Errorf("sql/migrate: too many parts in protect pattern: %q", p)
+		}
+	}
+	// match returns the pattern that matches the given schema and table, if exists.
+	match := func(s, t string) (string, error) {
+		for i, g := range globs {
+			// Tables of unnamed schemas (e.g., schema-scoped
+			// connections) are matched only by their names.
+			if s != "" {
+				switch ok, err := filepath.Match(g[0], s); {
+				case err != nil:
+					return "", err
+				case !ok:
+					continue
+				}
+			}
+			switch {
+			// Schema globs protect the schema and all of its tables.
+			case len(g) == 1 && s != "":
+				return patterns[i], nil
+			case len(g) == 2 && t != "":
+				switch ok, err := filepath.Match(g[1], t); {
+				case err != nil:
+					return "", err
+				case ok:
+					return patterns[i], nil
+				}
+			}
+		}
+		return "", nil
+	}
+	check := func(c schema.Change, action, typ string, s *schema.Schema, t string) error {
+		var sn string
+		if s != nil {
+			sn = s.Name
+		}
+		switch p, err := match(sn, t); {
+		case err != nil:
+			return err
+		case p != "":
+			name := sn
+			if t != "" && sn != "" {
+				name = sn + "." + t
+			} else if t != "" {
+				name = t
+			}
+			return &ProtectedError{Change: c, Action: action, Type: typ, Name: name, Pattern: p}
+		}
+		return nil
+	}
+	for _, c := range changes {
+		var err error
+		switch c := c.(type) {
+		case *schema.DropSchema:
+			if err = check(c, "drop", "schema", c.S, ""); err == nil {
+				for _, t := range c.S.Tables {
+					if err = check(c, "drop", "table", c.S, t.Name); err != nil {
+						break
+					}
+				}
+			}
+		case *schema.ModifySchema:
+			err = check(c, "modify", "schema", c.S, "")
+		case *schema.DropTable:
+			err = check(c, "drop", "table", c.T.Schema, c.T.Name)
+		case *schema.ModifyTable:
+			err = check(c, "modify", "table", c.T.Schema, c.T.Name)
+		case *schema.RenameTable:
+			err = check(c, "rename", "table", c.From.Schema, c.From.Name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // NopRevisionReadWriter is a RevisionReadWriter that does nothing.
 // It is useful for one-time replay of the migration directory.
 type NopRevisionReadWriter struct{}
@@ -1213,6 +1923,13 @@ type (
 	// LogDone is sent if the execution is done.
 	LogDone struct{}
 
+	// LogWarning is sent if a statement failed with an error that
+	// was tolerated, and the execution continued. See WithTolerateErrors.
+	LogWarning struct {
+		SQL   string // SQL statement.
+		Error error  // Tolerated error.
+	}
+
 	// LogError is sent if there is an error while execution.
 	LogError struct {
 		SQL   string // Set, if Error was caused by a SQL statement.
@@ -1250,6 +1967,7 @@ func (LogChecks) logEntry()     {}
 func (LogChecksDone) logEntry() {}
 func (LogDone) logEntry()       {}
 func (LogError) logEntry()      {}
+func (LogWarning) logEntry()    {}
 
 // Log implements the Logger interface.
 func (NopLogger) Log(LogEntry) {}
@@ -224,6 +224,56 @@ func TestPlanner_CheckpointSchema(t *testing.T) {
 	require.Equal(t, &migrate.Plan{Name: "empty"}, plan)
 }
 
+func TestExecutor_ReplayCache(t *testing.T) {
+	ctx := context.Background()
+	d, err := migrate.NewLocalDir(filepath.FromSlash("testdata/migrate/sub"))
+	require.NoError(t, err)
+	drv := &mockDriver{realm: schema.Realm{Schemas: []*schema.Schema{{Name: "main"}}}}
+	ex, err := migrate.NewExecutor(drv, d, migrate.NopRevisionReadWriter{})
+	require.NoError(t, err)
+
+	// Cache miss.
+	c := &mockReplayCache{states: make(map[string]*schema.Realm)}
+	r1, err := ex.Replay(ctx, migrate.RealmConn(drv, nil), migrate.ReplayWithCache(c))
+	require.NoError(t, err)
+	require.NotEmpty(t, drv.executed)
+	sum, err := d.Checksum()
+	require.NoError(t, err)
+	require.Equal(t, map[string]*schema.Realm{"h1:" + sum.Sum(): r1}, c.states)
+
+	// Cache hit skips replay.
+	drv.executed = nil
+	r2, err := ex.Replay(ctx, migrate.RealmConn(drv, nil), migrate.ReplayWithCache(c))
+	require.NoError(t, err)
+	require.Same(t, r1, r2)
+	require.Empty(t, drv.executed)
+
+	// Versions are cached separately.
+	_, err = ex.Replay(ctx, migrate.RealmConn(drv, nil), migrate.ReplayWithCache(c), migrate.ReplayToVersion("1.a"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"CREATE TABLE t_sub(c int);", "ALTER TABLE t_sub ADD c1 int;"}, drv.executed)
+	require.Contains(t, c.states, "h1:"+sum.Sum()+"@1.a")
+
+	// Cache errors are reported.
+	c.err = errors.New("oops")
+	_, err = ex.Replay(ctx, migrate.RealmConn(drv, nil), migrate.ReplayWithCache(c))
+	require.EqualError(t, err, "sql/migrate: load replayed state from cache: oops")
+}
+
+type mockReplayCache struct {
+	states map[string]*schema.Realm
+	err    error
+}
+
+func (c *mockReplayCache) Load(_ context.Context, key string) (*schema.Realm, error) {
+	return c.states[key], c.err
+}
+
+func (c *mockReplayCache) Store(_ context.Context, key string, r *schema.Realm) error {
+	c.states[key] = r
+	return c.err
+}
+
 func TestExecutor_Replay(t *testing.T) {
 	ctx := context.Background()
 	d, err := migrate.NewLocalDir(filepath.FromSlash("testdata/migrate"))
@@ -770,6 +820,198 @@ func TestExecutor(t *testing.T) {
 	require.Empty(t, (*rrw)[0].ErrorStmt)
 }
 
+func TestCheckProtected(t *testing.T) {
+	var (
+		public = schema.New("public")
+		audit  = schema.New("audit")
+		logs   = schema.NewTable("audit_log").SetSchema(public)
+		users  = schema.NewTable("users").SetSchema(public)
+	)
+	audit.AddTables(schema.NewTable("events"))
+	require.NoError(t, migrate.CheckProtected([]schema.Change{&schema.DropTable{T: logs}}, nil))
+	require.NoError(t, migrate.CheckProtected([]schema.Change{&schema.DropTable{T: users}, &schema.AddTable{T: logs}}, []string{"public.audit_*"}))
+
+	err := migrate.CheckProtected([]schema.Change{&schema.DropTable{T: users}, &schema.ModifyTable{T: logs}}, []string{"public.audit_*"})
+	require.EqualError(t, err, `sql/migrate: cannot modify protected table "public.audit_log" (matched pattern "public.audit_*")`)
+	var perr *migrate.ProtectedError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "public.audit_log", perr.Name)
+
+	err = migrate.CheckProtected([]schema.Change{&schema.RenameTable{From: users, To: logs}}, []string{"audit", "*.users"})
+	require.EqualError(t, err, `sql/migrate: cannot rename protected table "public.users" (matched pattern "*.users")`)
+	err = migrate.CheckProtected([]schema.Change{&schema.ModifyTable{T: audit.Tables[0]}}, []string{"audit"})
+	require.EqualError(t, err, `sql/migrate: cannot modify protected table "audit.events" (matched pattern "audit")`)
+	err = migrate.CheckProtected([]schema.Change{&schema.DropSchema{S: audit}}, []string{"audit.events"})
+	require.EqualError(t, err, `sql/migrate: cannot drop protected table "audit.events" (matched pattern "audit.events")`)
+	err = migrate.CheckProtected([]schema.Change{&schema.DropSchema{S: audit}}, []string{"a*"})
+	require.EqualError(t, err, `sql/migrate: cannot drop protected schema "audit" (matched pattern "a*")`)
+
+	// Unqualified tables are matched by their names.
+	err = migrate.CheckProtected([]schema.Change{&schema.DropTable{T: schema.NewTable("audit_log")}}, []string{"public.audit_*"})
+	require.EqualError(t, err, `sql/migrate: cannot drop protected table "audit_log" (matched pattern "public.audit_*")`)
+
+	err = migrate.CheckProtected([]schema.Change{&schema.DropTable{T: logs}}, []string{"a.b.c"})
+	require.EqualError(t, err, `sql/migrate: too many parts in protect pattern: "a.b.c"`)
+}
+
+func TestPlan_Stages(t *testing.T) {
+	var (
+		c1 = &migrate.Change{Cmd: "CREATE TABLE t1 (id int)"}
+		c2 = &migrate.Change{Cmd: "CREATE TABLE t2 (id int)"}
+		c3 = &migrate.Change{Cmd: "CREATE INDEX CONCURRENTLY i1 ON t1 (id)", NoTx: true}
+		c4 = &migrate.Change{Cmd: "CREATE INDEX CONCURRENTLY i2 ON t2 (id)", NoTx: true}
+		c5 = &migrate.Change{Cmd: "DROP TABLE t3"}
+	)
+	require.Empty(t, (&migrate.Plan{Transactional: true}).Stages())
+	require.Equal(t, []*migrate.Stage{
+		{Changes: []*migrate.Change{c1, c2}, Transactional: true},
+	}, (&migrate.Plan{Transactional: true, Changes: []*migrate.Change{c1, c2}}).Stages())
+	require.Equal(t, []*migrate.Stage{
+		{Changes: []*migrate.Change{c1, c2}, Transactional: true},
+		{Changes: []*migrate.Change{c3}},
+		{Changes: []*migrate.Change{c4}},
+		{Changes: []*migrate.Change{c5}, Transactional: true},
+	}, (&migrate.Plan{Transactional: true, Changes: []*migrate.Change{c1, c2, c3, c4, c5}}).Stages())
+	require.Equal(t, []*migrate.Stage{
+		{Changes: []*migrate.Change{c1, c3, c5}},
+	}, (&migrate.Plan{Changes: []*migrate.Change{c1, c3, c5}}).Stages())
+}
+
+func TestChangedTables(t *testing.T) {
+	var (
+		t1 = schema.NewTable("t1")
+		t2 = schema.NewTable("t2")
+		t3 = schema.NewTable("t3")
+		t4 = schema.NewTable("t4")
+	)
+	tables := migrate.ChangedTables([]schema.Change{
+		&schema.AddTable{T: t1},
+		&schema.ModifyTable{T: t2},
+		&schema.DropTable{T: t3},
+		&schema.RenameTable{From: t3, To: t4},
+		&schema.ModifyTable{T: t1},
+	})
+	require.Equal(t, []*schema.Table{t1, t2, t4}, tables)
+	require.Empty(t, migrate.ChangedTables(nil))
+}
+
+func TestRequiredPrivileges(t *testing.T) {
+	var (
+		app    = schema.New("app")
+		logs   = schema.New("logs")
+		users  = schema.NewTable("users")
+		pets   = schema.NewTable("pets")
+		events = schema.NewTable("events")
+		v      = schema.NewView("active", "SELECT 1")
+	)
+	app.AddTables(users, pets).AddViews(v)
+	logs.AddTables(events)
+	privs := migrate.RequiredPrivileges([]schema.Change{
+		&schema.AddSchema{S: logs},
+		&schema.AddTable{T: events},
+		&schema.ModifyTable{T: users},
+		&schema.ModifyTable{T: users},
+		&schema.DropTable{T: pets},
+		&schema.AddTable{T: schema.NewTable("tags")},
+		&schema.ModifyView{From: v, To: v},
+		&schema.AddTrigger{T: &schema.Trigger{Name: "t", Table: pets}},
+		&schema.DropSchema{S: schema.New("old")},
+	})
+	require.Equal(t, []*migrate.Privilege{
+		{Action: migrate.PrivilegeCreate, Type: "schema", Schema: "logs"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Schema: "app", Name: "users"},
+		{Action: migrate.PrivilegeDrop, Type: "table", Schema: "app", Name: "pets"},
+		{Action: migrate.PrivilegeCreate, Type: "table", Name: "tags"},
+		{Action: migrate.PrivilegeAlter, Type: "view", Schema: "app", Name: "active"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Schema: "app", Name: "pets"},
+		{Action: migrate.PrivilegeDrop, Type: "schema", Schema: "old"},
+	}, privs)
+	require.Equal(t, `CREATE on schema "logs"`, privs[0].String())
+	require.Equal(t, `ALTER on table "app"."users"`, privs[1].String())
+	require.Equal(t, `CREATE on table "tags"`, privs[3].String())
+	require.Empty(t, migrate.RequiredPrivileges(nil))
+}
+
+func TestClassifyChange(t *testing.T) {
+	var (
+		t1  = schema.NewTable("t1")
+		c1  = schema.NewIntColumn("c1", "int")
+		c2  = schema.NewNullIntColumn("c2", "int")
+		idx = schema.NewIndex("i").AddColumns(c1)
+	)
+	for _, tt := range []struct {
+		change schema.Change
+		class  migrate.StmtClass
+	}{
+		{&schema.AddTable{T: t1}, migrate.StmtClassMetadata},
+		{&schema.DropTable{T: t1}, migrate.StmtClassMetadata},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddColumn{C: c2}}}, migrate.StmtClassMetadata},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c2, To: c2, Change: schema.ChangeComment}}}, migrate.StmtClassMetadata},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddIndex{I: idx}}}, migrate.StmtClassScan},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c2, To: c1, Change: schema.ChangeNull}}}, migrate.StmtClassScan},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c1, To: c2, Change: schema.ChangeNull}}}, migrate.StmtClassMetadata},
+		{&schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddIndex{I: idx}, &schema.ModifyColumn{From: c1, To: c1, Change: schema.ChangeType}}}, migrate.StmtClassRewrite},
+		{&schema.AddIndex{I: idx}, migrate.StmtClassScan},
+		{&schema.AddColumn{C: c1}, migrate.StmtClassMetadata},
+	} {
+		require.Equal(t, tt.class, migrate.ClassifyChange(tt.change))
+	}
+	b, err := migrate.StmtClassRewrite.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "rewrite", string(b))
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	var (
+		t1   = schema.NewTable("t1")
+		t2   = schema.NewTable("t2")
+		c1   = schema.NewIntColumn("c1", "int")
+		e    = &migrate.Estimator{Base: time.Millisecond, RowScan: time.Microsecond, RowRewrite: 10 * time.Microsecond}
+		plan = &migrate.Plan{
+			Changes: []*migrate.Change{
+				{Cmd: "CREATE TABLE t2", Source: &schema.AddTable{T: t2}},
+				{Cmd: "CREATE INDEX i ON t1", Source: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.AddIndex{I: schema.NewIndex("i")}}}},
+				{Cmd: "ALTER TABLE t1", Source: &schema.ModifyTable{T: t1, Changes: []schema.Change{&schema.ModifyColumn{From: c1, To: c1, Change: schema.ChangeType}}}},
+				{Cmd: "CREATE INDEX j ON t1", Source: &schema.AddIndex{I: schema.NewIndex("j").SetTable(t1)}},
+				{Cmd: "SELECT 1"},
+			},
+		}
+	)
+	// Drivers without size information.
+	ests, err := e.Estimate(context.Background(), &mockDriver{}, plan)
+	require.NoError(t, err)
+	require.Equal(t, []*migrate.Estimate{
+		{Class: migrate.StmtClassMetadata, Duration: time.Millisecond},
+		{Class: migrate.StmtClassScan, Duration: time.Millisecond},
+		{Class: migrate.StmtClassRewrite, Duration: time.Millisecond},
+		{Class: migrate.StmtClassScan, Duration: time.Millisecond},
+		{Class: migrate.StmtClassMetadata, Duration: time.Millisecond},
+	}, ests)
+
+	drv := &mockTableSizer{sizes: map[*schema.Table]int64{t1: 1000}}
+	ests, err = e.Estimate(context.Background(), drv, plan)
+	require.NoError(t, err)
+	require.Equal(t, []*schema.Table{t1}, drv.tables)
+	require.Equal(t, []*migrate.Estimate{
+		{Class: migrate.StmtClassMetadata, Duration: time.Millisecond},
+		{Class: migrate.StmtClassScan, Rows: 1000, Duration: 2 * time.Millisecond},
+		{Class: migrate.StmtClassRewrite, Rows: 1000, Duration: 11 * time.Millisecond},
+		{Class: migrate.StmtClassScan, Rows: 1000, Duration: 2 * time.Millisecond},
+		{Class: migrate.StmtClassMetadata, Duration: time.Millisecond},
+	}, ests)
+}
+
+type mockTableSizer struct {
+	mockDriver
+	tables []*schema.Table
+	sizes  map[*schema.Table]int64
+}
+
+func (m *mockTableSizer) TableSizes(_ context.Context, tables []*schema.Table) (map[*schema.Table]int64, error) {
+	m.tables = tables
+	return m.sizes, nil
+}
+
 func TestExecutor_Baseline(t *testing.T) {
 	var (
 		rrw mockRevisionReadWriter
@@ -815,6 +1057,169 @@ func TestExecutor_Baseline(t *testing.T) {
 	require.Equal(t, migrate.RevisionTypeBaseline, rrw[0].Type)
 }
 
+func TestExecutor_StmtExecutor(t *testing.T) {
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+		stx = &mockStmtExecutor{err: migrate.ErrStmtPending}
+	)
+	dir, err := migrate.NewLocalDir(filepath.Join("testdata/migrate", "sub"))
+	require.NoError(t, err)
+	ex, err := migrate.NewExecutor(drv, dir, &rrw, migrate.WithStmtExecutor(stx))
+	require.NoError(t, err)
+
+	// The first statement is proxied and the revision is recorded as pending.
+	err = ex.ExecuteN(context.Background(), 1)
+	var pe *migrate.ExecPendingError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "1.a", pe.Version)
+	require.Equal(t, "CREATE TABLE t_sub(c int);", pe.Stmt)
+	require.Empty(t, drv.executed)
+	require.Equal(t, []string{"CREATE TABLE t_sub(c int);"}, stx.executed)
+	require.Len(t, rrw, 1)
+	require.Equal(t, 0, rrw[0].Applied)
+	require.Equal(t, 2, rrw[0].Total)
+	require.Equal(t, migrate.ErrStmtPending.Error(), rrw[0].Error)
+
+	// Pending statements are not submitted twice.
+	err = ex.ExecuteN(context.Background(), 1)
+	require.ErrorAs(t, err, &pe)
+	require.Len(t, stx.executed, 1)
+
+	// Once resolved, the pending statement is recorded as applied and the next one is proxied.
+	rrw[0].Type |= migrate.RevisionTypeResolved
+	err = ex.ExecuteN(context.Background(), 1)
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "ALTER TABLE t_sub ADD c1 int;", pe.Stmt)
+	require.Equal(t, []string{"CREATE TABLE t_sub(c int);", "ALTER TABLE t_sub ADD c1 int;"}, stx.executed)
+	require.Equal(t, 1, rrw[0].Applied)
+	require.Equal(t, migrate.RevisionTypeExecute, rrw[0].Type)
+	require.Equal(t, migrate.ErrStmtPending.Error(), rrw[0].Error)
+
+	// Resolving the last pending statement marks the file as applied.
+	rrw[0].Type |= migrate.RevisionTypeResolved
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Len(t, stx.executed, 2)
+	require.Equal(t, 2, rrw[0].Applied)
+	require.Empty(t, rrw[0].Error)
+	require.Empty(t, rrw[0].PartialHashes)
+
+	// Statements executed by the proxy are recorded as applied.
+	rrw, stx.err, stx.executed = mockRevisionReadWriter{}, nil, nil
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Empty(t, drv.executed)
+	require.Len(t, stx.executed, 2)
+	require.Len(t, rrw, 1)
+	require.Equal(t, 2, rrw[0].Applied)
+	require.Empty(t, rrw[0].Error)
+}
+
+func TestExecutor_TolerateErrors(t *testing.T) {
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+		log = &mockLogger{}
+		mem = &migrate.MemDir{}
+	)
+	require.NoError(t, mem.WriteFile("1_init.sql", []byte("CREATE TABLE t1(c int);\nCREATE INDEX i ON t1(c);\nCREATE TABLE t2(c int);\n")))
+	require.NoError(t, mem.WriteFile("2_next.sql", []byte("-- atlas:tolerate duplicate key name\n\nCREATE INDEX i ON t1(c);\n-- atlas:tolerate 42P07\nCREATE TABLE t2(c int);\n")))
+	sum, err := mem.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(mem, sum))
+
+	// Errors are not tolerated by default.
+	ex, err := migrate.NewExecutor(drv, mem, &rrw, migrate.WithLogger(log))
+	require.NoError(t, err)
+	drv.failOn(2, errors.New("Error 1061 (42000): Duplicate key name 'i'"))
+	require.ErrorContains(t, ex.ExecuteN(context.Background(), 1), "Duplicate key name")
+	require.Equal(t, 1, rrw[0].Applied)
+
+	// Errors matched by the executor patterns are logged as warnings.
+	rrw, *drv, *log = mockRevisionReadWriter{}, mockDriver{}, mockLogger{}
+	ex, err = migrate.NewExecutor(drv, mem, &rrw, migrate.WithLogger(log), migrate.WithTolerateErrors("1061"))
+	require.NoError(t, err)
+	drv.failOn(2, errors.New("Error 1061 (42000): Duplicate key name 'i'"))
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE TABLE t2(c int);"}, drv.executed)
+	require.Equal(t, 3, rrw[0].Applied)
+	require.Empty(t, rrw[0].Error)
+	require.Contains(t, *log, migrate.LogWarning{SQL: "CREATE INDEX i ON t1(c);", Error: drv.failWith})
+
+	// Errors matched by the file and statement directives.
+	*drv, *log = mockDriver{}, mockLogger{}
+	ex, err = migrate.NewExecutor(drv, mem, &rrw, migrate.WithLogger(log))
+	require.NoError(t, err)
+	drv.failOn(1, errors.New("Error 1061 (42000): Duplicate key name 'i'"))
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, 2, rrw[1].Applied)
+	require.Contains(t, *log, migrate.LogWarning{SQL: "CREATE INDEX i ON t1(c);", Error: drv.failWith})
+
+	rrw, *drv = rrw[:1], mockDriver{}
+	drv.failOn(2, &sqlStateError{code: "42P07"})
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{"CREATE INDEX i ON t1(c);"}, drv.executed)
+	require.Equal(t, 2, rrw[1].Applied)
+}
+
+type sqlStateError struct{ code string }
+
+func (e *sqlStateError) Error() string    { return "relation already exists" }
+func (e *sqlStateError) SQLState() string { return e.code }
+
+func TestExecutor_StmtExecutorPending(t *testing.T) {
+	var (
+		rrw mockRevisionReadWriter
+		drv = &mockDriver{}
+		mem = &migrate.MemDir{}
+		stx = &mockStmtExecutor{pending: "CREATE INDEX i ON t1(c);"}
+	)
+	require.NoError(t, mem.WriteFile("1_init.sql", []byte("CREATE TABLE t1(c int);\nCREATE INDEX i ON t1(c);\nCREATE TABLE t2(c int);\n")))
+	sum, err := mem.Checksum()
+	require.NoError(t, err)
+	require.NoError(t, migrate.WriteSumFile(mem, sum))
+	ex, err := migrate.NewExecutor(drv, mem, &rrw, migrate.WithStmtExecutor(stx))
+	require.NoError(t, err)
+
+	// Statements that follow a pending statement are not executed.
+	err = ex.ExecuteN(context.Background(), 1)
+	var pe *migrate.ExecPendingError
+	require.ErrorAs(t, err, &pe)
+	require.Equal(t, "CREATE INDEX i ON t1(c);", pe.Stmt)
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE INDEX i ON t1(c);"}, stx.executed)
+	require.Len(t, rrw, 1)
+	require.Equal(t, 1, rrw[0].Applied)
+	require.Len(t, rrw[0].PartialHashes, 1)
+	require.Equal(t, "CREATE INDEX i ON t1(c);", rrw[0].ErrorStmt)
+
+	// Re-running does not execute any statement.
+	require.ErrorAs(t, ex.ExecuteN(context.Background(), 1), &pe)
+	require.Len(t, stx.executed, 2)
+
+	// Once resolved, only the statements that follow the pending one are executed.
+	rrw[0].Type |= migrate.RevisionTypeResolved
+	require.NoError(t, ex.ExecuteN(context.Background(), 1))
+	require.Equal(t, []string{"CREATE TABLE t1(c int);", "CREATE INDEX i ON t1(c);", "CREATE TABLE t2(c int);"}, stx.executed)
+	require.Equal(t, 3, rrw[0].Applied)
+	require.Empty(t, rrw[0].Error)
+	require.Empty(t, rrw[0].ErrorStmt)
+	require.ErrorIs(t, ex.ExecuteN(context.Background(), 1), migrate.ErrNoPendingFiles)
+	require.Len(t, stx.executed, 3)
+}
+
+type mockStmtExecutor struct {
+	err      error
+	pending  string // statement to report as pending.
+	executed []string
+}
+
+func (m *mockStmtExecutor) ExecStmt(_ context.Context, _ migrate.File, stmt *migrate.Stmt) error {
+	m.executed = append(m.executed, stmt.Text)
+	if m.pending != "" && stmt.Text == m.pending {
+		return migrate.ErrStmtPending
+	}
+	return m.err
+}
+
 type (
 	mockDriver struct {
 		migrate.Driver
@@ -0,0 +1,103 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package plan_test
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/migrate/plan"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/sqlite"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRealms(t *testing.T) {
+	drv, err := sqlite.Open(nil)
+	require.NoError(t, err)
+	current := schema.NewRealm(schema.New("main"))
+	desired := schema.NewRealm(schema.New("main").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+	))
+	p, err := plan.Realms(context.Background(), drv, current, desired, plan.WithName("add_users"))
+	require.NoError(t, err)
+	require.Equal(t, "add_users", p.Name)
+	require.Len(t, p.Changes, 1)
+	require.Equal(t, "CREATE TABLE `users` (`id` int NOT NULL)", p.Changes[0].Cmd)
+
+	_, err = plan.Realms(context.Background(), drv, current, current)
+	require.ErrorIs(t, err, migrate.ErrNoPlan)
+
+	_, err = plan.Realms(context.Background(), drv, desired, current, plan.WithProtect("main.users"))
+	require.EqualError(t, err, `sql/migrate: cannot drop protected table "main.users" (matched pattern "main.users")`)
+}
+
+func TestHCL(t *testing.T) {
+	drv, err := sqlite.Open(nil)
+	require.NoError(t, err)
+	var (
+		dir    = t.TempDir()
+		client = &sqlclient.Client{
+			Name: sqlite.DriverName,
+			URL:  &sqlclient.URL{URL: &url.URL{Scheme: "sqlite"}, Schema: "main"},
+			Driver: &inspector{
+				Driver: drv,
+				realm: schema.NewRealm(schema.New("main").AddTables(
+					schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+				)),
+			},
+			Evaluator: sqlite.EvalHCL,
+		}
+	)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "schema.hcl"), []byte(`
+schema "main" {}
+
+table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+  }
+  column "name" {
+    type = text
+    null = true
+  }
+}
+`), 0644))
+	p, err := plan.HCL(context.Background(), client, []string{dir})
+	require.NoError(t, err)
+	require.Len(t, p.Changes, 1)
+	require.Equal(t, "ALTER TABLE `users` ADD COLUMN `name` text NULL", p.Changes[0].Cmd)
+
+	client.URL.Schema = "other"
+	_, err = plan.HCL(context.Background(), client, []string{dir})
+	require.EqualError(t, err, `sql/migrate/plan: mismatched HCL and database schemas: "other" <> "main"`)
+
+	_, err = plan.HCL(context.Background(), client, []string{t.TempDir()})
+	require.EqualError(t, err, "sql/migrate/plan: no schema files were found")
+}
+
+// inspector returns a static realm on inspection.
+type inspector struct {
+	migrate.Driver
+	realm *schema.Realm
+}
+
+func (i *inspector) InspectSchema(_ context.Context, name string, _ *schema.InspectOptions) (*schema.Schema, error) {
+	s, ok := i.realm.Schema(name)
+	if !ok {
+		return nil, &schema.NotExistError{}
+	}
+	return s, nil
+}
+
+func (i *inspector) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
+	return i.realm, nil
+}
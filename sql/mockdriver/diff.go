@@ -0,0 +1,85 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mockdriver
+
+import (
+	"reflect"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// A diff provides a dialect-agnostic implementation for sqlx.DiffDriver.
+type diff struct{}
+
+// RealmObjectDiff returns a changeset for migrating realm (database) objects
+// from one state to the other. For example, adding extensions or users.
+func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	return nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (*diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	return sqlx.CheckDiffMode(from, to, opts.Mode), nil
+}
+
+// ViewAttrChanges returns the changes between the two view attributes.
+func (*diff) ViewAttrChanges(_, _ *schema.View) []schema.Change {
+	return nil
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (*diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.DiffOptions) (schema.Change, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	if from.Type.Null != to.Type.Null {
+		change |= schema.ChangeNull
+	}
+	if !reflect.DeepEqual(from.Type.Type, to.Type.Type) {
+		change |= schema.ChangeType
+	}
+	if !reflect.DeepEqual(from.Default, to.Default) {
+		change |= schema.ChangeDefault
+	}
+	if change.Is(schema.NoChange) {
+		return sqlx.NoChange, nil
+	}
+	return &schema.ModifyColumn{Change: change, From: from, To: to}, nil
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(_, _ []schema.Attr) bool {
+	return false
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false
+}
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, _ *schema.Index) bool {
+	return false
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(from, to schema.ReferenceOption) bool {
+	return from != to
+}
+
+// ForeignKeyAttrChanged reports if any of the foreign-key attributes were changed.
+func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
+	return false
+}
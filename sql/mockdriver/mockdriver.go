@@ -0,0 +1,392 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package mockdriver provides a programmable migrate.Driver for testing applications that
+// embed Atlas. A Driver is registered by name, and can be opened by the sqlclient package
+// using the "mock://<name>" URL. Its inspection results, statements execution and locking
+// behavior are controlled by the test, which allows covering error paths such as lock
+// timeouts or partially applied changes without a real database.
+//
+//	drv := mockdriver.New(schema.NewRealm(schema.New("public")))
+//	drv.ExecFn = mockdriver.FailAt(2, errors.New("lock wait timeout exceeded"))
+//	u := mockdriver.Register(t.Name(), drv)
+//	defer mockdriver.Unregister(t.Name())
+//	client, err := sqlclient.Open(ctx, u)
+package mockdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+)
+
+// DriverName holds the name used for registration.
+const DriverName = "mock"
+
+type (
+	// Driver is a programmable migrate.Driver. Its exported fields can be
+	// set by tests to control the driver behavior, but should not be changed
+	// while the driver is in use.
+	Driver struct {
+		schema.Differ
+
+		// Realm holds the state returned by inspection. Note that applying
+		// changes does not modify it, and tests that require the state to
+		// be changed after execution should set it manually.
+		Realm *schema.Realm
+
+		// InspectErr, if not nil, is returned by all inspection calls.
+		InspectErr error
+
+		// ExecFn, if set, is called before executing any statement. An error returned
+		// by it fails the execution, and the statement is not recorded as executed.
+		ExecFn func(ctx context.Context, query string, args ...any) error
+
+		// LockFn, if set, overrides the default locking behavior. By default,
+		// named locks are held in memory and a schema.ErrLocked is returned
+		// in case the lock is already taken by another session.
+		LockFn func(ctx context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error)
+
+		mu    sync.Mutex
+		stmts []string
+		locks map[string]bool
+	}
+
+	// result implements the sql.Result and
+	// the driver.Result interfaces.
+	result struct{}
+)
+
+// New returns a new Driver with the given realm as its inspected state.
+func New(r *schema.Realm) *Driver {
+	if r == nil {
+		r = schema.NewRealm()
+	}
+	return &Driver{
+		Differ: &sqlx.Diff{DiffDriver: &diff{}},
+		Realm:  r,
+		locks:  make(map[string]bool),
+	}
+}
+
+// Stmts returns the statements that were executed successfully on the driver.
+func (d *Driver) Stmts() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.stmts...)
+}
+
+// FailAt returns an ExecFn that fails the n-th (1-based) executed statement with the given error.
+// Statements executed before it succeed, which is useful for testing partially applied changes.
+func FailAt(n int, err error) func(context.Context, string, ...any) error {
+	var (
+		mu sync.Mutex
+		i  int
+	)
+	return func(context.Context, string, ...any) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if i++; i == n {
+			return err
+		}
+		return nil
+	}
+}
+
+// ExecContext records the statement as executed, unless ExecFn returns an error.
+func (d *Driver) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	if d.ExecFn != nil {
+		if err := d.ExecFn(ctx, query, args...); err != nil {
+			return nil, err
+		}
+	}
+	d.mu.Lock()
+	d.stmts = append(d.stmts, query)
+	d.mu.Unlock()
+	return result{}, nil
+}
+
+// QueryContext is not supported by the mock driver.
+func (*Driver) QueryContext(_ context.Context, query string, _ ...any) (*sql.Rows, error) {
+	return nil, fmt.Errorf("mockdriver: querying is not supported: %q", query)
+}
+
+// InspectRealm returns the configured realm, filtered by the given options.
+func (d *Driver) InspectRealm(_ context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	if d.InspectErr != nil {
+		return nil, d.InspectErr
+	}
+	if opts == nil || len(opts.Schemas) == 0 {
+		return d.Realm, nil
+	}
+	r := &schema.Realm{Attrs: d.Realm.Attrs, Objects: d.Realm.Objects}
+	for _, s := range d.Realm.Schemas {
+		for _, name := range opts.Schemas {
+			if s.Name == name {
+				r.Schemas = append(r.Schemas, s)
+			}
+		}
+	}
+	return r, nil
+}
+
+// InspectSchema returns the schema with the given name from the configured realm.
+// An empty name is allowed only if the realm holds exactly one schema.
+func (d *Driver) InspectSchema(_ context.Context, name string, _ *schema.InspectOptions) (*schema.Schema, error) {
+	if d.InspectErr != nil {
+		return nil, d.InspectErr
+	}
+	switch {
+	case name == "" && len(d.Realm.Schemas) == 1:
+		return d.Realm.Schemas[0], nil
+	case name == "":
+		return nil, fmt.Errorf("mockdriver: schema name is required for realm with %d schemas", len(d.Realm.Schemas))
+	}
+	s, ok := d.Realm.Schema(name)
+	if !ok {
+		return nil, &schema.NotExistError{Err: fmt.Errorf("mockdriver: schema %q was not found", name)}
+	}
+	return s, nil
+}
+
+// Lock implements the schema.Locker interface.
+func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error) {
+	if d.LockFn != nil {
+		return d.LockFn(ctx, name, timeout)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.locks[name] {
+		return nil, schema.ErrLocked
+	}
+	d.locks[name] = true
+	return func() error {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		delete(d.locks, name)
+		return nil
+	}, nil
+}
+
+// ApplyChanges plans and executes the given changes.
+func (d *Driver) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, d, opts...)
+}
+
+// PlanChanges returns a migration plan with one statement for each of the given changes.
+// The statements are not valid SQL of any dialect, but describe the changes they plan.
+func (d *Driver) PlanChanges(_ context.Context, name string, changes []schema.Change, _ ...migrate.PlanOption) (*migrate.Plan, error) {
+	plan := &migrate.Plan{
+		Name:          name,
+		Reversible:    true,
+		Transactional: true,
+	}
+	for _, c := range changes {
+		var cmd, reverse *sqlx.Builder
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			cmd, reverse = build("CREATE SCHEMA").Ident(c.S.Name), build("DROP SCHEMA").Ident(c.S.Name)
+		case *schema.DropSchema:
+			cmd, reverse = build("DROP SCHEMA").Ident(c.S.Name), build("CREATE SCHEMA").Ident(c.S.Name)
+		case *schema.ModifySchema:
+			cmd = build("ALTER SCHEMA").Ident(c.S.Name)
+		case *schema.AddTable:
+			cmd, reverse = build("CREATE TABLE").Table(c.T), build("DROP TABLE").Table(c.T)
+		case *schema.DropTable:
+			cmd, reverse = build("DROP TABLE").Table(c.T), build("CREATE TABLE").Table(c.T)
+		case *schema.ModifyTable:
+			cmd = build("ALTER TABLE").Table(c.T)
+		case *schema.RenameTable:
+			cmd, reverse = build("ALTER TABLE").Table(c.From).P("RENAME TO").Table(c.To), build("ALTER TABLE").Table(c.To).P("RENAME TO").Table(c.From)
+		case *schema.AddView:
+			cmd, reverse = build("CREATE VIEW").View(c.V), build("DROP VIEW").View(c.V)
+		case *schema.DropView:
+			cmd, reverse = build("DROP VIEW").View(c.V), build("CREATE VIEW").View(c.V)
+		case *schema.ModifyView:
+			cmd = build("ALTER VIEW").View(c.To)
+		default:
+			return nil, fmt.Errorf("mockdriver: unsupported change %T", c)
+		}
+		mc := &migrate.Change{Cmd: cmd.String(), Source: c}
+		if reverse != nil {
+			mc.Reverse = reverse.String()
+		} else {
+			plan.Reversible = false
+		}
+		plan.Changes = append(plan.Changes, mc)
+	}
+	return plan, nil
+}
+
+// LastInsertId implements the sql.Result interface.
+func (result) LastInsertId() (int64, error) { return 0, nil }
+
+// RowsAffected implements the sql.Result interface.
+func (result) RowsAffected() (int64, error) { return 0, nil }
+
+func build(phrases ...string) *sqlx.Builder {
+	b := &sqlx.Builder{QuoteOpening: '"', QuoteClosing: '"'}
+	return b.P(phrases...)
+}
+
+var (
+	// registered holds the drivers registered by name.
+	registered sync.Map
+	// opened maps opened databases and transactions to their drivers.
+	opened sync.Map
+)
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.OpenerFunc(opener),
+		sqlclient.RegisterDriverOpener(open),
+		sqlclient.RegisterTxOpener(openTx),
+		sqlclient.RegisterURLParser(sqlclient.URLParserFunc(parseURL)),
+	)
+}
+
+// Register registers the driver with the given name and returns
+// the URL for opening it using the sqlclient package.
+func Register(name string, d *Driver) string {
+	registered.Store(name, d)
+	return (&url.URL{Scheme: DriverName, Host: name}).String()
+}
+
+// Unregister removes the driver registered with the given name.
+func Unregister(name string) {
+	registered.Delete(name)
+}
+
+// opener opens the registered driver by the URL host.
+func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+	v, ok := registered.Load(u.Host)
+	if !ok {
+		return nil, fmt.Errorf("mockdriver: driver %q was not registered", u.Host)
+	}
+	drv := v.(*Driver)
+	c := &connector{drv: drv}
+	db := sql.OpenDB(c)
+	c.db = db
+	opened.Store(db, drv)
+	return &sqlclient.Client{
+		Name:   DriverName,
+		DB:     db,
+		URL:    parseURL(u),
+		Driver: drv,
+	}, nil
+}
+
+// open returns the driver attached to the given database or transaction.
+func open(c schema.ExecQuerier) (migrate.Driver, error) {
+	v, ok := opened.Load(c)
+	if !ok {
+		return nil, errors.New("mockdriver: connection was not opened by the mock driver")
+	}
+	return v.(*Driver), nil
+}
+
+// openTx opens a transaction and attaches it to the driver of the given database.
+func openTx(ctx context.Context, db *sql.DB, opts *sql.TxOptions) (*sqlclient.Tx, error) {
+	drv, ok := opened.Load(db)
+	if !ok {
+		return nil, errors.New("mockdriver: database was not opened by the mock driver")
+	}
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	stx := &sqlclient.Tx{Tx: tx}
+	stx.CommitFn = func() error {
+		opened.Delete(stx)
+		return tx.Commit()
+	}
+	stx.RollbackFn = func() error {
+		opened.Delete(stx)
+		return tx.Rollback()
+	}
+	opened.Store(stx, drv)
+	return stx, nil
+}
+
+// parseURL parses the "mock://<name>/<schema>" URL.
+func parseURL(u *url.URL) *sqlclient.URL {
+	return &sqlclient.URL{URL: u, DSN: u.String(), Schema: strings.TrimPrefix(u.Path, "/")}
+}
+
+type (
+	// connector implements the driver.Connector interface
+	// for attaching database connections to the mock driver.
+	connector struct {
+		drv *Driver
+		db  *sql.DB
+	}
+
+	// conn implements the driver.Conn interface and routes
+	// statements executions to the mock driver.
+	conn struct{ drv *Driver }
+)
+
+// Connect implements the driver.Connector interface.
+func (c *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{drv: c.drv}, nil
+}
+
+// Driver implements the driver.Connector interface.
+func (c *connector) Driver() driver.Driver {
+	return c
+}
+
+// Open implements the driver.Driver interface.
+func (c *connector) Open(string) (driver.Conn, error) {
+	return &conn{drv: c.drv}, nil
+}
+
+// Close implements the io.Closer interface and is called by sql.DB.Close.
+func (c *connector) Close() error {
+	opened.Delete(c.db)
+	return nil
+}
+
+// ExecContext implements the driver.ExecerContext interface.
+func (c *conn) ExecContext(ctx context.Context, query string, nv []driver.NamedValue) (driver.Result, error) {
+	args := make([]any, len(nv))
+	for i := range nv {
+		args[i] = nv[i].Value
+	}
+	if _, err := c.drv.ExecContext(ctx, query, args...); err != nil {
+		return nil, err
+	}
+	return result{}, nil
+}
+
+// Prepare implements the driver.Conn interface.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("mockdriver: prepared statements are not supported: %q", query)
+}
+
+// Begin implements the driver.Conn interface.
+func (c *conn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+// Close implements the driver.Conn interface.
+func (*conn) Close() error { return nil }
+
+// Commit implements the driver.Tx interface.
+func (*conn) Commit() error { return nil }
+
+// Rollback implements the driver.Tx interface.
+func (*conn) Rollback() error { return nil }
@@ -0,0 +1,136 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mockdriver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/mockdriver"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_Inspect(t *testing.T) {
+	ctx := context.Background()
+	drv := mockdriver.New(schema.NewRealm(
+		schema.New("public").AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))),
+		schema.New("other"),
+	))
+	u := mockdriver.Register(t.Name(), drv)
+	defer mockdriver.Unregister(t.Name())
+	require.Equal(t, "mock://"+t.Name(), u)
+
+	c, err := sqlclient.Open(ctx, u+"/public")
+	require.NoError(t, err)
+	defer c.Close()
+	require.Equal(t, "public", c.URL.Schema)
+	s, err := c.InspectSchema(ctx, c.URL.Schema, nil)
+	require.NoError(t, err)
+	require.Len(t, s.Tables, 1)
+	r, err := c.InspectRealm(ctx, &schema.InspectRealmOption{Schemas: []string{"other"}})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 1)
+	require.Equal(t, "other", r.Schemas[0].Name)
+	_, err = c.InspectSchema(ctx, "", nil)
+	require.EqualError(t, err, "mockdriver: schema name is required for realm with 2 schemas")
+	_, err = c.InspectSchema(ctx, "unknown", nil)
+	require.True(t, schema.IsNotExistError(err))
+
+	drv.InspectErr = errors.New("connection refused")
+	_, err = c.InspectRealm(ctx, nil)
+	require.EqualError(t, err, "connection refused")
+
+	_, err = sqlclient.Open(ctx, "mock://unknown")
+	require.EqualError(t, err, `mockdriver: driver "unknown" was not registered`)
+}
+
+func TestDriver_ApplyChanges(t *testing.T) {
+	ctx := context.Background()
+	drv := mockdriver.New(schema.NewRealm(schema.New("public")))
+	mockdriver.Register(t.Name(), drv)
+	defer mockdriver.Unregister(t.Name())
+	c, err := sqlclient.Open(ctx, "mock://"+t.Name())
+	require.NoError(t, err)
+	defer c.Close()
+
+	current, err := c.InspectSchema(ctx, "", nil)
+	require.NoError(t, err)
+	desired := schema.New("public").AddTables(
+		schema.NewTable("t1").AddColumns(schema.NewIntColumn("id", "int")),
+		schema.NewTable("t2").AddColumns(schema.NewIntColumn("id", "int")),
+	)
+	changes, err := c.SchemaDiff(current, desired)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+
+	drv.ExecFn = mockdriver.FailAt(2, errors.New("lock wait timeout exceeded"))
+	err = c.ApplyChanges(ctx, changes)
+	require.EqualError(t, err, "lock wait timeout exceeded")
+	require.Equal(t, 1, err.(interface{ Applied() int }).Applied())
+	require.Equal(t, []string{`CREATE TABLE "public"."t1"`}, drv.Stmts())
+
+	// Statements executed on transactions are routed to the driver.
+	drv.ExecFn = nil
+	tx, err := c.Tx(ctx, nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.ApplyChanges(ctx, changes[1:]))
+	require.NoError(t, tx.Commit())
+	_, err = c.DB.ExecContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+	require.Equal(t, []string{`CREATE TABLE "public"."t1"`, `CREATE TABLE "public"."t2"`, "SELECT 1"}, drv.Stmts())
+}
+
+func TestDriver_Lock(t *testing.T) {
+	ctx := context.Background()
+	drv := mockdriver.New(nil)
+	mockdriver.Register(t.Name(), drv)
+	defer mockdriver.Unregister(t.Name())
+	c1, err := sqlclient.Open(ctx, "mock://"+t.Name())
+	require.NoError(t, err)
+	defer c1.Close()
+	c2, err := sqlclient.Open(ctx, "mock://"+t.Name())
+	require.NoError(t, err)
+	defer c2.Close()
+
+	unlock, err := c1.Lock(ctx, "atlas_migrate_execute", 0)
+	require.NoError(t, err)
+	_, err = c2.Lock(ctx, "atlas_migrate_execute", 0)
+	require.ErrorIs(t, err, schema.ErrLocked)
+	require.NoError(t, unlock())
+	unlock, err = c2.Lock(ctx, "atlas_migrate_execute", 0)
+	require.NoError(t, err)
+	require.NoError(t, unlock())
+
+	drv.LockFn = func(context.Context, string, time.Duration) (schema.UnlockFunc, error) {
+		return nil, errors.New("lock timeout")
+	}
+	_, err = c1.Lock(ctx, "atlas_migrate_execute", time.Second)
+	require.EqualError(t, err, "lock timeout")
+}
+
+func TestDriver_PlanChanges(t *testing.T) {
+	drv := mockdriver.New(nil)
+	users := schema.NewTable("users").SetSchema(schema.New("public"))
+	plan, err := drv.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddSchema{S: users.Schema},
+		&schema.AddTable{T: users},
+		&schema.ModifyTable{T: users},
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Reversible)
+	require.Equal(t, []*migrate.Change{
+		{Cmd: `CREATE SCHEMA "public"`, Reverse: `DROP SCHEMA "public"`, Source: &schema.AddSchema{S: users.Schema}},
+		{Cmd: `CREATE TABLE "public"."users"`, Reverse: `DROP TABLE "public"."users"`, Source: &schema.AddTable{T: users}},
+		{Cmd: `ALTER TABLE "public"."users"`, Source: &schema.ModifyTable{T: users}},
+	}, plan.Changes)
+	_, err = drv.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddFunc{}})
+	require.EqualError(t, err, "mockdriver: unsupported change *schema.AddFunc")
+}
@@ -0,0 +1,226 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// FormatType converts schema type to its column form in the database.
+// An error is returned if the type cannot be recognized.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = TypeBit
+	case *schema.BinaryType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeBinary:
+			size := 1
+			if t.Size != nil {
+				size = *t.Size
+			}
+			f = fmt.Sprintf("%s(%d)", f, size)
+		case TypeVarBinary:
+			f = sizedType(f, t.Size)
+		case TypeImage:
+		default:
+			return "", fmt.Errorf("mssql: unexpected binary type: %q", t.T)
+		}
+	case *schema.DecimalType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeMoney, TypeSmallMoney:
+		case TypeDecimal, TypeDec, TypeNumeric:
+			if f == TypeDec {
+				f = TypeDecimal
+			}
+			p := t.Precision
+			if p == 0 {
+				p = defaultDecimalPrec
+			}
+			f = fmt.Sprintf("%s(%d,%d)", f, p, t.Scale)
+		default:
+			return "", fmt.Errorf("mssql: unexpected decimal type: %q", t.T)
+		}
+	case *schema.FloatType:
+		switch f = strings.ToLower(t.T); {
+		case f == TypeReal, f == TypeFloat && t.Precision > 0 && t.Precision <= 24:
+			f = TypeReal
+		default:
+			f = TypeFloat
+		}
+	case *schema.IntegerType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeTinyInt, TypeSmallInt, TypeInt, TypeBigInt:
+		case TypeInteger:
+			f = TypeInt
+		default:
+			return "", fmt.Errorf("mssql: unexpected integer type: %q", t.T)
+		}
+	case *schema.StringType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeChar, TypeNChar:
+			size := t.Size
+			if size == 0 {
+				size = 1
+			}
+			f = fmt.Sprintf("%s(%d)", f, size)
+		case TypeVarchar, TypeNVarchar:
+			var size *int
+			if t.Size > 0 {
+				size = &t.Size
+			}
+			f = sizedType(f, size)
+		case TypeText, TypeNText:
+		default:
+			return "", fmt.Errorf("mssql: unexpected string type: %q", t.T)
+		}
+	case *schema.TimeType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeDate, TypeDateTime, TypeSmallDateTime:
+		case TypeTime, TypeDateTime2, TypeDateTimeOffset:
+			if p := t.Precision; p != nil && *p != defaultTimePrec {
+				f = fmt.Sprintf("%s(%d)", f, *p)
+			}
+		default:
+			return "", fmt.Errorf("mssql: unexpected time type: %q", t.T)
+		}
+	case *schema.UUIDType:
+		f = TypeUniqueIdentifier
+	case *schema.SpatialType:
+		f = strings.ToLower(t.T)
+	case *UserDefinedType:
+		f = strings.ToLower(t.T)
+	case *schema.UnsupportedType:
+		return "", fmt.Errorf("mssql: unsupported type: %q", t.T)
+	default:
+		return "", fmt.Errorf("mssql: invalid schema type: %T", t)
+	}
+	return f, nil
+}
+
+// sizedType returns the type with its size, or with
+// the MAX specifier in case the size was not set.
+func sizedType(t string, size *int) string {
+	if size == nil {
+		return t + "(max)"
+	}
+	return fmt.Sprintf("%s(%d)", t, *size)
+}
+
+// reType matches the name of the type, its size (or MAX) or
+// precision, and its scale. For example: "decimal(10,2)",
+// "nvarchar(max)", "datetime2(3)" or "double precision".
+var reType = regexp.MustCompile(`^([a-z][a-z0-9_ ]*?)\s*(?:\(\s*(max|\d+)\s*(?:,\s*(\d+)\s*)?\))?$`)
+
+// ParseType returns the schema.Type value represented by the given raw type.
+// Types that are not recognized are returned as UserDefinedType.
+func ParseType(raw string) (schema.Type, error) {
+	c := strings.Join(strings.Fields(strings.ToLower(raw)), " ")
+	// Types can be quoted, e.g., [nvarchar](10).
+	c = strings.NewReplacer("[", "", "]", "").Replace(c)
+	m := reType.FindStringSubmatch(c)
+	if m == nil {
+		return &UserDefinedType{T: c}, nil
+	}
+	var (
+		err         error
+		size, scale int
+		name        = m[1]
+		hasSize     = m[2] != "" && m[2] != "max"
+	)
+	if hasSize {
+		if size, err = strconv.Atoi(m[2]); err != nil {
+			return nil, fmt.Errorf("mssql: parse size %q: %w", m[2], err)
+		}
+	}
+	if m[3] != "" {
+		if scale, err = strconv.Atoi(m[3]); err != nil {
+			return nil, fmt.Errorf("mssql: parse scale %q: %w", m[3], err)
+		}
+	}
+	switch name {
+	case TypeBit:
+		return &schema.BoolType{T: name}, nil
+	case TypeTinyInt, TypeSmallInt, TypeInt, TypeBigInt:
+		return &schema.IntegerType{T: name}, nil
+	case TypeInteger:
+		return &schema.IntegerType{T: TypeInt}, nil
+	case TypeDecimal, TypeDec, TypeNumeric:
+		if name == TypeDec {
+			name = TypeDecimal
+		}
+		if !hasSize {
+			size = defaultDecimalPrec
+		}
+		return &schema.DecimalType{T: name, Precision: size, Scale: scale}, nil
+	case TypeMoney, TypeSmallMoney:
+		return &schema.DecimalType{T: name}, nil
+	case TypeFloat:
+		// FLOAT(n) with n between 1 and 24 is treated as REAL.
+		if hasSize && size <= 24 {
+			return &schema.FloatType{T: TypeReal, Precision: 24}, nil
+		}
+		return &schema.FloatType{T: TypeFloat, Precision: defaultFloatPrec}, nil
+	case TypeReal:
+		return &schema.FloatType{T: TypeReal, Precision: 24}, nil
+	case TypeDouble:
+		return &schema.FloatType{T: TypeFloat, Precision: defaultFloatPrec}, nil
+	case TypeChar, TypeNChar:
+		// CHAR and NCHAR columns default to a length of 1.
+		if !hasSize {
+			size = 1
+		}
+		return &schema.StringType{T: name, Size: size}, nil
+	case TypeVarchar, TypeNVarchar:
+		// A zero size represents the MAX specifier, and
+		// VARCHAR and NVARCHAR default to a length of 1.
+		if !hasSize && m[2] == "" {
+			size = 1
+		}
+		return &schema.StringType{T: name, Size: size}, nil
+	case TypeText, TypeNText:
+		return &schema.StringType{T: name}, nil
+	case TypeBinary:
+		if !hasSize {
+			size = 1
+		}
+		return &schema.BinaryType{T: name, Size: &size}, nil
+	case TypeVarBinary:
+		t := &schema.BinaryType{T: name}
+		switch {
+		case hasSize:
+			t.Size = &size
+		case m[2] == "":
+			size = 1
+			t.Size = &size
+		}
+		return t, nil
+	case TypeImage:
+		return &schema.BinaryType{T: name}, nil
+	case TypeDate, TypeDateTime, TypeSmallDateTime:
+		return &schema.TimeType{T: name}, nil
+	case TypeTime, TypeDateTime2, TypeDateTimeOffset:
+		t := &schema.TimeType{T: name}
+		// Precision is omitted if it is the default.
+		if hasSize && size != defaultTimePrec {
+			t.Precision = &size
+		}
+		return t, nil
+	case TypeUniqueIdentifier:
+		return &schema.UUIDType{T: name}, nil
+	case TypeGeometry, TypeGeography:
+		return &schema.SpatialType{T: name}, nil
+	case "rowversion", "timestamp":
+		return &UserDefinedType{T: "rowversion"}, nil
+	default:
+		return &UserDefinedType{T: c}, nil
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultDiff provides basic diffing capabilities for SQL Server dialects.
+// Note, it is recommended to call Open, create a new Driver and use its
+// Differ when a database connection is available.
+var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{&conn{ExecQuerier: sqlx.NoRows, current: defaultSchema}}}
+
+// A diff provides a SQL Server implementation for sqlx.DiffDriver.
+type diff struct{ *conn }
+
+var _ sqlx.DropSchemaChanger = (*diff)(nil)
+
+// RealmObjectDiff returns a changeset for migrating realm (database) objects
+// from one state to the other. For example, adding extensions or users.
+func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// DropSchemaChange returns the changes for dropping the schema. Unlike other databases,
+// SQL Server does not allow dropping a schema that contains objects, and therefore its
+// tables are dropped first.
+func (*diff) DropSchemaChange(s *schema.Schema) []schema.Change {
+	changes := make([]schema.Change, 0, len(s.Tables)+1)
+	for _, t := range s.Tables {
+		changes = append(changes, &schema.DropTable{T: t})
+	}
+	return append(changes, &schema.DropSchema{S: s})
+}
+
+// SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
+func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
+	// No special schema attribute diffing for SQL Server.
+	return nil
+}
+
+// SchemaObjectDiff returns a changeset for migrating schema objects from
+// one state to the other.
+func (*diff) SchemaObjectDiff(_, _ *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+	return nil, nil
+}
+
+// TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
+func (*diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	var changes []schema.Change
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	return append(changes, sqlx.CheckDiffMode(from, to, opts.Mode)...), nil
+}
+
+// ViewAttrChanges returns the changes between the two view attributes.
+func (*diff) ViewAttrChanges(_, _ *schema.View) []schema.Change {
+	return nil // Not implemented.
+}
+
+// ColumnChange returns the schema changes (if any) for migrating one column to the other.
+func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.DiffOptions) (schema.Change, error) {
+	change := sqlx.CommentChange(from.Attrs, to.Attrs)
+	if from.Type.Null != to.Type.Null {
+		change |= schema.ChangeNull
+	}
+	changed, err := d.typeChanged(from, to)
+	if err != nil {
+		return sqlx.NoChange, err
+	}
+	if changed {
+		change |= schema.ChangeType
+	}
+	if d.defaultChanged(from, to) {
+		change |= schema.ChangeDefault
+	}
+	if d.generatedChanged(from, to) {
+		change |= schema.ChangeGenerated
+	}
+	if identityChanged(from.Attrs, to.Attrs) {
+		change |= schema.ChangeAttr
+	}
+	if change.Is(schema.NoChange) {
+		return sqlx.NoChange, nil
+	}
+	return &schema.ModifyColumn{
+		Change: change,
+		From:   from,
+		To:     to,
+	}, nil
+}
+
+// typeChanged reports if the column type was changed.
+func (*diff) typeChanged(from, to *schema.Column) (bool, error) {
+	fromT, toT := from.Type.Type, to.Type.Type
+	if fromT == nil || toT == nil {
+		return false, fmt.Errorf("mssql: missing type information for column %q", from.Name)
+	}
+	fromF, err := FormatType(fromT)
+	if err != nil {
+		return false, err
+	}
+	toF, err := FormatType(toT)
+	if err != nil {
+		return false, err
+	}
+	return fromF != toF, nil
+}
+
+// defaultChanged reports if the default value of a column was changed.
+func (*diff) defaultChanged(from, to *schema.Column) bool {
+	d1, ok1 := sqlx.DefaultValue(from)
+	d2, ok2 := sqlx.DefaultValue(to)
+	if ok1 != ok2 {
+		return true
+	}
+	if d1 == d2 {
+		return false
+	}
+	x1, err1 := sqlx.Unquote(unwrap(d1))
+	x2, err2 := sqlx.Unquote(unwrap(d2))
+	return err1 != nil || err2 != nil || x1 != x2
+}
+
+// generatedChanged reports if the generated expression of a column was changed.
+func (*diff) generatedChanged(from, to *schema.Column) bool {
+	var (
+		fromX, toX     schema.GeneratedExpr
+		fromHas, toHas = sqlx.Has(from.Attrs, &fromX), sqlx.Has(to.Attrs, &toX)
+	)
+	return fromHas != toHas || fromHas && (unwrap(fromX.Expr) != unwrap(toX.Expr) || generatedType(fromX.Type) != generatedType(toX.Type))
+}
+
+// reGeneratedName matches the names that are generated by the database for
+// unnamed constraints. For example, PK__users__3213E83F5D4A1C2B.
+var reGeneratedName = regexp.MustCompile(`^(?:PK|UQ|DF|CK|FK)__.+__[0-9A-F]{8,16}$`)
+
+// IsGeneratedIndexName reports if the index name was generated by the database.
+func (*diff) IsGeneratedIndexName(_ *schema.Table, idx *schema.Index) bool {
+	return reGeneratedName.MatchString(idx.Name)
+}
+
+// IndexAttrChanged reports if the index attributes were changed.
+func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
+	var p1, p2 IndexPredicate
+	if sqlx.Has(from, &p1) != sqlx.Has(to, &p2) || unwrap(p1.P) != unwrap(p2.P) {
+		return true
+	}
+	var t1, t2 IndexType
+	sqlx.Has(from, &t1)
+	sqlx.Has(to, &t2)
+	return !strings.EqualFold(t1.T, t2.T)
+}
+
+// IndexPartAttrChanged reports if the index-part attributes were changed.
+func (*diff) IndexPartAttrChanged(_, _ *schema.Index, _ int) bool {
+	return false
+}
+
+// ReferenceChanged reports if the foreign key referential action was changed.
+func (*diff) ReferenceChanged(from, to schema.ReferenceOption) bool {
+	// According to SQL Server, if an action is not explicitly
+	// specified, it defaults to "NO ACTION".
+	if from == "" {
+		from = schema.NoAction
+	}
+	if to == "" {
+		to = schema.NoAction
+	}
+	return from != to
+}
+
+// ForeignKeyAttrChanged reports if any of the foreign-key attributes were changed.
+func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
+	return false
+}
+
+// Normalize implements the sqlx.Normalizer interface.
+func (*diff) Normalize(from, to *schema.Table, _ *schema.DiffOptions) error {
+	// Unnamed CHECK constraints are named by the database with a generated name.
+	// Therefore, an unnamed constraint in the desired state is matched with an
+	// existing one by its expression, and inherits its name.
+	for _, a := range to.Attrs {
+		c2, ok := a.(*schema.Check)
+		if !ok || c2.Name != "" {
+			continue
+		}
+		for _, a := range from.Attrs {
+			if c1, ok := a.(*schema.Check); ok && reGeneratedName.MatchString(c1.Name) && unwrap(c1.Expr) == unwrap(c2.Expr) {
+				c2.Name = c1.Name
+				break
+			}
+		}
+	}
+	// Similarly, an unnamed primary key inherits its generated name.
+	if pk1, pk2 := from.PrimaryKey, to.PrimaryKey; pk1 != nil && pk2 != nil && pk2.Name == "" && reGeneratedName.MatchString(pk1.Name) {
+		pk2.Name = pk1.Name
+	}
+	return nil
+}
+
+// identityChanged reports if the identity attribute of a column was changed.
+func identityChanged(from, to []schema.Attr) bool {
+	var (
+		fromI, toI     Identity
+		fromHas, toHas = sqlx.Has(from, &fromI), sqlx.Has(to, &toI)
+	)
+	if fromHas != toHas {
+		return true
+	}
+	s1, i1 := identityValues(fromI)
+	s2, i2 := identityValues(toI)
+	return fromHas && (s1 != s2 || i1 != i2)
+}
+
+// identityValues returns the seed and the increment of the identity column.
+// A zero increment is invalid in SQL Server, and it represents an identity
+// attribute that was defined without arguments, i.e., IDENTITY(1, 1).
+func identityValues(i Identity) (int64, int64) {
+	if i.Increment == 0 {
+		return 1, 1
+	}
+	return i.Seed, i.Increment
+}
+
+// generatedType returns the storage type of a computed column,
+// and defaults to VIRTUAL (non-persisted) if it was not set.
+func generatedType(s string) string {
+	if strings.EqualFold(strings.TrimSpace(s), persisted) || strings.EqualFold(strings.TrimSpace(s), "STORED") {
+		return persisted
+	}
+	return virtual
+}
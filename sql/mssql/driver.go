@@ -0,0 +1,482 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/types"
+)
+
+type (
+	// Driver represents a SQL Server driver for introspecting database schemas,
+	// generating diff between schema elements and apply migrations changes.
+	Driver struct {
+		*conn
+		schema.Differ
+		schema.Inspector
+		migrate.PlanApplier
+	}
+
+	// database connection and its information.
+	conn struct {
+		schema.ExecQuerier
+		// The schema in the `schema` parameter (if given).
+		schema string
+		// The default schema of the connected user.
+		current string
+		// System variables that are set on `Open`.
+		version string
+		edition int
+	}
+)
+
+var _ interface {
+	migrate.Snapshoter
+	migrate.StmtScanner
+	migrate.CleanChecker
+	schema.TypeParseFormatter
+} = (*Driver)(nil)
+
+// DriverName holds the name used for registration.
+// Note, this package does not register a database/sql driver, and
+// users are expected to import one that is registered under this
+// name. For example, github.com/microsoft/go-mssqldb, or its azuread
+// package for connecting to Azure SQL using the "azuresql" scheme.
+const DriverName = "sqlserver"
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.OpenerFunc(opener),
+		sqlclient.RegisterDriverOpener(Open),
+		sqlclient.RegisterFlavours("azuresql"),
+		sqlclient.RegisterCodec(codec, codec),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+	types.Register(DriverName, (*Driver)(nil))
+}
+
+func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+	ur := parser{}.ParseURL(u)
+	db, err := sql.Open(u.Scheme, ur.DSN)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := Open(db)
+	if err != nil {
+		if cerr := db.Close(); cerr != nil {
+			err = fmt.Errorf("%w: %v", err, cerr)
+		}
+		return nil, err
+	}
+	drv.(*Driver).schema = ur.Schema
+	return &sqlclient.Client{
+		Name:   DriverName,
+		DB:     db,
+		URL:    ur,
+		Driver: drv,
+	}, nil
+}
+
+// Open opens a new SQL Server driver.
+func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	c := &conn{ExecQuerier: db}
+	rows, err := db.QueryContext(context.Background(), paramsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("mssql: query system variables: %w", err)
+	}
+	var (
+		current, version sql.NullString
+		edition          sql.NullInt64
+	)
+	if err := sqlx.ScanOne(rows, &current, &version, &edition); err != nil {
+		return nil, fmt.Errorf("mssql: scan system variables: %w", err)
+	}
+	c.current, c.version, c.edition = current.String, version.String, int(edition.Int64)
+	if c.current == "" {
+		c.current = defaultSchema
+	}
+	// Azure SQL Database reports a fixed version (12.x),
+	// but it is always running the latest engine version.
+	if c.edition != editionAzure && c.major() < 13 {
+		return nil, fmt.Errorf("mssql: unsupported sql server version: %q", c.version)
+	}
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &diff{c}},
+		Inspector:   &inspect{c},
+		PlanApplier: &planApply{c},
+	}, nil
+}
+
+// Snapshot implements migrate.Snapshoter.
+func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
+	// The driver is considered bound to a schema only if the `schema`
+	// parameter was given. Otherwise, it is bound to the database.
+	if d.schema != "" {
+		s, err := d.InspectSchema(ctx, d.schema, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(s.Tables) > 0 {
+			return nil, &migrate.NotCleanError{
+				State:  schema.NewRealm(s),
+				Reason: fmt.Sprintf("found table %q in connected schema", s.Tables[0].Name),
+			}
+		}
+		return d.SchemaRestoreFunc(s), nil
+	}
+	realm, err := d.InspectRealm(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// A database is considered clean, if it has no schemas
+	// other than the default one, and the latter has no tables.
+	for _, s := range realm.Schemas {
+		switch {
+		case s.Name != d.current:
+			return nil, &migrate.NotCleanError{
+				State:  realm,
+				Reason: fmt.Sprintf("found schema %q", s.Name),
+			}
+		case len(s.Tables) > 0:
+			return nil, &migrate.NotCleanError{
+				State:  realm,
+				Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name),
+			}
+		}
+	}
+	return d.RealmRestoreFunc(realm), nil
+}
+
+// SchemaRestoreFunc returns a function that restores the given schema to its desired state.
+func (d *Driver) SchemaRestoreFunc(desired *schema.Schema) migrate.RestoreFunc {
+	return func(ctx context.Context) error {
+		current, err := d.InspectSchema(ctx, desired.Name, nil)
+		if err != nil {
+			return err
+		}
+		changes, err := d.SchemaDiff(current, desired)
+		if err != nil {
+			return err
+		}
+		return d.ApplyChanges(ctx, changes)
+	}
+}
+
+// RealmRestoreFunc returns a function that restores the given realm to its desired state.
+func (d *Driver) RealmRestoreFunc(desired *schema.Realm) migrate.RestoreFunc {
+	return func(ctx context.Context) error {
+		current, err := d.InspectRealm(ctx, nil)
+		if err != nil {
+			return err
+		}
+		changes, err := d.RealmDiff(current, desired)
+		if err != nil {
+			return err
+		}
+		return d.ApplyChanges(ctx, changes)
+	}
+}
+
+// CheckClean implements migrate.CleanChecker.
+func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
+	if revT == nil { // accept nil values
+		revT = &migrate.TableIdent{}
+	}
+	if d.schema != "" {
+		switch s, err := d.InspectSchema(ctx, d.schema, nil); {
+		case err != nil:
+			return err
+		case len(s.Tables) == 0, (revT.Schema == "" || s.Name == revT.Schema) && len(s.Tables) == 1 && s.Tables[0].Name == revT.Name:
+			return nil
+		default:
+			return &migrate.NotCleanError{State: schema.NewRealm(s), Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name)}
+		}
+	}
+	r, err := d.InspectRealm(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		switch {
+		case len(s.Tables) == 0 && (s.Name == d.current || s.Name == revT.Schema):
+		case len(s.Tables) == 0:
+			return &migrate.NotCleanError{State: r, Reason: fmt.Sprintf("found schema %q", s.Name)}
+		case len(s.Tables) > 1:
+			return &migrate.NotCleanError{State: r, Reason: fmt.Sprintf("found %d tables in schema %q", len(s.Tables), s.Name)}
+		case len(s.Tables) == 1 && s.Tables[0].Name != revT.Name:
+			return &migrate.NotCleanError{State: r, Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name)}
+		}
+	}
+	return nil
+}
+
+// Lock implements the schema.Locker interface. The lock is held
+// by the session, and is released when the connection is closed.
+func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error) {
+	conn, err := sqlx.SingleConn(ctx, d.ExecQuerier)
+	if err != nil {
+		return nil, err
+	}
+	if err := acquire(ctx, conn, name, timeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() error {
+		defer conn.Close()
+		rows, err := conn.QueryContext(ctx, releaseQuery, name)
+		if err != nil {
+			return err
+		}
+		var status sql.NullInt64
+		switch err := sqlx.ScanOne(rows, &status); {
+		case err != nil:
+			return err
+		case status.Int64 < 0:
+			return fmt.Errorf("mssql: failed releasing lock %q: status %d", name, status.Int64)
+		}
+		return nil
+	}, nil
+}
+
+// FormatType converts schema type to its column form in the database.
+func (*Driver) FormatType(t schema.Type) (string, error) {
+	return FormatType(t)
+}
+
+// ParseType returns the schema.Type value represented by the given string.
+func (*Driver) ParseType(s string) (schema.Type, error) {
+	return ParseType(s)
+}
+
+// StmtBuilder is a helper method used to build statements with SQL Server formatting.
+func (*Driver) StmtBuilder(opts migrate.PlanOptions) *sqlx.Builder {
+	return &sqlx.Builder{
+		QuoteOpening: '[',
+		QuoteClosing: ']',
+		Schema:       opts.SchemaQualifier,
+		Indent:       opts.Indent,
+	}
+}
+
+// ScanStmts implements migrate.StmtScanner. Note, the "GO" batch separator
+// is a client-side command (e.g., sqlcmd) and is not accepted by the server.
+// Hence, it is treated as a statement delimiter and removed from the output.
+func (*Driver) ScanStmts(input string) ([]*migrate.Stmt, error) {
+	stmts, err := (&migrate.Scanner{
+		ScannerOptions: migrate.ScannerOptions{
+			MatchBegin: true,
+			// The following are not supported by SQL Server.
+			MatchBeginAtomic: false,
+			MatchDollarQuote: false,
+		},
+	}).Scan(input)
+	if err != nil {
+		return nil, err
+	}
+	scanned := make([]*migrate.Stmt, 0, len(stmts))
+	for _, s := range stmts {
+		for _, b := range splitBatches(s) {
+			if strings.TrimSpace(b.Text) != "" {
+				scanned = append(scanned, b)
+			}
+		}
+	}
+	return scanned, nil
+}
+
+// splitBatches splits the statement by lines that contain only the GO separator.
+func splitBatches(s *migrate.Stmt) []*migrate.Stmt {
+	lines := strings.SplitAfter(s.Text, "\n")
+	if !slices.ContainsFunc(lines, isGO) {
+		return []*migrate.Stmt{s}
+	}
+	var (
+		pos    = s.Pos
+		start  = s.Pos
+		batch  strings.Builder
+		stmts  []*migrate.Stmt
+		commit = func() {
+			stmts = append(stmts, &migrate.Stmt{
+				Pos:  start,
+				Text: strings.TrimSpace(batch.String()),
+			})
+			batch.Reset()
+		}
+	)
+	for _, l := range lines {
+		pos += len(l)
+		if isGO(l) {
+			commit()
+			start = pos
+			continue
+		}
+		batch.WriteString(l)
+	}
+	commit()
+	// Comments are attached to the first batch.
+	stmts[0].Comments = s.Comments
+	return stmts
+}
+
+// isGO reports if the line is a GO batch separator.
+func isGO(l string) bool {
+	l = strings.TrimRight(strings.TrimSpace(l), ";")
+	return strings.EqualFold(l, "GO")
+}
+
+// acquire requests an exclusive application lock with the given timeout.
+// See: https://learn.microsoft.com/en-us/sql/relational-databases/system-stored-procedures/sp-getapplock-transact-sql
+func acquire(ctx context.Context, conn schema.ExecQuerier, name string, timeout time.Duration) error {
+	rows, err := conn.QueryContext(ctx, acquireQuery, name, timeout.Milliseconds())
+	if err != nil {
+		return err
+	}
+	var status sql.NullInt64
+	if err := sqlx.ScanOne(rows, &status); err != nil {
+		return err
+	}
+	switch status.Int64 {
+	// The lock was granted synchronously, or after waiting for other locks.
+	case 0, 1:
+		return nil
+	// The lock request timed out.
+	case -1:
+		return schema.ErrLocked
+	default:
+		return fmt.Errorf("mssql: failed acquiring lock %q: status %d", name, status.Int64)
+	}
+}
+
+// bound returns the schema the driver is bound to.
+func (c *conn) bound() string {
+	if c.schema != "" {
+		return c.schema
+	}
+	return c.current
+}
+
+// major returns the major version of the connected database.
+func (c *conn) major() int {
+	v, _, _ := strings.Cut(c.version, ".")
+	n, _ := strconv.Atoi(v)
+	return n
+}
+
+type parser struct{}
+
+// ParseURL implements the sqlclient.URLParser interface.
+func (parser) ParseURL(u *url.URL) *sqlclient.URL {
+	dsn := *u
+	// The schema parameter is used by Atlas and not passed to the underlying driver.
+	if q := dsn.Query(); q.Has("schema") {
+		q.Del("schema")
+		dsn.RawQuery = q.Encode()
+	}
+	// The Azure AD driver (registered as "azuresql") accepts
+	// the same connection strings as the SQL Server driver.
+	dsn.Scheme = DriverName
+	return &sqlclient.URL{URL: u, DSN: dsn.String(), Schema: u.Query().Get("schema")}
+}
+
+// ChangeSchema implements the sqlclient.SchemaChanger interface.
+func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
+	nu := *u
+	q := nu.Query()
+	q.Set("schema", s)
+	nu.RawQuery = q.Encode()
+	return &nu
+}
+
+// Standard column types (and their aliases) as defined in
+// the SQL Server documentation.
+// https://learn.microsoft.com/en-us/sql/t-sql/data-types/data-types-transact-sql
+const (
+	TypeBit      = "bit"
+	TypeTinyInt  = "tinyint"
+	TypeSmallInt = "smallint"
+	TypeInt      = "int"
+	TypeInteger  = "integer" // int.
+	TypeBigInt   = "bigint"
+
+	TypeDecimal    = "decimal"
+	TypeDec        = "dec" // decimal.
+	TypeNumeric    = "numeric"
+	TypeMoney      = "money"
+	TypeSmallMoney = "smallmoney"
+
+	TypeFloat  = "float"
+	TypeReal   = "real"             // float(24).
+	TypeDouble = "double precision" // float(53).
+
+	TypeChar     = "char"
+	TypeVarchar  = "varchar"
+	TypeText     = "text"
+	TypeNChar    = "nchar"
+	TypeNVarchar = "nvarchar"
+	TypeNText    = "ntext"
+
+	TypeBinary    = "binary"
+	TypeVarBinary = "varbinary"
+	TypeImage     = "image"
+
+	TypeDate           = "date"
+	TypeTime           = "time"
+	TypeDateTime       = "datetime"
+	TypeDateTime2      = "datetime2"
+	TypeDateTimeOffset = "datetimeoffset"
+	TypeSmallDateTime  = "smalldatetime"
+
+	TypeUniqueIdentifier = "uniqueidentifier"
+	TypeXML              = "xml"
+	TypeGeometry         = "geometry"
+	TypeGeography        = "geography"
+)
+
+const (
+	// The default schema of the database.
+	defaultSchema = "dbo"
+	// Default fractional seconds precision of time types.
+	defaultTimePrec = 7
+	// Default number of mantissa bits of the float type.
+	defaultFloatPrec = 53
+	// Default precision of the decimal and numeric types.
+	defaultDecimalPrec = 18
+	// Maximum length of identifiers (the sysname type).
+	maxIdentLen = 128
+	// The engine edition of Azure SQL Database.
+	editionAzure = 5
+	// Computed columns that are physically stored in the table.
+	persisted = "PERSISTED"
+	// Computed columns that are calculated on read.
+	virtual = "VIRTUAL"
+	// The name of the extended property used for comments.
+	descProperty = "MS_Description"
+)
+
+const (
+	// Query to list the default schema, the database version and its edition.
+	paramsQuery = `SELECT SCHEMA_NAME(), CAST(SERVERPROPERTY('ProductVersion') AS NVARCHAR(128)), CAST(SERVERPROPERTY('EngineEdition') AS INT)`
+
+	// Query to acquire a session-level application lock. The return value
+	// is 0 or 1 if the lock was granted, and a negative number otherwise.
+	acquireQuery = `DECLARE @r INT; EXEC @r = sp_getapplock @Resource = @p1, @LockMode = 'Exclusive', @LockOwner = 'Session', @LockTimeout = @p2; SELECT @r`
+
+	// Query to release a session-level application lock.
+	releaseQuery = `DECLARE @r INT; EXEC @r = sp_releaseapplock @Resource = @p1, @LockOwner = 'Session'; SELECT @r`
+)
@@ -0,0 +1,594 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// An inspect provides a SQL Server implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all resources in the given realm.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	var (
+		r    = schema.NewRealm(schemas...)
+		mode = sqlx.ModeInspectRealm(opts)
+	)
+	if len(schemas) > 0 && mode.Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, nil); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// If the schema name is empty, the result will be the attached schema.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	if name == "" {
+		name = i.bound()
+	}
+	schemas, err := i.schemas(ctx, &schema.InspectRealmOption{Schemas: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("mssql: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("mssql: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	var (
+		r    = schema.NewRealm(schemas...)
+		mode = sqlx.ModeInspectSchema(opts)
+	)
+	if mode.Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.SchemaChecksums(schema.ExcludeSchema(r.Schemas[0], opts.Exclude))
+}
+
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, r, opts); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Tables) == 0 {
+			continue
+		}
+		if err := i.columns(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.indexes(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.fks(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.checks(ctx, s, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemas returns the list of the schemas in the database.
+func (i *inspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = schemasQuery
+	)
+	if opts != nil && len(opts.Schemas) > 0 {
+		query = fmt.Sprintf(schemasQueryArgs, nArgs(1, len(opts.Schemas)))
+		for _, s := range opts.Schemas {
+			args = append(args, s)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("mssql: querying schemas: %w", err)
+	}
+	defer rows.Close()
+	var schemas []*schema.Schema
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema.New(name))
+	}
+	return schemas, rows.Err()
+}
+
+func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, nArgs(1, len(realm.Schemas)))
+	)
+	for _, s := range realm.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		query = fmt.Sprintf(tablesQueryArgs, nArgs(1, len(realm.Schemas)), nArgs(len(args)+1, len(opts.Tables)))
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("mssql: querying schema tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tSchema, name, comment sql.NullString
+		if err := rows.Scan(&tSchema, &name, &comment); err != nil {
+			return fmt.Errorf("scan table information: %w", err)
+		}
+		if !sqlx.ValidString(tSchema) || !sqlx.ValidString(name) {
+			return fmt.Errorf("invalid schema or table name: %q.%q", tSchema.String, name.String)
+		}
+		s, ok := realm.Schema(tSchema.String)
+		if !ok {
+			return fmt.Errorf("schema %q was not found in realm", tSchema.String)
+		}
+		t := schema.NewTable(name.String)
+		s.AddTables(t)
+		if sqlx.ValidString(comment) {
+			t.SetComment(comment.String)
+		}
+	}
+	return rows.Err()
+}
+
+// columns queries and appends the columns of the given table.
+func (i *inspect) columns(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, columnsQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("mssql: query schema %q columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := i.addColumn(s, rows); err != nil {
+			return fmt.Errorf("mssql: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// addColumn scans the current row and adds a new column from it to the table.
+func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
+	var (
+		nullable, persist                                 sql.NullBool
+		length, precision, scale, seed, incr              sql.NullInt64
+		table, name, typ, defaults, defName, computed, cm sql.NullString
+	)
+	if err := rows.Scan(&table, &name, &typ, &length, &precision, &scale, &nullable, &defaults, &defName, &computed, &persist, &seed, &incr, &cm); err != nil {
+		return err
+	}
+	t, ok := s.Table(table.String)
+	if !ok {
+		return nil
+	}
+	c := &schema.Column{
+		Name: name.String,
+		Type: &schema.ColumnType{
+			Raw:  columnType(typ.String, length, precision, scale),
+			Null: nullable.Bool,
+		},
+	}
+	ct, err := ParseType(c.Type.Raw)
+	if err != nil {
+		return fmt.Errorf("parse %q.%q type %q: %w", t.Name, c.Name, c.Type.Raw, err)
+	}
+	c.Type.Type = ct
+	switch {
+	case sqlx.ValidString(computed):
+		x := &schema.GeneratedExpr{Expr: unwrap(computed.String), Type: virtual}
+		if persist.Bool {
+			x.Type = persisted
+		}
+		c.SetGeneratedExpr(x)
+	case seed.Valid:
+		c.AddAttrs(&Identity{Seed: seed.Int64, Increment: incr.Int64})
+	}
+	if sqlx.ValidString(defaults) {
+		c.Default = defaultExpr(unwrap(defaults.String))
+		if sqlx.ValidString(defName) {
+			c.AddAttrs(&DefaultConstraint{Name: defName.String})
+		}
+	}
+	if sqlx.ValidString(cm) {
+		c.SetComment(cm.String)
+	}
+	t.AddColumns(c)
+	return nil
+}
+
+// columnType returns the column type as defined in the database, as the
+// sys.types catalog view does not include the length and precision arguments.
+func columnType(typ string, length, precision, scale sql.NullInt64) string {
+	switch typ = strings.ToLower(typ); typ {
+	case TypeDecimal, TypeNumeric:
+		return fmt.Sprintf("%s(%d,%d)", typ, precision.Int64, scale.Int64)
+	case TypeChar, TypeVarchar, TypeBinary, TypeVarBinary:
+		if length.Int64 == -1 {
+			return typ + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typ, length.Int64)
+	// The length of Unicode types is stored in bytes.
+	case TypeNChar, TypeNVarchar:
+		if length.Int64 == -1 {
+			return typ + "(max)"
+		}
+		return fmt.Sprintf("%s(%d)", typ, length.Int64/2)
+	case TypeTime, TypeDateTime2, TypeDateTimeOffset:
+		return fmt.Sprintf("%s(%d)", typ, scale.Int64)
+	default:
+		return typ
+	}
+}
+
+// indexes queries and appends the indexes of the given table.
+func (i *inspect) indexes(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, indexesQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("mssql: query schema %q indexes: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := i.addIndexes(s, rows); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// addIndexes scans the rows and adds the indexes to the table.
+func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
+	for rows.Next() {
+		var (
+			seqno                               int
+			uniq, primary, constraint, desc     sql.NullBool
+			table, name, column, typ, predicate sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &typ, &uniq, &primary, &constraint, &column, &seqno, &desc, &predicate); err != nil {
+			return fmt.Errorf("mssql: scanning indexes for schema %q: %w", s.Name, err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			continue
+		}
+		var idx *schema.Index
+		switch {
+		case primary.Bool:
+			if idx = t.PrimaryKey; idx == nil {
+				idx = schema.NewPrimaryKey().SetName(name.String)
+				if typ.String == nonClustered {
+					idx.AddAttrs(&IndexType{T: nonClustered})
+				}
+				t.SetPrimaryKey(idx)
+			}
+		default:
+			if idx, ok = t.Index(name.String); !ok {
+				idx = schema.NewIndex(name.String).SetUnique(uniq.Bool)
+				// Indexes that enforce UNIQUE constraints are named after the constraint.
+				if constraint.Bool {
+					idx.AddAttrs(&Constraint{T: "U"})
+				}
+				if typ.String == clustered {
+					idx.AddAttrs(&IndexType{T: clustered})
+				}
+				if sqlx.ValidString(predicate) {
+					idx.AddAttrs(&IndexPredicate{P: unwrap(predicate.String)})
+				}
+				t.AddIndexes(idx)
+			}
+		}
+		// Rows are ordered by the key ordinal of
+		// the column in the index definition.
+		part := &schema.IndexPart{SeqNo: seqno, Desc: desc.Bool}
+		if part.C, ok = t.Column(column.String); !ok {
+			return fmt.Errorf("mssql: column %q was not found for index %q", column.String, idx.Name)
+		}
+		part.C.Indexes = append(part.C.Indexes, idx)
+		idx.Parts = append(idx.Parts, part)
+	}
+	return nil
+}
+
+// fks queries and appends the foreign keys of the given table.
+func (i *inspect) fks(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, fksQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("mssql: querying %q foreign keys: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := sqlx.SchemaFKs(s, rows); err != nil {
+		return fmt.Errorf("mssql: %w", err)
+	}
+	return rows.Err()
+}
+
+// checks queries and appends the check constraints of the given table.
+func (i *inspect) checks(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, checksQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("mssql: querying %q check constraints: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, clause sql.NullString
+		if err := rows.Scan(&table, &name, &clause); err != nil {
+			return fmt.Errorf("mssql: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			continue
+		}
+		t.AddChecks(&schema.Check{
+			Name: name.String,
+			Expr: unwrap(clause.String),
+		})
+	}
+	return rows.Err()
+}
+
+// querySchema calls sqlx.QueryContext on the given query with the schema name
+// as the first argument, and the inspected table names (if filtered) after it.
+func (i *inspect) querySchema(ctx context.Context, query string, s *schema.Schema, opts *schema.InspectOptions) (*sql.Rows, error) {
+	var (
+		filter string
+		args   = []any{s.Name}
+	)
+	if opts != nil && len(opts.Tables) > 0 {
+		filter = fmt.Sprintf(" AND t.name IN (%s)", nArgs(2, len(s.Tables)))
+		for _, t := range s.Tables {
+			args = append(args, t.Name)
+		}
+	}
+	return i.QueryContext(ctx, fmt.Sprintf(query, filter), args...)
+}
+
+// nArgs returns n positional bind placeholders starting from the given position.
+func nArgs(start, n int) string {
+	args := make([]string, n)
+	for i := range args {
+		args[i] = "@p" + strconv.Itoa(start+i)
+	}
+	return strings.Join(args, ", ")
+}
+
+// unwrap removes the redundant parentheses that wrap expressions stored by
+// SQL Server. For example, "((0))" is returned as "0", and "([a]>(0))" as
+// "[a]>(0)".
+func unwrap(x string) string {
+	x = strings.TrimSpace(x)
+	for n := len(x) - 1; n > 1 && x[0] == '(' && x[n] == ')' && sqlx.ExprLastIndex(x[1:n]) == n-2; n = len(x) - 1 {
+		x = strings.TrimSpace(x[1:n])
+	}
+	return x
+}
+
+func defaultExpr(x string) schema.Expr {
+	switch {
+	case sqlx.IsLiteralNumber(x), sqlx.IsQuoted(x, '\''):
+		return &schema.Literal{V: x}
+	// Unicode string literals, e.g., N'value'.
+	case len(x) > 1 && (x[0] == 'N' || x[0] == 'n') && sqlx.IsQuoted(x[1:], '\''):
+		return &schema.Literal{V: x}
+	default:
+		return &schema.RawExpr{X: x}
+	}
+}
+
+type (
+	// Identity defines an identity column.
+	// https://learn.microsoft.com/en-us/sql/t-sql/statements/create-table-transact-sql-identity-property
+	Identity struct {
+		schema.Attr
+		Seed, Increment int64
+	}
+
+	// DefaultConstraint describes the name of the constraint that holds the column
+	// default. In SQL Server, dropping or changing a column default requires its name.
+	DefaultConstraint struct {
+		schema.Attr
+		Name string
+	}
+
+	// Constraint describes the constraint that is enforced by an index.
+	Constraint struct {
+		schema.Attr
+		T string // U.
+	}
+
+	// IndexType describes the type of the index, if it is not the default
+	// one: CLUSTERED for primary keys and NONCLUSTERED for other indexes.
+	IndexType struct {
+		schema.Attr
+		T string // CLUSTERED, NONCLUSTERED.
+	}
+
+	// IndexPredicate describes a filtered index.
+	IndexPredicate struct {
+		schema.Attr
+		P string
+	}
+
+	// UserDefinedType defines a user-defined type, or a
+	// built-in type that is not supported by the driver.
+	UserDefinedType struct {
+		schema.Type
+		T string
+	}
+)
+
+const (
+	clustered    = "CLUSTERED"
+	nonClustered = "NONCLUSTERED"
+)
+
+const (
+	// Query to list database schemas. Schemas that are maintained by the
+	// database (e.g., sys or INFORMATION_SCHEMA) and the schemas of fixed
+	// database roles (e.g., db_owner) are excluded.
+	schemasQuery = `SELECT name FROM sys.schemas WHERE schema_id NOT IN (2, 3, 4) AND schema_id < 16384 ORDER BY name`
+
+	// Query to list specific database schemas.
+	schemasQueryArgs = `SELECT name FROM sys.schemas WHERE name IN (%s) ORDER BY name`
+
+	// Query to list schema tables.
+	tablesQuery = `
+SELECT
+	s.name,
+	t.name,
+	CAST(p.value AS NVARCHAR(MAX))
+FROM
+	sys.tables t
+	JOIN sys.schemas s ON s.schema_id = t.schema_id
+	LEFT JOIN sys.extended_properties p ON p.major_id = t.object_id AND p.minor_id = 0 AND p.class = 1 AND p.name = 'MS_Description'
+WHERE
+	s.name IN (%s)
+	AND t.is_ms_shipped = 0
+ORDER BY
+	s.name, t.name
+`
+
+	// Query to list specific schema tables.
+	tablesQueryArgs = `
+SELECT
+	s.name,
+	t.name,
+	CAST(p.value AS NVARCHAR(MAX))
+FROM
+	sys.tables t
+	JOIN sys.schemas s ON s.schema_id = t.schema_id
+	LEFT JOIN sys.extended_properties p ON p.major_id = t.object_id AND p.minor_id = 0 AND p.class = 1 AND p.name = 'MS_Description'
+WHERE
+	s.name IN (%s)
+	AND t.name IN (%s)
+	AND t.is_ms_shipped = 0
+ORDER BY
+	s.name, t.name
+`
+
+	// Query to list table columns.
+	columnsQuery = `
+SELECT
+	t.name,
+	c.name,
+	TYPE_NAME(c.user_type_id),
+	c.max_length,
+	c.precision,
+	c.scale,
+	c.is_nullable,
+	d.definition,
+	d.name,
+	cc.definition,
+	cc.is_persisted,
+	CAST(ic.seed_value AS BIGINT),
+	CAST(ic.increment_value AS BIGINT),
+	CAST(p.value AS NVARCHAR(MAX))
+FROM
+	sys.columns c
+	JOIN sys.tables t ON t.object_id = c.object_id
+	LEFT JOIN sys.default_constraints d ON d.object_id = c.default_object_id
+	LEFT JOIN sys.computed_columns cc ON cc.object_id = c.object_id AND cc.column_id = c.column_id
+	LEFT JOIN sys.identity_columns ic ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+	LEFT JOIN sys.extended_properties p ON p.major_id = c.object_id AND p.minor_id = c.column_id AND p.class = 1 AND p.name = 'MS_Description'
+WHERE
+	SCHEMA_NAME(t.schema_id) = @p1%s
+ORDER BY
+	t.name, c.column_id
+`
+
+	// Query to list table indexes, and the constraints they enforce.
+	indexesQuery = `
+SELECT
+	t.name,
+	i.name,
+	i.type_desc,
+	i.is_unique,
+	i.is_primary_key,
+	i.is_unique_constraint,
+	c.name,
+	ic.key_ordinal,
+	ic.is_descending_key,
+	i.filter_definition
+FROM
+	sys.indexes i
+	JOIN sys.tables t ON t.object_id = i.object_id
+	JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+	JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+WHERE
+	SCHEMA_NAME(t.schema_id) = @p1
+	AND i.type IN (1, 2)
+	AND ic.is_included_column = 0%s
+ORDER BY
+	t.name, i.name, ic.key_ordinal
+`
+
+	// Query to list table foreign keys.
+	fksQuery = `
+SELECT
+	fk.name,
+	t.name,
+	c.name,
+	SCHEMA_NAME(t.schema_id),
+	rt.name,
+	rc.name,
+	SCHEMA_NAME(rt.schema_id),
+	REPLACE(fk.update_referential_action_desc, '_', ' '),
+	REPLACE(fk.delete_referential_action_desc, '_', ' ')
+FROM
+	sys.foreign_keys fk
+	JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+	JOIN sys.tables t ON t.object_id = fkc.parent_object_id
+	JOIN sys.columns c ON c.object_id = fkc.parent_object_id AND c.column_id = fkc.parent_column_id
+	JOIN sys.tables rt ON rt.object_id = fkc.referenced_object_id
+	JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+WHERE
+	SCHEMA_NAME(t.schema_id) = @p1%s
+ORDER BY
+	fk.name, fkc.constraint_column_id
+`
+
+	// Query to list table check constraints.
+	checksQuery = `
+SELECT
+	t.name,
+	k.name,
+	k.definition
+FROM
+	sys.check_constraints k
+	JOIN sys.tables t ON t.object_id = k.parent_object_id
+WHERE
+	SCHEMA_NAME(t.schema_id) = @p1%s
+ORDER BY
+	t.name, k.name
+`
+)
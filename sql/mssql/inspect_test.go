@@ -0,0 +1,143 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("16.0.1000.6", 3)
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "@p1"))).
+		WithArgs("dbo").
+		WillReturnRows(sqltest.Rows(`
+ name
+------
+ dbo
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "@p1"))).
+		WithArgs("dbo").
+		WillReturnRows(sqltest.Rows(`
+ schema | name  | comment
+--------+-------+-------------
+ dbo    | posts | nil
+ dbo    | users | users table
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, ""))).
+		WithArgs("dbo").
+		WillReturnRows(sqlmock.NewRows([]string{"table", "name", "type", "max_length", "precision", "scale", "is_nullable", "default", "default_name", "computed", "is_persisted", "seed", "increment", "comment"}).
+			AddRow("posts", "id", "bigint", 8, 19, 0, false, nil, nil, nil, nil, 1, 1, nil).
+			AddRow("posts", "author_id", "int", 4, 10, 0, true, nil, nil, nil, nil, nil, nil, nil).
+			AddRow("posts", "body", "nvarchar", -1, 0, 0, true, nil, nil, nil, nil, nil, nil, nil).
+			AddRow("users", "id", "int", 4, 10, 0, false, nil, nil, nil, nil, 100, 10, nil).
+			AddRow("users", "name", "nvarchar", 200, 0, 0, false, "(N'unknown')", "DF_users_name", nil, nil, nil, nil, "user name").
+			AddRow("users", "price", "decimal", 9, 10, 2, true, "((0))", "DF__users__price__38996AB5", nil, nil, nil, nil, nil).
+			AddRow("users", "created_at", "datetime2", 8, 27, 7, false, "(sysdatetime())", "DF__users__created__398D8EEE", nil, nil, nil, nil, nil).
+			AddRow("users", "total", "decimal", 9, 11, 2, true, nil, nil, "([price]*(2))", true, nil, nil, nil))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, ""))).
+		WithArgs("dbo").
+		WillReturnRows(sqlmock.NewRows([]string{"table", "name", "type", "is_unique", "is_primary_key", "is_unique_constraint", "column", "key_ordinal", "is_descending_key", "filter"}).
+			AddRow("posts", "PK__posts__3213E83F5D4A1C2B", "CLUSTERED", true, true, false, "id", 1, false, nil).
+			AddRow("posts", "posts_author", "NONCLUSTERED", false, false, false, "author_id", 1, false, "([author_id] IS NOT NULL)").
+			AddRow("users", "users_name", "NONCLUSTERED", true, false, true, "name", 1, false, nil).
+			AddRow("users", "users_pk", "NONCLUSTERED", true, true, false, "id", 1, false, nil).
+			AddRow("users", "users_price", "CLUSTERED", false, false, false, "price", 1, true, nil))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, ""))).
+		WithArgs("dbo").
+		WillReturnRows(sqltest.Rows(`
+ name         | table | column    | schema | ref_table | ref_column | ref_schema | update_rule | delete_rule
+--------------+-------+-----------+--------+-----------+------------+------------+-------------+------------
+ posts_author | posts | author_id | dbo    | users     | id         | dbo        | NO ACTION   | CASCADE
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(checksQuery, ""))).
+		WithArgs("dbo").
+		WillReturnRows(sqltest.Rows(`
+ table | name        | definition
+-------+-------------+--------------
+ users | users_price | ([price]>(0))
+`))
+	s, err := drv.InspectSchema(context.Background(), "", nil)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+
+	require.Equal(t, "dbo", s.Name)
+	require.Len(t, s.Tables, 2)
+	posts, users := s.Tables[0], s.Tables[1]
+
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: TypeBigInt}}, Attrs: []schema.Attr{&Identity{Seed: 1, Increment: 1}}},
+		{Name: "author_id", Type: &schema.ColumnType{Raw: "int", Type: &schema.IntegerType{T: TypeInt}, Null: true}},
+		{Name: "body", Type: &schema.ColumnType{Raw: "nvarchar(max)", Type: &schema.StringType{T: TypeNVarchar}, Null: true}},
+	}, dropIndexes(posts.Columns))
+	require.Equal(t, "PK__posts__3213E83F5D4A1C2B", posts.PrimaryKey.Name)
+	require.Empty(t, posts.PrimaryKey.Attrs)
+	require.Len(t, posts.Indexes, 1)
+	require.Equal(t, "posts_author", posts.Indexes[0].Name)
+	require.False(t, posts.Indexes[0].Unique)
+	require.Equal(t, []schema.Attr{&IndexPredicate{P: "[author_id] IS NOT NULL"}}, posts.Indexes[0].Attrs)
+	require.Len(t, posts.ForeignKeys, 1)
+	fk := posts.ForeignKeys[0]
+	require.Equal(t, "posts_author", fk.Symbol)
+	require.Equal(t, users, fk.RefTable)
+	require.Equal(t, schema.Cascade, fk.OnDelete)
+
+	var c schema.Comment
+	require.True(t, sqlx.Has(users.Attrs, &c))
+	require.Equal(t, "users table", c.Text)
+	require.Equal(t, []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "int", Type: &schema.IntegerType{T: TypeInt}}, Attrs: []schema.Attr{&Identity{Seed: 100, Increment: 10}}},
+		{Name: "name", Type: &schema.ColumnType{Raw: "nvarchar(100)", Type: &schema.StringType{T: TypeNVarchar, Size: 100}}, Default: &schema.Literal{V: "N'unknown'"}, Attrs: []schema.Attr{&DefaultConstraint{Name: "DF_users_name"}, &schema.Comment{Text: "user name"}}},
+		{Name: "price", Type: &schema.ColumnType{Raw: "decimal(10,2)", Type: &schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}, Null: true}, Default: &schema.Literal{V: "0"}, Attrs: []schema.Attr{&DefaultConstraint{Name: "DF__users__price__38996AB5"}}},
+		{Name: "created_at", Type: &schema.ColumnType{Raw: "datetime2(7)", Type: &schema.TimeType{T: TypeDateTime2}}, Default: &schema.RawExpr{X: "sysdatetime()"}, Attrs: []schema.Attr{&DefaultConstraint{Name: "DF__users__created__398D8EEE"}}},
+		{Name: "total", Type: &schema.ColumnType{Raw: "decimal(11,2)", Type: &schema.DecimalType{T: TypeDecimal, Precision: 11, Scale: 2}, Null: true}, Attrs: []schema.Attr{&schema.GeneratedExpr{Expr: "[price]*(2)", Type: persisted}}},
+	}, dropIndexes(users.Columns))
+	require.Equal(t, "users_pk", users.PrimaryKey.Name)
+	require.Equal(t, []schema.Attr{&IndexType{T: nonClustered}}, users.PrimaryKey.Attrs)
+	require.Len(t, users.Indexes, 2)
+	require.Equal(t, "users_name", users.Indexes[0].Name)
+	require.True(t, users.Indexes[0].Unique)
+	require.True(t, isConstraint(users.Indexes[0]))
+	idx := users.Indexes[1]
+	require.Equal(t, "users_price", idx.Name)
+	require.Equal(t, []schema.Attr{&IndexType{T: clustered}}, idx.Attrs)
+	require.True(t, idx.Parts[0].Desc)
+	require.Equal(t, []schema.Attr{&c, &schema.Check{Name: "users_price", Expr: "[price]>(0)"}}, schema.RemoveAttr[*schema.Checksum](users.Attrs))
+}
+
+func TestUnwrap(t *testing.T) {
+	for x, want := range map[string]string{
+		"((0))":              "0",
+		"(N'a')":             "N'a'",
+		"(getdate())":        "getdate()",
+		"([a]+(1))":          "[a]+(1)",
+		"([a]>(0)) OR ([b])": "([a]>(0)) OR ([b])",
+		"(a)+(b)":            "(a)+(b)",
+		"('(')":              "'('",
+	} {
+		require.Equal(t, want, unwrap(x), x)
+	}
+}
+
+func dropIndexes(columns []*schema.Column) []*schema.Column {
+	for _, c := range columns {
+		c.Indexes, c.ForeignKeys = nil, nil
+	}
+	return columns
+}
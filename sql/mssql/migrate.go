@@ -0,0 +1,887 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for SQL Server dialects.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows, current: defaultSchema, version: "16.0.1000.6"}}
+
+// A planApply provides migration capabilities for schema elements.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// DDL statements in SQL Server are transactional.
+			Transactional: true,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := identChecker(s.PlanOptions).CheckChanges(changes); err != nil {
+		return nil, err
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It is not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan for applying the
+// given changes on the attached connection.
+func (s *state) plan(changes []schema.Change) error {
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	planned, drops, err := s.topLevel(changes)
+	if err != nil {
+		return err
+	}
+	if s.PlanOptions.Mode != migrate.PlanModeUnsortedDump {
+		planned, err = sqlx.DetachCycles(planned)
+		if err != nil {
+			return err
+		}
+		planned = sqlx.SortChanges(planned, nil)
+	}
+	for _, c := range planned {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			err = s.modifyTable(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		default:
+			err = fmt.Errorf("unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// Schemas are dropped after their objects.
+	for _, c := range drops {
+		s.append(&migrate.Change{
+			Cmd:     s.Build("DROP SCHEMA").Ident(c.S.Name).String(),
+			Source:  c,
+			Comment: fmt.Sprintf("drop schema named %q", c.S.Name),
+		})
+	}
+	return nil
+}
+
+// topLevel appends first the changes for creating schemas (top-level schema elements),
+// and returns the schemas to drop, as they can be dropped only after their objects.
+func (s *state) topLevel(changes []schema.Change) ([]schema.Change, []*schema.DropSchema, error) {
+	var (
+		drops   []*schema.DropSchema
+		planned = make([]schema.Change, 0, len(changes))
+	)
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.append(&migrate.Change{
+				Cmd:     s.Build("CREATE SCHEMA").Ident(c.S.Name).String(),
+				Source:  c,
+				Reverse: s.Build("DROP SCHEMA").Ident(c.S.Name).String(),
+				Comment: fmt.Sprintf("add new schema named %q", c.S.Name),
+			})
+		case *schema.DropSchema:
+			drops = append(drops, c)
+		case *schema.ModifySchema:
+			if len(c.Changes) > 0 {
+				return nil, nil, fmt.Errorf("unsupported schema changes for %q", c.S.Name)
+			}
+		default:
+			planned = append(planned, c)
+		}
+	}
+	return planned, drops, nil
+}
+
+// addTable builds and appends the migration changes
+// for creating a table in a schema.
+func (s *state) addTable(add *schema.AddTable) error {
+	var (
+		errs []string
+		b    = s.Build("CREATE TABLE").Table(add.T)
+	)
+	if len(add.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns", add.T.Name)
+	}
+	b.WrapIndent(func(b *sqlx.Builder) {
+		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
+			if err := s.column(b, add.T.Columns[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		})
+		if pk := add.T.PrimaryKey; pk != nil {
+			b.Comma().NL()
+			if err := s.primaryKey(b, pk); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		for _, idx := range add.T.Indexes {
+			if isConstraint(idx) {
+				b.Comma().NL()
+				if err := s.unique(b, idx); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+		}
+		for _, fk := range add.T.ForeignKeys {
+			b.Comma().NL()
+			s.fk(b, fk)
+		}
+		for _, attr := range add.T.Attrs {
+			if c, ok := attr.(*schema.Check); ok {
+				b.Comma().NL()
+				check(b, c)
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	for _, idx := range add.T.Indexes {
+		if !isConstraint(idx) {
+			if err := s.addIndex(add.T, idx); err != nil {
+				return err
+			}
+		}
+	}
+	s.tableComments(add)
+	return nil
+}
+
+// dropTable builds and appends the migrate.Change
+// for dropping a table from a schema.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	b.Table(drop.T)
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+		// The reverse of 'DROP TABLE' might be a multi
+		// statement operation. e.g., table with indexes.
+		Reverse: func() any {
+			cmd := make([]string, len(rs.Changes))
+			for i, c := range rs.Changes {
+				cmd[i] = c.Cmd
+			}
+			if len(cmd) == 1 {
+				return cmd[0]
+			}
+			return cmd
+		}(),
+	})
+	return nil
+}
+
+// modifyTable builds and appends the migration changes for
+// bringing the table into its modified state. Unlike other
+// databases, each change is executed in a separate statement.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	if len(modify.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
+	}
+	var changes [2][]schema.Change
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		// Constraints and indexes modifications are translated into 2 steps.
+		// Dropping the current one and creating a new one.
+		case *schema.ModifyForeignKey:
+			changes[0] = append(changes[0], &schema.DropForeignKey{F: change.From})
+			changes[1] = append(changes[1], &schema.AddForeignKey{F: change.To})
+		case *schema.ModifyIndex:
+			changes[0] = append(changes[0], &schema.DropIndex{I: change.From})
+			changes[1] = append(changes[1], &schema.AddIndex{I: change.To})
+		case *schema.ModifyPrimaryKey:
+			changes[0] = append(changes[0], &schema.DropPrimaryKey{P: change.From})
+			changes[1] = append(changes[1], &schema.AddPrimaryKey{P: change.To})
+		case *schema.ModifyCheck:
+			changes[0] = append(changes[0], &schema.DropCheck{C: change.From})
+			changes[1] = append(changes[1], &schema.AddCheck{C: change.To})
+		case *schema.DropForeignKey, *schema.DropIndex, *schema.DropPrimaryKey, *schema.DropCheck:
+			changes[0] = append(changes[0], change)
+		default:
+			changes[1] = append(changes[1], change)
+		}
+	}
+	for _, c := range append(changes[0], changes[1]...) {
+		if err := s.alterTable(modify.T, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterTable appends the statement for applying the given change on the table.
+func (s *state) alterTable(t *schema.Table, change schema.Change) error {
+	var (
+		b       = s.Build("ALTER TABLE").Table(t)
+		r       = b.Clone()
+		reverse = true
+		comment = fmt.Sprintf("modify %q table", t.Name)
+	)
+	switch change := change.(type) {
+	case *schema.AddColumn:
+		if err := s.column(b.P("ADD"), change.C); err != nil {
+			return err
+		}
+		r.P("DROP COLUMN").Ident(change.C.Name)
+		comment = fmt.Sprintf("add column %q to table: %q", change.C.Name, t.Name)
+		if c := (schema.Comment{}); sqlx.Has(change.C.Attrs, &c) && c.Text != "" {
+			defer s.columnComment(t, change.C, change, "", c.Text)
+		}
+	case *schema.DropColumn:
+		// Columns with a default value cannot be dropped before their DEFAULT constraint.
+		rc := change.C
+		if dc := (DefaultConstraint{}); change.C.Default != nil && sqlx.Has(change.C.Attrs, &dc) {
+			if err := s.dropDefault(t, change.C, dc.Name, change); err != nil {
+				return err
+			}
+			rc = &schema.Column{Name: change.C.Name, Type: change.C.Type, Attrs: change.C.Attrs}
+		}
+		b.P("DROP COLUMN").Ident(change.C.Name)
+		if err := s.column(r.P("ADD"), rc); err != nil {
+			return err
+		}
+		comment = fmt.Sprintf("drop column %q from table: %q", change.C.Name, t.Name)
+	case *schema.ModifyColumn:
+		return s.modifyColumn(t, change)
+	case *schema.RenameColumn:
+		s.rename(change, s.Build().TableColumn(t, change.From), change.To.Name, s.Build().TableColumn(t, change.To), change.From.Name, "COLUMN")
+		return nil
+	case *schema.AddIndex:
+		if !isConstraint(change.I) {
+			return s.addIndex(t, change.I)
+		}
+		if err := s.unique(b.P("ADD"), change.I); err != nil {
+			return err
+		}
+		r.P("DROP CONSTRAINT").Ident(change.I.Name)
+	case *schema.DropIndex:
+		if !isConstraint(change.I) {
+			return s.dropIndex(t, change.I)
+		}
+		b.P("DROP CONSTRAINT").Ident(change.I.Name)
+		if err := s.unique(r.P("ADD"), change.I); err != nil {
+			return err
+		}
+	case *schema.RenameIndex:
+		// Renaming the index of a UNIQUE constraint renames the constraint as well.
+		s.rename(change, s.Build().TableResource(t, change.From), change.To.Name, s.Build().TableResource(t, change.To), change.From.Name, "INDEX")
+		return nil
+	case *schema.AddPrimaryKey:
+		if err := s.primaryKey(b.P("ADD"), change.P); err != nil {
+			return err
+		}
+		r.P("DROP CONSTRAINT").Ident(change.P.Name)
+		reverse = change.P.Name != ""
+	case *schema.DropPrimaryKey:
+		if change.P.Name == "" {
+			return fmt.Errorf("alter table %q: cannot drop unnamed primary key", t.Name)
+		}
+		b.P("DROP CONSTRAINT").Ident(change.P.Name)
+		if err := s.primaryKey(r.P("ADD"), change.P); err != nil {
+			return err
+		}
+	case *schema.AddForeignKey:
+		s.fk(b.P("ADD"), change.F)
+		r.P("DROP CONSTRAINT").Ident(change.F.Symbol)
+	case *schema.DropForeignKey:
+		b.P("DROP CONSTRAINT").Ident(change.F.Symbol)
+		s.fk(r.P("ADD"), change.F)
+	case *schema.AddCheck:
+		check(b.P("ADD"), change.C)
+		r.P("DROP CONSTRAINT").Ident(change.C.Name)
+		// Reverse operation is supported if
+		// the constraint name is not generated.
+		reverse = change.C.Name != ""
+	case *schema.DropCheck:
+		if change.C.Name == "" {
+			return fmt.Errorf("alter table %q: cannot drop unnamed check constraint", t.Name)
+		}
+		b.P("DROP CONSTRAINT").Ident(change.C.Name)
+		check(r.P("ADD"), change.C)
+	case *schema.AddAttr, *schema.ModifyAttr, *schema.DropAttr:
+		return s.tableComment(t, change)
+	default:
+		return fmt.Errorf("alter table %q: unsupported change %T", t.Name, change)
+	}
+	c := &migrate.Change{
+		Cmd:     b.String(),
+		Source:  &schema.ModifyTable{T: t, Changes: []schema.Change{change}},
+		Comment: comment,
+	}
+	if reverse {
+		c.Reverse = r.String()
+	}
+	s.append(c)
+	return nil
+}
+
+// modifyColumn appends the statements for modifying a column. In SQL Server,
+// the ALTER COLUMN clause changes only the type and the nullability of the
+// column, and the default value is a constraint that is changed separately.
+func (s *state) modifyColumn(t *schema.Table, change *schema.ModifyColumn) error {
+	from, to := change.From, change.To
+	switch {
+	case change.Change.Is(schema.ChangeGenerated):
+		return fmt.Errorf("alter table %q: changing the computed expression of column %q is not supported", t.Name, to.Name)
+	case change.Change.Is(schema.ChangeAttr):
+		return fmt.Errorf("alter table %q: changing the identity property of column %q is not supported", t.Name, to.Name)
+	}
+	if change.Change.Is(schema.ChangeDefault) && from.Default != nil {
+		var dc DefaultConstraint
+		if !sqlx.Has(from.Attrs, &dc) {
+			return fmt.Errorf("alter table %q: cannot drop the unnamed default constraint of column %q", t.Name, from.Name)
+		}
+		if err := s.dropDefault(t, from, dc.Name, change); err != nil {
+			return err
+		}
+	}
+	if change.Change.Is(schema.ChangeType) || change.Change.Is(schema.ChangeNull) {
+		b := s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN")
+		if err := s.columnType(b.Ident(to.Name), to); err != nil {
+			return fmt.Errorf("alter table %q: %w", t.Name, err)
+		}
+		r := s.Build("ALTER TABLE").Table(t).P("ALTER COLUMN")
+		if err := s.columnType(r.Ident(from.Name), from); err != nil {
+			return fmt.Errorf("alter table %q: %w", t.Name, err)
+		}
+		s.append(&migrate.Change{
+			Cmd:     nullable(b, to).String(),
+			Source:  change,
+			Reverse: nullable(r, from).String(),
+			Comment: fmt.Sprintf("modify column %q of table: %q", to.Name, t.Name),
+		})
+	}
+	if change.Change.Is(schema.ChangeDefault) && to.Default != nil {
+		if err := s.addDefault(t, to, change); err != nil {
+			return err
+		}
+	}
+	if change.Change.Is(schema.ChangeComment) {
+		var c1, c2 schema.Comment
+		sqlx.Has(from.Attrs, &c1)
+		sqlx.Has(to.Attrs, &c2)
+		s.columnComment(t, to, change, c1.Text, c2.Text)
+	}
+	return nil
+}
+
+// addDefault appends the statement for adding a DEFAULT constraint to the column.
+func (s *state) addDefault(t *schema.Table, c *schema.Column, source schema.Change) error {
+	var (
+		dc   DefaultConstraint
+		b    = s.Build("ALTER TABLE").Table(t).P("ADD")
+		name = sqlx.Has(c.Attrs, &dc) && !reGeneratedName.MatchString(dc.Name)
+	)
+	if name {
+		b.P("CONSTRAINT").Ident(dc.Name)
+	}
+	if err := s.columnDefault(b, c); err != nil {
+		return fmt.Errorf("alter table %q: %w", t.Name, err)
+	}
+	change := &migrate.Change{
+		Cmd:     b.P("FOR").Ident(c.Name).String(),
+		Source:  source,
+		Comment: fmt.Sprintf("set default value of column %q of table: %q", c.Name, t.Name),
+	}
+	// Reverse operation is supported if
+	// the constraint name is not generated.
+	if name {
+		change.Reverse = s.Build("ALTER TABLE").Table(t).P("DROP CONSTRAINT").Ident(dc.Name).String()
+	}
+	s.append(change)
+	return nil
+}
+
+// dropDefault appends the statement for dropping the DEFAULT constraint of the column.
+func (s *state) dropDefault(t *schema.Table, c *schema.Column, name string, source schema.Change) error {
+	r := s.Build("ALTER TABLE").Table(t).P("ADD CONSTRAINT").Ident(name)
+	if err := s.columnDefault(r, c); err != nil {
+		return fmt.Errorf("alter table %q: %w", t.Name, err)
+	}
+	s.append(&migrate.Change{
+		Cmd:     s.Build("ALTER TABLE").Table(t).P("DROP CONSTRAINT").Ident(name).String(),
+		Source:  source,
+		Reverse: r.P("FOR").Ident(c.Name).String(),
+		Comment: fmt.Sprintf("drop default value of column %q of table: %q", c.Name, t.Name),
+	})
+	return nil
+}
+
+// renameTable appends the statement for renaming a table.
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.rename(c, s.Build().Table(c.From), c.To.Name, s.Build().Table(c.To), c.From.Name, "")
+}
+
+// rename appends the sp_rename statement for renaming the given object.
+// The new name of the object cannot be qualified with the schema name.
+func (s *state) rename(source schema.Change, from *sqlx.Builder, to string, rfrom *sqlx.Builder, rto, typ string) {
+	build := func(name *sqlx.Builder, to string) string {
+		b := s.Build("EXEC sp_rename").Quote("N", func(b *sqlx.Builder) {
+			b.WriteString(strings.ReplaceAll(strings.TrimSpace(name.String()), "'", "''"))
+		})
+		b.Comma().Quote("N", func(b *sqlx.Builder) {
+			b.WriteString(strings.ReplaceAll(to, "'", "''"))
+		})
+		if typ != "" {
+			b.Comma().Quote("N", func(b *sqlx.Builder) {
+				b.WriteString(typ)
+			})
+		}
+		return b.String()
+	}
+	var comment string
+	switch c := source.(type) {
+	case *schema.RenameTable:
+		comment = fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name)
+	case *schema.RenameColumn:
+		comment = fmt.Sprintf("rename a column from %q to %q", c.From.Name, c.To.Name)
+	case *schema.RenameIndex:
+		comment = fmt.Sprintf("rename an index from %q to %q", c.From.Name, c.To.Name)
+	}
+	s.append(&migrate.Change{
+		Cmd:     build(from, to),
+		Source:  source,
+		Reverse: build(rfrom, rto),
+		Comment: comment,
+	})
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	b.Ident(c.Name)
+	var (
+		id     Identity
+		x      schema.GeneratedExpr
+		hasI   = sqlx.Has(c.Attrs, &id)
+		hasX   = sqlx.Has(c.Attrs, &x)
+		hasDef = c.Default != nil
+	)
+	switch {
+	case hasI && (hasX || hasDef):
+		return fmt.Errorf("identity column %q cannot have a default value or a computed expression", c.Name)
+	case hasX && hasDef:
+		return fmt.Errorf("computed column %q cannot have a default value", c.Name)
+	// Computed columns are defined without a type, and
+	// only persisted columns can be defined as NOT NULL.
+	case hasX:
+		b.P("AS", sqlx.MayWrap(x.Expr))
+		if generatedType(x.Type) == persisted {
+			b.P(persisted)
+			if !c.Type.Null {
+				b.P("NOT NULL")
+			}
+		}
+		return nil
+	}
+	if err := s.columnType(b, c); err != nil {
+		return err
+	}
+	switch {
+	case hasI:
+		seed, incr := identityValues(id)
+		b.P(fmt.Sprintf("IDENTITY(%d,%d)", seed, incr))
+	case hasDef:
+		if dc := (DefaultConstraint{}); sqlx.Has(c.Attrs, &dc) && !reGeneratedName.MatchString(dc.Name) {
+			b.P("CONSTRAINT").Ident(dc.Name)
+		}
+		if err := s.columnDefault(b, c); err != nil {
+			return err
+		}
+	}
+	nullable(b, c)
+	return nil
+}
+
+// nullable writes the nullability of the column to the builder. Unlike other
+// databases, the default nullability of columns in SQL Server depends on the
+// session settings, and therefore it is always written explicitly.
+func nullable(b *sqlx.Builder, c *schema.Column) *sqlx.Builder {
+	if !c.Type.Null {
+		b.P("NOT")
+	}
+	return b.P("NULL")
+}
+
+// columnType writes the column type to the builder.
+func (*state) columnType(b *sqlx.Builder, c *schema.Column) error {
+	t, err := FormatType(c.Type.Type)
+	if err != nil {
+		return fmt.Errorf("format type for column %q: %w", c.Name, err)
+	}
+	b.P(t)
+	return nil
+}
+
+// columnDefault writes the default value of column to the builder.
+func (*state) columnDefault(b *sqlx.Builder, c *schema.Column) error {
+	switch x := c.Default.(type) {
+	case *schema.Literal:
+		v := x.V
+		switch c.Type.Type.(type) {
+		case *schema.BoolType, *schema.DecimalType, *schema.IntegerType, *schema.FloatType:
+		default:
+			// Unicode string literals are kept as is.
+			if len(v) > 1 && (v[0] == 'N' || v[0] == 'n') && sqlx.IsQuoted(v[1:], '\'') {
+				break
+			}
+			var err error
+			if v, err = sqlx.SingleQuote(v); err != nil {
+				return err
+			}
+		}
+		b.P("DEFAULT", v)
+	case *schema.RawExpr:
+		b.P("DEFAULT", x.X)
+	default:
+		return fmt.Errorf("unexpected default value type: %T", x)
+	}
+	return nil
+}
+
+// primaryKey writes the PRIMARY KEY constraint to the builder.
+func (*state) primaryKey(b *sqlx.Builder, pk *schema.Index) error {
+	if pk.Name != "" && !reGeneratedName.MatchString(pk.Name) {
+		b.P("CONSTRAINT").Ident(pk.Name)
+	}
+	b.P("PRIMARY KEY")
+	if t := (IndexType{}); sqlx.Has(pk.Attrs, &t) && strings.EqualFold(t.T, nonClustered) {
+		b.P(nonClustered)
+	}
+	return indexParts(b, pk)
+}
+
+// unique writes the UNIQUE constraint to the builder.
+func (*state) unique(b *sqlx.Builder, idx *schema.Index) error {
+	if idx.Name != "" && !reGeneratedName.MatchString(idx.Name) {
+		b.P("CONSTRAINT").Ident(idx.Name)
+	}
+	b.P("UNIQUE")
+	if t := (IndexType{}); sqlx.Has(idx.Attrs, &t) && strings.EqualFold(t.T, clustered) {
+		b.P(clustered)
+	}
+	return indexParts(b, idx)
+}
+
+// fk writes the FOREIGN KEY constraint to the builder.
+func (*state) fk(b *sqlx.Builder, fk *schema.ForeignKey) {
+	if fk.Symbol != "" {
+		b.P("CONSTRAINT").Ident(fk.Symbol)
+	}
+	b.P("FOREIGN KEY")
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(fk.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(fk.Columns[i].Name)
+		})
+	})
+	b.P("REFERENCES").Table(fk.RefTable)
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(fk.RefColumns, func(i int, b *sqlx.Builder) {
+			b.Ident(fk.RefColumns[i].Name)
+		})
+	})
+	// SQL Server does not support RESTRICT, and
+	// its default action is equivalent to NO ACTION.
+	if fk.OnUpdate != "" && fk.OnUpdate != schema.NoAction && fk.OnUpdate != schema.Restrict {
+		b.P("ON UPDATE", string(fk.OnUpdate))
+	}
+	if fk.OnDelete != "" && fk.OnDelete != schema.NoAction && fk.OnDelete != schema.Restrict {
+		b.P("ON DELETE", string(fk.OnDelete))
+	}
+}
+
+// addIndex appends the statement for creating the given index.
+func (s *state) addIndex(t *schema.Table, idx *schema.Index) error {
+	b := s.Build("CREATE")
+	if idx.Unique {
+		b.P("UNIQUE")
+	}
+	if it := (IndexType{}); sqlx.Has(idx.Attrs, &it) && strings.EqualFold(it.T, clustered) {
+		b.P(clustered)
+	}
+	b.P("INDEX").Ident(idx.Name).P("ON").Table(t)
+	if err := indexParts(b, idx); err != nil {
+		return fmt.Errorf("create index %q: %w", idx.Name, err)
+	}
+	if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) && p.P != "" {
+		b.P("WHERE", unwrap(p.P))
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  &schema.AddIndex{I: idx},
+		Reverse: s.Build("DROP INDEX").Ident(idx.Name).P("ON").Table(t).String(),
+		Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
+	})
+	return nil
+}
+
+// dropIndex appends the statement for dropping the given index.
+func (s *state) dropIndex(t *schema.Table, idx *schema.Index) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addIndex(t, idx); err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Cmd:     rs.Changes[0].Reverse.(string),
+		Source:  &schema.DropIndex{I: idx},
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop index %q from table: %q", idx.Name, t.Name),
+	})
+	return nil
+}
+
+// tableComments appends the statements for setting the (non-empty)
+// comments of the created table and its columns.
+func (s *state) tableComments(add *schema.AddTable) {
+	var c schema.Comment
+	if sqlx.Has(add.T.Attrs, &c) && c.Text != "" {
+		s.comment(add.T, nil, add, "", c.Text)
+	}
+	for _, col := range add.T.Columns {
+		if c := (schema.Comment{}); sqlx.Has(col.Attrs, &c) && c.Text != "" {
+			s.columnComment(add.T, col, add, "", c.Text)
+		}
+	}
+}
+
+// columnComment appends the statement for changing the column comment.
+func (s *state) columnComment(t *schema.Table, c *schema.Column, source schema.Change, from, to string) {
+	s.comment(t, c, source, from, to)
+}
+
+// tableComment appends the statement for changing the table comment.
+func (s *state) tableComment(t *schema.Table, change schema.Change) error {
+	var from, to schema.Comment
+	switch change := change.(type) {
+	case *schema.AddAttr:
+		c, ok := change.A.(*schema.Comment)
+		if !ok {
+			return fmt.Errorf("unexpected table attribute %T", change.A)
+		}
+		to = *c
+	case *schema.DropAttr:
+		c, ok := change.A.(*schema.Comment)
+		if !ok {
+			return fmt.Errorf("unexpected table attribute %T", change.A)
+		}
+		from = *c
+	case *schema.ModifyAttr:
+		c1, ok1 := change.From.(*schema.Comment)
+		c2, ok2 := change.To.(*schema.Comment)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("unexpected table attribute change from %T to %T", change.From, change.To)
+		}
+		from, to = *c1, *c2
+	}
+	s.comment(t, nil, change, from.Text, to.Text)
+	return nil
+}
+
+// comment appends the statement for changing the comment of a table, or
+// one of its columns. Comments are stored as the MS_Description extended
+// property, that is added, updated or dropped based on the change.
+func (s *state) comment(t *schema.Table, c *schema.Column, source schema.Change, from, to string) {
+	if from == to {
+		return
+	}
+	change := &migrate.Change{
+		Cmd:     s.commentProc(t, c, from, to),
+		Source:  source,
+		Reverse: s.commentProc(t, c, to, from),
+		Comment: fmt.Sprintf("set comment to table: %q", t.Name),
+	}
+	if c != nil {
+		change.Comment = fmt.Sprintf("set comment to column: %q on table: %q", c.Name, t.Name)
+	}
+	s.append(change)
+}
+
+// commentProc returns the statement for changing the MS_Description extended
+// property of the table (or its column) from one value to the other.
+func (s *state) commentProc(t *schema.Table, c *schema.Column, from, to string) string {
+	var b *sqlx.Builder
+	switch {
+	case from == "":
+		b = s.Build("EXEC sp_addextendedproperty").P("@name =", "N"+quote(descProperty)).Comma().P("@value =", "N"+quote(to)).Comma()
+	case to == "":
+		b = s.Build("EXEC sp_dropextendedproperty").P("@name =", "N"+quote(descProperty)).Comma()
+	default:
+		b = s.Build("EXEC sp_updateextendedproperty").P("@name =", "N"+quote(descProperty)).Comma().P("@value =", "N"+quote(to)).Comma()
+	}
+	b.P("@level0type = N'SCHEMA', @level0name =", "N"+quote(s.schemaName(t))).Comma().
+		P("@level1type = N'TABLE', @level1name =", "N"+quote(t.Name))
+	if c != nil {
+		b.Comma().P("@level2type = N'COLUMN', @level2name =", "N"+quote(c.Name))
+	}
+	return b.String()
+}
+
+// schemaName returns the name of the schema that the table belongs to,
+// as extended properties are always qualified with their schema name.
+func (s *state) schemaName(t *schema.Table) string {
+	switch {
+	case s.SchemaQualifier != nil && *s.SchemaQualifier != "":
+		return *s.SchemaQualifier
+	case s.SchemaQualifier == nil && t.Schema != nil && t.Schema.Name != "":
+		return t.Schema.Name
+	default:
+		return s.bound()
+	}
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	return (*Driver).StmtBuilder(nil, s.PlanOptions).
+		P(phrases...)
+}
+
+// indexParts writes the index parts to the builder.
+func indexParts(b *sqlx.Builder, idx *schema.Index) (err error) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(idx.Parts, func(i int, b *sqlx.Builder) {
+			switch part := idx.Parts[i]; {
+			case part.C != nil:
+				b.Ident(part.C.Name)
+			case part.X != nil:
+				err = fmt.Errorf("index %q: expression parts are not supported by SQL Server", idx.Name)
+			}
+			if idx.Parts[i].Desc {
+				b.P("DESC")
+			}
+		})
+	})
+	return err
+}
+
+// check writes the CHECK constraint to the builder.
+func check(b *sqlx.Builder, c *schema.Check) {
+	if c.Name != "" && !reGeneratedName.MatchString(c.Name) {
+		b.P("CONSTRAINT").Ident(c.Name)
+	}
+	b.P("CHECK", sqlx.MayWrap(c.Expr))
+}
+
+// isConstraint reports if the index is a UNIQUE constraint.
+func isConstraint(idx *schema.Index) bool {
+	c := &Constraint{}
+	return idx.Unique && sqlx.Has(idx.Attrs, c) && c.T == "U"
+}
+
+// quote returns the given string as an SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// identChecker returns the checker of the identifiers used by the planned changes.
+func identChecker(opts migrate.PlanOptions) *sqlx.IdentChecker {
+	c := &sqlx.IdentChecker{
+		MaxLen: maxIdentLen,
+		Len:    func(s string) int { return len([]rune(s)) },
+		Unit:   "characters",
+	}
+	if opts.CheckReserved {
+		c.Reserved = func(name string) bool {
+			_, ok := reservedWords[strings.ToLower(name)]
+			return ok
+		}
+	}
+	return c
+}
+
+// reservedWords holds the reserved keywords of Transact-SQL.
+// https://learn.microsoft.com/en-us/sql/t-sql/language-elements/reserved-keywords-transact-sql
+var reservedWords = map[string]struct{}{
+	"add": {}, "all": {}, "alter": {}, "and": {}, "any": {}, "as": {}, "asc": {}, "authorization": {},
+	"backup": {}, "begin": {}, "between": {}, "break": {}, "browse": {}, "bulk": {}, "by": {},
+	"cascade": {}, "case": {}, "check": {}, "checkpoint": {}, "close": {}, "clustered": {},
+	"coalesce": {}, "collate": {}, "column": {}, "commit": {}, "compute": {}, "constraint": {},
+	"contains": {}, "containstable": {}, "continue": {}, "convert": {}, "create": {}, "cross": {},
+	"current": {}, "current_date": {}, "current_time": {}, "current_timestamp": {}, "current_user": {},
+	"cursor": {}, "database": {}, "dbcc": {}, "deallocate": {}, "declare": {}, "default": {},
+	"delete": {}, "deny": {}, "desc": {}, "disk": {}, "distinct": {}, "distributed": {}, "double": {},
+	"drop": {}, "dump": {}, "else": {}, "end": {}, "errlvl": {}, "escape": {}, "except": {}, "exec": {},
+	"execute": {}, "exists": {}, "exit": {}, "external": {}, "fetch": {}, "file": {}, "fillfactor": {},
+	"for": {}, "foreign": {}, "freetext": {}, "freetexttable": {}, "from": {}, "full": {}, "function": {},
+	"goto": {}, "grant": {}, "group": {}, "having": {}, "holdlock": {}, "identity": {},
+	"identity_insert": {}, "identitycol": {}, "if": {}, "in": {}, "index": {}, "inner": {}, "insert": {},
+	"intersect": {}, "into": {}, "is": {}, "join": {}, "key": {}, "kill": {}, "left": {}, "like": {},
+	"lineno": {}, "load": {}, "merge": {}, "national": {}, "nocheck": {}, "nonclustered": {}, "not": {},
+	"null": {}, "nullif": {}, "of": {}, "off": {}, "offsets": {}, "on": {}, "open": {},
+	"opendatasource": {}, "openquery": {}, "openrowset": {}, "openxml": {}, "option": {}, "or": {},
+	"order": {}, "outer": {}, "over": {}, "percent": {}, "pivot": {}, "plan": {}, "precision": {},
+	"primary": {}, "print": {}, "proc": {}, "procedure": {}, "public": {}, "raiserror": {}, "read": {},
+	"readtext": {}, "reconfigure": {}, "references": {}, "replication": {}, "restore": {}, "restrict": {},
+	"return": {}, "revert": {}, "revoke": {}, "right": {}, "rollback": {}, "rowcount": {}, "rowguidcol": {},
+	"rule": {}, "save": {}, "schema": {}, "securityaudit": {}, "select": {},
+	"semantickeyphrasetable": {}, "semanticsimilaritydetailstable": {}, "semanticsimilaritytable": {},
+	"session_user": {}, "set": {}, "setuser": {}, "shutdown": {}, "some": {}, "statistics": {},
+	"system_user": {}, "table": {}, "tablesample": {}, "textsize": {}, "then": {}, "to": {}, "top": {},
+	"tran": {}, "transaction": {}, "trigger": {}, "truncate": {}, "try_convert": {}, "tsequal": {},
+	"union": {}, "unique": {}, "unpivot": {}, "update": {}, "updatetext": {}, "use": {}, "user": {},
+	"values": {}, "varying": {}, "view": {}, "waitfor": {}, "when": {}, "where": {}, "while": {},
+	"with": {}, "within group": {}, "writetext": {},
+}
@@ -0,0 +1,230 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("app")).
+		AddColumns(
+			schema.NewIntColumn("id", TypeInt).AddAttrs(&Identity{Seed: 1, Increment: 1}),
+			schema.NewStringColumn("name", TypeNVarchar, schema.StringSize(255)).SetComment("user's name"),
+			schema.NewColumn("price").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}).SetDefault(&schema.Literal{V: "0"}).AddAttrs(&DefaultConstraint{Name: "DF_users_price"}),
+			schema.NewColumn("created_at").SetType(&schema.TimeType{T: TypeDateTime2}).SetDefault(&schema.RawExpr{X: "sysdatetime()"}),
+			schema.NewColumn("total").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 11, Scale: 2}).SetNull(true).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "[price] * 2", Type: persisted}),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]).SetName("users_pk"))
+	users.AddIndexes(
+		schema.NewUniqueIndex("users_name").AddColumns(users.Columns[1]).AddAttrs(&Constraint{T: "U"}),
+		schema.NewIndex("users_created").AddParts(schema.NewColumnPart(users.Columns[3]).SetDesc(true)).AddAttrs(&IndexPredicate{P: "[created_at] IS NOT NULL"}),
+	)
+	users.AddChecks(schema.NewCheck().SetName("users_price").SetExpr("[price] > 0"))
+	users.SetComment("users table")
+	posts := schema.NewTable("posts").
+		SetSchema(users.Schema).
+		AddColumns(
+			schema.NewIntColumn("id", TypeInt),
+			schema.NewIntColumn("author_id", TypeInt),
+		)
+	posts.SetPrimaryKey(schema.NewPrimaryKey(posts.Columns[0]).AddAttrs(&IndexType{T: nonClustered}))
+	posts.AddForeignKeys(
+		schema.NewForeignKey("posts_author").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]).SetOnDelete(schema.Cascade),
+	)
+	tests := []struct {
+		changes []schema.Change
+		options []migrate.PlanOption
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			changes: []schema.Change{&schema.AddSchema{S: schema.New("app")}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE SCHEMA [app]`, Reverse: `DROP SCHEMA [app]`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.DropSchema{S: schema.New("app")}, &schema.DropTable{T: posts}},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: `DROP TABLE [app].[posts]`, Reverse: `CREATE TABLE [app].[posts] ([id] int NOT NULL, [author_id] int NOT NULL, PRIMARY KEY NONCLUSTERED ([id]), CONSTRAINT [posts_author] FOREIGN KEY ([author_id]) REFERENCES [app].[users] ([id]) ON DELETE CASCADE)`},
+					{Cmd: `DROP SCHEMA [app]`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.AddTable{T: users}, &schema.AddTable{T: posts}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE TABLE [app].[users] ([id] int IDENTITY(1,1) NOT NULL, [name] nvarchar(255) NOT NULL, [price] decimal(10,2) CONSTRAINT [DF_users_price] DEFAULT 0 NOT NULL, [created_at] datetime2 DEFAULT sysdatetime() NOT NULL, [total] AS ([price] * 2) PERSISTED, CONSTRAINT [users_pk] PRIMARY KEY ([id]), CONSTRAINT [users_name] UNIQUE ([name]), CONSTRAINT [users_price] CHECK ([price] > 0))`,
+						Reverse: `DROP TABLE [app].[users]`,
+					},
+					{Cmd: `CREATE INDEX [users_created] ON [app].[users] ([created_at] DESC) WHERE [created_at] IS NOT NULL`, Reverse: `DROP INDEX [users_created] ON [app].[users]`},
+					{
+						Cmd:     `EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'users table', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users'`,
+						Reverse: `EXEC sp_dropextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users'`,
+					},
+					{
+						Cmd:     `EXEC sp_addextendedproperty @name = N'MS_Description', @value = N'user''s name', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users', @level2type = N'COLUMN', @level2name = N'name'`,
+						Reverse: `EXEC sp_dropextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users', @level2type = N'COLUMN', @level2name = N'name'`,
+					},
+					{
+						Cmd:     `CREATE TABLE [app].[posts] ([id] int NOT NULL, [author_id] int NOT NULL, PRIMARY KEY NONCLUSTERED ([id]), CONSTRAINT [posts_author] FOREIGN KEY ([author_id]) REFERENCES [app].[users] ([id]) ON DELETE CASCADE)`,
+						Reverse: `DROP TABLE [app].[posts]`,
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewStringColumn("email", TypeVarchar, schema.StringSize(100)).SetNull(true)},
+						&schema.DropCheck{C: users.Attrs[0].(*schema.Check)},
+						&schema.ModifyColumn{
+							From:   users.Columns[2],
+							To:     schema.NewColumn("price").SetType(&schema.DecimalType{T: TypeDecimal, Precision: 12, Scale: 2}).SetNull(true),
+							Change: schema.ChangeType | schema.ChangeNull | schema.ChangeDefault,
+						},
+						&schema.RenameColumn{From: users.Columns[1], To: schema.NewColumn("full_name")},
+						&schema.ModifyAttr{From: &schema.Comment{Text: "users table"}, To: &schema.Comment{Text: "users"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE [app].[users] DROP CONSTRAINT [users_price]`, Reverse: `ALTER TABLE [app].[users] ADD CONSTRAINT [users_price] CHECK ([price] > 0)`},
+					{Cmd: `ALTER TABLE [app].[users] ADD [email] varchar(100) NULL`, Reverse: `ALTER TABLE [app].[users] DROP COLUMN [email]`},
+					{Cmd: `ALTER TABLE [app].[users] DROP CONSTRAINT [DF_users_price]`, Reverse: `ALTER TABLE [app].[users] ADD CONSTRAINT [DF_users_price] DEFAULT 0 FOR [price]`},
+					{Cmd: `ALTER TABLE [app].[users] ALTER COLUMN [price] decimal(12,2) NULL`, Reverse: `ALTER TABLE [app].[users] ALTER COLUMN [price] decimal(10,2) NOT NULL`},
+					{Cmd: `EXEC sp_rename N'[app].[users].[name]', N'full_name', N'COLUMN'`, Reverse: `EXEC sp_rename N'[app].[users].[full_name]', N'name', N'COLUMN'`},
+					{
+						Cmd:     `EXEC sp_updateextendedproperty @name = N'MS_Description', @value = N'users', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users'`,
+						Reverse: `EXEC sp_updateextendedproperty @name = N'MS_Description', @value = N'users table', @level0type = N'SCHEMA', @level0name = N'app', @level1type = N'TABLE', @level1name = N'users'`,
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.DropIndex{I: users.Indexes[0]},
+						&schema.DropIndex{I: users.Indexes[1]},
+						&schema.RenameIndex{From: schema.NewIndex("a"), To: schema.NewIndex("b")},
+					},
+				},
+				&schema.RenameTable{From: posts, To: schema.NewTable("articles").SetSchema(posts.Schema)},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE [app].[users] DROP CONSTRAINT [users_name]`, Reverse: `ALTER TABLE [app].[users] ADD CONSTRAINT [users_name] UNIQUE ([name])`},
+					{Cmd: `DROP INDEX [users_created] ON [app].[users]`, Reverse: `CREATE INDEX [users_created] ON [app].[users] ([created_at] DESC) WHERE [created_at] IS NOT NULL`},
+					{Cmd: `EXEC sp_rename N'[app].[users].[a]', N'b', N'INDEX'`, Reverse: `EXEC sp_rename N'[app].[users].[b]', N'a', N'INDEX'`},
+					{Cmd: `EXEC sp_rename N'[app].[posts]', N'articles'`, Reverse: `EXEC sp_rename N'[app].[articles]', N'posts'`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   users.Columns[3],
+							To:     schema.NewColumn("created_at").SetType(&schema.TimeType{T: TypeDateTime2}).SetDefault(&schema.RawExpr{X: "getdate()"}),
+							Change: schema.ChangeDefault,
+						},
+					},
+				},
+			},
+			wantErr: `cannot drop the unnamed default constraint of column "created_at"`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   users.Columns[0],
+							To:     schema.NewIntColumn("id", TypeInt),
+							Change: schema.ChangeAttr,
+						},
+					},
+				},
+			},
+			wantErr: `changing the identity property of column "id" is not supported`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable(strings.Repeat("t", 129)).AddColumns(schema.NewIntColumn("a", TypeInt))},
+			},
+			wantErr: "exceeds the maximum identifier length of 128 characters",
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("users").AddColumns(schema.NewIntColumn("identity", TypeInt))},
+			},
+			options: []migrate.PlanOption{func(o *migrate.PlanOptions) { o.CheckReserved = true }},
+			wantErr: "reserved",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			db, m, err := sqlmock.New()
+			require.NoError(t, err)
+			mock{m}.version("16.0.1000.6", 3)
+			drv, err := Open(db)
+			require.NoError(t, err)
+			plan, err := drv.PlanChanges(context.Background(), "plan", tt.changes, tt.options...)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.plan.Reversible, plan.Reversible)
+			require.True(t, plan.Transactional)
+			require.Len(t, plan.Changes, len(tt.plan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestDefaultPlan(t *testing.T) {
+	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", TypeInt))},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(changes.Changes))
+	require.Equal(t, `CREATE TABLE [t1] ([a] int NOT NULL)`, changes.Changes[0].Cmd)
+
+	err = DefaultPlan.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", TypeInt))},
+	})
+	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
+}
@@ -0,0 +1,327 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type doc struct {
+	Tables  []*sqlspec.Table  `spec:"table"`
+	Schemas []*sqlspec.Schema `spec:"schema"`
+}
+
+// Codec for schemahcl.
+type Codec struct {
+	State *schemahcl.State
+}
+
+// Eval evaluates an Atlas DDL document into v using the input.
+func (c *Codec) Eval(p *hclparse.Parser, v any, input map[string]cty.Value) error {
+	return c.EvalOptions(p, v, &schemahcl.EvalOptions{Variables: input})
+}
+
+// EvalOptions decodes the HCL with the given options.
+func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptions) error {
+	switch v := v.(type) {
+	case *schema.Realm:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if err := specutil.Scan(v, &specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables}, scanFuncs); err != nil {
+			return fmt.Errorf("mssql: failed converting to *schema.Realm: %w", err)
+		}
+	case *schema.Schema:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if len(d.Schemas) != 1 {
+			return fmt.Errorf("mssql: expecting document to contain a single schema, got %d", len(d.Schemas))
+		}
+		r := &schema.Realm{}
+		if err := specutil.Scan(r, &specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables}, scanFuncs); err != nil {
+			return err
+		}
+		*v = *r.Schemas[0]
+	case schema.Schema, schema.Realm:
+		return fmt.Errorf("mssql: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
+	default:
+		return fmt.Errorf("mssql: unexpected type %T", v)
+	}
+	return nil
+}
+
+// MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
+func (c *Codec) MarshalSpec(v any) ([]byte, error) {
+	return specutil.Marshal(v, c.State, specutil.RealmFuncs{
+		Schema: schemaSpec,
+	})
+}
+
+var scanFuncs = &specutil.ScanFuncs{
+	Table: convertTable,
+}
+
+// convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
+// ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
+// at this point. Instead, the linking is done by the convertSchema function.
+func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
+	return specutil.Table(spec, parent, convertColumn, convertPK, convertIndex, specutil.Check)
+}
+
+// convertPK converts a sqlspec.PrimaryKey into a schema.Index.
+func convertPK(spec *sqlspec.PrimaryKey, parent *schema.Table) (*schema.Index, error) {
+	pk, err := specutil.PrimaryKey(spec, parent)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertIndexType(spec, pk); err != nil {
+		return nil, err
+	}
+	return pk, nil
+}
+
+// convertIndex converts a sqlspec.Index into a schema.Index.
+func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
+	idx, err := specutil.Index(spec, t)
+	if err != nil {
+		return nil, err
+	}
+	if err := convertIndexType(spec, idx); err != nil {
+		return nil, err
+	}
+	if attr, ok := spec.Attr("where"); ok {
+		p, err := attr.String()
+		if err != nil {
+			return nil, err
+		}
+		idx.AddAttrs(&IndexPredicate{P: p})
+	}
+	return idx, nil
+}
+
+func convertIndexType(spec specutil.Attrer, idx *schema.Index) error {
+	if attr, ok := spec.Attr("type"); ok {
+		t, err := attr.String()
+		if err != nil {
+			return err
+		}
+		idx.AddAttrs(&IndexType{T: strings.ToUpper(t)})
+	}
+	return nil
+}
+
+// convertColumn converts a sqlspec.Column into a schema.Column.
+func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
+	c, err := specutil.Column(spec, convertColumnType)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := spec.Extra.Resource("identity"); ok {
+		id := struct {
+			Seed      int64 `spec:"seed"`
+			Increment int64 `spec:"increment"`
+		}{Seed: 1, Increment: 1}
+		if err := r.As(&id); err != nil {
+			return nil, err
+		}
+		c.Attrs = append(c.Attrs, &Identity{Seed: id.Seed, Increment: id.Increment})
+	}
+	if err := specutil.ConvertGenExpr(spec.Remain(), c, generatedType); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// convertColumnType converts a sqlspec.Column into a concrete SQL Server schema.Type.
+func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
+	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
+}
+
+// schemaSpec converts from a concrete SQL Server schema to Atlas specification.
+func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
+	return specutil.FromSchema(s, &specutil.SchemaFuncs{
+		Table: tableSpec,
+	})
+}
+
+// tableSpec converts from a concrete SQL Server sqlspec.Table to a schema.Table.
+func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
+	return specutil.FromTable(
+		t,
+		columnSpec,
+		pkSpec,
+		indexSpec,
+		specutil.FromForeignKey,
+		specutil.FromCheck,
+	)
+}
+
+// pkSpec converts from a concrete SQL Server primary key into a sqlspec.PrimaryKey.
+func pkSpec(idx *schema.Index) (*sqlspec.PrimaryKey, error) {
+	spec, err := specutil.FromPrimaryKey(idx)
+	if err != nil {
+		return nil, err
+	}
+	// Avoid printing the index type if it is the default.
+	if t := (IndexType{}); sqlx.Has(idx.Attrs, &t) && strings.EqualFold(t.T, nonClustered) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("type", nonClustered))
+	}
+	return spec, nil
+}
+
+// indexSpec converts from a concrete SQL Server schema.Index into a sqlspec.Index.
+func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
+	spec, err := specutil.FromIndex(idx)
+	if err != nil {
+		return nil, err
+	}
+	// Avoid printing the index type if it is the default.
+	if t := (IndexType{}); sqlx.Has(idx.Attrs, &t) && strings.EqualFold(t.T, clustered) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("type", clustered))
+	}
+	if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) && p.P != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, specutil.VarAttr("where", strconv.Quote(p.P)))
+	}
+	return spec, nil
+}
+
+// columnSpec converts from a concrete SQL Server schema.Column into a sqlspec.Column.
+func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
+	s, err := specutil.FromColumn(c, columnTypeSpec)
+	if err != nil {
+		return nil, err
+	}
+	if i := (Identity{}); sqlx.Has(c.Attrs, &i) {
+		seed, incr := identityValues(i)
+		s.Extra.Children = append(s.Extra.Children, &schemahcl.Resource{
+			Type: "identity",
+			Attrs: []*schemahcl.Attr{
+				schemahcl.Int64Attr("seed", seed),
+				schemahcl.Int64Attr("increment", incr),
+			},
+		})
+	}
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
+		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, generatedType))
+	}
+	return s, nil
+}
+
+// columnTypeSpec converts from a concrete SQL Server schema.Type into sqlspec.Column Type.
+func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
+	st, err := TypeRegistry.Convert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlspec.Column{Type: st}, nil
+}
+
+// TypeRegistry contains the supported TypeSpecs for the SQL Server driver.
+var TypeRegistry = schemahcl.NewRegistry(
+	schemahcl.WithFormatter(FormatType),
+	schemahcl.WithParser(ParseType),
+	schemahcl.WithSpecs(
+		schemahcl.NewTypeSpec(TypeBit),
+		schemahcl.NewTypeSpec(TypeTinyInt),
+		schemahcl.NewTypeSpec(TypeSmallInt),
+		schemahcl.NewTypeSpec(TypeInt),
+		schemahcl.NewTypeSpec(TypeBigInt),
+		schemahcl.NewTypeSpec(TypeDecimal, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeNumeric, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeMoney),
+		schemahcl.NewTypeSpec(TypeSmallMoney),
+		schemahcl.NewTypeSpec(TypeFloat, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr()), schemahcl.WithToSpec(floatSpec)),
+		schemahcl.NewTypeSpec(TypeReal, schemahcl.WithToSpec(floatSpec)),
+		schemahcl.NewTypeSpec(TypeChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeNChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false)), schemahcl.WithToSpec(maxSpec)),
+		schemahcl.NewTypeSpec(TypeNVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false)), schemahcl.WithToSpec(maxSpec)),
+		schemahcl.NewTypeSpec(TypeText),
+		schemahcl.NewTypeSpec(TypeNText),
+		schemahcl.NewTypeSpec(TypeBinary, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeVarBinary, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false)), schemahcl.WithToSpec(maxSpec)),
+		schemahcl.NewTypeSpec(TypeImage),
+		schemahcl.NewTypeSpec(TypeDate),
+		schemahcl.NewTypeSpec(TypeTime, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeDateTime),
+		schemahcl.NewTypeSpec(TypeDateTime2, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeDateTimeOffset, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeSmallDateTime),
+		schemahcl.NewTypeSpec(TypeUniqueIdentifier),
+		schemahcl.NewTypeSpec(TypeGeometry),
+		schemahcl.NewTypeSpec(TypeGeography),
+	),
+)
+
+var (
+	codec = &Codec{
+		State: schemahcl.New(
+			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+			schemahcl.WithScopedEnums("table.column.as.type", persisted, virtual),
+			schemahcl.WithScopedEnums("table.index.type", clustered, nonClustered),
+			schemahcl.WithScopedEnums("table.primary_key.type", clustered, nonClustered),
+			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.column.diff.ignore", specutil.DiffIgnoreVars...),
+		),
+	}
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(codec.MarshalSpec)
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(codec.Eval)
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
+
+// maxSpec converts variable-length types to their spec form. Types with
+// the MAX specifier are converted to their raw form, as it is not a valid
+// value for the size attribute.
+func maxSpec(t schema.Type) (*schemahcl.Type, error) {
+	var size *int
+	switch t := t.(type) {
+	case *schema.StringType:
+		if t.Size > 0 {
+			size = &t.Size
+		}
+	case *schema.BinaryType:
+		size = t.Size
+	default:
+		return nil, fmt.Errorf("mssql: unexpected variable-length type %T", t)
+	}
+	f, err := FormatType(t)
+	if err != nil {
+		return nil, err
+	}
+	if size == nil {
+		return &schemahcl.Type{T: f}, nil
+	}
+	name, _, _ := strings.Cut(f, "(")
+	return &schemahcl.Type{T: name, Attrs: []*schemahcl.Attr{schemahcl.IntAttr("size", *size)}}, nil
+}
+
+// floatSpec converts floating-point types to their spec form,
+// without their precision, as it is implied by the type name.
+func floatSpec(t schema.Type) (*schemahcl.Type, error) {
+	f, err := FormatType(t)
+	if err != nil {
+		return nil, err
+	}
+	return &schemahcl.Type{T: f}, nil
+}
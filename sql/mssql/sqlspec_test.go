@@ -0,0 +1,140 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package mssql
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	f := `
+schema "dbo" {
+}
+
+table "users" {
+	schema = schema.dbo
+	column "id" {
+		type = int
+		identity {
+			seed = 100
+			increment = 10
+		}
+	}
+	column "name" {
+		type = nvarchar(100)
+		comment = "user name"
+	}
+	column "bio" {
+		type = sql("nvarchar(max)")
+		null = true
+	}
+	column "price" {
+		type = decimal(10,2)
+		default = 0
+	}
+	column "created_at" {
+		type = datetime2
+		default = sql("sysdatetime()")
+	}
+	column "total" {
+		type = decimal(11,2)
+		null = true
+		as {
+			expr = "[price] * 2"
+			type = PERSISTED
+		}
+	}
+	primary_key {
+		columns = [column.id]
+		type = NONCLUSTERED
+	}
+	index "users_name" {
+		unique = true
+		columns = [column.name]
+	}
+	index "users_created" {
+		columns = [column.created_at]
+		type = CLUSTERED
+		where = "[created_at] IS NOT NULL"
+	}
+	check "users_price" {
+		expr = "[price] > 0"
+	}
+	comment = "users table"
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Equal(t, "dbo", s.Name)
+	users, ok := s.Table("users")
+	require.True(t, ok)
+	require.Len(t, users.Columns, 6)
+	require.Equal(t, []schema.Attr{&Identity{Seed: 100, Increment: 10}}, users.Columns[0].Attrs)
+	require.Equal(t, &schema.IntegerType{T: TypeInt}, users.Columns[0].Type.Type)
+	require.Equal(t, &schema.StringType{T: TypeNVarchar, Size: 100}, users.Columns[1].Type.Type)
+	require.Equal(t, &schema.StringType{T: TypeNVarchar}, users.Columns[2].Type.Type)
+	require.Equal(t, &schema.DecimalType{T: TypeDecimal, Precision: 10, Scale: 2}, users.Columns[3].Type.Type)
+	require.Equal(t, &schema.Literal{V: "0"}, users.Columns[3].Default)
+	require.Equal(t, &schema.TimeType{T: TypeDateTime2}, users.Columns[4].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "sysdatetime()"}, users.Columns[4].Default)
+	require.Equal(t, []schema.Attr{&schema.GeneratedExpr{Expr: "[price] * 2", Type: persisted}}, users.Columns[5].Attrs)
+	require.Equal(t, "id", users.PrimaryKey.Parts[0].C.Name)
+	require.Equal(t, []schema.Attr{&IndexType{T: nonClustered}}, users.PrimaryKey.Attrs)
+	require.True(t, users.Indexes[0].Unique)
+	require.Equal(t, []schema.Attr{&IndexType{T: clustered}, &IndexPredicate{P: "[created_at] IS NOT NULL"}}, users.Indexes[1].Attrs)
+	require.Equal(t, []schema.Attr{&schema.Check{Name: "users_price", Expr: "[price] > 0"}, &schema.Comment{Text: "users table"}}, users.Attrs)
+
+	// The schema should be marshaled back to the same document.
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	changes, err := DefaultDiff.SchemaDiff(&s, &s2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestMarshalSpec(t *testing.T) {
+	s := schema.New("dbo").AddTables(
+		schema.NewTable("t").AddColumns(
+			schema.NewIntColumn("id", TypeBigInt).AddAttrs(&Identity{Seed: 1, Increment: 1}),
+			schema.NewColumn("data").SetType(&schema.BinaryType{T: TypeVarBinary}),
+			schema.NewColumn("uid").SetType(&schema.UUIDType{T: TypeUniqueIdentifier}),
+			schema.NewColumn("doc").SetType(&UserDefinedType{T: "xml"}).SetNull(true),
+		),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "t" {
+  schema = schema.dbo
+  column "id" {
+    null = false
+    type = bigint
+    identity {
+      seed      = 1
+      increment = 1
+    }
+  }
+  column "data" {
+    null = false
+    type = sql("varbinary(max)")
+  }
+  column "uid" {
+    null = false
+    type = uniqueidentifier
+  }
+  column "doc" {
+    null = true
+    type = sql("xml")
+  }
+}
+schema "dbo" {
+}
+`, string(buf))
+}
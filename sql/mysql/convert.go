@@ -83,14 +83,14 @@ func FormatType(t schema.Type) (string, error) {
 	case *schema.StringType:
 		f = strings.ToLower(t.T)
 		switch f {
-		case TypeChar:
+		case TypeChar, TypeNChar:
 			// Not a single char.
 			if t.Size > 0 {
 				f += fmt.Sprintf("(%d)", t.Size)
 			}
-		case TypeVarchar:
+		case TypeVarchar, TypeNVarchar:
 			// Zero is also a valid length.
-			f = fmt.Sprintf("varchar(%d)", t.Size)
+			f = fmt.Sprintf("%s(%d)", f, t.Size)
 		}
 	case *schema.SpatialType:
 		f = strings.ToLower(t.T)
@@ -198,6 +198,21 @@ func ParseType(raw string) (schema.Type, error) {
 			T:    t,
 			Size: size,
 		}, nil
+	// National types can be defined in multiple forms. For example,
+	// NATIONAL CHAR, NATIONAL CHARACTER VARYING or NCHAR VARCHAR.
+	case TypeNChar, TypeNVarchar, "national":
+		st := &schema.StringType{T: TypeNChar}
+		for _, p := range parts {
+			switch {
+			case p == TypeNVarchar || p == TypeVarchar || p == "varying":
+				st.T = TypeNVarchar
+			case sqlx.IsUint(p):
+				if st.Size, err = strconv.Atoi(p); err != nil {
+					return nil, fmt.Errorf("parse national type size %q", p)
+				}
+			}
+		}
+		return st, nil
 	case TypeTinyText, TypeMediumText, TypeText, TypeLongText:
 		return &schema.StringType{
 			T: t,
@@ -257,6 +272,25 @@ func ParseType(raw string) (schema.Type, error) {
 	}
 }
 
+// national reports if the given type is a national character type.
+func national(t schema.Type) bool {
+	st, ok := t.(*schema.StringType)
+	return ok && (st.T == TypeNChar || st.T == TypeNVarchar)
+}
+
+// nonNational returns the non-national form of the given string type, as
+// national types are stored by the database as CHAR and VARCHAR columns.
+func nonNational(t *schema.StringType) *schema.StringType {
+	switch t.T {
+	case TypeNChar:
+		return &schema.StringType{T: TypeChar, Size: t.Size}
+	case TypeNVarchar:
+		return &schema.StringType{T: TypeVarchar, Size: t.Size}
+	default:
+		return t
+	}
+}
+
 // formatValues formats ENUM and SET values.
 func formatValues(vs []string) string {
 	values := make([]string, len(vs))
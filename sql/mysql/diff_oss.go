@@ -9,11 +9,13 @@ package mysql
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
 )
@@ -63,14 +65,92 @@ func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
 
 // SchemaObjectDiff returns a changeset for migrating schema objects from
 // one state to the other.
-func (*diff) SchemaObjectDiff(_, _ *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
-	return nil, nil
+func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+	var changes []schema.Change
+	// Drop or modify sequences.
+	for _, o1 := range from.Objects {
+		s1, ok := o1.(*Sequence)
+		if !ok {
+			continue // Unsupported object type.
+		}
+		o2, ok := to.Object(func(o schema.Object) bool {
+			s2, ok := o.(*Sequence)
+			return ok && s1.Name == s2.Name
+		})
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: s1})
+			continue
+		}
+		if s2 := o2.(*Sequence); sequenceChanged(s1, s2) {
+			changes = append(changes, &schema.ModifyObject{From: s1, To: s2})
+		}
+	}
+	// Add new sequences.
+	for _, o1 := range to.Objects {
+		s1, ok := o1.(*Sequence)
+		if !ok {
+			continue // Unsupported object type.
+		}
+		if _, ok := from.Object(func(o schema.Object) bool {
+			s2, ok := o.(*Sequence)
+			return ok && s1.Name == s2.Name
+		}); !ok {
+			changes = append(changes, &schema.AddObject{O: s1})
+		}
+	}
+	return changes, nil
+}
+
+// sequenceChanged reports if the options of the two sequences are different.
+func sequenceChanged(from, to *Sequence) bool {
+	return sequenceOptions(from) != sequenceOptions(to) || sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// Default values of MariaDB sequence options.
+const (
+	seqDefaultStart     = 1
+	seqDefaultIncrement = 1
+	seqDefaultMin       = 1
+	seqDefaultMax       = math.MaxInt64 - 1
+	seqDefaultCache     = 1000
+)
+
+// seqOptions holds the normalized options of a sequence.
+type seqOptions struct {
+	start, increment, min, max, cache int64
+	cycle                             bool
+}
+
+// sequenceOptions returns the sequence options with the
+// zero values replaced with their server defaults.
+func sequenceOptions(s *Sequence) seqOptions {
+	o := seqOptions{start: s.Start, increment: s.Increment, min: s.Min, max: s.Max, cache: s.Cache, cycle: s.Cycle}
+	if o.increment == 0 {
+		o.increment = seqDefaultIncrement
+	}
+	if o.min == 0 {
+		o.min = seqDefaultMin
+	}
+	if o.max == 0 {
+		o.max = seqDefaultMax
+	}
+	if o.start == 0 {
+		o.start = o.min
+	}
+	if o.cache == 0 {
+		o.cache = seqDefaultCache
+	}
+	return o
 }
 
 // TableAttrDiff returns a changeset for migrating table attributes from one state to the other.
 func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) ([]schema.Change, error) {
+	extra, err := diffOptions(opts)
+	if err != nil {
+		return nil, err
+	}
 	var changes []schema.Change
-	if change := d.autoIncChange(from.Attrs, to.Attrs); change != noChange {
+	if change := d.autoIncChange(from.Attrs, to.Attrs, extra); change != noChange {
 		changes = append(changes, change)
 	}
 	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
@@ -85,6 +165,9 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	if change := d.engineChange(from.Attrs, to.Attrs); change != noChange {
 		changes = append(changes, change)
 	}
+	if change := d.tablespaceChange(from.Attrs, to.Attrs); change != noChange {
+		changes = append(changes, change)
+	}
 	if change := d.systemVerChange(from.Attrs, to.Attrs); change != noChange {
 		changes = append(changes, change)
 	}
@@ -132,6 +215,9 @@ func (d *diff) ColumnChange(fromT *schema.Table, from, to *schema.Column, _ *sch
 	if changed {
 		change |= schema.ChangeDefault
 	}
+	if d.onUpdateChanged(from, to) {
+		change |= schema.ChangeAttr
+	}
 	if changed, err = d.generatedChanged(from, to); err != nil {
 		return sqlx.NoChange, err
 	}
@@ -190,7 +276,7 @@ func (d *diff) IsGeneratedIndexName(_ *schema.Table, idx *schema.Index) bool {
 
 // IndexAttrChanged reports if the index attributes were changed.
 func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
-	if indexType(from).T != indexType(to).T {
+	if indexType(from).T != indexType(to).T || sqlx.Has(from, &IndexInvisible{}) != sqlx.Has(to, &IndexInvisible{}) {
 		return true
 	}
 	var (
@@ -258,6 +344,24 @@ func (d *diff) Normalize(from, to *schema.Table, opts *schema.DiffOptions) error
 			return err
 		}
 	}
+	// Columns without an explicit charset inherit the one defined on their table. Hence,
+	// if the table charset was changed, these columns are expected to be converted as
+	// well, as MySQL does not convert existing columns on table charset changes.
+	var fromC, toC schema.Charset
+	inherit := sqlx.Has(from.Attrs, &fromC) && sqlx.Has(to.Attrs, &toC) && fromC.V != toC.V
+	for _, c := range to.Columns {
+		switch {
+		case c.Type == nil || !supportsCharset(c.Type.Type) || sqlx.Has(c.Attrs, &schema.Charset{}):
+		// National types are stored with the national charset.
+		case national(c.Type.Type):
+			c.Attrs = append(c.Attrs, &schema.Charset{V: d.NationalCharset()})
+		case inherit && !sqlx.Has(c.Attrs, &schema.Collation{}):
+			c.Attrs = append(c.Attrs, &schema.Charset{V: toC.V})
+			if co := (schema.Collation{}); sqlx.Has(to.Attrs, &co) {
+				c.Attrs = append(c.Attrs, &schema.Collation{V: co.V})
+			}
+		}
+	}
 	return nil
 }
 
@@ -354,6 +458,25 @@ func (*diff) engineChange(from, to []schema.Attr) schema.Change {
 	return noChange
 }
 
+// tablespaceChange returns the schema change for migrating the table tablespace in
+// case it was changed. Tables without a general tablespace are stored in their own
+// file, and moving a table back to it is done using the innodb_file_per_table tablespace.
+func (*diff) tablespaceChange(from, to []schema.Attr) schema.Change {
+	var fromT, toT Tablespace
+	sqlx.Has(from, &fromT)
+	sqlx.Has(to, &toT)
+	if fromT.V == toT.V {
+		return noChange
+	}
+	if toT.V == "" {
+		toT.V = TablespaceFilePerTable
+	}
+	return &schema.ModifyAttr{
+		From: &fromT,
+		To:   &toT,
+	}
+}
+
 // systemVerChange returns the schema change for migrating the system versioning
 // attributes if it was changed.
 func (d *diff) systemVerChange(from, to []schema.Attr) schema.Change {
@@ -436,13 +559,60 @@ func (d *diff) columnCollateChanged(fromT *schema.Table, from, to *schema.Column
 
 }
 
+// DiffOptions defines MySQL specific schema diffing process.
+type DiffOptions struct {
+	// AutoIncrement configures how changes to the table AUTO_INCREMENT counter are planned.
+	// By default, a change is suggested only if the desired value is greater than the current.
+	AutoIncrement struct {
+		// Ignore skips all changes to the counter. Useful for avoiding noise
+		// in environments where the counter is not managed by the schema.
+		Ignore bool `spec:"ignore"`
+		// Exact pins the counter to the value defined on the desired table, and suggests
+		// a change whenever the two values differ, including lowering the counter. Note,
+		// InnoDB does not allow setting the counter below the maximum value of the column.
+		Exact bool `spec:"exact"`
+	} `spec:"auto_increment"`
+}
+
+// diffOptions returns the MySQL specific options from the DiffOptions.Extra field.
+func diffOptions(opts *schema.DiffOptions) (*DiffOptions, error) {
+	var extra DiffOptions
+	switch ex := opts.Extra.(type) {
+	case nil:
+	case schemahcl.DefaultExtension:
+		if err := ex.Extra.As(&extra); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("mysql: unexpected DiffOptions.Extra type %T", opts.Extra)
+	}
+	if extra.AutoIncrement.Ignore && extra.AutoIncrement.Exact {
+		return nil, fmt.Errorf("mysql: auto_increment policy cannot set both ignore and exact")
+	}
+	return &extra, nil
+}
+
 // autoIncChange returns the schema change for changing the AUTO_INCREMENT
 // attribute in case it is not the default.
-func (*diff) autoIncChange(from, to []schema.Attr) schema.Change {
+func (*diff) autoIncChange(from, to []schema.Attr, opts *DiffOptions) schema.Change {
 	var fromA, toA AutoIncrement
 	switch fromHas, toHas := sqlx.Has(from, &fromA), sqlx.Has(to, &toA); {
+	// Changes to the counter are ignored by the diff policy.
+	case opts.AutoIncrement.Ignore:
 	// Ignore if the AUTO_INCREMENT attribute was dropped from the desired schema.
 	case fromHas && !toHas:
+	// The counter is pinned to the value defined in the desired schema.
+	case opts.AutoIncrement.Exact && toHas:
+		// A table without a counter value starts at 1.
+		if !fromHas {
+			fromA.V = 1
+		}
+		if toA.V != fromA.V {
+			return &schema.ModifyAttr{
+				From: &fromA,
+				To:   &toA,
+			}
+		}
 	// The AUTO_INCREMENT exists in the desired schema, and may not exist in the inspected one.
 	// This can happen because older versions of MySQL (< 8.0) stored the AUTO_INCREMENT counter
 	// in main memory (not persistent), and the value is reset on process restart for empty tables.
@@ -490,8 +660,20 @@ func (d *diff) typeChanged(from, to *schema.Column) (bool, error) {
 	}
 	var changed bool
 	switch fromT := fromT.(type) {
+	case *schema.StringType:
+		// National types are compared by their stored form, and
+		// their charset is compared by the column charset diff.
+		ft, err := FormatType(nonNational(fromT))
+		if err != nil {
+			return false, err
+		}
+		tt, err := FormatType(nonNational(toT.(*schema.StringType)))
+		if err != nil {
+			return false, err
+		}
+		changed = ft != tt
 	case *BitType, *schema.BinaryType, *schema.BoolType, *schema.DecimalType, *schema.FloatType,
-		*schema.JSONType, *schema.StringType, *schema.SpatialType, *schema.TimeType, *schema.UUIDType, *NetworkType:
+		*schema.JSONType, *schema.SpatialType, *schema.TimeType, *schema.UUIDType, *NetworkType:
 		ft, err := FormatType(fromT)
 		if err != nil {
 			return false, err
@@ -561,6 +743,12 @@ func (d *diff) defaultChanged(from, to *schema.Column) (bool, error) {
 	case *schema.EnumType, *SetType, *schema.StringType:
 		return !equalsStringValues(d1, d2), nil
 	case *schema.TimeType:
+		// CURRENT_TIMESTAMP expressions are compared by their precision.
+		p1, ok1 := currentTimestamp(d1)
+		p2, ok2 := currentTimestamp(d2)
+		if ok1 && ok2 {
+			return p1 != p2, nil
+		}
 		x1 := strings.ToLower(strings.Trim(d1, "' ()"))
 		x2 := strings.ToLower(strings.Trim(d2, "' ()"))
 		return !equalsStringValues(x1, x2), nil
@@ -571,6 +759,26 @@ func (d *diff) defaultChanged(from, to *schema.Column) (bool, error) {
 	}
 }
 
+// onUpdateChanged reports if the ON UPDATE clause of a column was changed.
+func (*diff) onUpdateChanged(from, to *schema.Column) bool {
+	var (
+		fromU, toU     OnUpdate
+		fromHas, toHas = sqlx.Has(from.Attrs, &fromU), sqlx.Has(to.Attrs, &toU)
+	)
+	switch {
+	case fromHas != toHas:
+		return true
+	case !fromHas:
+		return false
+	}
+	p1, ok1 := currentTimestamp(fromU.A)
+	p2, ok2 := currentTimestamp(toU.A)
+	if ok1 && ok2 {
+		return p1 != p2
+	}
+	return !strings.EqualFold(strings.TrimSpace(fromU.A), strings.TrimSpace(toU.A))
+}
+
 // generatedChanged reports if the generated expression of a column was changed.
 func (*diff) generatedChanged(from, to *schema.Column) (bool, error) {
 	var (
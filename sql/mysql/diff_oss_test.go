@@ -7,8 +7,10 @@
 package mysql
 
 import (
+	"context"
 	"testing"
 
+	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/schema"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -116,6 +118,29 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "add tablespace",
+			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}},
+			to:   &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{&Tablespace{V: "archive"}}},
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &Tablespace{},
+					To:   &Tablespace{V: "archive"},
+				},
+			},
+		},
+		// Tables that were removed from a general tablespace are moved to their own file.
+		{
+			name: "drop tablespace",
+			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{&Tablespace{V: "archive"}}},
+			to:   &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}},
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{
+					From: &Tablespace{V: "archive"},
+					To:   &Tablespace{V: TablespaceFilePerTable},
+				},
+			},
+		},
 		{
 			name: "add collation",
 			from: &schema.Table{Name: "users", Schema: &schema.Schema{Name: "public"}, Attrs: []schema.Attr{&schema.Charset{V: "latin1"}}},
@@ -383,6 +408,126 @@ func TestDiff_TableDiff(t *testing.T) {
 				to:   to,
 			}
 		}(),
+		// National types are stored as CHAR and VARCHAR with the national charset.
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8mb4").
+					SetCollation("utf8mb4_0900_ai_ci").
+					AddColumns(
+						schema.NewColumn("c1").SetType(&schema.StringType{T: TypeChar, Size: 10}).SetCharset("utf8").SetCollation("utf8_general_ci"),
+						schema.NewColumn("c2").SetType(&schema.StringType{T: TypeVarchar, Size: 10}).SetCharset("utf8mb4").SetCollation("utf8mb4_0900_ai_ci"),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8mb4").
+					SetCollation("utf8mb4_0900_ai_ci").
+					AddColumns(
+						schema.NewColumn("c1").SetType(&schema.StringType{T: TypeNChar, Size: 10}),
+						schema.NewColumn("c2").SetType(&schema.StringType{T: TypeNVarchar, Size: 10}),
+					)
+			)
+			return testcase{
+				name: "national types",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{
+						From:   from.Columns[1],
+						To:     to.Columns[1],
+						Change: schema.ChangeCharset | schema.ChangeCollate,
+					},
+				},
+			}
+		}(),
+		// Columns without explicit CHARSET inherit the new table CHARSET.
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("latin1").
+					SetCollation("latin1_swedish_ci").
+					AddColumns(
+						schema.NewStringColumn("c1", "text").SetCharset("latin1").SetCollation("latin1_swedish_ci"),
+						schema.NewStringColumn("c2", "text").SetCharset("latin1").SetCollation("latin1_swedish_ci"),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					SetCharset("utf8mb4").
+					AddColumns(
+						schema.NewStringColumn("c1", "text"),
+						schema.NewStringColumn("c2", "text").SetCharset("latin1"),
+					)
+			)
+			return testcase{
+				name: "columns inherit charset",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyAttr{
+						From: &schema.Charset{V: "latin1"},
+						To:   &schema.Charset{V: "utf8mb4"},
+					},
+					&schema.ModifyAttr{
+						From: &schema.Collation{V: "latin1_swedish_ci"},
+						To:   &schema.Collation{V: "utf8mb4_0900_ai_ci"},
+					},
+					&schema.ModifyColumn{
+						From:   from.Columns[0],
+						To:     to.Columns[0],
+						Change: schema.ChangeCharset | schema.ChangeCollate,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			var (
+				s  = schema.New("public")
+				ts = func(name string, p int, def, onUpdate string) *schema.Column {
+					c := schema.NewTimeColumn(name, TypeTimestamp, schema.TimePrecision(p))
+					if def != "" {
+						c.SetDefault(&schema.RawExpr{X: def})
+					}
+					if onUpdate != "" {
+						c.AddAttrs(&OnUpdate{A: onUpdate})
+					}
+					return c
+				}
+				from = schema.NewTable("t1").
+					SetSchema(s).
+					AddColumns(
+						ts("c1", 6, "current_timestamp(6)", "current_timestamp(6)"),
+						ts("c2", 0, "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP"),
+						ts("c3", 6, "CURRENT_TIMESTAMP(6)", ""),
+						ts("c4", 6, "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)"),
+						ts("c5", 3, "CURRENT_TIMESTAMP(3)", "CURRENT_TIMESTAMP(3)"),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(s).
+					AddColumns(
+						// Case and synonyms are ignored.
+						ts("c1", 6, "CURRENT_TIMESTAMP(6)", "now(6)"),
+						ts("c2", 0, "current_timestamp()", "CURRENT_TIMESTAMP(0)"),
+						// Add the ON UPDATE clause.
+						ts("c3", 6, "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)"),
+						// Drop the ON UPDATE clause.
+						ts("c4", 6, "CURRENT_TIMESTAMP(6)", ""),
+						// Change the precision.
+						ts("c5", 6, "CURRENT_TIMESTAMP(6)", "CURRENT_TIMESTAMP(6)"),
+					)
+			)
+			return testcase{
+				name: "on update current_timestamp",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyColumn{From: from.Columns[2], To: to.Columns[2], Change: schema.ChangeAttr},
+					&schema.ModifyColumn{From: from.Columns[3], To: to.Columns[3], Change: schema.ChangeAttr},
+					&schema.ModifyColumn{From: from.Columns[4], To: to.Columns[4], Change: schema.ChangeType | schema.ChangeDefault | schema.ChangeAttr},
+				},
+			}
+		}(),
 		func() testcase {
 			var (
 				s    = schema.New("public")
@@ -465,6 +610,7 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c1_prefix", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1], Attrs: []schema.Attr{&SubPart{Len: 50}}}}},
 				{Name: "c1_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1]}}},
 				{Name: "parser", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[3]}}, Attrs: []schema.Attr{&IndexType{T: IndexTypeFullText}, &IndexParser{P: "ngram"}}},
+				{Name: "invisible", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[2]}}},
 			}
 			to.Indexes = []*schema.Index{
 				{Name: "c1_index", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[0]}}},
@@ -472,6 +618,7 @@ func TestDiff_TableDiff(t *testing.T) {
 				{Name: "c1_prefix", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[0], Attrs: []schema.Attr{&SubPart{Len: 100}}}}},
 				{Name: "c1_desc", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[1], Desc: true}}},
 				{Name: "parser", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[3]}}, Attrs: []schema.Attr{&IndexType{T: IndexTypeFullText}}},
+				{Name: "invisible", Table: from, Parts: []*schema.IndexPart{{SeqNo: 1, C: from.Columns[2]}}, Attrs: []schema.Attr{&IndexInvisible{}}},
 			}
 			return testcase{
 				name: "indexes",
@@ -483,6 +630,7 @@ func TestDiff_TableDiff(t *testing.T) {
 					&schema.ModifyIndex{From: from.Indexes[2], To: to.Indexes[2], Change: schema.ChangeParts},
 					&schema.ModifyIndex{From: from.Indexes[3], To: to.Indexes[3], Change: schema.ChangeParts},
 					&schema.ModifyIndex{From: from.Indexes[4], To: to.Indexes[4], Change: schema.ChangeAttr},
+					&schema.ModifyIndex{From: from.Indexes[5], To: to.Indexes[5], Change: schema.ChangeAttr},
 					&schema.AddIndex{I: to.Indexes[1]},
 				},
 			}
@@ -611,6 +759,75 @@ func TestDiff_TableDiff(t *testing.T) {
 	}
 }
 
+func TestDiff_AutoIncrementPolicy(t *testing.T) {
+	policy := func(t *testing.T, src string) schema.DiffOption {
+		var cfg struct {
+			schemahcl.DefaultExtension
+		}
+		require.NoError(t, schemahcl.New().EvalBytes([]byte(src), &cfg, nil))
+		return func(opts *schema.DiffOptions) { opts.Extra = cfg.DefaultExtension }
+	}
+	var (
+		s    = schema.New("public")
+		from = schema.NewTable("users").SetSchema(s).AddAttrs(&AutoIncrement{V: 100})
+		to   = schema.NewTable("users").SetSchema(s).AddAttrs(&AutoIncrement{V: 50})
+	)
+	// By default, the counter is never lowered.
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// language=hcl
+	exact := policy(t, `
+auto_increment {
+  exact = true
+}
+`)
+	changes, err = DefaultDiff.TableDiff(from, to, exact)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyAttr{From: &AutoIncrement{V: 100}, To: &AutoIncrement{V: 50}}}, changes)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: schema.NewTable("users").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int")), Changes: changes},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, "ALTER TABLE `public`.`users` AUTO_INCREMENT 50", plan.Changes[0].Cmd)
+	// Once applied, the counter converges.
+	changes, err = DefaultDiff.TableDiff(schema.NewTable("users").SetSchema(s).AddAttrs(&AutoIncrement{V: 50}), to, exact)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	// Tables without a counter start at 1.
+	changes, err = DefaultDiff.TableDiff(schema.NewTable("users").SetSchema(s), schema.NewTable("users").SetSchema(s).AddAttrs(&AutoIncrement{V: 1}), exact)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	// Counters that are not defined in the desired state are not managed.
+	changes, err = DefaultDiff.TableDiff(from, schema.NewTable("users").SetSchema(s), exact)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// language=hcl
+	ignore := policy(t, `
+auto_increment {
+  ignore = true
+}
+`)
+	changes, err = DefaultDiff.TableDiff(to, from, ignore)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	changes, err = DefaultDiff.TableDiff(to, from)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	// language=hcl
+	_, err = DefaultDiff.TableDiff(from, to, policy(t, `
+auto_increment {
+  exact  = true
+  ignore = true
+}
+`))
+	require.EqualError(t, err, "mysql: auto_increment policy cannot set both ignore and exact")
+}
+
 func TestDiff_UnsupportedChecks(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -677,6 +894,27 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	}, changes)
 }
 
+func TestDiff_SchemaObjectDiff(t *testing.T) {
+	from := schema.New("test").AddObjects(
+		&Sequence{Name: "s1"},
+		&Sequence{Name: "s2", Start: 1, Increment: 1, Cache: 1000},
+		&Sequence{Name: "s3", Increment: 1},
+	)
+	to := schema.New("test").AddObjects(
+		&Sequence{Name: "s1", Increment: 2},
+		// Defaults are ignored.
+		&Sequence{Name: "s2"},
+		&Sequence{Name: "s4", Attrs: []schema.Attr{&schema.Comment{Text: "c"}}},
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.EqualValues(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[0], To: to.Objects[0]},
+		&schema.DropObject{O: from.Objects[2]},
+		&schema.AddObject{O: to.Objects[2]},
+	}, changes)
+}
+
 func TestDiff_LowerCaseMode(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -11,6 +11,7 @@ import (
 	"database/sql"
 	"fmt"
 	"net/url"
+	"regexp"
 	"strings"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
 	"ariga.io/atlas/sql/sqlspec"
+	"ariga.io/atlas/sql/types"
 )
 
 type (
@@ -49,6 +51,7 @@ var _ interface {
 	migrate.Snapshoter
 	migrate.StmtScanner
 	migrate.CleanChecker
+	migrate.StatsRefresher
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
 
@@ -75,6 +78,8 @@ func init() {
 		sqlclient.RegisterFlavours("mariadb+unix", "maria", "maria+unix"),
 		sqlclient.RegisterURLParser(parser{}),
 	)
+	types.Register(DriverName, (*Driver)(nil))
+	types.Register(DriverMaria, (*Driver)(nil))
 }
 
 // Open opens a new MySQL driver.
@@ -257,6 +262,164 @@ func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error
 	return nil
 }
 
+// RefreshStats implements migrate.StatsRefresher.
+func (d *Driver) RefreshStats(ctx context.Context, tables []*schema.Table) error {
+	if len(tables) == 0 {
+		return nil
+	}
+	b := d.StmtBuilder(migrate.PlanOptions{}).P("ANALYZE TABLE").MapComma(tables, func(i int, b *sqlx.Builder) {
+		b.Table(tables[i])
+	})
+	if _, err := d.ExecContext(ctx, b.String()); err != nil {
+		return fmt.Errorf("mysql: analyze tables: %w", err)
+	}
+	return nil
+}
+
+// TableSizes implements migrate.TableSizer. The sizes are read from the
+// information schema, and are approximated for storage engines like InnoDB.
+func (d *Driver) TableSizes(ctx context.Context, tables []*schema.Table) (map[*schema.Table]int64, error) {
+	sizes := make(map[*schema.Table]int64, len(tables))
+	for _, t := range tables {
+		var s string
+		if t.Schema != nil {
+			s = t.Schema.Name
+		}
+		rows, err := d.QueryContext(ctx, tableSizeQuery, s, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("mysql: querying size of table %q: %w", t.Name, err)
+		}
+		var n int64
+		if err := sqlx.ScanOne(rows, &n); err != nil {
+			return nil, fmt.Errorf("mysql: scanning size of table %q: %w", t.Name, err)
+		}
+		if n >= 0 {
+			sizes[t] = n
+		}
+	}
+	return sizes, nil
+}
+
+// Query to estimate the number of rows in a table. Returns -1 if the table does not exist.
+const tableSizeQuery = "SELECT COALESCE(MAX(`TABLE_ROWS`), -1) FROM `INFORMATION_SCHEMA`.`TABLES` WHERE `TABLE_SCHEMA` = COALESCE(NULLIF(?, ''), DATABASE()) AND `TABLE_NAME` = ?"
+
+// MissingPrivileges implements migrate.PrivilegeChecker. The privileges are resolved from the
+// grants of the current user in the information schema, on the global, schema and table levels.
+// Note that privileges granted to the user through roles are not taken into account.
+func (d *Driver) MissingPrivileges(ctx context.Context, privs []*migrate.Privilege) ([]*migrate.Privilege, error) {
+	if len(privs) == 0 {
+		return nil, nil
+	}
+	rows, err := d.QueryContext(ctx, "SELECT CURRENT_USER(), DATABASE()")
+	if err != nil {
+		return nil, fmt.Errorf("mysql: querying current user: %w", err)
+	}
+	var user, db sql.NullString
+	if err := sqlx.ScanOne(rows, &user, &db); err != nil {
+		return nil, fmt.Errorf("mysql: scanning current user: %w", err)
+	}
+	i := strings.LastIndexByte(user.String, '@')
+	if i == -1 {
+		return nil, fmt.Errorf("mysql: unexpected current user %q", user.String)
+	}
+	grantee := fmt.Sprintf("'%s'@'%s'", user.String[:i], user.String[i+1:])
+	if rows, err = d.QueryContext(ctx, privilegesQuery, grantee, grantee, grantee); err != nil {
+		return nil, fmt.Errorf("mysql: querying user privileges: %w", err)
+	}
+	defer rows.Close()
+	var grants []*grant
+	for rows.Next() {
+		g := &grant{}
+		if err := rows.Scan(&g.priv, &g.schema, &g.table); err != nil {
+			return nil, fmt.Errorf("mysql: scanning user privileges: %w", err)
+		}
+		grants = append(grants, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	var missing []*migrate.Privilege
+	for _, p := range privs {
+		s := p.Schema
+		if s == "" {
+			s = db.String
+		}
+		granted := false
+		for _, g := range grants {
+			if granted = g.covers(privilegeName(p), s, p); granted {
+				break
+			}
+		}
+		if !granted {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
+
+// grant describes a privilege granted to the current user. The schema
+// is empty for global privileges, and the table is empty for global and
+// schema privileges.
+type grant struct {
+	priv, schema, table string
+}
+
+// covers reports if the grant covers the named privilege on an object in the given schema.
+func (g *grant) covers(name, schema string, p *migrate.Privilege) bool {
+	switch {
+	case !strings.EqualFold(g.priv, name):
+		return false
+	case g.schema == "":
+		return true
+	case g.table == "":
+		return likeMatch(g.schema, schema)
+	default:
+		return (p.Type == "table" || p.Type == "view") && g.schema == schema && g.table == p.Name
+	}
+}
+
+// privilegeName returns the MySQL privilege required for the given privilege.
+func privilegeName(p *migrate.Privilege) string {
+	switch p.Type {
+	case "view":
+		if p.Action != migrate.PrivilegeDrop {
+			return "CREATE VIEW"
+		}
+	case "function", "procedure":
+		if p.Action == migrate.PrivilegeCreate {
+			return "CREATE ROUTINE"
+		}
+		return "ALTER ROUTINE"
+	}
+	return p.Action
+}
+
+// likeMatch reports if s matches the given pattern, using the wildcards that
+// can be used in schema-level grants: '%' matches any sequence of characters,
+// '_' matches any single character and '\' escapes the next character.
+func likeMatch(pattern, s string) bool {
+	var b strings.Builder
+	b.WriteByte('^')
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '%':
+			b.WriteString(".*")
+		case c == '_':
+			b.WriteByte('.')
+		default:
+			if c == '\\' && i+1 < len(pattern) {
+				i++
+			}
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	b.WriteByte('$')
+	return regexp.MustCompile(b.String()).MatchString(s)
+}
+
+// Query to list the global, schema and table privileges granted to a user.
+const privilegesQuery = "SELECT `PRIVILEGE_TYPE`, '', '' FROM `INFORMATION_SCHEMA`.`USER_PRIVILEGES` WHERE `GRANTEE` = ? UNION ALL SELECT `PRIVILEGE_TYPE`, `TABLE_SCHEMA`, '' FROM `INFORMATION_SCHEMA`.`SCHEMA_PRIVILEGES` WHERE `GRANTEE` = ? UNION ALL SELECT `PRIVILEGE_TYPE`, `TABLE_SCHEMA`, `TABLE_NAME` FROM `INFORMATION_SCHEMA`.`TABLE_PRIVILEGES` WHERE `GRANTEE` = ?"
+
 // Version returns the version of the connected database.
 func (d *Driver) Version() string {
 	return string(d.conn.V)
@@ -428,6 +591,8 @@ const (
 
 	TypeVarchar    = "varchar"    // MYSQL_TYPE_VAR_STRING, MYSQL_TYPE_VARCHAR
 	TypeChar       = "char"       // MYSQL_TYPE_STRING
+	TypeNVarchar   = "nvarchar"   // MYSQL_TYPE_VARCHAR + CHARACTER_SET utf8mb3 (national)
+	TypeNChar      = "nchar"      // MYSQL_TYPE_STRING + CHARACTER_SET utf8mb3 (national)
 	TypeVarBinary  = "varbinary"  // MYSQL_TYPE_VAR_STRING + NULL CHARACTER_SET.
 	TypeBinary     = "binary"     // MYSQL_TYPE_STRING + NULL CHARACTER_SET.
 	TypeBlob       = "blob"       // MYSQL_TYPE_BLOB
@@ -475,6 +640,10 @@ const (
 	EngineCSV    = "CSV"
 	EngineNDB    = "NDB" // NDBCLUSTER
 
+	// TablespaceFilePerTable is the tablespace name used for
+	// storing a table in its own (file-per-table) data file.
+	TablespaceFilePerTable = "innodb_file_per_table"
+
 	currentTS     = "current_timestamp"
 	defaultGen    = "default_generated"
 	autoIncrement = "auto_increment"
@@ -213,6 +213,81 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "8.0.13", drv.(vr).Version())
 }
 
+func TestDriver_RefreshStats(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("8.0.13")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s := schema.New("test")
+	s.AddTables(schema.NewTable("users"), schema.NewTable("pets"))
+	m.ExpectExec(sqltest.Escape("ANALYZE TABLE `test`.`users`, `test`.`pets`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	err = drv.(migrate.StatsRefresher).RefreshStats(context.Background(), s.Tables)
+	require.NoError(t, err)
+	// No tables to refresh.
+	err = drv.(migrate.StatsRefresher).RefreshStats(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_TableSizes(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("8.0.13")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	users, pets := schema.NewTable("users"), schema.NewTable("pets")
+	schema.New("test").AddTables(users)
+	m.ExpectQuery(sqltest.Escape(tableSizeQuery)).
+		WithArgs("test", "users").
+		WillReturnRows(sqlmock.NewRows([]string{"rows"}).AddRow(1000))
+	m.ExpectQuery(sqltest.Escape(tableSizeQuery)).
+		WithArgs("", "pets").
+		WillReturnRows(sqlmock.NewRows([]string{"rows"}).AddRow(-1))
+	sizes, err := drv.(migrate.TableSizer).TableSizes(context.Background(), []*schema.Table{users, pets})
+	require.NoError(t, err)
+	require.Equal(t, map[*schema.Table]int64{users: 1000}, sizes)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_MissingPrivileges(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("8.0.13")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	m.ExpectQuery(sqltest.Escape("SELECT CURRENT_USER(), DATABASE()")).
+		WillReturnRows(sqlmock.NewRows([]string{"user", "db"}).AddRow("atlas@%", "app"))
+	m.ExpectQuery(sqltest.Escape(privilegesQuery)).
+		WithArgs("'atlas'@'%'", "'atlas'@'%'", "'atlas'@'%'").
+		WillReturnRows(sqlmock.NewRows([]string{"priv", "schema", "table"}).
+			AddRow("CREATE", "", "").
+			AddRow("ALTER", "app", "").
+			AddRow("ALTER", `app\_%`, "").
+			AddRow("DROP", "app", "pets").
+			AddRow("CREATE VIEW", "app", ""))
+	privs := []*migrate.Privilege{
+		{Action: migrate.PrivilegeCreate, Type: "schema", Schema: "logs"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Name: "users"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Schema: "app_v2", Name: "users"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Schema: "appv2", Name: "users"},
+		{Action: migrate.PrivilegeDrop, Type: "table", Schema: "app", Name: "pets"},
+		{Action: migrate.PrivilegeDrop, Type: "table", Schema: "app", Name: "users"},
+		{Action: migrate.PrivilegeAlter, Type: "view", Schema: "app", Name: "active"},
+		{Action: migrate.PrivilegeCreate, Type: "function", Schema: "app", Name: "f"},
+	}
+	missing, err := drv.(migrate.PrivilegeChecker).MissingPrivileges(context.Background(), privs)
+	require.NoError(t, err)
+	require.Equal(t, []*migrate.Privilege{privs[3], privs[5], privs[7]}, missing)
+	require.NoError(t, m.ExpectationsWereMet())
+
+	// Nothing to check.
+	missing, err = drv.(migrate.PrivilegeChecker).MissingPrivileges(context.Background(), nil)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
 type mockInspector struct {
 	schema.Inspector
 	realm  *schema.Realm
@@ -11,6 +11,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -43,8 +44,13 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			}
 			sqlx.LinkSchemaTables(schemas)
 		}
+		if mode.Is(schema.InspectObjects) && i.SupportsSequences() {
+			if err := i.sequences(ctx, r); err != nil {
+				return nil, err
+			}
+		}
 	}
-	return schema.ExcludeRealm(r, opts.Exclude)
+	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
 }
 
 // InspectSchema returns schema descriptions of the tables in the given schema.
@@ -73,7 +79,12 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 		}
 		sqlx.LinkSchemaTables(schemas)
 	}
-	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	if mode.Is(schema.InspectObjects) && i.SupportsSequences() {
+		if err := i.sequences(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return sqlx.SchemaChecksums(schema.ExcludeSchema(r.Schemas[0], opts.Exclude))
 }
 
 func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
@@ -96,6 +107,9 @@ func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *sche
 		if err := i.checks(ctx, s); err != nil {
 			return err
 		}
+		if err := i.tablespaces(ctx, s); err != nil {
+			return err
+		}
 		if err := i.showCreate(ctx, s); err != nil {
 			return err
 		}
@@ -221,6 +235,56 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 	return rows.Err()
 }
 
+// sequences queries and appends the sequences of the realm schemas.
+// Sequences are supported by MariaDB only. See: https://mariadb.com/kb/en/sequences
+func (i *inspect) sequences(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(sequencesQuery, nArgs(len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("mysql: querying sequences: %w", err)
+	}
+	var seqs []*Sequence
+	for rows.Next() {
+		var (
+			ns, name string
+			comment  sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &comment); err != nil {
+			rows.Close()
+			return fmt.Errorf("mysql: scan sequence: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			rows.Close()
+			return fmt.Errorf("schema %q was not found in realm", ns)
+		}
+		seq := &Sequence{Name: name, Schema: s}
+		if sqlx.ValidString(comment) {
+			seq.Attrs = append(seq.Attrs, &schema.Comment{Text: comment.String})
+		}
+		s.AddObjects(seq)
+		seqs = append(seqs, seq)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	// The options of each sequence are stored in its underlying table.
+	for _, seq := range seqs {
+		b := &sqlx.Builder{QuoteOpening: '`', QuoteClosing: '`'}
+		rows, err := i.QueryContext(ctx, fmt.Sprintf(sequenceQuery, b.SchemaResource(seq.Schema, seq.Name).String()))
+		if err != nil {
+			return fmt.Errorf("mysql: querying sequence %q: %w", seq.Name, err)
+		}
+		if err := sqlx.ScanOne(rows, &seq.Start, &seq.Min, &seq.Max, &seq.Increment, &seq.Cache, &seq.Cycle); err != nil {
+			return fmt.Errorf("mysql: scan sequence %q: %w", seq.Name, err)
+		}
+	}
+	return nil
+}
+
 // columns queries and appends the columns of the given table.
 func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
 	query := columnsQuery
@@ -277,7 +341,7 @@ func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
 		c.Attrs = append(c.Attrs, a)
 	}
 	if attr.onUpdate != "" {
-		c.Attrs = append(c.Attrs, &OnUpdate{A: attr.onUpdate})
+		c.Attrs = append(c.Attrs, &OnUpdate{A: timestampPrecision(attr.onUpdate, ct)})
 	}
 	if x := expr.String; x != "" {
 		if !i.Maria() {
@@ -327,8 +391,9 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
 			table, name, indexType         string
 			nonuniq, desc                  sql.NullBool
 			column, subPart, expr, comment sql.NullString
+			visible                        sql.NullString
 		)
-		if err := rows.Scan(&table, &name, &column, &nonuniq, &seqno, &indexType, &desc, &comment, &subPart, &expr); err != nil {
+		if err := rows.Scan(&table, &name, &column, &nonuniq, &seqno, &indexType, &desc, &comment, &subPart, &expr, &visible); err != nil {
 			return fmt.Errorf("mysql: scanning indexes for schema %q: %w", s.Name, err)
 		}
 		t, ok := s.Table(table)
@@ -355,6 +420,9 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
 				if sqlx.ValidString(comment) {
 					idx.SetComment(comment.String)
 				}
+				if strings.EqualFold(visible.String, "NO") {
+					idx.AddAttrs(&IndexInvisible{})
+				}
 				t.AddIndexes(idx)
 			}
 		}
@@ -470,6 +538,10 @@ func (i *inspect) indexQuery() string {
 	if i.SupportsIndexExpr() {
 		query = indexesExprQuery
 	}
+	// Ignored indexes are the MariaDB equivalent of invisible indexes.
+	if i.Maria() && i.SupportsInvisibleIndex() {
+		query = indexesIgnoredQuery
+	}
 	return query
 }
 
@@ -508,6 +580,37 @@ func parseExtra(extra string) (*extraAttr, error) {
 	return attr, nil
 }
 
+// tablespaces queries and sets the general tablespaces of the InnoDB tables in the schema.
+// Tables that are stored in their own file (file-per-table) do not have this attribute.
+func (i *inspect) tablespaces(ctx context.Context, s *schema.Schema) error {
+	if !i.SupportsTablespaces() || !slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		var e Engine
+		return sqlx.Has(t.Attrs, &e) && strings.EqualFold(e.V, EngineInnoDB)
+	}) {
+		return nil
+	}
+	rows, err := i.QueryContext(ctx, tablespacesQuery, s.Name+"/%")
+	if err != nil {
+		return fmt.Errorf("mysql: query schema %q tablespaces: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, space string
+		if err := rows.Scan(&name, &space); err != nil {
+			return fmt.Errorf("mysql: scan tablespace information: %w", err)
+		}
+		// InnoDB names the tables in the format of "<schema>/<table>".
+		ns, tn, ok := strings.Cut(name, "/")
+		if !ok || ns != s.Name {
+			continue
+		}
+		if t, ok := s.Table(tn); ok {
+			t.AddAttrs(&Tablespace{V: space})
+		}
+	}
+	return rows.Err()
+}
+
 // showCreate sets and fixes schema elements that require information from
 // the 'SHOW CREATE' command.
 func (i *inspect) showCreate(ctx context.Context, s *schema.Schema) error {
@@ -545,7 +648,40 @@ func (i *inspect) createStmt(ctx context.Context, t *schema.Table) (*CreateStmt,
 	return c, nil
 }
 
-var reCurrTimestamp = regexp.MustCompile(`(?i)^current_timestamp(?:\(\d?\))?$`)
+var (
+	reCurrTimestamp = regexp.MustCompile(`(?i)^current_timestamp(?:\(\d?\))?$`)
+	// reTimestampFn matches CURRENT_TIMESTAMP and its synonyms, optionally wrapped
+	// with parens and with an explicit fractional seconds precision.
+	reTimestampFn = regexp.MustCompile(`(?i)^\(?\s*(current_timestamp|localtimestamp|localtime|now)\s*(?:\(\s*(\d?)\s*\))?\s*\)?$`)
+)
+
+// currentTimestamp reports if the given expression is CURRENT_TIMESTAMP (or
+// one of its synonyms) and returns its fractional seconds precision.
+func currentTimestamp(x string) (int, bool) {
+	m := reTimestampFn.FindStringSubmatch(strings.TrimSpace(x))
+	if m == nil {
+		return 0, false
+	}
+	if m[2] == "" {
+		return 0, true
+	}
+	p, err := strconv.Atoi(m[2])
+	return p, err == nil
+}
+
+// timestampPrecision sets the fractional seconds precision of CURRENT_TIMESTAMP expressions
+// that were reported without it, based on the column type. MySQL requires the precision of
+// the expression to match the column precision, but old versions omit it in EXTRA.
+func timestampPrecision(x string, t schema.Type) string {
+	tt, ok := t.(*schema.TimeType)
+	if !ok || tt.Precision == nil || *tt.Precision == 0 || !reCurrTimestamp.MatchString(x) {
+		return x
+	}
+	if p, _ := currentTimestamp(x); p > 0 {
+		return x
+	}
+	return fmt.Sprintf("%s(%d)", strings.TrimSuffix(x, "()"), *tt.Precision)
+}
 
 // myDefaultExpr returns the correct schema.Expr based on the column attributes for MySQL.
 func (i *inspect) myDefaultExpr(c *schema.Column, x string, attr *extraAttr) schema.Expr {
@@ -675,9 +811,10 @@ const (
 	columnsExprQuery = "SELECT `TABLE_NAME`, `COLUMN_NAME`, `COLUMN_TYPE`, `COLUMN_COMMENT`, `IS_NULLABLE`, `COLUMN_KEY`, `COLUMN_DEFAULT`, `EXTRA`, `CHARACTER_SET_NAME`, `COLLATION_NAME`, `GENERATION_EXPRESSION` FROM `INFORMATION_SCHEMA`.`COLUMNS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `ORDINAL_POSITION`"
 
 	// Query to list table indexes.
-	indexesQuery          = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
-	indexesExprQuery      = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
-	indexesNoCommentQuery = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, NULL AS `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
+	indexesQuery          = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION`, 'YES' AS `IS_VISIBLE` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
+	indexesExprQuery      = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, `EXPRESSION`, `IS_VISIBLE` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
+	indexesIgnoredQuery   = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION`, IF(`IGNORED` = 'YES', 'NO', 'YES') AS `IS_VISIBLE` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
+	indexesNoCommentQuery = "SELECT `TABLE_NAME`, `INDEX_NAME`, `COLUMN_NAME`, `NON_UNIQUE`, `SEQ_IN_INDEX`, `INDEX_TYPE`, UPPER(`COLLATION`) = 'D' AS `DESC`, NULL AS `INDEX_COMMENT`, `SUB_PART`, NULL AS `EXPRESSION`, 'YES' AS `IS_VISIBLE` FROM `INFORMATION_SCHEMA`.`STATISTICS` WHERE `TABLE_SCHEMA` = ? AND `TABLE_NAME` IN (%s) ORDER BY `index_name`, `seq_in_index`"
 
 	tablesQuery = `
 SELECT
@@ -703,6 +840,21 @@ WHERE
 ORDER BY
 	TABLE_SCHEMA, TABLE_NAME`
 
+	// Query to list the InnoDB tables of a schema that are stored in general tablespaces.
+	tablespacesQuery = `
+SELECT
+	t1.NAME,
+	t2.NAME
+FROM
+	INFORMATION_SCHEMA.INNODB_TABLES AS t1
+	JOIN INFORMATION_SCHEMA.INNODB_TABLESPACES AS t2
+	ON t1.SPACE = t2.SPACE
+WHERE
+	t1.NAME LIKE ?
+	AND t2.SPACE_TYPE = 'General'
+ORDER BY
+	t1.NAME`
+
 	tablesQueryArgs = `
 SELECT
 	t1.TABLE_SCHEMA,
@@ -762,6 +914,12 @@ WHERE
 ORDER BY
 	TABLE_NAME, CONSTRAINT_NAME
 `
+	// Query to list MariaDB sequences.
+	sequencesQuery = "SELECT `TABLE_SCHEMA`, `TABLE_NAME`, `TABLE_COMMENT` FROM `INFORMATION_SCHEMA`.`TABLES` WHERE `TABLE_SCHEMA` IN (%s) AND `TABLE_TYPE` = 'SEQUENCE' ORDER BY `TABLE_SCHEMA`, `TABLE_NAME`"
+
+	// Query to get the options of a MariaDB sequence from its underlying table.
+	sequenceQuery = "SELECT `start_value`, `minimum_value`, `maximum_value`, `increment`, `cache_size`, `cycle_option` FROM %s"
+
 	// Query to list table foreign keys.
 	fksQuery = `
 SELECT
@@ -816,6 +974,14 @@ type (
 		Default bool   // The default engine used by the server.
 	}
 
+	// Tablespace attribute describes the general tablespace an InnoDB table is stored in.
+	// Tables that are stored in their own file (file-per-table) do not have this attribute.
+	// See: https://dev.mysql.com/doc/refman/8.0/en/general-tablespaces.html
+	Tablespace struct {
+		schema.Attr
+		V string
+	}
+
 	// SystemVersioned is an attribute attached to MariaDB tables indicates they are
 	// system versioned. See: https://mariadb.com/kb/en/system-versioned-tables
 	SystemVersioned struct {
@@ -866,6 +1032,14 @@ type (
 		P string // Name of the parser plugin. e.g., ngram or mecab.
 	}
 
+	// IndexInvisible describes an index that is maintained by the
+	// storage engine, but is not used by the optimizer. Supported
+	// by MySQL 8. See: https://dev.mysql.com/doc/refman/8.0/en/invisible-indexes.html
+	// and by MariaDB 10.6 as ignored indexes. See: https://mariadb.com/kb/en/ignored-indexes
+	IndexInvisible struct {
+		schema.Attr
+	}
+
 	// BitType represents the type bit.
 	BitType struct {
 		schema.Type
@@ -885,6 +1059,21 @@ type (
 		T string
 	}
 
+	// Sequence describes a MariaDB sequence. Zero values of the numeric
+	// options indicate the server defaults (e.g., START WITH 1).
+	// See: https://mariadb.com/kb/en/create-sequence
+	Sequence struct {
+		schema.Object
+		Name      string
+		Schema    *schema.Schema
+		Start     int64
+		Increment int64
+		Min, Max  int64
+		Cache     int64
+		Cycle     bool
+		Attrs     []schema.Attr // Additional attributes (e.g., comments).
+	}
+
 	// putShow is an intermediate table attribute used
 	// on inspection to indicate if the 'SHOW TABLE' is
 	// required and for what.
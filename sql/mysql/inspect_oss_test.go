@@ -27,6 +27,7 @@ var (
 	queryColumnsNoExpr    = sqltest.Escape(fmt.Sprintf(columnsQuery, "?"))
 	queryIndexes          = sqltest.Escape(fmt.Sprintf(indexesQuery, "?"))
 	queryIndexesNoComment = sqltest.Escape(fmt.Sprintf(indexesNoCommentQuery, "?"))
+	queryIndexesIgnored   = sqltest.Escape(fmt.Sprintf(indexesIgnoredQuery, "?"))
 	queryIndexesExpr      = sqltest.Escape(fmt.Sprintf(indexesExprQuery, "?"))
 	queryMyChecks         = sqltest.Escape(fmt.Sprintf(myChecksQuery, "?"))
 	queryMarChecks        = sqltest.Escape(fmt.Sprintf(marChecksQuery, "?"))
@@ -63,13 +64,23 @@ func TestDriver_InspectTable(t *testing.T) {
 				m.ExpectQuery(queryIndexesExpr).
 					WithArgs("public", "users").
 					WillReturnRows(sqltest.Rows(`
-+--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
-| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       |
-+--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
-| users              | PRIMARY      | id          |          0 |            1 | BTREE        | 0        |              |       NULL |      NULL        |
-+--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+------------+
+| TABLE_NAME         | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC     | COMMENT      | SUB_PART   | EXPRESSION       | IS_VISIBLE |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+------------+
+| users              | PRIMARY      | id          |          0 |            1 | BTREE        | 0        |              |       NULL |      NULL        | YES        |
++--------------------+--------------+-------------+------------+--------------+--------------+----------+--------------+------------+------------------+------------+
 `))
 				m.noFKs()
+				m.ExpectQuery(sqltest.Escape(tablespacesQuery)).
+					WithArgs("public/%").
+					WillReturnRows(sqltest.Rows(`
++--------------+---------+
+| NAME         | NAME    |
++--------------+---------+
+| public/users | archive |
+| other/users  | archive |
++--------------+---------+
+`))
 				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
 					WillReturnRows(sqltest.Rows(`
 +-------+---------------------------------------------------------------------------------------------------------------------------------------------+
@@ -88,6 +99,7 @@ func TestDriver_InspectTable(t *testing.T) {
 					&schema.Comment{Text: "Comment"},
 					&CreateOptions{V: `COMPRESSION="ZLIB"`},
 					&Engine{V: "InnoDB", Default: true},
+					&Tablespace{V: "archive"},
 					&CreateStmt{S: "CREATE TABLE users (id bigint NOT NULL AUTO_INCREMENT) ENGINE=InnoDB AUTO_INCREMENT=55834574848 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin"},
 					&AutoIncrement{V: 55834574848},
 				}, t.Attrs)
@@ -134,6 +146,7 @@ func TestDriver_InspectTable(t *testing.T) {
 `))
 				m.noIndexes()
 				m.noFKs()
+				m.noTablespaces("public")
 			},
 			expect: func(require *require.Assertions, t *schema.Table, err error) {
 				require.NoError(err)
@@ -178,7 +191,7 @@ func TestDriver_InspectTable(t *testing.T) {
 | users      |  inet6         | inet6                         |                      | NO          |            | NULL           |                | NULL               | NULL           | NULL                      |
 +------------+----------------+-------------------------------+----------------------+-------------+------------+----------------+----------------+--------------------+----------------+---------------------------+
 `))
-				m.ExpectQuery(queryIndexes).
+				m.ExpectQuery(queryIndexesIgnored).
 					WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "column_name", "non_unique", "key_part", "expression"}))
 				m.noFKs()
 				m.ExpectQuery(queryMarChecks).
@@ -191,6 +204,7 @@ func TestDriver_InspectTable(t *testing.T) {
 | users  | users_chk_1      | longtext <> '\'\'""'                      |  YES       |
 +--------+------------------+-------------------------------------------+------------+
 `))
+				m.noSequences("public")
 			},
 			expect: func(require *require.Assertions, t *schema.Table, err error) {
 				require.NoError(err)
@@ -211,6 +225,45 @@ func TestDriver_InspectTable(t *testing.T) {
 				}, t.Attrs)
 			},
 		},
+		{
+			name:    "maria/ignored_indexes",
+			version: "10.6.4-MariaDB",
+			before: func(m mock) {
+				m.tableExists("public", "users", true)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+| table_name | column_name | column_type | column_comment | is_nullable | column_key | column_default | extra | character_set_name | collation_name | generation_expression |
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+| users      | id          | bigint(20)  |                | NO          | PRI        | NULL           |       | NULL               | NULL           | NULL                  |
+| users      | oid         | bigint(20)  |                | NO          | MUL        | NULL           |       | NULL               | NULL           | NULL                  |
++------------+-------------+-------------+----------------+-------------+------------+----------------+-------+--------------------+----------------+-----------------------+
+`))
+				m.ExpectQuery(queryIndexesIgnored).
+					WithArgs("public", "users").
+					WillReturnRows(sqltest.Rows(`
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| TABLE_NAME   | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC    | COMMENT      | SUB_PART   | EXPRESSION       | IS_VISIBLE |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| users        | ignored      | oid         |          1 |            1 | BTREE        | 0       |              |       NULL |      NULL        | NO         |
+| users        | PRIMARY      | id          |          0 |            1 | BTREE        | 0       |              |       NULL |      NULL        | YES        |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+`))
+				m.noFKs()
+				m.ExpectQuery(queryMarChecks).
+					WithArgs("public", "users").
+					WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "check_clause", "enforced"}))
+				m.noSequences("public")
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.Indexes, 1)
+				require.Equal("ignored", t.Indexes[0].Name)
+				require.EqualValues([]schema.Attr{&IndexType{T: "BTREE"}, &IndexInvisible{}}, t.Indexes[0].Attrs)
+				require.False(sqlx.Has(t.PrimaryKey.Attrs, &IndexInvisible{}))
+			},
+		},
 		{
 			name: "decimal types",
 			before: func(m mock) {
@@ -417,6 +470,7 @@ func TestDriver_InspectTable(t *testing.T) {
 | users      | c5          | year(4)      |                   | NO          |            | NULL                 |                                | NULL               | NULL           | NULL                      |
 | users      | c6          | year         |                   | NO          |            | NULL                 |                                | NULL               | NULL           | NULL                      |
 | users      | c7          | timestamp(6) |                   | NO          |            | CURRENT_TIMESTAMP(6) | on update CURRENT_TIMESTAMP(6) | NULL               | NULL           | NULL                      |
+| users      | c8          | datetime(3)  |                   | NO          |            | CURRENT_TIMESTAMP(3) | on update CURRENT_TIMESTAMP    | NULL               | NULL           | NULL                      |
 +------------+--------------+-------------------+-------------+------------+----------------------+--------------------------------+--------------------+-------------+----------------+---------------------------+
 `))
 				m.noIndexes()
@@ -434,6 +488,8 @@ func TestDriver_InspectTable(t *testing.T) {
 					{Name: "c5", Type: &schema.ColumnType{Raw: "year(4)", Type: &schema.TimeType{T: "year", Precision: p(4)}}},
 					{Name: "c6", Type: &schema.ColumnType{Raw: "year", Type: &schema.TimeType{T: "year"}}},
 					{Name: "c7", Type: &schema.ColumnType{Raw: "timestamp(6)", Type: &schema.TimeType{T: "timestamp", Precision: p(6)}}, Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"}, Attrs: []schema.Attr{&OnUpdate{A: "CURRENT_TIMESTAMP(6)"}}},
+					// Old versions omit the precision of the ON UPDATE clause.
+					{Name: "c8", Type: &schema.ColumnType{Raw: "datetime(3)", Type: &schema.TimeType{T: "datetime", Precision: p(3)}}, Default: &schema.RawExpr{X: "CURRENT_TIMESTAMP(3)"}, Attrs: []schema.Attr{&OnUpdate{A: "CURRENT_TIMESTAMP(3)"}}},
 				}, t.Columns)
 			},
 		},
@@ -550,17 +606,17 @@ func TestDriver_InspectTable(t *testing.T) {
 				m.ExpectQuery(queryIndexesExpr).
 					WithArgs("public", "users").
 					WillReturnRows(sqltest.Rows(`
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
-| TABLE_NAME   | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC    | COMMENT      | SUB_PART   | EXPRESSION       |
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
-| users        | nickname     | nickname    |          0 |            1 | BTREE        | nil     |              |        255 |      NULL        |
-| users        | lower_nick   | NULL        |          1 |            1 | HASH         | 0       |              |       NULL | lower(nickname)  |
-| users        | non_unique   | oid         |          1 |            1 | BTREE        | 0       |              |       NULL |      NULL        |
-| users        | non_unique   | uid         |          1 |            2 | BTREE        | 0       |              |       NULL |      NULL        |
-| users        | PRIMARY      | id          |          0 |            1 | BTREE        | 0       |              |       NULL |      NULL        |
-| users        | unique_index | uid         |          0 |            1 | BTREE        | 1       |              |       NULL |      NULL        |
-| users        | unique_index | oid         |          0 |            2 | BTREE        | 1       |              |       NULL |      NULL        |
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| TABLE_NAME   | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC    | COMMENT      | SUB_PART   | EXPRESSION       | IS_VISIBLE |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| users        | nickname     | nickname    |          0 |            1 | BTREE        | nil     |              |        255 |      NULL        | YES        |
+| users        | lower_nick   | NULL        |          1 |            1 | HASH         | 0       |              |       NULL | lower(nickname)  | YES        |
+| users        | non_unique   | oid         |          1 |            1 | BTREE        | 0       |              |       NULL |      NULL        | NO         |
+| users        | non_unique   | uid         |          1 |            2 | BTREE        | 0       |              |       NULL |      NULL        | YES        |
+| users        | PRIMARY      | id          |          0 |            1 | BTREE        | 0       |              |       NULL |      NULL        | YES        |
+| users        | unique_index | uid         |          0 |            1 | BTREE        | 1       |              |       NULL |      NULL        | YES        |
+| users        | unique_index | oid         |          0 |            2 | BTREE        | 1       |              |       NULL |      NULL        | YES        |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
 `))
 				m.noFKs()
 				m.ExpectQuery(sqltest.Escape("SHOW CREATE TABLE `public`.`users`")).
@@ -579,7 +635,7 @@ func TestDriver_InspectTable(t *testing.T) {
 				indexes := []*schema.Index{
 					{Name: "nickname", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "BTREE"}}}, // Implicitly created by the UNIQUE clause.
 					{Name: "lower_nick", Table: t, Attrs: []schema.Attr{&IndexType{T: "HASH"}}},
-					{Name: "non_unique", Table: t, Attrs: []schema.Attr{&IndexType{T: "BTREE"}}},
+					{Name: "non_unique", Table: t, Attrs: []schema.Attr{&IndexType{T: "BTREE"}, &IndexInvisible{}}},
 					{Name: "unique_index", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "BTREE"}}},
 				}
 				columns := []*schema.Column{
@@ -627,11 +683,11 @@ func TestDriver_InspectTable(t *testing.T) {
 				m.ExpectQuery(queryIndexesNoComment).
 					WithArgs("public", "users").
 					WillReturnRows(sqltest.Rows(`
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
-| TABLE_NAME   | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC    | COMMENT      | SUB_PART   | EXPRESSION       |
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
-| users        | PRIMARY      | id          |          0 |            1 | BTREE        | 0       | NULL         |       NULL |      NULL        |
-+--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| TABLE_NAME   | INDEX_NAME   | COLUMN_NAME | NON_UNIQUE | SEQ_IN_INDEX | INDEX_TYPE   | DESC    | COMMENT      | SUB_PART   | EXPRESSION       | IS_VISIBLE |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
+| users        | PRIMARY      | id          |          0 |            1 | BTREE        | 0       | NULL         |       NULL |      NULL        | YES        |
++--------------+--------------+-------------+------------+--------------+--------------+---------+--------------+------------+------------------+------------+
 `))
 				m.noFKs()
 			},
@@ -772,7 +828,13 @@ func TestDriver_InspectTable(t *testing.T) {
 			})
 			require.NoError(t, err)
 			require.NotNil(t, s)
-			tt.expect(require.New(t), s.Tables[0], err)
+			// The checksum of the table is cached on inspection.
+			t1 := s.Tables[0]
+			c, ok := t1.Attrs[len(t1.Attrs)-1].(*schema.Checksum)
+			require.True(t, ok)
+			t1.Attrs = t1.Attrs[:len(t1.Attrs)-1]
+			require.Equal(t, schema.ChecksumOf(t1), c.V)
+			tt.expect(require.New(t), t1, err)
 		})
 	}
 }
@@ -1053,6 +1115,55 @@ func TestInspectMode_InspectRealm(t *testing.T) {
 	}(), realm)
 }
 
+func TestDriver_InspectSequences(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("10.3.1-MariaDB")
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, "= ?"))).
+		WithArgs("test").
+		WillReturnRows(sqltest.Rows(`
++-------------+----------------------------+------------------------+
+| SCHEMA_NAME | DEFAULT_CHARACTER_SET_NAME | DEFAULT_COLLATION_NAME |
++-------------+----------------------------+------------------------+
+| test        | latin1                     | lain1_ci               |
++-------------+----------------------------+------------------------+
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "?"))).
+		WithArgs("test").
+		WillReturnRows(sqltest.Rows(`
++--------------+------------+---------------+
+| TABLE_SCHEMA | TABLE_NAME | TABLE_COMMENT |
++--------------+------------+---------------+
+| test         | s1         |               |
+| test         | s2         | comment       |
++--------------+------------+---------------+
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequenceQuery, "`test`.`s1`"))).
+		WillReturnRows(sqltest.Rows(`
++-------------+---------------+---------------------+-----------+------------+--------------+
+| start_value | minimum_value | maximum_value       | increment | cache_size | cycle_option |
++-------------+---------------+---------------------+-----------+------------+--------------+
+| 1           | 1             | 9223372036854775806 | 1         | 1000       | 0            |
++-------------+---------------+---------------------+-----------+------------+--------------+
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequenceQuery, "`test`.`s2`"))).
+		WillReturnRows(sqltest.Rows(`
++-------------+---------------+---------------+-----------+------------+--------------+
+| start_value | minimum_value | maximum_value | increment | cache_size | cycle_option |
++-------------+---------------+---------------+-----------+------------+--------------+
+| 100         | 10            | 1000          | 10        | 0          | 1            |
++-------------+---------------+---------------+-----------+------------+--------------+
+`))
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s, err := drv.InspectSchema(context.Background(), "test", &schema.InspectOptions{Mode: schema.InspectObjects})
+	require.NoError(t, err)
+	require.Len(t, s.Objects, 2)
+	require.Equal(t, &Sequence{Name: "s1", Schema: s, Start: 1, Min: 1, Max: 9223372036854775806, Increment: 1, Cache: 1000}, s.Objects[0])
+	require.Equal(t, &Sequence{Name: "s2", Schema: s, Start: 100, Min: 10, Max: 1000, Increment: 10, Cycle: true, Attrs: []schema.Attr{&schema.Comment{Text: "comment"}}}, s.Objects[1])
+}
+
 type mock struct {
 	sqlmock.Sqlmock
 }
@@ -1079,11 +1190,23 @@ func (m mock) lcmode(version, mode string) {
 `))
 }
 
+func (m mock) noSequences(schema string) {
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "?"))).
+		WithArgs(schema).
+		WillReturnRows(sqlmock.NewRows([]string{"TABLE_SCHEMA", "TABLE_NAME", "TABLE_COMMENT"}))
+}
+
 func (m mock) noIndexes() {
 	m.ExpectQuery(queryIndexesExpr).
 		WillReturnRows(sqlmock.NewRows([]string{"table_name", "index_name", "column_name", "non_unique", "key_part", "expression"}))
 }
 
+func (m mock) noTablespaces(schema string) {
+	m.ExpectQuery(sqltest.Escape(tablespacesQuery)).
+		WithArgs(schema + "/%").
+		WillReturnRows(sqlmock.NewRows([]string{"NAME", "NAME"}))
+}
+
 func (m mock) noFKs() {
 	m.ExpectQuery(queryFKs).
 		WillReturnRows(sqlmock.NewRows([]string{"TABLE_NAME", "CONSTRAINT_NAME", "TABLE_NAME", "COLUMN_NAME", "REFERENCED_TABLE_NAME", "REFERENCED_COLUMN_NAME", "REFERENCED_TABLE_SCHEMA", "UPDATE_RULE", "DELETE_RULE"}))
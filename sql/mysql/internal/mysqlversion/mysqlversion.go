@@ -36,6 +36,15 @@ func (v V) SupportsIndexExpr() bool {
 	return !v.Maria() && v.GTE("8.0.13")
 }
 
+// SupportsInvisibleIndex reports if the version supports invisible indexes. MySQL
+// uses the VISIBLE and INVISIBLE options, and MariaDB uses IGNORED and NOT IGNORED.
+func (v V) SupportsInvisibleIndex() bool {
+	if v.Maria() {
+		return v.GTE("10.6")
+	}
+	return v.GTE("8")
+}
+
 // SupportsDisplayWidth reports if the version supports getting
 // the display width information from the information schema.
 func (v V) SupportsDisplayWidth() bool {
@@ -94,6 +103,34 @@ func (v V) SupportsViewUsage() bool {
 	return !v.Maria() && v.GTE("8.0.13")
 }
 
+// SupportsSequences reports if the version supports sequences
+// (CREATE SEQUENCE). Sequences were added in MariaDB 10.3.
+func (v V) SupportsSequences() bool {
+	return v.Maria() && v.GTE("10.3")
+}
+
+// SupportsAtomicDDL reports if the version supports atomic DDL statements,
+// backed by the transactional data dictionary that was added in MySQL 8.0.
+func (v V) SupportsAtomicDDL() bool {
+	return !v.Maria() && !v.TiDB() && v.GTE("8")
+}
+
+// SupportsTablespaces reports if the version supports querying the general
+// tablespaces of InnoDB tables. The INNODB_TABLES and INNODB_TABLESPACES
+// tables were added to INFORMATION_SCHEMA in MySQL 8.0.3.
+func (v V) SupportsTablespaces() bool {
+	return !v.Maria() && !v.TiDB() && v.GTE("8.0.3")
+}
+
+// NationalCharset returns the character set of the national types (e.g., NCHAR).
+// The utf8 character set was renamed to utf8mb3 in MySQL 8.0.30 and MariaDB 10.6.
+func (v V) NationalCharset() string {
+	if v.Maria() && v.GTE("10.6") || !v.Maria() && !v.TiDB() && v.GTE("8.0.30") {
+		return "utf8mb3"
+	}
+	return "utf8"
+}
+
 // CharsetToCollate returns the mapping from charset to its default collation.
 func (v V) CharsetToCollate(conn schema.ExecQuerier) (map[string]string, error) {
 	name := "is/charset2collate"
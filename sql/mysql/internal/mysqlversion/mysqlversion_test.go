@@ -35,6 +35,30 @@ func TestV_SupportsGeneratedColumns(t *testing.T) {
 	}
 }
 
+func TestV_SupportsSequences(t *testing.T) {
+	for v, want := range map[string]bool{
+		"5.7":                    false,
+		"8.0.32":                 false,
+		"10.2.1-MariaDB":         false,
+		"10.3.1-MariaDB":         true,
+		"11.4.2-MariaDB-ubu2404": true,
+	} {
+		require.Equal(t, want, mysqlversion.V(v).SupportsSequences(), v)
+	}
+}
+
+func TestV_NationalCharset(t *testing.T) {
+	for v, want := range map[string]string{
+		"5.7":                    "utf8",
+		"8.0.29":                 "utf8",
+		"8.0.30":                 "utf8mb3",
+		"10.5.1-MariaDB":         "utf8",
+		"11.4.2-MariaDB-ubu2404": "utf8mb3",
+	} {
+		require.Equal(t, want, mysqlversion.V(v).NationalCharset(), v)
+	}
+}
+
 func TestV_CollateToCharset(t *testing.T) {
 	c2c, err := mysqlversion.V("8.0.0").CollateToCharset(nil)
 	require.NoError(t, err)
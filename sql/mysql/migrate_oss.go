@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/migrate"
@@ -46,6 +47,9 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 	if err := verifyChanges(ctx, changes); err != nil {
 		return nil, err
 	}
+	if err := identChecker(s.PlanOptions).CheckChanges(changes); err != nil {
+		return nil, err
+	}
 	if err := s.plan(changes); err != nil {
 		return nil, err
 	}
@@ -90,6 +94,9 @@ func (s *state) plan(changes []schema.Change) error {
 		}
 		planned = sqlx.SortChanges(planned, nil)
 	}
+	if s.combine() {
+		planned = combineModify(planned)
+	}
 	for _, c := range planned {
 		switch c := c.(type) {
 		case *schema.AddTable:
@@ -100,6 +107,8 @@ func (s *state) plan(changes []schema.Change) error {
 			err = s.modifyTable(c)
 		case *schema.RenameTable:
 			s.renameTable(c)
+		case *schema.DropObject:
+			err = s.dropObject(c)
 		default:
 			err = fmt.Errorf("unsupported change %T", c)
 		}
@@ -150,6 +159,16 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 			if err := s.modifySchema(c); err != nil {
 				return nil, err
 			}
+		// Sequences are created before tables, as they might be
+		// referenced by column defaults (e.g., NEXT VALUE FOR).
+		case *schema.AddObject:
+			if err := s.addObject(c); err != nil {
+				return nil, err
+			}
+		case *schema.ModifyObject:
+			if err := s.modifyObject(c); err != nil {
+				return nil, err
+			}
 		default:
 			planned = append(planned, c)
 		}
@@ -157,6 +176,138 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 	return planned, nil
 }
 
+// addObject builds and appends the migrate.Change for creating a schema object.
+func (s *state) addObject(add *schema.AddObject) error {
+	seq, ok := add.O.(*Sequence)
+	if !ok {
+		return fmt.Errorf("unsupported object type %T", add.O)
+	}
+	if !s.SupportsSequences() {
+		return fmt.Errorf("mysql: sequences are not supported by this version (%s). MariaDB 10.3 or above is required", s.V)
+	}
+	b := s.Build("CREATE SEQUENCE")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.SchemaResource(seq.Schema, seq.Name)
+	seqOpts(b, seq, nil)
+	if c := (schema.Comment{}); sqlx.Has(seq.Attrs, &c) && c.Text != "" {
+		b.P("COMMENT", quote(c.Text))
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP SEQUENCE").SchemaResource(seq.Schema, seq.Name).String(),
+		Comment: fmt.Sprintf("create sequence %q", seq.Name),
+	})
+	return nil
+}
+
+// modifyObject builds and appends the migrate.Change for altering a schema object.
+func (s *state) modifyObject(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Sequence)
+	to, ok2 := modify.To.(*Sequence)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("unsupported object types %T and %T", modify.From, modify.To)
+	}
+	if !s.SupportsSequences() {
+		return fmt.Errorf("mysql: sequences are not supported by this version (%s). MariaDB 10.3 or above is required", s.V)
+	}
+	b, r := s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name), s.Build("ALTER SEQUENCE").SchemaResource(from.Schema, from.Name)
+	if seqOpts(b, to, from) {
+		seqOpts(r, from, to)
+		s.append(&migrate.Change{
+			Cmd:     b.String(),
+			Source:  modify,
+			Reverse: r.String(),
+			Comment: fmt.Sprintf("modify sequence %q", to.Name),
+		})
+	}
+	// Sequence comments are table options, and cannot be changed using ALTER SEQUENCE.
+	if sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange {
+		var c1, c2 schema.Comment
+		sqlx.Has(from.Attrs, &c1)
+		sqlx.Has(to.Attrs, &c2)
+		s.append(&migrate.Change{
+			Cmd:     s.Build("ALTER TABLE").SchemaResource(to.Schema, to.Name).P("COMMENT", quote(c2.Text)).String(),
+			Source:  modify,
+			Reverse: s.Build("ALTER TABLE").SchemaResource(from.Schema, from.Name).P("COMMENT", quote(c1.Text)).String(),
+			Comment: fmt.Sprintf("modify sequence %q comment", to.Name),
+		})
+	}
+	return nil
+}
+
+// dropObject builds and appends the migrate.Change for dropping a schema object.
+func (s *state) dropObject(drop *schema.DropObject) error {
+	seq, ok := drop.O.(*Sequence)
+	if !ok {
+		return fmt.Errorf("unsupported object type %T", drop.O)
+	}
+	b := s.Build("DROP SEQUENCE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	create := s.Build("CREATE SEQUENCE").SchemaResource(seq.Schema, seq.Name)
+	seqOpts(create, seq, nil)
+	s.append(&migrate.Change{
+		Cmd:     b.SchemaResource(seq.Schema, seq.Name).String(),
+		Source:  drop,
+		Reverse: create.String(),
+		Comment: fmt.Sprintf("drop sequence %q", seq.Name),
+	})
+	return nil
+}
+
+// seqOpts writes the options of the sequence to the builder. If the previous
+// state of the sequence is given, only the changed options are written. The
+// returned value reports if any option was written.
+func seqOpts(b *sqlx.Builder, seq, prev *Sequence) bool {
+	var (
+		changed bool
+		o       = sequenceOptions(seq)
+		p       seqOptions
+	)
+	if prev != nil {
+		p = sequenceOptions(prev)
+	}
+	if prev == nil && seq.Increment != 0 || prev != nil && o.increment != p.increment {
+		b.P("INCREMENT BY").Int64(o.increment)
+		changed = true
+	}
+	if prev == nil && seq.Min != 0 || prev != nil && o.min != p.min {
+		b.P("MINVALUE").Int64(o.min)
+		changed = true
+	}
+	if prev == nil && seq.Max != 0 || prev != nil && o.max != p.max {
+		b.P("MAXVALUE").Int64(o.max)
+		changed = true
+	}
+	switch {
+	case prev == nil && seq.Start != 0:
+		b.P("START WITH").Int64(o.start)
+		changed = true
+	case prev != nil && o.start != p.start:
+		// Changing the start value of an existing sequence
+		// restarts it, as the START value is not used afterwards.
+		b.P("RESTART WITH").Int64(o.start)
+		changed = true
+	}
+	if prev == nil && seq.Cache != 0 || prev != nil && o.cache != p.cache {
+		b.P("CACHE").Int64(o.cache)
+		changed = true
+	}
+	if prev == nil && seq.Cycle || prev != nil && o.cycle != p.cycle {
+		if o.cycle {
+			b.P("CYCLE")
+		} else {
+			b.P("NOCYCLE")
+		}
+		changed = true
+	}
+	return changed
+}
+
 // modifySchema builds and appends the migrate.Changes for bringing
 // the schema into its modified state.
 func (s *state) modifySchema(modify *schema.ModifySchema) error {
@@ -246,8 +397,9 @@ func (s *state) addTable(add *schema.AddTable) error {
 			b.Comma()
 		}
 		b.MapIndent(add.T.Indexes, func(i int, b *sqlx.Builder) {
-			idx := add.T.Indexes[i]
-			index(b, idx)
+			if err := s.index(b, add.T.Indexes[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
 		})
 		if len(add.T.ForeignKeys) > 0 {
 			b.Comma()
@@ -325,28 +477,90 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			changes[1] = append(changes[1], &schema.AddForeignKey{
 				F: change.To,
 			})
-		// Index modification requires rebuilding the index.
+		// Changing only the index visibility does not require rebuilding it.
 		case *schema.ModifyIndex:
-			changes[0] = append(changes[0], &schema.DropIndex{
-				I: change.From,
-			})
-			changes[1] = append(changes[1], &schema.AddIndex{
-				I: change.To,
-			})
+			if visibilityChanged(change) {
+				changes[1] = append(changes[1], change)
+				break
+			}
+			// Index modification requires rebuilding the index. With atomic
+			// DDL, the index is dropped and re-created in the same statement.
+			drop, add := &schema.DropIndex{I: change.From}, &schema.AddIndex{I: change.To}
+			if s.combine() {
+				changes[1] = append(changes[1], drop, add)
+			} else {
+				changes[0] = append(changes[0], drop)
+				changes[1] = append(changes[1], add)
+			}
 		default:
 			changes[1] = append(changes[1], change)
 		}
 	}
 	for i := range changes {
-		if len(changes[i]) > 0 {
+		if len(changes[i]) == 0 {
+			continue
+		}
+		if !s.NoCombine {
 			if err := s.alterTable(modify.T, changes[i]); err != nil {
 				return err
 			}
+			continue
+		}
+		for _, c := range changes[i] {
+			if err := s.alterTable(modify.T, []schema.Change{c}); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
+// combine reports if the changes of a table that are planned separately
+// (e.g., index rebuilds) can be combined into one ALTER TABLE statement.
+// MySQL 8 executes it atomically, which reduces the number of table
+// rebuilds and the time metadata locks are held.
+func (s *state) combine() bool {
+	return !s.NoCombine && s.SupportsAtomicDDL()
+}
+
+// combineModify combines adjacent ModifyTable changes of the same table.
+// Only adjacent changes are combined, to keep the order of the sorted plan.
+func combineModify(changes []schema.Change) []schema.Change {
+	combined := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		m, ok := c.(*schema.ModifyTable)
+		if !ok || len(combined) == 0 {
+			combined = append(combined, c)
+			continue
+		}
+		prev, ok := combined[len(combined)-1].(*schema.ModifyTable)
+		if !ok || !sameTable(prev.T, m.T) {
+			combined = append(combined, c)
+			continue
+		}
+		combined[len(combined)-1] = &schema.ModifyTable{
+			T:       prev.T,
+			Changes: append(append(make([]schema.Change, 0, len(prev.Changes)+len(m.Changes)), prev.Changes...), m.Changes...),
+		}
+	}
+	return combined
+}
+
+// sameTable reports if the two tables are the same table.
+func sameTable(t1, t2 *schema.Table) bool {
+	if t1 == t2 {
+		return true
+	}
+	var s1, s2 string
+	if t1.Schema != nil {
+		s1 = t1.Schema.Name
+	}
+	if t2.Schema != nil {
+		s2 = t2.Schema.Name
+	}
+	return t1.Name == t2.Name && s1 == s2
+}
+
 // alterTable modifies the given table by executing on it a list of
 // changes in one SQL statement.
 func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
@@ -404,7 +618,9 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 				reverse = append(reverse, &schema.AddColumn{C: change.C})
 			case *schema.AddIndex:
 				b.P("ADD")
-				index(b, change.I)
+				if err := s.index(b, change.I); err != nil {
+					return err
+				}
 				reverse = append(reverse, &schema.DropIndex{I: change.I})
 			case *schema.RenameIndex:
 				b.P("RENAME INDEX").Ident(change.From.Name).P("TO").Ident(change.To.Name)
@@ -412,6 +628,12 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 			case *schema.DropIndex:
 				b.P("DROP INDEX").Ident(change.I.Name)
 				reverse = append(reverse, &schema.AddIndex{I: change.I})
+			case *schema.ModifyIndex:
+				b.P("ALTER INDEX").Ident(change.To.Name)
+				if err := s.visibility(b, change.To); err != nil {
+					return err
+				}
+				reverse = append(reverse, &schema.ModifyIndex{From: change.To, To: change.From, Change: change.Change})
 			case *schema.AddPrimaryKey:
 				b.P("ADD PRIMARY KEY")
 				indexTypeParts(b, change.P)
@@ -458,16 +680,17 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 					return errors.New("cannot modify unnamed check constraint")
 				case change.From.Name != change.To.Name:
 					return fmt.Errorf("mismatch check constraint names: %q != %q", change.From.Name, change.To.Name)
+				// Expr was changed. The enforcement
+				// is set when the check is re-added.
+				case change.From.Expr != change.To.Expr:
+					b.P("DROP CHECK").Ident(change.From.Name).Comma().P("ADD")
+					s.check(b, change.To)
 				// Enforcement added.
-				case s.SupportsEnforceCheck() && sqlx.Has(change.From.Attrs, &Enforced{}) && !sqlx.Has(change.To.Attrs, &Enforced{}):
+				case s.SupportsEnforceCheck() && !enforced(change.From.Attrs) && enforced(change.To.Attrs):
 					b.P("ALTER CHECK").Ident(change.From.Name).P("ENFORCED")
 				// Enforcement dropped.
-				case s.SupportsEnforceCheck() && !sqlx.Has(change.From.Attrs, &Enforced{}) && sqlx.Has(change.To.Attrs, &Enforced{}):
+				case s.SupportsEnforceCheck() && enforced(change.From.Attrs) && !enforced(change.To.Attrs):
 					b.P("ALTER CHECK").Ident(change.From.Name).P("NOT ENFORCED")
-				// Expr was changed.
-				case change.From.Expr != change.To.Expr:
-					b.P("DROP CHECK").Ident(change.From.Name).Comma().P("ADD")
-					s.check(b, change.To)
 				default:
 					return errors.New("unknown check constraint change")
 				}
@@ -523,12 +746,17 @@ func (s *state) column(b *sqlx.Builder, t *schema.Table, c *schema.Column) error
 	}
 	b.Ident(c.Name).P(typ)
 	if cs := (schema.Charset{}); sqlx.Has(c.Attrs, &cs) {
-		if !supportsCharset(c.Type.Type) {
+		switch {
+		case !supportsCharset(c.Type.Type):
 			return fmt.Errorf("column %q of type %T does not support the CHARSET attribute", c.Name, c.Type.Type)
-		}
+		// National types are defined without a charset, as it is implied by the type.
+		case national(c.Type.Type):
+			if cs.V != s.NationalCharset() {
+				return fmt.Errorf("column %q of type %q does not support the %q charset", c.Name, typ, cs.V)
+			}
 		// Define the charset explicitly
 		// in case it is not the default.
-		if s.character(t) != cs.V {
+		case s.character(t) != cs.V:
 			b.P("CHARSET", cs.V)
 		}
 	}
@@ -583,7 +811,7 @@ func (s *state) column(b *sqlx.Builder, t *schema.Table, c *schema.Column) error
 	return nil
 }
 
-func index(b *sqlx.Builder, idx *schema.Index) {
+func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 	switch t := indexType(idx.Attrs); {
 	case idx.Unique:
 		b.P("UNIQUE")
@@ -600,6 +828,40 @@ func index(b *sqlx.Builder, idx *schema.Index) {
 	if c := (schema.Comment{}); sqlx.Has(idx.Attrs, &c) {
 		b.P("COMMENT", quote(c.Text))
 	}
+	if sqlx.Has(idx.Attrs, &IndexInvisible{}) {
+		return s.visibility(b, idx)
+	}
+	return nil
+}
+
+// visibility writes the visibility option of the index. MySQL uses the
+// VISIBLE and INVISIBLE keywords, and MariaDB uses IGNORED and NOT IGNORED.
+func (s *state) visibility(b *sqlx.Builder, idx *schema.Index) error {
+	invisible := sqlx.Has(idx.Attrs, &IndexInvisible{})
+	switch {
+	case !s.SupportsInvisibleIndex():
+		return fmt.Errorf("mysql: invisible indexes are not supported by this version (%s). MySQL 8 or MariaDB 10.6 or above is required", s.V)
+	case s.Maria() && invisible:
+		b.P("IGNORED")
+	case s.Maria():
+		b.P("NOT IGNORED")
+	case invisible:
+		b.P("INVISIBLE")
+	default:
+		b.P("VISIBLE")
+	}
+	return nil
+}
+
+// visibilityChanged reports if the only change made to the
+// index is its visibility (VISIBLE/INVISIBLE or IGNORED).
+func visibilityChanged(m *schema.ModifyIndex) bool {
+	if m.Change != schema.ChangeAttr || sqlx.Has(m.From.Attrs, &IndexInvisible{}) == sqlx.Has(m.To.Attrs, &IndexInvisible{}) {
+		return false
+	}
+	var fromP, toP IndexParser
+	return indexType(m.From.Attrs).T == indexType(m.To.Attrs).T &&
+		sqlx.Has(m.From.Attrs, &fromP) == sqlx.Has(m.To.Attrs, &toP) && fromP.P == toP.P
 }
 
 func indexTypeParts(b *sqlx.Builder, idx *schema.Index) {
@@ -678,6 +940,14 @@ func (s *state) tableAttrs(b *sqlx.Builder, c schema.Change, attrs ...schema.Att
 			if _, ok := c.(*schema.ModifyAttr); ok || !a.Default {
 				b.P("ENGINE", a.V)
 			}
+		case *Tablespace:
+			// Moving a table out of a general tablespace is done using the file-per-table tablespace.
+			switch _, ok := c.(*schema.ModifyAttr); {
+			case a.V != "":
+				b.P("TABLESPACE").Ident(a.V)
+			case ok:
+				b.P("TABLESPACE").Ident(TablespaceFilePerTable)
+			}
 		case *schema.Check:
 			// Ignore CHECK constraints as they are not real attributes,
 			// and handled on CREATE or ALTER.
@@ -792,7 +1062,10 @@ func (s *state) check(b *sqlx.Builder, c *schema.Check) {
 		b.P("CONSTRAINT").Ident(c.Name)
 	}
 	b.P("CHECK", sqlx.MayWrap(c.Expr))
-	if s.SupportsEnforceCheck() && sqlx.Has(c.Attrs, &Enforced{}) {
+	if e := (Enforced{}); s.SupportsEnforceCheck() && sqlx.Has(c.Attrs, &e) {
+		if !e.V {
+			b.P("NOT")
+		}
 		b.P("ENFORCED")
 	}
 }
@@ -828,3 +1101,66 @@ func quote(s string) string {
 	}
 	return strconv.Quote(s)
 }
+
+// maxIdentLen is the maximum length (in characters) of identifiers in MySQL.
+const maxIdentLen = 64
+
+// identChecker returns the checker of the identifiers used by the planned changes.
+func identChecker(opts migrate.PlanOptions) *sqlx.IdentChecker {
+	c := &sqlx.IdentChecker{MaxLen: maxIdentLen, Len: utf8.RuneCountInString, Unit: "characters"}
+	if opts.CheckReserved {
+		c.Reserved = func(name string) bool {
+			_, ok := reservedWords[strings.ToLower(name)]
+			return ok
+		}
+	}
+	return c
+}
+
+// reservedWords holds the keywords that are reserved in MySQL 8.0.
+// https://dev.mysql.com/doc/refman/8.0/en/keywords.html
+var reservedWords = map[string]struct{}{
+	"accessible": {}, "add": {}, "all": {}, "alter": {}, "analyze": {}, "and": {}, "as": {},
+	"asc": {}, "asensitive": {}, "before": {}, "between": {}, "bigint": {}, "binary": {}, "blob": {},
+	"both": {}, "by": {}, "call": {}, "cascade": {}, "case": {}, "change": {}, "char": {},
+	"character": {}, "check": {}, "collate": {}, "column": {}, "condition": {}, "constraint": {},
+	"continue": {}, "convert": {}, "create": {}, "cross": {}, "cube": {}, "cume_dist": {},
+	"current_date": {}, "current_time": {}, "current_timestamp": {}, "current_user": {}, "cursor": {},
+	"database": {}, "databases": {}, "day_hour": {}, "day_microsecond": {}, "day_minute": {},
+	"day_second": {}, "dec": {}, "decimal": {}, "declare": {}, "default": {}, "delayed": {},
+	"delete": {}, "dense_rank": {}, "desc": {}, "describe": {}, "deterministic": {}, "distinct": {},
+	"distinctrow": {}, "div": {}, "double": {}, "drop": {}, "dual": {}, "each": {}, "else": {},
+	"elseif": {}, "empty": {}, "enclosed": {}, "escaped": {}, "except": {}, "exists": {}, "exit": {},
+	"explain": {}, "false": {}, "fetch": {}, "first_value": {}, "float": {}, "float4": {},
+	"float8": {}, "for": {}, "force": {}, "foreign": {}, "from": {}, "fulltext": {}, "function": {},
+	"generated": {}, "get": {}, "grant": {}, "group": {}, "grouping": {}, "groups": {}, "having": {},
+	"high_priority": {}, "hour_microsecond": {}, "hour_minute": {}, "hour_second": {}, "if": {},
+	"ignore": {}, "in": {}, "index": {}, "infile": {}, "inner": {}, "inout": {}, "insensitive": {},
+	"insert": {}, "int": {}, "int1": {}, "int2": {}, "int3": {}, "int4": {}, "int8": {},
+	"integer": {}, "intersect": {}, "interval": {}, "into": {}, "io_after_gtids": {},
+	"io_before_gtids": {}, "is": {}, "iterate": {}, "join": {}, "json_table": {}, "key": {},
+	"keys": {}, "kill": {}, "lag": {}, "last_value": {}, "lateral": {}, "lead": {}, "leading": {},
+	"leave": {}, "left": {}, "like": {}, "limit": {}, "linear": {}, "lines": {}, "load": {},
+	"localtime": {}, "localtimestamp": {}, "lock": {}, "long": {}, "longblob": {}, "longtext": {},
+	"loop": {}, "low_priority": {}, "master_bind": {}, "master_ssl_verify_server_cert": {},
+	"match": {}, "maxvalue": {}, "mediumblob": {}, "mediumint": {}, "mediumtext": {}, "middleint": {},
+	"minute_microsecond": {}, "minute_second": {}, "mod": {}, "modifies": {}, "natural": {},
+	"no_write_to_binlog": {}, "not": {}, "nth_value": {}, "ntile": {}, "null": {}, "numeric": {},
+	"of": {}, "on": {}, "optimize": {}, "optimizer_costs": {}, "option": {}, "optionally": {},
+	"or": {}, "order": {}, "out": {}, "outer": {}, "outfile": {}, "over": {}, "partition": {},
+	"percent_rank": {}, "precision": {}, "primary": {}, "procedure": {}, "purge": {}, "range": {},
+	"rank": {}, "read": {}, "read_write": {}, "reads": {}, "real": {}, "recursive": {},
+	"references": {}, "regexp": {}, "release": {}, "rename": {}, "repeat": {}, "replace": {},
+	"require": {}, "resignal": {}, "restrict": {}, "return": {}, "revoke": {}, "right": {},
+	"rlike": {}, "row": {}, "row_number": {}, "rows": {}, "schema": {}, "schemas": {},
+	"second_microsecond": {}, "select": {}, "sensitive": {}, "separator": {}, "set": {}, "show": {},
+	"signal": {}, "smallint": {}, "spatial": {}, "specific": {}, "sql": {}, "sql_big_result": {},
+	"sql_calc_found_rows": {}, "sql_small_result": {}, "sqlexception": {}, "sqlstate": {},
+	"sqlwarning": {}, "ssl": {}, "starting": {}, "stored": {}, "straight_join": {}, "system": {},
+	"table": {}, "terminated": {}, "then": {}, "tinyblob": {}, "tinyint": {}, "tinytext": {},
+	"to": {}, "trailing": {}, "trigger": {}, "true": {}, "undo": {}, "union": {}, "unique": {},
+	"unlock": {}, "unsigned": {}, "update": {}, "usage": {}, "use": {}, "using": {}, "utc_date": {},
+	"utc_time": {}, "utc_timestamp": {}, "values": {}, "varbinary": {}, "varchar": {},
+	"varcharacter": {}, "varying": {}, "virtual": {}, "when": {}, "where": {}, "while": {},
+	"window": {}, "with": {}, "write": {}, "xor": {}, "year_month": {}, "zerofill": {},
+}
@@ -35,9 +35,8 @@ func TestMigrate_ApplyChanges(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(0, 0))
 	mk.ExpectExec(sqltest.Escape("CREATE TABLE IF NOT EXISTS `public`.`pets` (`a` int NOT NULL DEFAULT (int(rand())), `b` bigint NOT NULL DEFAULT 1, `c` bigint NULL, PRIMARY KEY (`a`, `b`), UNIQUE INDEX `b_c_unique` (`b`, `c`) COMMENT \"comment\")")).
 		WillReturnResult(sqlmock.NewResult(0, 0))
-	mk.ExpectExec(sqltest.Escape("ALTER TABLE `users` DROP INDEX `id_spouse_id`")).
-		WillReturnResult(sqlmock.NewResult(0, 0))
-	mk.ExpectExec(sqltest.Escape("ALTER TABLE `users` ADD CONSTRAINT `spouse` FOREIGN KEY (`spouse_id`) REFERENCES `users` (`id`) ON DELETE SET NULL, ADD INDEX `id_spouse_id` (`spouse_id`, `id` DESC) COMMENT \"comment\"")).
+	// With atomic DDL, the index is rebuilt in the same statement.
+	mk.ExpectExec(sqltest.Escape("ALTER TABLE `users` ADD CONSTRAINT `spouse` FOREIGN KEY (`spouse_id`) REFERENCES `users` (`id`) ON DELETE SET NULL, DROP INDEX `id_spouse_id`, ADD INDEX `id_spouse_id` (`spouse_id`, `id` DESC) COMMENT \"comment\"")).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 	mk.ExpectExec(sqltest.Escape("CREATE TABLE `posts` (`id` bigint NOT NULL, `author_id` bigint NULL, CONSTRAINT `author` FOREIGN KEY (`author_id`) REFERENCES `users` (`id`))")).
 		WillReturnResult(sqlmock.NewResult(0, 0))
@@ -162,6 +161,32 @@ func TestMigrate_ApplyChanges(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// combineChanges returns two adjacent modifications of the same
+// table, one of them rebuilds an index of the table.
+func combineChanges() []schema.Change {
+	a, b := schema.NewIntColumn("a", "int"), schema.NewIntColumn("b", "int")
+	users := schema.NewTable("users").AddColumns(a, b)
+	return []schema.Change{
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: b},
+				&schema.ModifyIndex{
+					From:   schema.NewIndex("idx").AddColumns(a),
+					To:     schema.NewIndex("idx").AddColumns(a, b),
+					Change: schema.ChangeParts,
+				},
+			},
+		},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddIndex{I: schema.NewIndex("idx_b").AddColumns(b)},
+			},
+		},
+	}
+}
+
 func TestMigrate_DetachCycles(t *testing.T) {
 	migrate, mk, err := newMigrate("8.0.13")
 	require.NoError(t, err)
@@ -202,6 +227,30 @@ func TestMigrate_DetachCycles(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestPlanChanges_Idents(t *testing.T) {
+	var (
+		long  = strings.Repeat("é", 65)
+		users = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("key", "int"))
+	)
+	_, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: users},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddIndex{I: schema.NewIndex(strings.Repeat("é", 64)).AddColumns(users.Columns[0])},
+				&schema.AddForeignKey{F: schema.NewForeignKey(long)},
+			},
+		},
+	})
+	require.EqualError(t, err, `foreign key "`+long+`" of table "users" exceeds the maximum identifier length of 64 characters (65)`)
+
+	// Reserved keywords are checked only if requested.
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}}, func(o *migrate.PlanOptions) {
+		o.CheckReserved = true
+	})
+	require.EqualError(t, err, `column "key" of table "users" is named after a reserved keyword`)
+}
+
 func TestPlanChanges(t *testing.T) {
 	tests := []struct {
 		version  string
@@ -269,6 +318,50 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("logs").
+						AddColumns(schema.NewIntColumn("id", "int")).
+						AddAttrs(&Tablespace{V: "archive"}),
+				},
+				&schema.ModifyTable{
+					T: schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{
+							From: &Tablespace{V: "archive"},
+							To:   &Tablespace{V: TablespaceFilePerTable},
+						},
+					},
+				},
+				&schema.ModifyTable{
+					T: schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "int")),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{
+							From: &Tablespace{},
+							To:   &Tablespace{V: "archive"},
+						},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `logs` (`id` int NOT NULL) TABLESPACE `archive`",
+						Reverse: "DROP TABLE `logs`",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` TABLESPACE `innodb_file_per_table`",
+						Reverse: "ALTER TABLE `users` TABLESPACE `archive`",
+					},
+					{
+						Cmd:     "ALTER TABLE `pets` TABLESPACE `archive`",
+						Reverse: "ALTER TABLE `pets` TABLESPACE `innodb_file_per_table`",
+					},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				&schema.ModifyTable{
@@ -588,6 +681,99 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					t := schema.NewTable("posts").
+						AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("author_id", "bigint"))
+					from, to := schema.NewIndex("author_id").AddColumns(t.Columns[1]), schema.NewIndex("author_id").AddColumns(t.Columns[1])
+					to.AddAttrs(&IndexInvisible{})
+					return &schema.ModifyTable{
+						T: t,
+						Changes: []schema.Change{
+							&schema.AddIndex{I: schema.NewIndex("id").AddColumns(t.Columns[0]).AddAttrs(&IndexInvisible{})},
+							&schema.ModifyIndex{From: from, To: to, Change: schema.ChangeAttr},
+						},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `posts` ADD INDEX `id` (`id`) INVISIBLE, ALTER INDEX `author_id` INVISIBLE",
+						Reverse: "ALTER TABLE `posts` ALTER INDEX `author_id` VISIBLE, DROP INDEX `id`",
+					},
+				},
+			},
+		},
+		// MariaDB uses the IGNORED and NOT IGNORED keywords.
+		{
+			version: "10.6.4-MariaDB",
+			changes: []schema.Change{
+				func() schema.Change {
+					t := schema.NewTable("posts").
+						AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("author_id", "bigint"))
+					from, to := schema.NewIndex("author_id").AddColumns(t.Columns[1]), schema.NewIndex("author_id").AddColumns(t.Columns[1])
+					to.AddAttrs(&IndexInvisible{})
+					return &schema.ModifyTable{
+						T: t,
+						Changes: []schema.Change{
+							&schema.AddIndex{I: schema.NewIndex("id").AddColumns(t.Columns[0]).AddAttrs(&IndexInvisible{})},
+							&schema.ModifyIndex{From: from, To: to, Change: schema.ChangeAttr},
+						},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `posts` ADD INDEX `id` (`id`) IGNORED, ALTER INDEX `author_id` IGNORED",
+						Reverse: "ALTER TABLE `posts` ALTER INDEX `author_id` NOT IGNORED, DROP INDEX `id`",
+					},
+				},
+			},
+		},
+		// Ignored indexes were added in MariaDB 10.6.
+		{
+			version: "10.5.8-MariaDB",
+			changes: []schema.Change{
+				func() schema.Change {
+					t := schema.NewTable("posts").
+						AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("author_id", "bigint"))
+					from, to := schema.NewIndex("author_id").AddColumns(t.Columns[1]), schema.NewIndex("author_id").AddColumns(t.Columns[1])
+					to.AddAttrs(&IndexInvisible{})
+					return &schema.ModifyTable{
+						T: t,
+						Changes: []schema.Change{
+							&schema.AddIndex{I: schema.NewIndex("id").AddColumns(t.Columns[0]).AddAttrs(&IndexInvisible{})},
+							&schema.ModifyIndex{From: from, To: to, Change: schema.ChangeAttr},
+						},
+					}
+				}(),
+			},
+			wantErr: true,
+		},
+		{
+			version: "5.7.38",
+			changes: []schema.Change{
+				func() schema.Change {
+					t := schema.NewTable("posts").
+						AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewIntColumn("author_id", "bigint"))
+					from, to := schema.NewIndex("author_id").AddColumns(t.Columns[1]), schema.NewIndex("author_id").AddColumns(t.Columns[1])
+					to.AddAttrs(&IndexInvisible{})
+					return &schema.ModifyTable{
+						T: t,
+						Changes: []schema.Change{
+							&schema.AddIndex{I: schema.NewIndex("id").AddColumns(t.Columns[0]).AddAttrs(&IndexInvisible{})},
+							&schema.ModifyIndex{From: from, To: to, Change: schema.ChangeAttr},
+						},
+					}
+				}(),
+			},
+			wantErr: true,
+		},
 		{
 			changes: []schema.Change{
 				func() *schema.AddTable {
@@ -689,7 +875,7 @@ func TestPlanChanges(t *testing.T) {
 								C: &schema.Check{
 									Name:  "id_nonzero",
 									Expr:  "(id > 0)",
-									Attrs: []schema.Attr{&Enforced{}},
+									Attrs: []schema.Attr{&Enforced{V: true}},
 								},
 							},
 							&schema.ModifyAttr{
@@ -789,7 +975,7 @@ func TestPlanChanges(t *testing.T) {
 								C: &schema.Check{
 									Name:  "id_nonzero",
 									Expr:  "(id > 0)",
-									Attrs: []schema.Attr{&Enforced{}},
+									Attrs: []schema.Attr{&Enforced{V: true}},
 								},
 							},
 						},
@@ -864,6 +1050,34 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"))
+					return &schema.ModifyTable{
+						T: users,
+						Changes: []schema.Change{
+							&schema.AddCheck{
+								C: schema.NewCheck().SetName("check1").SetExpr("(id > 0)").AddAttrs(&Enforced{V: false}),
+							},
+							&schema.ModifyCheck{
+								From: schema.NewCheck().SetName("check2").SetExpr("(id > 0)"),
+								To:   schema.NewCheck().SetName("check2").SetExpr("(id >= 0)").AddAttrs(&Enforced{V: false}),
+							},
+						},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` ADD CONSTRAINT `check1` CHECK (id > 0) NOT ENFORCED, DROP CHECK `check2`, ADD CONSTRAINT `check2` CHECK (id >= 0) NOT ENFORCED",
+						Reverse: "ALTER TABLE `users` DROP CHECK `check2`, ADD CONSTRAINT `check2` CHECK (id > 0), DROP CONSTRAINT `check1`",
+					},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				&schema.AddTable{
@@ -1146,6 +1360,241 @@ func TestPlanChanges(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		// Sequences are not supported by MySQL.
+		{
+			changes: []schema.Change{
+				&schema.AddObject{O: &Sequence{Name: "s1", Schema: schema.New("test")}},
+			},
+			wantErr: true,
+		},
+		{
+			version: "10.3.0-MariaDB",
+			changes: []schema.Change{
+				&schema.AddObject{O: &Sequence{Name: "s1", Schema: schema.New("test"), Start: 100, Increment: 10, Cycle: true, Attrs: []schema.Attr{&schema.Comment{Text: "c"}}}},
+				&schema.ModifyObject{
+					From: &Sequence{Name: "s2", Schema: schema.New("test")},
+					To:   &Sequence{Name: "s2", Schema: schema.New("test"), Increment: 2, Cache: 1000, Attrs: []schema.Attr{&schema.Comment{Text: "c"}}},
+				},
+				&schema.DropObject{O: &Sequence{Name: "s3", Schema: schema.New("test"), Max: 100}},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE SEQUENCE `test`.`s1` INCREMENT BY 10 START WITH 100 CYCLE COMMENT \"c\"",
+						Reverse: "DROP SEQUENCE `test`.`s1`",
+					},
+					{
+						Cmd:     "ALTER SEQUENCE `test`.`s2` INCREMENT BY 2",
+						Reverse: "ALTER SEQUENCE `test`.`s2` INCREMENT BY 1",
+					},
+					{
+						Cmd:     "ALTER TABLE `test`.`s2` COMMENT \"c\"",
+						Reverse: "ALTER TABLE `test`.`s2` COMMENT \"\"",
+					},
+					{
+						Cmd:     "DROP SEQUENCE `test`.`s3`",
+						Reverse: "CREATE SEQUENCE `test`.`s3` MAXVALUE 100",
+					},
+				},
+			},
+		},
+		// National types are defined without a charset.
+		{
+			version: "8.0.31",
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("users").
+						SetCharset("utf8mb4").
+						AddColumns(
+							schema.NewColumn("a").SetType(&schema.StringType{T: TypeNChar, Size: 10}).SetCharset("utf8mb3"),
+							schema.NewColumn("b").SetType(&schema.StringType{T: TypeNVarchar, Size: 20}),
+						),
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "CREATE TABLE `users` (`a` nchar(10) NOT NULL, `b` nvarchar(20) NOT NULL) CHARSET utf8mb4",
+						Reverse: "DROP TABLE `users`",
+					},
+				},
+			},
+		},
+		{
+			version: "8.0.31",
+			changes: []schema.Change{
+				&schema.AddTable{
+					T: schema.NewTable("users").
+						AddColumns(schema.NewColumn("a").SetType(&schema.StringType{T: TypeNChar, Size: 10}).SetCharset("latin1")),
+				},
+			},
+			// National types support only the national charset.
+			wantErr: true,
+		},
+		// The ON UPDATE clause keeps the precision of the column.
+		{
+			version: "5.6.35",
+			changes: []schema.Change{
+				func() *schema.ModifyTable {
+					from := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"})
+					to := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"}).
+						AddAttrs(&OnUpdate{A: "CURRENT_TIMESTAMP(6)"})
+					return &schema.ModifyTable{
+						T:       schema.NewTable("users").AddColumns(to),
+						Changes: []schema.Change{&schema.ModifyColumn{From: from, To: to, Change: schema.ChangeAttr}},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)",
+						Reverse: "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+					},
+				},
+			},
+		},
+		{
+			version: "8.0.31",
+			changes: []schema.Change{
+				func() *schema.ModifyTable {
+					from := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"})
+					to := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"}).
+						AddAttrs(&OnUpdate{A: "CURRENT_TIMESTAMP(6)"})
+					return &schema.ModifyTable{
+						T:       schema.NewTable("users").AddColumns(to),
+						Changes: []schema.Change{&schema.ModifyColumn{From: from, To: to, Change: schema.ChangeAttr}},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)",
+						Reverse: "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+					},
+				},
+			},
+		},
+		{
+			version: "10.6.4-MariaDB",
+			changes: []schema.Change{
+				func() *schema.ModifyTable {
+					from := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"})
+					to := schema.NewTimeColumn("c", TypeTimestamp, schema.TimePrecision(6)).
+						SetDefault(&schema.RawExpr{X: "CURRENT_TIMESTAMP(6)"}).
+						AddAttrs(&OnUpdate{A: "CURRENT_TIMESTAMP(6)"})
+					return &schema.ModifyTable{
+						T:       schema.NewTable("users").AddColumns(to),
+						Changes: []schema.Change{&schema.ModifyColumn{From: from, To: to, Change: schema.ChangeAttr}},
+					}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6)",
+						Reverse: "ALTER TABLE `users` MODIFY COLUMN `c` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)",
+					},
+				},
+			},
+		},
+		// Changes of the same table are combined into one statement in MySQL 8.
+		{
+			version: "8.0.31",
+			changes: combineChanges(),
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` ADD COLUMN `b` int NOT NULL, DROP INDEX `idx`, ADD INDEX `idx` (`a`, `b`), ADD INDEX `idx_b` (`b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx_b`, DROP INDEX `idx`, ADD INDEX `idx` (`a`), DROP COLUMN `b`",
+					},
+				},
+			},
+		},
+		{
+			version: "5.7.38",
+			changes: combineChanges(),
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` DROP INDEX `idx`",
+						Reverse: "ALTER TABLE `users` ADD INDEX `idx` (`a`)",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD COLUMN `b` int NOT NULL, ADD INDEX `idx` (`a`, `b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx`, DROP COLUMN `b`",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD INDEX `idx_b` (`b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx_b`",
+					},
+				},
+			},
+		},
+		// MariaDB does not execute DDL atomically.
+		{
+			version: "10.11.2-MariaDB",
+			changes: combineChanges(),
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` DROP INDEX `idx`",
+						Reverse: "ALTER TABLE `users` ADD INDEX `idx` (`a`)",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD COLUMN `b` int NOT NULL, ADD INDEX `idx` (`a`, `b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx`, DROP COLUMN `b`",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD INDEX `idx_b` (`b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx_b`",
+					},
+				},
+			},
+		},
+		// Combining can be disabled for review clarity.
+		{
+			version: "8.0.31",
+			changes: combineChanges(),
+			options: []migrate.PlanOption{
+				func(o *migrate.PlanOptions) { o.NoCombine = true },
+			},
+			wantPlan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     "ALTER TABLE `users` DROP INDEX `idx`",
+						Reverse: "ALTER TABLE `users` ADD INDEX `idx` (`a`)",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD COLUMN `b` int NOT NULL",
+						Reverse: "ALTER TABLE `users` DROP COLUMN `b`",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD INDEX `idx` (`a`, `b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx`",
+					},
+					{
+						Cmd:     "ALTER TABLE `users` ADD INDEX `idx_b` (`b`)",
+						Reverse: "ALTER TABLE `users` DROP INDEX `idx_b`",
+					},
+				},
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
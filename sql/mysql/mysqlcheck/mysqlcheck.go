@@ -18,9 +18,11 @@ import (
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/design"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
 	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlcheck/narrowing"
 )
 
 var (
@@ -28,6 +30,10 @@ var (
 	codeImplicitUpdate = sqlcheck.Code("MY101")
 	// codeInlineRef is a MySQL specific code for reporting columns with inline references.
 	codeInlineRef = sqlcheck.Code("MY102")
+	// codeDropVisibleIndex is a MySQL specific code for reporting visible indexes being dropped.
+	codeDropVisibleIndex = sqlcheck.Code("MY103")
+	// codeTableRebuild is a MySQL specific code for reporting changes that rebuild (copy) tables.
+	codeTableRebuild = sqlcheck.Code("MY104")
 )
 
 func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err error) {
@@ -204,6 +210,90 @@ func inlineRefs(_ context.Context, p *sqlcheck.Pass) error {
 	return nil
 }
 
+// dropVisibleIndex is an analyzer function that detects indexes dropped while they are still
+// visible to the optimizer, and suggests making them invisible before dropping them. This is
+// the standard safe-drop procedure, as an invisible index can be made visible again instantly
+// in case the queries that rely on it are slowed down, while re-creating a dropped index may
+// take a long time on large tables.
+func dropVisibleIndex(_ context.Context, p *sqlcheck.Pass) error {
+	drv, ok := p.Dev.Driver.(*mysql.Driver)
+	if !ok || !drv.SupportsInvisibleIndex() {
+		return nil
+	}
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			if !ok {
+				continue
+			}
+			for _, mc := range m.Changes {
+				d, ok := mc.(*schema.DropIndex)
+				if !ok || sqlx.Has(d.I.Attrs, &mysql.IndexInvisible{}) {
+					continue
+				}
+				diag := sqlcheck.Diagnostic{
+					Pos:  sc.Stmt.Pos,
+					Code: codeDropVisibleIndex,
+					Text: fmt.Sprintf("Dropping visible index %q on table %q", d.I.Name, m.T.Name),
+				}
+				invisible := "INVISIBLE"
+				if drv.Maria() {
+					invisible = "IGNORED"
+				}
+				diag.SuggestFix(fmt.Sprintf(
+					"Make the index invisible first (ALTER TABLE `%s` ALTER INDEX `%s` %s), and drop it in a later migration once it is confirmed to be unused",
+					m.T.Name, d.I.Name, invisible,
+				), nil)
+				diags = append(diags, diag)
+			}
+		}
+	}
+	if len(diags) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "visible indexes dropped", Diagnostics: diags})
+	}
+	return nil
+}
+
+// tableRebuild is an analyzer function that detects changes to the storage engine or to the
+// tablespace of existing tables. Such changes rebuild the table by copying all of its rows,
+// which may take a long time on large tables and block concurrent writes until it is done.
+func tableRebuild(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			if !ok {
+				continue
+			}
+			for _, mc := range m.Changes {
+				a, ok := mc.(*schema.ModifyAttr)
+				if !ok {
+					continue
+				}
+				switch to := a.To.(type) {
+				case *mysql.Engine:
+					diags = append(diags, sqlcheck.Diagnostic{
+						Pos:  sc.Stmt.Pos,
+						Code: codeTableRebuild,
+						Text: fmt.Sprintf("Changing the storage engine of table %q to %s rebuilds the table", m.T.Name, to.V),
+					})
+				case *mysql.Tablespace:
+					diags = append(diags, sqlcheck.Diagnostic{
+						Pos:  sc.Stmt.Pos,
+						Code: codeTableRebuild,
+						Text: fmt.Sprintf("Moving table %q to tablespace %q rebuilds the table", m.T.Name, to.V),
+					})
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "table rebuild detected", Diagnostics: diags})
+	}
+	return nil
+}
+
 func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	ds, err := destructive.New(r)
 	if err != nil {
@@ -223,9 +313,17 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
+	nr, err := narrowing.New(r)
+	if err != nil {
+		return nil, err
+	}
 	nm, err := naming.New(r)
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc, nm, sqlcheck.AnalyzerFunc(inlineRefs)}, nil
+	sd, err := design.New(r)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Analyzer{ds, dd, cd, bc, nr, nm, sd, sqlcheck.AnalyzerFunc(inlineRefs), sqlcheck.AnalyzerFunc(dropVisibleIndex), sqlcheck.AnalyzerFunc(tableRebuild)}, nil
 }
@@ -230,6 +230,116 @@ func TestDataDepend_Maria_ImplicitUpdate(t *testing.T) {
 
 }
 
+func TestDropVisibleIndex(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").
+			SetSchema(schema.New("test")).
+			AddColumns(schema.NewIntColumn("a", mysql.TypeInt))
+		pass = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Name:   "mysql",
+				Driver: devDriver(t, "8.0.19"),
+			},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Text: "ALTER TABLE users DROP INDEX a, DROP INDEX b",
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: []schema.Change{
+									&schema.DropIndex{I: schema.NewIndex("a").AddColumns(users.Columns[0])},
+									&schema.DropIndex{I: schema.NewIndex("b").AddColumns(users.Columns[0]).AddAttrs(&mysql.IndexInvisible{})},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(mysql.DriverName, nil)
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.NotNil(t, report)
+	require.Equal(t, "visible indexes dropped", report.Text)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "MY103", report.Diagnostics[0].Code)
+	require.Equal(t, `Dropping visible index "a" on table "users"`, report.Diagnostics[0].Text)
+	require.Equal(t, "Make the index invisible first (ALTER TABLE `users` ALTER INDEX `a` INVISIBLE), and drop it in a later migration once it is confirmed to be unused", report.Diagnostics[0].SuggestedFixes[0].Message)
+
+	// Invisible indexes are not supported by MySQL 5.7.
+	report, pass.Dev.Driver = nil, devDriver(t, "5.7.0")
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.Nil(t, report)
+
+	// MariaDB uses ignored indexes instead.
+	report, pass.Dev.Driver = nil, devDriver(t, "10.6.4-MariaDB")
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.NotNil(t, report)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, "Make the index invisible first (ALTER TABLE `users` ALTER INDEX `a` IGNORED), and drop it in a later migration once it is confirmed to be unused", report.Diagnostics[0].SuggestedFixes[0].Message)
+
+	// Ignored indexes are not supported before MariaDB 10.6.
+	report, pass.Dev.Driver = nil, devDriver(t, "10.5.8-MariaDB")
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.Nil(t, report)
+}
+
+func TestTableRebuild(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").
+			SetSchema(schema.New("test")).
+			AddColumns(schema.NewIntColumn("a", mysql.TypeInt))
+		pass = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{
+				Name:   "mysql",
+				Driver: devDriver(t, "8.0.19"),
+			},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Text: "ALTER TABLE users ENGINE MyISAM, TABLESPACE `archive`, COMMENT 'users'",
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: []schema.Change{
+									&schema.ModifyAttr{From: &mysql.Engine{V: mysql.EngineInnoDB, Default: true}, To: &mysql.Engine{V: mysql.EngineMyISAM}},
+									&schema.ModifyAttr{From: &mysql.Tablespace{}, To: &mysql.Tablespace{V: "archive"}},
+									&schema.ModifyAttr{From: &schema.Comment{}, To: &schema.Comment{Text: "users"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(mysql.DriverName, nil)
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.NotNil(t, report)
+	require.Equal(t, "table rebuild detected", report.Text)
+	require.Len(t, report.Diagnostics, 2)
+	require.Equal(t, "MY104", report.Diagnostics[0].Code)
+	require.Equal(t, `Changing the storage engine of table "users" to MyISAM rebuilds the table`, report.Diagnostics[0].Text)
+	require.Equal(t, `Moving table "users" to tablespace "archive" rebuilds the table`, report.Diagnostics[1].Text)
+}
+
 type testFile struct {
 	name string
 	migrate.File
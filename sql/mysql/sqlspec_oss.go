@@ -22,9 +22,37 @@ import (
 	"github.com/zclconf/go-cty/cty"
 )
 
-// Codec for schemahcl.
-type Codec struct {
-	State *schemahcl.State
+type (
+	// Codec for schemahcl.
+	Codec struct {
+		State *schemahcl.State
+		// Reports if the codec is used for the MariaDB
+		// flavor, which supports schema sequences.
+		maria bool
+	}
+
+	// doc represents the MySQL HCL spec document.
+	doc struct {
+		Tables       []*sqlspec.Table    `spec:"table"`
+		Views        []*sqlspec.View     `spec:"view"`
+		Materialized []*sqlspec.View     `spec:"materialized"`
+		Sequences    []*sqlspec.Sequence `spec:"sequence"`
+		Funcs        []*sqlspec.Func     `spec:"function"`
+		Procs        []*sqlspec.Func     `spec:"procedure"`
+		Triggers     []*sqlspec.Trigger  `spec:"trigger"`
+		Schemas      []*sqlspec.Schema   `spec:"schema"`
+	}
+)
+
+func (d *doc) ScanDoc() *specutil.ScanDoc {
+	return &specutil.ScanDoc{
+		Schemas:  d.Schemas,
+		Tables:   d.Tables,
+		Views:    d.Views,
+		Funcs:    d.Funcs,
+		Procs:    d.Procs,
+		Triggers: d.Triggers,
+	}
 }
 
 // Eval evaluates an Atlas DDL document into v using the input.
@@ -36,16 +64,16 @@ func (c *Codec) Eval(p *hclparse.Parser, v any, input map[string]cty.Value) erro
 func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptions) error {
 	switch v := v.(type) {
 	case *schema.Realm:
-		var d specutil.Doc
+		var d doc
 		if err := c.State.EvalOptions(p, &d, opts); err != nil {
 			return err
 		}
-		if err := specutil.Scan(v,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs, Triggers: d.Triggers},
-			scanFuncs,
-		); err != nil {
+		if err := specutil.Scan(v, d.ScanDoc(), scanFuncs); err != nil {
 			return fmt.Errorf("mysql: failed converting to *schema.Realm: %w", err)
 		}
+		if err := c.convertSequences(d.Sequences, v); err != nil {
+			return err
+		}
 		for _, spec := range d.Schemas {
 			s, ok := v.Schema(spec.Name)
 			if !ok {
@@ -56,7 +84,7 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 			}
 		}
 	case *schema.Schema:
-		var d specutil.Doc
+		var d doc
 		if err := c.State.EvalOptions(p, &d, opts); err != nil {
 			return err
 		}
@@ -64,10 +92,10 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 			return fmt.Errorf("mysql: expecting document to contain a single schema, got %d", len(d.Schemas))
 		}
 		r := &schema.Realm{}
-		if err := specutil.Scan(r,
-			&specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables, Views: d.Views, Funcs: d.Funcs, Procs: d.Procs, Triggers: d.Triggers},
-			scanFuncs,
-		); err != nil {
+		if err := specutil.Scan(r, d.ScanDoc(), scanFuncs); err != nil {
+			return err
+		}
+		if err := c.convertSequences(d.Sequences, r); err != nil {
 			return err
 		}
 		if err := convertCharset(d.Schemas[0], &r.Schemas[0].Attrs); err != nil {
@@ -84,12 +112,126 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 
 // MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
 func (c *Codec) MarshalSpec(v any) ([]byte, error) {
-	return specutil.Marshal(v, c.State, specutil.RealmFuncs{
+	// Sequences are not part of the common spec document,
+	// and therefore, are appended to the MySQL document.
+	m := schemahcl.MarshalerFunc(func(s any) ([]byte, error) {
+		d1, ok := s.(*specutil.Doc)
+		if !ok {
+			return nil, fmt.Errorf("mysql: unexpected spec document %T", s)
+		}
+		d := &doc{
+			Tables:       d1.Tables,
+			Views:        d1.Views,
+			Materialized: d1.Materialized,
+			Funcs:        d1.Funcs,
+			Procs:        d1.Procs,
+			Triggers:     d1.Triggers,
+			Schemas:      d1.Schemas,
+		}
+		if err := sequencesSpec(d, v); err != nil {
+			return nil, err
+		}
+		return c.State.MarshalSpec(d)
+	})
+	return specutil.Marshal(v, m, specutil.RealmFuncs{
 		Schema:   schemaSpec,
 		Triggers: triggersSpec,
 	})
 }
 
+// convertSequences converts the sequence specs to schema sequences.
+func (c *Codec) convertSequences(specs []*sqlspec.Sequence, r *schema.Realm) error {
+	if len(specs) > 0 && !c.maria {
+		return errors.New("mysql: sequences are supported only by MariaDB")
+	}
+	for _, spec := range specs {
+		n, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return err
+		}
+		s, ok := r.Schema(n)
+		if !ok {
+			return fmt.Errorf("mysql: schema %q for sequence %q was not found", n, spec.Name)
+		}
+		seq := &Sequence{Name: spec.Name, Schema: s}
+		for _, a := range []struct {
+			name string
+			v    *int64
+		}{
+			{"start", &seq.Start},
+			{"increment", &seq.Increment},
+			{"min_value", &seq.Min},
+			{"max_value", &seq.Max},
+			{"cache", &seq.Cache},
+		} {
+			if attr, ok := spec.Attr(a.name); ok {
+				if *a.v, err = attr.Int64(); err != nil {
+					return fmt.Errorf("mysql: sequence %q attribute %q: %w", spec.Name, a.name, err)
+				}
+			}
+		}
+		if attr, ok := spec.Attr("cycle"); ok {
+			if seq.Cycle, err = attr.Bool(); err != nil {
+				return fmt.Errorf("mysql: sequence %q attribute \"cycle\": %w", spec.Name, err)
+			}
+		}
+		if attr, ok := spec.Attr("comment"); ok {
+			v, err := attr.String()
+			if err != nil {
+				return fmt.Errorf("mysql: sequence %q attribute \"comment\": %w", spec.Name, err)
+			}
+			seq.Attrs = append(seq.Attrs, &schema.Comment{Text: v})
+		}
+		s.AddObjects(seq)
+	}
+	return nil
+}
+
+// sequencesSpec appends the sequence specs of the schema or realm to the document.
+func sequencesSpec(d *doc, v any) error {
+	var schemas []*schema.Schema
+	switch v := v.(type) {
+	case *schema.Schema:
+		schemas = []*schema.Schema{v}
+	case *schema.Realm:
+		schemas = v.Schemas
+	}
+	for _, s := range schemas {
+		for _, o := range s.Objects {
+			seq, ok := o.(*Sequence)
+			if !ok {
+				continue
+			}
+			spec := &sqlspec.Sequence{Name: seq.Name, Schema: specutil.SchemaRef(s.Name)}
+			for _, a := range []struct {
+				name string
+				v    int64
+			}{
+				{"start", seq.Start},
+				{"increment", seq.Increment},
+				{"min_value", seq.Min},
+				{"max_value", seq.Max},
+				{"cache", seq.Cache},
+			} {
+				if a.v != 0 {
+					spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.Int64Attr(a.name, a.v))
+				}
+			}
+			if seq.Cycle {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("cycle", true))
+			}
+			if c := (schema.Comment{}); sqlx.Has(seq.Attrs, &c) && c.Text != "" {
+				spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c.Text))
+			}
+			d.Sequences = append(d.Sequences, spec)
+		}
+	}
+	if _, ok := v.(*schema.Realm); ok {
+		return specutil.QualifyObjects(d.Sequences)
+	}
+	return nil
+}
+
 func triggersSpec([]*schema.Trigger, *specutil.Doc) ([]*sqlspec.Trigger, error) {
 	return nil, nil // unimplemented.
 }
@@ -107,6 +249,7 @@ var (
 		schemahcl.WithScopedEnums("table.column.as.type", stored, persistent, virtual),
 		schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
 		schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+		schemahcl.WithScopedEnums("table.column.diff.ignore", specutil.DiffIgnoreVars...),
 	}
 	codec = &Codec{
 		State: schemahcl.New(
@@ -117,6 +260,7 @@ var (
 		),
 	}
 	mariaCodec = &Codec{
+		maria: true,
 		State: schemahcl.New(
 			append(
 				mariaSpecOptions,
@@ -172,6 +316,13 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 		}
 		t.AddAttrs(&Engine{V: v})
 	}
+	if attr, ok := spec.Attr("tablespace"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return nil, err
+		}
+		t.AddAttrs(&Tablespace{V: v})
+	}
 	return t, nil
 }
 
@@ -196,6 +347,15 @@ func convertIndex(spec *sqlspec.Index, parent *schema.Table) (*schema.Index, err
 	if err := convertIndexParser(spec, idx); err != nil {
 		return nil, err
 	}
+	if attr, ok := spec.Attr("invisible"); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return nil, err
+		}
+		if b {
+			idx.AddAttrs(&IndexInvisible{})
+		}
+	}
 	return idx, nil
 }
 
@@ -332,6 +492,9 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 		}
 		ts.Extra.Attrs = append(ts.Extra.Attrs, attr)
 	}
+	if s := (&Tablespace{}); sqlx.Has(t.Attrs, s) && s.V != "" {
+		ts.Extra.Attrs = append(ts.Extra.Attrs, schemahcl.StringAttr("tablespace", s.V))
+	}
 	return ts, nil
 }
 
@@ -354,6 +517,9 @@ func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 		return nil, err
 	}
 	spec.Extra.Attrs = indexTypeSpec(idx, spec.Extra.Attrs)
+	if sqlx.Has(idx.Attrs, &IndexInvisible{}) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("invisible", true))
+	}
 	return spec, nil
 }
 
@@ -425,7 +591,7 @@ func storedOrVirtual(s string) string {
 func checkSpec(s *schema.Check) *sqlspec.Check {
 	c := specutil.FromCheck(s)
 	if e := (Enforced{}); sqlx.Has(s.Attrs, &e) {
-		c.Extra.Attrs = append(c.Extra.Attrs, schemahcl.BoolAttr("enforced", true))
+		c.Extra.Attrs = append(c.Extra.Attrs, schemahcl.BoolAttr("enforced", e.V))
 	}
 	return c
 }
@@ -532,6 +698,8 @@ var TypeRegistry = schemahcl.NewRegistry(
 		schemahcl.NewTypeSpec(TypeYear, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
 		schemahcl.NewTypeSpec(TypeVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
 		schemahcl.NewTypeSpec(TypeChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeNVarchar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
+		schemahcl.NewTypeSpec(TypeNChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
 		schemahcl.NewTypeSpec(TypeVarBinary, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
 		schemahcl.NewTypeSpec(TypeBinary, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
 		schemahcl.NewTypeSpec(TypeBlob, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
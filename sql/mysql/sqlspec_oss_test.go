@@ -528,6 +528,30 @@ schema "test" {
 	require.EqualValues(t, expected, string(buf))
 }
 
+func TestMarshalSpec_Tablespace(t *testing.T) {
+	var (
+		s = &schema.Schema{}
+		f = `table "logs" {
+  schema     = schema.test
+  tablespace = "archive"
+  column "id" {
+    null = false
+    type = bigint
+  }
+}
+schema "test" {
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), s, nil))
+	tt, ok := s.Table("logs")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Tablespace{V: "archive"}}, tt.Attrs)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+}
+
 func TestMarshalSpec_Check(t *testing.T) {
 	s := schema.New("test").
 		AddTables(
@@ -538,7 +562,8 @@ func TestMarshalSpec_Check(t *testing.T) {
 				).
 				AddChecks(
 					schema.NewCheck().SetName("price1 positive").SetExpr("price1 > 0"),
-					schema.NewCheck().SetExpr("price1 <> price2").AddAttrs(&Enforced{}),
+					schema.NewCheck().SetExpr("price1 <> price2").AddAttrs(&Enforced{V: true}),
+					schema.NewCheck().SetName("price2 positive").SetExpr("price2 > 0").AddAttrs(&Enforced{V: false}),
 				),
 		)
 	buf, err := MarshalHCL(s)
@@ -560,11 +585,21 @@ func TestMarshalSpec_Check(t *testing.T) {
     expr     = "price1 <> price2"
     enforced = true
   }
+  check "price2 positive" {
+    expr     = "price2 > 0"
+    enforced = false
+  }
 }
 schema "test" {
 }
 `
 	require.EqualValues(t, expected, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	checks := got.Tables[0].Attrs
+	require.Len(t, checks, 3)
+	require.Equal(t, &schema.Check{Name: "price2 positive", Expr: "price2 > 0", Attrs: []schema.Attr{&Enforced{V: false}}}, checks[2])
 }
 
 func TestMarshalSpec_TableEngine(t *testing.T) {
@@ -809,6 +844,41 @@ schema "test" {
 	require.EqualValues(t, exp, &s)
 }
 
+func TestMarshalSpec_IndexInvisible(t *testing.T) {
+	c := schema.NewIntColumn("id", "int")
+	s := schema.New("test").
+		AddTables(
+			schema.NewTable("users").
+				AddColumns(c).
+				AddIndexes(
+					schema.NewIndex("idx").
+						AddColumns(c).
+						AddAttrs(&IndexInvisible{}),
+				),
+		)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "users" {
+  schema = schema.test
+  column "id" {
+    null = false
+    type = int
+  }
+  index "idx" {
+    columns   = [column.id]
+    invisible = true
+  }
+}
+schema "test" {
+}
+`, string(buf))
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	idx, ok := got.Tables[0].Index("idx")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&IndexInvisible{}}, idx.Attrs)
+}
+
 func TestMarshalSpec_PrimaryKeyType(t *testing.T) {
 	s := schema.New("test").
 		AddTables(
@@ -1185,6 +1255,22 @@ func TestTypes(t *testing.T) {
 			typeExpr: `sql("custom")`,
 			expected: &schema.UnsupportedType{T: "custom"},
 		},
+		{
+			typeExpr: "nchar(10)",
+			expected: &schema.StringType{T: TypeNChar, Size: 10},
+		},
+		{
+			typeExpr: "nvarchar(10)",
+			expected: &schema.StringType{T: TypeNVarchar, Size: 10},
+		},
+		{
+			typeExpr: `sql("national character varying(20)")`,
+			expected: &schema.StringType{T: TypeNVarchar, Size: 20},
+		},
+		{
+			typeExpr: `sql("national char")`,
+			expected: &schema.StringType{T: TypeNChar},
+		},
 		{
 			typeExpr: "binary(255)",
 			expected: &schema.BinaryType{T: TypeBinary, Size: p(255)},
@@ -1796,3 +1882,43 @@ schema "nati.schema" {
 }
 `, string(got))
 }
+
+func TestMarshalSpec_Sequence(t *testing.T) {
+	s := schema.New("test").AddObjects(
+		&Sequence{Name: "s1"},
+		&Sequence{Name: "s2", Start: 100, Increment: 10, Max: 1000, Cycle: true, Attrs: []schema.Attr{&schema.Comment{Text: "c"}}},
+	)
+	got, err := mariaCodec.MarshalSpec(s)
+	require.NoError(t, err)
+	require.Equal(t, `sequence "s1" {
+  schema = schema.test
+}
+sequence "s2" {
+  schema    = schema.test
+  start     = 100
+  increment = 10
+  max_value = 1000
+  cycle     = true
+  comment   = "c"
+}
+schema "test" {
+}
+`, string(got))
+
+	var r schema.Realm
+	require.NoError(t, EvalMariaHCLBytes(got, &r, nil))
+	require.Len(t, r.Schemas, 1)
+	require.Len(t, r.Schemas[0].Objects, 2)
+	seq := r.Schemas[0].Objects[1].(*Sequence)
+	require.Equal(t, "s2", seq.Name)
+	require.Equal(t, r.Schemas[0], seq.Schema)
+	require.Equal(t, int64(100), seq.Start)
+	require.Equal(t, int64(10), seq.Increment)
+	require.Equal(t, int64(1000), seq.Max)
+	require.True(t, seq.Cycle)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "c"}}, seq.Attrs)
+
+	// Sequences are not supported by MySQL.
+	err = EvalHCLBytes(got, &r, nil)
+	require.EqualError(t, err, "mysql: sequences are supported only by MariaDB")
+}
@@ -123,7 +123,8 @@ func (i *tinspect) InspectSchema(ctx context.Context, name string, opts *schema.
 	if err != nil {
 		return nil, err
 	}
-	return i.patchSchema(ctx, s)
+	// Checksums are computed again, as the tables were patched.
+	return sqlx.SchemaChecksums(i.patchSchema(ctx, s))
 }
 
 func (i *tinspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
@@ -136,6 +137,8 @@ func (i *tinspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOp
 			return nil, err
 		}
 	}
+	// Checksums are computed again, as the tables were patched.
+	schema.SetChecksums(r.Schemas...)
 	return r, nil
 }
 
@@ -0,0 +1,186 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// FormatType converts schema type to its column form in the database.
+// An error is returned if the type cannot be recognized.
+func FormatType(t schema.Type) (string, error) {
+	var f string
+	switch t := t.(type) {
+	case *schema.BoolType:
+		f = TypeBoolean
+	case *schema.BinaryType:
+		switch f = strings.ToLower(t.T); {
+		case f == TypeRaw && t.Size != nil:
+			f = fmt.Sprintf("%s(%d)", f, *t.Size)
+		case f == TypeRaw:
+			return "", fmt.Errorf("oracle: missing size for type %q", t.T)
+		}
+	case *schema.DecimalType:
+		switch {
+		case t.Precision == 0 && t.Scale == 0:
+			f = TypeNumber
+		case t.Precision == 0:
+			f = fmt.Sprintf("%s(*,%d)", TypeNumber, t.Scale)
+		case t.Scale == 0:
+			f = fmt.Sprintf("%s(%d)", TypeNumber, t.Precision)
+		default:
+			f = fmt.Sprintf("%s(%d,%d)", TypeNumber, t.Precision, t.Scale)
+		}
+	case *schema.FloatType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeBinaryFloat, TypeBinaryDouble:
+		default:
+			f = TypeFloat
+			if t.Precision > 0 && t.Precision != defaultFloatPrec {
+				f = fmt.Sprintf("%s(%d)", f, t.Precision)
+			}
+		}
+	case *schema.IntegerType:
+		// All integer types are aliases to NUMBER(*,0).
+		f = TypeInteger
+	case *schema.JSONType:
+		f = TypeJSON
+	case *schema.StringType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeVarchar2, TypeVarchar, TypeNVarchar2, TypeChar, TypeNChar:
+			if f == TypeVarchar {
+				f = TypeVarchar2
+			}
+			if t.Size == 0 {
+				if f == TypeVarchar2 || f == TypeNVarchar2 {
+					return "", fmt.Errorf("oracle: missing size for type %q", t.T)
+				}
+				break
+			}
+			semantics := ""
+			if (f == TypeVarchar2 || f == TypeChar) && sqlx.Has(t.Attrs, &CharSemantics{}) {
+				semantics = " char"
+			}
+			f = fmt.Sprintf("%s(%d%s)", f, t.Size, semantics)
+		case TypeCLOB, TypeNCLOB, TypeLong:
+		default:
+			return "", fmt.Errorf("oracle: unexpected string type: %q", t.T)
+		}
+	case *schema.TimeType:
+		switch f = strings.ToLower(t.T); f {
+		case TypeDate:
+		case TypeTimestamp, TypeTimestampTZ, TypeTimestampLTZ:
+			if p := t.Precision; p != nil && *p != defaultTimePrec {
+				f = TypeTimestamp + fmt.Sprintf("(%d)", *p) + strings.TrimPrefix(f, TypeTimestamp)
+			}
+		default:
+			return "", fmt.Errorf("oracle: unexpected time type: %q", t.T)
+		}
+	case *UserDefinedType:
+		f = strings.ToLower(t.T)
+	case *schema.UnsupportedType:
+		return "", fmt.Errorf("oracle: unsupported type: %q", t.T)
+	default:
+		return "", fmt.Errorf("oracle: invalid schema type: %T", t)
+	}
+	return f, nil
+}
+
+// reType matches the name of the type, its size or precision, its
+// scale, its length semantics and the optional suffix of the type.
+// For example: "number(10,2)", "varchar2(10 char)", "float(63)",
+// "timestamp(3) with time zone" or "timestamp with time zone(3)".
+var reType = regexp.MustCompile(`^([a-z][a-z0-9_ ]*?)\s*(?:\(\s*(\*|\d+)\s*(?:,\s*(-?\d+)\s*)?(byte|char)?\s*\)\s*([a-z ]*))?$`)
+
+// ParseType returns the schema.Type value represented by the given raw type.
+// Types that are not recognized are returned as UserDefinedType.
+func ParseType(raw string) (schema.Type, error) {
+	c := strings.Join(strings.Fields(strings.ToLower(raw)), " ")
+	m := reType.FindStringSubmatch(c)
+	if m == nil {
+		return &UserDefinedType{T: c}, nil
+	}
+	var (
+		err         error
+		size, scale int
+		name        = strings.TrimSpace(strings.TrimSpace(m[1]) + " " + m[5])
+		hasSize     = m[2] != "" && m[2] != "*"
+	)
+	if hasSize {
+		if size, err = strconv.Atoi(m[2]); err != nil {
+			return nil, fmt.Errorf("oracle: parse size %q: %w", m[2], err)
+		}
+	}
+	if m[3] != "" {
+		if scale, err = strconv.Atoi(m[3]); err != nil {
+			return nil, fmt.Errorf("oracle: parse scale %q: %w", m[3], err)
+		}
+	}
+	switch name {
+	case TypeInteger, TypeInt, TypeSmall:
+		return &schema.IntegerType{T: name}, nil
+	case TypeNumber, TypeDecimal, TypeNumeric:
+		// NUMBER(*,0) is an alias to INTEGER.
+		if m[2] == "*" && m[3] != "" && scale == 0 {
+			return &schema.IntegerType{T: TypeInteger}, nil
+		}
+		return &schema.DecimalType{T: TypeNumber, Precision: size, Scale: scale}, nil
+	case TypeFloat:
+		if size == defaultFloatPrec {
+			size = 0
+		}
+		return &schema.FloatType{T: TypeFloat, Precision: size}, nil
+	case TypeReal:
+		return &schema.FloatType{T: TypeFloat, Precision: 63}, nil
+	case TypeDouble:
+		return &schema.FloatType{T: TypeFloat}, nil
+	case TypeBinaryFloat, TypeBinaryDouble:
+		return &schema.FloatType{T: name}, nil
+	case TypeVarchar2, TypeVarchar, TypeNVarchar2, TypeChar, TypeNChar:
+		if name == TypeVarchar {
+			name = TypeVarchar2
+		}
+		t := &schema.StringType{T: name, Size: size}
+		// CHAR and NCHAR columns default to a length of 1.
+		if !hasSize && (name == TypeChar || name == TypeNChar) {
+			t.Size = 1
+		}
+		if m[4] == "char" && (name == TypeVarchar2 || name == TypeChar) {
+			t.Attrs = append(t.Attrs, &CharSemantics{})
+		}
+		return t, nil
+	case TypeCLOB, TypeNCLOB, TypeLong:
+		return &schema.StringType{T: name}, nil
+	case TypeRaw:
+		t := &schema.BinaryType{T: name}
+		if hasSize {
+			t.Size = &size
+		}
+		return t, nil
+	case TypeBLOB, TypeLongRaw:
+		return &schema.BinaryType{T: name}, nil
+	case TypeDate:
+		return &schema.TimeType{T: name}, nil
+	case TypeTimestamp, TypeTimestampTZ, TypeTimestampLTZ:
+		t := &schema.TimeType{T: name}
+		// Precision is omitted if it is the default.
+		if hasSize && size != defaultTimePrec {
+			t.Precision = &size
+		}
+		return t, nil
+	case TypeJSON:
+		return &schema.JSONType{T: name}, nil
+	case TypeBoolean, "bool":
+		return &schema.BoolType{T: TypeBoolean}, nil
+	default:
+		return &UserDefinedType{T: c}, nil
+	}
+}
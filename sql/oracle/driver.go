@@ -0,0 +1,367 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/types"
+)
+
+type (
+	// Driver represents an Oracle driver for introspecting database schemas,
+	// generating diff between schema elements and apply migrations changes.
+	Driver struct {
+		*conn
+		schema.Differ
+		schema.Inspector
+		migrate.PlanApplier
+	}
+
+	// database connection and its information.
+	conn struct {
+		schema.ExecQuerier
+		// The schema in the `schema` parameter (if given).
+		schema string
+		// The current schema of the session, that
+		// defaults to the name of the connected user.
+		current string
+		// System variables that are set on `Open`.
+		version string
+	}
+)
+
+var _ interface {
+	migrate.Snapshoter
+	migrate.StmtScanner
+	migrate.CleanChecker
+	schema.TypeParseFormatter
+} = (*Driver)(nil)
+
+// DriverName holds the name used for registration.
+// Note, this package does not register a database/sql driver, and
+// users are expected to import one that is registered under this
+// name. For example, github.com/sijms/go-ora/v2.
+const DriverName = "oracle"
+
+func init() {
+	sqlclient.Register(
+		DriverName,
+		sqlclient.OpenerFunc(opener),
+		sqlclient.RegisterDriverOpener(Open),
+		sqlclient.RegisterCodec(codec, codec),
+		sqlclient.RegisterURLParser(parser{}),
+	)
+	types.Register(DriverName, (*Driver)(nil))
+}
+
+func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
+	ur := parser{}.ParseURL(u)
+	db, err := sql.Open(DriverName, ur.DSN)
+	if err != nil {
+		return nil, err
+	}
+	drv, err := Open(db)
+	if err != nil {
+		if cerr := db.Close(); cerr != nil {
+			err = fmt.Errorf("%w: %v", err, cerr)
+		}
+		return nil, err
+	}
+	drv.(*Driver).schema = ur.Schema
+	return &sqlclient.Client{
+		Name:   DriverName,
+		DB:     db,
+		URL:    ur,
+		Driver: drv,
+	}, nil
+}
+
+// Open opens a new Oracle driver.
+func Open(db schema.ExecQuerier) (migrate.Driver, error) {
+	c := &conn{ExecQuerier: db}
+	rows, err := db.QueryContext(context.Background(), paramsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("oracle: query system variables: %w", err)
+	}
+	var current, version sql.NullString
+	if err := sqlx.ScanOne(rows, &current, &version); err != nil {
+		return nil, fmt.Errorf("oracle: scan system variables: %w", err)
+	}
+	c.current, c.version = current.String, version.String
+	if major := c.major(); major < 12 {
+		return nil, fmt.Errorf("oracle: unsupported oracle version: %q", c.version)
+	}
+	return &Driver{
+		conn:        c,
+		Differ:      &sqlx.Diff{DiffDriver: &diff{c}},
+		Inspector:   &inspect{c},
+		PlanApplier: &planApply{c},
+	}, nil
+}
+
+// Snapshot implements migrate.Snapshoter.
+func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
+	// In Oracle, a schema is owned by the user with the same name, and creating
+	// or dropping it requires administrative privileges. Hence, the driver is
+	// always bound to a schema, and it defaults to the session's current schema.
+	s, err := d.InspectSchema(ctx, d.bound(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Tables) > 0 {
+		return nil, &migrate.NotCleanError{
+			State:  schema.NewRealm(s),
+			Reason: fmt.Sprintf("found table %q in connected schema", s.Tables[0].Name),
+		}
+	}
+	return d.SchemaRestoreFunc(s), nil
+}
+
+// SchemaRestoreFunc returns a function that restores the given schema to its desired state.
+func (d *Driver) SchemaRestoreFunc(desired *schema.Schema) migrate.RestoreFunc {
+	return func(ctx context.Context) error {
+		current, err := d.InspectSchema(ctx, desired.Name, nil)
+		if err != nil {
+			return err
+		}
+		changes, err := d.SchemaDiff(current, desired)
+		if err != nil {
+			return err
+		}
+		return d.ApplyChanges(ctx, changes)
+	}
+}
+
+// CheckClean implements migrate.CleanChecker.
+func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error {
+	if revT == nil { // accept nil values
+		revT = &migrate.TableIdent{}
+	}
+	switch s, err := d.InspectSchema(ctx, d.bound(), nil); {
+	case err != nil:
+		return err
+	case len(s.Tables) == 0, (revT.Schema == "" || s.Name == revT.Schema) && len(s.Tables) == 1 && s.Tables[0].Name == revT.Name:
+		return nil
+	default:
+		return &migrate.NotCleanError{State: schema.NewRealm(s), Reason: fmt.Sprintf("found table %q in schema %q", s.Tables[0].Name, s.Name)}
+	}
+}
+
+// Lock implements the schema.Locker interface. Note, the connected
+// user requires the EXECUTE privilege on the DBMS_LOCK package.
+func (d *Driver) Lock(ctx context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error) {
+	conn, err := sqlx.SingleConn(ctx, d.ExecQuerier)
+	if err != nil {
+		return nil, err
+	}
+	h := fnv.New32()
+	h.Write([]byte(name))
+	// User-defined lock identifiers are in the range of 0 to 1073741823.
+	id := int64(h.Sum32() % (1 << 30))
+	if err := acquire(ctx, conn, id, timeout); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() error {
+		defer conn.Close()
+		rows, err := conn.QueryContext(ctx, "SELECT DBMS_LOCK.RELEASE(:1) FROM DUAL", id)
+		if err != nil {
+			return err
+		}
+		var status sql.NullInt64
+		switch err := sqlx.ScanOne(rows, &status); {
+		case err != nil:
+			return err
+		case status.Int64 != 0:
+			return fmt.Errorf("oracle: failed releasing lock %d: status %d", id, status.Int64)
+		}
+		return nil
+	}, nil
+}
+
+// FormatType converts schema type to its column form in the database.
+func (*Driver) FormatType(t schema.Type) (string, error) {
+	return FormatType(t)
+}
+
+// ParseType returns the schema.Type value represented by the given string.
+func (*Driver) ParseType(s string) (schema.Type, error) {
+	return ParseType(s)
+}
+
+// StmtBuilder is a helper method used to build statements with Oracle formatting.
+func (*Driver) StmtBuilder(opts migrate.PlanOptions) *sqlx.Builder {
+	return &sqlx.Builder{
+		QuoteOpening: '"',
+		QuoteClosing: '"',
+		Schema:       opts.SchemaQualifier,
+		Indent:       opts.Indent,
+	}
+}
+
+// ScanStmts implements migrate.StmtScanner.
+func (*Driver) ScanStmts(input string) ([]*migrate.Stmt, error) {
+	stmts, err := (&migrate.Scanner{
+		ScannerOptions: migrate.ScannerOptions{
+			MatchBegin: true,
+			// The following are not supported by Oracle.
+			MatchBeginAtomic: false,
+			MatchDollarQuote: false,
+		},
+	}).Scan(input)
+	if err != nil {
+		return nil, err
+	}
+	// Unlike PL/SQL blocks, SQL statements executed by the
+	// database driver must not be terminated by a semicolon.
+	for _, s := range stmts {
+		if !rePLSQL.MatchString(s.Text) {
+			s.Text = strings.TrimRightFunc(strings.TrimSuffix(s.Text, ";"), unicode.IsSpace)
+		}
+	}
+	return stmts, nil
+}
+
+// rePLSQL matches the beginning of PL/SQL blocks and stored program units.
+var rePLSQL = regexp.MustCompile(`(?is)^(?:DECLARE|BEGIN)\b|^CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:NON)?EDITIONABLE\s+)?(?:FUNCTION|PROCEDURE|PACKAGE|TRIGGER|TYPE\s+BODY)\b`)
+
+// acquire requests an exclusive user lock with the given timeout.
+// See: https://docs.oracle.com/en/database/oracle/oracle-database/19/arpls/DBMS_LOCK.html
+func acquire(ctx context.Context, conn schema.ExecQuerier, id int64, timeout time.Duration) error {
+	rows, err := conn.QueryContext(ctx, "SELECT DBMS_LOCK.REQUEST(:1, 6, :2) FROM DUAL", id, int64(timeout.Seconds()))
+	if err != nil {
+		return err
+	}
+	var status sql.NullInt64
+	if err := sqlx.ScanOne(rows, &status); err != nil {
+		return err
+	}
+	switch status.Int64 {
+	// Success, or the lock is already owned by this session.
+	case 0, 4:
+		return nil
+	case 1:
+		return schema.ErrLocked
+	default:
+		return fmt.Errorf("oracle: failed acquiring lock %d: status %d", id, status.Int64)
+	}
+}
+
+// bound returns the schema the driver is bound to.
+func (c *conn) bound() string {
+	if c.schema != "" {
+		return c.schema
+	}
+	return c.current
+}
+
+// major returns the major version of the connected database.
+func (c *conn) major() int {
+	v, _, _ := strings.Cut(c.version, ".")
+	n, _ := strconv.Atoi(v)
+	return n
+}
+
+// maxIdentLen returns the maximum length (in bytes) of identifiers.
+// Before Oracle 12.2, identifiers were limited to 30 bytes.
+func (c *conn) maxIdentLen() int {
+	if v := c.major(); v > 12 || v == 12 && !strings.HasPrefix(c.version, "12.1") {
+		return 128
+	}
+	return 30
+}
+
+type parser struct{}
+
+// ParseURL implements the sqlclient.URLParser interface.
+func (parser) ParseURL(u *url.URL) *sqlclient.URL {
+	dsn := *u
+	// The schema parameter is used by Atlas and not passed to the underlying driver.
+	if q := dsn.Query(); q.Has("schema") {
+		q.Del("schema")
+		dsn.RawQuery = q.Encode()
+	}
+	return &sqlclient.URL{URL: u, DSN: dsn.String(), Schema: u.Query().Get("schema")}
+}
+
+// ChangeSchema implements the sqlclient.SchemaChanger interface.
+func (parser) ChangeSchema(u *url.URL, s string) *url.URL {
+	nu := *u
+	q := nu.Query()
+	q.Set("schema", s)
+	nu.RawQuery = q.Encode()
+	return &nu
+}
+
+// Standard column types (and their aliases) as defined in
+// the Oracle documentation.
+// https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/Data-Types.html
+const (
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeInt     = "int"      // integer.
+	TypeSmall   = "smallint" // integer.
+	TypeDecimal = "decimal"  // number.
+	TypeNumeric = "numeric"  // number.
+
+	TypeFloat        = "float"
+	TypeReal         = "real"             // float(63).
+	TypeDouble       = "double precision" // float(126).
+	TypeBinaryFloat  = "binary_float"
+	TypeBinaryDouble = "binary_double"
+
+	TypeVarchar2  = "varchar2"
+	TypeVarchar   = "varchar" // varchar2.
+	TypeNVarchar2 = "nvarchar2"
+	TypeChar      = "char"
+	TypeNChar     = "nchar"
+	TypeCLOB      = "clob"
+	TypeNCLOB     = "nclob"
+	TypeLong      = "long"
+
+	TypeBLOB    = "blob"
+	TypeRaw     = "raw"
+	TypeLongRaw = "long raw"
+
+	TypeDate         = "date"
+	TypeTimestamp    = "timestamp"
+	TypeTimestampTZ  = "timestamp with time zone"
+	TypeTimestampLTZ = "timestamp with local time zone"
+
+	TypeJSON    = "json"    // Oracle 21c.
+	TypeBoolean = "boolean" // Oracle 23ai.
+)
+
+const (
+	// Default fractional seconds precision of timestamp types.
+	defaultTimePrec = 6
+	// Default binary precision of the float type.
+	defaultFloatPrec = 126
+	// Prefix of the names generated by the database for unnamed constraints.
+	generatedNameP = "SYS_C"
+	// Virtual columns are the only generated columns supported by Oracle.
+	virtual = "VIRTUAL"
+	// Identity columns generation types.
+	identityAlways          = "ALWAYS"
+	identityByDefault       = "BY DEFAULT"
+	identityByDefaultOnNull = "BY DEFAULT ON NULL"
+)
+
+// Query to list the current schema and the database version.
+const paramsQuery = `SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'), (SELECT VERSION FROM PRODUCT_COMPONENT_VERSION WHERE PRODUCT LIKE 'Oracle%' AND ROWNUM = 1) FROM DUAL`
@@ -0,0 +1,591 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+)
+
+// An inspect provides an Oracle implementation for schema.Inspector.
+type inspect struct{ *conn }
+
+var _ schema.Inspector = (*inspect)(nil)
+
+// InspectRealm returns schema descriptions of all resources in the given realm.
+func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOption) (*schema.Realm, error) {
+	schemas, err := i.schemas(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &schema.InspectRealmOption{}
+	}
+	var (
+		r    = schema.NewRealm(schemas...)
+		mode = sqlx.ModeInspectRealm(opts)
+	)
+	if len(schemas) > 0 && mode.Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, nil); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
+}
+
+// InspectSchema returns schema descriptions of the tables in the given schema.
+// If the schema name is empty, the result will be the attached schema.
+func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.InspectOptions) (*schema.Schema, error) {
+	if name == "" {
+		name = i.bound()
+	}
+	schemas, err := i.schemas(ctx, &schema.InspectRealmOption{Schemas: []string{name}})
+	if err != nil {
+		return nil, err
+	}
+	switch n := len(schemas); {
+	case n == 0:
+		return nil, &schema.NotExistError{Err: fmt.Errorf("oracle: schema %q was not found", name)}
+	case n > 1:
+		return nil, fmt.Errorf("oracle: %d schemas were found for %q", n, name)
+	}
+	if opts == nil {
+		opts = &schema.InspectOptions{}
+	}
+	var (
+		r    = schema.NewRealm(schemas...)
+		mode = sqlx.ModeInspectSchema(opts)
+	)
+	if mode.Is(schema.InspectTables) {
+		if err := i.inspectTables(ctx, r, opts); err != nil {
+			return nil, err
+		}
+		sqlx.LinkSchemaTables(schemas)
+	}
+	return sqlx.SchemaChecksums(schema.ExcludeSchema(r.Schemas[0], opts.Exclude))
+}
+
+func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
+	if err := i.tables(ctx, r, opts); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Tables) == 0 {
+			continue
+		}
+		if err := i.columns(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.indexes(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.fks(ctx, s, opts); err != nil {
+			return err
+		}
+		if err := i.checks(ctx, s, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// schemas returns the list of the schemas in the database.
+func (i *inspect) schemas(ctx context.Context, opts *schema.InspectRealmOption) ([]*schema.Schema, error) {
+	var (
+		args  []any
+		query = schemasQuery
+	)
+	if opts != nil && len(opts.Schemas) > 0 {
+		query = fmt.Sprintf(schemasQueryArgs, nArgs(1, len(opts.Schemas)))
+		for _, s := range opts.Schemas {
+			args = append(args, s)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("oracle: querying schemas: %w", err)
+	}
+	defer rows.Close()
+	var schemas []*schema.Schema
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema.New(name))
+	}
+	return schemas, rows.Err()
+}
+
+func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.InspectOptions) error {
+	var (
+		args  []any
+		query = fmt.Sprintf(tablesQuery, nArgs(1, len(realm.Schemas)))
+	)
+	for _, s := range realm.Schemas {
+		args = append(args, s.Name)
+	}
+	if opts != nil && len(opts.Tables) > 0 {
+		query = fmt.Sprintf(tablesQueryArgs, nArgs(1, len(realm.Schemas)), nArgs(len(args)+1, len(opts.Tables)))
+		for _, t := range opts.Tables {
+			args = append(args, t)
+		}
+	}
+	rows, err := i.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("oracle: querying schema tables: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tSchema, name, comment sql.NullString
+		if err := rows.Scan(&tSchema, &name, &comment); err != nil {
+			return fmt.Errorf("scan table information: %w", err)
+		}
+		if !sqlx.ValidString(tSchema) || !sqlx.ValidString(name) {
+			return fmt.Errorf("invalid schema or table name: %q.%q", tSchema.String, name.String)
+		}
+		s, ok := realm.Schema(tSchema.String)
+		if !ok {
+			return fmt.Errorf("schema %q was not found in realm", tSchema.String)
+		}
+		t := schema.NewTable(name.String)
+		s.AddTables(t)
+		if sqlx.ValidString(comment) {
+			t.SetComment(comment.String)
+		}
+	}
+	return rows.Err()
+}
+
+// columns queries and appends the columns of the given table.
+func (i *inspect) columns(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, columnsQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("oracle: query schema %q columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := i.addColumn(s, rows); err != nil {
+			return fmt.Errorf("oracle: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+// addColumn scans the current row and adds a new column from it to the table.
+func (i *inspect) addColumn(s *schema.Schema, rows *sql.Rows) error {
+	var (
+		length, precision, scale, charLength                               sql.NullInt64
+		table, name, typ, charUsed, nullable, defaults, virt, comment, gen sql.NullString
+	)
+	if err := rows.Scan(&table, &name, &typ, &length, &precision, &scale, &charLength, &charUsed, &nullable, &defaults, &virt, &comment, &gen); err != nil {
+		return err
+	}
+	t, ok := s.Table(table.String)
+	// Skip columns of tables that are not inspected by the driver, like tables in the recycle bin.
+	if !ok {
+		return nil
+	}
+	c := &schema.Column{
+		Name: name.String,
+		Type: &schema.ColumnType{
+			Raw:  columnType(typ.String, length, precision, scale, charLength, charUsed.String),
+			Null: nullable.String == "Y",
+		},
+	}
+	ct, err := ParseType(c.Type.Raw)
+	if err != nil {
+		return fmt.Errorf("parse %q.%q type %q: %w", t.Name, c.Name, c.Type.Raw, err)
+	}
+	c.Type.Type = ct
+	switch x := strings.TrimSpace(defaults.String); {
+	case virt.String == "YES":
+		c.SetGeneratedExpr(&schema.GeneratedExpr{Expr: x, Type: virtual})
+	// The default value of identity columns is the sequence they use.
+	case sqlx.ValidString(gen):
+		c.AddAttrs(&Identity{Generation: gen.String})
+	case x != "" && !strings.EqualFold(x, "NULL"):
+		c.Default = defaultExpr(x)
+	}
+	if sqlx.ValidString(comment) {
+		c.SetComment(comment.String)
+	}
+	t.AddColumns(c)
+	return nil
+}
+
+// columnType returns the column type as defined in the database, as the DATA_TYPE
+// of the ALL_TAB_COLS view does not include the length and precision arguments.
+func columnType(typ string, length, precision, scale, charLength sql.NullInt64, charUsed string) string {
+	switch typ {
+	case "NUMBER":
+		switch {
+		case !precision.Valid && !scale.Valid:
+			return TypeNumber
+		case !precision.Valid && scale.Int64 == 0:
+			return TypeInteger
+		case !precision.Valid:
+			return fmt.Sprintf("%s(*,%d)", TypeNumber, scale.Int64)
+		case scale.Int64 == 0:
+			return fmt.Sprintf("%s(%d)", TypeNumber, precision.Int64)
+		default:
+			return fmt.Sprintf("%s(%d,%d)", TypeNumber, precision.Int64, scale.Int64)
+		}
+	case "FLOAT":
+		if precision.Valid {
+			return fmt.Sprintf("%s(%d)", TypeFloat, precision.Int64)
+		}
+		return TypeFloat
+	case "VARCHAR2", "CHAR":
+		if charUsed == "C" {
+			return fmt.Sprintf("%s(%d char)", strings.ToLower(typ), charLength.Int64)
+		}
+		return fmt.Sprintf("%s(%d)", strings.ToLower(typ), charLength.Int64)
+	case "NVARCHAR2", "NCHAR":
+		return fmt.Sprintf("%s(%d)", strings.ToLower(typ), charLength.Int64)
+	case "RAW":
+		return fmt.Sprintf("%s(%d)", TypeRaw, length.Int64)
+	default:
+		return strings.ToLower(typ)
+	}
+}
+
+// indexes queries and appends the indexes of the given table.
+func (i *inspect) indexes(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, indexesQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("oracle: query schema %q indexes: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := i.addIndexes(s, rows); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// reQuotedIdent matches a quoted identifier, which is the expression
+// of descending index parts that are defined on columns.
+var reQuotedIdent = regexp.MustCompile(`^"([^"]+)"$`)
+
+// addIndexes scans the rows and adds the indexes to the table.
+func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows) error {
+	for rows.Next() {
+		var (
+			seqno                                                   int
+			table, name, uniq, column, desc, expr, conType, conName sql.NullString
+		)
+		if err := rows.Scan(&table, &name, &uniq, &column, &seqno, &desc, &expr, &conType, &conName); err != nil {
+			return fmt.Errorf("oracle: scanning indexes for schema %q: %w", s.Name, err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			continue
+		}
+		var idx *schema.Index
+		switch {
+		case conType.String == "P":
+			if idx = t.PrimaryKey; idx == nil {
+				idx = schema.NewPrimaryKey().SetName(conName.String)
+				t.SetPrimaryKey(idx)
+			}
+		default:
+			idxName := name.String
+			// Indexes that enforce UNIQUE constraints are named after the constraint.
+			if conType.String == "U" {
+				idxName = conName.String
+			}
+			if idx, ok = t.Index(idxName); !ok {
+				idx = schema.NewIndex(idxName).SetUnique(uniq.String == "UNIQUE")
+				if conType.String == "U" {
+					idx.SetUnique(true).AddAttrs(&Constraint{N: conName.String, T: conType.String})
+				}
+				t.AddIndexes(idx)
+			}
+		}
+		// Rows are ordered by COLUMN_POSITION that specifies the
+		// position of the column in the index definition.
+		part := &schema.IndexPart{SeqNo: seqno, Desc: desc.String == "DESC"}
+		x := strings.TrimSpace(expr.String)
+		// Descending parts are stored as function-based index parts,
+		// where the expression is the quoted name of the column.
+		if m := reQuotedIdent.FindStringSubmatch(x); m != nil {
+			if _, ok := t.Column(m[1]); ok {
+				x, column.String = "", m[1]
+			}
+		}
+		switch {
+		case x != "":
+			part.X = &schema.RawExpr{X: x}
+		case sqlx.ValidString(column):
+			part.C, ok = t.Column(column.String)
+			if !ok {
+				return fmt.Errorf("oracle: column %q was not found for index %q", column.String, idx.Name)
+			}
+			part.C.Indexes = append(part.C.Indexes, idx)
+		default:
+			return fmt.Errorf("oracle: invalid part for index %q", idx.Name)
+		}
+		idx.Parts = append(idx.Parts, part)
+	}
+	return nil
+}
+
+// fks queries and appends the foreign keys of the given table.
+func (i *inspect) fks(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, fksQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("oracle: querying %q foreign keys: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := sqlx.SchemaFKs(s, rows); err != nil {
+		return fmt.Errorf("oracle: %w", err)
+	}
+	return rows.Err()
+}
+
+// reNotNull matches the CHECK constraints that are
+// created by the database for NOT NULL columns.
+var reNotNull = regexp.MustCompile(`^"[^"]+" IS NOT NULL$`)
+
+// checks queries and appends the check constraints of the given table.
+func (i *inspect) checks(ctx context.Context, s *schema.Schema, opts *schema.InspectOptions) error {
+	rows, err := i.querySchema(ctx, checksQuery, s, opts)
+	if err != nil {
+		return fmt.Errorf("oracle: querying %q check constraints: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, name, clause, generated sql.NullString
+		if err := rows.Scan(&table, &name, &clause, &generated); err != nil {
+			return fmt.Errorf("oracle: %w", err)
+		}
+		t, ok := s.Table(table.String)
+		if !ok {
+			continue
+		}
+		x := strings.TrimSpace(clause.String)
+		if generated.String == "GENERATED NAME" && reNotNull.MatchString(x) {
+			continue
+		}
+		t.AddChecks(&schema.Check{
+			Name: name.String,
+			Expr: x,
+		})
+	}
+	return rows.Err()
+}
+
+// querySchema calls sqlx.QueryContext on the given query with the schema name
+// as the first argument, and the inspected table names (if filtered) after it.
+func (i *inspect) querySchema(ctx context.Context, query string, s *schema.Schema, opts *schema.InspectOptions) (*sql.Rows, error) {
+	var (
+		filter string
+		args   = []any{s.Name}
+	)
+	if opts != nil && len(opts.Tables) > 0 {
+		filter = fmt.Sprintf(" AND t.TABLE_NAME IN (%s)", nArgs(2, len(s.Tables)))
+		for _, t := range s.Tables {
+			args = append(args, t.Name)
+		}
+	}
+	return i.QueryContext(ctx, fmt.Sprintf(query, filter), args...)
+}
+
+// nArgs returns n positional bind placeholders starting from the given position.
+func nArgs(start, n int) string {
+	args := make([]string, n)
+	for i := range args {
+		args[i] = ":" + strconv.Itoa(start+i)
+	}
+	return strings.Join(args, ", ")
+}
+
+func defaultExpr(x string) schema.Expr {
+	switch {
+	case sqlx.IsLiteralNumber(x), sqlx.IsQuoted(x, '\''):
+		return &schema.Literal{V: x}
+	default:
+		return &schema.RawExpr{X: x}
+	}
+}
+
+type (
+	// Identity defines an identity column.
+	// https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/CREATE-TABLE.html#GUID-F9CE0CC3-13AE-4744-A43C-EAC7A71AAAB6
+	Identity struct {
+		schema.Attr
+		// Generation is one of ALWAYS, BY DEFAULT or BY DEFAULT ON NULL.
+		Generation string
+	}
+
+	// CharSemantics describes a string type that its length is
+	// measured in characters rather than bytes. e.g., VARCHAR2(10 CHAR).
+	CharSemantics struct {
+		schema.Attr
+	}
+
+	// Constraint describes the constraint that is enforced by an index.
+	Constraint struct {
+		schema.Attr
+		N string // constraint name
+		T string // P, U.
+	}
+
+	// UserDefinedType defines a user-defined type, or a
+	// built-in type that is not supported by the driver.
+	UserDefinedType struct {
+		schema.Type
+		T string
+	}
+)
+
+const (
+	// Query to list database schemas. Users that are maintained
+	// by the database (e.g., SYS or SYSTEM) are excluded.
+	schemasQuery = `SELECT USERNAME FROM ALL_USERS WHERE ORACLE_MAINTAINED = 'N' ORDER BY USERNAME`
+
+	// Query to list specific database schemas.
+	schemasQueryArgs = `SELECT USERNAME FROM ALL_USERS WHERE USERNAME IN (%s) ORDER BY USERNAME`
+
+	// Query to list schema tables.
+	tablesQuery = `
+SELECT
+	t.OWNER,
+	t.TABLE_NAME,
+	c.COMMENTS
+FROM
+	ALL_TABLES t
+	LEFT JOIN ALL_TAB_COMMENTS c ON c.OWNER = t.OWNER AND c.TABLE_NAME = t.TABLE_NAME
+WHERE
+	t.OWNER IN (%s)
+	AND t.DROPPED = 'NO'
+	AND t.NESTED = 'NO'
+	AND t.SECONDARY = 'N'
+	AND (t.IOT_TYPE IS NULL OR t.IOT_TYPE = 'IOT')
+ORDER BY
+	t.OWNER, t.TABLE_NAME
+`
+
+	// Query to list specific schema tables.
+	tablesQueryArgs = `
+SELECT
+	t.OWNER,
+	t.TABLE_NAME,
+	c.COMMENTS
+FROM
+	ALL_TABLES t
+	LEFT JOIN ALL_TAB_COMMENTS c ON c.OWNER = t.OWNER AND c.TABLE_NAME = t.TABLE_NAME
+WHERE
+	t.OWNER IN (%s)
+	AND t.TABLE_NAME IN (%s)
+	AND t.DROPPED = 'NO'
+	AND t.NESTED = 'NO'
+	AND t.SECONDARY = 'N'
+	AND (t.IOT_TYPE IS NULL OR t.IOT_TYPE = 'IOT')
+ORDER BY
+	t.OWNER, t.TABLE_NAME
+`
+
+	// Query to list table columns.
+	columnsQuery = `
+SELECT
+	t.TABLE_NAME,
+	t.COLUMN_NAME,
+	t.DATA_TYPE,
+	t.DATA_LENGTH,
+	t.DATA_PRECISION,
+	t.DATA_SCALE,
+	t.CHAR_LENGTH,
+	t.CHAR_USED,
+	t.NULLABLE,
+	t.DATA_DEFAULT,
+	t.VIRTUAL_COLUMN,
+	c.COMMENTS,
+	i.GENERATION_TYPE
+FROM
+	ALL_TAB_COLS t
+	LEFT JOIN ALL_COL_COMMENTS c ON c.OWNER = t.OWNER AND c.TABLE_NAME = t.TABLE_NAME AND c.COLUMN_NAME = t.COLUMN_NAME
+	LEFT JOIN ALL_TAB_IDENTITY_COLS i ON i.OWNER = t.OWNER AND i.TABLE_NAME = t.TABLE_NAME AND i.COLUMN_NAME = t.COLUMN_NAME
+WHERE
+	t.OWNER = :1
+	AND t.HIDDEN_COLUMN = 'NO'%s
+ORDER BY
+	t.TABLE_NAME, t.COLUMN_ID
+`
+
+	// Query to list table indexes, and the constraints they enforce.
+	indexesQuery = `
+SELECT
+	t.TABLE_NAME,
+	t.INDEX_NAME,
+	t.UNIQUENESS,
+	c.COLUMN_NAME,
+	c.COLUMN_POSITION,
+	c.DESCEND,
+	e.COLUMN_EXPRESSION,
+	k.CONSTRAINT_TYPE,
+	k.CONSTRAINT_NAME
+FROM
+	ALL_INDEXES t
+	JOIN ALL_IND_COLUMNS c ON c.INDEX_OWNER = t.OWNER AND c.INDEX_NAME = t.INDEX_NAME
+	LEFT JOIN ALL_IND_EXPRESSIONS e ON e.INDEX_OWNER = c.INDEX_OWNER AND e.INDEX_NAME = c.INDEX_NAME AND e.COLUMN_POSITION = c.COLUMN_POSITION
+	LEFT JOIN ALL_CONSTRAINTS k ON k.OWNER = t.TABLE_OWNER AND k.TABLE_NAME = t.TABLE_NAME AND k.INDEX_NAME = t.INDEX_NAME AND k.CONSTRAINT_TYPE IN ('P', 'U')
+WHERE
+	t.TABLE_OWNER = :1
+	AND t.INDEX_TYPE IN ('NORMAL', 'FUNCTION-BASED NORMAL')%s
+ORDER BY
+	t.TABLE_NAME, t.INDEX_NAME, c.COLUMN_POSITION
+`
+
+	// Query to list table foreign keys. Oracle does not support
+	// the ON UPDATE clause, and updates are always restricted.
+	fksQuery = `
+SELECT
+	t.CONSTRAINT_NAME,
+	t.TABLE_NAME,
+	c.COLUMN_NAME,
+	t.OWNER,
+	r.TABLE_NAME,
+	rc.COLUMN_NAME,
+	r.OWNER,
+	'NO ACTION',
+	t.DELETE_RULE
+FROM
+	ALL_CONSTRAINTS t
+	JOIN ALL_CONS_COLUMNS c ON c.OWNER = t.OWNER AND c.CONSTRAINT_NAME = t.CONSTRAINT_NAME
+	JOIN ALL_CONSTRAINTS r ON r.OWNER = t.R_OWNER AND r.CONSTRAINT_NAME = t.R_CONSTRAINT_NAME
+	JOIN ALL_CONS_COLUMNS rc ON rc.OWNER = r.OWNER AND rc.CONSTRAINT_NAME = r.CONSTRAINT_NAME AND rc.POSITION = c.POSITION
+WHERE
+	t.OWNER = :1
+	AND t.CONSTRAINT_TYPE = 'R'
+	AND t.TABLE_NAME NOT LIKE 'BIN$%%'%s
+ORDER BY
+	t.CONSTRAINT_NAME, c.POSITION
+`
+
+	// Query to list table check constraints.
+	checksQuery = `
+SELECT
+	t.TABLE_NAME,
+	t.CONSTRAINT_NAME,
+	t.SEARCH_CONDITION_VC,
+	t.GENERATED
+FROM
+	ALL_CONSTRAINTS t
+WHERE
+	t.OWNER = :1
+	AND t.CONSTRAINT_TYPE = 'C'%s
+ORDER BY
+	t.TABLE_NAME, t.CONSTRAINT_NAME
+`
+)
@@ -0,0 +1,136 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_InspectSchema(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("19.0.0.0.0")
+	drv, err := Open(db)
+	require.NoError(t, err)
+
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(schemasQueryArgs, ":1"))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ USERNAME
+----------
+ APP
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, ":1"))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ OWNER | TABLE_NAME | COMMENTS
+-------+------------+----------
+ APP   | POSTS      | nil
+ APP   | USERS      | users table
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, ""))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ TABLE_NAME | COLUMN_NAME | DATA_TYPE                   | DATA_LENGTH | DATA_PRECISION | DATA_SCALE | CHAR_LENGTH | CHAR_USED | NULLABLE | DATA_DEFAULT    | VIRTUAL_COLUMN | COMMENTS | GENERATION_TYPE
+------------+-------------+-----------------------------+-------------+----------------+------------+-------------+-----------+----------+-----------------+----------------+----------+----------------
+ POSTS      | ID          | NUMBER                      | 22          | nil            | 0          | 0           | nil       | N        | "APP"."ISEQ$$_1".nextval | NO | nil    | BY DEFAULT
+ POSTS      | AUTHOR_ID   | NUMBER                      | 22          | nil            | 0          | 0           | nil       | Y        | nil             | NO             | nil      | nil
+ POSTS      | BODY        | CLOB                        | 4000        | nil            | nil        | 0           | nil       | Y        | nil             | NO             | nil      | nil
+ USERS      | ID          | NUMBER                      | 22          | 10             | 0          | 0           | nil       | N        | nil             | NO             | nil      | nil
+ USERS      | NAME        | VARCHAR2                    | 400         | nil            | nil        | 100         | C         | N        | 'unknown'       | NO             | user name| nil
+ USERS      | PRICE       | NUMBER                      | 22          | 10             | 2          | 0           | nil       | Y        | 0               | NO             | nil      | nil
+ USERS      | CREATED_AT  | TIMESTAMP(6) WITH TIME ZONE | 13          | nil            | 6          | 0           | nil       | N        | SYSTIMESTAMP    | NO             | nil      | nil
+ USERS      | TOTAL       | NUMBER                      | 22          | nil            | nil        | 0           | nil       | Y        | "PRICE"*2       | YES            | nil      | nil
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesQuery, ""))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ TABLE_NAME | INDEX_NAME   | UNIQUENESS | COLUMN_NAME  | COLUMN_POSITION | DESCEND | COLUMN_EXPRESSION | CONSTRAINT_TYPE | CONSTRAINT_NAME
+------------+--------------+------------+--------------+-----------------+---------+-------------------+-----------------+----------------
+ POSTS      | POSTS_AUTHOR | NONUNIQUE  | AUTHOR_ID    | 1               | ASC     | nil               | nil             | nil
+ POSTS      | SYS_C008     | UNIQUE     | ID           | 1               | ASC     | nil               | P               | SYS_C008
+ USERS      | SYS_C001     | UNIQUE     | ID           | 1               | ASC     | nil               | P               | USERS_PK
+ USERS      | SYS_C002     | UNIQUE     | NAME         | 1               | ASC     | nil               | U               | USERS_NAME
+ USERS      | USERS_LOWER  | NONUNIQUE  | SYS_NC00005$ | 1               | ASC     | LOWER("NAME")     | nil             | nil
+ USERS      | USERS_LOWER  | NONUNIQUE  | SYS_NC00006$ | 2               | DESC    | "PRICE"           | nil             | nil
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksQuery, ""))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ CONSTRAINT_NAME | TABLE_NAME | COLUMN_NAME | OWNER | TABLE_NAME | COLUMN_NAME | OWNER | UPDATE_RULE | DELETE_RULE
+-----------------+------------+-------------+-------+------------+-------------+-------+-------------+------------
+ POSTS_AUTHOR    | POSTS      | AUTHOR_ID   | APP   | USERS      | ID          | APP   | NO ACTION   | CASCADE
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(checksQuery, ""))).
+		WithArgs("APP").
+		WillReturnRows(sqltest.Rows(`
+ TABLE_NAME | CONSTRAINT_NAME | SEARCH_CONDITION_VC | GENERATED
+------------+-----------------+---------------------+---------------
+ USERS      | SYS_C003        | "ID" IS NOT NULL    | GENERATED NAME
+ USERS      | USERS_PRICE     | PRICE > 0           | USER NAME
+`))
+	s, err := drv.InspectSchema(context.Background(), "", nil)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+
+	require.Equal(t, "APP", s.Name)
+	require.Len(t, s.Tables, 2)
+	posts, users := s.Tables[0], s.Tables[1]
+
+	require.Equal(t, []*schema.Column{
+		{Name: "ID", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: TypeInteger}}, Attrs: []schema.Attr{&Identity{Generation: "BY DEFAULT"}}},
+		{Name: "AUTHOR_ID", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: TypeInteger}, Null: true}},
+		{Name: "BODY", Type: &schema.ColumnType{Raw: "clob", Type: &schema.StringType{T: TypeCLOB}, Null: true}},
+	}, dropIndexes(posts.Columns))
+	require.Equal(t, "SYS_C008", posts.PrimaryKey.Name)
+	require.Len(t, posts.Indexes, 1)
+	require.Equal(t, "POSTS_AUTHOR", posts.Indexes[0].Name)
+	require.False(t, posts.Indexes[0].Unique)
+	require.Len(t, posts.ForeignKeys, 1)
+	fk := posts.ForeignKeys[0]
+	require.Equal(t, "POSTS_AUTHOR", fk.Symbol)
+	require.Equal(t, users, fk.RefTable)
+	require.Equal(t, schema.Cascade, fk.OnDelete)
+
+	var c schema.Comment
+	require.True(t, sqlx.Has(users.Attrs, &c))
+	require.Equal(t, "users table", c.Text)
+	require.Equal(t, []*schema.Column{
+		{Name: "ID", Type: &schema.ColumnType{Raw: "number(10)", Type: &schema.DecimalType{T: TypeNumber, Precision: 10}}},
+		{Name: "NAME", Type: &schema.ColumnType{Raw: "varchar2(100 char)", Type: &schema.StringType{T: TypeVarchar2, Size: 100, Attrs: []schema.Attr{&CharSemantics{}}}}, Default: &schema.Literal{V: "'unknown'"}, Attrs: []schema.Attr{&schema.Comment{Text: "user name"}}},
+		{Name: "PRICE", Type: &schema.ColumnType{Raw: "number(10,2)", Type: &schema.DecimalType{T: TypeNumber, Precision: 10, Scale: 2}, Null: true}, Default: &schema.Literal{V: "0"}},
+		{Name: "CREATED_AT", Type: &schema.ColumnType{Raw: "timestamp(6) with time zone", Type: &schema.TimeType{T: TypeTimestampTZ}}, Default: &schema.RawExpr{X: "SYSTIMESTAMP"}},
+		{Name: "TOTAL", Type: &schema.ColumnType{Raw: "number", Type: &schema.DecimalType{T: TypeNumber}, Null: true}, Attrs: []schema.Attr{&schema.GeneratedExpr{Expr: `"PRICE"*2`, Type: virtual}}},
+	}, dropIndexes(users.Columns))
+	require.Equal(t, "USERS_PK", users.PrimaryKey.Name)
+	require.Len(t, users.Indexes, 2)
+	require.Equal(t, "USERS_NAME", users.Indexes[0].Name)
+	require.True(t, users.Indexes[0].Unique)
+	require.True(t, isConstraint(users.Indexes[0]))
+	idx := users.Indexes[1]
+	require.Equal(t, "USERS_LOWER", idx.Name)
+	require.Len(t, idx.Parts, 2)
+	require.Equal(t, &schema.RawExpr{X: `LOWER("NAME")`}, idx.Parts[0].X)
+	require.Equal(t, "PRICE", idx.Parts[1].C.Name)
+	require.True(t, idx.Parts[1].Desc)
+	require.Equal(t, []schema.Attr{&c, &schema.Check{Name: "USERS_PRICE", Expr: "PRICE > 0"}}, schema.RemoveAttr[*schema.Checksum](users.Attrs))
+}
+
+func dropIndexes(columns []*schema.Column) []*schema.Column {
+	for _, c := range columns {
+		c.Indexes, c.ForeignKeys = nil, nil
+	}
+	return columns
+}
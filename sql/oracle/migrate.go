@@ -0,0 +1,759 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// DefaultPlan provides basic planning capabilities for Oracle dialects.
+// Note, it is recommended to call Open, create a new Driver and use its
+// migrate.PlanApplier when a database connection is available.
+var DefaultPlan migrate.PlanApplier = &planApply{conn: &conn{ExecQuerier: sqlx.NoRows, version: "19.0.0.0.0"}}
+
+// A planApply provides migration capabilities for schema elements.
+type planApply struct{ *conn }
+
+// PlanChanges returns a migration plan for the given schema changes.
+func (p *planApply) PlanChanges(_ context.Context, name string, changes []schema.Change, opts ...migrate.PlanOption) (*migrate.Plan, error) {
+	s := &state{
+		conn: p.conn,
+		Plan: migrate.Plan{
+			Name: name,
+			// DDL statements in Oracle are committed implicitly.
+			Transactional: false,
+		},
+	}
+	for _, o := range opts {
+		o(&s.PlanOptions)
+	}
+	if err := identChecker(s.PlanOptions, s.maxIdentLen()).CheckChanges(changes); err != nil {
+		return nil, err
+	}
+	if err := s.plan(changes); err != nil {
+		return nil, err
+	}
+	if err := sqlx.SetReversible(&s.Plan); err != nil {
+		return nil, err
+	}
+	return &s.Plan, nil
+}
+
+// ApplyChanges applies the changes on the database. An error is returned
+// if the driver is unable to produce a plan to it, or one of the statements
+// is failed or unsupported.
+func (p *planApply) ApplyChanges(ctx context.Context, changes []schema.Change, opts ...migrate.PlanOption) error {
+	return sqlx.ApplyChanges(ctx, changes, p, opts...)
+}
+
+// state represents the state of a planning. It is not part of
+// planApply so that multiple planning/applying can be called
+// in parallel.
+type state struct {
+	*conn
+	migrate.Plan
+	migrate.PlanOptions
+}
+
+// plan builds the migration plan for applying the
+// given changes on the attached connection.
+func (s *state) plan(changes []schema.Change) error {
+	if s.SchemaQualifier != nil {
+		if err := sqlx.CheckChangesScope(s.PlanOptions, changes); err != nil {
+			return err
+		}
+	}
+	planned, err := s.topLevel(changes)
+	if err != nil {
+		return err
+	}
+	if s.PlanOptions.Mode != migrate.PlanModeUnsortedDump {
+		planned, err = sqlx.DetachCycles(planned)
+		if err != nil {
+			return err
+		}
+		planned = sqlx.SortChanges(planned, nil)
+	}
+	for _, c := range planned {
+		switch c := c.(type) {
+		case *schema.AddTable:
+			err = s.addTable(c)
+		case *schema.DropTable:
+			err = s.dropTable(c)
+		case *schema.ModifyTable:
+			err = s.modifyTable(c)
+		case *schema.RenameTable:
+			s.renameTable(c)
+		default:
+			err = fmt.Errorf("unsupported change %T", c)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// topLevel appends first the changes for creating or dropping schemas (top-level schema elements).
+// Note, schemas in Oracle are users, and creating or dropping them requires administrative privileges.
+func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
+	planned := make([]schema.Change, 0, len(changes))
+	for _, c := range changes {
+		switch c := c.(type) {
+		case *schema.AddSchema:
+			s.append(&migrate.Change{
+				Cmd:     s.Build("CREATE USER").Ident(c.S.Name).P("NO AUTHENTICATION").String(),
+				Source:  c,
+				Reverse: s.Build("DROP USER").Ident(c.S.Name).P("CASCADE").String(),
+				Comment: fmt.Sprintf("add new schema named %q", c.S.Name),
+			})
+		case *schema.DropSchema:
+			s.append(&migrate.Change{
+				Cmd:     s.Build("DROP USER").Ident(c.S.Name).P("CASCADE").String(),
+				Source:  c,
+				Comment: fmt.Sprintf("drop schema named %q", c.S.Name),
+			})
+		case *schema.ModifySchema:
+			if len(c.Changes) > 0 {
+				return nil, fmt.Errorf("unsupported schema changes for %q", c.S.Name)
+			}
+		default:
+			planned = append(planned, c)
+		}
+	}
+	return planned, nil
+}
+
+// addTable builds and appends the migration changes
+// for creating a table in a schema.
+func (s *state) addTable(add *schema.AddTable) error {
+	var (
+		errs []string
+		b    = s.Build("CREATE TABLE")
+	)
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		// IF NOT EXISTS was added to CREATE TABLE in Oracle 23c.
+		if s.major() < 23 {
+			return fmt.Errorf("create table %q: IF NOT EXISTS is not supported by Oracle versions before 23c", add.T.Name)
+		}
+		b.P("IF NOT EXISTS")
+	}
+	b.Table(add.T)
+	if len(add.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns", add.T.Name)
+	}
+	b.WrapIndent(func(b *sqlx.Builder) {
+		b.MapIndent(add.T.Columns, func(i int, b *sqlx.Builder) {
+			if err := s.column(b, add.T.Columns[i]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		})
+		if pk := add.T.PrimaryKey; pk != nil {
+			b.Comma().NL()
+			s.primaryKey(b, pk)
+		}
+		for _, idx := range add.T.Indexes {
+			if isConstraint(idx) {
+				b.Comma().NL()
+				s.unique(b, idx)
+			}
+		}
+		for _, fk := range add.T.ForeignKeys {
+			b.Comma().NL()
+			if err := s.fk(b, fk); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		for _, attr := range add.T.Attrs {
+			if c, ok := attr.(*schema.Check); ok {
+				b.Comma().NL()
+				check(b, c)
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Reverse: s.Build("DROP TABLE").Table(add.T).String(),
+		Comment: fmt.Sprintf("create %q table", add.T.Name),
+	})
+	for _, idx := range add.T.Indexes {
+		if !isConstraint(idx) {
+			s.addIndex(add.T, idx)
+		}
+	}
+	s.tableComments(add)
+	return nil
+}
+
+// dropTable builds and appends the migrate.Change
+// for dropping a table from a schema.
+func (s *state) dropTable(drop *schema.DropTable) error {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	b := s.Build("DROP TABLE")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	b.Table(drop.T)
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop %q table", drop.T.Name),
+		// The reverse of 'DROP TABLE' might be a multi
+		// statement operation. e.g., table with indexes.
+		Reverse: func() any {
+			cmd := make([]string, len(rs.Changes))
+			for i, c := range rs.Changes {
+				cmd[i] = c.Cmd
+			}
+			if len(cmd) == 1 {
+				return cmd[0]
+			}
+			return cmd
+		}(),
+	})
+	return nil
+}
+
+// modifyTable builds and appends the migration changes for
+// bringing the table into its modified state. Unlike other
+// databases, each change is executed in a separate statement.
+func (s *state) modifyTable(modify *schema.ModifyTable) error {
+	if len(modify.T.Columns) == 0 {
+		return fmt.Errorf("table %q has no columns; drop the table instead", modify.T.Name)
+	}
+	var changes [2][]schema.Change
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		// Constraints and indexes modifications are translated into 2 steps.
+		// Dropping the current one and creating a new one.
+		case *schema.ModifyForeignKey:
+			changes[0] = append(changes[0], &schema.DropForeignKey{F: change.From})
+			changes[1] = append(changes[1], &schema.AddForeignKey{F: change.To})
+		case *schema.ModifyIndex:
+			changes[0] = append(changes[0], &schema.DropIndex{I: change.From})
+			changes[1] = append(changes[1], &schema.AddIndex{I: change.To})
+		case *schema.ModifyPrimaryKey:
+			changes[0] = append(changes[0], &schema.DropPrimaryKey{P: change.From})
+			changes[1] = append(changes[1], &schema.AddPrimaryKey{P: change.To})
+		case *schema.ModifyCheck:
+			changes[0] = append(changes[0], &schema.DropCheck{C: change.From})
+			changes[1] = append(changes[1], &schema.AddCheck{C: change.To})
+		case *schema.DropForeignKey, *schema.DropIndex, *schema.DropPrimaryKey, *schema.DropCheck:
+			changes[0] = append(changes[0], change)
+		default:
+			changes[1] = append(changes[1], change)
+		}
+	}
+	for _, c := range append(changes[0], changes[1]...) {
+		if err := s.alterTable(modify.T, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// alterTable appends the statement for applying the given change on the table.
+func (s *state) alterTable(t *schema.Table, change schema.Change) error {
+	var (
+		b       = s.Build("ALTER TABLE").Table(t)
+		r       = b.Clone()
+		reverse = true
+		comment = fmt.Sprintf("modify %q table", t.Name)
+	)
+	switch change := change.(type) {
+	case *schema.AddColumn:
+		if err := s.column(b.P("ADD").Clone(), change.C); err != nil {
+			return err
+		}
+		b.Wrap(func(b *sqlx.Builder) {
+			_ = s.column(b, change.C)
+		})
+		r.P("DROP COLUMN").Ident(change.C.Name)
+		comment = fmt.Sprintf("add column %q to table: %q", change.C.Name, t.Name)
+		if c := (schema.Comment{}); sqlx.Has(change.C.Attrs, &c) && c.Text != "" {
+			defer s.columnComment(t, change.C, change, "", c.Text)
+		}
+	case *schema.DropColumn:
+		b.P("DROP COLUMN").Ident(change.C.Name)
+		if err := s.column(r.P("ADD").Clone(), change.C); err != nil {
+			return err
+		}
+		r.Wrap(func(b *sqlx.Builder) {
+			_ = s.column(b, change.C)
+		})
+		comment = fmt.Sprintf("drop column %q from table: %q", change.C.Name, t.Name)
+	case *schema.ModifyColumn:
+		return s.modifyColumn(t, change)
+	case *schema.RenameColumn:
+		b.P("RENAME COLUMN").Ident(change.From.Name).P("TO").Ident(change.To.Name)
+		r.P("RENAME COLUMN").Ident(change.To.Name).P("TO").Ident(change.From.Name)
+		comment = fmt.Sprintf("rename a column from %q to %q", change.From.Name, change.To.Name)
+	case *schema.AddIndex:
+		if !isConstraint(change.I) {
+			s.addIndex(t, change.I)
+			return nil
+		}
+		s.unique(b.P("ADD"), change.I)
+		r.P("DROP CONSTRAINT").Ident(change.I.Name)
+	case *schema.DropIndex:
+		if !isConstraint(change.I) {
+			s.dropIndex(t, change.I)
+			return nil
+		}
+		b.P("DROP CONSTRAINT").Ident(change.I.Name)
+		s.unique(r.P("ADD"), change.I)
+	case *schema.RenameIndex:
+		if !isConstraint(change.From) {
+			s.append(&migrate.Change{
+				Source:  change,
+				Cmd:     s.Build("ALTER INDEX").SchemaResource(t.Schema, change.From.Name).P("RENAME TO").Ident(change.To.Name).String(),
+				Reverse: s.Build("ALTER INDEX").SchemaResource(t.Schema, change.To.Name).P("RENAME TO").Ident(change.From.Name).String(),
+				Comment: fmt.Sprintf("rename an index from %q to %q", change.From.Name, change.To.Name),
+			})
+			return nil
+		}
+		b.P("RENAME CONSTRAINT").Ident(change.From.Name).P("TO").Ident(change.To.Name)
+		r.P("RENAME CONSTRAINT").Ident(change.To.Name).P("TO").Ident(change.From.Name)
+	case *schema.AddPrimaryKey:
+		s.primaryKey(b.P("ADD"), change.P)
+		r.P("DROP PRIMARY KEY")
+	case *schema.DropPrimaryKey:
+		b.P("DROP PRIMARY KEY")
+		s.primaryKey(r.P("ADD"), change.P)
+	case *schema.AddForeignKey:
+		if err := s.fk(b.P("ADD"), change.F); err != nil {
+			return err
+		}
+		r.P("DROP CONSTRAINT").Ident(change.F.Symbol)
+	case *schema.DropForeignKey:
+		b.P("DROP CONSTRAINT").Ident(change.F.Symbol)
+		if err := s.fk(r.P("ADD"), change.F); err != nil {
+			return err
+		}
+	case *schema.AddCheck:
+		check(b.P("ADD"), change.C)
+		r.P("DROP CONSTRAINT").Ident(change.C.Name)
+		// Reverse operation is supported if
+		// the constraint name is not generated.
+		reverse = change.C.Name != ""
+	case *schema.DropCheck:
+		if change.C.Name == "" {
+			return fmt.Errorf("alter table %q: cannot drop unnamed check constraint", t.Name)
+		}
+		b.P("DROP CONSTRAINT").Ident(change.C.Name)
+		check(r.P("ADD"), change.C)
+	case *schema.AddAttr, *schema.ModifyAttr, *schema.DropAttr:
+		return s.tableComment(t, change)
+	default:
+		return fmt.Errorf("alter table %q: unsupported change %T", t.Name, change)
+	}
+	c := &migrate.Change{
+		Cmd:     b.String(),
+		Source:  &schema.ModifyTable{T: t, Changes: []schema.Change{change}},
+		Comment: comment,
+	}
+	if reverse {
+		c.Reverse = r.String()
+	}
+	s.append(c)
+	return nil
+}
+
+// modifyColumn appends the statements for modifying a column.
+// Only the changed parts of the column are written to the MODIFY
+// clause, as Oracle fails modifying a column to its current state.
+func (s *state) modifyColumn(t *schema.Table, change *schema.ModifyColumn) error {
+	from, to := change.From, change.To
+	if change.Change.Is(schema.ChangeGenerated) {
+		return fmt.Errorf("alter table %q: changing the generated expression of column %q is not supported", t.Name, to.Name)
+	}
+	var (
+		fromI, toI     Identity
+		fromHas, toHas = sqlx.Has(from.Attrs, &fromI), sqlx.Has(to.Attrs, &toI)
+	)
+	switch {
+	case !fromHas && toHas:
+		return fmt.Errorf("alter table %q: changing column %q to an identity column is not supported", t.Name, to.Name)
+	case fromHas && !toHas:
+		s.append(&migrate.Change{
+			Cmd:     s.Build("ALTER TABLE").Table(t).P("MODIFY").Wrap(func(b *sqlx.Builder) { b.Ident(to.Name).P("DROP IDENTITY") }).String(),
+			Source:  change,
+			Comment: fmt.Sprintf("drop identity from column %q of table: %q", to.Name, t.Name),
+		})
+	}
+	// Identity generation changes are part of the MODIFY clause.
+	k := change.Change & (schema.ChangeType | schema.ChangeDefault | schema.ChangeNull)
+	if fromHas && toHas && identityGeneration(fromI.Generation) != identityGeneration(toI.Generation) {
+		k |= schema.ChangeAttr
+	}
+	if k != schema.NoChange {
+		cmd, err := s.modifyClause(t, from, to, k)
+		if err != nil {
+			return err
+		}
+		rev, err := s.modifyClause(t, to, from, k)
+		if err != nil {
+			return err
+		}
+		s.append(&migrate.Change{
+			Cmd:     cmd,
+			Source:  change,
+			Reverse: rev,
+			Comment: fmt.Sprintf("modify column %q of table: %q", to.Name, t.Name),
+		})
+	}
+	if change.Change.Is(schema.ChangeComment) {
+		var c1, c2 schema.Comment
+		sqlx.Has(from.Attrs, &c1)
+		sqlx.Has(to.Attrs, &c2)
+		s.columnComment(t, to, change, c1.Text, c2.Text)
+	}
+	return nil
+}
+
+// modifyClause returns the statement for modifying the column to its desired
+// state. Only the changed parts are written to the MODIFY clause, because
+// Oracle fails when a column is modified to its current nullability.
+func (s *state) modifyClause(t *schema.Table, from, to *schema.Column, k schema.ChangeKind) (string, error) {
+	var err error
+	b := s.Build("ALTER TABLE").Table(t).P("MODIFY")
+	b.Wrap(func(b *sqlx.Builder) {
+		b.Ident(to.Name)
+		if k.Is(schema.ChangeType) {
+			err = s.columnType(b, to)
+		}
+		if k.Is(schema.ChangeDefault) && err == nil {
+			if to.Default == nil {
+				b.P("DEFAULT NULL")
+			} else {
+				err = s.columnDefault(b, to)
+			}
+		}
+		if i := (Identity{}); k.Is(schema.ChangeAttr) && sqlx.Has(to.Attrs, &i) {
+			b.P("GENERATED", identityGeneration(i.Generation), "AS IDENTITY")
+		}
+		if k.Is(schema.ChangeNull) {
+			if !to.Type.Null {
+				b.P("NOT")
+			}
+			b.P("NULL")
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("alter table %q: %w", t.Name, err)
+	}
+	return b.String(), nil
+}
+
+// renameTable appends the statement for renaming a table.
+func (s *state) renameTable(c *schema.RenameTable) {
+	s.append(&migrate.Change{
+		Source:  c,
+		Comment: fmt.Sprintf("rename a table from %q to %q", c.From.Name, c.To.Name),
+		// The new name of the table cannot be qualified with the schema name.
+		Cmd:     s.Build("ALTER TABLE").Table(c.From).P("RENAME TO").Ident(c.To.Name).String(),
+		Reverse: s.Build("ALTER TABLE").Table(c.To).P("RENAME TO").Ident(c.From.Name).String(),
+	})
+}
+
+// column writes the column definition to the builder.
+func (s *state) column(b *sqlx.Builder, c *schema.Column) error {
+	b.Ident(c.Name)
+	if err := s.columnType(b, c); err != nil {
+		return err
+	}
+	var (
+		id     Identity
+		x      schema.GeneratedExpr
+		hasI   = sqlx.Has(c.Attrs, &id)
+		hasX   = sqlx.Has(c.Attrs, &x)
+		hasDef = c.Default != nil
+	)
+	switch {
+	case hasI && (hasX || hasDef):
+		return fmt.Errorf("identity column %q cannot have a default value or a generation expression", c.Name)
+	case hasX && hasDef:
+		return fmt.Errorf("generated column %q cannot have a default value", c.Name)
+	case hasI:
+		b.P("GENERATED", identityGeneration(id.Generation), "AS IDENTITY")
+	case hasX:
+		b.P("GENERATED ALWAYS AS", sqlx.MayWrap(x.Expr), virtual)
+	case hasDef:
+		if err := s.columnDefault(b, c); err != nil {
+			return err
+		}
+	}
+	if !c.Type.Null {
+		b.P("NOT NULL")
+	}
+	return nil
+}
+
+// columnType writes the column type to the builder.
+func (*state) columnType(b *sqlx.Builder, c *schema.Column) error {
+	t, err := FormatType(c.Type.Type)
+	if err != nil {
+		return fmt.Errorf("format type for column %q: %w", c.Name, err)
+	}
+	b.P(t)
+	return nil
+}
+
+// columnDefault writes the default value of column to the builder.
+func (*state) columnDefault(b *sqlx.Builder, c *schema.Column) error {
+	switch x := c.Default.(type) {
+	case *schema.Literal:
+		v := x.V
+		switch c.Type.Type.(type) {
+		case *schema.BoolType, *schema.DecimalType, *schema.IntegerType, *schema.FloatType:
+		default:
+			var err error
+			if v, err = sqlx.SingleQuote(v); err != nil {
+				return err
+			}
+		}
+		b.P("DEFAULT", v)
+	case *schema.RawExpr:
+		b.P("DEFAULT", x.X)
+	default:
+		return fmt.Errorf("unexpected default value type: %T", x)
+	}
+	return nil
+}
+
+// primaryKey writes the PRIMARY KEY constraint to the builder.
+func (*state) primaryKey(b *sqlx.Builder, pk *schema.Index) {
+	if pk.Name != "" && !strings.HasPrefix(pk.Name, generatedNameP) {
+		b.P("CONSTRAINT").Ident(pk.Name)
+	}
+	b.P("PRIMARY KEY")
+	indexParts(b, pk)
+}
+
+// unique writes the UNIQUE constraint to the builder.
+func (*state) unique(b *sqlx.Builder, idx *schema.Index) {
+	if idx.Name != "" && !strings.HasPrefix(idx.Name, generatedNameP) {
+		b.P("CONSTRAINT").Ident(idx.Name)
+	}
+	b.P("UNIQUE")
+	indexParts(b, idx)
+}
+
+// fk writes the FOREIGN KEY constraint to the builder.
+func (*state) fk(b *sqlx.Builder, fk *schema.ForeignKey) error {
+	if fk.Symbol != "" {
+		b.P("CONSTRAINT").Ident(fk.Symbol)
+	}
+	b.P("FOREIGN KEY")
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(fk.Columns, func(i int, b *sqlx.Builder) {
+			b.Ident(fk.Columns[i].Name)
+		})
+	})
+	b.P("REFERENCES").Table(fk.RefTable)
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(fk.RefColumns, func(i int, b *sqlx.Builder) {
+			b.Ident(fk.RefColumns[i].Name)
+		})
+	})
+	// Oracle does not support the ON UPDATE clause, and
+	// its default action is equivalent to NO ACTION.
+	switch fk.OnUpdate {
+	case "", schema.NoAction, schema.Restrict:
+	default:
+		return fmt.Errorf("foreign key %q: ON UPDATE %s is not supported by Oracle", fk.Symbol, fk.OnUpdate)
+	}
+	switch fk.OnDelete {
+	case "", schema.NoAction, schema.Restrict:
+	case schema.Cascade, schema.SetNull:
+		b.P("ON DELETE", string(fk.OnDelete))
+	default:
+		return fmt.Errorf("foreign key %q: ON DELETE %s is not supported by Oracle", fk.Symbol, fk.OnDelete)
+	}
+	return nil
+}
+
+// addIndex appends the statement for creating the given index.
+func (s *state) addIndex(t *schema.Table, idx *schema.Index) {
+	b := s.Build("CREATE")
+	if idx.Unique {
+		b.P("UNIQUE")
+	}
+	b.P("INDEX").SchemaResource(t.Schema, idx.Name).P("ON").Table(t)
+	indexParts(b, idx)
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  &schema.AddIndex{I: idx},
+		Reverse: s.Build("DROP INDEX").SchemaResource(t.Schema, idx.Name).String(),
+		Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
+	})
+}
+
+// dropIndex appends the statement for dropping the given index.
+func (s *state) dropIndex(t *schema.Table, idx *schema.Index) {
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	rs.addIndex(t, idx)
+	s.append(&migrate.Change{
+		Cmd:     rs.Changes[0].Reverse.(string),
+		Source:  &schema.DropIndex{I: idx},
+		Reverse: rs.Changes[0].Cmd,
+		Comment: fmt.Sprintf("drop index %q from table: %q", idx.Name, t.Name),
+	})
+}
+
+// tableComments appends the statements for setting the (non-empty)
+// comments of the created table and its columns.
+func (s *state) tableComments(add *schema.AddTable) {
+	var c schema.Comment
+	if sqlx.Has(add.T.Attrs, &c) && c.Text != "" {
+		s.append(&migrate.Change{
+			Cmd:     s.Build("COMMENT ON TABLE").Table(add.T).P("IS", quote(c.Text)).String(),
+			Source:  add,
+			Reverse: s.Build("COMMENT ON TABLE").Table(add.T).P("IS", quote("")).String(),
+			Comment: fmt.Sprintf("set comment to table: %q", add.T.Name),
+		})
+	}
+	for _, col := range add.T.Columns {
+		if c := (schema.Comment{}); sqlx.Has(col.Attrs, &c) && c.Text != "" {
+			s.columnComment(add.T, col, add, "", c.Text)
+		}
+	}
+}
+
+// columnComment appends the statement for changing the column comment.
+func (s *state) columnComment(t *schema.Table, c *schema.Column, source schema.Change, from, to string) {
+	s.append(&migrate.Change{
+		Cmd:     s.Build("COMMENT ON COLUMN").TableColumn(t, c).P("IS", quote(to)).String(),
+		Source:  source,
+		Reverse: s.Build("COMMENT ON COLUMN").TableColumn(t, c).P("IS", quote(from)).String(),
+		Comment: fmt.Sprintf("set comment to column: %q on table: %q", c.Name, t.Name),
+	})
+}
+
+// tableComment appends the statement for changing the table comment.
+func (s *state) tableComment(t *schema.Table, change schema.Change) error {
+	var from, to schema.Comment
+	switch change := change.(type) {
+	case *schema.AddAttr:
+		c, ok := change.A.(*schema.Comment)
+		if !ok {
+			return fmt.Errorf("unexpected table attribute %T", change.A)
+		}
+		to = *c
+	case *schema.DropAttr:
+		c, ok := change.A.(*schema.Comment)
+		if !ok {
+			return fmt.Errorf("unexpected table attribute %T", change.A)
+		}
+		from = *c
+	case *schema.ModifyAttr:
+		c1, ok1 := change.From.(*schema.Comment)
+		c2, ok2 := change.To.(*schema.Comment)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("unexpected table attribute change from %T to %T", change.From, change.To)
+		}
+		from, to = *c1, *c2
+	}
+	s.append(&migrate.Change{
+		Cmd:     s.Build("COMMENT ON TABLE").Table(t).P("IS", quote(to.Text)).String(),
+		Source:  change,
+		Reverse: s.Build("COMMENT ON TABLE").Table(t).P("IS", quote(from.Text)).String(),
+		Comment: fmt.Sprintf("set comment to table: %q", t.Name),
+	})
+	return nil
+}
+
+func (s *state) append(c *migrate.Change) {
+	s.Changes = append(s.Changes, c)
+}
+
+// Build instantiates a new builder and writes the given phrase to it.
+func (s *state) Build(phrases ...string) *sqlx.Builder {
+	return (*Driver).StmtBuilder(nil, s.PlanOptions).
+		P(phrases...)
+}
+
+// indexParts writes the index parts to the builder.
+func indexParts(b *sqlx.Builder, idx *schema.Index) {
+	b.Wrap(func(b *sqlx.Builder) {
+		b.MapComma(idx.Parts, func(i int, b *sqlx.Builder) {
+			switch part := idx.Parts[i]; {
+			case part.C != nil:
+				b.Ident(part.C.Name)
+			case part.X != nil:
+				b.WriteString(sqlx.MayWrap(part.X.(*schema.RawExpr).X))
+			}
+			if idx.Parts[i].Desc {
+				b.P("DESC")
+			}
+		})
+	})
+}
+
+// check writes the CHECK constraint to the builder.
+func check(b *sqlx.Builder, c *schema.Check) {
+	if c.Name != "" {
+		b.P("CONSTRAINT").Ident(c.Name)
+	}
+	b.P("CHECK", sqlx.MayWrap(c.Expr))
+}
+
+// isConstraint reports if the index is a UNIQUE constraint.
+func isConstraint(idx *schema.Index) bool {
+	c := &Constraint{}
+	return idx.Unique && sqlx.Has(idx.Attrs, c) && c.T == "U"
+}
+
+// quote returns the given string as an SQL string literal.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// identChecker returns the checker of the identifiers used by the planned changes.
+func identChecker(opts migrate.PlanOptions, maxLen int) *sqlx.IdentChecker {
+	c := &sqlx.IdentChecker{MaxLen: maxLen, Unit: "bytes"}
+	if opts.CheckReserved {
+		c.Reserved = func(name string) bool {
+			_, ok := reservedWords[strings.ToLower(name)]
+			return ok
+		}
+	}
+	return c
+}
+
+// reservedWords holds the reserved words of Oracle SQL.
+// https://docs.oracle.com/en/database/oracle/oracle-database/19/sqlrf/Oracle-SQL-Reserved-Words.html
+var reservedWords = map[string]struct{}{
+	"access": {}, "add": {}, "all": {}, "alter": {}, "and": {}, "any": {}, "as": {}, "asc": {},
+	"audit": {}, "between": {}, "by": {}, "char": {}, "check": {}, "cluster": {}, "column": {},
+	"column_value": {}, "comment": {}, "compress": {}, "connect": {}, "create": {}, "current": {},
+	"date": {}, "decimal": {}, "default": {}, "delete": {}, "desc": {}, "distinct": {}, "drop": {},
+	"else": {}, "exclusive": {}, "exists": {}, "file": {}, "float": {}, "for": {}, "from": {},
+	"grant": {}, "group": {}, "having": {}, "identified": {}, "immediate": {}, "in": {},
+	"increment": {}, "index": {}, "initial": {}, "insert": {}, "integer": {}, "intersect": {},
+	"into": {}, "is": {}, "level": {}, "like": {}, "lock": {}, "long": {}, "maxextents": {},
+	"minus": {}, "mlslabel": {}, "mode": {}, "modify": {}, "nested_table_id": {}, "noaudit": {},
+	"nocompress": {}, "not": {}, "nowait": {}, "null": {}, "number": {}, "of": {}, "offline": {},
+	"on": {}, "online": {}, "option": {}, "or": {}, "order": {}, "pctfree": {}, "prior": {},
+	"public": {}, "raw": {}, "rename": {}, "resource": {}, "revoke": {}, "row": {}, "rowid": {},
+	"rownum": {}, "rows": {}, "select": {}, "session": {}, "set": {}, "share": {}, "size": {},
+	"smallint": {}, "start": {}, "successful": {}, "synonym": {}, "sysdate": {}, "table": {},
+	"then": {}, "to": {}, "trigger": {}, "uid": {}, "union": {}, "unique": {}, "update": {},
+	"user": {}, "validate": {}, "values": {}, "varchar": {}, "varchar2": {}, "view": {},
+	"whenever": {}, "where": {}, "with": {},
+}
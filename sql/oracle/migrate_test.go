@@ -0,0 +1,229 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanChanges(t *testing.T) {
+	users := schema.NewTable("users").
+		SetSchema(schema.New("APP")).
+		AddColumns(
+			schema.NewIntColumn("id", TypeInteger).AddAttrs(&Identity{}),
+			schema.NewStringColumn("name", TypeVarchar2, schema.StringSize(255)).SetComment("user's name"),
+			schema.NewColumn("price").SetType(&schema.DecimalType{T: TypeNumber, Precision: 10, Scale: 2}).SetDefault(&schema.Literal{V: "0"}),
+			schema.NewColumn("created_at").SetType(&schema.TimeType{T: TypeTimestampTZ}).SetDefault(&schema.RawExpr{X: "SYSTIMESTAMP"}),
+			schema.NewColumn("total").SetType(&schema.DecimalType{T: TypeNumber}).SetNull(true).SetGeneratedExpr(&schema.GeneratedExpr{Expr: "price * 2"}),
+		)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]).SetName("users_pk"))
+	users.AddIndexes(
+		schema.NewUniqueIndex("users_name").AddColumns(users.Columns[1]).AddAttrs(&Constraint{N: "users_name", T: "U"}),
+		schema.NewIndex("users_created").AddParts(schema.NewColumnPart(users.Columns[3]).SetDesc(true)),
+	)
+	users.AddChecks(schema.NewCheck().SetName("users_price").SetExpr("price > 0"))
+	users.SetComment("users table")
+	posts := schema.NewTable("posts").
+		SetSchema(users.Schema).
+		AddColumns(
+			schema.NewIntColumn("id", TypeInteger),
+			schema.NewIntColumn("author_id", TypeInteger),
+		)
+	posts.AddForeignKeys(
+		schema.NewForeignKey("posts_author").AddColumns(posts.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]).SetOnDelete(schema.Cascade),
+	)
+	tests := []struct {
+		version string
+		changes []schema.Change
+		options []migrate.PlanOption
+		plan    *migrate.Plan
+		wantErr string
+	}{
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("t").AddColumns(schema.NewIntColumn("a", TypeInteger)), Extra: []schema.Clause{&schema.IfNotExists{}}},
+			},
+			wantErr: `create table "t": IF NOT EXISTS is not supported by Oracle versions before 23c`,
+		},
+		{
+			version: "23.4.0.24.5",
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("t").AddColumns(schema.NewIntColumn("a", TypeInteger)), Extra: []schema.Clause{&schema.IfNotExists{}}},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE TABLE IF NOT EXISTS "t" ("a" integer NOT NULL)`, Reverse: `DROP TABLE "t"`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.AddSchema{S: schema.New("APP")}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `CREATE USER "APP" NO AUTHENTICATION`, Reverse: `DROP USER "APP" CASCADE`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{&schema.AddTable{T: users}, &schema.AddTable{T: posts}},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE TABLE "APP"."users" ("id" integer GENERATED ALWAYS AS IDENTITY NOT NULL, "name" varchar2(255) NOT NULL, "price" number(10,2) DEFAULT 0 NOT NULL, "created_at" timestamp with time zone DEFAULT SYSTIMESTAMP NOT NULL, "total" number GENERATED ALWAYS AS (price * 2) VIRTUAL, CONSTRAINT "users_pk" PRIMARY KEY ("id"), CONSTRAINT "users_name" UNIQUE ("name"), CONSTRAINT "users_price" CHECK (price > 0))`,
+						Reverse: `DROP TABLE "APP"."users"`,
+					},
+					{Cmd: `CREATE INDEX "APP"."users_created" ON "APP"."users" ("created_at" DESC)`, Reverse: `DROP INDEX "APP"."users_created"`},
+					{Cmd: `COMMENT ON TABLE "APP"."users" IS 'users table'`, Reverse: `COMMENT ON TABLE "APP"."users" IS ''`},
+					{Cmd: `COMMENT ON COLUMN "APP"."users"."name" IS 'user''s name'`, Reverse: `COMMENT ON COLUMN "APP"."users"."name" IS ''`},
+					{
+						Cmd:     `CREATE TABLE "APP"."posts" ("id" integer NOT NULL, "author_id" integer NOT NULL, CONSTRAINT "posts_author" FOREIGN KEY ("author_id") REFERENCES "APP"."users" ("id") ON DELETE CASCADE)`,
+						Reverse: `DROP TABLE "APP"."posts"`,
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewStringColumn("email", TypeVarchar2, schema.StringSize(100)).SetNull(true)},
+						&schema.DropCheck{C: users.Attrs[0].(*schema.Check)},
+						&schema.ModifyColumn{
+							From:   users.Columns[2],
+							To:     schema.NewColumn("price").SetType(&schema.DecimalType{T: TypeNumber, Precision: 12, Scale: 2}).SetNull(true),
+							Change: schema.ChangeType | schema.ChangeNull | schema.ChangeDefault,
+						},
+						&schema.RenameColumn{From: users.Columns[1], To: schema.NewColumn("full_name")},
+						&schema.ModifyAttr{From: &schema.Comment{Text: "users table"}, To: &schema.Comment{Text: "users"}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "APP"."users" DROP CONSTRAINT "users_price"`, Reverse: `ALTER TABLE "APP"."users" ADD CONSTRAINT "users_price" CHECK (price > 0)`},
+					{Cmd: `ALTER TABLE "APP"."users" ADD ("email" varchar2(100))`, Reverse: `ALTER TABLE "APP"."users" DROP COLUMN "email"`},
+					{Cmd: `ALTER TABLE "APP"."users" MODIFY ("price" number(12,2) DEFAULT NULL NULL)`, Reverse: `ALTER TABLE "APP"."users" MODIFY ("price" number(10,2) DEFAULT 0 NOT NULL)`},
+					{Cmd: `ALTER TABLE "APP"."users" RENAME COLUMN "name" TO "full_name"`, Reverse: `ALTER TABLE "APP"."users" RENAME COLUMN "full_name" TO "name"`},
+					{Cmd: `COMMENT ON TABLE "APP"."users" IS 'users'`, Reverse: `COMMENT ON TABLE "APP"."users" IS 'users table'`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.DropIndex{I: users.Indexes[0]},
+						&schema.DropIndex{I: users.Indexes[1]},
+						&schema.RenameIndex{From: schema.NewIndex("a"), To: schema.NewIndex("b")},
+					},
+				},
+				&schema.RenameTable{From: posts, To: schema.NewTable("articles").SetSchema(posts.Schema)},
+			},
+			plan: &migrate.Plan{
+				Reversible: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "APP"."users" DROP CONSTRAINT "users_name"`, Reverse: `ALTER TABLE "APP"."users" ADD CONSTRAINT "users_name" UNIQUE ("name")`},
+					{Cmd: `DROP INDEX "APP"."users_created"`, Reverse: `CREATE INDEX "APP"."users_created" ON "APP"."users" ("created_at" DESC)`},
+					{Cmd: `ALTER INDEX "APP"."a" RENAME TO "b"`, Reverse: `ALTER INDEX "APP"."b" RENAME TO "a"`},
+					{Cmd: `ALTER TABLE "APP"."posts" RENAME TO "articles"`, Reverse: `ALTER TABLE "APP"."articles" RENAME TO "posts"`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifyTable{
+					T: users,
+					Changes: []schema.Change{
+						&schema.ModifyColumn{
+							From:   users.Columns[0],
+							To:     schema.NewIntColumn("id", TypeInteger),
+							Change: schema.ChangeAttr,
+						},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TABLE "APP"."users" MODIFY ("id" DROP IDENTITY)`},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("t").AddColumns(schema.NewIntColumn("a", TypeInteger)).AddForeignKeys(
+					schema.NewForeignKey("fk").AddColumns(schema.NewIntColumn("a", TypeInteger)).SetRefTable(users).AddRefColumns(users.Columns[0]).SetOnUpdate(schema.Cascade),
+				)},
+			},
+			wantErr: `create table "t": foreign key "fk": ON UPDATE CASCADE is not supported by Oracle`,
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable(strings.Repeat("t", 129)).AddColumns(schema.NewIntColumn("a", TypeInteger))},
+			},
+			wantErr: "exceeds the maximum identifier length of 128 bytes",
+		},
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("users").AddColumns(schema.NewIntColumn("level", TypeInteger))},
+			},
+			options: []migrate.PlanOption{func(o *migrate.PlanOptions) { o.CheckReserved = true }},
+			wantErr: "reserved",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			db, m, err := sqlmock.New()
+			require.NoError(t, err)
+			if tt.version == "" {
+				tt.version = "19.0.0.0.0"
+			}
+			mock{m}.version(tt.version)
+			drv, err := Open(db)
+			require.NoError(t, err)
+			plan, err := drv.PlanChanges(context.Background(), "plan", tt.changes, tt.options...)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.plan.Reversible, plan.Reversible)
+			require.False(t, plan.Transactional)
+			require.Len(t, plan.Changes, len(tt.plan.Changes))
+			for i, c := range plan.Changes {
+				require.Equal(t, tt.plan.Changes[i].Cmd, c.Cmd)
+				require.Equal(t, tt.plan.Changes[i].Reverse, c.Reverse)
+			}
+		})
+	}
+}
+
+func TestDefaultPlan(t *testing.T) {
+	changes, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", TypeInteger))},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(changes.Changes))
+	require.Equal(t, `CREATE TABLE "t1" ("a" integer NOT NULL)`, changes.Changes[0].Cmd)
+
+	err = DefaultPlan.ApplyChanges(context.Background(), []schema.Change{
+		&schema.AddTable{T: schema.NewTable("t1").AddColumns(schema.NewIntColumn("a", TypeInteger))},
+	})
+	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
+}
@@ -0,0 +1,246 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/specutil"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlspec"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/zclconf/go-cty/cty"
+)
+
+type doc struct {
+	Tables  []*sqlspec.Table  `spec:"table"`
+	Schemas []*sqlspec.Schema `spec:"schema"`
+}
+
+// Codec for schemahcl.
+type Codec struct {
+	State *schemahcl.State
+}
+
+// Eval evaluates an Atlas DDL document into v using the input.
+func (c *Codec) Eval(p *hclparse.Parser, v any, input map[string]cty.Value) error {
+	return c.EvalOptions(p, v, &schemahcl.EvalOptions{Variables: input})
+}
+
+// EvalOptions decodes the HCL with the given options.
+func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptions) error {
+	switch v := v.(type) {
+	case *schema.Realm:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if err := specutil.Scan(v, &specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables}, scanFuncs); err != nil {
+			return fmt.Errorf("oracle: failed converting to *schema.Realm: %w", err)
+		}
+	case *schema.Schema:
+		var d doc
+		if err := c.State.EvalOptions(p, &d, opts); err != nil {
+			return err
+		}
+		if len(d.Schemas) != 1 {
+			return fmt.Errorf("oracle: expecting document to contain a single schema, got %d", len(d.Schemas))
+		}
+		r := &schema.Realm{}
+		if err := specutil.Scan(r, &specutil.ScanDoc{Schemas: d.Schemas, Tables: d.Tables}, scanFuncs); err != nil {
+			return err
+		}
+		*v = *r.Schemas[0]
+	case schema.Schema, schema.Realm:
+		return fmt.Errorf("oracle: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
+	default:
+		return fmt.Errorf("oracle: unexpected type %T", v)
+	}
+	return nil
+}
+
+// MarshalSpec marshals v into an Atlas DDL document using a schemahcl.Marshaler.
+func (c *Codec) MarshalSpec(v any) ([]byte, error) {
+	return specutil.Marshal(v, c.State, specutil.RealmFuncs{
+		Schema: schemaSpec,
+	})
+}
+
+var scanFuncs = &specutil.ScanFuncs{
+	Table: convertTable,
+}
+
+// convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
+// ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
+// at this point. Instead, the linking is done by the convertSchema function.
+func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, error) {
+	return specutil.Table(spec, parent, convertColumn, specutil.PrimaryKey, convertIndex, specutil.Check)
+}
+
+// convertIndex converts a sqlspec.Index into a schema.Index.
+func convertIndex(spec *sqlspec.Index, t *schema.Table) (*schema.Index, error) {
+	return specutil.Index(spec, t)
+}
+
+// convertColumn converts a sqlspec.Column into a schema.Column.
+func convertColumn(spec *sqlspec.Column, _ *schema.Table) (*schema.Column, error) {
+	c, err := specutil.Column(spec, convertColumnType)
+	if err != nil {
+		return nil, err
+	}
+	if r, ok := spec.Extra.Resource("identity"); ok {
+		var id struct {
+			Generation string `spec:"generated"`
+		}
+		if err := r.As(&id); err != nil {
+			return nil, err
+		}
+		c.Attrs = append(c.Attrs, &Identity{Generation: identityGeneration(specutil.FromVar(id.Generation))})
+	}
+	if err := specutil.ConvertGenExpr(spec.Remain(), c, generatedType); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// convertColumnType converts a sqlspec.Column into a concrete Oracle schema.Type.
+func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
+	return TypeRegistry.Type(spec.Type, spec.Extra.Attrs)
+}
+
+// schemaSpec converts from a concrete Oracle schema to Atlas specification.
+func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
+	return specutil.FromSchema(s, &specutil.SchemaFuncs{
+		Table: tableSpec,
+	})
+}
+
+// tableSpec converts from a concrete Oracle sqlspec.Table to a schema.Table.
+func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
+	return specutil.FromTable(
+		t,
+		columnSpec,
+		specutil.FromPrimaryKey,
+		indexSpec,
+		specutil.FromForeignKey,
+		specutil.FromCheck,
+	)
+}
+
+// indexSpec converts from a concrete Oracle schema.Index into a sqlspec.Index.
+func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
+	return specutil.FromIndex(idx)
+}
+
+// columnSpec converts from a concrete Oracle schema.Column into a sqlspec.Column.
+func columnSpec(c *schema.Column, _ *schema.Table) (*sqlspec.Column, error) {
+	s, err := specutil.FromColumn(c, columnTypeSpec)
+	if err != nil {
+		return nil, err
+	}
+	if i := (Identity{}); sqlx.Has(c.Attrs, &i) {
+		s.Extra.Children = append(s.Extra.Children, &schemahcl.Resource{
+			Type: "identity",
+			Attrs: []*schemahcl.Attr{
+				specutil.VarAttr("generated", specutil.Var(identityGeneration(i.Generation))),
+			},
+		})
+	}
+	if x := (schema.GeneratedExpr{}); sqlx.Has(c.Attrs, &x) {
+		s.Extra.Children = append(s.Extra.Children, specutil.FromGenExpr(x, generatedType))
+	}
+	return s, nil
+}
+
+// columnTypeSpec converts from a concrete Oracle schema.Type into sqlspec.Column Type.
+func columnTypeSpec(t schema.Type) (*sqlspec.Column, error) {
+	st, err := TypeRegistry.Convert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlspec.Column{Type: st}, nil
+}
+
+// TypeRegistry contains the supported TypeSpecs for the Oracle driver.
+var TypeRegistry = schemahcl.NewRegistry(
+	schemahcl.WithFormatter(FormatType),
+	schemahcl.WithParser(ParseType),
+	schemahcl.WithSpecs(
+		schemahcl.NewTypeSpec(TypeNumber, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr(), schemahcl.ScaleTypeAttr())),
+		schemahcl.NewTypeSpec(TypeInteger),
+		schemahcl.NewTypeSpec(TypeInt),
+		schemahcl.NewTypeSpec(TypeSmall),
+		schemahcl.NewTypeSpec(TypeFloat, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeBinaryFloat),
+		schemahcl.NewTypeSpec(TypeBinaryDouble),
+		schemahcl.NewTypeSpec(TypeVarchar2, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true)), schemahcl.WithToSpec(stringSpec)),
+		schemahcl.NewTypeSpec(TypeNVarchar2, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
+		schemahcl.NewTypeSpec(TypeChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false)), schemahcl.WithToSpec(stringSpec)),
+		schemahcl.NewTypeSpec(TypeNChar, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(false))),
+		schemahcl.NewTypeSpec(TypeCLOB),
+		schemahcl.NewTypeSpec(TypeNCLOB),
+		schemahcl.NewTypeSpec(TypeLong),
+		schemahcl.NewTypeSpec(TypeBLOB),
+		schemahcl.NewTypeSpec(TypeRaw, schemahcl.WithAttributes(schemahcl.SizeTypeAttr(true))),
+		schemahcl.AliasTypeSpec("long_raw", TypeLongRaw),
+		schemahcl.NewTypeSpec(TypeDate),
+		schemahcl.NewTypeSpec(TypeTimestamp, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.AliasTypeSpec("timestamp_with_time_zone", TypeTimestampTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.AliasTypeSpec("timestamp_with_local_time_zone", TypeTimestampLTZ, schemahcl.WithAttributes(schemahcl.PrecisionTypeAttr())),
+		schemahcl.NewTypeSpec(TypeJSON),
+		schemahcl.NewTypeSpec(TypeBoolean),
+	),
+)
+
+var (
+	codec = &Codec{
+		State: schemahcl.New(
+			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
+			schemahcl.WithScopedEnums("table.column.as.type", virtual),
+			schemahcl.WithScopedEnums("table.column.identity.generated", identityAlways, specutil.Var(identityByDefault), specutil.Var(identityByDefaultOnNull)),
+			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.column.diff.ignore", specutil.DiffIgnoreVars...),
+		),
+	}
+	// MarshalHCL marshals v into an Atlas HCL DDL document.
+	MarshalHCL = schemahcl.MarshalerFunc(codec.MarshalSpec)
+	// EvalHCL implements the schemahcl.Evaluator interface.
+	EvalHCL = schemahcl.EvalFunc(codec.Eval)
+	// EvalHCLBytes is a helper that evaluates an HCL document from a byte slice instead
+	// of from an hclparse.Parser instance.
+	EvalHCLBytes = specutil.HCLBytesFunc(EvalHCL)
+)
+
+// stringSpec converts character types with character length semantics
+// to their raw form, as the semantics are not an attribute of the spec.
+func stringSpec(t schema.Type) (*schemahcl.Type, error) {
+	s, ok := t.(*schema.StringType)
+	if !ok {
+		return nil, fmt.Errorf("oracle: unexpected string type %T", t)
+	}
+	if sqlx.Has(s.Attrs, &CharSemantics{}) {
+		f, err := FormatType(s)
+		if err != nil {
+			return nil, err
+		}
+		return &schemahcl.Type{T: f}, nil
+	}
+	st := &schemahcl.Type{T: strings.ToLower(s.T)}
+	if s.Size > 0 {
+		st.Attrs = append(st.Attrs, schemahcl.IntAttr("size", s.Size))
+	}
+	return st, nil
+}
+
+// generatedType returns the VIRTUAL generated type, the only
+// type of generated columns supported by Oracle.
+func generatedType(string) string {
+	return virtual
+}
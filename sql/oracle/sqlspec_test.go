@@ -0,0 +1,130 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package oracle
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSpec(t *testing.T) {
+	f := `
+schema "APP" {
+}
+
+table "USERS" {
+	schema = schema.APP
+	column "ID" {
+		type = integer
+		identity {
+			generated = BY_DEFAULT
+		}
+	}
+	column "NAME" {
+		type = sql("varchar2(100 char)")
+		comment = "user name"
+	}
+	column "CODE" {
+		type = char(2)
+		null = true
+	}
+	column "PRICE" {
+		type = number(10,2)
+		default = 0
+	}
+	column "CREATED_AT" {
+		type = timestamp_with_time_zone(3)
+		default = sql("SYSTIMESTAMP")
+	}
+	column "TOTAL" {
+		type = number
+		null = true
+		as {
+			expr = "PRICE * 2"
+		}
+	}
+	primary_key {
+		columns = [column.ID]
+	}
+	index "USERS_NAME" {
+		unique = true
+		columns = [column.NAME]
+	}
+	check "USERS_PRICE" {
+		expr = "PRICE > 0"
+	}
+	comment = "users table"
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	require.Equal(t, "APP", s.Name)
+	users, ok := s.Table("USERS")
+	require.True(t, ok)
+	require.Len(t, users.Columns, 6)
+	require.Equal(t, []schema.Attr{&Identity{Generation: identityByDefault}}, users.Columns[0].Attrs)
+	require.Equal(t, &schema.IntegerType{T: TypeInteger}, users.Columns[0].Type.Type)
+	require.Equal(t, &schema.StringType{T: TypeVarchar2, Size: 100, Attrs: []schema.Attr{&CharSemantics{}}}, users.Columns[1].Type.Type)
+	require.Equal(t, &schema.StringType{T: TypeChar, Size: 2}, users.Columns[2].Type.Type)
+	require.Equal(t, &schema.DecimalType{T: TypeNumber, Precision: 10, Scale: 2}, users.Columns[3].Type.Type)
+	require.Equal(t, &schema.Literal{V: "0"}, users.Columns[3].Default)
+	p := 3
+	require.Equal(t, &schema.TimeType{T: TypeTimestampTZ, Precision: &p}, users.Columns[4].Type.Type)
+	require.Equal(t, &schema.RawExpr{X: "SYSTIMESTAMP"}, users.Columns[4].Default)
+	require.Equal(t, []schema.Attr{&schema.GeneratedExpr{Expr: "PRICE * 2", Type: virtual}}, users.Columns[5].Attrs)
+	require.Equal(t, "ID", users.PrimaryKey.Parts[0].C.Name)
+	require.True(t, users.Indexes[0].Unique)
+	require.Equal(t, []schema.Attr{&schema.Check{Name: "USERS_PRICE", Expr: "PRICE > 0"}, &schema.Comment{Text: "users table"}}, users.Attrs)
+
+	// The schema should be marshaled back to the same document.
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	var s2 schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &s2, nil))
+	changes, err := DefaultDiff.SchemaDiff(&s, &s2)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestMarshalSpec(t *testing.T) {
+	s := schema.New("APP").AddTables(
+		schema.NewTable("T").AddColumns(
+			schema.NewIntColumn("ID", TypeInteger).AddAttrs(&Identity{Generation: identityAlways}),
+			schema.NewColumn("DATA").SetType(&schema.BinaryType{T: TypeRaw, Size: func() *int { v := 16; return &v }()}),
+			schema.NewColumn("NAME").SetType(&schema.StringType{T: TypeVarchar2, Size: 10, Attrs: []schema.Attr{&CharSemantics{}}}),
+			schema.NewColumn("GEO").SetType(&UserDefinedType{T: "sdo_geometry"}).SetNull(true),
+		),
+	)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, `table "T" {
+  schema = schema.APP
+  column "ID" {
+    null = false
+    type = integer
+    identity {
+      generated = ALWAYS
+    }
+  }
+  column "DATA" {
+    null = false
+    type = raw(16)
+  }
+  column "NAME" {
+    null = false
+    type = sql("varchar2(10 char)")
+  }
+  column "GEO" {
+    null = true
+    type = sql("sdo_geometry")
+  }
+}
+schema "APP" {
+}
+`, string(buf))
+}
@@ -57,6 +57,8 @@ func (i *crdbInspect) InspectSchema(ctx context.Context, name string, opts *sche
 		return nil, err
 	}
 	i.patchSchema(s)
+	// Checksums are computed again, as the tables were patched.
+	schema.SetChecksums(s)
 	return s, err
 }
 
@@ -68,6 +70,8 @@ func (i *crdbInspect) InspectRealm(ctx context.Context, opts *schema.InspectReal
 	for _, s := range r.Schemas {
 		i.patchSchema(s)
 	}
+	// Checksums are computed again, as the tables were patched.
+	schema.SetChecksums(r.Schemas...)
 	return r, nil
 }
 
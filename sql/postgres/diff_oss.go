@@ -10,7 +10,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
@@ -28,6 +31,573 @@ var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{&conn{ExecQuerier:
 // A diff provides a PostgreSQL implementation for sqlx.DiffDriver.
 type diff struct{ *conn }
 
+// RealmObjectDiff returns a changeset for migrating realm (database) objects
+// from one state to the other. For example, adding languages or casts, or
+// changing the settings of the database.
+func (*diff) RealmObjectDiff(from, to *schema.Realm) ([]schema.Change, error) {
+	var changes []schema.Change
+	// Drop or modify objects.
+	for _, o1 := range from.Objects {
+		switch o1 := o1.(type) {
+		case *Language:
+			switch o2, ok := realmLanguage(to, o1.Name); {
+			case !ok:
+				changes = append(changes, &schema.DropObject{O: o1})
+			case languageChanged(o1, o2):
+				changes = append(changes, &schema.ModifyObject{From: o1, To: o2})
+			}
+		case *Cast:
+			switch o2, ok := realmCast(to, o1.Source, o1.Target); {
+			case !ok:
+				changes = append(changes, &schema.DropObject{O: o1})
+			case castChanged(o1, o2):
+				changes = append(changes, &schema.ModifyObject{From: o1, To: o2})
+			}
+		case *Database:
+			switch o2, ok := realmDatabase(to); {
+			case !ok:
+				changes = append(changes, &schema.DropObject{O: o1})
+			case databaseChanged(o1, o2):
+				changes = append(changes, &schema.ModifyObject{From: o1, To: o2})
+			}
+		}
+	}
+	// Add new objects.
+	for _, o2 := range to.Objects {
+		switch o2 := o2.(type) {
+		case *Language:
+			if _, ok := realmLanguage(from, o2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: o2})
+			}
+		case *Cast:
+			if _, ok := realmCast(from, o2.Source, o2.Target); !ok {
+				changes = append(changes, &schema.AddObject{O: o2})
+			}
+		case *Database:
+			if _, ok := realmDatabase(from); !ok {
+				changes = append(changes, &schema.AddObject{O: o2})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// realmLanguage returns the language with the given name from the realm.
+func realmLanguage(r *schema.Realm, name string) (*Language, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		l, ok := o.(*Language)
+		return ok && l.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Language), true
+}
+
+// realmCast returns the cast between the given types from the realm.
+func realmCast(r *schema.Realm, source, target string) (*Cast, bool) {
+	source, target = castType(source), castType(target)
+	o, ok := r.Object(func(o schema.Object) bool {
+		c, ok := o.(*Cast)
+		return ok && castType(c.Source) == source && castType(c.Target) == target
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Cast), true
+}
+
+// realmDatabase returns the database object of the realm. Note, its name is
+// not compared, as a realm describes a single database, and the inspected
+// database might be named differently from the desired one (e.g., dev database).
+func realmDatabase(r *schema.Realm) (*Database, bool) {
+	o, ok := r.Object(func(o schema.Object) bool {
+		_, ok := o.(*Database)
+		return ok
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Database), true
+}
+
+// castType returns the canonical form of the given cast type.
+// For example, "int4" and "integer" are formatted as "integer".
+func castType(t string) string {
+	if pt, err := ParseType(t); err == nil {
+		if f, err := FormatType(pt); err == nil {
+			return f
+		}
+	}
+	return t
+}
+
+// languageChanged reports if the language definition or its comment were changed.
+func languageChanged(from, to *Language) bool {
+	return languageDefChanged(from, to) || sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// languageDefChanged reports if the language definition was changed.
+func languageDefChanged(from, to *Language) bool {
+	// The definition of packaged languages
+	// is controlled by their extensions.
+	if from.Handler == "" && to.Handler == "" {
+		return false
+	}
+	return from.Trusted != to.Trusted || from.Handler != to.Handler || from.Inline != to.Inline || from.Validator != to.Validator
+}
+
+// castChanged reports if the cast definition or its comment were changed.
+func castChanged(from, to *Cast) bool {
+	return castDefChanged(from, to) || sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// castDefChanged reports if the cast definition was changed.
+func castDefChanged(from, to *Cast) bool {
+	return from.Method != to.Method || castFuncChanged(from.Func, to.Func) || castContext(from) != castContext(to)
+}
+
+// castFuncChanged reports if the cast function was changed. Functions that are
+// visible in the search_path are inspected without their schema qualifier.
+func castFuncChanged(from, to string) bool {
+	if from == to {
+		return false
+	}
+	n1, a1, _ := strings.Cut(from, "(")
+	n2, a2, _ := strings.Cut(to, "(")
+	s1, n1 := parseFmtType(n1)
+	s2, n2 := parseFmtType(n2)
+	return n1 != n2 || a1 != a2 || s1 != "" && s2 != "" && s1 != s2
+}
+
+// databaseChanged reports if the database settings or its comment were changed.
+func databaseChanged(from, to *Database) bool {
+	if len(from.Settings) != len(to.Settings) {
+		return true
+	}
+	for _, s1 := range from.Settings {
+		s2, ok := to.Setting(s1.Name)
+		if !ok || s1.Value != s2.Value {
+			return true
+		}
+	}
+	return sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// castContext returns the context of the cast, with its default.
+func castContext(c *Cast) string {
+	if c.Context == "" {
+		return CastContextExplicit
+	}
+	return c.Context
+}
+
+// statisticsDiff returns a changeset for migrating the extended statistics of the schema.
+// Statistics defined on dropped tables are not dropped explicitly, as they are dropped
+// by the database along with their tables.
+func (d *diff) statisticsDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		s1, ok := o1.(*Statistics)
+		if !ok {
+			continue
+		}
+		switch s2, ok := schemaStatistics(to, s1.Name); {
+		case !ok:
+			if _, ok := to.Table(s1.T.Name); ok || s1.T.Schema != from {
+				changes = append(changes, &schema.DropObject{O: s1})
+			}
+		case d.statisticsChanged(s1, s2):
+			changes = append(changes, &schema.ModifyObject{From: s1, To: s2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if s2, ok := o2.(*Statistics); ok {
+			if _, ok := schemaStatistics(from, s2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: s2})
+			}
+		}
+	}
+	return changes
+}
+
+// schemaStatistics returns the statistics object with the given name from the schema.
+func schemaStatistics(s *schema.Schema, name string) (*Statistics, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		st, ok := o.(*Statistics)
+		return ok && st.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Statistics), true
+}
+
+// statisticsChanged reports if the statistics definition or its comment were changed.
+func (d *diff) statisticsChanged(from, to *Statistics) bool {
+	return d.statisticsDefChanged(from, to) || sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// statisticsDefChanged reports if the statistics definition was changed. Columns
+// and kinds are compared regardless of their order, as they are stored sorted.
+func (c *conn) statisticsDefChanged(from, to *Statistics) bool {
+	if from.T.Name != to.T.Name || from.T.Schema != nil && to.T.Schema != nil && from.T.Schema.Name != to.T.Schema.Name {
+		return true
+	}
+	names := func(cs []*schema.Column) []string {
+		ns := make([]string, len(cs))
+		for i := range cs {
+			ns[i] = cs[i].Name
+		}
+		slices.Sort(ns)
+		return ns
+	}
+	if !slices.Equal(names(from.Columns), names(to.Columns)) {
+		return true
+	}
+	if !slices.EqualFunc(from.Exprs, to.Exprs, func(x1, x2 string) bool {
+		return x1 == x2 || sqlx.MayWrap(x1) == sqlx.MayWrap(x2)
+	}) {
+		return true
+	}
+	return !slices.Equal(c.statisticsKindsOf(from), c.statisticsKindsOf(to))
+}
+
+// statisticsKindsOf returns the sorted kinds of the statistics, with their default.
+func (c *conn) statisticsKindsOf(s *Statistics) []string {
+	kinds := slices.Clone(s.Kinds)
+	if len(kinds) == 0 {
+		kinds = c.statisticsKinds()
+	}
+	for i, k := range kinds {
+		kinds[i] = strings.ToUpper(k)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// sequencesDiff returns a changeset for migrating the sequences of the schema. Sequences
+// owned by dropped tables are not dropped explicitly, as they are dropped by the database
+// along with their tables.
+func (*diff) sequencesDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		s1, ok := o1.(*Sequence)
+		if !ok {
+			continue
+		}
+		switch s2, ok := schemaSequence(to, s1.Name); {
+		case !ok:
+			if !sequenceOwnerDropped(s1, to) {
+				changes = append(changes, &schema.DropObject{O: s1})
+			}
+		case sequenceChanged(s1, s2):
+			changes = append(changes, &schema.ModifyObject{From: s1, To: s2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if s2, ok := o2.(*Sequence); ok {
+			if _, ok := schemaSequence(from, s2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: s2})
+			}
+		}
+	}
+	return changes
+}
+
+// schemaSequence returns the sequence with the given name from the schema.
+func schemaSequence(s *schema.Schema, name string) (*Sequence, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		seq, ok := o.(*Sequence)
+		return ok && seq.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Sequence), true
+}
+
+// sequenceOwnerDropped reports if the table owning the sequence does not exist in the desired state.
+func sequenceOwnerDropped(seq *Sequence, to *schema.Schema) bool {
+	t := seq.Owner.T
+	if t == nil {
+		return false
+	}
+	ts := to
+	if to.Realm != nil && t.Schema != nil {
+		if s, ok := to.Realm.Schema(t.Schema.Name); ok {
+			ts = s
+		}
+	}
+	_, ok := ts.Table(t.Name)
+	return !ok
+}
+
+// sequenceChanged reports if the definition of the sequence was changed.
+func sequenceChanged(from, to *Sequence) bool {
+	return sequenceOptions(from) != sequenceOptions(to) || !sequenceOwnerEqual(from, to) ||
+		sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange
+}
+
+// sequenceOwnerEqual reports if the two sequences are owned by the same column.
+func sequenceOwnerEqual(s1, s2 *Sequence) bool {
+	switch o1, o2 := s1.Owner, s2.Owner; {
+	case o1.T == nil || o2.T == nil:
+		return o1.T == nil && o2.T == nil
+	default:
+		return o1.T.Name == o2.T.Name && o1.C.Name == o2.C.Name &&
+			(o1.T.Schema == nil || o2.T.Schema == nil || o1.T.Schema.Name == o2.T.Schema.Name)
+	}
+}
+
+// seqOptions holds the normalized options of a sequence.
+type seqOptions struct {
+	typ                               string
+	start, increment, min, max, cache int64
+	cycle                             bool
+}
+
+// sequenceOptions returns the sequence options with the zero values replaced with their
+// server defaults. The defaults of the bounds depend on the type and the direction of the
+// sequence, and the default start value is its lower bound, or the upper one if descending.
+func sequenceOptions(s *Sequence) seqOptions {
+	o := seqOptions{typ: sequenceType(s), start: s.Start, increment: s.Increment, cache: s.Cache, cycle: s.Cycle}
+	if o.increment == 0 {
+		o.increment = defaultSeqIncrement
+	}
+	typMin, typMax := int64(math.MinInt64), int64(math.MaxInt64)
+	switch o.typ {
+	case TypeInteger:
+		typMin, typMax = math.MinInt32, math.MaxInt32
+	case TypeSmallInt:
+		typMin, typMax = math.MinInt16, math.MaxInt16
+	}
+	switch {
+	case s.Min != nil:
+		o.min = *s.Min
+	case o.increment > 0:
+		o.min = 1
+	default:
+		o.min = typMin
+	}
+	switch {
+	case s.Max != nil:
+		o.max = *s.Max
+	case o.increment > 0:
+		o.max = typMax
+	default:
+		o.max = -1
+	}
+	switch {
+	case o.start != 0:
+	case o.increment > 0:
+		o.start = o.min
+	default:
+		o.start = o.max
+	}
+	if o.cache == 0 {
+		o.cache = 1
+	}
+	return o
+}
+
+// defaults returns the default options of the sequence. Note, the default bounds are derived
+// from the type and the direction of the sequence, and the default start value from its bounds.
+func (o seqOptions) defaults() seqOptions {
+	d := sequenceOptions(&Sequence{Type: &schema.IntegerType{T: o.typ}, Increment: o.increment})
+	d.typ, d.increment = TypeBigInt, defaultSeqIncrement
+	if d.start = o.min; o.increment < 0 {
+		d.start = o.max
+	}
+	return d
+}
+
+// sequenceType returns the normalized data type of the sequence. Sequences are bigint by default.
+func sequenceType(s *Sequence) string {
+	t, ok := s.Type.(*schema.IntegerType)
+	if !ok {
+		return TypeBigInt
+	}
+	switch strings.ToLower(t.T) {
+	case TypeInteger, TypeInt, TypeInt4:
+		return TypeInteger
+	case TypeSmallInt, TypeInt2:
+		return TypeSmallInt
+	default:
+		return TypeBigInt
+	}
+}
+
+// policiesDiff returns a changeset for migrating the row-level security policies of the schema.
+// Policies defined on dropped tables are not dropped explicitly, as they are dropped by the
+// database along with their tables.
+func (*diff) policiesDiff(from, to *schema.Schema) []schema.Change {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		p1, ok := o1.(*Policy)
+		if !ok {
+			continue
+		}
+		switch p2, ok := schemaPolicy(to, p1.T.Name, p1.Name); {
+		case !ok:
+			if _, ok := to.Table(p1.T.Name); ok {
+				changes = append(changes, &schema.DropObject{O: p1})
+			}
+		case policyChanged(p1, p2):
+			changes = append(changes, &schema.ModifyObject{From: p1, To: p2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if p2, ok := o2.(*Policy); ok {
+			if _, ok := schemaPolicy(from, p2.T.Name, p2.Name); !ok {
+				changes = append(changes, &schema.AddObject{O: p2})
+			}
+		}
+	}
+	return changes
+}
+
+// schemaPolicy returns the policy with the given name that was defined on the table from the schema.
+func schemaPolicy(s *schema.Schema, table, name string) (*Policy, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		p, ok := o.(*Policy)
+		return ok && p.T.Name == table && p.Name == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*Policy), true
+}
+
+// policyChanged reports if the policy definition was changed.
+func policyChanged(from, to *Policy) bool {
+	return policyRecreated(from, to) ||
+		!slices.Equal(policyRoles(from), policyRoles(to)) ||
+		!policyExprEqual(from.Using, to.Using) ||
+		!policyExprEqual(from.Check, to.Check)
+}
+
+// policyRecreated reports if the policy cannot be altered, and must be recreated instead.
+// The type and the command of a policy cannot be changed, and its expressions cannot be removed.
+func policyRecreated(from, to *Policy) bool {
+	return policyAs(from) != policyAs(to) || policyFor(from) != policyFor(to) ||
+		from.Using != "" && to.Using == "" || from.Check != "" && to.Check == ""
+}
+
+// policyAs returns the type of the policy, with its default.
+func policyAs(p *Policy) string {
+	if p.As == "" {
+		return PolicyAsPermissive
+	}
+	return strings.ToUpper(p.As)
+}
+
+// policyFor returns the command of the policy, with its default.
+func policyFor(p *Policy) string {
+	if p.For == "" {
+		return PolicyForAll
+	}
+	return strings.ToUpper(p.For)
+}
+
+// policyRoles returns the sorted roles of the policy, with their default.
+func policyRoles(p *Policy) []string {
+	if len(p.To) == 0 {
+		return []string{"public"}
+	}
+	roles := slices.Clone(p.To)
+	for i, r := range roles {
+		// PUBLIC is a keyword, and not a role name.
+		if strings.EqualFold(r, "public") {
+			roles[i] = "public"
+		}
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// policyExprEqual reports if the two policy expressions are equal.
+func policyExprEqual(x1, x2 string) bool {
+	return x1 == x2 || x1 != "" && x2 != "" && sqlx.MayWrap(x1) == sqlx.MayWrap(x2)
+}
+
+// compositeDiff returns a changeset for migrating the composite types of the schema.
+func (d *diff) compositeDiff(from, to *schema.Schema) ([]schema.Change, error) {
+	var changes []schema.Change
+	for _, o1 := range from.Objects {
+		c1, ok := o1.(*CompositeType)
+		if !ok {
+			continue
+		}
+		c2, ok := schemaComposite(to, c1.T)
+		if !ok {
+			changes = append(changes, &schema.DropObject{O: c1})
+			continue
+		}
+		changed, err := d.compositeChanged(c1, c2)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			changes = append(changes, &schema.ModifyObject{From: c1, To: c2})
+		}
+	}
+	for _, o2 := range to.Objects {
+		if c2, ok := o2.(*CompositeType); ok {
+			if _, ok := schemaComposite(from, c2.T); !ok {
+				changes = append(changes, &schema.AddObject{O: c2})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// schemaComposite returns the composite type with the given name from the schema.
+func schemaComposite(s *schema.Schema, name string) (*CompositeType, bool) {
+	o, ok := s.Object(func(o schema.Object) bool {
+		c, ok := o.(*CompositeType)
+		return ok && c.T == name
+	})
+	if !ok {
+		return nil, false
+	}
+	return o.(*CompositeType), true
+}
+
+// compositeChanged reports if the fields of the composite type were changed.
+// Fields are matched by their names, as their order cannot be altered.
+func (d *diff) compositeChanged(from, to *CompositeType) (bool, error) {
+	if len(from.Fields) != len(to.Fields) {
+		return true, nil
+	}
+	for _, f1 := range from.Fields {
+		i := slices.IndexFunc(to.Fields, func(f2 *schema.Column) bool { return f1.Name == f2.Name })
+		if i == -1 {
+			return true, nil
+		}
+		changed, err := d.fieldChanged(f1, to.Fields[i])
+		if err != nil {
+			return false, err
+		}
+		if changed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fieldChanged reports if the type or the collation of a composite field was changed.
+func (d *diff) fieldChanged(from, to *schema.Column) (bool, error) {
+	changed, err := d.typeChanged(from, to)
+	if err != nil || changed {
+		return changed, err
+	}
+	var c1, c2 schema.Collation
+	sqlx.Has(from.Attrs, &c1)
+	sqlx.Has(to.Attrs, &c2)
+	return c1.V != c2.V, nil
+}
+
 // SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
 func (d *diff) SchemaAttrDiff(from, to *schema.Schema) []schema.Change {
 	var (
@@ -65,6 +635,28 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	if err := d.partitionChanged(from, to); err != nil {
 		return nil, err
 	}
+	changes = append(changes, partitionBoundsDiff(from, to)...)
+	if fromU, toU := unlogged(from.Attrs), unlogged(to.Attrs); fromU != toU {
+		changes = append(changes, &schema.ModifyAttr{
+			From: &Unlogged{V: fromU},
+			To:   &Unlogged{V: toU},
+		})
+	}
+	if fromT, toT := tablespace(from.Attrs), tablespace(to.Attrs); fromT != toT {
+		changes = append(changes, &schema.ModifyAttr{
+			From: &Tablespace{V: fromT},
+			To:   &Tablespace{V: toT},
+		})
+	}
+	var fromR, toR RowSecurity
+	sqlx.Has(from.Attrs, &fromR)
+	sqlx.Has(to.Attrs, &toR)
+	if fromR.Enabled != toR.Enabled || fromR.Enforced != toR.Enforced {
+		changes = append(changes, &schema.ModifyAttr{
+			From: &fromR,
+			To:   &toR,
+		})
+	}
 	change, err := d.tableAttrDiff(from, to)
 	if err != nil {
 		return nil, err
@@ -75,6 +667,23 @@ func (d *diff) TableAttrDiff(from, to *schema.Table, opts *schema.DiffOptions) (
 	})...), nil
 }
 
+// ViewAttrChanges returns the changes for migrating the attributes of a materialized view
+// from one state to the other. Populating a view that was created WITH NO DATA is planned
+// as a refresh, but the opposite direction is not, as it would discard the view content.
+func (*diff) ViewAttrChanges(from, to *schema.View) []schema.Change {
+	if !from.Materialized() || !to.Materialized() {
+		return nil
+	}
+	var changes []schema.Change
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		changes = append(changes, change)
+	}
+	if sqlx.Has(from.Attrs, &WithNoData{}) && !sqlx.Has(to.Attrs, &WithNoData{}) {
+		changes = append(changes, &schema.DropAttr{A: &WithNoData{}})
+	}
+	return changes
+}
+
 // ColumnChange returns the schema changes (if any) for migrating one column to the other.
 func (d *diff) ColumnChange(_ *schema.Table, from, to *schema.Column, _ *schema.DiffOptions) (schema.Change, error) {
 	change := sqlx.CommentChange(from.Attrs, to.Attrs)
@@ -123,6 +732,11 @@ func (d *diff) defaultChanged(from, to *schema.Column) (bool, error) {
 	if !ok1 && !ok2 || trimCast(d1) == trimCast(d2) || quote(d1) == quote(d2) {
 		return false, nil
 	}
+	if f1, ok := funcDefault(d1); ok {
+		if f2, ok := funcDefault(d2); ok && f1 == f2 {
+			return false, nil
+		}
+	}
 	var (
 		_, fromX = from.Default.(*schema.RawExpr)
 		_, toX   = to.Default.(*schema.RawExpr)
@@ -178,6 +792,84 @@ func (*diff) partitionChanged(from, to *schema.Table) error {
 	return nil
 }
 
+// partitionBoundsDiff returns the changes for migrating the partitions of a table. Partitions
+// are managed only if the desired table defines them. Otherwise, partitions that were created
+// outside of Atlas (e.g., by the partition maintenance) are kept as is.
+func partitionBoundsDiff(from, to *schema.Table) []schema.Change {
+	fromB, toB := partitionsOf(from.Attrs), partitionsOf(to.Attrs)
+	if len(toB) == 0 {
+		return nil
+	}
+	var changes []schema.Change
+	for _, b1 := range fromB {
+		i := slices.IndexFunc(toB, func(b2 *PartitionBound) bool { return b1.Name == b2.Name })
+		switch {
+		case i == -1:
+			changes = append(changes, &schema.DropAttr{A: b1})
+		case b1.Default != toB[i].Default || normalBound(b1.Values) != normalBound(toB[i].Values):
+			changes = append(changes, &schema.ModifyAttr{From: b1, To: toB[i]})
+		}
+	}
+	for _, b2 := range toB {
+		if !slices.ContainsFunc(fromB, func(b1 *PartitionBound) bool { return b1.Name == b2.Name }) {
+			changes = append(changes, &schema.AddAttr{A: b2})
+		}
+	}
+	return changes
+}
+
+// partitionsOf returns the partitions defined in the given table attributes.
+func partitionsOf(attrs []schema.Attr) []*PartitionBound {
+	var bs []*PartitionBound
+	for _, a := range attrs {
+		if b, ok := a.(*PartitionBound); ok {
+			bs = append(bs, b)
+		}
+	}
+	return bs
+}
+
+// normalBound returns the normalized form of partition bound values for comparison.
+// Whitespace is removed, and keywords are lowercased, unless they are quoted.
+func normalBound(s string) string {
+	var (
+		b      strings.Builder
+		quoted bool
+	)
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case unicode.IsSpace(r):
+			continue
+		default:
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unlogged reports if the UNLOGGED option is set in the given table attributes.
+func unlogged(attrs []schema.Attr) bool {
+	u := &Unlogged{}
+	return sqlx.Has(attrs, u) && u.V
+}
+
+// defaultTablespace is the tablespace of the template databases,
+// and the default tablespace of objects that do not set one.
+const defaultTablespace = "pg_default"
+
+// tablespace returns the tablespace set in the given attributes,
+// or an empty string in case the default tablespace is used.
+func tablespace(attrs []schema.Attr) string {
+	if t := (Tablespace{}); sqlx.Has(attrs, &t) && t.V != defaultTablespace {
+		return t.V
+	}
+	return ""
+}
+
 // IsGeneratedIndexName reports if the index name was generated by the database.
 func (d *diff) IsGeneratedIndexName(t *schema.Table, idx *schema.Index) bool {
 	names := make([]string, len(idx.Parts))
@@ -200,6 +892,12 @@ func (d *diff) IsGeneratedIndexName(t *schema.Table, idx *schema.Index) bool {
 // IndexAttrChanged reports if the index attributes were changed.
 // The default type is BTREE if no type was specified.
 func (*diff) IndexAttrChanged(from, to []schema.Attr) bool {
+	return indexDefChanged(from, to) || tablespace(from) != tablespace(to)
+}
+
+// indexDefChanged reports if the index definition was changed, which requires
+// the index to be rebuilt. Unlike the index tablespace that can be altered.
+func indexDefChanged(from, to []schema.Attr) bool {
 	t1 := &IndexType{T: IndexTypeBTree}
 	if sqlx.Has(from, t1) {
 		t1.T = strings.ToUpper(t1.T)
@@ -278,8 +976,13 @@ func (*diff) ReferenceChanged(from, to schema.ReferenceOption) bool {
 }
 
 // ForeignKeyAttrChanged reports if any of the foreign-key attributes were changed.
-func (*diff) ForeignKeyAttrChanged(_, _ []schema.Attr) bool {
-	return false
+func (*diff) ForeignKeyAttrChanged(from, to []schema.Attr) bool {
+	var c1, c2 DeleteSetColumns
+	sqlx.Has(from, &c1)
+	sqlx.Has(to, &c2)
+	return !slices.EqualFunc(c1.Columns, c2.Columns, func(c1, c2 *schema.Column) bool {
+		return c1.Name == c2.Name
+	})
 }
 
 // DiffOptions defines PostgreSQL specific schema diffing process.
@@ -561,6 +1264,59 @@ func excludeConst(attrs []schema.Attr) (*Constraint, bool) {
 	return nil, false
 }
 
+// reFuncDefault matches a (possibly schema-qualified) function call, e.g. public.gen_random_uuid().
+var reFuncDefault = regexp.MustCompile(`^(?:("[^"]+"|\w+)\s*\.\s*)?("[^"]+"|\w+)\s*\((.*)\)$`)
+
+// funcAliases maps functions to their equivalent form. uuid_generate_v4
+// (uuid-ossp) and gen_random_uuid (pgcrypto or core) generate random UUIDs.
+var funcAliases = map[string]string{
+	"uuid_generate_v4": "gen_random_uuid",
+}
+
+// funcDefault returns the normalized form of a function-call default value.
+// Schema qualifiers are dropped, unquoted identifiers are lowered, and simple
+// argument lists are reformatted. For example, "public.GEN_RANDOM_UUID( )"
+// and "gen_random_uuid()" are both normalized to "gen_random_uuid()".
+func funcDefault(x string) (string, bool) {
+	m := reFuncDefault.FindStringSubmatch(strings.TrimSpace(trimCast(x)))
+	if m == nil {
+		return "", false
+	}
+	// Ensure the expression is a single function call, and not
+	// an expression like "f(a) + g(b)".
+	depth := 0
+	for _, r := range m[3] {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth < 0 {
+				return "", false
+			}
+		}
+	}
+	name := m[2]
+	if sqlx.IsQuoted(name, '"') {
+		name = name[1 : len(name)-1]
+	} else {
+		name = strings.ToLower(name)
+	}
+	if a, ok := funcAliases[name]; ok {
+		name = a
+	}
+	args := strings.TrimSpace(m[3])
+	// Reformat arguments only if they do not contain
+	// literals, quoted identifiers or nested calls.
+	if !strings.ContainsAny(args, `'"()`) {
+		parts := strings.Split(args, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		args = strings.Join(parts, ", ")
+	}
+	return name + "(" + args + ")", true
+}
+
 func trimCast(s string) string {
 	i := strings.LastIndex(s, "::")
 	if i == -1 {
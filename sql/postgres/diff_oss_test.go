@@ -8,6 +8,7 @@ package postgres
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"ariga.io/atlas/schemahcl"
@@ -80,6 +81,35 @@ func TestDiff_TableDiff(t *testing.T) {
 				}),
 			wantErr: true,
 		},
+		{
+			name: "set unlogged",
+			from: schema.NewTable("cache"),
+			to:   schema.NewTable("cache").AddAttrs(&Unlogged{V: true}),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{From: &Unlogged{V: false}, To: &Unlogged{V: true}},
+			},
+		},
+		{
+			name: "set logged",
+			from: schema.NewTable("cache").AddAttrs(&Unlogged{V: true}),
+			to:   schema.NewTable("cache"),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{From: &Unlogged{V: true}, To: &Unlogged{V: false}},
+			},
+		},
+		{
+			name: "set tablespace",
+			from: schema.NewTable("logs"),
+			to:   schema.NewTable("logs").AddAttrs(&Tablespace{V: "archive"}),
+			wantChanges: []schema.Change{
+				&schema.ModifyAttr{From: &Tablespace{}, To: &Tablespace{V: "archive"}},
+			},
+		},
+		{
+			name: "default tablespace",
+			from: schema.NewTable("logs"),
+			to:   schema.NewTable("logs").AddAttrs(&Tablespace{V: "pg_default"}),
+		},
 		{
 			name: "change partition key column",
 			from: schema.NewTable("logs").
@@ -108,6 +138,37 @@ func TestDiff_TableDiff(t *testing.T) {
 				}),
 			wantErr: true,
 		},
+		{
+			name: "partitions are not managed",
+			from: schema.NewTable("logs").
+				AddAttrs(&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: schema.NewColumn("c")}}}, &PartitionBound{Name: "logs_1", Values: "FROM (1) TO (10)"}),
+			to: schema.NewTable("logs").
+				AddAttrs(&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: schema.NewColumn("c")}}}),
+		},
+		{
+			name: "change partitions",
+			from: schema.NewTable("logs").
+				AddAttrs(
+					&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: schema.NewColumn("c")}}},
+					&PartitionBound{Name: "logs_1", Values: "FROM (1) TO (10)"},
+					&PartitionBound{Name: "logs_2", Values: "FROM (10) TO (20)"},
+					&PartitionBound{Name: "logs_3", Values: "FROM ('a') TO ('b')"},
+					&PartitionBound{Name: "logs_4", Default: true},
+				),
+			to: schema.NewTable("logs").
+				AddAttrs(
+					&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: schema.NewColumn("c")}}},
+					&PartitionBound{Name: "logs_1", Values: "from (1)  to (10)"},
+					&PartitionBound{Name: "logs_3", Values: "FROM ('A') TO ('B')"},
+					&PartitionBound{Name: "logs_4", Default: true},
+					&PartitionBound{Name: "logs_5", Values: "FROM (20) TO (30)"},
+				),
+			wantChanges: []schema.Change{
+				&schema.DropAttr{A: &PartitionBound{Name: "logs_2", Values: "FROM (10) TO (20)"}},
+				&schema.ModifyAttr{From: &PartitionBound{Name: "logs_3", Values: "FROM ('a') TO ('b')"}, To: &PartitionBound{Name: "logs_3", Values: "FROM ('A') TO ('B')"}},
+				&schema.AddAttr{A: &PartitionBound{Name: "logs_5", Values: "FROM (20) TO (30)"}},
+			},
+		},
 		{
 			name: "add check",
 			from: &schema.Table{Name: "t1", Schema: &schema.Schema{Name: "public"}},
@@ -471,6 +532,59 @@ func TestDiff_TableDiff(t *testing.T) {
 				},
 			}
 		}(),
+		func() testcase {
+			fk := func(cols ...string) *schema.Table {
+				ref := schema.NewTable("users").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("tenant_id", "int"), schema.NewIntColumn("id", "int"))
+				t := schema.NewTable("posts").SetSchema(ref.Schema).AddColumns(schema.NewIntColumn("tenant_id", "int"), schema.NewNullIntColumn("author_id", "int"))
+				f := schema.NewForeignKey("author").AddColumns(t.Columns...).SetRefTable(ref).AddRefColumns(ref.Columns...).SetOnDelete(schema.SetNull)
+				if len(cols) > 0 {
+					d := &DeleteSetColumns{}
+					for _, c := range cols {
+						d.Columns = append(d.Columns, schema.NewColumn(c))
+					}
+					f.AddAttrs(d)
+				}
+				return t.AddForeignKeys(f)
+			}
+			from, to := fk(), fk("author_id")
+			return testcase{
+				name: "foreign-key delete columns",
+				from: from,
+				to:   to,
+				wantChanges: []schema.Change{
+					&schema.ModifyForeignKey{
+						From:   from.ForeignKeys[0],
+						To:     to.ForeignKeys[0],
+						Change: schema.ChangeAttr,
+					},
+				},
+			}
+		}(),
+		func() testcase {
+			var (
+				from = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					AddColumns(
+						schema.NewColumn("c1").SetType(&UUIDType{T: "uuid"}).SetDefault(&schema.RawExpr{X: "public.gen_random_uuid()"}),
+						schema.NewColumn("c2").SetType(&UUIDType{T: "uuid"}).SetDefault(&schema.RawExpr{X: "uuid_generate_v4()"}),
+						schema.NewColumn("c3").SetType(&schema.StringType{T: "text"}).SetDefault(&schema.RawExpr{X: "extensions.digest( 'a','sha256' )"}),
+						schema.NewColumn("c4").SetType(&schema.StringType{T: "text"}).SetDefault(&schema.RawExpr{X: "md5(random()::text)"}),
+					)
+				to = schema.NewTable("t1").
+					SetSchema(schema.New("public")).
+					AddColumns(
+						schema.NewColumn("c1").SetType(&UUIDType{T: "uuid"}).SetDefault(&schema.RawExpr{X: "gen_random_uuid()"}),
+						schema.NewColumn("c2").SetType(&UUIDType{T: "uuid"}).SetDefault(&schema.RawExpr{X: "GEN_RANDOM_UUID( )"}),
+						schema.NewColumn("c3").SetType(&schema.StringType{T: "text"}).SetDefault(&schema.RawExpr{X: "digest( 'a','sha256' )"}),
+						schema.NewColumn("c4").SetType(&schema.StringType{T: "text"}).SetDefault(&schema.RawExpr{X: "md5(random()::text)::text"}),
+					)
+			)
+			return testcase{
+				name: "function defaults",
+				from: from,
+				to:   to,
+			}
+		}(),
 	}
 	for _, tt := range tests {
 		db, m, err := sqlmock.New()
@@ -624,6 +738,28 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	})
 }
 
+func TestFuncDefault(t *testing.T) {
+	for x, want := range map[string]string{
+		"gen_random_uuid()":                  "gen_random_uuid()",
+		`"public"."gen_random_uuid"()`:       "gen_random_uuid()",
+		"public.uuid_generate_v4()":          "gen_random_uuid()",
+		"now()::timestamptz":                 "now()",
+		"nextval('seq'::regclass)":           "nextval('seq'::regclass)",
+		"Round( x ,2 )":                      "round(x, 2)",
+		`"MyFunc"()`:                         "MyFunc()",
+		"extensions.digest('a', 'sha256')":   "digest('a', 'sha256')",
+		"coalesce(current_setting('a'), '')": "coalesce(current_setting('a'), '')",
+	} {
+		got, ok := funcDefault(x)
+		require.True(t, ok, x)
+		require.Equal(t, want, got, x)
+	}
+	for _, x := range []string{"'text'", "1", "f(1) + g(2)", "(now())"} {
+		_, ok := funcDefault(x)
+		require.False(t, ok, x)
+	}
+}
+
 func TestDefaultDiff(t *testing.T) {
 	changes, err := DefaultDiff.SchemaDiff(
 		schema.New("public").
@@ -662,3 +798,240 @@ concurrent_index {
 	require.Equal(t, `CREATE INDEX CONCURRENTLY "users_pkey_new" ON "public"."users" ("id")`, plan.Changes[1].Cmd)
 	require.Equal(t, `DROP INDEX CONCURRENTLY "public"."users_pkey_new"`, plan.Changes[1].Reverse)
 }
+
+func TestDiff_RealmObjectDiff(t *testing.T) {
+	var (
+		d    = &diff{}
+		from = schema.NewRealm()
+		to   = schema.NewRealm()
+	)
+	from.Objects = []schema.Object{
+		&Language{Name: "plperl", Trusted: true},
+		&Language{Name: "plpython3u"},
+		&Cast{Source: "text", Target: "public.mood", Method: CastMethodFunc, Func: "text_to_mood(text)"},
+		&Cast{Source: "int4", Target: "public.mood", Method: CastMethodBinary, Context: CastContextImplicit},
+	}
+	to.Objects = []schema.Object{
+		&Language{Name: "plperl", Attrs: []schema.Attr{&schema.Comment{Text: "perl"}}},
+		&Cast{Source: "text", Target: "public.mood", Method: CastMethodFunc, Func: "public.text_to_mood(text)", Context: CastContextExplicit},
+		&Cast{Source: "integer", Target: "public.mood", Method: CastMethodInOut, Context: CastContextImplicit},
+		&Cast{Source: "public.mood", Target: "text", Method: CastMethodInOut},
+	}
+	changes, err := d.RealmObjectDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[0], To: to.Objects[0]},
+		&schema.DropObject{O: from.Objects[1]},
+		&schema.ModifyObject{From: from.Objects[3], To: to.Objects[2]},
+		&schema.AddObject{O: to.Objects[3]},
+	}, changes)
+
+	// Database names are not compared.
+	from.Objects = []schema.Object{
+		&Database{Name: "app", Settings: []*DatabaseSetting{{Name: "timezone", Value: "UTC"}}},
+	}
+	to.Objects = []schema.Object{
+		&Database{Name: "dev", Settings: []*DatabaseSetting{{Name: "TimeZone", Value: "UTC"}}},
+	}
+	changes, err = d.RealmObjectDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+	to.Objects[0].(*Database).Attrs = []schema.Attr{&schema.Comment{Text: "app db"}}
+	changes, err = d.RealmObjectDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyObject{From: from.Objects[0], To: to.Objects[0]}}, changes)
+	to.Objects[0].(*Database).Attrs = nil
+	to.Objects[0].(*Database).Settings[0].Value = "Asia/Jerusalem"
+	changes, err = d.RealmObjectDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.ModifyObject{From: from.Objects[0], To: to.Objects[0]}}, changes)
+	changes, err = d.RealmObjectDiff(from, schema.NewRealm())
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.DropObject{O: from.Objects[0]}}, changes)
+	changes, err = d.RealmObjectDiff(schema.NewRealm(), to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{&schema.AddObject{O: to.Objects[0]}}, changes)
+}
+
+func TestDiff_SchemaObjectDiff_Composite(t *testing.T) {
+	var (
+		d    = &diff{conn: &conn{version: 130000}}
+		from = schema.New("public")
+		to   = schema.New("public")
+	)
+	from.AddObjects(
+		&CompositeType{T: "c1", Schema: from, Fields: []*schema.Column{schema.NewStringColumn("a", "text"), schema.NewIntColumn("b", "int")}},
+		&CompositeType{T: "c2", Schema: from, Fields: []*schema.Column{schema.NewStringColumn("a", "text")}},
+		&CompositeType{T: "c3", Schema: from, Fields: []*schema.Column{schema.NewStringColumn("a", "text")}},
+		&CompositeType{T: "c4", Schema: from, Fields: []*schema.Column{schema.NewStringColumn("a", "text")}},
+	)
+	to.AddObjects(
+		// Fields are compared regardless of their order.
+		&CompositeType{T: "c1", Schema: to, Fields: []*schema.Column{schema.NewIntColumn("b", "int"), schema.NewStringColumn("a", "text")}},
+		&CompositeType{T: "c2", Schema: to, Fields: []*schema.Column{schema.NewStringColumn("a", "text").SetCollation("C")}},
+		&CompositeType{T: "c3", Schema: to, Fields: []*schema.Column{schema.NewStringColumn("a", "varchar")}},
+		&CompositeType{T: "c5", Schema: to, Fields: []*schema.Column{schema.NewStringColumn("a", "text")}},
+	)
+	changes, err := d.SchemaObjectDiff(from, to, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]},
+		&schema.ModifyObject{From: from.Objects[2], To: to.Objects[2]},
+		&schema.DropObject{O: from.Objects[3]},
+		&schema.AddObject{O: to.Objects[3]},
+	}, changes)
+}
+
+func TestDiff_SchemaObjectDiff_Statistics(t *testing.T) {
+	var (
+		d    = &diff{conn: &conn{version: 130000}}
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewStringColumn("a", "text"), schema.NewStringColumn("b", "text")),
+			schema.NewTable("pets").AddColumns(schema.NewStringColumn("a", "text"), schema.NewStringColumn("b", "text")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewStringColumn("a", "text"), schema.NewStringColumn("b", "text")),
+		)
+		fu, fp, tu = from.Tables[0], from.Tables[1], to.Tables[0]
+	)
+	from.AddObjects(
+		&Statistics{Name: "s1", Schema: from, T: fu, Columns: fu.Columns},
+		&Statistics{Name: "s2", Schema: from, T: fu, Columns: fu.Columns, Kinds: []string{StatisticsKindNDistinct}},
+		&Statistics{Name: "s3", Schema: from, T: fu, Columns: fu.Columns},
+		// Dropped along with its table.
+		&Statistics{Name: "s4", Schema: from, T: fp, Columns: fp.Columns},
+	)
+	to.AddObjects(
+		// Columns and kinds are compared regardless of their order.
+		&Statistics{Name: "s1", Schema: to, T: tu, Columns: []*schema.Column{tu.Columns[1], tu.Columns[0]}, Kinds: []string{StatisticsKindMCV, StatisticsKindNDistinct, StatisticsKindDependencies}},
+		&Statistics{Name: "s2", Schema: to, T: tu, Columns: tu.Columns, Kinds: []string{StatisticsKindNDistinct, StatisticsKindMCV}},
+		&Statistics{Name: "s5", Schema: to, T: tu, Columns: tu.Columns[:1], Exprs: []string{"lower(b)"}},
+	)
+	changes, err := d.SchemaObjectDiff(from, to, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]},
+		&schema.DropObject{O: from.Objects[2]},
+		&schema.AddObject{O: to.Objects[2]},
+	}, changes)
+}
+
+func TestDiff_SchemaObjectDiff_Policies(t *testing.T) {
+	var (
+		d    = &diff{conn: &conn{version: 130000}}
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("a", "int")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("a", "int")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("a", "int")),
+		)
+		fu, fp, tu = from.Tables[0], from.Tables[1], to.Tables[0]
+	)
+	from.AddObjects(
+		&Policy{Name: "p1", T: fu, To: []string{"b", "a"}, Using: "(a > 0)"},
+		&Policy{Name: "p2", T: fu, Using: "(a > 0)"},
+		&Policy{Name: "p3", T: fu, For: PolicyForSelect, Using: "(a > 0)"},
+		// Dropped along with its table.
+		&Policy{Name: "p1", T: fp, Using: "(a > 0)"},
+	)
+	to.AddObjects(
+		// Defaults and roles order are ignored.
+		&Policy{Name: "p1", T: tu, As: PolicyAsPermissive, For: PolicyForAll, To: []string{"a", "b"}, Using: "a > 0"},
+		&Policy{Name: "p2", T: tu, To: []string{"PUBLIC"}, Using: "(a > 1)"},
+		&Policy{Name: "p4", T: tu, Check: "(a > 0)"},
+	)
+	changes, err := d.SchemaObjectDiff(from, to, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyObject{From: from.Objects[1], To: to.Objects[1]},
+		&schema.DropObject{O: from.Objects[2]},
+		&schema.AddObject{O: to.Objects[2]},
+	}, changes)
+
+	// Row-level security options are diffed as table attributes.
+	changes, err = d.TableAttrDiff(
+		schema.NewTable("users").AddAttrs(&RowSecurity{Enabled: true}),
+		schema.NewTable("users").AddAttrs(&RowSecurity{Enabled: true, Enforced: true}),
+		&schema.DiffOptions{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyAttr{From: &RowSecurity{Enabled: true}, To: &RowSecurity{Enabled: true, Enforced: true}},
+	}, changes)
+	changes, err = d.TableAttrDiff(schema.NewTable("users"), schema.NewTable("users").AddAttrs(&RowSecurity{}), &schema.DiffOptions{})
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestDiff_SchemaObjectDiff_Sequences(t *testing.T) {
+	var (
+		d    = &diff{conn: &conn{version: 150000}}
+		from = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")),
+			schema.NewTable("pets").AddColumns(schema.NewIntColumn("id", "bigint")),
+		)
+		to = schema.New("public").AddTables(
+			schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")),
+		)
+		one, maxV = int64(1), int64(math.MaxInt64)
+		s1        = &Sequence{Name: "s1", Schema: from, Type: &schema.IntegerType{T: TypeBigInt}, Start: 1, Increment: 1, Min: &one, Max: &maxV, Cache: 1}
+		s2        = &Sequence{Name: "s2", Schema: from}
+		s3        = &Sequence{Name: "s3", Schema: from}
+		s4        = &Sequence{Name: "s4", Schema: from}
+		s5        = &Sequence{Name: "s5", Schema: to}
+	)
+	// Dropped along with its owner table.
+	s2.Owner.T, s2.Owner.C = from.Tables[1], from.Tables[1].Columns[0]
+	from.AddObjects(s1, s2, s3, s4)
+	to.AddObjects(
+		// Defaults are ignored.
+		&Sequence{Name: "s1", Schema: to},
+		&Sequence{Name: "s4", Schema: to, Start: 10},
+		s5,
+	)
+	changes, err := d.SchemaObjectDiff(from, to, nil)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.DropObject{O: s3},
+		&schema.ModifyObject{From: s4, To: to.Objects[1]},
+		&schema.AddObject{O: s5},
+	}, changes)
+
+	// Owner and type changes.
+	s6 := &Sequence{Name: "s4", Schema: to, Type: &schema.IntegerType{T: TypeInt}}
+	require.True(t, sequenceChanged(s4, s6))
+	require.False(t, sequenceChanged(&Sequence{Type: &schema.IntegerType{T: TypeInt4}}, &Sequence{Type: &schema.IntegerType{T: TypeInteger}}))
+	s6 = &Sequence{Name: "s4", Schema: to}
+	s6.Owner.T, s6.Owner.C = to.Tables[0], to.Tables[0].Columns[0]
+	require.True(t, sequenceChanged(s4, s6))
+	// Descending sequences start from their upper bound.
+	minusOne := int64(-1)
+	require.False(t, sequenceChanged(&Sequence{Increment: -1}, &Sequence{Increment: -1, Start: -1, Max: &minusOne}))
+}
+
+func TestDiff_MaterializedViews(t *testing.T) {
+	var (
+		from = schema.New("public").AddViews(
+			schema.NewMaterializedView("m1", "SELECT 1").AddAttrs(&WithNoData{}),
+			schema.NewMaterializedView("m2", "SELECT 2"),
+			schema.NewMaterializedView("m3", "SELECT 3"),
+		)
+		to = schema.New("public").AddViews(
+			schema.NewMaterializedView("m1", "SELECT 1").SetComment("populated"),
+			// Views are not emptied by the migration.
+			schema.NewMaterializedView("m2", "SELECT 2").AddAttrs(&WithNoData{}),
+			schema.NewMaterializedView("m4", "SELECT 4"),
+		)
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifyView{From: from.Views[0], To: to.Views[0], Changes: []schema.Change{
+			&schema.AddAttr{A: &schema.Comment{Text: "populated"}},
+			&schema.DropAttr{A: &WithNoData{}},
+		}},
+		&schema.DropView{V: from.Views[2]},
+		&schema.AddView{V: to.Views[2]},
+	}, changes)
+}
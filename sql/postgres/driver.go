@@ -18,6 +18,7 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/types"
 )
 
 type (
@@ -47,6 +48,7 @@ var _ interface {
 	migrate.Snapshoter
 	migrate.StmtScanner
 	migrate.CleanChecker
+	migrate.StatsRefresher
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
 
@@ -62,6 +64,7 @@ func init() {
 		sqlclient.RegisterCodec(codec, codec),
 		sqlclient.RegisterURLParser(parser{}),
 	)
+	types.Register(DriverName, (*Driver)(nil))
 }
 
 func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
@@ -140,7 +143,13 @@ func (d *Driver) dev() *sqlx.DevDriver {
 
 // NormalizeRealm returns the normal representation of the given database.
 func (d *Driver) NormalizeRealm(ctx context.Context, r *schema.Realm) (*schema.Realm, error) {
-	return d.dev().NormalizeRealm(ctx, r)
+	r, restore := excludeDevObjects(r)
+	nr, err := d.dev().NormalizeRealm(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	restore(nr)
+	return nr, nil
 }
 
 // NormalizeSchema returns the normal representation of the given database.
@@ -333,6 +342,91 @@ func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error
 	return nil
 }
 
+// RefreshStats implements migrate.StatsRefresher.
+func (d *Driver) RefreshStats(ctx context.Context, tables []*schema.Table) error {
+	for _, t := range tables {
+		b := d.StmtBuilder(migrate.PlanOptions{}).P("ANALYZE").Table(t)
+		if _, err := d.ExecContext(ctx, b.String()); err != nil {
+			return fmt.Errorf("postgres: analyze table %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// TableSizes implements migrate.TableSizer. The sizes are read from the planner
+// statistics, and tables that were never analyzed are omitted from the result.
+func (d *Driver) TableSizes(ctx context.Context, tables []*schema.Table) (map[*schema.Table]int64, error) {
+	sizes := make(map[*schema.Table]int64, len(tables))
+	for _, t := range tables {
+		var s string
+		if t.Schema != nil {
+			s = t.Schema.Name
+		}
+		rows, err := d.QueryContext(ctx, tableSizeQuery, s, t.Name)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: querying size of table %q: %w", t.Name, err)
+		}
+		var n int64
+		if err := sqlx.ScanOne(rows, &n); err != nil {
+			return nil, fmt.Errorf("postgres: scanning size of table %q: %w", t.Name, err)
+		}
+		if n >= 0 {
+			sizes[t] = n
+		}
+	}
+	return sizes, nil
+}
+
+// Query to estimate the number of rows in a table. Returns -1 if the table does not exist or was never analyzed.
+const tableSizeQuery = `SELECT COALESCE(MAX(c.reltuples), -1)::bigint FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2`
+
+// MissingPrivileges implements migrate.PrivilegeChecker. Creating objects requires the CREATE
+// privilege on their schema (or the database, for schemas), and altering or dropping objects
+// requires membership in the role that owns them. Objects that do not exist are skipped.
+func (d *Driver) MissingPrivileges(ctx context.Context, privs []*migrate.Privilege) ([]*migrate.Privilege, error) {
+	var missing []*migrate.Privilege
+	for _, p := range privs {
+		query, args := privilegeQuery(p)
+		rows, err := d.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: querying privilege %s: %w", p, err)
+		}
+		granted, err := sqlx.ScanNullBool(rows)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: scanning privilege %s: %w", p, err)
+		}
+		if granted.Valid && !granted.Bool {
+			missing = append(missing, p)
+		}
+	}
+	return missing, nil
+}
+
+// privilegeQuery returns the query and its arguments used to check the given privilege.
+func privilegeQuery(p *migrate.Privilege) (string, []any) {
+	switch {
+	case p.Type == "schema" && p.Action == migrate.PrivilegeCreate:
+		return privCreateSchemaQuery, nil
+	case p.Type == "schema":
+		return privSchemaOwnerQuery, []any{p.Schema}
+	case p.Action == migrate.PrivilegeCreate:
+		return privCreateQuery, []any{p.Schema}
+	case p.Type == "function" || p.Type == "procedure":
+		return privFuncOwnerQuery, []any{p.Schema, p.Name}
+	default:
+		return privRelOwnerQuery, []any{p.Schema, p.Name}
+	}
+}
+
+// Queries to check the privileges of the current user. NULL is returned if the checked object does not exist.
+const (
+	privCreateSchemaQuery = `SELECT has_database_privilege(current_database(), 'CREATE')`
+	privSchemaOwnerQuery  = `SELECT bool_and(pg_has_role(nspowner, 'USAGE')) FROM pg_catalog.pg_namespace WHERE nspname = $1`
+	privCreateQuery       = `SELECT bool_and(has_schema_privilege(oid, 'CREATE')) FROM pg_catalog.pg_namespace WHERE nspname = COALESCE(NULLIF($1, ''), current_schema())`
+	privRelOwnerQuery     = `SELECT bool_and(pg_has_role(c.relowner, 'USAGE')) FROM pg_catalog.pg_class c JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND c.relname = $2`
+	privFuncOwnerQuery    = `SELECT bool_and(pg_has_role(p.proowner, 'USAGE')) FROM pg_catalog.pg_proc p JOIN pg_catalog.pg_namespace n ON n.oid = p.pronamespace WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema()) AND p.proname = $2`
+)
+
 // Version returns the version of the connected database.
 func (d *Driver) Version() string {
 	return strconv.Itoa(d.conn.version)
@@ -415,6 +509,32 @@ func (c *conn) supportsIndexNullsDistinct() bool {
 	return c.version >= 15_00_00
 }
 
+// supportsFKDeleteColumns reports if the server supports column
+// lists in the ON DELETE SET NULL and SET DEFAULT actions.
+func (c *conn) supportsFKDeleteColumns() bool {
+	return c.version >= 15_00_00
+}
+
+// supportsEnumAddValueTx reports if ALTER TYPE ... ADD VALUE
+// can be executed inside a transaction block.
+func (c *conn) supportsEnumAddValueTx() bool {
+	return c.version >= 12_00_00
+}
+
+// supportsStatisticsExprs reports if the server supports
+// creating extended statistics on expressions.
+func (c *conn) supportsStatisticsExprs() bool {
+	return c.version >= 14_00_00
+}
+
+// statisticsKinds returns the extended statistics kinds supported by the server.
+func (c *conn) statisticsKinds() []string {
+	if c.version < 12_00_00 {
+		return []string{StatisticsKindNDistinct, StatisticsKindDependencies}
+	}
+	return []string{StatisticsKindNDistinct, StatisticsKindDependencies, StatisticsKindMCV}
+}
+
 type parser struct{}
 
 // ParseURL implements the sqlclient.URLParser interface.
@@ -593,3 +713,39 @@ const (
 	PartitionTypeList  = "LIST"
 	PartitionTypeHash  = "HASH"
 )
+
+// List of cast methods.
+const (
+	CastMethodFunc   = "FUNCTION" // WITH FUNCTION.
+	CastMethodBinary = "BINARY"   // WITHOUT FUNCTION.
+	CastMethodInOut  = "INOUT"    // WITH INOUT.
+)
+
+// List of cast contexts.
+const (
+	CastContextExplicit   = "EXPLICIT"
+	CastContextAssignment = "ASSIGNMENT"
+	CastContextImplicit   = "IMPLICIT"
+)
+
+// List of extended statistics kinds.
+const (
+	StatisticsKindNDistinct    = "NDISTINCT"
+	StatisticsKindDependencies = "DEPENDENCIES"
+	StatisticsKindMCV          = "MCV" // PostgreSQL 12 and above.
+)
+
+// List of row-level security policy types.
+const (
+	PolicyAsPermissive  = "PERMISSIVE"
+	PolicyAsRestrictive = "RESTRICTIVE"
+)
+
+// List of commands a row-level security policy applies to.
+const (
+	PolicyForAll    = "ALL"
+	PolicyForSelect = "SELECT"
+	PolicyForInsert = "INSERT"
+	PolicyForUpdate = "UPDATE"
+	PolicyForDelete = "DELETE"
+)
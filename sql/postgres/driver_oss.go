@@ -9,6 +9,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"ariga.io/atlas/schemahcl"
 	"ariga.io/atlas/sql/internal/specutil"
@@ -25,8 +26,9 @@ var (
 		View:  viewSpec,
 	}
 	scanFuncs = &specutil.ScanFuncs{
-		Table: convertTable,
-		View:  convertView,
+		Table:      convertTable,
+		View:       convertView,
+		ForeignKey: convertForeignKey,
 	}
 )
 
@@ -53,28 +55,30 @@ func (s *state) alterTableAttr(*sqlx.Builder, *schema.ModifyAttr) {
 	// unimplemented.
 }
 
-func realmObjectsSpec(*doc, *schema.Realm) error {
-	return nil // unimplemented.
-}
-
 func triggersSpec([]*schema.Trigger, *doc) error {
 	return nil // unimplemented.
 }
 
-func (*inspect) inspectViews(context.Context, *schema.Realm, *schema.InspectOptions) error {
-	return nil // unimplemented.
-}
-
 func (*inspect) inspectFuncs(context.Context, *schema.Realm, *schema.InspectOptions) error {
 	return nil // unimplemented.
 }
 
-func (*inspect) inspectTypes(context.Context, *schema.Realm, *schema.InspectOptions) error {
-	return nil // unimplemented.
+func (i *inspect) inspectTypes(ctx context.Context, r *schema.Realm, _ *schema.InspectOptions) error {
+	// Composite types are not inspected on CockroachDB.
+	if i.crdb {
+		return nil
+	}
+	return i.inspectComposites(ctx, r)
 }
 
-func (*inspect) inspectObjects(context.Context, *schema.Realm, *schema.InspectOptions) error {
-	return nil // unimplemented.
+func (i *inspect) inspectObjects(ctx context.Context, r *schema.Realm, _ *schema.InspectOptions) error {
+	if err := i.inspectStatistics(ctx, r); err != nil {
+		return err
+	}
+	if err := i.inspectPolicies(ctx, r); err != nil {
+		return err
+	}
+	return i.inspectSequences(ctx, r)
 }
 
 func (*inspect) inspectTriggers(context.Context, *schema.Realm, *schema.InspectOptions) error {
@@ -85,22 +89,6 @@ func (*inspect) inspectDeps(context.Context, *schema.Realm, *schema.InspectOptio
 	return nil // unimplemented.
 }
 
-func (*inspect) inspectRealmObjects(context.Context, *schema.Realm, *schema.InspectOptions) error {
-	return nil // unimplemented.
-}
-
-func (*state) addView(*schema.AddView) error {
-	return nil // unimplemented.
-}
-
-func (*state) dropView(*schema.DropView) error {
-	return nil // unimplemented.
-}
-
-func (*state) modifyView(*schema.ModifyView) error {
-	return nil // unimplemented.
-}
-
 func (*state) renameView(*schema.RenameView) {
 	// unimplemented.
 }
@@ -137,6 +125,35 @@ func (s *state) renameProc(*schema.RenameProc) error {
 	return nil // unimplemented.
 }
 
+// excludeDevObjects excludes the realm objects that cannot be applied on the dev database,
+// and returns a function for restoring them on the normalized realm. Database settings and
+// comments are bound to the database name, and therefore, are kept as defined.
+func excludeDevObjects(r *schema.Realm) (*schema.Realm, func(*schema.Realm)) {
+	var (
+		dbs  []schema.Object
+		objs = make([]schema.Object, 0, len(r.Objects))
+	)
+	for _, o := range r.Objects {
+		if _, ok := o.(*Database); ok {
+			dbs = append(dbs, o)
+		} else {
+			objs = append(objs, o)
+		}
+	}
+	if len(dbs) > 0 {
+		r1 := *r
+		r1.Objects = objs
+		r = &r1
+	}
+	return r, func(nr *schema.Realm) {
+		nr.Objects = slices.DeleteFunc(nr.Objects, func(o schema.Object) bool {
+			_, ok := o.(*Database)
+			return ok
+		})
+		nr.Objects = append(nr.Objects, dbs...)
+	}
+}
+
 func (s *state) addObject(add *schema.AddObject) error {
 	switch o := add.O.(type) {
 	case *schema.EnumType:
@@ -147,6 +164,20 @@ func (s *state) addObject(add *schema.AddObject) error {
 			Reverse: drop,
 			Comment: fmt.Sprintf("create enum type %q", o.T),
 		})
+	case *Language:
+		s.addLanguage(add, o)
+	case *Cast:
+		s.addCast(add, o)
+	case *Database:
+		s.addDatabase(add, o)
+	case *Statistics:
+		s.addStatistics(add, o)
+	case *Policy:
+		s.addPolicy(add, o)
+	case *Sequence:
+		s.addSequence(add, o)
+	case *CompositeType:
+		return s.addComposite(add, o)
 	default:
 		// unsupported object type.
 	}
@@ -163,6 +194,20 @@ func (s *state) dropObject(drop *schema.DropObject) error {
 			Reverse: create,
 			Comment: fmt.Sprintf("drop enum type %q", o.T),
 		})
+	case *Language:
+		s.dropLanguage(drop, o)
+	case *Cast:
+		s.dropCast(drop, o)
+	case *Database:
+		s.dropDatabase(drop, o)
+	case *Statistics:
+		s.dropStatistics(drop, o)
+	case *Policy:
+		s.dropPolicy(drop, o)
+	case *Sequence:
+		s.dropSequence(drop, o)
+	case *CompositeType:
+		return s.dropComposite(drop, o)
 	default:
 		// unsupported object type.
 	}
@@ -170,8 +215,23 @@ func (s *state) dropObject(drop *schema.DropObject) error {
 }
 
 func (s *state) modifyObject(modify *schema.ModifyObject) error {
-	if _, ok := modify.From.(*schema.EnumType); ok {
+	switch modify.From.(type) {
+	case *schema.EnumType:
 		return s.alterEnum(modify)
+	case *Language:
+		return s.alterLanguage(modify)
+	case *Cast:
+		return s.alterCast(modify)
+	case *Database:
+		return s.alterDatabase(modify)
+	case *Statistics:
+		return s.alterStatistics(modify)
+	case *Policy:
+		return s.alterPolicy(modify)
+	case *Sequence:
+		return s.alterSequence(modify)
+	case *CompositeType:
+		return s.alterComposite(modify)
 	}
 	return nil // unimplemented.
 }
@@ -192,19 +252,9 @@ func (*state) modifyTrigger(*schema.ModifyTrigger) error {
 	return nil // unimplemented.
 }
 
-func (*diff) ViewAttrChanges(_, _ *schema.View) []schema.Change {
-	return nil // unimplemented.
-}
-
-// RealmObjectDiff returns a changeset for migrating realm (database) objects
-// from one state to the other. For example, adding extensions or users.
-func (*diff) RealmObjectDiff(_, _ *schema.Realm) ([]schema.Change, error) {
-	return nil, nil // unimplemented.
-}
-
 // SchemaObjectDiff returns a changeset for migrating schema objects from
 // one state to the other.
-func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
+func (d *diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([]schema.Change, error) {
 	var changes []schema.Change
 	// Drop or modify enums.
 	for _, o1 := range from.Objects {
@@ -237,7 +287,14 @@ func (*diff) SchemaObjectDiff(from, to *schema.Schema, _ *schema.DiffOptions) ([
 			changes = append(changes, &schema.AddObject{O: e1})
 		}
 	}
-	return changes, nil
+	composites, err := d.compositeDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, composites...)
+	changes = append(changes, d.statisticsDiff(from, to)...)
+	changes = append(changes, d.policiesDiff(from, to)...)
+	return append(changes, d.sequencesDiff(from, to)...), nil
 }
 
 func verifyChanges(context.Context, []schema.Change) error {
@@ -258,20 +315,6 @@ func convertAggregate(d *doc, _ *schema.Realm) error {
 	return nil
 }
 
-func convertSequences(_ []*sqlspec.Table, seqs []*sqlspec.Sequence, _ *schema.Realm) error {
-	if len(seqs) > 0 {
-		return fmt.Errorf("postgres: sequences are not supported by this version. Use: https://atlasgo.io/getting-started")
-	}
-	return nil
-}
-
-func convertPolicies(_ []*sqlspec.Table, ps []*policy, _ *schema.Realm) error {
-	if len(ps) > 0 {
-		return fmt.Errorf("postgres: policies are not supported by this version. Use: https://atlasgo.io/getting-started")
-	}
-	return nil
-}
-
 func convertExtensions(exs []*extension, _ *schema.Realm) error {
 	if len(exs) > 0 {
 		return fmt.Errorf("postgres: extensions are not supported by this version. Use: https://atlasgo.io/getting-started")
@@ -297,21 +340,42 @@ func schemasObjectSpec(*doc, ...*schema.Schema) error {
 // objectSpec converts from a concrete schema objects into specs.
 func objectSpec(d *doc, spec *specutil.SchemaSpec, s *schema.Schema) error {
 	for _, o := range s.Objects {
-		if e, ok := o.(*schema.EnumType); ok {
+		switch o := o.(type) {
+		case *schema.EnumType:
 			d.Enums = append(d.Enums, &enum{
-				Name:   e.T,
-				Values: e.Values,
+				Name:   o.T,
+				Values: o.Values,
 				Schema: specutil.SchemaRef(spec.Schema.Name),
 			})
+		case *CompositeType:
+			c, err := compositeSpec(o, spec.Schema)
+			if err != nil {
+				return err
+			}
+			d.Composites = append(d.Composites, c)
+		case *Statistics:
+			st, err := statisticsSpec(o, spec.Schema)
+			if err != nil {
+				return err
+			}
+			d.Statistics = append(d.Statistics, st)
+		case *Policy:
+			d.Policies = append(d.Policies, policySpec(o))
+		case *Sequence:
+			seq, err := sequenceSpec(o, spec.Schema)
+			if err != nil {
+				return err
+			}
+			d.Sequences = append(d.Sequences, seq)
 		}
 	}
 	return nil
 }
 
-// convertEnums converts possibly referenced column types (like enums) to
-// an actual schema.Type and sets it on the correct schema.Column.
+// convertTypes converts possibly referenced column types (like enums and composite
+// types) to an actual schema.Type and sets it on the correct schema.Column.
 func convertTypes(d *doc, r *schema.Realm) error {
-	if len(d.Enums) == 0 {
+	if len(d.Enums) == 0 && len(d.Composites) == 0 {
 		return nil
 	}
 	byName := make(map[string]*schema.EnumType)
@@ -331,9 +395,13 @@ func convertTypes(d *doc, r *schema.Realm) error {
 		es.AddObjects(e1)
 		byName[e.Name] = e1
 	}
+	composites, err := convertComposites(d.Composites, byName, r)
+	if err != nil {
+		return err
+	}
 	for _, t := range d.Tables {
 		for _, c := range t.Columns {
-			var enum *schema.EnumType
+			var typ schema.Type
 			switch {
 			case c.Type.IsRefTo("enum"):
 				n, err := enumName(c.Type)
@@ -344,13 +412,27 @@ func convertTypes(d *doc, r *schema.Realm) error {
 				if !ok {
 					return fmt.Errorf("enum %q was not found in realm", n)
 				}
-				enum = e
+				typ = e
+			case c.Type.IsRefTo("composite"):
+				n, err := compositeName(c.Type)
+				if err != nil {
+					return err
+				}
+				ct, ok := composites[n]
+				if !ok {
+					return fmt.Errorf("composite %q was not found in realm", n)
+				}
+				typ = ct
 			default:
 				if n, ok := arrayType(c.Type.T); ok {
-					enum = byName[n]
+					if e, ok := byName[n]; ok {
+						typ = e
+					} else if ct, ok := composites[n]; ok {
+						typ = ct
+					}
 				}
 			}
-			if enum == nil {
+			if typ == nil {
 				continue
 			}
 			schemaT, err := specutil.SchemaName(t.Schema)
@@ -371,9 +453,9 @@ func convertTypes(d *doc, r *schema.Realm) error {
 			}
 			switch t := cc.Type.Type.(type) {
 			case *ArrayType:
-				t.Type = enum
+				t.Type = typ
 			default:
-				cc.Type.Type = enum
+				cc.Type.Type = typ
 			}
 		}
 	}
@@ -413,8 +495,8 @@ func excludeSpec(*sqlspec.Table, *sqlspec.Index, *schema.Index, *Constraint) err
 }
 
 const (
-	// Query to list tables information.
-	// Note, 'attrs' are not supported in this version.
+	// Query to list tables information. Note, 'attrs'
+	// holds only the owner and the ACL in this version.
 	tablesQuery = `
 SELECT
 	t3.oid,
@@ -424,13 +506,16 @@ SELECT
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
 	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
-	'{}' AS attrs
+	t3.relpersistence = 'u' AS unlogged,
+	t6.spcname AS tablespace,
+	json_build_object('owner', pg_catalog.pg_get_userbyid(t3.relowner), 'acl', t3.relacl) AS attrs
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
 	JOIN pg_catalog.pg_class AS t3 ON t3.relnamespace = t2.oid AND t3.relname = t1.table_name
 	LEFT JOIN pg_catalog.pg_partitioned_table AS t4 ON t4.partrelid = t3.oid
 	LEFT JOIN pg_depend AS t5 ON t5.classid = 'pg_catalog.pg_class'::regclass::oid AND t5.objid = t3.oid AND t5.deptype = 'e'
+	LEFT JOIN pg_catalog.pg_tablespace AS t6 ON t6.oid = t3.reltablespace
 WHERE
 	t1.table_type = 'BASE TABLE'
 	AND NOT COALESCE(t3.relispartition, false)
@@ -439,8 +524,8 @@ WHERE
 ORDER BY
 	t1.table_schema, t1.table_name
 `
-	// Query to list tables by their names.
-	// Note, 'attrs' are not supported in this version.
+	// Query to list tables by their names. Note, 'attrs'
+	// holds only the owner and the ACL in this version.
 	tablesQueryArgs = `
 SELECT
 	t3.oid,
@@ -450,13 +535,16 @@ SELECT
 	t4.partattrs AS partition_attrs,
 	t4.partstrat AS partition_strategy,
 	pg_get_expr(t4.partexprs, t4.partrelid) AS partition_exprs,
-	'{}' AS attrs
+	t3.relpersistence = 'u' AS unlogged,
+	t6.spcname AS tablespace,
+	json_build_object('owner', pg_catalog.pg_get_userbyid(t3.relowner), 'acl', t3.relacl) AS attrs
 FROM
 	INFORMATION_SCHEMA.TABLES AS t1
 	JOIN pg_catalog.pg_namespace AS t2 ON t2.nspname = t1.table_schema
 	JOIN pg_catalog.pg_class AS t3 ON t3.relnamespace = t2.oid AND t3.relname = t1.table_name
 	LEFT JOIN pg_catalog.pg_partitioned_table AS t4 ON t4.partrelid = t3.oid
 	LEFT JOIN pg_depend AS t5 ON t5.classid = 'pg_catalog.pg_class'::regclass::oid AND t5.objid = t3.oid AND t5.deptype = 'e'
+	LEFT JOIN pg_catalog.pg_tablespace AS t6 ON t6.oid = t3.reltablespace
 WHERE
 	t1.table_type = 'BASE TABLE'
 	AND NOT COALESCE(t3.relispartition, false)
@@ -6,6 +6,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"io"
 	"testing"
 	"time"
@@ -167,6 +168,77 @@ func TestDriver_Version(t *testing.T) {
 	require.Equal(t, "130000", drv.(vr).Version())
 }
 
+func TestDriver_RefreshStats(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s := schema.New("public")
+	s.AddTables(schema.NewTable("users"), schema.NewTable("pets"))
+	m.ExpectExec(sqltest.Escape(`ANALYZE "public"."users"`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec(sqltest.Escape(`ANALYZE "public"."pets"`)).
+		WillReturnError(errors.New("permission denied"))
+	err = drv.(migrate.StatsRefresher).RefreshStats(context.Background(), s.Tables)
+	require.EqualError(t, err, `postgres: analyze table "pets": permission denied`)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_TableSizes(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	s := schema.New("public")
+	s.AddTables(schema.NewTable("users"), schema.NewTable("pets"))
+	m.ExpectQuery(sqltest.Escape(tableSizeQuery)).
+		WithArgs("public", "users").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(1000))
+	m.ExpectQuery(sqltest.Escape(tableSizeQuery)).
+		WithArgs("public", "pets").
+		WillReturnRows(sqlmock.NewRows([]string{"reltuples"}).AddRow(-1))
+	sizes, err := drv.(migrate.TableSizer).TableSizes(context.Background(), s.Tables)
+	require.NoError(t, err)
+	require.Equal(t, map[*schema.Table]int64{s.Tables[0]: 1000}, sizes)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_MissingPrivileges(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	privs := []*migrate.Privilege{
+		{Action: migrate.PrivilegeCreate, Type: "schema", Schema: "logs"},
+		{Action: migrate.PrivilegeDrop, Type: "schema", Schema: "old"},
+		{Action: migrate.PrivilegeCreate, Type: "table", Schema: "public", Name: "users"},
+		{Action: migrate.PrivilegeAlter, Type: "table", Name: "pets"},
+		{Action: migrate.PrivilegeDrop, Type: "function", Schema: "public", Name: "f"},
+	}
+	m.ExpectQuery(sqltest.Escape(privCreateSchemaQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(false))
+	m.ExpectQuery(sqltest.Escape(privSchemaOwnerQuery)).
+		WithArgs("old").
+		WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(true))
+	m.ExpectQuery(sqltest.Escape(privCreateQuery)).
+		WithArgs("public").
+		WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(true))
+	m.ExpectQuery(sqltest.Escape(privRelOwnerQuery)).
+		WithArgs("", "pets").
+		WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(false))
+	// Functions that do not exist are skipped.
+	m.ExpectQuery(sqltest.Escape(privFuncOwnerQuery)).
+		WithArgs("public", "f").
+		WillReturnRows(sqlmock.NewRows([]string{"granted"}).AddRow(nil))
+	missing, err := drv.(migrate.PrivilegeChecker).MissingPrivileges(context.Background(), privs)
+	require.NoError(t, err)
+	require.Equal(t, []*migrate.Privilege{privs[0], privs[3]}, missing)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
 func TestDriver_RealmRestoreFunc(t *testing.T) {
 	var (
 		apply   = &mockPlanApplier{}
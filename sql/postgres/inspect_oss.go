@@ -14,6 +14,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,7 +59,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			}
 		}
 		if mode.Is(schema.InspectTables) {
-			if err := i.inspectTables(ctx, r, nil); err != nil {
+			if err := i.inspectTables(ctx, r, &schema.InspectOptions{Concurrency: opts.Concurrency}); err != nil {
 				return nil, err
 			}
 			sqlx.LinkSchemaTables(schemas)
@@ -90,7 +91,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			return nil, err
 		}
 	}
-	return schema.ExcludeRealm(r, opts.Exclude)
+	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
 }
 
 // noSearchPath ensures the session search_path is clean when inspecting realms to ensures all
@@ -182,16 +183,20 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 	if err := i.inspectDeps(ctx, r, opts); err != nil {
 		return nil, err
 	}
-	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	return sqlx.SchemaChecksums(schema.ExcludeSchema(r.Schemas[0], opts.Exclude))
 }
 
 func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *schema.InspectOptions) error {
 	if err := i.tables(ctx, r, opts); err != nil {
 		return err
 	}
-	for _, s := range r.Schemas {
+	var n int
+	if opts != nil {
+		n = opts.Concurrency
+	}
+	return i.eachSchema(ctx, r.Schemas, n, func(i *inspect, s *schema.Schema) error {
 		if len(s.Tables) == 0 {
-			continue
+			return nil
 		}
 		if err := i.columns(ctx, s); err != nil {
 			return err
@@ -208,6 +213,100 @@ func (i *inspect) inspectTables(ctx context.Context, r *schema.Realm, opts *sche
 		if err := i.checks(ctx, s); err != nil {
 			return err
 		}
+		return i.partitionBounds(ctx, s)
+	})
+}
+
+// eachSchema calls f for each of the given schemas. If n is greater than one, and the
+// connection is a pool (e.g., sql.DB), up to n schemas are handled concurrently. Each
+// worker uses a dedicated connection that inherits the search_path of the session.
+func (i *inspect) eachSchema(ctx context.Context, schemas []*schema.Schema, n int, f func(*inspect, *schema.Schema) error) error {
+	if _, ok := i.ExecQuerier.(interface {
+		Conn(context.Context) (*sql.Conn, error)
+	}); !ok || n <= 1 || len(schemas) <= 1 {
+		for _, s := range schemas {
+			if err := f(i, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var path string
+	if !i.crdb {
+		rows, err := i.QueryContext(ctx, "SELECT current_setting('search_path')")
+		if err != nil {
+			return err
+		}
+		if err := sqlx.ScanOne(rows, &path); err != nil {
+			return err
+		}
+	}
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		rerr error
+		ch   = make(chan *schema.Schema)
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for range min(n, len(schemas)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := i.worker(ctx, path, ch, f); err != nil {
+				once.Do(func() {
+					rerr = err
+					cancel()
+				})
+			}
+		}()
+	}
+loop:
+	for _, s := range schemas {
+		select {
+		case ch <- s:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(ch)
+	wg.Wait()
+	return rerr
+}
+
+// worker calls f for the schemas received from the channel, using
+// a dedicated connection with the given search_path.
+func (i *inspect) worker(ctx context.Context, path string, ch <-chan *schema.Schema, f func(*inspect, *schema.Schema) error) (rerr error) {
+	c, err := sqlx.SingleConn(ctx, i.ExecQuerier)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if !i.crdb {
+		rows, err := c.QueryContext(ctx, "SELECT current_setting('search_path'), set_config('search_path', $1, false)", path)
+		if err != nil {
+			return err
+		}
+		var prev sql.NullString
+		if err := sqlx.ScanOne(rows, &prev, &sql.NullString{}); err != nil {
+			return err
+		}
+		// Restore the search_path before the connection is returned to the pool.
+		defer func() {
+			rows, err := c.QueryContext(context.WithoutCancel(ctx), "SELECT set_config('search_path', $1, false)", prev.String)
+			if err == nil {
+				err = rows.Close()
+			}
+			rerr = errors.Join(rerr, err)
+		}()
+	}
+	cc := *i.conn
+	cc.ExecQuerier = c
+	w := &inspect{conn: &cc}
+	for s := range ch {
+		if err := f(w, s); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -234,10 +333,11 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 	defer rows.Close()
 	for rows.Next() {
 		var (
-			oid                                                            sql.NullInt64
-			tSchema, name, comment, partattrs, partstart, partexprs, extra sql.NullString
+			oid                                                                        sql.NullInt64
+			unlogged                                                                   sql.NullBool
+			tSchema, name, comment, partattrs, partstart, partexprs, tablespace, extra sql.NullString
 		)
-		if err := rows.Scan(&oid, &tSchema, &name, &comment, &partattrs, &partstart, &partexprs, &extra); err != nil {
+		if err := rows.Scan(&oid, &tSchema, &name, &comment, &partattrs, &partstart, &partexprs, &unlogged, &tablespace, &extra); err != nil {
 			return fmt.Errorf("scan table information: %w", err)
 		}
 		if !sqlx.ValidString(tSchema) || !sqlx.ValidString(name) {
@@ -262,10 +362,40 @@ func (i *inspect) tables(ctx context.Context, realm *schema.Realm, opts *schema.
 				exprs: partexprs.String,
 			})
 		}
+		if unlogged.Bool {
+			t.AddAttrs(&Unlogged{V: true})
+		}
+		if sqlx.ValidString(tablespace) {
+			t.AddAttrs(&Tablespace{V: tablespace.String})
+		}
+		if sqlx.ValidString(extra) {
+			if err := addAccess(&t.Attrs, extra.String); err != nil {
+				return fmt.Errorf("postgres: parsing attributes of table %q: %w", name.String, err)
+			}
+		}
 	}
 	return rows.Err()
 }
 
+// addAccess appends the owner and the access privileges of a relation,
+// encoded as a JSON object by the inspection queries, to the given attributes.
+func addAccess(attrs *[]schema.Attr, s string) error {
+	var a struct {
+		Owner string   `json:"owner"`
+		ACL   []string `json:"acl"`
+	}
+	if err := json.Unmarshal([]byte(s), &a); err != nil {
+		return err
+	}
+	if a.Owner != "" {
+		schema.ReplaceOrAppend(attrs, &Owner{Name: a.Owner})
+	}
+	if len(a.ACL) > 0 {
+		schema.ReplaceOrAppend(attrs, &ACL{Items: a.ACL})
+	}
+	return nil
+}
+
 // columns queries and appends the columns of the given table.
 func (i *inspect) columns(ctx context.Context, s *schema.Schema) error {
 	query := columnsQuery
@@ -470,6 +600,577 @@ func (i *inspect) inspectEnums(ctx context.Context, r *schema.Realm) error {
 	return nil
 }
 
+// inspectComposites queries and appends the composite types of the inspected schemas.
+// Fields are parsed after all types were collected, as they may reference each other.
+func (i *inspect) inspectComposites(ctx context.Context, r *schema.Realm) error {
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(compositesQuery, nArgs(0, len(args))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying composite types: %w", err)
+	}
+	defer rows.Close()
+	type field struct {
+		c                   *CompositeType
+		name, typ, typeName string
+		collate             sql.NullString
+	}
+	var (
+		fields []*field
+		ids    = make(map[int64]*CompositeType)
+	)
+	for rows.Next() {
+		var (
+			id int64
+			ns string
+			f  = &field{}
+		)
+		if err := rows.Scan(&ns, &id, &f.typeName, &f.name, &f.typ, &f.collate); err != nil {
+			return fmt.Errorf("postgres: scanning composite type: %w", err)
+		}
+		c, ok := ids[id]
+		if !ok {
+			s, ok := r.Schema(ns)
+			if !ok {
+				return fmt.Errorf("postgres: schema %q for composite %q was not found in inspection", ns, f.typeName)
+			}
+			c = &CompositeType{T: f.typeName, Schema: s}
+			ids[id] = c
+			s.AddObjects(c)
+		}
+		f.c = c
+		fields = append(fields, f)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	for _, f := range fields {
+		t, err := i.parseType(f.c.Schema, f.typ)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing type of field %q of composite %q: %w", f.name, f.c.T, err)
+		}
+		c := schema.NewColumn(f.name)
+		c.Type = &schema.ColumnType{Type: t, Raw: f.typ}
+		if sqlx.ValidString(f.collate) {
+			c.SetCollation(f.collate.String)
+		}
+		if o, ok := schema.UnderlyingType(t).(schema.Object); ok && o != schema.Object(f.c) && !slices.Contains(f.c.Deps, o) {
+			f.c.Deps = append(f.c.Deps, o)
+		}
+		f.c.Fields = append(f.c.Fields, c)
+	}
+	return nil
+}
+
+// inspectRealmObjects inspects the procedural languages, the casts and the
+// database-level attributes (comment and settings) defined in the database.
+func (i *inspect) inspectRealmObjects(ctx context.Context, r *schema.Realm, _ *schema.InspectOptions) error {
+	// CockroachDB does not support defining casts or languages.
+	if i.crdb {
+		return nil
+	}
+	if err := i.inspectLanguages(ctx, r); err != nil {
+		return err
+	}
+	if err := i.inspectCasts(ctx, r); err != nil {
+		return err
+	}
+	return i.inspectDatabase(ctx, r)
+}
+
+// inspectDatabase queries the comment and the settings of the connected database.
+// The database is appended to the realm objects only if one of them is set.
+func (i *inspect) inspectDatabase(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, databaseQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying database: %w", err)
+	}
+	defer rows.Close()
+	d := &Database{}
+	for rows.Next() {
+		var comment, setting sql.NullString
+		if err := rows.Scan(&d.Name, &comment, &setting); err != nil {
+			return fmt.Errorf("postgres: scanning database: %w", err)
+		}
+		if sqlx.ValidString(comment) {
+			schema.ReplaceOrAppend(&d.Attrs, &schema.Comment{Text: comment.String})
+		}
+		if setting.Valid {
+			name, value, ok := strings.Cut(setting.String, "=")
+			if !ok {
+				return fmt.Errorf("postgres: unexpected setting %q for database %q", setting.String, d.Name)
+			}
+			d.Settings = append(d.Settings, &DatabaseSetting{Name: name, Value: value})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(d.Settings) > 0 || len(d.Attrs) > 0 {
+		r.Objects = append(r.Objects, d)
+	}
+	return nil
+}
+
+// inspectLanguages queries and appends the procedural languages of the database.
+func (i *inspect) inspectLanguages(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, languagesQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying languages: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			l                                   = &Language{}
+			handler, inline, validator, comment sql.NullString
+		)
+		if err := rows.Scan(&l.Name, &l.Trusted, &handler, &inline, &validator, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning language: %w", err)
+		}
+		l.Handler, l.Inline, l.Validator = handler.String, inline.String, validator.String
+		if sqlx.ValidString(comment) {
+			schema.ReplaceOrAppend(&l.Attrs, &schema.Comment{Text: comment.String})
+		}
+		r.Objects = append(r.Objects, l)
+	}
+	return rows.Err()
+}
+
+// inspectCasts queries and appends the user-defined casts of the database.
+func (i *inspect) inspectCasts(ctx context.Context, r *schema.Realm) error {
+	rows, err := i.QueryContext(ctx, castsQuery)
+	if err != nil {
+		return fmt.Errorf("postgres: querying casts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			c            = &Cast{}
+			method, ctxt string
+			fn, comment  sql.NullString
+		)
+		if err := rows.Scan(&c.Source, &c.Target, &method, &ctxt, &fn, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning cast: %w", err)
+		}
+		switch method {
+		case "f":
+			c.Method, c.Func = CastMethodFunc, fn.String
+		case "b":
+			c.Method = CastMethodBinary
+		case "i":
+			c.Method = CastMethodInOut
+		default:
+			return fmt.Errorf("postgres: unexpected method %q for cast (%s AS %s)", method, c.Source, c.Target)
+		}
+		switch ctxt {
+		case "e":
+			c.Context = CastContextExplicit
+		case "a":
+			c.Context = CastContextAssignment
+		case "i":
+			c.Context = CastContextImplicit
+		default:
+			return fmt.Errorf("postgres: unexpected context %q for cast (%s AS %s)", ctxt, c.Source, c.Target)
+		}
+		if sqlx.ValidString(comment) {
+			schema.ReplaceOrAppend(&c.Attrs, &schema.Comment{Text: comment.String})
+		}
+		r.Objects = append(r.Objects, c)
+	}
+	return rows.Err()
+}
+
+// inspectStatistics queries and appends the extended statistics objects of the inspected
+// schemas. Statistics defined on tables that were not inspected are ignored.
+func (i *inspect) inspectStatistics(ctx context.Context, r *schema.Realm) error {
+	// CockroachDB does not support extended statistics.
+	if i.crdb || !slices.ContainsFunc(r.Schemas, func(s *schema.Schema) bool { return len(s.Tables) > 0 }) {
+		return nil
+	}
+	exprs := "NULL"
+	if i.supportsStatisticsExprs() {
+		exprs = "array_to_json(pg_catalog.pg_get_statisticsobjdef_expressions(s.oid))"
+	}
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(statisticsQuery, exprs, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying statistics: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name, tns, tname, kinds string
+			columns, exprs, comment     sql.NullString
+			owner                       sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &tns, &tname, &kinds, &columns, &exprs, &comment, &owner); err != nil {
+			return fmt.Errorf("postgres: scanning statistics: %w", err)
+		}
+		s, ok1 := r.Schema(ns)
+		ts, ok2 := r.Schema(tns)
+		if !ok1 || !ok2 {
+			continue
+		}
+		t, ok := ts.Table(tname)
+		if !ok {
+			continue
+		}
+		st := &Statistics{Name: name, Schema: s, T: t}
+		if sqlx.ValidString(columns) {
+			var names []string
+			if err := json.Unmarshal([]byte(columns.String), &names); err != nil {
+				return fmt.Errorf("postgres: decoding columns of statistics %q: %w", name, err)
+			}
+			for _, n := range names {
+				c, ok := t.Column(n)
+				if !ok {
+					return fmt.Errorf("postgres: column %q of statistics %q was not found in table %q", n, name, t.Name)
+				}
+				st.Columns = append(st.Columns, c)
+			}
+		}
+		if sqlx.ValidString(exprs) {
+			if err := json.Unmarshal([]byte(exprs.String), &st.Exprs); err != nil {
+				return fmt.Errorf("postgres: decoding expressions of statistics %q: %w", name, err)
+			}
+		}
+		for _, k := range strings.Split(kinds, ",") {
+			switch k {
+			case "d":
+				st.Kinds = append(st.Kinds, StatisticsKindNDistinct)
+			case "f":
+				st.Kinds = append(st.Kinds, StatisticsKindDependencies)
+			case "m":
+				st.Kinds = append(st.Kinds, StatisticsKindMCV)
+			case "e":
+				// Expressions statistics are built implicitly for expressions.
+			default:
+				return fmt.Errorf("postgres: unexpected kind %q for statistics %q", k, name)
+			}
+		}
+		// All kinds are built by default, if none was specified.
+		if len(st.Kinds) == len(i.statisticsKinds()) {
+			st.Kinds = nil
+		}
+		if sqlx.ValidString(comment) {
+			schema.ReplaceOrAppend(&st.Attrs, &schema.Comment{Text: comment.String})
+		}
+		if sqlx.ValidString(owner) {
+			schema.ReplaceOrAppend(&st.Attrs, &Owner{Name: owner.String})
+		}
+		s.AddObjects(st)
+	}
+	return rows.Err()
+}
+
+// inspectPolicies queries the row-level security options of the inspected tables, and appends their
+// policies to the schemas. Policies defined on tables that were not inspected are ignored.
+func (i *inspect) inspectPolicies(ctx context.Context, r *schema.Realm) error {
+	// Row-level security is not inspected on CockroachDB.
+	if i.crdb || !slices.ContainsFunc(r.Schemas, func(s *schema.Schema) bool { return len(s.Tables) > 0 }) {
+		return nil
+	}
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	if err := i.inspectRowSecurity(ctx, r, args); err != nil {
+		return err
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(policiesQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying policies: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, tname, name, as, cmd string
+			roles, using, check      sql.NullString
+		)
+		if err := rows.Scan(&ns, &tname, &name, &as, &cmd, &roles, &using, &check); err != nil {
+			return fmt.Errorf("postgres: scanning policies: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			continue
+		}
+		t, ok := s.Table(tname)
+		if !ok {
+			continue
+		}
+		p := &Policy{Name: name, T: t, Using: using.String, Check: check.String}
+		if as != PolicyAsPermissive {
+			p.As = as
+		}
+		if cmd != PolicyForAll {
+			p.For = cmd
+		}
+		if sqlx.ValidString(roles) {
+			if err := json.Unmarshal([]byte(roles.String), &p.To); err != nil {
+				return fmt.Errorf("postgres: decoding roles of policy %q: %w", name, err)
+			}
+		}
+		// Policies that apply to all roles are defined with PUBLIC.
+		if len(p.To) == 1 && p.To[0] == "public" {
+			p.To = nil
+		}
+		s.AddObjects(p)
+	}
+	return rows.Err()
+}
+
+// inspectRowSecurity sets the row-level security options of the inspected tables.
+func (i *inspect) inspectRowSecurity(ctx context.Context, r *schema.Realm, args []any) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(rowSecurityQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying row-level security: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, tname         string
+			enabled, enforced bool
+		)
+		if err := rows.Scan(&ns, &tname, &enabled, &enforced); err != nil {
+			return fmt.Errorf("postgres: scanning row-level security: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			continue
+		}
+		if t, ok := s.Table(tname); ok {
+			t.AddAttrs(&RowSecurity{Enabled: enabled, Enforced: enforced})
+		}
+	}
+	return rows.Err()
+}
+
+// inspectSequences queries and appends the sequences of the inspected schemas. Sequences that
+// were created for identity or serial columns, or by extensions, are skipped, as well as sequences
+// that are owned by tables that were not inspected.
+func (i *inspect) inspectSequences(ctx context.Context, r *schema.Realm) error {
+	// Sequences are not inspected on CockroachDB.
+	if i.crdb || len(r.Schemas) == 0 {
+		return nil
+	}
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(sequencesQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying sequences: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ns, name, typ                  string
+			start, incr, minV, maxV, cache int64
+			cycle                          bool
+			last                           sql.NullInt64
+			tns, tname, cname, comment     sql.NullString
+		)
+		if err := rows.Scan(&ns, &name, &typ, &start, &incr, &minV, &maxV, &cache, &cycle, &last, &tns, &tname, &cname, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning sequences: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok || serialSequence(s, name) {
+			continue
+		}
+		seq := &Sequence{
+			Name:      name,
+			Schema:    s,
+			Type:      &schema.IntegerType{T: typ},
+			Start:     start,
+			Increment: incr,
+			Min:       &minV,
+			Max:       &maxV,
+			Cache:     cache,
+			Cycle:     cycle,
+			Last:      last.Int64,
+		}
+		if sqlx.ValidString(tname) {
+			ts, ok := r.Schema(tns.String)
+			if !ok {
+				continue
+			}
+			t, ok := ts.Table(tname.String)
+			if !ok {
+				continue
+			}
+			c, ok := t.Column(cname.String)
+			if !ok {
+				return fmt.Errorf("postgres: column %q owning sequence %q was not found in table %q", cname.String, name, t.Name)
+			}
+			seq.Owner.T, seq.Owner.C = t, c
+		}
+		if sqlx.ValidString(comment) {
+			schema.ReplaceOrAppend(&seq.Attrs, &schema.Comment{Text: comment.String})
+		}
+		s.AddObjects(seq)
+	}
+	return rows.Err()
+}
+
+// serialSequence reports if the sequence with the given name
+// is used by one of the serial columns defined in the schema.
+func serialSequence(s *schema.Schema, name string) bool {
+	return slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		return slices.ContainsFunc(t.Columns, func(c *schema.Column) bool {
+			st, ok := c.Type.Type.(*SerialType)
+			return ok && st.sequence(t, c) == name
+		})
+	})
+}
+
+// inspectViews queries and appends the materialized views of the given schemas,
+// including their columns and indexes. Regular views are not inspected.
+func (i *inspect) inspectViews(ctx context.Context, r *schema.Realm, _ *schema.InspectOptions) error {
+	// Materialized views are not inspected on CockroachDB.
+	if i.crdb {
+		return nil
+	}
+	args := make([]any, 0, len(r.Schemas))
+	for _, s := range r.Schemas {
+		args = append(args, s.Name)
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(materializedQuery, nArgs(0, len(r.Schemas))), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying materialized views: %w", err)
+	}
+	if err := func() error {
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				ns, name, def  string
+				populated      bool
+				comment, attrs sql.NullString
+			)
+			if err := rows.Scan(&ns, &name, &def, &comment, &populated, &attrs); err != nil {
+				return fmt.Errorf("postgres: scanning materialized views: %w", err)
+			}
+			s, ok := r.Schema(ns)
+			if !ok {
+				return fmt.Errorf("postgres: schema %q was not found in realm", ns)
+			}
+			v := schema.NewMaterializedView(name, strings.TrimSuffix(strings.TrimSpace(def), ";"))
+			if sqlx.ValidString(comment) {
+				v.SetComment(comment.String)
+			}
+			if !populated {
+				v.AddAttrs(&WithNoData{})
+			}
+			if sqlx.ValidString(attrs) {
+				if err := addAccess(&v.Attrs, attrs.String); err != nil {
+					return fmt.Errorf("postgres: parsing attributes of materialized view %q: %w", name, err)
+				}
+			}
+			s.AddViews(v)
+		}
+		return rows.Err()
+	}(); err != nil {
+		return err
+	}
+	for _, s := range r.Schemas {
+		if len(s.Views) == 0 {
+			continue
+		}
+		if err := i.viewColumns(ctx, s); err != nil {
+			return err
+		}
+		if err := i.viewIndexes(ctx, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// viewColumns queries and appends the columns of the materialized views in the given schema.
+func (i *inspect) viewColumns(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.queryViews(ctx, materializedColumnsQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q materialized view columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			view, name, typ string
+			comment         sql.NullString
+		)
+		if err := rows.Scan(&view, &name, &typ, &comment); err != nil {
+			return fmt.Errorf("postgres: scanning materialized view columns: %w", err)
+		}
+		v, ok := s.Materialized(view)
+		if !ok {
+			return fmt.Errorf("postgres: materialized view %q was not found in schema", view)
+		}
+		t, err := i.parseType(s, typ)
+		if err != nil {
+			return fmt.Errorf("postgres: parsing type of column %q in materialized view %q: %w", name, view, err)
+		}
+		// Columns of materialized views cannot be constrained, and are always nullable.
+		c := schema.NewColumn(name).SetType(t).SetNull(true)
+		c.Type.Raw = typ
+		if sqlx.ValidString(comment) {
+			c.SetComment(comment.String)
+		}
+		v.AddColumns(c)
+	}
+	return rows.Err()
+}
+
+// viewIndexes queries and appends the indexes of the materialized views in the given schema.
+func (i *inspect) viewIndexes(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.queryViews(ctx, i.indexesQuery(), s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q materialized view indexes: %w", s.Name, err)
+	}
+	defer rows.Close()
+	if err := i.addIndexes(s, rows, queryScope{
+		hasT: func(tv string) bool {
+			_, ok := s.Materialized(tv)
+			return ok
+		},
+		setPK: func(tv string, _ *schema.Index) error {
+			return fmt.Errorf("postgres: unexpected primary key for materialized view %q", tv)
+		},
+		addIndex: func(tv string, idx *schema.Index) error {
+			v, ok := s.Materialized(tv)
+			if !ok {
+				return fmt.Errorf("postgres: materialized view %q for index was not found in schema", tv)
+			}
+			v.AddIndexes(idx)
+			return nil
+		},
+		column: func(tv, name string) (*schema.Column, bool) {
+			if v, ok := s.Materialized(tv); ok {
+				return v.Column(name)
+			}
+			return nil, false
+		},
+	}); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// queryViews executes the given query with the schema name and the names of its materialized views.
+func (i *inspect) queryViews(ctx context.Context, query string, s *schema.Schema) (*sql.Rows, error) {
+	args := []any{s.Name}
+	for _, v := range s.Views {
+		args = append(args, v.Name)
+	}
+	return i.QueryContext(ctx, fmt.Sprintf(query, nArgs(1, len(s.Views))), args...)
+}
+
 // indexes queries and appends the indexes of the given table.
 func (i *inspect) indexes(ctx context.Context, s *schema.Schema) error {
 	if i.crdb {
@@ -539,10 +1240,12 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows, scope queryScope)
 			uniq, primary, included, nullsnotdistinct                                                bool
 			desc, nullsfirst, nullslast, opcdefault                                                  sql.NullBool
 			column, constraints, pred, expr, comment, options, opcname, opcschema, opcparams, exoper sql.NullString
+			tablespace                                                                               sql.NullString
 		)
 		if err := rows.Scan(
 			&table, &name, &typ, &column, &included, &primary, &uniq, &exoper, &constraints, &pred, &expr, &desc,
 			&nullsfirst, &nullslast, &comment, &options, &opcname, &opcschema, &opcdefault, &opcparams, &nullsnotdistinct,
+			&tablespace,
 		); err != nil {
 			return fmt.Errorf("postgres: scanning indexes for schema %q: %w", s.Name, err)
 		}
@@ -583,6 +1286,9 @@ func (i *inspect) addIndexes(s *schema.Schema, rows *sql.Rows, scope queryScope)
 			if nullsnotdistinct {
 				idx.AddAttrs(&IndexNullsDistinct{V: false})
 			}
+			if sqlx.ValidString(tablespace) {
+				idx.AddAttrs(&Tablespace{V: tablespace.String})
+			}
 			names[name] = idx
 			var err error
 			if primary {
@@ -712,6 +1418,42 @@ func (i *inspect) partitions(s *schema.Schema) error {
 	return nil
 }
 
+// partitionBounds queries and appends the partitions of the partitioned tables in the schema.
+func (i *inspect) partitionBounds(ctx context.Context, s *schema.Schema) error {
+	args := []any{s.Name}
+	for _, t := range s.Tables {
+		if sqlx.Has(t.Attrs, &Partition{}) {
+			args = append(args, t.Name)
+		}
+	}
+	if i.crdb || len(args) == 1 {
+		return nil
+	}
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(partitionBoundsQuery, nArgs(1, len(args)-1)), args...)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q partitions: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var tname, name, bound string
+		if err := rows.Scan(&tname, &name, &bound); err != nil {
+			return fmt.Errorf("postgres: scanning schema %q partitions: %w", s.Name, err)
+		}
+		t, ok := s.Table(tname)
+		if !ok {
+			return fmt.Errorf("postgres: table %q was not found in schema %q", tname, s.Name)
+		}
+		b := &PartitionBound{Name: name}
+		if strings.EqualFold(bound, "DEFAULT") {
+			b.Default = true
+		} else {
+			b.Values = strings.TrimPrefix(bound, "FOR VALUES ")
+		}
+		t.AddAttrs(b)
+	}
+	return rows.Err()
+}
+
 // fks queries and appends the foreign keys of the given table.
 func (i *inspect) fks(ctx context.Context, s *schema.Schema) error {
 	rows, err := i.querySchema(ctx, fksQuery, s)
@@ -722,6 +1464,54 @@ func (i *inspect) fks(ctx context.Context, s *schema.Schema) error {
 	if err := sqlx.TypedSchemaFKs[*ReferenceOption](s, rows); err != nil {
 		return fmt.Errorf("postgres: %w", err)
 	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return i.fksDeleteColumns(ctx, s)
+}
+
+// fksDeleteColumns queries and sets the column lists of the ON DELETE SET NULL and
+// SET DEFAULT actions. The query is executed only if such foreign keys exist.
+func (i *inspect) fksDeleteColumns(ctx context.Context, s *schema.Schema) error {
+	if !i.supportsFKDeleteColumns() || !slices.ContainsFunc(s.Tables, func(t *schema.Table) bool {
+		return slices.ContainsFunc(t.ForeignKeys, func(fk *schema.ForeignKey) bool {
+			return fk.OnDelete == schema.SetNull || fk.OnDelete == schema.SetDefault
+		})
+	}) {
+		return nil
+	}
+	rows, err := i.querySchema(ctx, fksDeleteColumnsQuery, s)
+	if err != nil {
+		return fmt.Errorf("postgres: querying schema %q foreign keys delete columns: %w", s.Name, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, table, columns string
+		if err := rows.Scan(&name, &table, &columns); err != nil {
+			return fmt.Errorf("postgres: scanning foreign keys delete columns: %w", err)
+		}
+		t, ok := s.Table(table)
+		if !ok {
+			return fmt.Errorf("postgres: table %q was not found in schema", table)
+		}
+		fk, ok := t.ForeignKey(name)
+		if !ok {
+			return fmt.Errorf("postgres: foreign key %q was not found in table %q", name, table)
+		}
+		var names []string
+		if err := json.Unmarshal([]byte(columns), &names); err != nil {
+			return fmt.Errorf("postgres: decoding delete columns of foreign key %q: %w", name, err)
+		}
+		d := &DeleteSetColumns{}
+		for _, n := range names {
+			c, ok := t.Column(n)
+			if !ok {
+				return fmt.Errorf("postgres: column %q of foreign key %q was not found in table %q", n, name, table)
+			}
+			d.Columns = append(d.Columns, c)
+		}
+		schema.ReplaceOrAppend(&fk.Attrs, d)
+	}
 	return rows.Err()
 }
 
@@ -844,6 +1634,9 @@ func nArgs(start, n int) string {
 // nextval('<optional (quoted) schema>.<sequence name>'::regclass).
 var reNextval = regexp.MustCompile(`(?i) *nextval\('(?:"?[\w$]+"?\.)?"?([\w$]+_[\w$]+_seq)"?'(?:::regclass)*\) *$`)
 
+// reSeqNextval matches the sequence name (and its optional schema) used in a nextval expression.
+var reSeqNextval = regexp.MustCompile(`(?i)nextval\('(?:"?([\w$]+)"?\.)?"?([\w$]+)"?'(?:::regclass)?\)`)
+
 func columnDefault(c *schema.Column, s string) {
 	switch m := reNextval.FindStringSubmatch(s); {
 	// The definition of "<column> <serial type>" is equivalent to specifying:
@@ -1091,6 +1884,104 @@ type (
 		}
 	}
 
+	// Language describes a procedural language that was enabled in the database.
+	// A language without a handler is packaged as an extension with the same name.
+	// https://www.postgresql.org/docs/current/sql-createlanguage.html
+	Language struct {
+		schema.Object
+		Name      string        // Language name.
+		Trusted   bool          // Trusted languages can be used by unprivileged users.
+		Handler   string        // Optional call handler function.
+		Inline    string        // Optional inline handler function.
+		Validator string        // Optional validator function.
+		Attrs     []schema.Attr // Extra attributes, such as comments.
+	}
+
+	// Cast describes a user-defined cast between two data types.
+	// https://www.postgresql.org/docs/current/sql-createcast.html
+	Cast struct {
+		schema.Object
+		Source  string        // Source data type.
+		Target  string        // Target data type.
+		Method  string        // FUNCTION, BINARY or INOUT.
+		Func    string        // Function signature, if the method is FUNCTION.
+		Context string        // EXPLICIT, ASSIGNMENT or IMPLICIT.
+		Attrs   []schema.Attr // Extra attributes, such as comments.
+	}
+
+	// Database describes the database-level attributes of the connected
+	// database, such as its comment and configuration parameters that
+	// were set using ALTER DATABASE ... SET.
+	// https://www.postgresql.org/docs/current/sql-alterdatabase.html
+	Database struct {
+		schema.Object
+		Name     string             // Database name.
+		Settings []*DatabaseSetting // Configuration parameters, sorted by name.
+		Attrs    []schema.Attr      // Extra attributes, such as comments.
+	}
+
+	// DatabaseSetting describes a configuration parameter (e.g., timezone)
+	// that is set as a session default for the database.
+	DatabaseSetting struct {
+		Name  string // Parameter name.
+		Value string // Parameter value, as stored in pg_db_role_setting.
+	}
+
+	// Statistics describes an extended statistics object that was defined on a table.
+	// https://www.postgresql.org/docs/current/sql-createstatistics.html
+	Statistics struct {
+		schema.Object
+		Name    string           // Statistics name.
+		Schema  *schema.Schema   // Schema the statistics object resides in.
+		T       *schema.Table    // Table the statistics are computed on.
+		Columns []*schema.Column // Columns, ordered by their position in the table.
+		Exprs   []string         // Expressions, supported by PostgreSQL 14 and above.
+		Kinds   []string         // NDISTINCT, DEPENDENCIES or MCV. Empty means all kinds.
+		Attrs   []schema.Attr    // Extra attributes, such as comments.
+	}
+
+	// Policy describes a row-level security policy that was defined on a table.
+	// https://www.postgresql.org/docs/current/sql-createpolicy.html
+	Policy struct {
+		schema.Object
+		Name  string        // Policy name, unique per table.
+		T     *schema.Table // Table the policy is defined on.
+		As    string        // PERMISSIVE or RESTRICTIVE. Empty means PERMISSIVE.
+		For   string        // ALL, SELECT, INSERT, UPDATE or DELETE. Empty means ALL.
+		To    []string      // Roles the policy applies to. Empty means PUBLIC.
+		Using string        // USING expression.
+		Check string        // WITH CHECK expression.
+	}
+
+	// RowSecurity describes the row-level security options of a table.
+	RowSecurity struct {
+		schema.Attr
+		Enabled  bool // ENABLE ROW LEVEL SECURITY.
+		Enforced bool // FORCE ROW LEVEL SECURITY, applies the policies on the table owner as well.
+	}
+
+	// WithNoData describes a materialized view that was created (or refreshed)
+	// WITH NO DATA, and therefore, is not populated and cannot be queried.
+	WithNoData struct {
+		schema.Attr
+	}
+
+	// Owner describes the role that owns a database object. It is captured
+	// during inspection, and used to restore the ownership of objects that
+	// cannot be altered, and are recreated instead (e.g., statistics).
+	Owner struct {
+		schema.Attr
+		Name string
+	}
+
+	// ACL describes the access privileges of a relation, as stored in its
+	// access control list (e.g., "bob=arw/admin"). Like Owner, it is captured
+	// during inspection, and used to restore the privileges of recreated relations.
+	ACL struct {
+		schema.Attr
+		Items []string
+	}
+
 	// Identity defines an identity column.
 	Identity struct {
 		schema.Attr
@@ -1197,6 +2088,42 @@ type (
 		start, attrs, exprs string
 	}
 
+	// PartitionBound describes a partition (a child table) of a partitioned table,
+	// and the bound of the values it holds. Partitions of a table are not inspected
+	// as tables, but as attributes of their parent table.
+	PartitionBound struct {
+		schema.Attr
+		Name    string // Name of the partition.
+		Values  string // Bound values. e.g., "FROM (1) TO (10)", "IN ('a', 'b')" or "WITH (modulus 2, remainder 0)".
+		Default bool   // Indicates a DEFAULT partition.
+	}
+
+	// PartitionMaintenance describes the maintenance policy of a table partitioned
+	// by a time RANGE. It is defined in the partition block of the table, and used
+	// to plan the creation of future partitions and the removal of expired ones.
+	PartitionMaintenance struct {
+		schema.Attr
+		Interval  string // Range of each partition. e.g., "1 day" or "1 month".
+		Premake   int    // Number of partitions to create ahead of the current one.
+		Retention string // Optional period to keep partitions for. e.g., "6 months".
+	}
+
+	// Unlogged describes the UNLOGGED option of a table. Data written to unlogged
+	// tables is not written to the write-ahead log, which makes them faster, but
+	// not crash-safe. For example, tables that are used as caches.
+	Unlogged struct {
+		schema.Attr
+		V bool
+	}
+
+	// Tablespace describes the tablespace a table or an index is stored in.
+	// Objects that are stored in the database default tablespace do not have
+	// this attribute. https://postgresql.org/docs/current/manage-ag-tablespaces.html
+	Tablespace struct {
+		schema.Attr
+		V string
+	}
+
 	// An PartitionPart represents an index part that
 	// can be either an expression or a column.
 	PartitionPart struct {
@@ -1213,8 +2140,26 @@ type (
 
 	// ReferenceOption describes the ON DELETE and ON UPDATE options for foreign keys.
 	ReferenceOption schema.ReferenceOption
+
+	// DeleteSetColumns describes the column list of the ON DELETE SET NULL and SET DEFAULT
+	// actions of a foreign key. Supported by PostgreSQL 15 and above.
+	// https://www.postgresql.org/docs/current/sql-createtable.html#SQL-CREATETABLE-PARMS-REFERENCES
+	DeleteSetColumns struct {
+		schema.Attr
+		Columns []*schema.Column
+	}
 )
 
+// Setting returns the database setting with the given name. Parameter names are case-insensitive.
+func (d *Database) Setting(name string) (*DatabaseSetting, bool) {
+	for _, s := range d.Settings {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
 var _ specutil.RefNamer = (*DomainType)(nil)
 
 // Ref returns a reference to the domain type.
@@ -1531,6 +2476,218 @@ WHERE
 ORDER BY
     nspname`
 
+	// Query to list the procedural languages of the database. Languages that are packaged
+	// as extensions are returned without their handlers, and plpgsql is installed by default.
+	languagesQuery = `
+SELECT
+	l.lanname AS language_name,
+	l.lanpltrusted AS trusted,
+	CASE WHEN e.oid IS NULL AND l.lanplcallfoid <> 0 THEN l.lanplcallfoid::regproc::text END AS handler,
+	CASE WHEN e.oid IS NULL AND l.laninline <> 0 THEN l.laninline::regproc::text END AS inline,
+	CASE WHEN e.oid IS NULL AND l.lanvalidator <> 0 THEN l.lanvalidator::regproc::text END AS validator,
+	pg_catalog.obj_description(l.oid, 'pg_language') AS comment
+FROM
+	pg_catalog.pg_language AS l
+	LEFT JOIN pg_depend AS dep ON dep.classid = 'pg_catalog.pg_language'::regclass::oid AND dep.objid = l.oid AND dep.deptype = 'e'
+	LEFT JOIN pg_catalog.pg_extension AS e ON e.oid = dep.refobjid
+WHERE
+	l.lanispl
+	AND l.lanname <> 'plpgsql'
+	AND (e.oid IS NULL OR e.extname = l.lanname)
+ORDER BY
+	l.lanname`
+
+	// Query to list the user-defined casts of the database.
+	castsQuery = `
+SELECT
+	pg_catalog.format_type(c.castsource, NULL) AS source,
+	pg_catalog.format_type(c.casttarget, NULL) AS target,
+	c.castmethod AS method,
+	c.castcontext AS context,
+	CASE WHEN c.castfunc <> 0 THEN c.castfunc::regprocedure::text END AS func,
+	pg_catalog.obj_description(c.oid, 'pg_cast') AS comment
+FROM
+	pg_catalog.pg_cast AS c
+	LEFT JOIN pg_depend AS dep ON dep.classid = 'pg_catalog.pg_cast'::regclass::oid AND dep.objid = c.oid AND dep.deptype = 'e'
+WHERE
+	c.oid >= 16384
+	AND dep.objid IS NULL
+ORDER BY
+	1, 2`
+
+	// Query to list the comment and the settings of the connected database. Settings
+	// that were set for specific roles in the database (ALTER ROLE ... IN DATABASE) are ignored.
+	databaseQuery = `
+SELECT
+	d.datname AS database_name,
+	pg_catalog.shobj_description(d.oid, 'pg_database') AS comment,
+	c.setting
+FROM
+	pg_catalog.pg_database AS d
+	LEFT JOIN pg_catalog.pg_db_role_setting AS s ON s.setdatabase = d.oid AND s.setrole = 0
+	LEFT JOIN LATERAL unnest(s.setconfig) AS c(setting) ON true
+WHERE
+	d.datname = current_database()
+ORDER BY
+	c.setting`
+
+	// Query to list the extended statistics objects of the given schemas. The columns
+	// of the statistics are returned ordered by their position in the table.
+	statisticsQuery = `
+SELECT
+	n.nspname AS schema_name,
+	s.stxname AS statistics_name,
+	tn.nspname AS table_schema,
+	t.relname AS table_name,
+	array_to_string(s.stxkind, ',') AS kinds,
+	(SELECT json_agg(a.attname ORDER BY a.attnum) FROM pg_catalog.pg_attribute AS a WHERE a.attrelid = s.stxrelid AND a.attnum = ANY(s.stxkeys::int2[])) AS columns,
+	%s AS exprs,
+	pg_catalog.obj_description(s.oid, 'pg_statistic_ext') AS comment,
+	pg_catalog.pg_get_userbyid(s.stxowner) AS owner
+FROM
+	pg_catalog.pg_statistic_ext AS s
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = s.stxnamespace
+	JOIN pg_catalog.pg_class AS t ON t.oid = s.stxrelid
+	JOIN pg_catalog.pg_namespace AS tn ON tn.oid = t.relnamespace
+WHERE
+	n.nspname IN (%s)
+ORDER BY
+	1, 2`
+
+	// Query to list the tables of the given schemas that have row-level security enabled or enforced.
+	rowSecurityQuery = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS table_name,
+	c.relrowsecurity AS enabled,
+	c.relforcerowsecurity AS enforced
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+WHERE
+	c.relkind IN ('r', 'p')
+	AND (c.relrowsecurity OR c.relforcerowsecurity)
+	AND n.nspname IN (%s)
+ORDER BY
+	1, 2`
+
+	// Query to list the row-level security policies of the given schemas.
+	policiesQuery = `
+SELECT
+	schemaname AS schema_name,
+	tablename AS table_name,
+	policyname AS policy_name,
+	permissive,
+	cmd,
+	array_to_json(roles) AS roles,
+	qual,
+	with_check
+FROM
+	pg_catalog.pg_policies
+WHERE
+	schemaname IN (%s)
+ORDER BY
+	1, 2, 3`
+
+	// Query to list the sequences of the given schemas. Sequences that were created implicitly
+	// for identity columns, or by extensions, are skipped.
+	sequencesQuery = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS sequence_name,
+	format_type(s.seqtypid, NULL) AS data_type,
+	s.seqstart,
+	s.seqincrement,
+	s.seqmin,
+	s.seqmax,
+	s.seqcache,
+	s.seqcycle,
+	ps.last_value,
+	tn.nspname AS owner_schema,
+	t.relname AS owner_table,
+	a.attname AS owner_column,
+	pg_catalog.obj_description(c.oid, 'pg_class') AS comment
+FROM
+	pg_catalog.pg_sequence AS s
+	JOIN pg_catalog.pg_class AS c ON c.oid = s.seqrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_sequences AS ps ON ps.schemaname = n.nspname AND ps.sequencename = c.relname
+	LEFT JOIN pg_catalog.pg_depend AS d ON d.classid = 'pg_catalog.pg_class'::regclass AND d.objid = c.oid AND d.refclassid = 'pg_catalog.pg_class'::regclass AND d.deptype = 'a'
+	LEFT JOIN pg_catalog.pg_class AS t ON t.oid = d.refobjid
+	LEFT JOIN pg_catalog.pg_namespace AS tn ON tn.oid = t.relnamespace
+	LEFT JOIN pg_catalog.pg_attribute AS a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+WHERE
+	n.nspname IN (%s)
+	AND NOT EXISTS (
+		SELECT 1 FROM pg_catalog.pg_depend AS e
+		WHERE e.classid = 'pg_catalog.pg_class'::regclass AND e.objid = c.oid AND e.deptype IN ('i', 'e')
+	)
+ORDER BY
+	1, 2`
+
+	// Query to list the partitions of the given partitioned tables, and their bounds.
+	// Partitions that are partitioned themselves, or reside in another schema, are skipped.
+	partitionBoundsQuery = `
+SELECT
+	p.relname AS table_name,
+	c.relname AS partition_name,
+	pg_catalog.pg_get_expr(c.relpartbound, c.oid) AS partition_bound
+FROM
+	pg_catalog.pg_inherits AS i
+	JOIN pg_catalog.pg_class AS c ON c.oid = i.inhrelid
+	JOIN pg_catalog.pg_class AS p ON p.oid = i.inhparent
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = p.relnamespace
+WHERE
+	n.nspname = $1
+	AND p.relname IN (%s)
+	AND c.relispartition
+	AND c.relkind = 'r'
+	AND c.relnamespace = p.relnamespace
+ORDER BY
+	1, 2`
+
+	// Query to list the materialized views of the given schemas.
+	// Views that were created by extensions are skipped.
+	materializedQuery = `
+SELECT
+	n.nspname AS schema_name,
+	c.relname AS view_name,
+	pg_catalog.pg_get_viewdef(c.oid) AS definition,
+	pg_catalog.obj_description(c.oid, 'pg_class') AS comment,
+	c.relispopulated AS populated,
+	json_build_object('owner', pg_catalog.pg_get_userbyid(c.relowner), 'acl', c.relacl) AS attrs
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	LEFT JOIN pg_catalog.pg_depend AS d ON d.classid = 'pg_catalog.pg_class'::regclass::oid AND d.objid = c.oid AND d.deptype = 'e'
+WHERE
+	c.relkind = 'm'
+	AND n.nspname IN (%s)
+	AND d.objid IS NULL
+ORDER BY
+	1, 2`
+
+	// Query to list the columns of materialized views.
+	materializedColumnsQuery = `
+SELECT
+	c.relname AS view_name,
+	a.attname AS column_name,
+	pg_catalog.format_type(a.atttypid, a.atttypmod) AS data_type,
+	pg_catalog.col_description(c.oid, a.attnum) AS comment
+FROM
+	pg_catalog.pg_class AS c
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = c.relnamespace
+	JOIN pg_catalog.pg_attribute AS a ON a.attrelid = c.oid
+WHERE
+	c.relkind = 'm'
+	AND n.nspname = $1
+	AND c.relname IN (%s)
+	AND a.attnum > 0
+	AND NOT a.attisdropped
+ORDER BY
+	c.relname, a.attnum
+`
+
 	// Query to list table columns.
 	columnsQuery = `
 SELECT
@@ -1584,6 +2741,28 @@ WHERE
     n.nspname IN (%s)
 ORDER BY
     n.nspname, e.enumtypid, e.enumsortorder
+`
+	// Query to list the composite types and their fields. The collation is returned
+	// only if it is different from the default collation of the field type.
+	compositesQuery = `
+SELECT
+	n.nspname AS schema_name,
+	t.oid AS type_id,
+	t.typname AS type_name,
+	a.attname AS field_name,
+	pg_catalog.format_type(a.atttypid, a.atttypmod) AS field_type,
+	CASE WHEN a.attcollation <> ft.typcollation THEN co.collname END AS field_collation
+FROM
+	pg_catalog.pg_type t
+	JOIN pg_catalog.pg_namespace n ON n.oid = t.typnamespace
+	JOIN pg_catalog.pg_class c ON c.oid = t.typrelid AND c.relkind = 'c'
+	JOIN pg_catalog.pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+	JOIN pg_catalog.pg_type ft ON ft.oid = a.atttypid
+	LEFT JOIN pg_catalog.pg_collation co ON co.oid = a.attcollation
+WHERE
+	t.typtype = 'c' AND n.nspname IN (%s)
+ORDER BY
+	n.nspname, t.oid, a.attnum
 `
 	// Query to list foreign-keys.
 	fksQuery = `
@@ -1627,6 +2806,25 @@ SELECT
 	    fk.conrelid, fk.constraint_name, fk.ord
 `
 
+	// Query to list the column lists of the ON DELETE SET NULL and SET DEFAULT actions.
+	fksDeleteColumnsQuery = `
+SELECT
+	con.conname AS constraint_name,
+	t.relname AS table_name,
+	(SELECT json_agg(a.attname ORDER BY k.ord) FROM unnest(con.confdelsetcols) WITH ORDINALITY AS k(attnum, ord) JOIN pg_catalog.pg_attribute AS a ON a.attrelid = con.conrelid AND a.attnum = k.attnum) AS columns
+FROM
+	pg_catalog.pg_constraint AS con
+	JOIN pg_catalog.pg_class AS t ON t.oid = con.conrelid
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = t.relnamespace
+WHERE
+	n.nspname = $1
+	AND t.relname IN (%s)
+	AND con.contype = 'f'
+	AND con.confdelsetcols IS NOT NULL
+ORDER BY
+	con.conrelid, con.conname
+`
+
 	// Query to list table check constraints.
 	checksQuery = `
 SELECT
@@ -1680,7 +2878,8 @@ SELECT
 	op.opcnamespace::regnamespace::text AS opclass_schema,
 	op.opcdefault AS opclass_default,
 	a2.attoptions AS opclass_params,
-    %s AS indnullsnotdistinct
+    %s AS indnullsnotdistinct,
+	ts.spcname AS tablespace
 FROM
 	(
 		select
@@ -1701,6 +2900,7 @@ FROM
 	JOIN pg_am am ON am.oid = i.relam
 	LEFT JOIN pg_opclass op ON op.oid = idx.indclass[idx.ord-1]
 	LEFT JOIN pg_attribute a2 ON (a2.attrelid, a2.attnum) = (idx.indexrelid, idx.ord)
+	LEFT JOIN pg_tablespace ts ON ts.oid = i.reltablespace
 WHERE
 	n.nspname = $1
 	AND t.relname IN (%s)
@@ -9,6 +9,8 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"testing"
 
 	"ariga.io/atlas/sql/internal/sqltest"
@@ -180,24 +182,24 @@ users      | ts          | tsvector            | tsvector  |  NO          |
 				m.ExpectQuery(queryIndexes).
 					WithArgs("public", "users").
 					WillReturnRows(sqltest.Rows(`
-   table_name   |    index_name   | index_type  | column_name | included | primary | unique | opexpr |   constraints   | predicate             |   expression              | desc | nulls_first | nulls_last | comment   |                 options               |   opclass_name    |   opclass_schema  | opclass_default | opclass_params | indnullsnotdistinct 
-----------------+-----------------+-------------+-------------+----------+---------+--------+--------+-----------------+-----------------------+---------------------------+------+-------------+------------+-----------+---------------------------------------+-------------------+-------------------+-----------------+----------------+---------------------
-users           | idx             | hash        |             | f        | f       | f      |        |                 |                       | "left"((c11)::text, 100)  | t    | t           | f          | boring    |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx1            | btree       |             | f        | f       | f      |        |                 | (id <> NULL::integer) | "left"((c11)::text, 100)  | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | t1_c1_key       | btree       | c1          | f        | f       | t      |        | {"name": "u"}   |                       | c1                        | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | t1_pkey         | btree       | id          | f        | t       | t      |        | {"t_pkey": "p"} |                       | id                        | t    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx4            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx4            | btree       | id          | f        | f       | t      |        |                 |                       | id                        | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx5            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx5            | btree       |             | f        | f       | t      |        |                 |                       | coalesce(parent_id, 0)    | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx6            | brin        | c1          | f        | f       | t      |        |                 |                       |                           | f    | f           | f          |           | {autosummarize=true,pages_per_range=2}|     int4_ops      |     public        |        t        |                | f
-users           | idx2            | btree       |             | f        | f       | f      |        |                 |                       | ((c * 2))                 | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx2            | btree       | c1          | f        | f       | f      |        |                 |                       | c                         | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx2            | btree       | id          | f        | f       | f      |        |                 |                       | d                         | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx2            | btree       | c1          | t        | f       | f      |        |                 |                       | c                         |      |             |            |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | idx2            | btree       | parent_id   | t        | f       | f      |        |                 |                       | d                         |      |             |            |           |                                       |     int4_ops      |     public        |        t        |                | f
-users           | dep_other_ns    | vec         | c1          | f        | f       | f      |        |                 |                       | c1                        |      |             |            |           |                                       |     vec_ops       |     unknown_ns    |        f        | {siglen=1}     | f
-users           | tsx             | gist        | ts          | f        | f       | f      |        |                 |                       | ts                        |      |             |            |           |                                       |     tsvector_ops  |     pg_catalog    |        f        | {siglen=1}     | f
+   table_name   |    index_name   | index_type  | column_name | included | primary | unique | opexpr |   constraints   | predicate             |   expression              | desc | nulls_first | nulls_last | comment   |                 options               |   opclass_name    |   opclass_schema  | opclass_default | opclass_params | indnullsnotdistinct | tablespace
+----------------+-----------------+-------------+-------------+----------+---------+--------+--------+-----------------+-----------------------+---------------------------+------+-------------+------------+-----------+---------------------------------------+-------------------+-------------------+-----------------+----------------+----------------------+-----------
+users           | idx             | hash        |             | f        | f       | f      |        |                 |                       | "left"((c11)::text, 100)  | t    | t           | f          | boring    |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx1            | btree       |             | f        | f       | f      |        |                 | (id <> NULL::integer) | "left"((c11)::text, 100)  | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | t1_c1_key       | btree       | c1          | f        | f       | t      |        | {"name": "u"}   |                       | c1                        | t    | t           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | t1_pkey         | btree       | id          | f        | t       | t      |        | {"t_pkey": "p"} |                       | id                        | t    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx4            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx4            | btree       | id          | f        | f       | t      |        |                 |                       | id                        | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx5            | btree       | c1          | f        | f       | t      |        |                 |                       | c1                        | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx5            | btree       |             | f        | f       | t      |        |                 |                       | coalesce(parent_id, 0)    | f    | f           | f          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx6            | brin        | c1          | f        | f       | t      |        |                 |                       |                           | f    | f           | f          |           | {autosummarize=true,pages_per_range=2}|     int4_ops      |     public        |        t        |                | f  | fast
+users           | idx2            | btree       |             | f        | f       | f      |        |                 |                       | ((c * 2))                 | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx2            | btree       | c1          | f        | f       | f      |        |                 |                       | c                         | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx2            | btree       | id          | f        | f       | f      |        |                 |                       | d                         | f    | f           | t          |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx2            | btree       | c1          | t        | f       | f      |        |                 |                       | c                         |      |             |            |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | idx2            | btree       | parent_id   | t        | f       | f      |        |                 |                       | d                         |      |             |            |           |                                       |     int4_ops      |     public        |        t        |                | f  | 
+users           | dep_other_ns    | vec         | c1          | f        | f       | f      |        |                 |                       | c1                        |      |             |            |           |                                       |     vec_ops       |     unknown_ns    |        f        | {siglen=1}     | f  | 
+users           | tsx             | gist        | ts          | f        | f       | f      |        |                 |                       | ts                        |      |             |            |           |                                       |     tsvector_ops  |     pg_catalog    |        f        | {siglen=1}     | f  | 
 `))
 				m.noFKs()
 				m.noChecks()
@@ -217,7 +219,7 @@ users           | tsx             | gist        | ts          | f        | f
 					{Name: "t1_c1_key", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &Constraint{N: "name", T: "u"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1], Desc: true, Attrs: []schema.Attr{&IndexColumnProperty{NullsFirst: true}}}}},
 					{Name: "idx4", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
 					{Name: "idx5", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}, {SeqNo: 2, X: &schema.RawExpr{X: `coalesce(parent_id, 0)`}}}},
-					{Name: "idx6", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "brin"}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}}},
+					{Name: "idx6", Unique: true, Table: t, Attrs: []schema.Attr{&IndexType{T: "brin"}, &IndexStorageParams{AutoSummarize: true, PagesPerRange: 2}, &Tablespace{V: "fast"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1]}}},
 					{Name: "idx2", Unique: false, Table: t, Attrs: []schema.Attr{&IndexType{T: "btree"}, &IndexInclude{Columns: columns[1:3]}}, Parts: []*schema.IndexPart{{SeqNo: 1, X: &schema.RawExpr{X: `((c * 2))`}, Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}, {SeqNo: 2, C: columns[1], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}, {SeqNo: 3, C: columns[0], Attrs: []schema.Attr{&IndexColumnProperty{NullsLast: true}}}}},
 					{Name: "dep_other_ns", Unique: false, Table: t, Attrs: []schema.Attr{&IndexType{T: "vec"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[1], Attrs: []schema.Attr{&IndexOpClass{Name: "unknown_ns.vec_ops", Params: []struct{ N, V string }{{N: "siglen", V: "1"}}}}}}},
 					{Name: "tsx", Unique: false, Table: t, Attrs: []schema.Attr{&IndexType{T: "gist"}}, Parts: []*schema.IndexPart{{SeqNo: 1, C: columns[3], Attrs: []schema.Attr{&IndexOpClass{Name: "tsvector_ops", Params: []struct{ N, V string }{{N: "siglen", V: "1"}}}}}}},
@@ -285,6 +287,45 @@ self_reference  | users      | uid         | public       | users
 				require.EqualValues(fks, t.ForeignKeys)
 			},
 		},
+		{
+			name: "fks delete columns",
+			before: func(m mock) {
+				m.noEnums()
+				m.tableExists("public", "posts", true)
+				m.ExpectQuery(queryColumns).
+					WithArgs("public", "posts").
+					WillReturnRows(sqltest.Rows(`
+table_name | column_name |      data_type      | formatted | is_nullable |         column_default          | character_maximum_length | numeric_precision | datetime_precision | numeric_scale | interval_type | character_set_name | collation_name | is_identity | identity_start | identity_increment |   identity_last  | identity_generation | generation_expression | comment | typtype | typelem | oid  | attnum 
+-----------+-------------+---------------------+-----------+-------------+---------------------------------+--------------------------+-------------------+--------------------+---------------+---------------+--------------------+----------------+-------------+----------------+--------------------+------------------+---------------------+-----------------------+---------+---------+---------+------+-----
+posts      | tenant_id   | integer             | int       | NO          |                                 |                          |                32 |                    |             0 |               |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |   20 |   
+posts      | author_id   | integer             | int       | YES         |                                 |                          |                32 |                    |             0 |               |                    |                | NO          |                |                    |                  |                     |                       |         | b       |         |   21 |   
+`))
+				m.noIndexes()
+				m.ExpectQuery(queryFKs).
+					WithArgs("public", "posts").
+					WillReturnRows(sqltest.Rows(`
+constraint_name | table_name | column_name | table_schema | referenced_table_name | referenced_column_name | referenced_schema_name | confupdtype | condeltype
+-----------------+------------+-------------+--------------+-----------------------+------------------------+------------------------+-------------+-------------
+author          | posts      | tenant_id   | public       | users                 | tenant_id              | public                 | a            | n
+author          | posts      | author_id   | public       | users                 | id                     | public                 | a            | n
+`))
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(fksDeleteColumnsQuery, "$2"))).
+					WithArgs("public", "posts").
+					WillReturnRows(sqltest.Rows(`
+constraint_name | table_name | columns
+-----------------+------------+---------------
+author          | posts      | ["author_id"]
+`))
+				m.noChecks()
+			},
+			expect: func(require *require.Assertions, t *schema.Table, err error) {
+				require.NoError(err)
+				require.Len(t.ForeignKeys, 1)
+				fk := t.ForeignKeys[0]
+				require.Equal(schema.SetNull, fk.OnDelete)
+				require.Equal([]schema.Attr{&DeleteSetColumns{Columns: t.Columns[1:]}}, fk.Attrs)
+			},
+		},
 		{
 			name: "check",
 			before: func(m mock) {
@@ -358,7 +399,13 @@ users        | users_check1       | (((c2 + c1) + c3) > 10) | c3          | {2,1
 				Mode: schema.InspectSchemas | schema.InspectTables,
 			})
 			require.NoError(t, err)
-			tt.expect(require.New(t), s.Tables[0], err)
+			// The checksum of the table is cached on inspection.
+			t1 := s.Tables[0]
+			c, ok := t1.Attrs[len(t1.Attrs)-1].(*schema.Checksum)
+			require.True(t, ok)
+			t1.Attrs = t1.Attrs[:len(t1.Attrs)-1]
+			require.Equal(t, schema.ChecksumOf(t1), c.V)
+			tt.expect(require.New(t), t1, err)
 		})
 	}
 }
@@ -380,11 +427,11 @@ func TestDriver_InspectPartitionedTable(t *testing.T) {
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(tablesQuery, "$1"))).
 		WithArgs("public").
 		WillReturnRows(sqltest.Rows(`
- oid   | table_schema | table_name  | comment | partition_attrs | partition_strategy |                  partition_exprs                   |                  extra                   
--------+--------------+-------------+---------+-----------------+--------------------+----------------------------------------------------+----------------------------------------------------
- 112  | public       | logs1       |         |                 |                     |                                                    |                                                    
- 113  | public       | logs2       |         | 1               | r                   |                                                    |                                                    
- 114  | public       | logs3       |         | 2 0 0           | l                   | (a + b), (a + (b * 2))                             |                              
+ oid   | table_schema | table_name  | comment | partition_attrs | partition_strategy |                  partition_exprs                   | unlogged | tablespace |                  extra                   
+-------+--------------+-------------+---------+-----------------+--------------------+----------------------------------------------------+----------+------------+----------------------------------------------------
+ 112  | public       | logs1       |         |                 |                     |                                                    | t        |            | {"owner":"admin","acl":["=r/admin"]}
+ 113  | public       | logs2       |         | 1               | r                   |                                                    | f        | fast       |                                                    
+ 114  | public       | logs3       |         | 2 0 0           | l                   | (a + b), (a + (b * 2))                             | f        |            |                              
 
 `))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, "$2, $3, $4"))).
@@ -404,6 +451,15 @@ logs3      | c5         | integer   | integer   | NO          |                |
 		WillReturnRows(sqlmock.NewRows([]string{"constraint_name", "table_name", "column_name", "referenced_table_name", "referenced_column_name", "referenced_table_schema", "update_rule", "delete_rule"}))
 	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(checksQuery, "$2, $3, $4"))).
 		WillReturnRows(sqlmock.NewRows([]string{"table_name", "constraint_name", "expression", "column_name", "column_indexes"}))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(partitionBoundsQuery, "$2, $3"))).
+		WithArgs("public", "logs2", "logs3").
+		WillReturnRows(sqltest.Rows(`
+ table_name | partition_name |        partition_bound
+------------+----------------+--------------------------------
+ logs2      | logs2_a        | FOR VALUES FROM (1) TO (10)
+ logs2      | logs2_def      | DEFAULT
+ logs3      | logs3_a        | FOR VALUES IN (1, 2)
+`))
 	s, err := drv.InspectSchema(context.Background(), "", &schema.InspectOptions{
 		Mode: schema.InspectSchemas | schema.InspectTables,
 	})
@@ -411,11 +467,16 @@ logs3      | c5         | integer   | integer   | NO          |                |
 
 	t1, ok := s.Table("logs1")
 	require.True(t, ok)
-	require.Equal(t, []schema.Attr{&OID{V: 112}}, t1.Attrs)
+	require.Equal(t, []schema.Attr{&OID{V: 112}, &Unlogged{V: true}, &Owner{Name: "admin"}, &ACL{Items: []string{"=r/admin"}}}, schema.RemoveAttr[*schema.Checksum](t1.Attrs))
 
 	t2, ok := s.Table("logs2")
 	require.True(t, ok)
-	require.Len(t, t2.Attrs, 2)
+	require.Len(t, schema.RemoveAttr[*schema.Checksum](t2.Attrs), 5)
+	require.Equal(t, &Tablespace{V: "fast"}, t2.Attrs[2])
+	require.Equal(t, []*PartitionBound{
+		{Name: "logs2_a", Values: "FROM (1) TO (10)"},
+		{Name: "logs2_def", Default: true},
+	}, partitionsOf(t2.Attrs))
 	key := t2.Attrs[1].(*Partition)
 	require.Equal(t, PartitionTypeRange, key.T)
 	require.Equal(t, []*PartitionPart{
@@ -424,7 +485,8 @@ logs3      | c5         | integer   | integer   | NO          |                |
 
 	t3, ok := s.Table("logs3")
 	require.True(t, ok)
-	require.Len(t, t3.Attrs, 2)
+	require.Len(t, schema.RemoveAttr[*schema.Checksum](t3.Attrs), 3)
+	require.Equal(t, []*PartitionBound{{Name: "logs3_a", Values: "IN (1, 2)"}}, partitionsOf(t3.Attrs))
 	key = t3.Attrs[1].(*Partition)
 	require.Equal(t, PartitionTypeList, key.T)
 	require.Equal(t, []*PartitionPart{
@@ -666,6 +728,50 @@ func TestDriver_Realm(t *testing.T) {
 	}(), realm)
 }
 
+func TestInspect_EachSchemaConcurrently(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	// Workers run concurrently, and their queries are not ordered.
+	m.MatchExpectationsInOrder(false)
+	m.ExpectQuery("^" + sqltest.Escape("SELECT current_setting('search_path')") + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"current_setting"}).AddRow("public"))
+	for range 2 {
+		m.ExpectQuery("^" + sqltest.Escape("SELECT current_setting('search_path'), set_config('search_path', $1, false)") + "$").
+			WithArgs("public").
+			WillReturnRows(sqlmock.NewRows([]string{"current_setting", "set_config"}).AddRow(`"$user", public`, "public"))
+		m.ExpectQuery("^" + sqltest.Escape("SELECT set_config('search_path', $1, false)") + "$").
+			WithArgs(`"$user", public`).
+			WillReturnRows(sqlmock.NewRows(nil))
+	}
+	var (
+		mu       sync.Mutex
+		names    []string
+		i        = &inspect{conn: &conn{ExecQuerier: db}}
+		schemas  = []*schema.Schema{schema.New("a"), schema.New("b"), schema.New("c")}
+		dedicate = true
+	)
+	err = i.eachSchema(context.Background(), schemas, 2, func(w *inspect, s *schema.Schema) error {
+		mu.Lock()
+		defer mu.Unlock()
+		names = append(names, s.Name)
+		dedicate = dedicate && w.ExecQuerier != i.ExecQuerier
+		return nil
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"a", "b", "c"}, names)
+	require.True(t, dedicate, "workers should use dedicated connections")
+	require.NoError(t, m.ExpectationsWereMet())
+
+	// Errors are propagated, and stop the inspection.
+	names = nil
+	err = i.eachSchema(context.Background(), schemas, 1, func(_ *inspect, s *schema.Schema) error {
+		names = append(names, s.Name)
+		return fmt.Errorf("failed %s", s.Name)
+	})
+	require.EqualError(t, err, "failed a")
+	require.Equal(t, []string{"a"}, names)
+}
+
 func TestInspectMode_InspectRealm(t *testing.T) {
 	db, m, err := sqlmock.New()
 	require.NoError(t, err)
@@ -733,9 +839,9 @@ func (m mock) version(version string) {
 }
 
 func (m mock) tableExists(schema, table string, exists bool) {
-	rows := sqlmock.NewRows([]string{"oid", "table_schema", "table_name", "table_comment", "partition_attrs", "partition_strategy", "partition_exprs", "row_security"})
+	rows := sqlmock.NewRows([]string{"oid", "table_schema", "table_name", "table_comment", "partition_attrs", "partition_strategy", "partition_exprs", "unlogged", "tablespace", "row_security"})
 	if exists {
-		rows.AddRow(nil, schema, table, nil, nil, nil, nil, nil)
+		rows.AddRow(nil, schema, table, nil, nil, nil, nil, nil, nil, nil)
 	}
 	m.ExpectQuery(queryTables).
 		WithArgs(schema).
@@ -761,3 +867,276 @@ func (m mock) noEnums() {
 	m.ExpectQuery(queryEnums).
 		WillReturnRows(sqlmock.NewRows([]string{"schema_name", "enum_name", "comment", "enum_type", "enum_value"}))
 }
+
+func TestDriver_InspectRealmObjects(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(languagesQuery)).
+		WillReturnRows(sqltest.Rows(`
+ language_name | trusted | handler               | inline | validator          | comment
+---------------+---------+-----------------------+--------+--------------------+---------
+ plperl        | t       | nil                   | nil    | nil                | nil
+ plsample      | f       | plsample_call_handler | nil    | plsample_validator | sample
+`))
+	mk.ExpectQuery(sqltest.Escape(castsQuery)).
+		WillReturnRows(sqltest.Rows(`
+ source      | target      | method | context | func                      | comment
+-------------+-------------+--------+---------+---------------------------+---------
+ public.mood | text        | i      | a       | nil                       | nil
+ text        | public.mood | f      | e       | public.text_to_mood(text) | to mood
+`))
+	mk.ExpectQuery(sqltest.Escape(databaseQuery)).
+		WillReturnRows(sqltest.Rows(`
+ database_name | comment | setting
+---------------+---------+--------------------------------
+ app           | app db  | search_path="$user", public
+ app           | app db  | timezone=UTC
+`))
+	r := schema.NewRealm()
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectRealmObjects(context.Background(), r, nil))
+	require.Equal(t, []schema.Object{
+		&Language{Name: "plperl", Trusted: true},
+		&Language{Name: "plsample", Handler: "plsample_call_handler", Validator: "plsample_validator", Attrs: []schema.Attr{&schema.Comment{Text: "sample"}}},
+		&Cast{Source: "public.mood", Target: "text", Method: CastMethodInOut, Context: CastContextAssignment},
+		&Cast{Source: "text", Target: "public.mood", Method: CastMethodFunc, Func: "public.text_to_mood(text)", Context: CastContextExplicit, Attrs: []schema.Attr{&schema.Comment{Text: "to mood"}}},
+		&Database{
+			Name: "app",
+			Settings: []*DatabaseSetting{
+				{Name: "search_path", Value: `"$user", public`},
+				{Name: "timezone", Value: "UTC"},
+			},
+			Attrs: []schema.Attr{&schema.Comment{Text: "app db"}},
+		},
+	}, r.Objects)
+
+	// Databases without comment and settings are ignored.
+	mk.ExpectQuery(sqltest.Escape(languagesQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"language_name", "trusted", "handler", "inline", "validator", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(castsQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"source", "target", "method", "context", "func", "comment"}))
+	mk.ExpectQuery(sqltest.Escape(databaseQuery)).
+		WillReturnRows(sqlmock.NewRows([]string{"database_name", "comment", "setting"}).AddRow("app", nil, nil))
+	r = schema.NewRealm()
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectRealmObjects(context.Background(), r, nil))
+	require.Empty(t, r.Objects)
+}
+
+func TestDriver_InspectComposites(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(compositesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | type_id | type_name | field_name | field_type            | field_collation
+-------------+---------+-----------+------------+-----------------------+-----------------
+ public      | 16390   | address   | street     | text                  | C
+ public      | 16390   | address   | zip        | character varying(10) | nil
+ public      | 16390   | address   | status     | status                | nil
+ public      | 16395   | person    | name       | text                  | nil
+ public      | 16395   | person    | addresses  | address[]             | nil
+`))
+	var (
+		status = &schema.EnumType{T: "status", Values: []string{"active"}}
+		s      = schema.New("public").AddObjects(status)
+		r      = schema.NewRealm(s)
+	)
+	status.Schema = s
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectComposites(context.Background(), r))
+	require.Len(t, s.Objects, 3)
+	addr, person := s.Objects[1].(*CompositeType), s.Objects[2].(*CompositeType)
+	require.Equal(t, "address", addr.T)
+	require.Equal(t, s, addr.Schema)
+	require.Equal(t, []schema.Object{status}, addr.Deps)
+	require.Len(t, addr.Fields, 3)
+	require.Equal(t, &schema.StringType{T: "text"}, addr.Fields[0].Type.Type)
+	require.Equal(t, []schema.Attr{&schema.Collation{V: "C"}}, addr.Fields[0].Attrs)
+	require.Equal(t, &schema.StringType{T: "character varying", Size: 10}, addr.Fields[1].Type.Type)
+	require.Equal(t, status, addr.Fields[2].Type.Type)
+	require.Equal(t, "person", person.T)
+	require.Equal(t, []schema.Object{addr}, person.Deps)
+	require.Equal(t, &ArrayType{Type: addr, T: "address[]"}, person.Fields[1].Type.Type)
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_InspectMaterializedViews(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(materializedQuery, "$1, $2"))).
+		WithArgs("public", "other").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | view_name | definition                      | comment  | populated | attrs
+-------------+-----------+---------------------------------+----------+-----------+--------------------------------------------------------------
+ public      | m1        |  SELECT users.id FROM users;    | counters | t         | {"owner":"admin","acl":null}
+ public      | m2        |  SELECT users.name FROM users;  | nil      | f         | {"owner":"admin","acl":["admin=arwdDxt/admin","bob=r*/admin"]}
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(materializedColumnsQuery, "$2, $3"))).
+		WithArgs("public", "m1", "m2").
+		WillReturnRows(sqltest.Rows(`
+ view_name | column_name | data_type         | comment
+-----------+-------------+-------------------+---------
+ m1        | id          | bigint            | user id
+ m2        | name        | character varying | nil
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(indexesAbove15, "$2, $3"))).
+		WithArgs("public", "m1", "m2").
+		WillReturnRows(sqltest.Rows(`
+ table_name | index_name | index_type | column_name | included | primary | unique | opexpr | constraints | predicate | expression | desc | nulls_first | nulls_last | comment | options | opclass_name | opclass_schema | opclass_default | opclass_params | indnullsnotdistinct | tablespace
+------------+------------+------------+-------------+----------+---------+--------+--------+-------------+-----------+------------+------+-------------+------------+---------+---------+--------------+----------------+-----------------+----------------+---------------------+-----------
+ m1         | m1_id      | btree      | id          | f        | f       | t      |        |             |           | id         | f    | f           | f          |         |         | int8_ops     | pg_catalog     | t               |                | f                   |
+`))
+	var (
+		public = schema.New("public")
+		r      = schema.NewRealm(public, schema.New("other"))
+	)
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectViews(context.Background(), r, nil))
+	require.NoError(t, m.ExpectationsWereMet())
+	require.Len(t, public.Views, 2)
+	require.Empty(t, r.Schemas[1].Views)
+
+	m1, ok := public.Materialized("m1")
+	require.True(t, ok)
+	require.Equal(t, "SELECT users.id FROM users", m1.Def)
+	require.Equal(t, []schema.Attr{&schema.Materialized{}, &schema.Comment{Text: "counters"}, &Owner{Name: "admin"}}, m1.Attrs)
+	require.Len(t, m1.Columns, 1)
+	require.Equal(t, "id", m1.Columns[0].Name)
+	require.Equal(t, &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: TypeBigInt}, Null: true}, m1.Columns[0].Type)
+	require.Equal(t, []schema.Attr{&schema.Comment{Text: "user id"}}, m1.Columns[0].Attrs)
+	require.Len(t, m1.Indexes, 1)
+	require.Equal(t, "m1_id", m1.Indexes[0].Name)
+	require.True(t, m1.Indexes[0].Unique)
+	require.Equal(t, m1.Columns[0], m1.Indexes[0].Parts[0].C)
+
+	m2, ok := public.Materialized("m2")
+	require.True(t, ok)
+	require.True(t, sqlx.Has(m2.Attrs, &WithNoData{}))
+	require.Empty(t, m2.Indexes)
+	acl := &ACL{}
+	require.True(t, sqlx.Has(m2.Attrs, acl))
+	require.Equal(t, []string{"admin=arwdDxt/admin", "bob=r*/admin"}, acl.Items)
+}
+
+func TestDriver_InspectStatistics(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("140000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(statisticsQuery, "array_to_json(pg_catalog.pg_get_statisticsobjdef_expressions(s.oid))", "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | statistics_name | table_schema | table_name | kinds   | columns                     | exprs            | comment | owner
+-------------+-----------------+--------------+------------+---------+-----------------------------+------------------+---------+-------
+ public      | users_email     | public       | users      | d,f,m,e | ["first_name"]              | ["lower(email)"] | nil     | nil
+ public      | users_names     | public       | users      | d,f     | ["first_name", "last_name"] | nil              | names   | admin
+ public      | pets_names      | public       | pets       | d,f     | ["name", "owner"]           | nil              | nil     | nil
+`))
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").
+			AddColumns(
+				schema.NewStringColumn("first_name", "text"),
+				schema.NewStringColumn("last_name", "text"),
+			)
+		r = schema.NewRealm(s.AddTables(usr))
+	)
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectStatistics(context.Background(), r))
+	require.Equal(t, []schema.Object{
+		&Statistics{Name: "users_email", Schema: s, T: usr, Columns: usr.Columns[:1], Exprs: []string{"lower(email)"}},
+		&Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns, Kinds: []string{StatisticsKindNDistinct, StatisticsKindDependencies}, Attrs: []schema.Attr{&schema.Comment{Text: "names"}, &Owner{Name: "admin"}}},
+	}, s.Objects)
+
+	// Schemas without tables are skipped.
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectStatistics(context.Background(), schema.NewRealm(schema.New("public"))))
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_InspectPolicies(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(rowSecurityQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | enabled | enforced
+-------------+------------+---------+----------
+ public      | users      | t       | t
+ public      | pets       | t       | f
+`))
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(policiesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | table_name | policy_name | permissive  | cmd    | roles             | qual              | with_check
+-------------+------------+-------------+-------------+--------+-------------------+-------------------+------------
+ public      | users      | tenant      | PERMISSIVE  | ALL    | ["public"]        | (tenant_id = 1)   | nil
+ public      | users      | owner       | RESTRICTIVE | UPDATE | ["app", "admin"]  | (id > 0)          | (id > 0)
+ public      | pets       | owner       | PERMISSIVE  | ALL    | ["public"]        | nil               | nil
+`))
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"))
+		r   = schema.NewRealm(s.AddTables(usr))
+	)
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectPolicies(context.Background(), r))
+	require.Equal(t, []schema.Attr{&RowSecurity{Enabled: true, Enforced: true}}, usr.Attrs)
+	require.Equal(t, []schema.Object{
+		&Policy{Name: "tenant", T: usr, Using: "(tenant_id = 1)"},
+		&Policy{Name: "owner", T: usr, As: PolicyAsRestrictive, For: PolicyForUpdate, To: []string{"app", "admin"}, Using: "(id > 0)", Check: "(id > 0)"},
+	}, s.Objects)
+
+	// Schemas without tables are skipped.
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectPolicies(context.Background(), schema.NewRealm(schema.New("public"))))
+	require.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestDriver_InspectSequences(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mk := mock{m}
+	mk.version("150000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	mk.ExpectQuery(sqltest.Escape(fmt.Sprintf(sequencesQuery, "$1"))).
+		WithArgs("public").
+		WillReturnRows(sqltest.Rows(`
+ schema_name | sequence_name | data_type | seqstart | seqincrement | seqmin | seqmax     | seqcache | seqcycle | last_value | owner_schema | owner_table | owner_column | comment
+-------------+---------------+-----------+----------+--------------+--------+------------+----------+----------+------------+--------------+-------------+--------------+---------
+ public      | orders_seq    | integer   | 100      | 1            | 1      | 2147483647 | 10       | f        | 120        | public       | orders      | id           | order numbers
+ public      | pets_id_seq   | integer   | 1        | 1            | 1      | 2147483647 | 1        | f        | nil        | public       | pets        | id           | nil
+ public      | users_id_seq  | integer   | 1        | 1            | 1      | 2147483647 | 1        | f        | nil        | public       | users       | id           | nil
+ public      | codes         | bigint    | -1       | -1           | -10    | -1         | 1        | t        | nil        | nil          | nil         | nil          | nil
+`))
+	var (
+		s      = schema.New("public")
+		orders = schema.NewTable("orders").AddColumns(schema.NewIntColumn("id", "int"))
+		users  = schema.NewTable("users").AddColumns(schema.NewColumn("id").SetType(&SerialType{T: TypeSerial}))
+		r      = schema.NewRealm(s.AddTables(orders, users))
+	)
+	require.NoError(t, drv.(*Driver).Inspector.(*inspect).inspectSequences(context.Background(), r))
+	var (
+		one, maxV, minV, minusOne = int64(1), int64(math.MaxInt32), int64(-10), int64(-1)
+		seq                       = &Sequence{Name: "orders_seq", Schema: s, Type: &schema.IntegerType{T: TypeInteger}, Start: 100, Increment: 1, Min: &one, Max: &maxV, Cache: 10, Last: 120, Attrs: []schema.Attr{&schema.Comment{Text: "order numbers"}}}
+	)
+	seq.Owner.T, seq.Owner.C = orders, orders.Columns[0]
+	// Sequences of serial columns, or of tables that were not inspected, are skipped.
+	require.Equal(t, []schema.Object{
+		seq,
+		&Sequence{Name: "codes", Schema: s, Type: &schema.IntegerType{T: TypeBigInt}, Start: -1, Increment: -1, Min: &minV, Max: &minusOne, Cache: 1, Cycle: true},
+	}, s.Objects)
+	require.NoError(t, m.ExpectationsWereMet())
+}
@@ -0,0 +1,269 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+)
+
+// Units of partition intervals and retention periods.
+const (
+	unitHour  = "hour"
+	unitDay   = "day"
+	unitWeek  = "week"
+	unitMonth = "month"
+	unitYear  = "year"
+)
+
+// period is a parsed partition interval or retention period. e.g., "3 months".
+type period struct {
+	n    int
+	unit string
+}
+
+// parsePeriod parses periods in the format of "<n> <unit>", where unit
+// is one of: hour, day, week, month or year (optionally plural).
+func parsePeriod(s string) (*period, error) {
+	parts := strings.Fields(strings.ToLower(s))
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid period %q, expect format: <n> <unit>", s)
+	}
+	n, err := strconv.Atoi(parts[0])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("invalid period %q, expect a positive number of units", s)
+	}
+	switch u := strings.TrimSuffix(parts[1], "s"); u {
+	case unitHour, unitDay, unitWeek, unitMonth, unitYear:
+		return &period{n: n, unit: u}, nil
+	default:
+		return nil, fmt.Errorf("invalid period %q, unit must be one of: hour, day, week, month or year", s)
+	}
+}
+
+// add returns the given time with k periods added to it.
+func (p *period) add(t time.Time, k int) time.Time {
+	switch n := p.n * k; p.unit {
+	case unitHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case unitDay:
+		return t.AddDate(0, 0, n)
+	case unitWeek:
+		return t.AddDate(0, 0, 7*n)
+	case unitMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(n, 0, 0)
+	}
+}
+
+// floor returns the start of the period the given time belongs to. Periods are aligned
+// to fixed points in time (e.g., the Unix epoch), to ensure that periods of multiple
+// units (e.g., "3 months") have the same boundaries regardless of when they are computed.
+func (p *period) floor(t time.Time) time.Time {
+	t = t.UTC()
+	switch p.unit {
+	case unitHour:
+		h := t.Unix() / 3600
+		return time.Unix((h-h%int64(p.n))*3600, 0).UTC()
+	case unitDay:
+		d := t.Unix() / 86400
+		return time.Unix((d-d%int64(p.n))*86400, 0).UTC()
+	case unitWeek:
+		// The Unix epoch is a Thursday, and weeks are aligned to Monday (1970-01-05).
+		w := (t.Unix() - 4*86400) / (7 * 86400)
+		return time.Unix((w-w%int64(p.n))*7*86400+4*86400, 0).UTC()
+	case unitMonth:
+		m := t.Year()*12 + int(t.Month()) - 1
+		m -= m % p.n
+		return time.Date(m/12, time.Month(m%12+1), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		y := t.Year() - t.Year()%p.n
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// layout returns the layout of the partition bounds and the partition name suffix.
+func (p *period) layout() (bound, suffix string) {
+	if p.unit == unitHour {
+		return "2006-01-02 15:04:05", "2006010215"
+	}
+	return "2006-01-02", "20060102"
+}
+
+// partition describes an existing partition of a table.
+type partition struct {
+	name     string
+	from, to time.Time
+}
+
+// MaintainPartitions plans the maintenance of the partitions of the RANGE-partitioned tables
+// in the given realm that define a PartitionMaintenance policy. Partitions are created ahead of
+// time for the current period and the number of periods set by the policy, and partitions that
+// ended before the retention period are detached and dropped. Partitions are named after their
+// parent table and the start of their range, e.g., "events_p20240101".
+func (d *Driver) MaintainPartitions(ctx context.Context, r *schema.Realm, now time.Time) (*migrate.Plan, error) {
+	plan := &migrate.Plan{Name: "maintain_partitions", Transactional: true, Reversible: true}
+	for _, s := range r.Schemas {
+		for _, t := range s.Tables {
+			var m PartitionMaintenance
+			if !sqlx.Has(t.Attrs, &m) {
+				continue
+			}
+			changes, err := d.maintainPartitions(ctx, t, &m, now)
+			if err != nil {
+				return nil, err
+			}
+			plan.Changes = append(plan.Changes, changes...)
+		}
+	}
+	for _, c := range plan.Changes {
+		if c.Reverse == nil {
+			plan.Reversible = false
+		}
+	}
+	return plan, nil
+}
+
+func (d *Driver) maintainPartitions(ctx context.Context, t *schema.Table, m *PartitionMaintenance, now time.Time) ([]*migrate.Change, error) {
+	interval, err := parsePeriod(m.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: table %q: %w", t.Name, err)
+	}
+	parts, err := d.partitions(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		changes        []*migrate.Change
+		start          = interval.floor(now)
+		layout, suffix = interval.layout()
+		build          = func(phrases ...string) *sqlx.Builder {
+			return d.StmtBuilder(migrate.PlanOptions{}).P(phrases...)
+		}
+		child = func(name string) *schema.Table {
+			return schema.NewTable(name).SetSchema(t.Schema)
+		}
+		bounds = func(from, to time.Time) string {
+			return fmt.Sprintf("FOR VALUES FROM ('%s') TO ('%s')", from.Format(layout), to.Format(layout))
+		}
+	)
+	for k := 0; k <= m.Premake; k++ {
+		from, to := interval.add(start, k), interval.add(start, k+1)
+		// Skip ranges that are already covered (or overlapped) by existing partitions.
+		if covered(parts, from, to) {
+			continue
+		}
+		p := child(fmt.Sprintf("%s_p%s", t.Name, from.Format(suffix)))
+		changes = append(changes, &migrate.Change{
+			Cmd:     build("CREATE TABLE").Table(p).P("PARTITION OF").Table(t).P(bounds(from, to)).String(),
+			Comment: fmt.Sprintf("create partition %q of table %q", p.Name, t.Name),
+			Reverse: build("DROP TABLE").Table(p).String(),
+		})
+	}
+	if m.Retention != "" {
+		retention, err := parsePeriod(m.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("postgres: table %q: %w", t.Name, err)
+		}
+		cutoff := retention.add(start, -1)
+		for _, p := range parts {
+			if p.to.After(cutoff) {
+				continue
+			}
+			c := child(p.name)
+			changes = append(changes,
+				&migrate.Change{
+					Cmd:     build("ALTER TABLE").Table(t).P("DETACH PARTITION").Table(c).String(),
+					Comment: fmt.Sprintf("detach expired partition %q from table %q", p.name, t.Name),
+					Reverse: build("ALTER TABLE").Table(t).P("ATTACH PARTITION").Table(c).P(bounds(p.from, p.to)).String(),
+				},
+				&migrate.Change{
+					Cmd:     build("DROP TABLE").Table(c).String(),
+					Comment: fmt.Sprintf("drop expired partition %q", p.name),
+				},
+			)
+		}
+	}
+	return changes, nil
+}
+
+// covered reports if the given range overlaps one of the partitions.
+func covered(parts []*partition, from, to time.Time) bool {
+	for _, p := range parts {
+		if p.from.Before(to) && p.to.After(from) {
+			return true
+		}
+	}
+	return false
+}
+
+// reBounds matches the bounds of RANGE partitions with a single key.
+var reBounds = regexp.MustCompile(`^FOR VALUES FROM \('([^']+)'(?:::[\w ]+)?\) TO \('([^']+)'(?:::[\w ]+)?\)$`)
+
+// partitions returns the partitions of the given table, ordered by their range.
+// Partitions with bounds that are not time ranges (e.g., DEFAULT) are skipped.
+func (d *Driver) partitions(ctx context.Context, t *schema.Table) ([]*partition, error) {
+	ns := d.schema
+	if t.Schema != nil && t.Schema.Name != "" {
+		ns = t.Schema.Name
+	}
+	rows, err := d.QueryContext(ctx, partitionsQuery, ns, t.Name)
+	if err != nil {
+		return nil, fmt.Errorf("postgres: querying partitions of table %q: %w", t.Name, err)
+	}
+	defer rows.Close()
+	var parts []*partition
+	for rows.Next() {
+		var name, bound string
+		if err := rows.Scan(&name, &bound); err != nil {
+			return nil, fmt.Errorf("postgres: scanning partitions of table %q: %w", t.Name, err)
+		}
+		m := reBounds.FindStringSubmatch(bound)
+		if m == nil {
+			continue
+		}
+		from, err1 := parseBound(m[1])
+		to, err2 := parseBound(m[2])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		parts = append(parts, &partition{name: name, from: from, to: to})
+	}
+	return parts, rows.Err()
+}
+
+// parseBound parses the time value of a partition bound.
+func parseBound(s string) (time.Time, error) {
+	for _, l := range []string{"2006-01-02 15:04:05-07", "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unexpected partition bound %q", s)
+}
+
+// Query to list the partitions of a table, and their bounds.
+const partitionsQuery = `
+SELECT
+	c.relname AS partition_name,
+	pg_catalog.pg_get_expr(c.relpartbound, c.oid) AS partition_bound
+FROM
+	pg_catalog.pg_inherits AS i
+	JOIN pg_catalog.pg_class AS c ON c.oid = i.inhrelid
+	JOIN pg_catalog.pg_class AS p ON p.oid = i.inhparent
+	JOIN pg_catalog.pg_namespace AS n ON n.oid = p.relnamespace
+WHERE
+	n.nspname = $1 AND p.relname = $2 AND c.relispartition
+ORDER BY
+	1`
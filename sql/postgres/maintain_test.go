@@ -0,0 +1,84 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"ariga.io/atlas/sql/internal/sqltest"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_MaintainPartitions(t *testing.T) {
+	db, m, err := sqlmock.New()
+	require.NoError(t, err)
+	mock{m}.version("130000")
+	drv, err := Open(db)
+	require.NoError(t, err)
+	events := schema.NewTable("events").
+		AddAttrs(&PartitionMaintenance{Interval: "1 month", Premake: 2, Retention: "3 months"})
+	schema.New("public").AddTables(events, schema.NewTable("users"))
+	m.ExpectQuery(sqltest.Escape(partitionsQuery)).
+		WithArgs("public", "events").
+		WillReturnRows(
+			sqlmock.NewRows([]string{"partition_name", "partition_bound"}).
+				AddRow("events_default", "DEFAULT").
+				AddRow("events_p20240101", "FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')").
+				AddRow("events_p20240201", "FOR VALUES FROM ('2024-02-01 00:00:00') TO ('2024-03-01 00:00:00')").
+				AddRow("events_p20240601", "FOR VALUES FROM ('2024-06-01 00:00:00') TO ('2024-07-01 00:00:00')"),
+		)
+	now := time.Date(2024, time.May, 17, 10, 30, 0, 0, time.UTC)
+	plan, err := drv.(*Driver).MaintainPartitions(context.Background(), schema.NewRealm(events.Schema), now)
+	require.NoError(t, err)
+	require.NoError(t, m.ExpectationsWereMet())
+	require.False(t, plan.Reversible)
+	require.Equal(t, []*migrate.Change{
+		{
+			Cmd:     `CREATE TABLE "public"."events_p20240501" PARTITION OF "public"."events" FOR VALUES FROM ('2024-05-01') TO ('2024-06-01')`,
+			Comment: `create partition "events_p20240501" of table "events"`,
+			Reverse: `DROP TABLE "public"."events_p20240501"`,
+		},
+		{
+			Cmd:     `CREATE TABLE "public"."events_p20240701" PARTITION OF "public"."events" FOR VALUES FROM ('2024-07-01') TO ('2024-08-01')`,
+			Comment: `create partition "events_p20240701" of table "events"`,
+			Reverse: `DROP TABLE "public"."events_p20240701"`,
+		},
+		{
+			Cmd:     `ALTER TABLE "public"."events" DETACH PARTITION "public"."events_p20240101"`,
+			Comment: `detach expired partition "events_p20240101" from table "events"`,
+			Reverse: `ALTER TABLE "public"."events" ATTACH PARTITION "public"."events_p20240101" FOR VALUES FROM ('2024-01-01') TO ('2024-02-01')`,
+		},
+		{
+			Cmd:     `DROP TABLE "public"."events_p20240101"`,
+			Comment: `drop expired partition "events_p20240101"`,
+		},
+	}, plan.Changes)
+}
+
+func TestPeriod_Floor(t *testing.T) {
+	now := time.Date(2024, time.May, 17, 10, 30, 0, 0, time.UTC)
+	for s, want := range map[string]time.Time{
+		"1 hour":   time.Date(2024, time.May, 17, 10, 0, 0, 0, time.UTC),
+		"1 day":    time.Date(2024, time.May, 17, 0, 0, 0, 0, time.UTC),
+		"1 week":   time.Date(2024, time.May, 13, 0, 0, 0, 0, time.UTC),
+		"1 month":  time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC),
+		"3 months": time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		"1 year":   time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		p, err := parsePeriod(s)
+		require.NoError(t, err)
+		require.Equal(t, want, p.floor(now), s)
+	}
+	_, err := parsePeriod("0 days")
+	require.Error(t, err)
+	_, err = parsePeriod("1 decade")
+	require.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -42,6 +43,9 @@ func (p *planApply) PlanChanges(ctx context.Context, name string, changes []sche
 	if err := verifyChanges(ctx, changes); err != nil {
 		return nil, err
 	}
+	if err := identChecker(s.PlanOptions).CheckChanges(changes); err != nil {
+		return nil, err
+	}
 	if err := s.plan(changes); err != nil {
 		return nil, err
 	}
@@ -65,6 +69,9 @@ type state struct {
 	*conn
 	migrate.Plan
 	migrate.PlanOptions
+	// Sequences that were created before their owner tables,
+	// and their ownership is set after the tables are created.
+	owned []*Sequence
 }
 
 // Exec executes the changes on the database. An error is returned
@@ -223,8 +230,12 @@ func (s *state) topLevel(changes []schema.Change) ([]schema.Change, error) {
 func (s *state) addTable(add *schema.AddTable) error {
 	var (
 		errs []string
-		b    = s.Build("CREATE TABLE")
+		b    = s.Build("CREATE")
 	)
+	if unlogged(add.T.Attrs) {
+		b.P("UNLOGGED")
+	}
+	b.P("TABLE")
 	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
 		b.P("IF NOT EXISTS")
 	}
@@ -253,7 +264,9 @@ func (s *state) addTable(add *schema.AddTable) error {
 		}
 		if len(add.T.ForeignKeys) > 0 {
 			b.Comma()
-			s.fks(b, add.T.ForeignKeys...)
+			if err := s.fks(b, add.T.ForeignKeys...); err != nil {
+				errs = append(errs, err.Error())
+			}
 		}
 		for _, attr := range add.T.Attrs {
 			if c, ok := attr.(*schema.Check); ok {
@@ -269,6 +282,9 @@ func (s *state) addTable(add *schema.AddTable) error {
 		}
 		b.P(s)
 	}
+	if ts := tablespace(add.T.Attrs); ts != "" {
+		b.P("TABLESPACE").Ident(ts)
+	}
 	if len(errs) > 0 {
 		return fmt.Errorf("create table %q: %s", add.T.Name, strings.Join(errs, ", "))
 	}
@@ -288,11 +304,70 @@ func (s *state) addTable(add *schema.AddTable) error {
 			}
 		}
 	}
+	for _, b := range partitionsOf(add.T.Attrs) {
+		s.append(s.createPartition(add, add.T, b))
+	}
 	s.addComments(add, add.T)
+	s.ownSequences(add, add.T)
+	if r := (RowSecurity{}); sqlx.Has(add.T.Attrs, &r) && (r.Enabled || r.Enforced) {
+		b := s.Build("ALTER TABLE").Table(add.T)
+		s.append(&migrate.Change{
+			Cmd:     rowSecurity(b.Clone(), &RowSecurity{}, &r).String(),
+			Source:  add,
+			Comment: fmt.Sprintf("enable row-level security on %q table", add.T.Name),
+			Reverse: rowSecurity(b.Clone(), &r, &RowSecurity{}).String(),
+		})
+	}
 	s.addTableAttrs(add)
 	return nil
 }
 
+// createPartition returns the change for creating a partition of the given table.
+func (s *state) createPartition(src schema.Change, t *schema.Table, b *PartitionBound) *migrate.Change {
+	p := partitionTable(t, b)
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     s.Build("CREATE TABLE").Table(p).P("PARTITION OF").Table(t).P(boundClause(b)).String(),
+		Reverse: s.Build("DROP TABLE").Table(p).String(),
+		Comment: fmt.Sprintf("create partition %q of %q table", b.Name, t.Name),
+	}
+}
+
+// attachPartition returns the change for attaching a partition to the given table.
+func (s *state) attachPartition(src schema.Change, t *schema.Table, b *PartitionBound) *migrate.Change {
+	p := partitionTable(t, b)
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     s.Build("ALTER TABLE").Table(t).P("ATTACH PARTITION").Table(p).P(boundClause(b)).String(),
+		Reverse: s.Build("ALTER TABLE").Table(t).P("DETACH PARTITION").Table(p).String(),
+		Comment: fmt.Sprintf("attach partition %q to %q table", b.Name, t.Name),
+	}
+}
+
+// detachPartition returns the change for detaching a partition from the given table.
+func (s *state) detachPartition(src schema.Change, t *schema.Table, b *PartitionBound) *migrate.Change {
+	p := partitionTable(t, b)
+	return &migrate.Change{
+		Source:  src,
+		Cmd:     s.Build("ALTER TABLE").Table(t).P("DETACH PARTITION").Table(p).String(),
+		Reverse: s.Build("ALTER TABLE").Table(t).P("ATTACH PARTITION").Table(p).P(boundClause(b)).String(),
+		Comment: fmt.Sprintf("detach partition %q from %q table", b.Name, t.Name),
+	}
+}
+
+// partitionTable returns the table that represents the given partition of t.
+func partitionTable(t *schema.Table, b *PartitionBound) *schema.Table {
+	return schema.NewTable(b.Name).SetSchema(t.Schema)
+}
+
+// boundClause returns the bound clause of the given partition.
+func boundClause(b *PartitionBound) string {
+	if b.Default {
+		return "DEFAULT"
+	}
+	return "FOR VALUES " + b.Values
+}
+
 // dropTable builds and executes the query for dropping a table from a schema.
 func (s *state) dropTable(drop *schema.DropTable) error {
 	cmd := &changeGroup{}
@@ -303,6 +378,11 @@ func (s *state) dropTable(drop *schema.DropTable) error {
 	if err := rs.addTable(&schema.AddTable{T: drop.T}); err != nil {
 		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
 	}
+	access, err := rs.accessChanges(drop, "TABLE", drop.T.Attrs, func(b *sqlx.Builder) *sqlx.Builder { return b.Table(drop.T) })
+	if err != nil {
+		return fmt.Errorf("calculate reverse for drop table %q: %w", drop.T.Name, err)
+	}
+	rs.append(access...)
 	b := s.Build("DROP TABLE")
 	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
 		b.P("IF EXISTS")
@@ -343,6 +423,14 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 	for _, change := range skipAutoChanges(modify.Changes) {
 		switch change := change.(type) {
 		case *schema.ModifyAttr:
+			// Changing the bound of a partition requires detaching it and attaching it back.
+			if to, ok := change.To.(*PartitionBound); ok {
+				changes = append(changes,
+					s.detachPartition(modify, modify.T, change.From.(*PartitionBound)),
+					s.attachPartition(modify, modify.T, to),
+				)
+				continue
+			}
 			if _, ok := change.From.(*schema.Comment); !ok {
 				alter = append(alter, change)
 				continue
@@ -354,6 +442,10 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			// Comments are not part of the ALTER command.
 			changes = append(changes, s.tableComment(modify, modify.T, to, from))
 		case *schema.AddAttr:
+			if b, ok := change.A.(*PartitionBound); ok {
+				changes = append(changes, s.createPartition(modify, modify.T, b))
+				continue
+			}
 			from, to, err := commentChange(change)
 			if err != nil {
 				return err
@@ -361,7 +453,15 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 			// Comments are not part of the ALTER command.
 			changes = append(changes, s.tableComment(modify, modify.T, to, from))
 		case *schema.DropAttr:
-			return fmt.Errorf("unsupported change type: %T", change)
+			b, ok := change.A.(*PartitionBound)
+			if !ok {
+				return fmt.Errorf("unsupported change type: %T", change)
+			}
+			changes = append(changes, s.detachPartition(modify, modify.T, b), &migrate.Change{
+				Source:  modify,
+				Cmd:     s.Build("DROP TABLE").Table(partitionTable(modify.T, b)).String(),
+				Comment: fmt.Sprintf("drop partition %q of %q table", b.Name, modify.T.Name),
+			})
 		case *schema.AddIndex:
 			if c := (schema.Comment{}); sqlx.Has(change.I.Attrs, &c) {
 				changes = append(changes, s.indexComment(modify, modify.T, change.I, c.Text, ""))
@@ -386,6 +486,11 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 				dropI = append(dropI, change)
 			}
 		case *schema.ModifyPrimaryKey:
+			// Moving the index of the primary key to another tablespace does not require rebuilding it.
+			if change.Change == schema.ChangeAttr && !indexDefChanged(change.From.Attrs, change.To.Attrs) {
+				changes = append(changes, s.indexTablespace(modify, modify.T, pkName(modify.T, change.To), change.From, change.To))
+				continue
+			}
 			// Primary key modification needs to be split into "Drop" and "Add"
 			// because the new key may include columns that have not been added yet.
 			alter = append(alter, &schema.DropPrimaryKey{
@@ -410,6 +515,11 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 				alter = append(alter, addU)
 				continue
 			}
+			// Moving an index to another tablespace does not require rebuilding it.
+			if k == schema.ChangeAttr && !indexDefChanged(change.From.Attrs, change.To.Attrs) {
+				changes = append(changes, s.indexTablespace(modify, modify.T, change.To.Name, change.From, change.To))
+				continue
+			}
 			// Index (or constraint) modification requires rebuilding the index.
 			_, fromU := uniqueConst(change.From.Attrs)
 			_, fromE := excludeConst(change.From.Attrs)
@@ -485,6 +595,7 @@ func (s *state) modifyTable(modify *schema.ModifyTable) error {
 		return err
 	}
 	s.append(changes...)
+	s.ownSequences(modify, modify.T)
 	return nil
 }
 
@@ -585,7 +696,9 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 				b.P("DROP CONSTRAINT").Ident(pkName(t, change.P))
 				reverse = append(reverse, &schema.AddPrimaryKey{P: change.P})
 			case *schema.AddForeignKey:
-				s.fks(b.P("ADD"), change.F)
+				if err := s.fks(b.P("ADD"), change.F); err != nil {
+					return err
+				}
 				if sqlx.Has(change.Extra, &NotValid{}) {
 					b.P("NOT VALID")
 				}
@@ -629,7 +742,20 @@ func (s *state) alterTable(t *schema.Table, changes []schema.Change) error {
 					To:   change.From,
 				})
 			case *schema.ModifyAttr:
-				s.alterTableAttr(b, change)
+				switch a := change.To.(type) {
+				case *Unlogged:
+					if a.V {
+						b.P("SET UNLOGGED")
+					} else {
+						b.P("SET LOGGED")
+					}
+				case *Tablespace:
+					b.P("SET TABLESPACE").Ident(tablespaceOrDefault(a.V))
+				case *RowSecurity:
+					rowSecurity(b, change.From.(*RowSecurity), a)
+				default:
+					s.alterTableAttr(b, change)
+				}
 				reverse = append(reverse, &schema.ModifyAttr{
 					From: change.To,
 					To:   change.From,
@@ -834,6 +960,326 @@ func (s *state) renameTable(c *schema.RenameTable) {
 	})
 }
 
+// addView builds the statements for creating a materialized view, its indexes
+// and comments. Regular views are not supported by this version.
+func (s *state) addView(add *schema.AddView) error {
+	if !add.V.Materialized() {
+		return nil // unimplemented.
+	}
+	b := s.Build("CREATE MATERIALIZED VIEW")
+	if sqlx.Has(add.Extra, &schema.IfNotExists{}) {
+		b.P("IF NOT EXISTS")
+	}
+	b.View(add.V).P("AS", strings.TrimSuffix(strings.TrimSpace(add.V.Def), ";"))
+	if sqlx.Has(add.V.Attrs, &WithNoData{}) {
+		b.P("WITH NO DATA")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  add,
+		Comment: fmt.Sprintf("create %q materialized view", add.V.Name),
+		Reverse: s.Build("DROP MATERIALIZED VIEW").View(add.V).String(),
+	})
+	t := add.V.AsTable()
+	for _, idx := range add.V.Indexes {
+		if err := s.addIndexes(add, t, &schema.AddIndex{I: idx}); err != nil {
+			return err
+		}
+	}
+	var c schema.Comment
+	if sqlx.Has(add.V.Attrs, &c) && c.Text != "" {
+		s.append(s.viewComment(add, add.V, c.Text, ""))
+	}
+	for i := range add.V.Columns {
+		if sqlx.Has(add.V.Columns[i].Attrs, &c) && c.Text != "" {
+			s.append(s.columnComment(add, t, add.V.Columns[i], c.Text, ""))
+		}
+	}
+	for i := range add.V.Indexes {
+		if sqlx.Has(add.V.Indexes[i].Attrs, &c) && c.Text != "" {
+			s.append(s.indexComment(add, t, add.V.Indexes[i], c.Text, ""))
+		}
+	}
+	return nil
+}
+
+// dropView builds the statement for dropping a materialized view.
+func (s *state) dropView(drop *schema.DropView) error {
+	if !drop.V.Materialized() {
+		return nil // unimplemented.
+	}
+	rs := &state{conn: s.conn, PlanOptions: s.PlanOptions}
+	if err := rs.addView(&schema.AddView{V: drop.V}); err != nil {
+		return fmt.Errorf("calculate reverse for drop materialized view %q: %w", drop.V.Name, err)
+	}
+	access, err := rs.accessChanges(drop, "MATERIALIZED VIEW", drop.V.Attrs, func(b *sqlx.Builder) *sqlx.Builder { return b.View(drop.V) })
+	if err != nil {
+		return fmt.Errorf("calculate reverse for drop materialized view %q: %w", drop.V.Name, err)
+	}
+	rs.append(access...)
+	b := s.Build("DROP MATERIALIZED VIEW")
+	if sqlx.Has(drop.Extra, &schema.IfExists{}) {
+		b.P("IF EXISTS")
+	}
+	b.View(drop.V)
+	if sqlx.Has(drop.Extra, &Cascade{}) {
+		b.P("CASCADE")
+	}
+	s.append(&migrate.Change{
+		Cmd:     b.String(),
+		Source:  drop,
+		Comment: fmt.Sprintf("drop %q materialized view", drop.V.Name),
+		Reverse: func() any {
+			cmd := make([]string, len(rs.Changes))
+			for i, c := range rs.Changes {
+				cmd[i] = c.Cmd
+			}
+			if len(cmd) == 1 {
+				return cmd[0]
+			}
+			return cmd
+		}(),
+	})
+	return nil
+}
+
+// modifyView builds the statements that bring the materialized view into its modified
+// state. Since the query of a materialized view cannot be altered, changing it requires
+// recreating the view.
+func (s *state) modifyView(modify *schema.ModifyView) error {
+	if !modify.From.Materialized() || !modify.To.Materialized() {
+		return nil // unimplemented.
+	}
+	if sqlx.BodyDefChanged(modify.From.Def, modify.To.Def) {
+		if err := s.dropView(&schema.DropView{V: modify.From}); err != nil {
+			return err
+		}
+		if err := s.addView(&schema.AddView{V: modify.To}); err != nil {
+			return err
+		}
+		// The recreated view is owned by the role executing the migration, and
+		// loses its privileges. Hence, the inspected ones are restored.
+		access, err := s.accessChanges(modify, "MATERIALIZED VIEW", modify.From.Attrs, func(b *sqlx.Builder) *sqlx.Builder { return b.View(modify.To) })
+		if err != nil {
+			return err
+		}
+		s.append(access...)
+		return nil
+	}
+	fromT, toT := modify.From.AsTable(), modify.To.AsTable()
+	for _, change := range modify.Changes {
+		switch change := change.(type) {
+		case *schema.AddAttr, *schema.ModifyAttr:
+			from, to, err := commentChange(change)
+			if err != nil {
+				return err
+			}
+			s.append(s.viewComment(modify, modify.To, to, from))
+		case *schema.DropAttr:
+			if _, ok := change.A.(*WithNoData); !ok {
+				return fmt.Errorf("unexpected DropAttr.(%T) for materialized view %q", change.A, modify.To.Name)
+			}
+			b := s.Build("REFRESH MATERIALIZED VIEW").View(modify.To)
+			s.append(&migrate.Change{
+				Cmd:     b.String(),
+				Source:  modify,
+				Comment: fmt.Sprintf("populate %q materialized view", modify.To.Name),
+				Reverse: b.P("WITH NO DATA").String(),
+			})
+		case *schema.ModifyColumn:
+			from, to, err := commentChange(sqlx.CommentDiff(change.From.Attrs, change.To.Attrs))
+			if err != nil {
+				return err
+			}
+			s.append(s.columnComment(modify, toT, change.To, to, from))
+		case *schema.AddIndex:
+			if err := s.addIndexes(modify, toT, change); err != nil {
+				return err
+			}
+			if c := (schema.Comment{}); sqlx.Has(change.I.Attrs, &c) && c.Text != "" {
+				s.append(s.indexComment(modify, toT, change.I, c.Text, ""))
+			}
+		case *schema.DropIndex:
+			if err := s.dropIndexes(modify, fromT, change); err != nil {
+				return err
+			}
+		case *schema.ModifyIndex:
+			k := change.Change
+			if k.Is(schema.ChangeComment) {
+				from, to, err := commentChange(sqlx.CommentDiff(change.From.Attrs, change.To.Attrs))
+				if err != nil {
+					return err
+				}
+				s.append(s.indexComment(modify, toT, change.To, to, from))
+				// If only the comment of the index was changed.
+				if k &= ^schema.ChangeComment; k.Is(schema.NoChange) {
+					continue
+				}
+			}
+			// Index modification requires rebuilding the index.
+			if err := s.dropIndexes(modify, fromT, &schema.DropIndex{I: change.From}); err != nil {
+				return err
+			}
+			if err := s.addIndexes(modify, toT, &schema.AddIndex{I: change.To}); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported change type for materialized view %q: %T", modify.To.Name, change)
+		}
+	}
+	return nil
+}
+
+// accessChanges returns the changes for restoring the privileges and the owner of a recreated
+// relation, as captured by the inspection. The privileges of the owner are granted implicitly
+// when the relation is created, and the ownership is transferred after the other privileges
+// are granted, as it also transfers the grants made by the role executing the migration.
+func (s *state) accessChanges(src schema.Change, kind string, attrs []schema.Attr, rel func(*sqlx.Builder) *sqlx.Builder) ([]*migrate.Change, error) {
+	var (
+		o       Owner
+		acl     ACL
+		changes []*migrate.Change
+	)
+	hasO := sqlx.Has(attrs, &o)
+	sqlx.Has(attrs, &acl)
+	for _, item := range acl.Items {
+		a, err := parseACLItem(item)
+		if err != nil {
+			return nil, err
+		}
+		if hasO && a.grantee == o.Name {
+			continue
+		}
+		for _, g := range []struct {
+			privs  []string
+			suffix string
+		}{
+			{privs: a.privs},
+			{privs: a.grantable, suffix: "WITH GRANT OPTION"},
+		} {
+			if len(g.privs) == 0 {
+				continue
+			}
+			// Privileges on all kinds of relations are granted with the TABLE keyword.
+			grant, revoke := rel(s.Build("GRANT", strings.Join(g.privs, ", "), "ON TABLE")), rel(s.Build("REVOKE", strings.Join(g.privs, ", "), "ON TABLE"))
+			if a.grantee == "" {
+				grant.P("TO PUBLIC")
+				revoke.P("FROM PUBLIC")
+			} else {
+				grant.P("TO").Ident(a.grantee)
+				revoke.P("FROM").Ident(a.grantee)
+			}
+			if g.suffix != "" {
+				grant.P(g.suffix)
+			}
+			changes = append(changes, &migrate.Change{
+				Cmd:     grant.String(),
+				Source:  src,
+				Comment: fmt.Sprintf("restore privileges of %q", granteeName(a.grantee)),
+				Reverse: revoke.String(),
+			})
+		}
+	}
+	if hasO {
+		b := rel(s.Build("ALTER", kind)).P("OWNER TO").Ident(o.Name)
+		changes = append(changes, &migrate.Change{
+			Cmd:     b.String(),
+			Source:  src,
+			Comment: fmt.Sprintf("restore owner %q", o.Name),
+			// Reverting the change drops the relation, and recreates the original one.
+			Reverse: b.String(),
+		})
+	}
+	return changes, nil
+}
+
+// aclPrivileges maps the privilege abbreviations of relation ACLs to their names.
+var aclPrivileges = map[byte]string{
+	'r': "SELECT",
+	'a': "INSERT",
+	'w': "UPDATE",
+	'd': "DELETE",
+	'D': "TRUNCATE",
+	'x': "REFERENCES",
+	't': "TRIGGER",
+	'm': "MAINTAIN",
+}
+
+// aclItem is a parsed entry of an access control list.
+type aclItem struct {
+	grantee   string   // empty for PUBLIC.
+	privs     []string // privileges granted without grant option.
+	grantable []string // privileges granted with grant option.
+}
+
+// parseACLItem parses an ACL entry, formatted as "grantee=privileges/grantor".
+// For example, "bob=arw*/admin" grants SELECT and INSERT to bob, and UPDATE
+// with grant option.
+func parseACLItem(s string) (*aclItem, error) {
+	grantee, rest := aclRole(s)
+	if !strings.HasPrefix(rest, "=") {
+		return nil, fmt.Errorf("postgres: unexpected ACL item %q", s)
+	}
+	a := &aclItem{grantee: grantee}
+	privs, _, _ := strings.Cut(rest[1:], "/")
+	for i := 0; i < len(privs); i++ {
+		p, ok := aclPrivileges[privs[i]]
+		if !ok {
+			return nil, fmt.Errorf("postgres: unknown privilege %q in ACL item %q", privs[i], s)
+		}
+		if i+1 < len(privs) && privs[i+1] == '*' {
+			a.grantable = append(a.grantable, p)
+			i++
+		} else {
+			a.privs = append(a.privs, p)
+		}
+	}
+	return a, nil
+}
+
+// aclRole reads the role name at the start of an ACL entry, and returns it
+// along with the rest of the entry. Role names with special characters are
+// double-quoted by the database.
+func aclRole(s string) (string, string) {
+	if !strings.HasPrefix(s, `"`) {
+		if i := strings.IndexByte(s, '='); i != -1 {
+			return s[:i], s[i:]
+		}
+		return s, ""
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] != '"':
+			b.WriteByte(s[i])
+		case i+1 < len(s) && s[i+1] == '"':
+			b.WriteByte('"')
+			i++
+		default:
+			return b.String(), s[i+1:]
+		}
+	}
+	return b.String(), ""
+}
+
+// granteeName returns the display name of the given grantee.
+func granteeName(g string) string {
+	if g == "" {
+		return "PUBLIC"
+	}
+	return g
+}
+
+func (s *state) viewComment(src schema.Change, v *schema.View, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON MATERIALIZED VIEW").View(v).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to materialized view: %q", v.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
 func (s *state) addComments(src schema.Change, t *schema.Table) {
 	var c schema.Comment
 	if sqlx.Has(t.Attrs, &c) && c.Text != "" {
@@ -892,6 +1338,25 @@ func (s *state) indexComment(src schema.Change, t *schema.Table, idx *schema.Ind
 	}
 }
 
+// indexTablespace returns the change for moving the index to the tablespace of its desired state.
+func (s *state) indexTablespace(src schema.Change, t *schema.Table, name string, from, to *schema.Index) *migrate.Change {
+	b := s.Build("ALTER INDEX").SchemaResource(t.Schema, name).P("SET TABLESPACE")
+	return &migrate.Change{
+		Cmd:     b.Clone().Ident(tablespaceOrDefault(tablespace(to.Attrs))).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("move index %q of table %q to another tablespace", name, t.Name),
+		Reverse: b.Clone().Ident(tablespaceOrDefault(tablespace(from.Attrs))).String(),
+	}
+}
+
+// tablespaceOrDefault returns the given tablespace, or the default one if it is empty.
+func tablespaceOrDefault(ts string) string {
+	if ts == "" {
+		return defaultTablespace
+	}
+	return ts
+}
+
 func (s *state) dropIndexes(src schema.Change, t *schema.Table, drops ...*schema.DropIndex) error {
 	adds := make([]*schema.AddIndex, len(drops))
 	for i, d := range drops {
@@ -905,6 +1370,7 @@ func (s *state) dropIndexes(src schema.Change, t *schema.Table, drops ...*schema
 		s.append(&migrate.Change{
 			Cmd:     rs.Changes[i].Reverse.(string),
 			Source:  src,
+			NoTx:    rs.Changes[i].NoTx,
 			Comment: fmt.Sprintf("drop index %q from table: %q", add.I.Name, t.Name),
 			Reverse: rs.Changes[i].Cmd,
 		})
@@ -946,6 +1412,7 @@ func (s *state) alterEnum(modify *schema.ModifyObject) error {
 			}
 			s.append(&migrate.Change{
 				Cmd:     b.String(),
+				NoTx:    !s.conn.supportsEnumAddValueTx(),
 				Comment: fmt.Sprintf("add value to enum type: %q", from.T),
 			})
 		case ok && j == at:
@@ -957,42 +1424,1113 @@ func (s *state) alterEnum(modify *schema.ModifyObject) error {
 	return nil
 }
 
-func (s *state) addIndexes(src schema.Change, t *schema.Table, adds ...*schema.AddIndex) error {
-	for _, add := range adds {
-		b, idx := s.Build("CREATE"), add.I
-		if idx.Unique {
-			b.P("UNIQUE")
-		}
-		b.P("INDEX")
-		if sqlx.Has(add.Extra, &Concurrently{}) {
-			b.P("CONCURRENTLY")
-		}
-		if idx.Name != "" {
-			b.Ident(idx.Name)
-		}
-		b.P("ON").Table(t)
-		if err := s.index(b, idx); err != nil {
-			return err
-		}
+func (s *state) addLanguage(add *schema.AddObject, l *Language) {
+	create, drop := s.createDropLanguage(l, sqlx.Has(add.Extra, &schema.IfNotExists{}))
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create language %q", l.Name),
+	})
+	if c := (schema.Comment{}); sqlx.Has(l.Attrs, &c) && c.Text != "" {
+		s.append(s.languageComment(add, l, c.Text, ""))
+	}
+}
+
+func (s *state) dropLanguage(drop *schema.DropObject, l *Language) {
+	create, dropL := s.createDropLanguage(l, false)
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropL,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop language %q", l.Name),
+	})
+}
+
+func (s *state) alterLanguage(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Language)
+	to, ok2 := modify.To.(*Language)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	switch {
+	case !languageDefChanged(from, to):
+	// Languages with handlers can be replaced in place.
+	case from.Handler != "" && to.Handler != "":
+		create, _ := s.createDropLanguage(to, true)
+		reverse, _ := s.createDropLanguage(from, true)
 		s.append(&migrate.Change{
-			Cmd:     b.String(),
-			Source:  src,
-			Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
-			Reverse: func() string {
-				b := s.Build("DROP INDEX")
-				if sqlx.Has(add.Extra, &Concurrently{}) {
-					b.P("CONCURRENTLY")
-				}
-				// Unlike MySQL, the DROP command is not attached to ALTER TABLE.
-				// Therefore, we print indexes with their qualified name, because
-				// the connection that executes the statements may not be attached
-				// to this schema.
-				if t.Schema != nil {
-					b.WriteString(s.schemaPrefix(t.Schema))
-				}
-				b.Ident(idx.Name)
-				return b.String()
-			}(),
+			Source:  modify,
+			Cmd:     create,
+			Reverse: reverse,
+			Comment: fmt.Sprintf("replace language %q", to.Name),
+		})
+	default:
+		s.dropLanguage(&schema.DropObject{O: from}, from)
+		s.addLanguage(&schema.AddObject{O: to}, to)
+		return nil
+	}
+	if sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange {
+		var fromC, toC schema.Comment
+		sqlx.Has(from.Attrs, &fromC)
+		sqlx.Has(to.Attrs, &toC)
+		s.append(s.languageComment(modify, to, toC.Text, fromC.Text))
+	}
+	return nil
+}
+
+// createDropLanguage returns the CREATE and DROP statements of the given language.
+// Languages without a handler are packaged as extensions, and the CREATE LANGUAGE
+// statement is interpreted by the database as CREATE EXTENSION.
+func (s *state) createDropLanguage(l *Language, replace bool) (string, string) {
+	b := s.Build("CREATE")
+	if replace {
+		b.P("OR REPLACE")
+	}
+	if l.Handler == "" {
+		return b.P("LANGUAGE").Ident(l.Name).String(), s.Build("DROP EXTENSION").Ident(l.Name).String()
+	}
+	if l.Trusted {
+		b.P("TRUSTED")
+	}
+	b.P("LANGUAGE").Ident(l.Name).P("HANDLER", l.Handler)
+	if l.Inline != "" {
+		b.P("INLINE", l.Inline)
+	}
+	if l.Validator != "" {
+		b.P("VALIDATOR", l.Validator)
+	}
+	return b.String(), s.Build("DROP LANGUAGE").Ident(l.Name).String()
+}
+
+func (s *state) languageComment(src schema.Change, l *Language, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON LANGUAGE").Ident(l.Name).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to language: %q", l.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) addCast(add *schema.AddObject, c *Cast) {
+	create, drop := s.createDropCast(c)
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create cast from %q to %q", c.Source, c.Target),
+	})
+	if cm := (schema.Comment{}); sqlx.Has(c.Attrs, &cm) && cm.Text != "" {
+		s.append(s.castComment(add, c, cm.Text, ""))
+	}
+}
+
+func (s *state) dropCast(drop *schema.DropObject, c *Cast) {
+	create, dropC := s.createDropCast(c)
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropC,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop cast from %q to %q", c.Source, c.Target),
+	})
+}
+
+func (s *state) alterCast(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Cast)
+	to, ok2 := modify.To.(*Cast)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	// Casts cannot be altered, and are recreated instead.
+	if castDefChanged(from, to) {
+		s.dropCast(&schema.DropObject{O: from}, from)
+		s.addCast(&schema.AddObject{O: to}, to)
+		return nil
+	}
+	var fromC, toC schema.Comment
+	sqlx.Has(from.Attrs, &fromC)
+	sqlx.Has(to.Attrs, &toC)
+	s.append(s.castComment(modify, to, toC.Text, fromC.Text))
+	return nil
+}
+
+// createDropCast returns the CREATE and DROP statements of the given cast.
+func (s *state) createDropCast(c *Cast) (string, string) {
+	b := s.Build("CREATE CAST").Wrap(func(b *sqlx.Builder) {
+		b.P(c.Source, "AS", c.Target)
+	})
+	switch c.Method {
+	case CastMethodBinary:
+		b.P("WITHOUT FUNCTION")
+	case CastMethodInOut:
+		b.P("WITH INOUT")
+	default:
+		b.P("WITH FUNCTION", c.Func)
+	}
+	if ctx := castContext(c); ctx != CastContextExplicit {
+		b.P("AS", ctx)
+	}
+	return b.String(), s.Build("DROP CAST").Wrap(func(b *sqlx.Builder) {
+		b.P(c.Source, "AS", c.Target)
+	}).String()
+}
+
+func (s *state) castComment(src schema.Change, c *Cast, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON CAST").Wrap(func(b *sqlx.Builder) {
+		b.P(c.Source, "AS", c.Target)
+	}).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to cast from %q to %q", c.Source, c.Target),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+// addDatabase sets the settings and the comment of the database.
+func (s *state) addDatabase(add *schema.AddObject, d *Database) {
+	for _, st := range d.Settings {
+		s.append(s.databaseSetting(add, d.Name, st, nil))
+	}
+	if c := (schema.Comment{}); sqlx.Has(d.Attrs, &c) && c.Text != "" {
+		s.append(s.databaseComment(add, d.Name, c.Text, ""))
+	}
+}
+
+// dropDatabase resets the settings and the comment of the database,
+// as the database itself is not managed by the realm.
+func (s *state) dropDatabase(drop *schema.DropObject, d *Database) {
+	for _, st := range d.Settings {
+		s.append(s.databaseSetting(drop, d.Name, nil, st))
+	}
+	if c := (schema.Comment{}); sqlx.Has(d.Attrs, &c) && c.Text != "" {
+		s.append(s.databaseComment(drop, d.Name, "", c.Text))
+	}
+}
+
+func (s *state) alterDatabase(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Database)
+	to, ok2 := modify.To.(*Database)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	for _, st := range from.Settings {
+		if _, ok := to.Setting(st.Name); !ok {
+			s.append(s.databaseSetting(modify, to.Name, nil, st))
+		}
+	}
+	for _, st := range to.Settings {
+		if prev, ok := from.Setting(st.Name); !ok || prev.Value != st.Value {
+			s.append(s.databaseSetting(modify, to.Name, st, prev))
+		}
+	}
+	if sqlx.CommentChange(from.Attrs, to.Attrs) != schema.NoChange {
+		var fromC, toC schema.Comment
+		sqlx.Has(from.Attrs, &fromC)
+		sqlx.Has(to.Attrs, &toC)
+		s.append(s.databaseComment(modify, to.Name, toC.Text, fromC.Text))
+	}
+	return nil
+}
+
+// databaseSetting returns the change for setting the database parameter from
+// one value to the other. A nil setting means the parameter is reset.
+func (s *state) databaseSetting(src schema.Change, name string, to, from *DatabaseSetting) *migrate.Change {
+	param := to
+	if param == nil {
+		param = from
+	}
+	set := func(st *DatabaseSetting) string {
+		b := s.Build("ALTER DATABASE").Ident(name)
+		if st == nil {
+			return b.P("RESET", param.Name).String()
+		}
+		return b.P("SET", st.Name, "TO", databaseSettingValue(st)).String()
+	}
+	c := &migrate.Change{
+		Cmd:     set(to),
+		Source:  src,
+		Comment: fmt.Sprintf("set %q of database %q", param.Name, name),
+		Reverse: set(from),
+	}
+	if to == nil {
+		c.Comment = fmt.Sprintf("reset %q of database %q", param.Name, name)
+	}
+	return c
+}
+
+// databaseSettingValue returns the value of the setting, as it should be written in the
+// SET clause. Values of list parameters (e.g., search_path) are stored with their quoting
+// and are therefore written as is.
+func databaseSettingValue(st *DatabaseSetting) string {
+	switch strings.ToLower(st.Name) {
+	case "search_path", "temp_tablespaces", "session_preload_libraries", "local_preload_libraries", "shared_preload_libraries":
+		return st.Value
+	default:
+		return quote(st.Value)
+	}
+}
+
+func (s *state) databaseComment(src schema.Change, name, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON DATABASE").Ident(name).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to database: %q", name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) addStatistics(add *schema.AddObject, st *Statistics) {
+	create, drop := s.createDropStatistics(st)
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create statistics %q on table %q", st.Name, st.T.Name),
+	})
+	if c := (schema.Comment{}); sqlx.Has(st.Attrs, &c) && c.Text != "" {
+		s.append(s.statisticsComment(add, st, c.Text, ""))
+	}
+}
+
+func (s *state) dropStatistics(drop *schema.DropObject, st *Statistics) {
+	create, dropS := s.createDropStatistics(st)
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropS,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop statistics %q from table %q", st.Name, st.T.Name),
+	})
+}
+
+func (s *state) alterStatistics(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Statistics)
+	to, ok2 := modify.To.(*Statistics)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	// The definition of statistics cannot be altered, and they are recreated instead.
+	if s.statisticsDefChanged(from, to) {
+		s.dropStatistics(&schema.DropObject{O: from}, from)
+		// Recreated objects are owned by the role executing the migration. Hence,
+		// the inspected owner is restored on the new object, and on the old one in
+		// case the migration is reverted.
+		o := Owner{}
+		if !sqlx.Has(from.Attrs, &o) {
+			s.addStatistics(&schema.AddObject{O: to}, to)
+			return nil
+		}
+		drop := s.Changes[len(s.Changes)-1]
+		drop.Reverse = []string{drop.Reverse.(string), s.statisticsOwner(from, o.Name)}
+		s.addStatistics(&schema.AddObject{O: to}, to)
+		s.append(&migrate.Change{
+			Cmd:     s.statisticsOwner(to, o.Name),
+			Source:  modify,
+			Comment: fmt.Sprintf("restore owner of statistics %q", to.Name),
+			// Reverting the change drops the object, and recreates the original one.
+			Reverse: s.statisticsOwner(to, o.Name),
+		})
+		return nil
+	}
+	var fromC, toC schema.Comment
+	sqlx.Has(from.Attrs, &fromC)
+	sqlx.Has(to.Attrs, &toC)
+	s.append(s.statisticsComment(modify, to, toC.Text, fromC.Text))
+	return nil
+}
+
+// createDropStatistics returns the CREATE and DROP statements of the given statistics.
+func (s *state) createDropStatistics(st *Statistics) (string, string) {
+	b := s.Build("CREATE STATISTICS").SchemaResource(st.Schema, st.Name)
+	if len(st.Kinds) > 0 {
+		b.Wrap(func(b *sqlx.Builder) {
+			b.MapComma(st.Kinds, func(i int, b *sqlx.Builder) {
+				b.P(strings.ToLower(st.Kinds[i]))
+			})
+		})
+	}
+	b.P("ON")
+	b.MapComma(st.Columns, func(i int, b *sqlx.Builder) {
+		b.Ident(st.Columns[i].Name)
+	})
+	if len(st.Columns) > 0 && len(st.Exprs) > 0 {
+		b.Comma()
+	}
+	b.MapComma(st.Exprs, func(i int, b *sqlx.Builder) {
+		b.P(sqlx.MayWrap(st.Exprs[i]))
+	})
+	return b.P("FROM").Table(st.T).String(),
+		s.Build("DROP STATISTICS").SchemaResource(st.Schema, st.Name).String()
+}
+
+// statisticsOwner returns the statement for setting the owner of the statistics.
+func (s *state) statisticsOwner(st *Statistics, owner string) string {
+	return s.Build("ALTER STATISTICS").SchemaResource(st.Schema, st.Name).P("OWNER TO").Ident(owner).String()
+}
+
+func (s *state) statisticsComment(src schema.Change, st *Statistics, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON STATISTICS").SchemaResource(st.Schema, st.Name).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to statistics: %q", st.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+func (s *state) addSequence(add *schema.AddObject, seq *Sequence) {
+	// A sequence that is used by its owner table must be created before the table,
+	// and its ownership is set after the table is created.
+	owned := seq.Owner.T == nil || !seq.usedBy(seq.Owner.T)
+	if !owned {
+		s.owned = append(s.owned, seq)
+	}
+	create, drop := s.createDropSequence(seq, owned)
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create sequence %q", seq.Name),
+	})
+	if c := (schema.Comment{}); sqlx.Has(seq.Attrs, &c) && c.Text != "" {
+		s.append(s.sequenceComment(add, seq, c.Text, ""))
+	}
+}
+
+func (s *state) dropSequence(drop *schema.DropObject, seq *Sequence) {
+	create, dropS := s.createDropSequence(seq, true)
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropS,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop sequence %q", seq.Name),
+	})
+}
+
+func (s *state) alterSequence(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Sequence)
+	to, ok2 := modify.To.(*Sequence)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	var (
+		changed      bool
+		fromO, toO   = sequenceOptions(from), sequenceOptions(to)
+		cmd, reverse = s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name), s.Build("ALTER SEQUENCE").SchemaResource(to.Schema, to.Name)
+	)
+	for _, o := range []struct {
+		clause   string
+		from, to any
+	}{
+		{"AS", fromO.typ, toO.typ},
+		{"INCREMENT BY", fromO.increment, toO.increment},
+		{"MINVALUE", fromO.min, toO.min},
+		{"MAXVALUE", fromO.max, toO.max},
+		{"START WITH", fromO.start, toO.start},
+		{"CACHE", fromO.cache, toO.cache},
+	} {
+		if o.from != o.to {
+			cmd.P(o.clause, fmt.Sprint(o.to))
+			reverse.P(o.clause, fmt.Sprint(o.from))
+			changed = true
+		}
+	}
+	// Similar to identity columns, skip RESTART in case the "start value" is less than the "last value"
+	// in one of the states (inspected and desired), because this function is used for both UP and DOWN.
+	if fromO.start != toO.start {
+		if from.Last < toO.start && to.Last < toO.start {
+			cmd.P("RESTART")
+		}
+		if from.Last < fromO.start && to.Last < fromO.start {
+			reverse.P("RESTART")
+		}
+	}
+	if fromO.cycle != toO.cycle {
+		cmd.P(cycle(toO.cycle))
+		reverse.P(cycle(fromO.cycle))
+		changed = true
+	}
+	if !sequenceOwnerEqual(from, to) {
+		s.sequenceOwner(cmd.P("OWNED BY"), to)
+		s.sequenceOwner(reverse.P("OWNED BY"), from)
+		changed = true
+	}
+	if changed {
+		s.append(&migrate.Change{
+			Source:  modify,
+			Cmd:     cmd.String(),
+			Reverse: reverse.String(),
+			Comment: fmt.Sprintf("modify sequence %q", to.Name),
+		})
+	}
+	if change := sqlx.CommentDiff(from.Attrs, to.Attrs); change != nil {
+		fromC, toC, err := commentChange(change)
+		if err != nil {
+			return err
+		}
+		s.append(s.sequenceComment(modify, to, toC, fromC))
+	}
+	return nil
+}
+
+// createDropSequence returns the CREATE and DROP statements of the given sequence.
+// Options that are set to their defaults are omitted from the CREATE statement.
+func (s *state) createDropSequence(seq *Sequence, owned bool) (string, string) {
+	var (
+		o = sequenceOptions(seq)
+		d = o.defaults()
+		b = s.Build("CREATE SEQUENCE").SchemaResource(seq.Schema, seq.Name)
+	)
+	if o.typ != d.typ {
+		b.P("AS", o.typ)
+	}
+	for _, v := range []struct {
+		clause  string
+		v, defv int64
+	}{
+		{"INCREMENT BY", o.increment, d.increment},
+		{"MINVALUE", o.min, d.min},
+		{"MAXVALUE", o.max, d.max},
+		{"START WITH", o.start, d.start},
+		{"CACHE", o.cache, d.cache},
+	} {
+		if v.v != v.defv {
+			b.P(v.clause, strconv.FormatInt(v.v, 10))
+		}
+	}
+	if o.cycle {
+		b.P("CYCLE")
+	}
+	if owned && seq.Owner.T != nil {
+		s.sequenceOwner(b.P("OWNED BY"), seq)
+	}
+	return b.String(), s.Build("DROP SEQUENCE").SchemaResource(seq.Schema, seq.Name).String()
+}
+
+// sequenceOwner writes the column owning the sequence, or NONE if it is not owned.
+func (s *state) sequenceOwner(b *sqlx.Builder, seq *Sequence) *sqlx.Builder {
+	if t, c := seq.Owner.T, seq.Owner.C; t != nil && c != nil {
+		return b.P(fmt.Sprintf("%s%q.%q", s.schemaPrefix(t.Schema), t.Name, c.Name))
+	}
+	return b.P("NONE")
+}
+
+// ownSequences sets the owner of the sequences that were created before the given
+// table, because the table depends on them. See the addSequence method for details.
+func (s *state) ownSequences(src schema.Change, t *schema.Table) {
+	s.owned = slices.DeleteFunc(s.owned, func(seq *Sequence) bool {
+		if !sqlx.SameTable(seq.Owner.T, t) {
+			return false
+		}
+		b := s.Build("ALTER SEQUENCE").SchemaResource(seq.Schema, seq.Name).P("OWNED BY")
+		s.append(&migrate.Change{
+			Source:  src,
+			Cmd:     s.sequenceOwner(b.Clone(), seq).String(),
+			Reverse: b.P("NONE").String(),
+			Comment: fmt.Sprintf("set the owner of sequence %q", seq.Name),
+		})
+		return true
+	})
+}
+
+func (s *state) sequenceComment(src schema.Change, seq *Sequence, to, from string) *migrate.Change {
+	b := s.Build("COMMENT ON SEQUENCE").SchemaResource(seq.Schema, seq.Name).P("IS")
+	return &migrate.Change{
+		Cmd:     b.Clone().P(quote(to)).String(),
+		Source:  src,
+		Comment: fmt.Sprintf("set comment to sequence: %q", seq.Name),
+		Reverse: b.Clone().P(quote(from)).String(),
+	}
+}
+
+// cycle returns the CYCLE clause of a sequence.
+func cycle(v bool) string {
+	if v {
+		return "CYCLE"
+	}
+	return "NO CYCLE"
+}
+
+func (s *state) addPolicy(add *schema.AddObject, p *Policy) {
+	create, drop := s.createDropPolicy(p)
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create policy %q on table %q", p.Name, p.T.Name),
+	})
+}
+
+func (s *state) dropPolicy(drop *schema.DropObject, p *Policy) {
+	create, dropP := s.createDropPolicy(p)
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropP,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop policy %q from table %q", p.Name, p.T.Name),
+	})
+}
+
+func (s *state) alterPolicy(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*Policy)
+	to, ok2 := modify.To.(*Policy)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	// The type and the command of a policy cannot be altered, and it is recreated instead.
+	if policyRecreated(from, to) {
+		s.dropPolicy(&schema.DropObject{O: from}, from)
+		s.addPolicy(&schema.AddObject{O: to}, to)
+		return nil
+	}
+	var (
+		cmd     = s.Build("ALTER POLICY").Ident(to.Name).P("ON").Table(to.T)
+		reverse = cmd.Clone()
+	)
+	if !slices.Equal(policyRoles(from), policyRoles(to)) {
+		s.policyRoles(cmd.P("TO"), to.To)
+		s.policyRoles(reverse.P("TO"), from.To)
+	}
+	if !policyExprEqual(from.Using, to.Using) {
+		cmd.P("USING", sqlx.MayWrap(to.Using))
+		reverse.P("USING", sqlx.MayWrap(from.Using))
+	}
+	if !policyExprEqual(from.Check, to.Check) {
+		cmd.P("WITH CHECK", sqlx.MayWrap(to.Check))
+		reverse.P("WITH CHECK", sqlx.MayWrap(from.Check))
+	}
+	change := &migrate.Change{
+		Source:  modify,
+		Cmd:     cmd.String(),
+		Reverse: reverse.String(),
+		Comment: fmt.Sprintf("modify policy %q on table %q", to.Name, to.T.Name),
+	}
+	// Expressions that were added to the policy cannot be removed by
+	// ALTER POLICY, and the original policy is recreated on revert.
+	if from.Using == "" && to.Using != "" || from.Check == "" && to.Check != "" {
+		create, _ := s.createDropPolicy(from)
+		_, drop := s.createDropPolicy(to)
+		change.Reverse = []string{drop, create}
+	}
+	s.append(change)
+	return nil
+}
+
+// createDropPolicy returns the CREATE and DROP statements of the given policy.
+func (s *state) createDropPolicy(p *Policy) (string, string) {
+	b := s.Build("CREATE POLICY").Ident(p.Name).P("ON").Table(p.T)
+	if as := policyAs(p); as != PolicyAsPermissive {
+		b.P("AS", as)
+	}
+	if cmd := policyFor(p); cmd != PolicyForAll {
+		b.P("FOR", cmd)
+	}
+	if len(p.To) > 0 {
+		s.policyRoles(b.P("TO"), p.To)
+	}
+	if p.Using != "" {
+		b.P("USING", sqlx.MayWrap(p.Using))
+	}
+	if p.Check != "" {
+		b.P("WITH CHECK", sqlx.MayWrap(p.Check))
+	}
+	return b.String(), s.Build("DROP POLICY").Ident(p.Name).P("ON").Table(p.T).String()
+}
+
+// policyRoles writes the roles of a policy to the builder. Role specifications
+// that are keywords are written as is, and the rest are quoted as identifiers.
+func (*state) policyRoles(b *sqlx.Builder, roles []string) {
+	if len(roles) == 0 {
+		roles = []string{"PUBLIC"}
+	}
+	b.MapComma(roles, func(i int, b *sqlx.Builder) {
+		switch r := strings.ToUpper(roles[i]); r {
+		case "PUBLIC", "CURRENT_ROLE", "CURRENT_USER", "SESSION_USER":
+			b.P(r)
+		default:
+			b.Ident(roles[i])
+		}
+	})
+}
+
+// rowSecurity writes the row-level security options that were changed to the ALTER TABLE builder.
+func rowSecurity(b *sqlx.Builder, from, to *RowSecurity) *sqlx.Builder {
+	var opts []string
+	switch {
+	case !from.Enabled && to.Enabled:
+		opts = append(opts, "ENABLE")
+	case from.Enabled && !to.Enabled:
+		opts = append(opts, "DISABLE")
+	}
+	switch {
+	case !from.Enforced && to.Enforced:
+		opts = append(opts, "FORCE")
+	case from.Enforced && !to.Enforced:
+		opts = append(opts, "NO FORCE")
+	}
+	b.MapComma(opts, func(i int, b *sqlx.Builder) {
+		b.P(opts[i], "ROW LEVEL SECURITY")
+	})
+	return b
+}
+
+func (s *state) addComposite(add *schema.AddObject, c *CompositeType) error {
+	create, drop, err := s.createDropComposite(c)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  add,
+		Cmd:     create,
+		Reverse: drop,
+		Comment: fmt.Sprintf("create composite type %q", c.T),
+	})
+	return nil
+}
+
+func (s *state) dropComposite(drop *schema.DropObject, c *CompositeType) error {
+	create, dropC, err := s.createDropComposite(c)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  drop,
+		Cmd:     dropC,
+		Reverse: create,
+		Comment: fmt.Sprintf("drop composite type %q", c.T),
+	})
+	return nil
+}
+
+func (s *state) alterComposite(modify *schema.ModifyObject) error {
+	from, ok1 := modify.From.(*CompositeType)
+	to, ok2 := modify.To.(*CompositeType)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("altering objects (%T) to (%T) is not supported", modify.From, modify.To)
+	}
+	cmd, err := s.alterCompositeCmd(from, to)
+	if err != nil {
+		return err
+	}
+	reverse, err := s.alterCompositeCmd(to, from)
+	if err != nil {
+		return err
+	}
+	s.append(&migrate.Change{
+		Source:  modify,
+		Cmd:     cmd,
+		Reverse: reverse,
+		Comment: fmt.Sprintf("modify composite type %q", to.T),
+	})
+	return nil
+}
+
+// alterCompositeCmd returns the ALTER TYPE statement for migrating the
+// attributes (fields) of a composite type from one state to the other.
+func (s *state) alterCompositeCmd(from, to *CompositeType) (string, error) {
+	var (
+		clauses []func(*sqlx.Builder) error
+		b       = s.Build("ALTER TYPE").P(s.compositeIdent(to))
+	)
+	for _, f1 := range from.Fields {
+		if !slices.ContainsFunc(to.Fields, func(f2 *schema.Column) bool { return f1.Name == f2.Name }) {
+			clauses = append(clauses, func(b *sqlx.Builder) error {
+				b.P("DROP ATTRIBUTE").Ident(f1.Name)
+				return nil
+			})
+		}
+	}
+	for _, f2 := range to.Fields {
+		i := slices.IndexFunc(from.Fields, func(f1 *schema.Column) bool { return f1.Name == f2.Name })
+		switch {
+		case i == -1:
+			clauses = append(clauses, func(b *sqlx.Builder) error {
+				b.P("ADD ATTRIBUTE").Ident(f2.Name)
+				return s.compositeField(b, f2)
+			})
+		default:
+			changed, err := typeChanged(from.Fields[i], f2, s.schema)
+			if err != nil {
+				return "", err
+			}
+			var c1, c2 schema.Collation
+			sqlx.Has(from.Fields[i].Attrs, &c1)
+			sqlx.Has(f2.Attrs, &c2)
+			if changed || c1.V != c2.V {
+				clauses = append(clauses, func(b *sqlx.Builder) error {
+					b.P("ALTER ATTRIBUTE").Ident(f2.Name).P("TYPE")
+					return s.compositeField(b, f2)
+				})
+			}
+		}
+	}
+	if err := b.MapCommaErr(clauses, func(i int, b *sqlx.Builder) error {
+		return clauses[i](b)
+	}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// createDropComposite returns the CREATE and DROP statements of the given composite type.
+func (s *state) createDropComposite(c *CompositeType) (string, string, error) {
+	b := s.Build("CREATE TYPE").P(s.compositeIdent(c), "AS")
+	if err := b.WrapIndentErr(func(b *sqlx.Builder) error {
+		return b.MapIndentErr(c.Fields, func(i int, b *sqlx.Builder) error {
+			return s.compositeField(b.Ident(c.Fields[i].Name), c.Fields[i])
+		})
+	}); err != nil {
+		return "", "", err
+	}
+	return b.String(), s.Build("DROP TYPE").P(s.compositeIdent(c)).String(), nil
+}
+
+// compositeField writes the type and the collation of the given composite field.
+func (s *state) compositeField(b *sqlx.Builder, f *schema.Column) error {
+	t, err := s.formatType(f.Type.Type)
+	if err != nil {
+		return fmt.Errorf("format type of composite field %q: %w", f.Name, err)
+	}
+	b.P(t)
+	if c := (schema.Collation{}); sqlx.Has(f.Attrs, &c) && c.V != "" {
+		b.P("COLLATE").Ident(c.V)
+	}
+	return nil
+}
+
+var (
+	_ sqlx.Depender = (*Language)(nil)
+	_ sqlx.Depender = (*Cast)(nil)
+	_ sqlx.Depender = (*Statistics)(nil)
+	_ sqlx.Depender = (*Policy)(nil)
+	_ sqlx.Depender = (*CompositeType)(nil)
+)
+
+// DependsOn implements the sqlx.Depender interface. Statistics must be
+// created after their table and the columns they are computed on.
+func (st *Statistics) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+	default:
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddTable:
+		return sqlx.SameTable(o.T, st.T)
+	case *schema.ModifyTable:
+		return sqlx.SameTable(o.T, st.T)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. Statistics must be dropped
+// before the columns they are computed on, as these are dropped along with them.
+func (st *Statistics) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.ModifyTable:
+		return sqlx.SameTable(o.T, st.T)
+	case *schema.DropTable:
+		return sqlx.SameTable(o.T, st.T)
+	}
+	return false
+}
+
+// DependsOn implements the sqlx.Depender interface. Policies must be
+// created after their table and the columns they reference.
+func (p *Policy) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+	default:
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddTable:
+		return sqlx.SameTable(o.T, p.T)
+	case *schema.ModifyTable:
+		return sqlx.SameTable(o.T, p.T)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. Policies must be
+// dropped before the columns they reference are dropped from the table.
+func (p *Policy) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	if o, ok := other.(*schema.ModifyTable); ok {
+		return sqlx.SameTable(o.T, p.T)
+	}
+	return false
+}
+
+// DependsOn implements the sqlx.Depender interface. A sequence is created (or modified) after
+// its owner table, unless the table uses the sequence. In this case, the sequence is created
+// first, and its ownership is set after the table is created. A sequence is dropped after
+// the tables that use it are dropped.
+func (s *Sequence) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+		var t *schema.Table
+		switch o := other.(type) {
+		case *schema.AddTable:
+			t = o.T
+		case *schema.ModifyTable:
+			t = o.T
+		default:
+			return false
+		}
+		if !sqlx.SameTable(t, s.Owner.T) {
+			return false
+		}
+		_, ok := change.(*schema.ModifyObject)
+		return ok || !s.usedBy(t)
+	case *schema.DropObject:
+		if o, ok := other.(*schema.DropTable); ok {
+			return s.usedBy(o.T)
+		}
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. A sequence
+// must be created before the tables that use it are created or modified.
+func (s *Sequence) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.AddObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddTable:
+		return s.usedBy(o.T)
+	case *schema.ModifyTable:
+		return s.usedBy(o.T)
+	}
+	return false
+}
+
+// usedBy reports if the sequence is used by the default values of the table columns.
+func (s *Sequence) usedBy(t *schema.Table) bool {
+	return slices.ContainsFunc(t.Columns, func(c *schema.Column) bool {
+		x, ok := c.Default.(*schema.RawExpr)
+		if !ok {
+			return false
+		}
+		for _, m := range reSeqNextval.FindAllStringSubmatch(x.X, -1) {
+			ns := m[1]
+			if ns == "" && t.Schema != nil {
+				ns = t.Schema.Name
+			}
+			if m[2] == s.Name && (s.Schema == nil || ns == "" || ns == s.Schema.Name) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// DependsOn implements the sqlx.Depender interface. A composite type
+// must be created after the types that are used by its fields.
+func (c *CompositeType) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+	default:
+		return false
+	}
+	if o, ok := other.(*schema.AddObject); ok {
+		return c.refObject(o.O)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. A composite type
+// must be dropped before the types that are used by its fields.
+func (c *CompositeType) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	if o, ok := other.(*schema.DropObject); ok {
+		return c.refObject(o.O)
+	}
+	return false
+}
+
+// refObject reports if the composite type depends on the given object.
+func (c *CompositeType) refObject(o schema.Object) bool {
+	var (
+		name string
+		ns   *schema.Schema
+	)
+	switch o := o.(type) {
+	case *schema.EnumType:
+		name, ns = o.T, o.Schema
+	case *DomainType:
+		name, ns = o.T, o.Schema
+	case *CompositeType:
+		if o == c {
+			return false
+		}
+		name, ns = o.T, o.Schema
+	default:
+		return false
+	}
+	return slices.ContainsFunc(c.Deps, func(d schema.Object) bool {
+		switch d := d.(type) {
+		case *schema.EnumType:
+			return d.T == name && sqlx.SameSchema(d.Schema, ns)
+		case *DomainType:
+			return d.T == name && sqlx.SameSchema(d.Schema, ns)
+		case *CompositeType:
+			return d.T == name && sqlx.SameSchema(d.Schema, ns)
+		}
+		return false
+	})
+}
+
+// DependsOn implements the sqlx.Depender interface. A language can
+// be dropped only after the functions written in it were dropped.
+func (l *Language) DependsOn(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.DropFunc:
+		return strings.EqualFold(o.F.Lang, l.Name)
+	case *schema.DropProc:
+		return strings.EqualFold(o.P.Lang, l.Name)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. A language must
+// be created before the functions that are written in it.
+func (l *Language) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.AddObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddFunc:
+		return strings.EqualFold(o.F.Lang, l.Name)
+	case *schema.AddProc:
+		return strings.EqualFold(o.P.Lang, l.Name)
+	case *schema.ModifyFunc:
+		return strings.EqualFold(o.To.Lang, l.Name)
+	case *schema.ModifyProc:
+		return strings.EqualFold(o.To.Lang, l.Name)
+	}
+	return false
+}
+
+// DependsOn implements the sqlx.Depender interface. A cast must be
+// created after its function and the types it converts.
+func (c *Cast) DependsOn(change, other schema.Change) bool {
+	switch change.(type) {
+	case *schema.AddObject, *schema.ModifyObject:
+	default:
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.AddFunc:
+		return c.refFunc(o.F)
+	case *schema.ModifyFunc:
+		return c.refFunc(o.To)
+	case *schema.AddObject:
+		return c.refType(o.O)
+	}
+	return false
+}
+
+// DependencyOf implements the sqlx.Depender interface. A cast must be
+// dropped before its function and the types it converts.
+func (c *Cast) DependencyOf(change, other schema.Change) bool {
+	if _, ok := change.(*schema.DropObject); !ok {
+		return false
+	}
+	switch o := other.(type) {
+	case *schema.DropFunc:
+		return c.refFunc(o.F)
+	case *schema.DropObject:
+		return c.refType(o.O)
+	}
+	return false
+}
+
+// refFunc reports if the cast is done using the given function.
+func (c *Cast) refFunc(f *schema.Func) bool {
+	name, _, ok := strings.Cut(c.Func, "(")
+	if !ok || c.Method != CastMethodFunc {
+		return false
+	}
+	ns, name := parseFmtType(name)
+	return name == f.Name && (ns == "" || f.Schema == nil || ns == f.Schema.Name)
+}
+
+// refType reports if the cast converts from or to the given type.
+func (c *Cast) refType(o schema.Object) bool {
+	var (
+		name string
+		ns   *schema.Schema
+	)
+	switch o := o.(type) {
+	case *schema.EnumType:
+		name, ns = o.T, o.Schema
+	case *DomainType:
+		name, ns = o.T, o.Schema
+	case *CompositeType:
+		name, ns = o.T, o.Schema
+	default:
+		return false
+	}
+	for _, t := range []string{c.Source, c.Target} {
+		tns, tn := parseFmtType(strings.TrimSuffix(t, "[]"))
+		if tn == name && (tns == "" || ns == nil || tns == ns.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *state) addIndexes(src schema.Change, t *schema.Table, adds ...*schema.AddIndex) error {
+	for _, add := range adds {
+		b, idx := s.Build("CREATE"), add.I
+		if idx.Unique {
+			b.P("UNIQUE")
+		}
+		b.P("INDEX")
+		if sqlx.Has(add.Extra, &Concurrently{}) {
+			b.P("CONCURRENTLY")
+		}
+		if idx.Name != "" {
+			b.Ident(idx.Name)
+		}
+		b.P("ON").Table(t)
+		if err := s.indexParams(b, idx, false); err != nil {
+			return err
+		}
+		s.append(&migrate.Change{
+			Cmd:     b.String(),
+			Source:  src,
+			NoTx:    sqlx.Has(add.Extra, &Concurrently{}),
+			Comment: fmt.Sprintf("create index %q to table: %q", idx.Name, t.Name),
+			Reverse: func() string {
+				b := s.Build("DROP INDEX")
+				if sqlx.Has(add.Extra, &Concurrently{}) {
+					b.P("CONCURRENTLY")
+				}
+				// Unlike MySQL, the DROP command is not attached to ALTER TABLE.
+				// Therefore, we print indexes with their qualified name, because
+				// the connection that executes the statements may not be attached
+				// to this schema.
+				if t.Schema != nil {
+					b.WriteString(s.schemaPrefix(t.Schema))
+				}
+				b.Ident(idx.Name)
+				return b.String()
+			}(),
 		})
 	}
 	return nil
@@ -1120,7 +2658,15 @@ func (s *state) partAttrs(b *sqlx.Builder, idx *schema.Index, p *schema.IndexPar
 	return nil
 }
 
+// index writes the definition of an index that is created by a constraint,
+// such as PRIMARY KEY, UNIQUE or EXCLUDE.
 func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
+	return s.indexParams(b, idx, true)
+}
+
+// indexParams writes the definition of the index. Note, the tablespace
+// of constraint indexes is written using the USING INDEX clause.
+func (s *state) indexParams(b *sqlx.Builder, idx *schema.Index, constraint bool) error {
 	// Avoid appending the default method.
 	if t := (IndexType{}); sqlx.Has(idx.Attrs, &t) && strings.ToUpper(t.T) != IndexTypeBTree {
 		b.P("USING", t.T)
@@ -1153,13 +2699,20 @@ func (s *state) index(b *sqlx.Builder, idx *schema.Index) error {
 			b.WriteString(strings.Join(parts, ", "))
 		})
 	}
+	if ts := tablespace(idx.Attrs); ts != "" {
+		if constraint {
+			b.P("USING INDEX")
+		}
+		b.P("TABLESPACE").Ident(ts)
+	}
 	if p := (IndexPredicate{}); sqlx.Has(idx.Attrs, &p) {
 		b.P("WHERE").P(p.P)
 	}
 	return nil
 }
 
-func (s *state) fks(b *sqlx.Builder, fks ...*schema.ForeignKey) {
+func (s *state) fks(b *sqlx.Builder, fks ...*schema.ForeignKey) error {
+	var errs []string
 	b.MapIndent(fks, func(i int, b *sqlx.Builder) {
 		fk := fks[i]
 		if fk.Symbol != "" {
@@ -1183,7 +2736,24 @@ func (s *state) fks(b *sqlx.Builder, fks ...*schema.ForeignKey) {
 		if fk.OnDelete != "" {
 			b.P("ON DELETE", string(fk.OnDelete))
 		}
+		if c := (DeleteSetColumns{}); sqlx.Has(fk.Attrs, &c) && len(c.Columns) > 0 {
+			switch {
+			case fk.OnDelete != schema.SetNull && fk.OnDelete != schema.SetDefault:
+				errs = append(errs, fmt.Sprintf("foreign key %q: column list requires ON DELETE SET NULL or SET DEFAULT", fk.Symbol))
+			case !s.conn.supportsFKDeleteColumns():
+				errs = append(errs, fmt.Sprintf("foreign key %q: column list in ON DELETE action requires PostgreSQL 15 or above", fk.Symbol))
+			}
+			b.Wrap(func(b *sqlx.Builder) {
+				b.MapComma(c.Columns, func(i int, b *sqlx.Builder) {
+					b.Ident(c.Columns[i].Name)
+				})
+			})
+		}
 	})
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
 }
 
 func (s *state) constraint(b *sqlx.Builder, idx *schema.Index) error {
@@ -1415,3 +2985,40 @@ func nullsNotDistinct(b *sqlx.Builder, idx *schema.Index) {
 		b.P("NULLS NOT DISTINCT")
 	}
 }
+
+// maxIdentLen is the maximum length (in bytes) of identifiers in PostgreSQL.
+// Longer identifiers are silently truncated by the database. See NAMEDATALEN.
+const maxIdentLen = 63
+
+// identChecker returns the checker of the identifiers used by the planned changes.
+func identChecker(opts migrate.PlanOptions) *sqlx.IdentChecker {
+	c := &sqlx.IdentChecker{MaxLen: maxIdentLen, Unit: "bytes"}
+	if opts.CheckReserved {
+		c.Reserved = func(name string) bool {
+			_, ok := reservedWords[strings.ToLower(name)]
+			return ok
+		}
+	}
+	return c
+}
+
+// reservedWords holds the keywords that are reserved in PostgreSQL, including the ones
+// that can be used as function or type names. https://postgresql.org/docs/current/sql-keywords-appendix.html
+var reservedWords = map[string]struct{}{
+	"all": {}, "analyse": {}, "analyze": {}, "and": {}, "any": {}, "array": {}, "as": {}, "asc": {},
+	"asymmetric": {}, "authorization": {}, "binary": {}, "both": {}, "case": {}, "cast": {},
+	"check": {}, "collate": {}, "collation": {}, "column": {}, "concurrently": {}, "constraint": {},
+	"create": {}, "cross": {}, "current_catalog": {}, "current_date": {}, "current_role": {},
+	"current_schema": {}, "current_time": {}, "current_timestamp": {}, "current_user": {},
+	"default": {}, "deferrable": {}, "desc": {}, "distinct": {}, "do": {}, "else": {}, "end": {},
+	"except": {}, "false": {}, "fetch": {}, "for": {}, "foreign": {}, "freeze": {}, "from": {},
+	"full": {}, "grant": {}, "group": {}, "having": {}, "ilike": {}, "in": {}, "initially": {},
+	"inner": {}, "intersect": {}, "into": {}, "is": {}, "isnull": {}, "join": {}, "lateral": {},
+	"leading": {}, "left": {}, "like": {}, "limit": {}, "localtime": {}, "localtimestamp": {},
+	"natural": {}, "not": {}, "notnull": {}, "null": {}, "offset": {}, "on": {}, "only": {}, "or": {},
+	"order": {}, "outer": {}, "overlaps": {}, "placing": {}, "primary": {}, "references": {},
+	"returning": {}, "right": {}, "select": {}, "session_user": {}, "similar": {}, "some": {},
+	"symmetric": {}, "system_user": {}, "table": {}, "tablesample": {}, "then": {}, "to": {},
+	"trailing": {}, "true": {}, "union": {}, "unique": {}, "user": {}, "using": {}, "variadic": {},
+	"verbose": {}, "when": {}, "where": {}, "window": {}, "with": {},
+}
@@ -9,6 +9,7 @@ package postgres
 import (
 	"context"
 	"strconv"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/internal/sqltest"
@@ -23,6 +24,7 @@ func TestPlanChanges(t *testing.T) {
 	tests := []struct {
 		changes  []schema.Change
 		options  []migrate.PlanOption
+		version  string
 		mock     func(mock)
 		wantPlan *migrate.Plan
 		wantErr  bool
@@ -837,6 +839,7 @@ func TestPlanChanges(t *testing.T) {
 					{
 						Cmd:     `DROP INDEX CONCURRENTLY "drop_con"`,
 						Reverse: `CREATE INDEX CONCURRENTLY "drop_con" ON "users" ("id")`,
+						NoTx:    true,
 					},
 					{
 						Cmd:     `ALTER TABLE "users" DROP CONSTRAINT "id_nonzero", ADD COLUMN "name" character varying(255) NOT NULL DEFAULT 'logged_in', ADD COLUMN "last" character varying(255) NOT NULL DEFAULT 'logged_in', ADD CONSTRAINT "name_not_empty" CHECK ("name" <> ''), ADD CONSTRAINT "positive_id" CHECK ("id" > 0) NOT VALID, DROP CONSTRAINT "id_iseven", ADD CONSTRAINT "id_iseven" CHECK (("id") % 2 = 0), ADD CONSTRAINT "unique_const" UNIQUE NULLS NOT DISTINCT ("id")`,
@@ -857,6 +860,7 @@ func TestPlanChanges(t *testing.T) {
 					{
 						Cmd:     `CREATE INDEX CONCURRENTLY "add_con" ON "users" ("id")`,
 						Reverse: `DROP INDEX CONCURRENTLY "add_con"`,
+						NoTx:    true,
 					},
 					{
 						Cmd:     `CREATE INDEX "operator_class" ON "users" USING BRIN ("id" int8_bloom_ops, "id", "id" int8_minmax_multi_ops(values_per_range=8))`,
@@ -1183,6 +1187,62 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Enum values cannot be added inside a transaction block before PostgreSQL 12.
+		{
+			version: "110000",
+			changes: []schema.Change{
+				&schema.ModifyObject{
+					From: &schema.EnumType{T: "state", Values: []string{"on", "off"}, Schema: schema.New("public")},
+					To:   &schema.EnumType{T: "state", Values: []string{"on", "off", "unknown"}, Schema: schema.New("public")},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    false,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: `ALTER TYPE "public"."state" ADD VALUE 'unknown'`, NoTx: true},
+				},
+			},
+		},
+		// Column lists in ON DELETE actions are supported since PostgreSQL 15.
+		{
+			version: "150000",
+			changes: []schema.Change{
+				func() schema.Change {
+					tid, aid := schema.NewIntColumn("tenant_id", "int"), schema.NewNullIntColumn("author_id", "int")
+					posts := schema.NewTable("posts").SetSchema(schema.New("public")).AddColumns(tid, aid)
+					users := schema.NewTable("users").SetSchema(posts.Schema).AddColumns(schema.NewIntColumn("tenant_id", "int"), schema.NewIntColumn("id", "int"))
+					fk := schema.NewForeignKey("author").AddColumns(tid, aid).SetRefTable(users).AddRefColumns(users.Columns...).SetOnDelete(schema.SetNull)
+					fk.AddAttrs(&DeleteSetColumns{Columns: []*schema.Column{aid}})
+					posts.AddForeignKeys(fk)
+					return &schema.ModifyTable{T: posts, Changes: []schema.Change{&schema.AddForeignKey{F: fk}}}
+				}(),
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `ALTER TABLE "public"."posts" ADD CONSTRAINT "author" FOREIGN KEY ("tenant_id", "author_id") REFERENCES "public"."users" ("tenant_id", "id") ON DELETE SET NULL ("author_id")`,
+						Reverse: `ALTER TABLE "public"."posts" DROP CONSTRAINT "author"`,
+					},
+				},
+			},
+		},
+		{
+			version: "140000",
+			changes: []schema.Change{
+				func() schema.Change {
+					aid := schema.NewNullIntColumn("author_id", "int")
+					posts := schema.NewTable("posts").SetSchema(schema.New("public")).AddColumns(aid)
+					users := schema.NewTable("users").SetSchema(posts.Schema).AddColumns(schema.NewIntColumn("id", "int"))
+					fk := schema.NewForeignKey("author").AddColumns(aid).SetRefTable(users).AddRefColumns(users.Columns...).SetOnDelete(schema.SetNull)
+					fk.AddAttrs(&DeleteSetColumns{Columns: []*schema.Column{aid}})
+					return &schema.AddTable{T: posts.AddForeignKeys(fk)}
+				}(),
+			},
+			wantErr: true,
+		},
 		// Append enum values at the beginning.
 		{
 			changes: []schema.Change{
@@ -1847,13 +1907,167 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Languages and casts.
+		{
+			changes: []schema.Change{
+				&schema.AddObject{O: &Language{Name: "plperl", Trusted: true}},
+				&schema.AddObject{O: &Language{Name: "plsample", Trusted: true, Handler: "plsample_call_handler", Validator: "plsample_validator", Attrs: []schema.Attr{&schema.Comment{Text: "sample"}}}},
+				&schema.DropObject{O: &Language{Name: "plpython3u"}},
+				&schema.AddObject{O: &Cast{Source: "text", Target: "public.mood", Method: CastMethodFunc, Func: "public.text_to_mood(text)", Context: CastContextAssignment}},
+				&schema.AddObject{O: &Cast{Source: "public.mood", Target: "text", Method: CastMethodInOut}},
+				&schema.DropObject{O: &Cast{Source: "int4", Target: "public.mood", Method: CastMethodBinary, Context: CastContextImplicit}},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE LANGUAGE "plperl"`,
+						Reverse: `DROP EXTENSION "plperl"`,
+					},
+					{
+						Cmd:     `CREATE TRUSTED LANGUAGE "plsample" HANDLER plsample_call_handler VALIDATOR plsample_validator`,
+						Reverse: `DROP LANGUAGE "plsample"`,
+					},
+					{
+						Cmd:     `COMMENT ON LANGUAGE "plsample" IS 'sample'`,
+						Reverse: `COMMENT ON LANGUAGE "plsample" IS ''`,
+					},
+					{
+						Cmd:     `CREATE CAST (text AS public.mood) WITH FUNCTION public.text_to_mood(text) AS ASSIGNMENT`,
+						Reverse: `DROP CAST (text AS public.mood)`,
+					},
+					{
+						Cmd:     `CREATE CAST (public.mood AS text) WITH INOUT`,
+						Reverse: `DROP CAST (public.mood AS text)`,
+					},
+					{
+						Cmd:     `DROP EXTENSION "plpython3u"`,
+						Reverse: `CREATE LANGUAGE "plpython3u"`,
+					},
+					{
+						Cmd:     `DROP CAST (int4 AS public.mood)`,
+						Reverse: `CREATE CAST (int4 AS public.mood) WITHOUT FUNCTION AS IMPLICIT`,
+					},
+				},
+			},
+		},
+		// Database settings and comments.
+		{
+			changes: []schema.Change{
+				&schema.ModifyObject{
+					From: &Database{
+						Name: "app",
+						Settings: []*DatabaseSetting{
+							{Name: "statement_timeout", Value: "5s"},
+							{Name: "timezone", Value: "UTC"},
+						},
+					},
+					To: &Database{
+						Name: "app",
+						Settings: []*DatabaseSetting{
+							{Name: "search_path", Value: `"$user", public`},
+							{Name: "timezone", Value: "Asia/Jerusalem"},
+						},
+						Attrs: []schema.Attr{&schema.Comment{Text: "app's db"}},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `ALTER DATABASE "app" RESET statement_timeout`,
+						Reverse: `ALTER DATABASE "app" SET statement_timeout TO '5s'`,
+					},
+					{
+						Cmd:     `ALTER DATABASE "app" SET search_path TO "$user", public`,
+						Reverse: `ALTER DATABASE "app" RESET search_path`,
+					},
+					{
+						Cmd:     `ALTER DATABASE "app" SET timezone TO 'Asia/Jerusalem'`,
+						Reverse: `ALTER DATABASE "app" SET timezone TO 'UTC'`,
+					},
+					{
+						Cmd:     `COMMENT ON DATABASE "app" IS 'app''s db'`,
+						Reverse: `COMMENT ON DATABASE "app" IS ''`,
+					},
+				},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.DropObject{
+					O: &Database{
+						Name:     "app",
+						Settings: []*DatabaseSetting{{Name: "timezone", Value: "UTC"}},
+						Attrs:    []schema.Attr{&schema.Comment{Text: "app db"}},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `ALTER DATABASE "app" RESET timezone`,
+						Reverse: `ALTER DATABASE "app" SET timezone TO 'UTC'`,
+					},
+					{
+						Cmd:     `COMMENT ON DATABASE "app" IS ''`,
+						Reverse: `COMMENT ON DATABASE "app" IS 'app db'`,
+					},
+				},
+			},
+		},
+		// Unlogged tables.
+		{
+			changes: []schema.Change{
+				&schema.AddTable{T: schema.NewTable("cache").AddColumns(schema.NewStringColumn("k", "text")).AddAttrs(&Unlogged{V: true})},
+				&schema.ModifyTable{
+					T: schema.NewTable("sessions"),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &Unlogged{V: false}, To: &Unlogged{V: true}},
+					},
+				},
+				&schema.ModifyTable{
+					T: schema.NewTable("events"),
+					Changes: []schema.Change{
+						&schema.AddColumn{C: schema.NewIntColumn("id", "int")},
+						&schema.ModifyAttr{From: &Unlogged{V: true}, To: &Unlogged{V: false}},
+					},
+				},
+			},
+			wantPlan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{
+						Cmd:     `CREATE UNLOGGED TABLE "cache" ("k" text NOT NULL)`,
+						Reverse: `DROP TABLE "cache"`,
+					},
+					{
+						Cmd:     `ALTER TABLE "sessions" SET UNLOGGED`,
+						Reverse: `ALTER TABLE "sessions" SET LOGGED`,
+					},
+					{
+						Cmd:     `ALTER TABLE "events" ADD COLUMN "id" integer NOT NULL, SET LOGGED`,
+						Reverse: `ALTER TABLE "events" SET UNLOGGED, DROP COLUMN "id"`,
+					},
+				},
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
 			db, mk, err := sqlmock.New()
 			require.NoError(t, err)
 			m := mock{mk}
-			m.version("130000")
+			if tt.version == "" {
+				tt.version = "130000"
+			}
+			m.version(tt.version)
 			if tt.mock != nil {
 				tt.mock(m)
 			}
@@ -1870,6 +2084,7 @@ func TestPlanChanges(t *testing.T) {
 			for i, c := range plan.Changes {
 				require.Equal(t, tt.wantPlan.Changes[i].Cmd, c.Cmd)
 				require.Equal(t, tt.wantPlan.Changes[i].Reverse, c.Reverse)
+				require.Equal(t, tt.wantPlan.Changes[i].NoTx, c.NoTx)
 			}
 		})
 	}
@@ -1889,6 +2104,477 @@ func TestDefaultPlan(t *testing.T) {
 	require.EqualError(t, err, `create "t1" table: cannot execute statements without a database connection. use Open to create a new Driver`)
 }
 
+func TestPlanChanges_Tablespace(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		logs = schema.NewTable("logs").
+			SetSchema(s).
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("k", "text")).
+			AddAttrs(&Tablespace{V: "archive"})
+		events = schema.NewTable("events").
+			SetSchema(s).
+			AddColumns(schema.NewIntColumn("id", "int"), schema.NewStringColumn("k", "text"))
+	)
+	logs.SetPrimaryKey(schema.NewPrimaryKey(logs.Columns[0]).AddAttrs(&Tablespace{V: "fast"})).
+		AddIndexes(schema.NewIndex("logs_k").AddColumns(logs.Columns[1]).AddAttrs(&Tablespace{V: "fast"}, &IndexPredicate{P: "k <> ''"}))
+	events.SetPrimaryKey(schema.NewPrimaryKey(events.Columns[0]))
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: logs},
+		&schema.ModifyTable{
+			T: events,
+			Changes: []schema.Change{
+				&schema.ModifyAttr{From: &Tablespace{}, To: &Tablespace{V: "archive"}},
+				&schema.ModifyIndex{
+					From:   schema.NewIndex("events_k").AddColumns(events.Columns[1]),
+					To:     schema.NewIndex("events_k").AddColumns(events.Columns[1]).AddAttrs(&Tablespace{V: "fast"}),
+					Change: schema.ChangeAttr,
+				},
+				&schema.ModifyPrimaryKey{
+					From:   schema.NewPrimaryKey(events.Columns[0]).AddAttrs(&Tablespace{V: "fast"}),
+					To:     events.PrimaryKey,
+					Change: schema.ChangeAttr,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	// Moving indexes between tablespaces does not drop and recreate them.
+	require.Equal(t, [][2]any{
+		{`CREATE TABLE "public"."logs" ("id" integer NOT NULL, "k" text NOT NULL, PRIMARY KEY ("id") USING INDEX TABLESPACE "fast") TABLESPACE "archive"`, `DROP TABLE "public"."logs"`},
+		{`CREATE INDEX "logs_k" ON "public"."logs" ("k") TABLESPACE "fast" WHERE k <> ''`, `DROP INDEX "public"."logs_k"`},
+		{`ALTER TABLE "public"."events" SET TABLESPACE "archive"`, `ALTER TABLE "public"."events" SET TABLESPACE "pg_default"`},
+		{`ALTER INDEX "public"."events_k" SET TABLESPACE "fast"`, `ALTER INDEX "public"."events_k" SET TABLESPACE "pg_default"`},
+		{`ALTER INDEX "public"."events_pkey" SET TABLESPACE "pg_default"`, `ALTER INDEX "public"."events_pkey" SET TABLESPACE "fast"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+}
+
+func TestPlanChanges_Idents(t *testing.T) {
+	var (
+		long  = strings.Repeat("é", 32)
+		users = schema.NewTable("user").SetSchema(schema.New("public")).AddColumns(schema.NewIntColumn("id", "int"))
+	)
+	users.AddIndexes(schema.NewIndex(long).AddColumns(users.Columns[0]))
+	_, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}})
+	require.EqualError(t, err, `index "`+long+`" of table "user" exceeds the maximum identifier length of 63 bytes (64)`)
+
+	// Reserved keywords are checked only if requested.
+	users.Indexes = nil
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}})
+	require.NoError(t, err)
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{&schema.AddTable{T: users}}, func(o *migrate.PlanOptions) {
+		o.CheckReserved = true
+	})
+	require.EqualError(t, err, `table "user" is named after a reserved keyword`)
+}
+
+func TestPlanChanges_Composite(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		status = &schema.EnumType{T: "status", Schema: s, Values: []string{"active", "inactive"}}
+		addr   = &CompositeType{
+			T:      "address",
+			Schema: s,
+			Fields: []*schema.Column{
+				schema.NewStringColumn("street", "text").SetCollation("C"),
+				schema.NewColumn("status").SetType(status),
+			},
+			Deps: []schema.Object{status},
+		}
+		usr = schema.NewTable("users").SetSchema(s).AddColumns(schema.NewColumn("home").SetType(addr))
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: usr},
+		&schema.AddObject{O: addr},
+		&schema.AddObject{O: status},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	// Types are created before the types and the tables that use them.
+	require.Equal(t, [][2]any{
+		{`CREATE TYPE "public"."status" AS ENUM ('active', 'inactive')`, `DROP TYPE "public"."status"`},
+		{`CREATE TYPE "public"."address" AS ("street" text COLLATE "C", "status" "public"."status")`, `DROP TYPE "public"."address"`},
+		{`CREATE TABLE "public"."users" ("home" "public"."address" NOT NULL)`, `DROP TABLE "public"."users"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropObject{O: status},
+		&schema.DropObject{O: addr},
+		&schema.DropTable{T: usr},
+	})
+	require.NoError(t, err)
+	// Types are dropped after the types and the tables that use them.
+	require.Equal(t, []string{
+		`DROP TABLE "public"."users"`,
+		`DROP TYPE "public"."address"`,
+		`DROP TYPE "public"."status"`,
+	}, func() (cs []string) {
+		for _, c := range plan.Changes {
+			cs = append(cs, c.Cmd)
+		}
+		return cs
+	}())
+
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{
+			From: addr,
+			To: &CompositeType{
+				T:      "address",
+				Schema: s,
+				Fields: []*schema.Column{
+					schema.NewStringColumn("street", "varchar"),
+					schema.NewIntColumn("zip", "int"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, `ALTER TYPE "public"."address" DROP ATTRIBUTE "status", ALTER ATTRIBUTE "street" TYPE character varying, ADD ATTRIBUTE "zip" integer`, plan.Changes[0].Cmd)
+	require.Equal(t, `ALTER TYPE "public"."address" DROP ATTRIBUTE "zip", ALTER ATTRIBUTE "street" TYPE text COLLATE "C", ADD ATTRIBUTE "status" "public"."status"`, plan.Changes[0].Reverse)
+}
+
+func TestPlanChanges_Statistics(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").SetSchema(s).AddColumns(
+			schema.NewStringColumn("name", "text"),
+			schema.NewStringColumn("email", "text"),
+		)
+		names = &Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns, Kinds: []string{StatisticsKindNDistinct}}
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: &Statistics{Name: "users_email", Schema: s, T: usr, Columns: usr.Columns[1:], Exprs: []string{"lower(name)"}, Attrs: []schema.Attr{&schema.Comment{Text: "email"}}}},
+		&schema.DropObject{O: names},
+		&schema.ModifyObject{From: names, To: &Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns}},
+		&schema.ModifyObject{From: names, To: &Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns, Kinds: names.Kinds, Attrs: []schema.Attr{&schema.Comment{Text: "names"}}}},
+		&schema.ModifyTable{T: usr, Changes: []schema.Change{&schema.AddColumn{C: usr.Columns[1]}}},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	// Statistics are dropped before the columns they are computed on are
+	// changed, and created after the table columns were added.
+	require.Equal(t, [][2]any{
+		{`DROP STATISTICS "public"."users_names"`, `CREATE STATISTICS "public"."users_names" (ndistinct) ON "name", "email" FROM "public"."users"`},
+		{`ALTER TABLE "public"."users" ADD COLUMN "email" text NOT NULL`, `ALTER TABLE "public"."users" DROP COLUMN "email"`},
+		{`CREATE STATISTICS "public"."users_email" ON "email", (lower(name)) FROM "public"."users"`, `DROP STATISTICS "public"."users_email"`},
+		{`COMMENT ON STATISTICS "public"."users_email" IS 'email'`, `COMMENT ON STATISTICS "public"."users_email" IS ''`},
+		{`DROP STATISTICS "public"."users_names"`, `CREATE STATISTICS "public"."users_names" (ndistinct) ON "name", "email" FROM "public"."users"`},
+		{`CREATE STATISTICS "public"."users_names" ON "name", "email" FROM "public"."users"`, `DROP STATISTICS "public"."users_names"`},
+		{`COMMENT ON STATISTICS "public"."users_names" IS 'names'`, `COMMENT ON STATISTICS "public"."users_names" IS ''`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// The owner of recreated statistics is restored.
+	owned := &Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns, Kinds: names.Kinds, Attrs: []schema.Attr{&Owner{Name: "admin"}}}
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyObject{From: owned, To: &Statistics{Name: "users_names", Schema: s, T: usr, Columns: usr.Columns}},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	require.Equal(t, [][2]any{
+		{`DROP STATISTICS "public"."users_names"`, []string{`CREATE STATISTICS "public"."users_names" (ndistinct) ON "name", "email" FROM "public"."users"`, `ALTER STATISTICS "public"."users_names" OWNER TO "admin"`}},
+		{`CREATE STATISTICS "public"."users_names" ON "name", "email" FROM "public"."users"`, `DROP STATISTICS "public"."users_names"`},
+		{`ALTER STATISTICS "public"."users_names" OWNER TO "admin"`, `ALTER STATISTICS "public"."users_names" OWNER TO "admin"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+}
+
+func TestPlanChanges_Policies(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").SetSchema(s).AddColumns(
+			schema.NewIntColumn("id", "int"),
+			schema.NewIntColumn("tenant_id", "int"),
+		)
+		tenant = &Policy{Name: "tenant", T: usr, Using: "(tenant_id = 1)"}
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddObject{O: &Policy{Name: "owner", T: usr, As: PolicyAsRestrictive, For: PolicyForUpdate, To: []string{"app", "current_user"}, Using: "id > 0", Check: "(id > 0)"}},
+		&schema.DropObject{O: tenant},
+		&schema.ModifyObject{From: tenant, To: &Policy{Name: "tenant", T: usr, To: []string{"app"}, Using: "(tenant_id = 2)"}},
+		&schema.ModifyObject{From: tenant, To: &Policy{Name: "tenant", T: usr, Using: "(tenant_id = 1)", Check: "(tenant_id = 1)"}},
+		&schema.ModifyObject{From: tenant, To: &Policy{Name: "tenant", T: usr, For: PolicyForSelect, Using: "(tenant_id = 1)"}},
+		&schema.ModifyTable{T: usr, Changes: []schema.Change{
+			&schema.AddColumn{C: usr.Columns[1]},
+			&schema.ModifyAttr{From: &RowSecurity{}, To: &RowSecurity{Enabled: true, Enforced: true}},
+		}},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	// Policies are dropped before the table columns are changed,
+	// and created after the table columns were added.
+	require.Equal(t, [][2]any{
+		{`DROP POLICY "tenant" ON "public"."users"`, `CREATE POLICY "tenant" ON "public"."users" USING (tenant_id = 1)`},
+		{`ALTER TABLE "public"."users" ADD COLUMN "tenant_id" integer NOT NULL, ENABLE ROW LEVEL SECURITY, FORCE ROW LEVEL SECURITY`, `ALTER TABLE "public"."users" DISABLE ROW LEVEL SECURITY, NO FORCE ROW LEVEL SECURITY, DROP COLUMN "tenant_id"`},
+		{`CREATE POLICY "owner" ON "public"."users" AS RESTRICTIVE FOR UPDATE TO "app", CURRENT_USER USING (id > 0) WITH CHECK (id > 0)`, `DROP POLICY "owner" ON "public"."users"`},
+		{`ALTER POLICY "tenant" ON "public"."users" TO "app" USING (tenant_id = 2)`, `ALTER POLICY "tenant" ON "public"."users" TO PUBLIC USING (tenant_id = 1)`},
+		{`ALTER POLICY "tenant" ON "public"."users" WITH CHECK (tenant_id = 1)`, []string{`DROP POLICY "tenant" ON "public"."users"`, `CREATE POLICY "tenant" ON "public"."users" USING (tenant_id = 1)`}},
+		{`DROP POLICY "tenant" ON "public"."users"`, `CREATE POLICY "tenant" ON "public"."users" USING (tenant_id = 1)`},
+		{`CREATE POLICY "tenant" ON "public"."users" FOR SELECT USING (tenant_id = 1)`, `DROP POLICY "tenant" ON "public"."users"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Row-level security is enabled after the table was created.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: schema.NewTable("pets").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int")).AddAttrs(&RowSecurity{Enabled: true})},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `ALTER TABLE "public"."pets" ENABLE ROW LEVEL SECURITY`, plan.Changes[1].Cmd)
+	require.Equal(t, `ALTER TABLE "public"."pets" DISABLE ROW LEVEL SECURITY`, plan.Changes[1].Reverse)
+}
+
+func TestPlanChanges_Sequences(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		orders = schema.NewTable("orders").SetSchema(s).AddColumns(
+			schema.NewIntColumn("id", "bigint").SetDefault(&schema.RawExpr{X: "nextval('orders_seq'::regclass)"}),
+		)
+		users = schema.NewTable("users").SetSchema(s).AddColumns(
+			schema.NewIntColumn("id", "bigint"),
+			schema.NewIntColumn("code", "int"),
+		)
+		ordersSeq = &Sequence{Name: "orders_seq", Schema: s, Type: &schema.IntegerType{T: TypeInteger}, Start: 100, Cache: 10, Attrs: []schema.Attr{&schema.Comment{Text: "order numbers"}}}
+		codesSeq  = &Sequence{Name: "codes", Schema: s, Increment: -1, Cycle: true}
+		usersSeq  = &Sequence{Name: "users_seq", Schema: s, Last: 50}
+	)
+	ordersSeq.Owner.T, ordersSeq.Owner.C = orders, orders.Columns[0]
+	codesSeq.Owner.T, codesSeq.Owner.C = users, users.Columns[1]
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: orders},
+		&schema.AddObject{O: ordersSeq},
+		&schema.AddObject{O: codesSeq},
+		&schema.ModifyTable{T: users, Changes: []schema.Change{
+			&schema.AddColumn{C: users.Columns[1]},
+		}},
+		&schema.ModifyObject{From: usersSeq, To: &Sequence{Name: "users_seq", Schema: s, Start: 100, Increment: 2, Cycle: true}},
+		&schema.ModifyObject{From: usersSeq, To: &Sequence{Name: "users_seq", Schema: s, Start: 10}},
+		&schema.DropObject{O: &Sequence{Name: "old_seq", Schema: s, Max: &usersSeq.Last}},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	// Sequences are created before the tables that use them, and after their owner tables otherwise.
+	require.Equal(t, [][2]any{
+		{`CREATE SEQUENCE "public"."orders_seq" AS integer START WITH 100 CACHE 10`, `DROP SEQUENCE "public"."orders_seq"`},
+		{`COMMENT ON SEQUENCE "public"."orders_seq" IS 'order numbers'`, `COMMENT ON SEQUENCE "public"."orders_seq" IS ''`},
+		{`CREATE TABLE "public"."orders" ("id" bigint NOT NULL DEFAULT nextval('orders_seq'::regclass))`, `DROP TABLE "public"."orders"`},
+		{`ALTER SEQUENCE "public"."orders_seq" OWNED BY "public"."orders"."id"`, `ALTER SEQUENCE "public"."orders_seq" OWNED BY NONE`},
+		{`ALTER TABLE "public"."users" ADD COLUMN "code" integer NOT NULL`, `ALTER TABLE "public"."users" DROP COLUMN "code"`},
+		{`CREATE SEQUENCE "public"."codes" INCREMENT BY -1 CYCLE OWNED BY "public"."users"."code"`, `DROP SEQUENCE "public"."codes"`},
+		{`ALTER SEQUENCE "public"."users_seq" INCREMENT BY 2 START WITH 100 RESTART CYCLE`, `ALTER SEQUENCE "public"."users_seq" INCREMENT BY 1 START WITH 1 NO CYCLE`},
+		{`ALTER SEQUENCE "public"."users_seq" START WITH 10`, `ALTER SEQUENCE "public"."users_seq" START WITH 1`},
+		{`DROP SEQUENCE "public"."old_seq"`, `CREATE SEQUENCE "public"."old_seq" MAXVALUE 50`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Sequences are dropped after the tables that use them.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropObject{O: &Sequence{Name: "orders_seq", Schema: s}},
+		&schema.DropTable{T: orders},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `DROP TABLE "public"."orders"`, plan.Changes[0].Cmd)
+	require.Equal(t, `DROP SEQUENCE "public"."orders_seq"`, plan.Changes[1].Cmd)
+}
+
+func TestPlanChanges_Partitions(t *testing.T) {
+	var (
+		s    = schema.New("public")
+		logs = schema.NewTable("logs").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+	)
+	logs.AddAttrs(
+		&Partition{T: PartitionTypeRange, Parts: []*PartitionPart{{C: logs.Columns[0]}}},
+		&PartitionBound{Name: "logs_1", Values: "FROM (1) TO (10)"},
+		&PartitionBound{Name: "logs_default", Default: true},
+	)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddTable{T: logs},
+		&schema.ModifyTable{T: logs, Changes: []schema.Change{
+			&schema.AddAttr{A: &PartitionBound{Name: "logs_2", Values: "FROM (10) TO (20)"}},
+			&schema.ModifyAttr{From: &PartitionBound{Name: "logs_3", Values: "FROM (20) TO (30)"}, To: &PartitionBound{Name: "logs_3", Values: "FROM (20) TO (40)"}},
+		}},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	require.Equal(t, [][2]any{
+		{`CREATE TABLE "public"."logs" ("id" integer NOT NULL) PARTITION BY RANGE ("id")`, `DROP TABLE "public"."logs"`},
+		{`CREATE TABLE "public"."logs_1" PARTITION OF "public"."logs" FOR VALUES FROM (1) TO (10)`, `DROP TABLE "public"."logs_1"`},
+		{`CREATE TABLE "public"."logs_default" PARTITION OF "public"."logs" DEFAULT`, `DROP TABLE "public"."logs_default"`},
+		{`CREATE TABLE "public"."logs_2" PARTITION OF "public"."logs" FOR VALUES FROM (10) TO (20)`, `DROP TABLE "public"."logs_2"`},
+		{`ALTER TABLE "public"."logs" DETACH PARTITION "public"."logs_3"`, `ALTER TABLE "public"."logs" ATTACH PARTITION "public"."logs_3" FOR VALUES FROM (20) TO (30)`},
+		{`ALTER TABLE "public"."logs" ATTACH PARTITION "public"."logs_3" FOR VALUES FROM (20) TO (40)`, `ALTER TABLE "public"."logs" DETACH PARTITION "public"."logs_3"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Dropped partitions are detached first.
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyTable{T: logs, Changes: []schema.Change{
+			&schema.DropAttr{A: &PartitionBound{Name: "logs_1", Values: "FROM (1) TO (10)"}},
+		}},
+	})
+	require.NoError(t, err)
+	require.False(t, plan.Reversible)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `ALTER TABLE "public"."logs" DETACH PARTITION "public"."logs_1"`, plan.Changes[0].Cmd)
+	require.Equal(t, `DROP TABLE "public"."logs_1"`, plan.Changes[1].Cmd)
+}
+
+func TestPlanChanges_MaterializedViews(t *testing.T) {
+	var (
+		s  = schema.New("public")
+		m1 = schema.NewMaterializedView("m1", "SELECT id, name FROM users").
+			SetSchema(s).
+			SetComment("users").
+			AddColumns(schema.NewIntColumn("id", "int").SetNull(true), schema.NewStringColumn("name", "text").SetNull(true))
+	)
+	m1.AddIndexes(schema.NewUniqueIndex("m1_id").AddColumns(m1.Columns[0]))
+	m2 := schema.NewMaterializedView("m2", "SELECT name FROM users").SetSchema(s).AddAttrs(&WithNoData{})
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.AddView{V: m1},
+		&schema.AddView{V: m2},
+		&schema.DropView{V: m2},
+		// Regular views are not supported by this version.
+		&schema.AddView{V: schema.NewView("v1", "SELECT 1").SetSchema(s)},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	require.Equal(t, [][2]any{
+		{`CREATE MATERIALIZED VIEW "public"."m1" AS SELECT id, name FROM users`, `DROP MATERIALIZED VIEW "public"."m1"`},
+		{`CREATE UNIQUE INDEX "m1_id" ON "public"."m1" ("id")`, `DROP INDEX "public"."m1_id"`},
+		{`COMMENT ON MATERIALIZED VIEW "public"."m1" IS 'users'`, `COMMENT ON MATERIALIZED VIEW "public"."m1" IS ''`},
+		{`CREATE MATERIALIZED VIEW "public"."m2" AS SELECT name FROM users WITH NO DATA`, `DROP MATERIALIZED VIEW "public"."m2"`},
+		{`DROP MATERIALIZED VIEW "public"."m2"`, `CREATE MATERIALIZED VIEW "public"."m2" AS SELECT name FROM users WITH NO DATA`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Views are populated, and their indexes and comments are altered in place.
+	to := schema.NewMaterializedView("m2", "SELECT name FROM users").SetSchema(s).SetComment("names")
+	to.AddColumns(schema.NewStringColumn("name", "text").SetNull(true))
+	to.AddIndexes(schema.NewIndex("m2_name").AddColumns(to.Columns[0]))
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyView{From: m2, To: to, Changes: []schema.Change{
+			&schema.AddAttr{A: &schema.Comment{Text: "names"}},
+			&schema.DropAttr{A: &WithNoData{}},
+			&schema.AddIndex{I: to.Indexes[0]},
+		}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]any{
+		{`COMMENT ON MATERIALIZED VIEW "public"."m2" IS 'names'`, `COMMENT ON MATERIALIZED VIEW "public"."m2" IS ''`},
+		{`REFRESH MATERIALIZED VIEW "public"."m2"`, `REFRESH MATERIALIZED VIEW "public"."m2" WITH NO DATA`},
+		{`CREATE INDEX "m2_name" ON "public"."m2" ("name")`, `DROP INDEX "public"."m2_name"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Changing the view query requires recreating it.
+	to = schema.NewMaterializedView("m2", "SELECT lower(name) FROM users").SetSchema(s)
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyView{From: m2, To: to},
+	})
+	require.NoError(t, err)
+	require.Equal(t, [][2]any{
+		{`DROP MATERIALIZED VIEW "public"."m2"`, `CREATE MATERIALIZED VIEW "public"."m2" AS SELECT name FROM users WITH NO DATA`},
+		{`CREATE MATERIALIZED VIEW "public"."m2" AS SELECT lower(name) FROM users`, `DROP MATERIALIZED VIEW "public"."m2"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// The privileges and the owner of recreated views are restored.
+	from := schema.NewMaterializedView("m2", "SELECT name FROM users").SetSchema(s).AddAttrs(
+		&Owner{Name: "admin"},
+		&ACL{Items: []string{"admin=arwdDxtm/admin", "=r/admin", `"my role"=r*w/admin`}},
+	)
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.ModifyView{From: from, To: to},
+	})
+	require.NoError(t, err)
+	require.True(t, plan.Reversible)
+	require.Equal(t, [][2]any{
+		{`DROP MATERIALIZED VIEW "public"."m2"`, []string{
+			`CREATE MATERIALIZED VIEW "public"."m2" AS SELECT name FROM users`,
+			`GRANT SELECT ON TABLE "public"."m2" TO PUBLIC`,
+			`GRANT UPDATE ON TABLE "public"."m2" TO "my role"`,
+			`GRANT SELECT ON TABLE "public"."m2" TO "my role" WITH GRANT OPTION`,
+			`ALTER MATERIALIZED VIEW "public"."m2" OWNER TO "admin"`,
+		}},
+		{`CREATE MATERIALIZED VIEW "public"."m2" AS SELECT lower(name) FROM users`, `DROP MATERIALIZED VIEW "public"."m2"`},
+		{`GRANT SELECT ON TABLE "public"."m2" TO PUBLIC`, `REVOKE SELECT ON TABLE "public"."m2" FROM PUBLIC`},
+		{`GRANT UPDATE ON TABLE "public"."m2" TO "my role"`, `REVOKE UPDATE ON TABLE "public"."m2" FROM "my role"`},
+		{`GRANT SELECT ON TABLE "public"."m2" TO "my role" WITH GRANT OPTION`, `REVOKE SELECT ON TABLE "public"."m2" FROM "my role"`},
+		{`ALTER MATERIALIZED VIEW "public"."m2" OWNER TO "admin"`, `ALTER MATERIALIZED VIEW "public"."m2" OWNER TO "admin"`},
+	}, func() (cs [][2]any) {
+		for _, c := range plan.Changes {
+			cs = append(cs, [2]any{c.Cmd, c.Reverse})
+		}
+		return cs
+	}())
+
+	// Dropped tables are restored with their privileges and owner on revert.
+	users := schema.NewTable("users").SetSchema(s).AddColumns(schema.NewIntColumn("id", "int"))
+	users.AddAttrs(&Owner{Name: "admin"}, &ACL{Items: []string{"bob=ad/admin"}})
+	plan, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropTable{T: users},
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	require.Equal(t, []string{
+		`CREATE TABLE "public"."users" ("id" integer NOT NULL)`,
+		`GRANT INSERT, DELETE ON TABLE "public"."users" TO "bob"`,
+		`ALTER TABLE "public"."users" OWNER TO "admin"`,
+	}, plan.Changes[0].Reverse)
+
+	// Unknown privileges fail the planning, instead of being silently dropped.
+	schema.ReplaceOrAppend(&from.Attrs, &ACL{Items: []string{"bob=rZ/admin"}})
+	_, err = DefaultPlan.PlanChanges(context.Background(), "plan", []schema.Change{
+		&schema.DropView{V: from},
+	})
+	require.EqualError(t, err, `calculate reverse for drop materialized view "m2": postgres: unknown privilege 'Z' in ACL item "bob=rZ/admin"`)
+}
+
 func TestIndentedPlan(t *testing.T) {
 	tests := []struct {
 		T   *schema.Table
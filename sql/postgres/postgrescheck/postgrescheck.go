@@ -5,16 +5,24 @@
 package postgrescheck
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/design"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
 	"ariga.io/atlas/sql/sqlcheck/naming"
+	"ariga.io/atlas/sql/sqlcheck/narrowing"
 )
 
 func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err error) {
@@ -33,6 +41,194 @@ func addNotNull(p *datadepend.ColumnPass) (diags []sqlcheck.Diagnostic, err erro
 	}, nil
 }
 
+// codeDropLO is a PostgreSQL specific code for reporting dropped large object references.
+var codeDropLO = sqlcheck.Code("PG101")
+
+type (
+	// LargeObject checks for dropped columns that reference large objects (oid or lo).
+	// Dropping such columns does not delete the large objects themselves, and leaves
+	// them orphaned in pg_largeobject.
+	LargeObject struct {
+		sqlcheck.Options
+		LargeObjectOptions
+	}
+	// LargeObjectOptions defines the driver-specific options of the LargeObject analyzer.
+	LargeObjectOptions struct {
+		// Unlink indicates if a cleanup statement that unlinks the referenced
+		// large objects should be suggested before the column is dropped.
+		Unlink bool `spec:"unlink"`
+	}
+)
+
+// NewLargeObject creates a new large object Analyzer with the given options.
+func NewLargeObject(r *schemahcl.Resource) (*LargeObject, error) {
+	az := &LargeObject{}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing large_object check options: %w", err)
+		}
+		if err := r.As(&az.LargeObjectOptions); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing large_object check options: %w", err)
+		}
+	}
+	return az, nil
+}
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*LargeObject) Name() string {
+	return "large_object"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *LargeObject) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			var (
+				t    *schema.Table
+				cols []*schema.Column
+			)
+			switch c := c.(type) {
+			case *schema.DropTable:
+				if p.File.SchemaSpan(c.T.Schema) == sqlcheck.SpanDropped || p.File.TableSpan(c.T) == sqlcheck.SpanTemporary {
+					continue
+				}
+				t = c.T
+				for _, col := range c.T.Columns {
+					if col.Type != nil && isLargeObject(col.Type.Type) {
+						cols = append(cols, col)
+					}
+				}
+			case *schema.ModifyTable:
+				t = c.T
+				for _, mc := range c.Changes {
+					if d, ok := mc.(*schema.DropColumn); ok && p.File.ColumnSpan(c.T, d.C) != sqlcheck.SpanTemporary && d.C.Type != nil && isLargeObject(d.C.Type.Type) {
+						cols = append(cols, d.C)
+					}
+				}
+			}
+			for _, col := range cols {
+				d := sqlcheck.Diagnostic{
+					Code: codeDropLO,
+					Pos:  sc.Stmt.Pos,
+					Text: fmt.Sprintf("Dropping large object column %q of table %q leaves its referenced large objects orphaned", col.Name, t.Name),
+				}
+				if a.Unlink {
+					d.SuggestFix(
+						fmt.Sprintf("Unlink the large objects referenced by column %q before dropping it", col.Name),
+						unlinkEdit(p, sc.Stmt, t, col),
+					)
+				} else {
+					d.SuggestFix(fmt.Sprintf("Unlink the large objects referenced by column %q before dropping it, or use vacuumlo to remove them", col.Name), nil)
+				}
+				diags = append(diags, d)
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "large object references dropped"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// isLargeObject reports if the given type holds references to large objects.
+func isLargeObject(t schema.Type) bool {
+	switch t := t.(type) {
+	case *postgres.OIDType:
+		return t.T == "oid"
+	case *postgres.DomainType:
+		return t.T == "lo"
+	case *postgres.UserDefinedType:
+		return t.T == "lo" || strings.HasSuffix(t.T, ".lo")
+	}
+	return false
+}
+
+// unlinkEdit returns a text edit that prepends a statement unlinking the large
+// objects referenced by the column to the statement that drops it.
+func unlinkEdit(p *sqlcheck.Pass, stmt *migrate.Stmt, t *schema.Table, c *schema.Column) *sqlcheck.TextEdit {
+	name := ident(t.Name)
+	if t.Schema != nil && t.Schema.Name != "" {
+		name = ident(t.Schema.Name) + "." + name
+	}
+	unlink := fmt.Sprintf("SELECT lo_unlink(%s) FROM %s WHERE %[1]s IS NOT NULL;", ident(c.Name), name)
+	line := 1
+	if p.File.File != nil {
+		if b := p.File.Bytes(); stmt.Pos <= len(b) {
+			line += strings.Count(string(b[:stmt.Pos]), "\n")
+		}
+	}
+	return &sqlcheck.TextEdit{
+		Line:    line,
+		End:     line + strings.Count(stmt.Text, "\n"),
+		NewText: unlink + "\n" + stmt.Text,
+	}
+}
+
+// codeSetTablespace is a PostgreSQL specific code for reporting tables or indexes moved to another tablespace.
+var codeSetTablespace = sqlcheck.Code("PG102")
+
+// setTablespace is an analyzer function that detects tables and indexes that are moved to another
+// tablespace. SET TABLESPACE copies all data files of the relation while holding an ACCESS EXCLUSIVE
+// lock on it, which blocks both reads and writes until the copy is done.
+func setTablespace(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			if !ok {
+				continue
+			}
+			for _, mc := range m.Changes {
+				var text string
+				switch mc := mc.(type) {
+				case *schema.ModifyAttr:
+					if to, ok := mc.To.(*postgres.Tablespace); ok {
+						text = fmt.Sprintf("Moving table %q to tablespace %q rewrites the table", m.T.Name, tablespace(to))
+					}
+				case *schema.ModifyIndex:
+					if from, to := tablespace(mc.From.Attrs...), tablespace(mc.To.Attrs...); from != to {
+						text = fmt.Sprintf("Moving index %q of table %q to tablespace %q rewrites the index", mc.To.Name, m.T.Name, to)
+					}
+				case *schema.ModifyPrimaryKey:
+					if from, to := tablespace(mc.From.Attrs...), tablespace(mc.To.Attrs...); from != to {
+						text = fmt.Sprintf("Moving the primary key of table %q to tablespace %q rewrites the index", m.T.Name, to)
+					}
+				}
+				if text != "" {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Pos:  sc.Stmt.Pos,
+						Code: codeSetTablespace,
+						Text: text + ", and blocks reads and writes until it is done",
+					})
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		p.Reporter.WriteReport(sqlcheck.Report{Text: "tablespace change detected", Diagnostics: diags})
+	}
+	return nil
+}
+
+// tablespace returns the tablespace defined in the given attributes, or the default one.
+func tablespace(attrs ...schema.Attr) string {
+	var ts postgres.Tablespace
+	if !sqlx.Has(attrs, &ts) || ts.V == "" {
+		return "pg_default"
+	}
+	return ts.V
+}
+
+// ident quotes the given identifier.
+func ident(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
 func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	ds, err := destructive.New(r)
 	if err != nil {
@@ -52,9 +248,21 @@ func analyzers(r *schemahcl.Resource) ([]sqlcheck.Analyzer, error) {
 	if err != nil {
 		return nil, err
 	}
+	nr, err := narrowing.New(r)
+	if err != nil {
+		return nil, err
+	}
 	nm, err := naming.New(r)
 	if err != nil {
 		return nil, err
 	}
-	return []sqlcheck.Analyzer{ds, dd, cd, bc, nm}, nil
+	sd, err := design.New(r)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := NewLargeObject(r)
+	if err != nil {
+		return nil, err
+	}
+	return []sqlcheck.Analyzer{ds, dd, cd, bc, nr, nm, sd, lo, sqlcheck.AnalyzerFunc(setTablespace)}, nil
 }
@@ -10,7 +10,7 @@ import (
 
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/postgres"
-	_ "ariga.io/atlas/sql/postgres/postgrescheck"
+	"ariga.io/atlas/sql/postgres/postgrescheck"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlcheck"
 
@@ -55,11 +55,116 @@ func TestDataDepend_MightFail(t *testing.T) {
 	require.Equal(t, report.Diagnostics[0].Text, `Adding a non-nullable "int" column "b" will fail in case table "users" is not empty`)
 }
 
+func TestLargeObject_Drop(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").
+			SetSchema(schema.New("public")).
+			AddColumns(
+				schema.NewIntColumn("id", postgres.TypeInt),
+				schema.NewColumn("avatar").SetType(&postgres.OIDType{T: "oid"}),
+			)
+		pass = &sqlcheck.Pass{
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql", bytes: "-- comment\nALTER TABLE \"users\" DROP COLUMN \"avatar\";\n"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  11,
+							Text: `ALTER TABLE "users" DROP COLUMN "avatar";`,
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: []schema.Change{
+									&schema.DropColumn{C: users.Columns[1]},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	az, err := postgrescheck.NewLargeObject(nil)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, `Dropping large object column "avatar" of table "users" leaves its referenced large objects orphaned`, report.Diagnostics[0].Text)
+	require.Nil(t, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+
+	az.Unlink = true
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, &sqlcheck.TextEdit{
+		Line:    2,
+		End:     2,
+		NewText: "SELECT lo_unlink(\"avatar\") FROM \"public\".\"users\" WHERE \"avatar\" IS NOT NULL;\nALTER TABLE \"users\" DROP COLUMN \"avatar\";",
+	}, report.Diagnostics[0].SuggestedFixes[0].TextEdit)
+}
+
+func TestSetTablespace(t *testing.T) {
+	var (
+		report *sqlcheck.Report
+		users  = schema.NewTable("users").
+			SetSchema(schema.New("public")).
+			AddColumns(schema.NewIntColumn("id", postgres.TypeInt))
+		pass = &sqlcheck.Pass{
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Text: `ALTER TABLE "users" SET TABLESPACE "archive"`,
+						},
+						Changes: schema.Changes{
+							&schema.ModifyTable{
+								T: users,
+								Changes: []schema.Change{
+									&schema.ModifyAttr{From: &postgres.Tablespace{}, To: &postgres.Tablespace{V: "archive"}},
+									&schema.ModifyIndex{
+										From:   schema.NewIndex("users_id").AddColumns(users.Columns[0]).AddAttrs(&postgres.Tablespace{V: "fast"}),
+										To:     schema.NewIndex("users_id").AddColumns(users.Columns[0]),
+										Change: schema.ChangeAttr,
+									},
+									&schema.ModifyIndex{
+										From:   schema.NewIndex("users_id2").AddColumns(users.Columns[0]),
+										To:     schema.NewIndex("users_id2").AddColumns(users.Columns[0]).SetComment("c"),
+										Change: schema.ChangeComment,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	azs, err := sqlcheck.AnalyzerFor(postgres.DriverName, nil)
+	require.NoError(t, err)
+	require.NoError(t, sqlcheck.Analyzers(azs).Analyze(context.Background(), pass))
+	require.NotNil(t, report)
+	require.Equal(t, "tablespace change detected", report.Text)
+	require.Len(t, report.Diagnostics, 2)
+	require.Equal(t, "PG102", report.Diagnostics[0].Code)
+	require.Equal(t, `Moving table "users" to tablespace "archive" rewrites the table, and blocks reads and writes until it is done`, report.Diagnostics[0].Text)
+	require.Equal(t, `Moving index "users_id" of table "users" to tablespace "pg_default" rewrites the index, and blocks reads and writes until it is done`, report.Diagnostics[1].Text)
+}
+
 type testFile struct {
-	name string
+	name, bytes string
 	migrate.File
 }
 
+func (t testFile) Bytes() []byte {
+	return []byte(t.bytes)
+}
+
 func (t testFile) Name() string {
 	return t.name
 }
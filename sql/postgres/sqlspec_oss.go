@@ -7,8 +7,10 @@
 package postgres
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -39,6 +41,10 @@ type (
 		Policies      []*policy           `spec:"policy"`
 		EventTriggers []*eventTrigger     `spec:"event_trigger"`
 		Extensions    []*extension        `spec:"extension"`
+		Languages     []*language         `spec:"language"`
+		Casts         []*cast             `spec:"cast"`
+		Databases     []*database         `spec:"database"`
+		Statistics    []*statistics       `spec:"statistics"`
 		Schemas       []*sqlspec.Schema   `spec:"schema"`
 	}
 
@@ -89,6 +95,55 @@ type (
 		schemahcl.DefaultExtension
 	}
 
+	// language holds a specification for a procedural language.
+	// Note, language names are unique within a realm (database).
+	language struct {
+		Name      string `spec:",name"`
+		Trusted   bool   `spec:"trusted,omitempty"`
+		Handler   string `spec:"handler,omitempty"`
+		Inline    string `spec:"inline,omitempty"`
+		Validator string `spec:"validator,omitempty"`
+		Comment   string `spec:"comment,omitempty"`
+	}
+
+	// cast holds a specification for a user-defined cast.
+	// Note, casts are identified by their source and target types.
+	cast struct {
+		Source   string         `spec:"source"`
+		Target   string         `spec:"target"`
+		Method   *schemahcl.Ref `spec:"method,omitempty"`
+		Function string         `spec:"function,omitempty"`
+		As       *schemahcl.Ref `spec:"as,omitempty"`
+		Comment  string         `spec:"comment,omitempty"`
+	}
+
+	// database holds a specification for the comment and the settings of the
+	// database. Note, a realm describes a single database, and its name is used
+	// when planning changes.
+	database struct {
+		Name     string             `spec:",name"`
+		Comment  string             `spec:"comment,omitempty"`
+		Settings []*databaseSetting `spec:"setting"`
+	}
+
+	// databaseSetting holds a specification for a database configuration parameter.
+	databaseSetting struct {
+		Name  string `spec:",name"`
+		Value string `spec:"value"`
+	}
+
+	// statistics holds a specification for an extended statistics object.
+	statistics struct {
+		Name      string           `spec:",name"`
+		Qualifier string           `spec:",qualifier"`
+		Schema    *schemahcl.Ref   `spec:"schema"`
+		Table     *schemahcl.Ref   `spec:"table"`
+		Columns   []*schemahcl.Ref `spec:"columns,omitempty"`
+		Exprs     []string         `spec:"exprs,omitempty"`
+		Kinds     []string         `spec:"kinds,omitempty"`
+		Comment   string           `spec:"comment,omitempty"`
+	}
+
 	// eventTrigger holds a specification for a postgres event trigger.
 	// Note, event trigger names are unique within a realm (database).
 	eventTrigger struct {
@@ -112,10 +167,13 @@ type (
 	// Policy defines row-level security policy for a table.
 	// See: https://www.postgresql.org/docs/current/view-pg-policies.html.
 	policy struct {
-		Name string         `spec:",name"`
-		On   *schemahcl.Ref `spec:"on"`
-		// Rest of the attributes are appended after the table reference.
-		schemahcl.DefaultExtension
+		Name  string         `spec:",name"`
+		On    *schemahcl.Ref `spec:"on"`
+		As    *schemahcl.Ref `spec:"as,omitempty"`
+		For   *schemahcl.Ref `spec:"for,omitempty"`
+		To    []string       `spec:"to,omitempty"`
+		Using string         `spec:"using,omitempty"`
+		Check string         `spec:"check,omitempty"`
 	}
 )
 
@@ -132,6 +190,10 @@ func (d *doc) merge(d1 *doc) {
 	d.Aggregates = append(d.Aggregates, d1.Aggregates...)
 	d.Sequences = append(d.Sequences, d1.Sequences...)
 	d.Extensions = append(d.Extensions, d1.Extensions...)
+	d.Languages = append(d.Languages, d1.Languages...)
+	d.Casts = append(d.Casts, d1.Casts...)
+	d.Databases = append(d.Databases, d1.Databases...)
+	d.Statistics = append(d.Statistics, d1.Statistics...)
 	d.Triggers = append(d.Triggers, d1.Triggers...)
 	d.Policies = append(d.Policies, d1.Policies...)
 	d.EventTriggers = append(d.EventTriggers, d1.EventTriggers...)
@@ -198,6 +260,18 @@ func (a *aggregate) SetQualifier(q string) { a.Qualifier = q }
 // SchemaRef returns the schema reference for the aggregate.
 func (a *aggregate) SchemaRef() *schemahcl.Ref { return a.Schema }
 
+// Label returns the defaults label used for the statistics resource.
+func (s *statistics) Label() string { return s.Name }
+
+// QualifierLabel returns the qualifier label used for the statistics resource, if any.
+func (s *statistics) QualifierLabel() string { return s.Qualifier }
+
+// SetQualifier sets the qualifier label used for the statistics resource.
+func (s *statistics) SetQualifier(q string) { s.Qualifier = q }
+
+// SchemaRef returns the schema reference for the statistics.
+func (s *statistics) SchemaRef() *schemahcl.Ref { return s.Schema }
+
 func init() {
 	schemahcl.Register("enum", &enum{})
 	schemahcl.Register("domain", &domain{})
@@ -241,12 +315,24 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		if err := convertPolicies(d.Tables, d.Policies, v); err != nil {
 			return err
 		}
+		if err := convertStatistics(d.Statistics, v); err != nil {
+			return err
+		}
 		if err := convertExtensions(d.Extensions, v); err != nil {
 			return err
 		}
 		if err := convertEventTriggers(d.EventTriggers, v); err != nil {
 			return err
 		}
+		if err := convertLanguages(d.Languages, v); err != nil {
+			return err
+		}
+		if err := convertCasts(d.Casts, v); err != nil {
+			return err
+		}
+		if err := convertDatabases(d.Databases, v); err != nil {
+			return err
+		}
 		if err := normalizeRealm(v); err != nil {
 			return err
 		}
@@ -274,7 +360,10 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		if err := convertPolicies(d.Tables, d.Policies, r); err != nil {
 			return err
 		}
-		// Extensions are skipped in schema scope.
+		if err := convertStatistics(d.Statistics, r); err != nil {
+			return err
+		}
+		// Extensions, languages, casts and databases are skipped in schema scope.
 		if err := normalizeRealm(r); err != nil {
 			return err
 		}
@@ -340,6 +429,9 @@ func (c *Codec) MarshalSpec(v any) ([]byte, error) {
 		if err := specutil.QualifyObjects(d.Sequences); err != nil {
 			return nil, err
 		}
+		if err := specutil.QualifyObjects(d.Statistics); err != nil {
+			return nil, err
+		}
 		if err := specutil.QualifyObjects(d.Funcs); err != nil {
 			return nil, err
 		}
@@ -364,6 +456,8 @@ var (
 			schemahcl.WithTypes("table.column.type", TypeRegistry.Specs()),
 			schemahcl.WithTypes("view.column.type", TypeRegistry.Specs()),
 			schemahcl.WithTypes("materialized.column.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("composite.field.type", TypeRegistry.Specs()),
+			schemahcl.WithTypes("sequence.type", TypeRegistry.Specs()),
 			schemahcl.WithScopedEnums("view.check_option", schema.ViewCheckOptionLocal, schema.ViewCheckOptionCascaded),
 			schemahcl.WithScopedEnums("table.index.type", IndexTypeBTree, IndexTypeBRIN, IndexTypeHash, IndexTypeGIN, IndexTypeGiST, "GiST", IndexTypeSPGiST, "SPGiST"),
 			schemahcl.WithScopedEnums("table.partition.type", PartitionTypeRange, PartitionTypeList, PartitionTypeHash),
@@ -371,6 +465,11 @@ var (
 			schemahcl.WithScopedEnums("table.column.as.type", "STORED"),
 			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
 			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.column.diff.ignore", specutil.DiffIgnoreVars...),
+			schemahcl.WithScopedEnums("cast.method", CastMethodFunc, CastMethodBinary, CastMethodInOut),
+			schemahcl.WithScopedEnums("cast.as", CastContextExplicit, CastContextAssignment, CastContextImplicit),
+			schemahcl.WithScopedEnums("policy.as", PolicyAsPermissive, PolicyAsRestrictive),
+			schemahcl.WithScopedEnums("policy.for", PolicyForAll, PolicyForSelect, PolicyForInsert, PolicyForUpdate, PolicyForDelete),
 			schemahcl.WithScopedEnums("table.index.on.ops", func() (ops []string) {
 				for _, op := range postgresop.Classes {
 					ops = append(ops, op.Name)
@@ -388,6 +487,592 @@ var (
 	EvalHCLBytes = specutil.HCLBytesFunc(codec)
 )
 
+// convertLanguages converts the language specs to realm objects.
+func convertLanguages(specs []*language, r *schema.Realm) error {
+	for _, spec := range specs {
+		if _, ok := realmLanguage(r, spec.Name); ok {
+			return fmt.Errorf("duplicate language %q", spec.Name)
+		}
+		l := &Language{
+			Name:      spec.Name,
+			Trusted:   spec.Trusted,
+			Handler:   spec.Handler,
+			Inline:    spec.Inline,
+			Validator: spec.Validator,
+		}
+		if l.Handler == "" && (l.Inline != "" || l.Validator != "") {
+			return fmt.Errorf("language %q: handler is required when inline or validator are set", spec.Name)
+		}
+		if spec.Comment != "" {
+			l.Attrs = append(l.Attrs, &schema.Comment{Text: spec.Comment})
+		}
+		r.Objects = append(r.Objects, l)
+	}
+	return nil
+}
+
+// convertCasts converts the cast specs to realm objects.
+func convertCasts(specs []*cast, r *schema.Realm) error {
+	for _, spec := range specs {
+		if spec.Source == "" || spec.Target == "" {
+			return errors.New("cast: source and target types are required")
+		}
+		if _, ok := realmCast(r, spec.Source, spec.Target); ok {
+			return fmt.Errorf("duplicate cast (%s AS %s)", spec.Source, spec.Target)
+		}
+		c := &Cast{
+			Source:  spec.Source,
+			Target:  spec.Target,
+			Func:    spec.Function,
+			Method:  CastMethodFunc,
+			Context: CastContextExplicit,
+		}
+		if spec.Method != nil {
+			c.Method = strings.ToUpper(spec.Method.V)
+		}
+		if spec.As != nil {
+			c.Context = strings.ToUpper(spec.As.V)
+		}
+		switch {
+		case c.Method == CastMethodFunc && c.Func == "":
+			return fmt.Errorf("cast (%s AS %s): function is required", c.Source, c.Target)
+		case c.Method != CastMethodFunc && c.Func != "":
+			return fmt.Errorf("cast (%s AS %s): function cannot be set with method %s", c.Source, c.Target, c.Method)
+		}
+		if spec.Comment != "" {
+			c.Attrs = append(c.Attrs, &schema.Comment{Text: spec.Comment})
+		}
+		r.Objects = append(r.Objects, c)
+	}
+	return nil
+}
+
+// convertDatabases converts the database specs to realm objects.
+func convertDatabases(specs []*database, r *schema.Realm) error {
+	switch {
+	case len(specs) == 0:
+		return nil
+	case len(specs) > 1:
+		return fmt.Errorf("expect a single database definition, got %d", len(specs))
+	}
+	spec := specs[0]
+	d := &Database{Name: spec.Name}
+	for _, s := range spec.Settings {
+		if s.Name == "" {
+			return fmt.Errorf("database %q: setting name is required", spec.Name)
+		}
+		if _, ok := d.Setting(s.Name); ok {
+			return fmt.Errorf("database %q: duplicate setting %q", spec.Name, s.Name)
+		}
+		d.Settings = append(d.Settings, &DatabaseSetting{Name: s.Name, Value: s.Value})
+	}
+	slices.SortFunc(d.Settings, func(a, b *DatabaseSetting) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	if spec.Comment != "" {
+		d.Attrs = append(d.Attrs, &schema.Comment{Text: spec.Comment})
+	}
+	r.Objects = append(r.Objects, d)
+	return nil
+}
+
+// convertStatistics converts the statistics specs to schema objects.
+func convertStatistics(specs []*statistics, r *schema.Realm) error {
+	for _, spec := range specs {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from statistics reference: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on statistics %q was not found in realm", ns, spec.Name)
+		}
+		if _, ok := schemaStatistics(s, spec.Name); ok {
+			return fmt.Errorf("duplicate statistics %q in schema %q", spec.Name, ns)
+		}
+		if spec.Table == nil {
+			return fmt.Errorf("statistics %q: table is required", spec.Name)
+		}
+		q, name, err := specutil.TableName(spec.Table)
+		if err != nil {
+			return fmt.Errorf("statistics %q: %w", spec.Name, err)
+		}
+		ts := s
+		if q != "" {
+			if ts, ok = r.Schema(q); !ok {
+				return fmt.Errorf("statistics %q: schema %q was not found in realm", spec.Name, q)
+			}
+		}
+		t, ok := ts.Table(name)
+		if !ok {
+			return fmt.Errorf("statistics %q: table %q was not found in schema %q", spec.Name, name, ts.Name)
+		}
+		st := &Statistics{Name: spec.Name, Schema: s, T: t, Exprs: spec.Exprs}
+		for _, ref := range spec.Columns {
+			c, err := specutil.ColumnByRef(t, ref)
+			if err != nil {
+				return fmt.Errorf("statistics %q: %w", spec.Name, err)
+			}
+			st.Columns = append(st.Columns, c)
+		}
+		if len(st.Columns) == 0 && len(st.Exprs) == 0 {
+			return fmt.Errorf("statistics %q: columns or expressions are required", spec.Name)
+		}
+		for _, k := range spec.Kinds {
+			switch k = strings.ToUpper(k); k {
+			case StatisticsKindNDistinct, StatisticsKindDependencies, StatisticsKindMCV:
+				st.Kinds = append(st.Kinds, k)
+			default:
+				return fmt.Errorf("statistics %q: unknown kind %q", spec.Name, k)
+			}
+		}
+		if spec.Comment != "" {
+			st.Attrs = append(st.Attrs, &schema.Comment{Text: spec.Comment})
+		}
+		s.AddObjects(st)
+	}
+	return nil
+}
+
+// convertPolicies converts the policy specs to schema objects, and adds them to the schemas of their tables.
+func convertPolicies(_ []*sqlspec.Table, specs []*policy, r *schema.Realm) error {
+	for _, spec := range specs {
+		if spec.On == nil {
+			return fmt.Errorf("policy %q: table is required", spec.Name)
+		}
+		t, err := policyTable(spec, r)
+		if err != nil {
+			return err
+		}
+		if _, ok := schemaPolicy(t.Schema, t.Name, spec.Name); ok {
+			return fmt.Errorf("duplicate policy %q on table %q", spec.Name, t.Name)
+		}
+		p := &Policy{Name: spec.Name, T: t, To: spec.To, Using: spec.Using, Check: spec.Check}
+		if spec.As != nil {
+			p.As = strings.ToUpper(spec.As.V)
+		}
+		if spec.For != nil {
+			p.For = strings.ToUpper(spec.For.V)
+		}
+		switch pAs, pFor := policyAs(p), policyFor(p); {
+		case pAs != PolicyAsPermissive && pAs != PolicyAsRestrictive:
+			return fmt.Errorf("policy %q: unknown type %q", spec.Name, p.As)
+		case pFor != PolicyForAll && pFor != PolicyForSelect && pFor != PolicyForInsert && pFor != PolicyForUpdate && pFor != PolicyForDelete:
+			return fmt.Errorf("policy %q: unknown command %q", spec.Name, p.For)
+		case pFor == PolicyForInsert && p.Using != "":
+			return fmt.Errorf("policy %q: only a check expression can be defined for %s", spec.Name, pFor)
+		case (pFor == PolicyForSelect || pFor == PolicyForDelete) && p.Check != "":
+			return fmt.Errorf("policy %q: only a using expression can be defined for %s", spec.Name, pFor)
+		}
+		t.Schema.AddObjects(p)
+	}
+	return nil
+}
+
+// policyTable returns the table referenced by the policy. In case the reference
+// is not qualified, the table is searched in all schemas, and must be unique.
+func policyTable(spec *policy, r *schema.Realm) (*schema.Table, error) {
+	q, name, err := specutil.TableName(spec.On)
+	if err != nil {
+		return nil, fmt.Errorf("policy %q: %w", spec.Name, err)
+	}
+	if q != "" {
+		s, ok := r.Schema(q)
+		if !ok {
+			return nil, fmt.Errorf("policy %q: schema %q was not found in realm", spec.Name, q)
+		}
+		t, ok := s.Table(name)
+		if !ok {
+			return nil, fmt.Errorf("policy %q: table %q was not found in schema %q", spec.Name, name, q)
+		}
+		return t, nil
+	}
+	var ts []*schema.Table
+	for _, s := range r.Schemas {
+		if t, ok := s.Table(name); ok {
+			ts = append(ts, t)
+		}
+	}
+	switch len(ts) {
+	case 0:
+		return nil, fmt.Errorf("policy %q: table %q was not found in realm", spec.Name, name)
+	case 1:
+		return ts[0], nil
+	default:
+		return nil, fmt.Errorf("policy %q: multiple tables found for %q, use a qualified reference", spec.Name, name)
+	}
+}
+
+// convertSequences converts the sequence specs to schema objects, and adds them to their schemas.
+func convertSequences(_ []*sqlspec.Table, specs []*sqlspec.Sequence, r *schema.Realm) error {
+	for _, spec := range specs {
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return fmt.Errorf("extract schema name from sequence reference: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return fmt.Errorf("schema %q defined on sequence %q was not found in realm", ns, spec.Name)
+		}
+		if _, ok := schemaSequence(s, spec.Name); ok {
+			return fmt.Errorf("duplicate sequence %q in schema %q", spec.Name, ns)
+		}
+		seq := &Sequence{Name: spec.Name, Schema: s}
+		if a, ok := spec.Attr("type"); ok {
+			t, err := a.Type()
+			if err != nil {
+				return fmt.Errorf("sequence %q: %w", spec.Name, err)
+			}
+			if seq.Type, err = TypeRegistry.Type(t, nil); err != nil {
+				return fmt.Errorf("sequence %q: %w", spec.Name, err)
+			}
+			switch t, ok := seq.Type.(*schema.IntegerType); {
+			case !ok, !slices.Contains([]string{TypeSmallInt, TypeInt2, TypeInteger, TypeInt, TypeInt4, TypeBigInt, TypeInt8}, strings.ToLower(t.T)):
+				return fmt.Errorf("sequence %q: type must be one of smallint, integer or bigint", spec.Name)
+			}
+		}
+		for _, a := range []struct {
+			name string
+			v    **int64
+		}{
+			{"min_value", &seq.Min},
+			{"max_value", &seq.Max},
+		} {
+			if attr, ok := spec.Attr(a.name); ok {
+				v, err := attr.Int64()
+				if err != nil {
+					return fmt.Errorf("sequence %q attribute %q: %w", spec.Name, a.name, err)
+				}
+				*a.v = &v
+			}
+		}
+		for _, a := range []struct {
+			name string
+			v    *int64
+		}{
+			{"start", &seq.Start},
+			{"increment", &seq.Increment},
+			{"cache", &seq.Cache},
+		} {
+			if attr, ok := spec.Attr(a.name); ok {
+				if *a.v, err = attr.Int64(); err != nil {
+					return fmt.Errorf("sequence %q attribute %q: %w", spec.Name, a.name, err)
+				}
+			}
+		}
+		if attr, ok := spec.Attr("cycle"); ok {
+			if seq.Cycle, err = attr.Bool(); err != nil {
+				return fmt.Errorf("sequence %q attribute \"cycle\": %w", spec.Name, err)
+			}
+		}
+		if attr, ok := spec.Attr("owned_by"); ok {
+			if err := sequenceOwner(seq, attr); err != nil {
+				return err
+			}
+		}
+		if attr, ok := spec.Attr("comment"); ok {
+			c, err := attr.String()
+			if err != nil {
+				return fmt.Errorf("sequence %q attribute \"comment\": %w", spec.Name, err)
+			}
+			seq.Attrs = append(seq.Attrs, &schema.Comment{Text: c})
+		}
+		switch o := sequenceOptions(seq); {
+		case o.min > o.max:
+			return fmt.Errorf("sequence %q: min_value (%d) must be less than max_value (%d)", spec.Name, o.min, o.max)
+		case o.start < o.min || o.start > o.max:
+			return fmt.Errorf("sequence %q: start value (%d) must be between min_value (%d) and max_value (%d)", spec.Name, o.start, o.min, o.max)
+		}
+		s.AddObjects(seq)
+	}
+	return nil
+}
+
+// sequenceOwner sets the column owning the sequence from its reference. The
+// owner table must reside in the same schema as the sequence.
+func sequenceOwner(seq *Sequence, attr *schemahcl.Attr) error {
+	v, err := attr.Ref()
+	if err != nil {
+		return fmt.Errorf("sequence %q attribute \"owned_by\": %w", seq.Name, err)
+	}
+	ref := &schemahcl.Ref{V: v}
+	q, name, err := specutil.TableName(ref)
+	if err != nil {
+		return fmt.Errorf("sequence %q: %w", seq.Name, err)
+	}
+	if q != "" && q != seq.Schema.Name {
+		return fmt.Errorf("sequence %q: owner table %q must reside in schema %q", seq.Name, name, seq.Schema.Name)
+	}
+	t, ok := seq.Schema.Table(name)
+	if !ok {
+		return fmt.Errorf("sequence %q: table %q was not found in schema %q", seq.Name, name, seq.Schema.Name)
+	}
+	c, err := specutil.ColumnByRef(t, ref)
+	if err != nil {
+		return fmt.Errorf("sequence %q: %w", seq.Name, err)
+	}
+	seq.Owner.T, seq.Owner.C = t, c
+	return nil
+}
+
+// convertComposites converts the composite types specs into objects, and adds them to their schemas.
+// Fields can reference enums, or other composite types that are defined in the same document.
+func convertComposites(specs []*composite, enums map[string]*schema.EnumType, r *schema.Realm) (map[string]*CompositeType, error) {
+	byName := make(map[string]*CompositeType, len(specs))
+	for _, spec := range specs {
+		if byName[spec.Name] != nil {
+			return nil, fmt.Errorf("duplicate composite %q", spec.Name)
+		}
+		ns, err := specutil.SchemaName(spec.Schema)
+		if err != nil {
+			return nil, fmt.Errorf("extract schema name from composite reference: %w", err)
+		}
+		s, ok := r.Schema(ns)
+		if !ok {
+			return nil, fmt.Errorf("schema %q defined on composite %q was not found in realm", ns, spec.Name)
+		}
+		c := &CompositeType{T: spec.Name, Schema: s}
+		s.AddObjects(c)
+		byName[spec.Name] = c
+	}
+	// Fields are converted after all types were created,
+	// as they may reference types that are defined later.
+	for _, spec := range specs {
+		c := byName[spec.Name]
+		if len(spec.Fields) == 0 {
+			return nil, fmt.Errorf("composite %q: at least one field is required", spec.Name)
+		}
+		for _, f := range spec.Fields {
+			if f.Type == nil {
+				return nil, fmt.Errorf("composite %q: missing type for field %q", spec.Name, f.Name)
+			}
+			var (
+				t   schema.Type
+				dep schema.Object
+				err error
+			)
+			switch {
+			case f.Type.IsRefTo("enum"):
+				n, err := enumName(f.Type)
+				if err != nil {
+					return nil, err
+				}
+				e, ok := enums[n]
+				if !ok {
+					return nil, fmt.Errorf("composite %q: enum %q was not found in realm", spec.Name, n)
+				}
+				t, dep = e, e
+			case f.Type.IsRefTo("composite"):
+				n, err := compositeName(f.Type)
+				if err != nil {
+					return nil, err
+				}
+				ct, ok := byName[n]
+				if !ok {
+					return nil, fmt.Errorf("composite %q: composite %q was not found in realm", spec.Name, n)
+				}
+				t, dep = ct, ct
+			default:
+				if t, err = TypeRegistry.Type(f.Type, nil); err != nil {
+					return nil, fmt.Errorf("composite %q: field %q: %w", spec.Name, f.Name, err)
+				}
+				if n, ok := arrayType(f.Type.T); ok {
+					if e, ok := enums[n]; ok {
+						t.(*ArrayType).Type, dep = e, e
+					} else if ct, ok := byName[n]; ok {
+						t.(*ArrayType).Type, dep = ct, ct
+					}
+				}
+			}
+			if dep == c {
+				return nil, fmt.Errorf("composite %q: field %q cannot reference its own type", spec.Name, f.Name)
+			}
+			if dep != nil && !slices.Contains(c.Deps, dep) {
+				c.Deps = append(c.Deps, dep)
+			}
+			field := schema.NewColumn(f.Name).SetType(t)
+			if a, ok := f.Extra.Attr("collate"); ok {
+				v, err := a.String()
+				if err != nil {
+					return nil, fmt.Errorf("composite %q: field %q: %w", spec.Name, f.Name, err)
+				}
+				field.SetCollation(v)
+			}
+			c.Fields = append(c.Fields, field)
+		}
+	}
+	return byName, nil
+}
+
+// compositeName extracts the name of the referenced composite type from the reference string.
+func compositeName(ref *schemahcl.Type) (string, error) {
+	s := strings.Split(ref.T, "$composite.")
+	if len(s) != 2 {
+		return "", fmt.Errorf("postgres: failed to extract composite name from %q", ref.T)
+	}
+	return s[1], nil
+}
+
+// compositeSpec converts the composite type to its spec.
+func compositeSpec(c *CompositeType, s *sqlspec.Schema) (*composite, error) {
+	spec := &composite{
+		Name:   c.T,
+		Schema: specutil.SchemaRef(s.Name),
+	}
+	for _, f := range c.Fields {
+		ct, err := columnTypeSpec(f.Type.Type)
+		if err != nil {
+			return nil, fmt.Errorf("composite %q: field %q: %w", c.T, f.Name, err)
+		}
+		field := &compositeField{Name: f.Name, Type: ct.Type}
+		if v := (schema.Collation{}); sqlx.Has(f.Attrs, &v) {
+			field.Extra.Attrs = append(field.Extra.Attrs, schemahcl.StringAttr("collate", v.V))
+		}
+		spec.Fields = append(spec.Fields, field)
+	}
+	return spec, nil
+}
+
+// statisticsSpec converts the statistics object to its spec.
+func statisticsSpec(st *Statistics, s *sqlspec.Schema) (*statistics, error) {
+	spec := &statistics{
+		Name:   st.Name,
+		Schema: specutil.SchemaRef(s.Name),
+		Table:  specutil.TableSpecRef(st.T),
+		Exprs:  st.Exprs,
+		Kinds:  st.Kinds,
+	}
+	q, _, err := specutil.TableName(spec.Table)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range st.Columns {
+		if q != "" {
+			spec.Columns = append(spec.Columns, specutil.QualifiedExternalColRef(c.Name, st.T.Name, q))
+		} else {
+			spec.Columns = append(spec.Columns, specutil.ExternalColumnRef(c.Name, st.T.Name))
+		}
+	}
+	if c := (schema.Comment{}); sqlx.Has(st.Attrs, &c) {
+		spec.Comment = c.Text
+	}
+	return spec, nil
+}
+
+// sequenceSpec converts the sequence object to its spec.
+// Options that are set to their defaults are omitted.
+func sequenceSpec(seq *Sequence, s *sqlspec.Schema) (*sqlspec.Sequence, error) {
+	var (
+		o    = sequenceOptions(seq)
+		d    = o.defaults()
+		spec = &sqlspec.Sequence{Name: seq.Name, Schema: specutil.SchemaRef(s.Name)}
+	)
+	if o.typ != d.typ {
+		t, err := TypeRegistry.Convert(&schema.IntegerType{T: o.typ})
+		if err != nil {
+			return nil, err
+		}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, &schemahcl.Attr{K: "type", V: schemahcl.TypeValue(t)})
+	}
+	for _, a := range []struct {
+		name    string
+		v, defv int64
+	}{
+		{"start", o.start, d.start},
+		{"increment", o.increment, d.increment},
+		{"min_value", o.min, d.min},
+		{"max_value", o.max, d.max},
+		{"cache", o.cache, d.cache},
+	} {
+		if a.v != a.defv {
+			spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.Int64Attr(a.name, a.v))
+		}
+	}
+	if o.cycle {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("cycle", true))
+	}
+	if t, c := seq.Owner.T, seq.Owner.C; t != nil && c != nil {
+		q, _, err := specutil.TableName(specutil.TableSpecRef(t))
+		if err != nil {
+			return nil, err
+		}
+		ref := specutil.ExternalColumnRef(c.Name, t.Name)
+		if q != "" {
+			ref = specutil.QualifiedExternalColRef(c.Name, t.Name, q)
+		}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefAttr("owned_by", ref))
+	}
+	if c := (schema.Comment{}); sqlx.Has(seq.Attrs, &c) && c.Text != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("comment", c.Text))
+	}
+	return spec, nil
+}
+
+// policySpec converts the policy object to its spec.
+func policySpec(p *Policy) *policy {
+	spec := &policy{
+		Name:  p.Name,
+		On:    specutil.TableSpecRef(p.T),
+		To:    p.To,
+		Using: p.Using,
+		Check: p.Check,
+	}
+	if p.As != "" && policyAs(p) != PolicyAsPermissive {
+		spec.As = &schemahcl.Ref{V: policyAs(p)}
+	}
+	if p.For != "" && policyFor(p) != PolicyForAll {
+		spec.For = &schemahcl.Ref{V: policyFor(p)}
+	}
+	return spec
+}
+
+// realmObjectsSpec converts the realm objects to their specs.
+func realmObjectsSpec(d *doc, r *schema.Realm) error {
+	for _, o := range r.Objects {
+		switch o := o.(type) {
+		case *Language:
+			spec := &language{
+				Name:      o.Name,
+				Handler:   o.Handler,
+				Inline:    o.Inline,
+				Validator: o.Validator,
+				// Packaged languages are trusted by their extensions.
+				Trusted: o.Trusted && o.Handler != "",
+			}
+			if c := (schema.Comment{}); sqlx.Has(o.Attrs, &c) {
+				spec.Comment = c.Text
+			}
+			d.Languages = append(d.Languages, spec)
+		case *Cast:
+			spec := &cast{
+				Source:   o.Source,
+				Target:   o.Target,
+				Function: o.Func,
+			}
+			if o.Method != CastMethodFunc {
+				spec.Method = &schemahcl.Ref{V: o.Method}
+			}
+			if ctx := castContext(o); ctx != CastContextExplicit {
+				spec.As = &schemahcl.Ref{V: ctx}
+			}
+			if c := (schema.Comment{}); sqlx.Has(o.Attrs, &c) {
+				spec.Comment = c.Text
+			}
+			d.Casts = append(d.Casts, spec)
+		case *Database:
+			spec := &database{Name: o.Name}
+			for _, s := range o.Settings {
+				spec.Settings = append(spec.Settings, &databaseSetting{Name: s.Name, Value: s.Value})
+			}
+			if c := (schema.Comment{}); sqlx.Has(o.Attrs, &c) {
+				spec.Comment = c.Text
+			}
+			d.Databases = append(d.Databases, spec)
+		}
+	}
+	return nil
+}
+
 // convertTable converts a sqlspec.Table to a schema.Table. Table conversion is done without converting
 // ForeignKeySpecs into ForeignKeys, as the target tables do not necessarily exist in the schema
 // at this point. Instead, the linking is done by the convertSchema function.
@@ -405,6 +1090,25 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 	if err := convertPartition(spec.Extra, t); err != nil {
 		return nil, err
 	}
+	if err := convertRowSecurity(spec.Extra, t); err != nil {
+		return nil, err
+	}
+	if attr, ok := spec.Attr("unlogged"); ok {
+		b, err := attr.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s.unlogged: %w", t.Name, err)
+		}
+		if b {
+			t.AddAttrs(&Unlogged{V: true})
+		}
+	}
+	if attr, ok := spec.Attr("tablespace"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s.tablespace: %w", t.Name, err)
+		}
+		t.AddAttrs(&Tablespace{V: v})
+	}
 	if err := convertTableAttrs(spec, t); err != nil {
 		return nil, err
 	}
@@ -430,6 +1134,22 @@ func convertView(spec *sqlspec.View, parent *schema.Schema) (*schema.View, error
 	if err != nil {
 		return nil, err
 	}
+	// The security options apply only to regular views, that are not inspected
+	// or planned by this version. Hence, they are rejected instead of being lost.
+	for _, name := range []string{"security_barrier", "security_invoker"} {
+		if _, ok := spec.Extra.Attr(name); ok {
+			return nil, fmt.Errorf("attribute %s is supported only by regular views, that are not managed by this version", name)
+		}
+	}
+	if a, ok := spec.Extra.Attr("with_no_data"); ok {
+		noData, err := a.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("expect bool definition for attribute materialized.%s.with_no_data: %w", spec.Name, err)
+		}
+		if noData {
+			v.AddAttrs(&WithNoData{})
+		}
+	}
 	return v, nil
 }
 
@@ -451,6 +1171,34 @@ func convertUnique(spec schemahcl.Resource, t *schema.Table) error {
 	return nil
 }
 
+// convertForeignKey converts the "on_delete_columns" attribute of the foreign key, if exists.
+func convertForeignKey(spec *sqlspec.ForeignKey, fk *schema.ForeignKey) error {
+	a, ok := spec.Attr("on_delete_columns")
+	if !ok {
+		return nil
+	}
+	if fk.OnDelete != schema.SetNull && fk.OnDelete != schema.SetDefault {
+		return fmt.Errorf("%s.foreign_key.%s: on_delete_columns requires on_delete to be SET_NULL or SET_DEFAULT", fk.Table.Name, fk.Symbol)
+	}
+	refs, err := a.Refs()
+	if err != nil {
+		return fmt.Errorf("%s.foreign_key.%s: parsing on_delete_columns: %w", fk.Table.Name, fk.Symbol, err)
+	}
+	d := &DeleteSetColumns{}
+	for _, r := range refs {
+		c, err := specutil.ColumnByRef(fk.Table, r)
+		if err != nil {
+			return err
+		}
+		if _, ok := fk.Column(c.Name); !ok {
+			return fmt.Errorf("%s.foreign_key.%s: on_delete_columns column %q is not a referencing column", fk.Table.Name, fk.Symbol, c.Name)
+		}
+		d.Columns = append(d.Columns, c)
+	}
+	fk.AddAttrs(d)
+	return nil
+}
+
 // convertPartition converts and appends the partition block into the table attributes if exists.
 func convertPartition(spec schemahcl.Resource, table *schema.Table) error {
 	r, ok := spec.Resource("partition")
@@ -464,6 +1212,14 @@ func convertPartition(spec schemahcl.Resource, table *schema.Table) error {
 			Expr   string         `spec:"expr"`
 			Column *schemahcl.Ref `spec:"column"`
 		} `spec:"by"`
+		Interval  string `spec:"interval"`
+		Premake   int    `spec:"premake"`
+		Retention string `spec:"retention"`
+		Children  []*struct {
+			Name    string `spec:",name"`
+			Values  string `spec:"values"`
+			Default bool   `spec:"default"`
+		} `spec:"child"`
 	}
 	if err := r.As(&p); err != nil {
 		return fmt.Errorf("parsing %s.partition: %w", table.Name, err)
@@ -504,6 +1260,67 @@ func convertPartition(spec schemahcl.Resource, table *schema.Table) error {
 		}
 	}
 	table.AddAttrs(key)
+	if len(p.Children) > 0 && p.Interval != "" {
+		return fmt.Errorf("%s.partition.child cannot be combined with the partition maintenance (interval)", table.Name)
+	}
+	names := make(map[string]bool, len(p.Children))
+	for _, c := range p.Children {
+		switch {
+		case names[c.Name]:
+			return fmt.Errorf("duplicate partition %q for %s.partition", c.Name, table.Name)
+		case c.Default && c.Values != "":
+			return fmt.Errorf(`multiple definitions for %s.partition.child %q, use "values" or "default"`, table.Name, c.Name)
+		case !c.Default && c.Values == "":
+			return fmt.Errorf("missing values for %s.partition.child %q", table.Name, c.Name)
+		case c.Default && strings.EqualFold(key.T, PartitionTypeHash):
+			return fmt.Errorf("%s.partition.child %q: a %s partitioned table cannot have a default partition", table.Name, c.Name, PartitionTypeHash)
+		}
+		names[c.Name] = true
+		table.AddAttrs(&PartitionBound{Name: c.Name, Values: c.Values, Default: c.Default})
+	}
+	return convertMaintenance(table, key, p.Interval, p.Premake, p.Retention)
+}
+
+// convertRowSecurity converts the row_security block into a table attribute if exists.
+func convertRowSecurity(spec schemahcl.Resource, table *schema.Table) error {
+	r, ok := spec.Resource("row_security")
+	if !ok {
+		return nil
+	}
+	var rs struct {
+		Enabled  bool `spec:"enabled"`
+		Enforced bool `spec:"enforced"`
+	}
+	if err := r.As(&rs); err != nil {
+		return fmt.Errorf("parsing %s.row_security: %w", table.Name, err)
+	}
+	table.AddAttrs(&RowSecurity{Enabled: rs.Enabled, Enforced: rs.Enforced})
+	return nil
+}
+
+// convertMaintenance converts the maintenance policy of the partition block into a table attribute.
+func convertMaintenance(table *schema.Table, key *Partition, interval string, premake int, retention string) error {
+	switch {
+	case interval == "" && (premake != 0 || retention != ""):
+		return fmt.Errorf("missing attribute %s.partition.interval", table.Name)
+	case interval == "":
+		return nil
+	case !strings.EqualFold(key.T, PartitionTypeRange):
+		return fmt.Errorf("%s.partition.interval is supported only for %s partitions", table.Name, PartitionTypeRange)
+	case len(key.Parts) != 1:
+		return fmt.Errorf("%s.partition.interval is supported only for partitions with a single key", table.Name)
+	case premake < 0:
+		return fmt.Errorf("%s.partition.premake must be a non-negative number", table.Name)
+	}
+	if _, err := parsePeriod(interval); err != nil {
+		return fmt.Errorf("%s.partition.interval: %w", table.Name, err)
+	}
+	if retention != "" {
+		if _, err := parsePeriod(retention); err != nil {
+			return fmt.Errorf("%s.partition.retention: %w", table.Name, err)
+		}
+	}
+	table.AddAttrs(&PartitionMaintenance{Interval: interval, Premake: premake, Retention: retention})
 	return nil
 }
 
@@ -670,6 +1487,13 @@ func convertIndexPK(spec specutil.Attrer, t *schema.Table, idx *schema.Index) er
 		}
 		idx.Attrs = append(idx.Attrs, &IndexInclude{Columns: include})
 	}
+	if attr, ok := spec.Attr("tablespace"); ok {
+		v, err := attr.String()
+		if err != nil {
+			return err
+		}
+		idx.Attrs = append(idx.Attrs, &Tablespace{V: v})
+	}
 	return nil
 }
 
@@ -784,7 +1608,7 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 		tableColumnSpec,
 		pkSpec,
 		indexSpec,
-		specutil.FromForeignKey,
+		fkSpec,
 		specutil.FromCheck,
 	)
 	if err != nil {
@@ -812,7 +1636,42 @@ func tableSpec(t *schema.Table) (*sqlspec.Table, error) {
 	}
 	spec.Indexes = idxs
 	if p := (Partition{}); sqlx.Has(t.Attrs, &p) {
-		spec.Extra.Children = append(spec.Extra.Children, fromPartition(p))
+		key := fromPartition(p)
+		if m := (PartitionMaintenance{}); sqlx.Has(t.Attrs, &m) {
+			key.Attrs = append(key.Attrs, schemahcl.StringAttr("interval", m.Interval))
+			if m.Premake > 0 {
+				key.Attrs = append(key.Attrs, schemahcl.IntAttr("premake", m.Premake))
+			}
+			if m.Retention != "" {
+				key.Attrs = append(key.Attrs, schemahcl.StringAttr("retention", m.Retention))
+			}
+		}
+		for _, b := range partitionsOf(t.Attrs) {
+			child := &schemahcl.Resource{Type: "child", Name: b.Name}
+			if b.Default {
+				child.Attrs = append(child.Attrs, schemahcl.BoolAttr("default", true))
+			} else {
+				child.Attrs = append(child.Attrs, schemahcl.StringAttr("values", b.Values))
+			}
+			key.Children = append(key.Children, child)
+		}
+		spec.Extra.Children = append(spec.Extra.Children, key)
+	}
+	if unlogged(t.Attrs) {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.BoolAttr("unlogged", true))
+	}
+	if ts := tablespace(t.Attrs); ts != "" {
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.StringAttr("tablespace", ts))
+	}
+	if r := (RowSecurity{}); sqlx.Has(t.Attrs, &r) && (r.Enabled || r.Enforced) {
+		rs := &schemahcl.Resource{
+			Type:  "row_security",
+			Attrs: []*schemahcl.Attr{schemahcl.BoolAttr("enabled", r.Enabled)},
+		}
+		if r.Enforced {
+			rs.Attrs = append(rs.Attrs, schemahcl.BoolAttr("enforced", true))
+		}
+		spec.Extra.Children = append(spec.Extra.Children, rs)
 	}
 	tableAttrsSpec(t, spec)
 	return spec, nil
@@ -830,6 +1689,10 @@ func viewSpec(view *schema.View) (*sqlspec.View, error) {
 	if err != nil {
 		return nil, err
 	}
+	if sqlx.Has(view.Attrs, &WithNoData{}) && len(spec.Extra.Children) > 0 {
+		embed := spec.Extra.Children[len(spec.Extra.Children)-1]
+		embed.Attrs = append(embed.Attrs, schemahcl.BoolAttr("with_no_data", true))
+	}
 	return spec, nil
 }
 
@@ -842,6 +1705,22 @@ func pkSpec(idx *schema.Index) (*sqlspec.PrimaryKey, error) {
 	return spec, nil
 }
 
+// fkSpec converts from a concrete Postgres foreign key to a sqlspec.ForeignKey.
+func fkSpec(fk *schema.ForeignKey) (*sqlspec.ForeignKey, error) {
+	spec, err := specutil.FromForeignKey(fk)
+	if err != nil {
+		return nil, err
+	}
+	if c := (DeleteSetColumns{}); sqlx.Has(fk.Attrs, &c) && len(c.Columns) > 0 {
+		refs := make([]*schemahcl.Ref, len(c.Columns))
+		for i, c := range c.Columns {
+			refs[i] = specutil.ColumnRef(c.Name)
+		}
+		spec.Extra.Attrs = append(spec.Extra.Attrs, schemahcl.RefsAttr("on_delete_columns", refs...))
+	}
+	return spec, nil
+}
+
 func indexSpec(idx *schema.Index) (*sqlspec.Index, error) {
 	spec, err := specutil.FromIndex(idx, partAttr)
 	if err != nil {
@@ -879,6 +1758,9 @@ func indexPKSpec(idx *schema.Index, attrs []*schemahcl.Attr) []*schemahcl.Attr {
 	if p, ok := indexStorageParams(idx.Attrs); ok {
 		attrs = append(attrs, schemahcl.Int64Attr("page_per_range", p.PagesPerRange))
 	}
+	if ts := tablespace(idx.Attrs); ts != "" {
+		attrs = append(attrs, schemahcl.StringAttr("tablespace", ts))
+	}
 	return attrs
 }
 
@@ -7,13 +7,17 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/internal/spectest"
 	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/stretchr/testify/require"
 )
 
@@ -734,6 +738,68 @@ schema "test" {
 	require.Equal(t, "vector_cosine_ops", idx.Parts[0].Attrs[0].(*IndexOpClass).Name)
 }
 
+func TestMarshalSpec_Unlogged(t *testing.T) {
+	var (
+		s = &schema.Schema{}
+		f = `table "cache" {
+  schema   = schema.test
+  unlogged = true
+  column "k" {
+    null = false
+    type = text
+  }
+}
+schema "test" {
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), s, nil))
+	tt, ok := s.Table("cache")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Unlogged{V: true}}, tt.Attrs)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+}
+
+func TestMarshalSpec_Tablespace(t *testing.T) {
+	var (
+		s = &schema.Schema{}
+		f = `table "logs" {
+  schema     = schema.test
+  tablespace = "archive"
+  column "id" {
+    null = false
+    type = integer
+  }
+  column "k" {
+    null = false
+    type = text
+  }
+  primary_key {
+    columns    = [column.id]
+    tablespace = "fast"
+  }
+  index "logs_k" {
+    columns    = [column.k]
+    tablespace = "fast"
+  }
+}
+schema "test" {
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(f), s, nil))
+	tt, ok := s.Table("logs")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&Tablespace{V: "archive"}}, tt.Attrs)
+	require.Equal(t, []schema.Attr{&Tablespace{V: "fast"}}, tt.PrimaryKey.Attrs)
+	require.Equal(t, []schema.Attr{&Tablespace{V: "fast"}}, tt.Indexes[0].Attrs)
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+}
+
 func TestUnmarshalSpec_Partitioned(t *testing.T) {
 	t.Run("Columns", func(t *testing.T) {
 		var (
@@ -832,6 +898,87 @@ table "logs" {
 			}
 		`), &schema.Schema{}, nil)
 		require.EqualError(t, err, `cannot convert table "logs": multiple definitions for logs.partition, use "columns" or "by"`)
+
+		for def, msg := range map[string]string{
+			`child "a" {}`:                 `missing values for logs.partition.child "a"`,
+			`child "a" { default = true }`: `logs.partition.child "a": a HASH partitioned table cannot have a default partition`,
+			`child "a" {
+				values  = "IN (1)"
+				default = true
+			}`: `multiple definitions for logs.partition.child "a", use "values" or "default"`,
+			`child "a" { values = "WITH (MODULUS 2, REMAINDER 0)" }
+			 child "a" { values = "WITH (MODULUS 2, REMAINDER 1)" }`: `duplicate partition "a" for logs.partition`,
+		} {
+			err = EvalHCLBytes([]byte(`
+			schema "test" {}
+			table "logs" {
+				schema = schema.test
+				column "name" { type = text }
+				partition {
+					type = HASH
+					columns = [column.name]
+					`+def+`
+				}
+			}
+		`), &schema.Schema{}, nil)
+			require.EqualError(t, err, `cannot convert table "logs": `+msg)
+		}
+	})
+
+	t.Run("Children", func(t *testing.T) {
+		var (
+			s = &schema.Schema{}
+			f = `
+schema "test" {}
+table "logs" {
+	schema = schema.test
+	column "id" {
+		type = int
+	}
+	partition {
+		type    = RANGE
+		columns = [column.id]
+		child "logs_1" {
+			values = "FROM (1) TO (10)"
+		}
+		child "logs_default" {
+			default = true
+		}
+	}
+}
+`
+		)
+		err := EvalHCLBytes([]byte(f), s, nil)
+		require.NoError(t, err)
+		tt, ok := s.Table("logs")
+		require.True(t, ok)
+		require.Equal(t, []*PartitionBound{
+			{Name: "logs_1", Values: "FROM (1) TO (10)"},
+			{Name: "logs_default", Default: true},
+		}, partitionsOf(tt.Attrs))
+
+		buf, err := MarshalHCL(s)
+		require.NoError(t, err)
+		require.Equal(t, `table "logs" {
+  schema = schema.test
+  column "id" {
+    null = false
+    type = int
+  }
+  partition {
+    type    = RANGE
+    columns = [column.id]
+    child "logs_1" {
+      values = "FROM (1) TO (10)"
+    }
+    child "logs_default" {
+      default = true
+    }
+  }
+}
+schema "test" {
+}
+`, string(buf))
 	})
 }
 
@@ -1373,6 +1520,191 @@ schema "test" {
 	require.EqualValues(t, expected, string(buf))
 }
 
+func TestSpec_FKDeleteColumns(t *testing.T) {
+	const f = `table "posts" {
+  schema = schema.public
+  column "tenant_id" {
+    null = false
+    type = integer
+  }
+  column "author_id" {
+    null = true
+    type = integer
+  }
+  foreign_key "author" {
+    columns           = [column.tenant_id, column.author_id]
+    ref_columns       = [table.users.column.tenant_id, table.users.column.id]
+    on_update         = NO_ACTION
+    on_delete         = SET_NULL
+    on_delete_columns = [column.author_id]
+  }
+}
+table "users" {
+  schema = schema.public
+  column "tenant_id" {
+    null = false
+    type = integer
+  }
+  column "id" {
+    null = false
+    type = integer
+  }
+}
+schema "public" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	posts, ok := s.Table("posts")
+	require.True(t, ok)
+	var c DeleteSetColumns
+	require.True(t, sqlx.Has(posts.ForeignKeys[0].Attrs, &c))
+	require.Equal(t, []*schema.Column{posts.Columns[1]}, c.Columns)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+
+	err = EvalHCLBytes([]byte(strings.Replace(f, "SET_NULL", "CASCADE", 1)), &s, nil)
+	require.EqualError(t, err, "posts.foreign_key.author: on_delete_columns requires on_delete to be SET_NULL or SET_DEFAULT")
+	err = EvalHCLBytes([]byte(strings.Replace(f, "on_delete_columns = [column.author_id]", "on_delete_columns = [column.tenant_id, column.id]", 1)), &s, nil)
+	require.Error(t, err)
+}
+
+func TestSpec_ViewOptions(t *testing.T) {
+	const f = `table "users" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = integer
+  }
+}
+view "v1" {
+  schema       = schema.public
+  as           = "SELECT * FROM users WHERE id > 0"
+  check_option = CASCADED
+  depends_on   = [table.users]
+}
+view "v2" {
+  schema       = schema.public
+  as           = "SELECT * FROM v1"
+  check_option = LOCAL
+  depends_on   = [view.v1]
+}
+schema "public" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	v1, ok := s.View("v1")
+	require.True(t, ok)
+	var c schema.ViewCheckOption
+	require.True(t, sqlx.Has(v1.Attrs, &c))
+	require.Equal(t, schema.ViewCheckOptionCascaded, c.V)
+	v2, ok := s.View("v2")
+	require.True(t, ok)
+	require.True(t, sqlx.Has(v2.Attrs, &c))
+	require.Equal(t, schema.ViewCheckOptionLocal, c.V)
+	require.Equal(t, []schema.Object{v1}, v2.Deps)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+
+	// Security options are rejected, as regular views are not managed by this version.
+	err = EvalHCLBytes([]byte(strings.Replace(f, "check_option = LOCAL", "security_invoker = true", 1)), &s, nil)
+	require.EqualError(t, err, `cannot convert view "v2": attribute security_invoker is supported only by regular views, that are not managed by this version`)
+	err = EvalHCLBytes([]byte(strings.Replace(f, "check_option = CASCADED", "security_barrier = true", 1)), &s, nil)
+	require.EqualError(t, err, `cannot convert view "v1": attribute security_barrier is supported only by regular views, that are not managed by this version`)
+	err = EvalHCLBytes([]byte(`schema "public" {}
+materialized "m1" {
+  schema           = schema.public
+  as               = "SELECT 1"
+  security_barrier = true
+}
+`), &s, nil)
+	require.EqualError(t, err, `cannot convert materialized "m1": attribute security_barrier is supported only by regular views, that are not managed by this version`)
+}
+
+func TestSpec_MaterializedView(t *testing.T) {
+	const f = `table "users" {
+  schema = schema.public
+  column "id" {
+    null = false
+    type = integer
+  }
+}
+materialized "m1" {
+  schema = schema.public
+  column "id" {
+    null = true
+    type = integer
+  }
+  index "m1_id" {
+    unique  = true
+    columns = [column.id]
+  }
+  as           = "SELECT id FROM users"
+  depends_on   = [table.users]
+  with_no_data = true
+}
+schema "public" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	m1, ok := s.Materialized("m1")
+	require.True(t, ok)
+	require.True(t, sqlx.Has(m1.Attrs, &WithNoData{}))
+	require.Len(t, m1.Indexes, 1)
+	require.Equal(t, m1, m1.Indexes[0].View)
+	require.Equal(t, m1.Columns[0], m1.Indexes[0].Parts[0].C)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+
+	err = EvalHCLBytes([]byte(strings.Replace(f, "with_no_data = true", `with_no_data = "yes"`, 1)), &s, nil)
+	require.ErrorContains(t, err, "expect bool definition for attribute materialized.m1.with_no_data")
+}
+
+func TestSpec_PartitionMaintenance(t *testing.T) {
+	const f = `table "events" {
+  schema = schema.public
+  column "created_at" {
+    null = false
+    type = timestamp
+  }
+  partition {
+    type      = RANGE
+    columns   = [column.created_at]
+    interval  = "1 month"
+    premake   = 3
+    retention = "12 months"
+  }
+}
+schema "public" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(f), &s, nil))
+	events, ok := s.Table("events")
+	require.True(t, ok)
+	var m PartitionMaintenance
+	require.True(t, sqlx.Has(events.Attrs, &m))
+	require.Equal(t, PartitionMaintenance{Interval: "1 month", Premake: 3, Retention: "12 months"}, m)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, f, string(buf))
+
+	for _, tt := range []struct{ from, to, err string }{
+		{from: "type      = RANGE", to: "type      = LIST", err: "events.partition.interval is supported only for RANGE partitions"},
+		{from: `interval  = "1 month"`, to: `interval  = "1 fortnight"`, err: "unit must be one of"},
+		{from: `retention = "12 months"`, to: `retention = "forever"`, err: "events.partition.retention: invalid period"},
+		{from: `interval  = "1 month"`, to: "", err: "missing attribute events.partition.interval"},
+	} {
+		err = EvalHCLBytes([]byte(strings.Replace(f, tt.from, tt.to, 1)), &s, nil)
+		require.ErrorContains(t, err, tt.err)
+	}
+}
+
 func TestMarshalSpec_PrimaryKey(t *testing.T) {
 	s := schema.New("test").
 		AddTables(
@@ -2502,3 +2834,544 @@ schema "public" {
 	require.Equal(t, &IndexInclude{Columns: []*schema.Column{s.Tables[0].Columns[1]}}, u3.Attrs[0])
 	require.Equal(t, UniqueConstraint("u3"), u3.Attrs[1].(*Constraint))
 }
+
+func TestMarshalSpec_LanguagesCasts(t *testing.T) {
+	r := schema.NewRealm(schema.New("public"))
+	r.Objects = append(r.Objects,
+		&Language{Name: "plperl", Trusted: true},
+		&Language{Name: "plsample", Trusted: true, Handler: "plsample_call_handler", Validator: "plsample_validator", Attrs: []schema.Attr{&schema.Comment{Text: "sample language"}}},
+		&Cast{Source: "text", Target: "public.mood", Method: CastMethodFunc, Func: "public.text_to_mood(text)", Context: CastContextAssignment},
+		&Cast{Source: "public.mood", Target: "text", Method: CastMethodInOut, Context: CastContextExplicit},
+	)
+	got, err := MarshalHCL.MarshalSpec(r)
+	require.NoError(t, err)
+	expected := `language "plperl" {
+}
+language "plsample" {
+  trusted   = true
+  handler   = "plsample_call_handler"
+  validator = "plsample_validator"
+  comment   = "sample language"
+}
+cast {
+  source   = "text"
+  target   = "public.mood"
+  function = "public.text_to_mood(text)"
+  as       = ASSIGNMENT
+}
+cast {
+  source = "public.mood"
+  target = "text"
+  method = INOUT
+}
+schema "public" {
+}
+`
+	require.Equal(t, expected, string(got))
+
+	var u schema.Realm
+	require.NoError(t, EvalHCLBytes(got, &u, nil))
+	require.Len(t, u.Objects, 4)
+	require.Equal(t, &Language{Name: "plperl"}, u.Objects[0])
+	require.Equal(t, r.Objects[1], u.Objects[1])
+	require.Equal(t, r.Objects[2], u.Objects[2])
+	require.Equal(t, r.Objects[3], u.Objects[3])
+
+	err = EvalHCLBytes([]byte(`
+cast {
+  source = "text"
+  target = "public.mood"
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, "cast (text AS public.mood): function is required")
+}
+
+func TestMarshalSpec_Database(t *testing.T) {
+	r := schema.NewRealm(schema.New("public"))
+	r.Objects = append(r.Objects, &Database{
+		Name: "app",
+		Settings: []*DatabaseSetting{
+			{Name: "search_path", Value: `"$user", public`},
+			{Name: "timezone", Value: "UTC"},
+		},
+		Attrs: []schema.Attr{&schema.Comment{Text: "app db"}},
+	})
+	got, err := MarshalHCL.MarshalSpec(r)
+	require.NoError(t, err)
+	expected := `database "app" {
+  comment = "app db"
+  setting "search_path" {
+    value = "\"$user\", public"
+  }
+  setting "timezone" {
+    value = "UTC"
+  }
+}
+schema "public" {
+}
+`
+	require.Equal(t, expected, string(got))
+
+	var u schema.Realm
+	require.NoError(t, EvalHCLBytes(got, &u, nil))
+	require.Equal(t, r.Objects, u.Objects)
+
+	err = EvalHCLBytes([]byte(`
+database "app" {}
+database "dev" {}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, "expect a single database definition, got 2")
+	err = EvalHCLBytes([]byte(`
+database "app" {
+  setting "timezone" {
+    value = "UTC"
+  }
+  setting "TimeZone" {
+    value = "UTC"
+  }
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, `database "app": duplicate setting "TimeZone"`)
+}
+
+func TestSpec_Composite(t *testing.T) {
+	var (
+		r schema.Realm
+		h = `table "users" {
+  schema = schema.public
+  column "home" {
+    null = false
+    type = composite.address
+  }
+  column "prev" {
+    null = false
+    type = sql("address[]")
+  }
+}
+enum "status" {
+  schema = schema.public
+  values = ["active", "inactive"]
+}
+composite "address" {
+  schema = schema.public
+  field "street" {
+    type    = text
+    collate = "C"
+  }
+  field "status" {
+    type = enum.status
+  }
+  field "tags" {
+    type = sql("status[]")
+  }
+}
+schema "public" {
+}
+`
+	)
+	require.NoError(t, EvalHCLBytes([]byte(h), &r, nil))
+	var (
+		s      = r.Schemas[0]
+		status = s.Objects[0].(*schema.EnumType)
+		addr   = s.Objects[1].(*CompositeType)
+	)
+	require.Equal(t, "address", addr.T)
+	require.Equal(t, []schema.Object{status}, addr.Deps)
+	require.Len(t, addr.Fields, 3)
+	require.Equal(t, []schema.Attr{&schema.Collation{V: "C"}}, addr.Fields[0].Attrs)
+	require.Equal(t, status, addr.Fields[1].Type.Type)
+	require.Equal(t, status, addr.Fields[2].Type.Type.(*ArrayType).Type)
+	require.Equal(t, addr, s.Tables[0].Columns[0].Type.Type)
+	require.Equal(t, addr, s.Tables[0].Columns[1].Type.Type.(*ArrayType).Type)
+	got, err := MarshalHCL.MarshalSpec(&r)
+	require.NoError(t, err)
+	require.Equal(t, h, string(got))
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+composite "node" {
+  schema = schema.public
+  field "next" {
+    type = composite.node
+  }
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, `composite "node": field "next" cannot reference its own type`)
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+composite "empty" {
+  schema = schema.public
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, `composite "empty": at least one field is required`)
+}
+
+func TestMarshalSpec_Statistics(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").
+			AddColumns(
+				schema.NewStringColumn("first_name", "text"),
+				schema.NewStringColumn("last_name", "text"),
+				schema.NewStringColumn("email", "text"),
+			)
+		r = schema.NewRealm(s.AddTables(usr))
+	)
+	s.AddObjects(
+		&Statistics{Name: "users_names", T: usr, Columns: usr.Columns[:2], Kinds: []string{StatisticsKindNDistinct, StatisticsKindDependencies}, Attrs: []schema.Attr{&schema.Comment{Text: "names"}}},
+		&Statistics{Name: "users_email", T: usr, Columns: usr.Columns[:1], Exprs: []string{"lower(email)"}},
+	)
+	for _, o := range s.Objects {
+		o.(*Statistics).Schema = s
+	}
+	got, err := MarshalHCL.MarshalSpec(r)
+	require.NoError(t, err)
+	expected := `table "users" {
+  schema = schema.public
+  column "first_name" {
+    null = false
+    type = text
+  }
+  column "last_name" {
+    null = false
+    type = text
+  }
+  column "email" {
+    null = false
+    type = text
+  }
+}
+statistics "users_names" {
+  schema  = schema.public
+  table   = table.users
+  columns = [table.users.column.first_name, table.users.column.last_name]
+  kinds   = ["NDISTINCT", "DEPENDENCIES"]
+  comment = "names"
+}
+statistics "users_email" {
+  schema  = schema.public
+  table   = table.users
+  columns = [table.users.column.first_name]
+  exprs   = ["lower(email)"]
+}
+schema "public" {
+}
+`
+	require.Equal(t, expected, string(got))
+
+	var u schema.Realm
+	require.NoError(t, EvalHCLBytes(got, &u, nil))
+	require.Len(t, u.Schemas[0].Objects, 2)
+	for i, o := range u.Schemas[0].Objects {
+		st, ok := o.(*Statistics)
+		require.True(t, ok)
+		require.Equal(t, s.Objects[i].(*Statistics).Name, st.Name)
+		require.Equal(t, u.Schemas[0], st.Schema)
+		require.Equal(t, u.Schemas[0].Tables[0], st.T)
+		require.False(t, (&diff{conn: &conn{version: 130000}}).statisticsChanged(s.Objects[i].(*Statistics), st))
+	}
+
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "name" {
+    type = text
+  }
+}
+statistics "users_stats" {
+  schema  = schema.public
+  table   = table.users
+  columns = [table.users.column.name]
+  kinds   = ["histogram"]
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, `statistics "users_stats": unknown kind "HISTOGRAM"`)
+	err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "name" {
+    type = text
+  }
+}
+statistics "users_stats" {
+  schema = schema.public
+  table  = table.users
+}
+`), &schema.Realm{}, nil)
+	require.EqualError(t, err, `statistics "users_stats": columns or expressions are required`)
+}
+
+func TestMarshalSpec_Policies(t *testing.T) {
+	var (
+		s   = schema.New("public")
+		usr = schema.NewTable("users").
+			AddColumns(schema.NewIntColumn("tenant_id", "int")).
+			AddAttrs(&RowSecurity{Enabled: true, Enforced: true})
+		pets = schema.NewTable("pets").
+			AddColumns(schema.NewIntColumn("owner_id", "int")).
+			AddAttrs(&RowSecurity{Enabled: true})
+		r = schema.NewRealm(s.AddTables(usr, pets))
+	)
+	s.AddObjects(
+		&Policy{Name: "tenant_isolation", T: usr, Using: "(tenant_id = current_setting('app.tenant')::int)"},
+		&Policy{Name: "tenant_isolation", T: pets, As: PolicyAsRestrictive, For: PolicyForUpdate, To: []string{"app", "admin"}, Using: "(owner_id > 0)", Check: "(owner_id > 0)"},
+	)
+	got, err := MarshalHCL.MarshalSpec(r)
+	require.NoError(t, err)
+	expected := `table "users" {
+  schema = schema.public
+  column "tenant_id" {
+    null = false
+    type = int
+  }
+  row_security {
+    enabled  = true
+    enforced = true
+  }
+}
+table "pets" {
+  schema = schema.public
+  column "owner_id" {
+    null = false
+    type = int
+  }
+  row_security {
+    enabled = true
+  }
+}
+policy "tenant_isolation" {
+  on    = table.users
+  using = "(tenant_id = current_setting('app.tenant')::int)"
+}
+policy "tenant_isolation" {
+  on    = table.pets
+  as    = RESTRICTIVE
+  for   = UPDATE
+  to    = ["app", "admin"]
+  using = "(owner_id > 0)"
+  check = "(owner_id > 0)"
+}
+schema "public" {
+}
+`
+	require.Equal(t, expected, string(got))
+
+	var u schema.Realm
+	require.NoError(t, EvalHCLBytes(got, &u, nil))
+	require.Equal(t, []schema.Attr{&RowSecurity{Enabled: true, Enforced: true}}, u.Schemas[0].Tables[0].Attrs)
+	require.Equal(t, []schema.Attr{&RowSecurity{Enabled: true}}, u.Schemas[0].Tables[1].Attrs)
+	require.Len(t, u.Schemas[0].Objects, 2)
+	for i, o := range u.Schemas[0].Objects {
+		p, ok := o.(*Policy)
+		require.True(t, ok)
+		require.Equal(t, s.Objects[i].(*Policy).Name, p.Name)
+		require.Equal(t, u.Schemas[0].Tables[i], p.T)
+		require.False(t, policyChanged(s.Objects[i].(*Policy), p))
+	}
+
+	for _, tt := range []struct{ policy, err string }{
+		{
+			policy: `policy "p" {
+  using = "true"
+}`,
+			err: `policy "p": table is required`,
+		},
+		{
+			policy: `policy "p" {
+  on    = table.users
+  for   = INSERT
+  using = "true"
+}`,
+			err: `policy "p": only a check expression can be defined for INSERT`,
+		},
+		{
+			policy: `policy "p" {
+  on    = table.users
+  for   = SELECT
+  check = "true"
+}`,
+			err: `policy "p": only a using expression can be defined for SELECT`,
+		},
+		{
+			policy: `policy "p" {
+  on    = table.users
+  using = "true"
+}
+policy "p" {
+  on    = table.users
+  using = "false"
+}`,
+			err: `duplicate policy "p" on table "users"`,
+		},
+	} {
+		err = EvalHCLBytes([]byte(`
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "id" {
+    type = int
+  }
+}
+`+tt.policy), &schema.Realm{}, nil)
+		require.EqualError(t, err, tt.err)
+	}
+}
+
+func TestUnmarshalSpec_Layered(t *testing.T) {
+	var (
+		from, to schema.Realm
+		p        = hclparse.NewParser()
+	)
+	err := EvalHCLBytes([]byte(`
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "status" {
+    type    = text
+    default = "active"
+    comment = "user status"
+  }
+}
+`), &from, nil)
+	require.NoError(t, err)
+	_, diag := p.ParseHCL([]byte(`
+schema "public" {}
+table "users" {
+  schema = schema.public
+  column "status" {
+    type    = text
+    default = "active"
+    comment = "user status"
+  }
+}
+`), "base.pg.hcl")
+	require.False(t, diag.HasErrors())
+	// The override fragment unsets the default value and the comment of the column.
+	_, diag = p.ParseHCL([]byte(`
+table "users" {
+  schema = schema.public
+  column "status" {
+    default = null
+    comment = null
+  }
+}
+`), "override.pg.hcl")
+	require.False(t, diag.HasErrors())
+	require.NoError(t, EvalHCL.Eval(p, &to, nil))
+	require.Len(t, to.Schemas, 1)
+	require.Len(t, to.Schemas[0].Tables, 1)
+	c, ok := to.Schemas[0].Tables[0].Column("status")
+	require.True(t, ok)
+	require.Nil(t, c.Default)
+	require.False(t, sqlx.Has(c.Attrs, &schema.Comment{}))
+
+	changes, err := DefaultDiff.RealmDiff(&from, &to)
+	require.NoError(t, err)
+	plan, err := DefaultPlan.PlanChanges(context.Background(), "layered", changes)
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	require.Equal(t, `ALTER TABLE "public"."users" ALTER COLUMN "status" DROP DEFAULT`, plan.Changes[0].Cmd)
+	require.Equal(t, `COMMENT ON COLUMN "public"."users"."status" IS ''`, plan.Changes[1].Cmd)
+}
+
+func TestMarshalSpec_Sequences(t *testing.T) {
+	var (
+		s      = schema.New("public")
+		orders = schema.NewTable("orders").
+			AddColumns(schema.NewIntColumn("id", "bigint").SetDefault(&schema.RawExpr{X: "nextval('orders_seq')"}))
+		r   = schema.NewRealm(s.AddTables(orders))
+		min = int64(-1000)
+		seq = &Sequence{Name: "orders_seq", Schema: s, Type: &schema.IntegerType{T: TypeInteger}, Start: 100, Increment: 10, Cache: 5, Attrs: []schema.Attr{&schema.Comment{Text: "order numbers"}}}
+	)
+	seq.Owner.T, seq.Owner.C = orders, orders.Columns[0]
+	s.AddObjects(seq, &Sequence{Name: "countdown", Schema: s, Increment: -1, Min: &min, Cycle: true})
+	got, err := MarshalHCL.MarshalSpec(r)
+	require.NoError(t, err)
+	expected := `table "orders" {
+  schema = schema.public
+  column "id" {
+    null    = false
+    type    = bigint
+    default = sql("nextval('orders_seq')")
+  }
+}
+sequence "orders_seq" {
+  schema    = schema.public
+  type      = integer
+  start     = 100
+  increment = 10
+  cache     = 5
+  owned_by  = table.orders.column.id
+  comment   = "order numbers"
+}
+sequence "countdown" {
+  schema    = schema.public
+  increment = -1
+  min_value = -1000
+  cycle     = true
+}
+schema "public" {
+}
+`
+	require.Equal(t, expected, string(got))
+
+	var u schema.Realm
+	require.NoError(t, EvalHCLBytes(got, &u, nil))
+	require.Len(t, u.Schemas[0].Objects, 2)
+	for i, o := range u.Schemas[0].Objects {
+		seq, ok := o.(*Sequence)
+		require.True(t, ok)
+		require.Equal(t, s.Objects[i].(*Sequence).Name, seq.Name)
+		require.Equal(t, u.Schemas[0], seq.Schema)
+		require.False(t, sequenceChanged(s.Objects[i].(*Sequence), seq))
+	}
+	require.Equal(t, u.Schemas[0].Tables[0], u.Schemas[0].Objects[0].(*Sequence).Owner.T)
+
+	for _, tt := range []struct{ sequence, err string }{
+		{
+			sequence: `sequence "s" {
+  schema = schema.public
+  type   = text
+}`,
+			err: `sequence "s": type must be one of smallint, integer or bigint`,
+		},
+		{
+			sequence: `sequence "s" {
+  schema    = schema.public
+  start     = 10
+  max_value = 5
+}`,
+			err: `sequence "s": start value (10) must be between min_value (1) and max_value (5)`,
+		},
+		{
+			sequence: `table "users" {
+  schema = schema.other
+  column "id" {
+    type = int
+  }
+}
+schema "other" {}
+sequence "s" {
+  schema   = schema.public
+  owned_by = table.users.column.id
+}`,
+			err: `sequence "s": table "users" was not found in schema "public"`,
+		},
+		{
+			sequence: `sequence "s" {
+  schema = schema.public
+}
+sequence "s" {
+  schema = schema.public
+}`,
+			err: `duplicate sequence "s" in schema "public"`,
+		},
+	} {
+		err := EvalHCLBytes([]byte(tt.sequence+`
+schema "public" {}
+`), &schema.Realm{}, nil)
+		require.EqualError(t, err, tt.err)
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// Checksum is an attribute that holds the checksum of the definition of a table or
+// a view. Drivers compute it once, when the object is inspected from the database (see
+// SetChecksums), and the diffing process skips objects whose checksums are identical.
+type Checksum struct {
+	V string
+}
+
+func (*Checksum) attr() {}
+
+// SetChecksums computes the checksums of the tables and views in the given schemas,
+// and caches them on the objects. Note that the cached checksum is not updated when
+// the object is modified, and it is therefore set only on inspection.
+func SetChecksums(schemas ...*Schema) {
+	for _, s := range schemas {
+		for _, t := range s.Tables {
+			ReplaceOrAppend(&t.Attrs, &Checksum{V: computeChecksum(t)})
+		}
+		for _, v := range s.Views {
+			ReplaceOrAppend(&v.Attrs, &Checksum{V: computeChecksum(v)})
+		}
+	}
+}
+
+// ChecksumOf returns the checksum of the given table or view, or an empty string
+// for other objects. The checksum cached on inspection is returned if exists.
+//
+// The checksum covers all fields and attributes of the object, its columns, indexes,
+// foreign keys and triggers, and the attributes of its schema that are inherited by
+// the object (i.e., charset and collation). References to other objects are hashed by
+// their names, and names of schemas are hashed only if they differ from the schema of
+// the object. Positions, labels and the cached checksum of the object are not hashed.
+// Note that a checksum mismatch does not imply the objects are different, as their
+// representations can differ. For example, when one is inspected from a database,
+// and the other is loaded from a schema file.
+func ChecksumOf(o Object) string {
+	var attrs []Attr
+	switch o := o.(type) {
+	case *Table:
+		attrs = o.Attrs
+	case *View:
+		attrs = o.Attrs
+	}
+	for _, a := range attrs {
+		if c, ok := a.(*Checksum); ok {
+			return c.V
+		}
+	}
+	return computeChecksum(o)
+}
+
+func computeChecksum(o Object) string {
+	h := &hasher{seen: make(map[uintptr]bool)}
+	switch o := o.(type) {
+	case *Table:
+		h.root = o.Schema
+		h.str("table", o.Name)
+		h.attrs(o.Schema)
+		for _, c := range o.Columns {
+			h.deref(c)
+		}
+		for _, idx := range o.Indexes {
+			h.deref(idx)
+		}
+		h.str("pk")
+		if o.PrimaryKey != nil {
+			h.deref(o.PrimaryKey)
+		}
+		for _, fk := range o.ForeignKeys {
+			h.deref(fk)
+		}
+		h.value(reflect.ValueOf(o.Attrs))
+		for _, t := range o.Triggers {
+			h.deref(t)
+		}
+		h.value(reflect.ValueOf(o.Deps))
+	case *View:
+		h.root = o.Schema
+		h.str("view", o.Name, o.Def)
+		h.attrs(o.Schema)
+		for _, c := range o.Columns {
+			h.deref(c)
+		}
+		for _, idx := range o.Indexes {
+			h.deref(idx)
+		}
+		h.value(reflect.ValueOf(o.Attrs))
+		for _, t := range o.Triggers {
+			h.deref(t)
+		}
+		h.value(reflect.ValueOf(o.Deps))
+	default:
+		return ""
+	}
+	sum := sha256.Sum256(h.buf)
+	return hex.EncodeToString(sum[:])
+}
+
+// hasher writes a deterministic representation of schema elements.
+type hasher struct {
+	buf  []byte // Length-prefixed strings, hashed at the end.
+	root *Schema
+	seen map[uintptr]bool // Pointers on the current path, used to break cycles.
+}
+
+var (
+	tableT  = reflect.TypeOf((*Table)(nil))
+	viewT   = reflect.TypeOf((*View)(nil))
+	funcT   = reflect.TypeOf((*Func)(nil))
+	procT   = reflect.TypeOf((*Proc)(nil))
+	schemaT = reflect.TypeOf((*Schema)(nil))
+	realmT  = reflect.TypeOf((*Realm)(nil))
+	columnT = reflect.TypeOf((*Column)(nil))
+	indexT  = reflect.TypeOf((*Index)(nil))
+	fkT     = reflect.TypeOf((*ForeignKey)(nil))
+	trigT   = reflect.TypeOf((*Trigger)(nil))
+	attrsT  = reflect.TypeOf([]Attr(nil))
+)
+
+func (h *hasher) str(ss ...string) {
+	for _, s := range ss {
+		h.buf = binary.AppendUvarint(h.buf, uint64(len(s)))
+		h.buf = append(h.buf, s...)
+	}
+}
+
+// attrs writes the schema attributes that are inherited by its objects.
+func (h *hasher) attrs(s *Schema) {
+	if s == nil {
+		return
+	}
+	for _, a := range s.Attrs {
+		switch a.(type) {
+		case *Charset, *Collation:
+			h.value(reflect.ValueOf(a))
+		}
+	}
+}
+
+// attrList writes the given attributes, except for positions, labels
+// and checksums that are not part of the definition.
+func (h *hasher) attrList(attrs []Attr) {
+	n := 0
+	for _, a := range attrs {
+		switch a.(type) {
+		case *Pos, *Labels, *Checksum:
+		default:
+			n++
+		}
+	}
+	h.str(strconv.Itoa(n))
+	for _, a := range attrs {
+		switch a.(type) {
+		case *Pos, *Labels, *Checksum:
+		default:
+			h.value(reflect.ValueOf(&a).Elem())
+		}
+	}
+}
+
+// deref writes the fields of the element the given pointer points to,
+// instead of writing it as a reference to another schema element.
+func (h *hasher) deref(p any) {
+	h.value(reflect.ValueOf(p).Elem())
+}
+
+// schemaName returns the name of the schema, or an empty string in case
+// it is the schema of the hashed object.
+func (h *hasher) schemaName(s *Schema) string {
+	if s == nil || h.root != nil && s.Name == h.root.Name {
+		return ""
+	}
+	return s.Name
+}
+
+func (h *hasher) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		h.str("nil")
+	case reflect.Pointer:
+		if v.IsNil() {
+			h.str("nil")
+			return
+		}
+		if h.ref(v) {
+			return
+		}
+		p := v.Pointer()
+		if h.seen[p] {
+			h.str("cycle")
+			return
+		}
+		h.seen[p] = true
+		h.value(v.Elem())
+		delete(h.seen, p)
+	case reflect.Interface:
+		if v.IsNil() {
+			h.str("nil")
+			return
+		}
+		h.str(v.Elem().Type().String())
+		h.value(v.Elem())
+	case reflect.Struct:
+		// Fields are written in their declaration
+		// order, and therefore, without their names.
+		h.str(v.Type().String())
+		for i := 0; i < v.NumField(); i++ {
+			h.value(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type() == attrsT {
+			h.attrList(v.Interface().([]Attr))
+			return
+		}
+		h.str(strconv.Itoa(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h.value(v.Index(i))
+		}
+	case reflect.Map:
+		// Map entries are encoded separately, and
+		// written in sorted order of their encoding.
+		entries := make([]string, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			e := &hasher{root: h.root, seen: h.seen}
+			e.value(it.Key())
+			e.value(it.Value())
+			entries = append(entries, string(e.buf))
+		}
+		slices.Sort(entries)
+		h.str(strconv.Itoa(len(entries)))
+		h.str(entries...)
+	case reflect.String:
+		h.str(v.String())
+	case reflect.Bool:
+		h.str(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.str(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.str(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		h.str(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	case reflect.Complex64, reflect.Complex128:
+		h.str(strconv.FormatComplex(v.Complex(), 'g', -1, 128))
+	default:
+		// Functions, channels and unsafe pointers are not part of the definition.
+		h.str(v.Kind().String())
+	}
+}
+
+// ref writes pointers to schema elements as references by their names.
+func (h *hasher) ref(v reflect.Value) bool {
+	switch p := v.UnsafePointer(); v.Type() {
+	case tableT:
+		t := (*Table)(p)
+		h.str("table", h.schemaName(t.Schema), t.Name)
+	case viewT:
+		t := (*View)(p)
+		h.str("view", h.schemaName(t.Schema), t.Name)
+	case funcT:
+		f := (*Func)(p)
+		h.str("func", h.schemaName(f.Schema), f.Name)
+	case procT:
+		f := (*Proc)(p)
+		h.str("proc", h.schemaName(f.Schema), f.Name)
+	case schemaT:
+		h.str("schema", h.schemaName((*Schema)(p)))
+	case realmT:
+		h.str("realm")
+	case columnT:
+		h.str("column", (*Column)(p).Name)
+	case indexT:
+		h.str("index", (*Index)(p).Name)
+	case fkT:
+		h.str("fk", (*ForeignKey)(p).Symbol)
+	case trigT:
+		h.str("trigger", (*Trigger)(p).Name)
+	default:
+		return false
+	}
+	return true
+}
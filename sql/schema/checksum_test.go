@@ -0,0 +1,74 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	users := func(s string) *schema.Table {
+		id := schema.NewIntColumn("id", "int")
+		t := schema.NewTable("users").
+			AddColumns(id, schema.NewNullStringColumn("name", "text"), schema.NewIntColumn("parent_id", "int")).
+			SetPrimaryKey(schema.NewPrimaryKey(id)).
+			SetComment("users table")
+		t.AddIndexes(schema.NewUniqueIndex("name").AddColumns(t.Columns[1]))
+		t.AddForeignKeys(schema.NewForeignKey("parent").AddColumns(t.Columns[2]).SetRefTable(t).AddRefColumns(id))
+		schema.New(s).AddTables(t)
+		return t
+	}
+	t1, t2 := users("public"), users("public")
+	require.NotEmpty(t, schema.ChecksumOf(t1))
+	require.Equal(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+	require.Equal(t, schema.ChecksumOf(t1), schema.ChecksumOf(users("other")), "schema name is not part of the checksum")
+
+	t2.Columns[1].Type.Null = false
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+	t2 = users("public")
+	t2.Indexes[0].Unique = false
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+	t2 = users("public")
+	t2.SetComment("")
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+
+	// Inherited schema attributes and references to other schemas are part of the checksum.
+	t2 = users("public")
+	t2.Schema.SetCharset("utf8mb4")
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+	t2 = users("public")
+	t2.ForeignKeys[0].SetRefTable(schema.NewTable("users").SetSchema(schema.New("other")))
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+
+	v1, v2 := schema.NewView("v", "SELECT 1"), schema.NewView("v", "SELECT 1")
+	require.Equal(t, schema.ChecksumOf(v1), schema.ChecksumOf(v2))
+	v2.Def = "SELECT 2"
+	require.NotEqual(t, schema.ChecksumOf(v1), schema.ChecksumOf(v2))
+	require.Empty(t, schema.ChecksumOf(&schema.Func{Name: "f"}))
+
+	// Positions are not part of the checksum.
+	t2 = users("public")
+	t2.Columns[0].AddAttrs(schema.NewFilePos("schema.hcl"))
+	require.Equal(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+
+	// Checksums are cached on the objects.
+	t2 = users("public")
+	schema.SetChecksums(t2.Schema)
+	var cached string
+	for _, a := range t2.Attrs {
+		if c, ok := a.(*schema.Checksum); ok {
+			cached = c.V
+		}
+	}
+	require.Equal(t, schema.ChecksumOf(t1), cached)
+	t2.SetComment("")
+	require.Equal(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2), "cached checksum is returned")
+	schema.SetChecksums(t2.Schema)
+	require.NotEqual(t, schema.ChecksumOf(t1), schema.ChecksumOf(t2))
+}
@@ -12,51 +12,105 @@ import (
 	"strings"
 )
 
+type (
+	// A Matcher matches schema resources against a list of exclude patterns. It is the
+	// shared implementation of the --exclude flag, used by inspection, diffing and linting.
+	//
+	// A pattern is a dot-separated list of globs that describes the path of a resource,
+	// starting from its schema (or a top-level realm object). For example, "public.users.id"
+	// matches the column "id" of the table "users" in the schema "public". Each glob may end
+	// with a type selector, such as "*.*.idx_*[type=index]" or "public.*[type=table|view]".
+	//
+	// Patterns prefixed with "!" are negated, and resources that match any of them are never
+	// excluded. For example, the patterns "public.*" and "!public.users" exclude all resources
+	// in the "public" schema, except for the "users" table.
+	Matcher struct {
+		exclude, negate [][]string
+	}
+
+	// A ResourcePath describes the path of a resource in the realm, starting from its top-level
+	// resource. For example, {{"schema", "public"}, {"table", "users"}, {"column", "id"}}.
+	ResourcePath []struct{ Type, Name string }
+)
+
+// NewMatcher returns a Matcher for the given patterns.
+func NewMatcher(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, p := range patterns {
+		pattern, negate := strings.CutPrefix(p, "!")
+		globs, err := split([]string{pattern})
+		if err != nil {
+			return nil, err
+		}
+		if len(globs[0]) > 3 {
+			return nil, fmt.Errorf("too many parts in pattern: %q", p)
+		}
+		if negate {
+			m.negate = append(m.negate, globs[0])
+		} else {
+			m.exclude = append(m.exclude, globs[0])
+		}
+	}
+	return m, nil
+}
+
+// Match reports if the resource at the given path is excluded by the matcher.
+func (m *Matcher) Match(path ResourcePath) (bool, error) {
+	if m == nil {
+		return false, nil
+	}
+	for _, g := range m.negate {
+		switch match, err := matchPath(g, path); {
+		case err != nil:
+			return false, err
+		case match:
+			return false, nil
+		}
+	}
+	for _, g := range m.exclude {
+		if match, err := matchPath(g, path); match || err != nil {
+			return match, err
+		}
+	}
+	return false, nil
+}
+
+// matchPath reports if the given resource path matches the glob chain.
+func matchPath(glob []string, path ResourcePath) (bool, error) {
+	if len(glob) != len(path) {
+		return false, nil
+	}
+	for i, p := range path {
+		g, ok := excludeType(p.Type, glob[i])
+		if !ok {
+			return false, nil
+		}
+		if match, err := filepath.Match(g, p.Name); !match || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Append returns a copy of the path with the given element added to its end.
+func (p ResourcePath) Append(t, name string) ResourcePath {
+	c := make(ResourcePath, len(p), len(p)+1)
+	copy(c, p)
+	return append(c, struct{ Type, Name string }{t, name})
+}
+
 // ExcludeRealm filters resources in the realm based on the given patterns.
 func ExcludeRealm(r *Realm, patterns []string) (*Realm, error) {
 	if len(patterns) == 0 {
 		return r, nil
 	}
-	globs, err := split(patterns)
+	m, err := NewMatcher(patterns)
 	if err != nil {
 		return nil, err
 	}
-	for _, g := range globs {
-		// Realm objects are top-level
-		// resources, must like schemas.
-		if len(g) == 1 {
-			if r.Objects, err = excludeObjects(r.Objects, g); err != nil {
-				return nil, err
-			}
-		}
-	}
-	var schemas []*Schema
-Filter:
-	for _, s := range r.Schemas {
-		for i, g := range globs {
-			if len(g) > 3 {
-				return nil, fmt.Errorf("too many parts in pattern: %q", patterns[i])
-			}
-			if globS, exclude := excludeType(typeS, g[0]); exclude {
-				match, err := filepath.Match(globS, s.Name)
-				if err != nil {
-					return nil, err
-				}
-				if match {
-					// In case there is a match, and it is
-					// a single glob we exclude this
-					if len(g) == 1 {
-						continue Filter
-					}
-					if err := excludeS(s, g[1:]); err != nil {
-						return nil, err
-					}
-				}
-			}
-		}
-		schemas = append(schemas, s)
+	if err := m.excludeRealm(r); err != nil {
+		return nil, err
 	}
-	r.Schemas = schemas
 	return r, nil
 }
 
@@ -70,7 +124,11 @@ func ExcludeSchema(s *Schema, patterns []string) (*Schema, error) {
 	}
 	qualified := make([]string, len(patterns))
 	for i, p := range patterns {
-		qualified[i] = fmt.Sprintf("%s.%s", s.Name, p)
+		if p, ok := strings.CutPrefix(p, "!"); ok {
+			qualified[i] = fmt.Sprintf("!%s.%s", s.Name, p)
+		} else {
+			qualified[i] = fmt.Sprintf("%s.%s", s.Name, p)
+		}
 	}
 	if _, err := ExcludeRealm(s.Realm, qualified); err != nil {
 		return nil, err
@@ -99,153 +157,162 @@ func split(patterns []string) ([][]string, error) {
 	return globs, nil
 }
 
-func excludeS(s *Schema, glob []string) (err error) {
-	if s.Objects, err = excludeObjects(s.Objects, glob); err != nil {
+func (m *Matcher) excludeRealm(r *Realm) (err error) {
+	// Realm objects are top-level
+	// resources, must like schemas.
+	if r.Objects, err = m.excludeObjects(r.Objects, nil); err != nil {
 		return err
 	}
-	if globT, exclude := excludeType(typeT, glob[0]); exclude {
-		var tables []*Table
-		for _, t := range s.Tables {
-			match, err := filepath.Match(globT, t.Name)
-			if err != nil {
-				return err
-			}
-			if match {
-				// In case there is a match, and it is
-				// a single glob we exclude this table.
-				if len(glob) == 1 {
-					detachObject(t, t.Refs)
-					continue
-				}
-				if err := excludeT(t, glob[1]); err != nil {
-					return err
-				}
-			}
-			// No match or glob has more than one pattern.
-			tables = append(tables, t)
+	var schemas []*Schema
+	for _, s := range r.Schemas {
+		path := ResourcePath{}.Append(typeS, s.Name)
+		switch match, err := m.Match(path); {
+		case err != nil:
+			return err
+		case match:
+			continue
 		}
-		s.Tables = tables
-	}
-	if globV, exclude := excludeType(typeV, glob[0]); exclude {
-		var views []*View
-		for _, v := range s.Views {
-			match, err := filepath.Match(globV, v.Name)
-			if err != nil {
-				return err
-			}
-			if match {
-				if len(glob) == 1 {
-					detachObject(v, v.Refs)
-					continue
-				}
-				if err := excludeV(v, glob[1]); err != nil {
-					return err
-				}
-			}
-			views = append(views, v)
+		if err := m.excludeS(s, path); err != nil {
+			return err
 		}
-		s.Views = views
+		schemas = append(schemas, s)
 	}
-	if globF, exclude := excludeType(typeFn, glob[0]); exclude {
-		var err error
-		s.Funcs, err = filter(s.Funcs, func(f *Func) (bool, error) {
-			if match, err := filepath.Match(globF, f.Name); !match || err != nil {
-				return false, err
-			}
-			detachObject(f, f.Refs)
+	r.Schemas = schemas
+	return nil
+}
+
+func (m *Matcher) excludeS(s *Schema, path ResourcePath) (err error) {
+	if s.Objects, err = m.excludeObjects(s.Objects, path); err != nil {
+		return err
+	}
+	s.Tables, err = filter(s.Tables, func(t *Table) (bool, error) {
+		path := path.Append(typeT, t.Name)
+		match, err := m.Match(path)
+		switch {
+		case err != nil:
+			return false, err
+		case match:
+			detachObject(t, t.Refs)
 			return true, nil
-		})
-		if err != nil {
-			return err
 		}
+		return false, m.excludeT(t, path)
+	})
+	if err != nil {
+		return err
 	}
-	if globP, exclude := excludeType(typePr, glob[0]); exclude {
-		var err error
-		s.Procs, err = filter(s.Procs, func(p *Proc) (bool, error) {
-			if match, err := filepath.Match(globP, p.Name); !match || err != nil {
-				return false, err
-			}
-			detachObject(p, p.Refs)
+	s.Views, err = filter(s.Views, func(v *View) (bool, error) {
+		path := path.Append(typeV, v.Name)
+		match, err := m.Match(path)
+		switch {
+		case err != nil:
+			return false, err
+		case match:
+			detachObject(v, v.Refs)
 			return true, nil
-		})
-		if err != nil {
-			return err
 		}
+		return false, m.excludeV(v, path)
+	})
+	if err != nil {
+		return err
 	}
-	return nil
+	s.Funcs, err = filter(s.Funcs, func(f *Func) (bool, error) {
+		if match, err := m.Match(path.Append(typeFn, f.Name)); !match || err != nil {
+			return false, err
+		}
+		detachObject(f, f.Refs)
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	s.Procs, err = filter(s.Procs, func(p *Proc) (bool, error) {
+		if match, err := m.Match(path.Append(typePr, p.Name)); !match || err != nil {
+			return false, err
+		}
+		detachObject(p, p.Refs)
+		return true, nil
+	})
+	return err
 }
 
-func excludeT(t *Table, pattern string) (err error) {
+func (m *Matcher) excludeT(t *Table, path ResourcePath) (err error) {
+	n := len(t.Columns) + len(t.Indexes) + len(t.ForeignKeys) + len(t.Triggers) + len(t.Attrs)
+	defer func() {
+		// The cached checksum no longer describes the table.
+		if err == nil && n != len(t.Columns)+len(t.Indexes)+len(t.ForeignKeys)+len(t.Triggers)+len(t.Attrs) {
+			t.Attrs = RemoveAttr[*Checksum](t.Attrs)
+		}
+	}()
 	ex := make(map[*Index]struct{})
 	ef := make(map[*ForeignKey]struct{})
-	if p, exclude := excludeType(typeC, pattern); exclude {
-		t.Columns, err = filter(t.Columns, func(c *Column) (bool, error) {
-			match, err := filepath.Match(p, c.Name)
-			if !match || err != nil {
-				return false, err
-			}
-			for _, idx := range c.Indexes {
-				ex[idx] = struct{}{}
-			}
-			for _, fk := range c.ForeignKeys {
-				ef[fk] = struct{}{}
-			}
-			return true, nil
-		})
+	t.Columns, err = filter(t.Columns, func(c *Column) (bool, error) {
+		match, err := m.Match(path.Append(typeC, c.Name))
+		if !match || err != nil {
+			return false, err
+		}
+		for _, idx := range c.Indexes {
+			ex[idx] = struct{}{}
+		}
+		for _, fk := range c.ForeignKeys {
+			ef[fk] = struct{}{}
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
 	}
-	if p, exclude := excludeType(typeI, pattern); exclude {
-		t.Indexes, err = filter(t.Indexes, func(idx *Index) (bool, error) {
-			if _, ok := ex[idx]; ok {
-				return true, nil
-			}
-			return filepath.Match(p, idx.Name)
-		})
+	t.Indexes, err = filter(t.Indexes, func(idx *Index) (bool, error) {
+		if _, ok := ex[idx]; ok {
+			return true, nil
+		}
+		return m.Match(path.Append(typeI, idx.Name))
+	})
+	if err != nil {
+		return err
 	}
-	if p, exclude := excludeType(typeF, pattern); exclude {
-		t.ForeignKeys, err = filter(t.ForeignKeys, func(fk *ForeignKey) (bool, error) {
-			if _, ok := ef[fk]; ok {
-				return true, nil
-			}
-			return filepath.Match(p, fk.Symbol)
-		})
-	}
-	if p, exclude := excludeType(typeTg, pattern); exclude {
-		t.Triggers, err = filter(t.Triggers, func(t *Trigger) (bool, error) {
-			return filepath.Match(p, t.Name)
-		})
-	}
-	if p, exclude := excludeType(typeK, pattern); exclude {
-		t.Attrs, err = filter(t.Attrs, func(a Attr) (bool, error) {
-			c, ok := a.(*Check)
-			if !ok {
-				return false, nil
-			}
-			match, err := filepath.Match(p, c.Name)
-			if !match || err != nil {
-				return false, err
-			}
+	t.ForeignKeys, err = filter(t.ForeignKeys, func(fk *ForeignKey) (bool, error) {
+		if _, ok := ef[fk]; ok {
 			return true, nil
-		})
+		}
+		return m.Match(path.Append(typeF, fk.Symbol))
+	})
+	if err != nil {
+		return err
+	}
+	t.Triggers, err = filter(t.Triggers, func(tg *Trigger) (bool, error) {
+		return m.Match(path.Append(typeTg, tg.Name))
+	})
+	if err != nil {
+		return err
 	}
-	return
+	t.Attrs, err = filter(t.Attrs, func(a Attr) (bool, error) {
+		c, ok := a.(*Check)
+		if !ok {
+			return false, nil
+		}
+		return m.Match(path.Append(typeK, c.Name))
+	})
+	return err
 }
 
-func excludeV(v *View, pattern string) (err error) {
-	if p, exclude := excludeType(typeC, pattern); exclude {
-		v.Columns, err = filter(v.Columns, func(c *Column) (bool, error) {
-			match, err := filepath.Match(p, c.Name)
-			if !match || err != nil {
-				return false, err
-			}
-			return true, nil
-		})
-	}
-	if p, exclude := excludeType(typeTg, pattern); exclude {
-		v.Triggers, err = filter(v.Triggers, func(t *Trigger) (bool, error) {
-			return filepath.Match(p, t.Name)
-		})
+func (m *Matcher) excludeV(v *View, path ResourcePath) (err error) {
+	n := len(v.Columns) + len(v.Triggers)
+	defer func() {
+		// The cached checksum no longer describes the view.
+		if err == nil && n != len(v.Columns)+len(v.Triggers) {
+			v.Attrs = RemoveAttr[*Checksum](v.Attrs)
+		}
+	}()
+	v.Columns, err = filter(v.Columns, func(c *Column) (bool, error) {
+		return m.Match(path.Append(typeC, c.Name))
+	})
+	if err != nil {
+		return err
 	}
-	return
+	v.Triggers, err = filter(v.Triggers, func(t *Trigger) (bool, error) {
+		return m.Match(path.Append(typeTg, t.Name))
+	})
+	return err
 }
 
 // SpecTypeNamer is an interface that allows to get the spec type and name of the object.
@@ -254,39 +321,156 @@ type SpecTypeNamer interface {
 	SpecName() string
 }
 
-func excludeObjects(all []Object, glob []string) ([]Object, error) {
-	var (
-		objects = make([]Object, 0, len(all))
-		t2glob  = make(map[string]struct {
-			glob    string
-			exclude bool
-		})
-	)
-	for _, o := range all {
+func (m *Matcher) excludeObjects(all []Object, path ResourcePath) ([]Object, error) {
+	return filter(all, func(o Object) (bool, error) {
 		nt, ok := o.(SpecTypeNamer)
 		if !ok {
-			objects = append(objects, o)
-			continue
+			return false, nil
 		}
-		cache, ok := t2glob[nt.SpecType()]
-		if !ok {
-			cache.glob, cache.exclude = excludeType(nt.SpecType(), glob[0])
-			t2glob[nt.SpecType()] = cache
-		}
-		if cache.exclude {
-			match, err := filepath.Match(cache.glob, nt.SpecName())
-			if err != nil {
-				return nil, err
-			}
-			// No match or glob has more than one pattern.
-			if !match || len(glob) != 1 {
-				objects = append(objects, o)
+		return m.Match(path.Append(nt.SpecType(), nt.SpecName()))
+	})
+}
+
+// ExcludeChanges filters the changes of the resources that are excluded by
+// the matcher. It is used by commands that operate on changes, such as lint.
+// Generic objects that are not top-level resources are never filtered, as
+// their schema is unknown.
+func (m *Matcher) ExcludeChanges(changes Changes) (Changes, error) {
+	return filter(changes, func(c Change) (bool, error) {
+		switch c := c.(type) {
+		case *AddSchema:
+			return m.Match(schemaPath(c.S))
+		case *DropSchema:
+			return m.Match(schemaPath(c.S))
+		case *ModifySchema:
+			return m.Match(schemaPath(c.S))
+		case *AddTable:
+			return m.Match(tablePath(c.T))
+		case *DropTable:
+			return m.Match(tablePath(c.T))
+		case *RenameTable:
+			return m.Match(tablePath(c.To))
+		case *ModifyTable:
+			path := tablePath(c.T)
+			if match, err := m.Match(path); match || err != nil {
+				return match, err
 			}
-		} else {
-			objects = append(objects, o)
+			var err error
+			c.Changes, err = m.excludeTableChanges(c.Changes, path)
+			return len(c.Changes) == 0, err
+		case *AddView:
+			return m.Match(viewPath(c.V))
+		case *DropView:
+			return m.Match(viewPath(c.V))
+		case *ModifyView:
+			return m.Match(viewPath(c.To))
+		case *RenameView:
+			return m.Match(viewPath(c.To))
+		case *AddFunc:
+			return m.Match(schemaPath(c.F.Schema).Append(typeFn, c.F.Name))
+		case *DropFunc:
+			return m.Match(schemaPath(c.F.Schema).Append(typeFn, c.F.Name))
+		case *ModifyFunc:
+			return m.Match(schemaPath(c.To.Schema).Append(typeFn, c.To.Name))
+		case *RenameFunc:
+			return m.Match(schemaPath(c.To.Schema).Append(typeFn, c.To.Name))
+		case *AddProc:
+			return m.Match(schemaPath(c.P.Schema).Append(typePr, c.P.Name))
+		case *DropProc:
+			return m.Match(schemaPath(c.P.Schema).Append(typePr, c.P.Name))
+		case *ModifyProc:
+			return m.Match(schemaPath(c.To.Schema).Append(typePr, c.To.Name))
+		case *RenameProc:
+			return m.Match(schemaPath(c.To.Schema).Append(typePr, c.To.Name))
+		case *AddTrigger:
+			return m.Match(triggerPath(c.T))
+		case *DropTrigger:
+			return m.Match(triggerPath(c.T))
+		case *ModifyTrigger:
+			return m.Match(triggerPath(c.To))
+		case *RenameTrigger:
+			return m.Match(triggerPath(c.To))
+		case *AddObject:
+			return m.matchObject(c.O)
+		case *DropObject:
+			return m.matchObject(c.O)
+		case *ModifyObject:
+			return m.matchObject(c.To)
+		case *RenameObject:
+			return m.matchObject(c.To)
 		}
+		return false, nil
+	})
+}
+
+func (m *Matcher) excludeTableChanges(changes []Change, path ResourcePath) ([]Change, error) {
+	return filter(changes, func(c Change) (bool, error) {
+		switch c := c.(type) {
+		case *AddColumn:
+			return m.Match(path.Append(typeC, c.C.Name))
+		case *DropColumn:
+			return m.Match(path.Append(typeC, c.C.Name))
+		case *ModifyColumn:
+			return m.Match(path.Append(typeC, c.To.Name))
+		case *RenameColumn:
+			return m.Match(path.Append(typeC, c.To.Name))
+		case *AddIndex:
+			return m.Match(path.Append(typeI, c.I.Name))
+		case *DropIndex:
+			return m.Match(path.Append(typeI, c.I.Name))
+		case *ModifyIndex:
+			return m.Match(path.Append(typeI, c.To.Name))
+		case *RenameIndex:
+			return m.Match(path.Append(typeI, c.To.Name))
+		case *AddForeignKey:
+			return m.Match(path.Append(typeF, c.F.Symbol))
+		case *DropForeignKey:
+			return m.Match(path.Append(typeF, c.F.Symbol))
+		case *ModifyForeignKey:
+			return m.Match(path.Append(typeF, c.To.Symbol))
+		case *AddCheck:
+			return m.Match(path.Append(typeK, c.C.Name))
+		case *DropCheck:
+			return m.Match(path.Append(typeK, c.C.Name))
+		case *ModifyCheck:
+			return m.Match(path.Append(typeK, c.To.Name))
+		}
+		return false, nil
+	})
+}
+
+// matchObject matches top-level (realm) objects only.
+func (m *Matcher) matchObject(o Object) (bool, error) {
+	if nt, ok := o.(SpecTypeNamer); ok {
+		return m.Match(ResourcePath{}.Append(nt.SpecType(), nt.SpecName()))
+	}
+	return false, nil
+}
+
+func schemaPath(s *Schema) ResourcePath {
+	var name string
+	if s != nil {
+		name = s.Name
+	}
+	return ResourcePath{}.Append(typeS, name)
+}
+
+func tablePath(t *Table) ResourcePath {
+	return schemaPath(t.Schema).Append(typeT, t.Name)
+}
+
+func viewPath(v *View) ResourcePath {
+	return schemaPath(v.Schema).Append(typeV, v.Name)
+}
+
+func triggerPath(t *Trigger) ResourcePath {
+	if t.View != nil {
+		return viewPath(t.View).Append(typeTg, t.Name)
+	}
+	if t.Table != nil {
+		return tablePath(t.Table).Append(typeTg, t.Name)
 	}
-	return objects, nil
+	return ResourcePath{}.Append(typeTg, t.Name)
 }
 
 const (
@@ -321,6 +505,9 @@ func excludeType(t, v string) (string, bool) {
 }
 
 func filter[T any](s []T, f func(T) (bool, error)) ([]T, error) {
+	if len(s) == 0 {
+		return s, nil
+	}
 	r := make([]T, 0, len(s))
 	for i := range s {
 		match, err := f(s[i])
@@ -355,6 +355,20 @@ func TestExcludeRealm_Checks(t *testing.T) {
 	require.Len(t, r.Schemas[0].Tables[0].Attrs, 0)
 }
 
+func TestExcludeRealm_Checksums(t *testing.T) {
+	s := New("s1").AddTables(
+		NewTable("t1").AddColumns(NewColumn("c1"), NewColumn("c2")),
+		NewTable("t2").AddColumns(NewColumn("c1")),
+	)
+	SetChecksums(s)
+	_, err := ExcludeRealm(NewRealm(s), []string{"s1.t1.c2"})
+	require.NoError(t, err)
+	// Checksums of modified tables are no longer valid.
+	require.Len(t, s.Tables[0].Attrs, 0)
+	require.Len(t, s.Tables[1].Attrs, 1)
+	require.IsType(t, &Checksum{}, s.Tables[1].Attrs[0])
+}
+
 func TestExcludeRealm_Columns(t *testing.T) {
 	r := NewRealm(
 		New("s1").AddTables(
@@ -509,3 +523,74 @@ func TestExcludeRealmObjects(t *testing.T) {
 	require.Len(t, r.Schemas, 2)
 	require.Empty(t, r.Objects)
 }
+
+func TestExcludeRealm_Negate(t *testing.T) {
+	r := NewRealm(
+		New("s1").AddTables(
+			NewTable("t1").AddColumns(NewColumn("c1"), NewColumn("c2")),
+			NewTable("t2"),
+			NewTable("t3"),
+		),
+		New("s2").AddTables(NewTable("t1")),
+		New("s3"),
+	)
+	r, err := ExcludeRealm(r, []string{"s1.*", "!s1.t2", "s1.t2.c*", "*", "!s1", "!s2"})
+	require.NoError(t, err)
+	require.Len(t, r.Schemas, 2)
+	require.Equal(t, "s1", r.Schemas[0].Name)
+	require.Equal(t, "s2", r.Schemas[1].Name)
+	require.Len(t, r.Schemas[0].Tables, 1)
+	require.Equal(t, "t2", r.Schemas[0].Tables[0].Name)
+	require.Len(t, r.Schemas[1].Tables, 1)
+
+	s := New("s1").AddTables(
+		NewTable("t1").AddColumns(NewColumn("c1"), NewColumn("c2"), NewColumn("d1")),
+	)
+	NewRealm(s)
+	s, err = ExcludeSchema(s, []string{"t1.*[type=column]", "!t1.c*"})
+	require.NoError(t, err)
+	require.Len(t, s.Tables[0].Columns, 2)
+	require.Equal(t, "c1", s.Tables[0].Columns[0].Name)
+	require.Equal(t, "c2", s.Tables[0].Columns[1].Name)
+
+	_, err = NewMatcher([]string{"!a.b.c.d"})
+	require.EqualError(t, err, `too many parts in pattern: "!a.b.c.d"`)
+}
+
+func TestMatcher_ExcludeChanges(t *testing.T) {
+	var (
+		s1 = New("s1")
+		t1 = NewTable("t1").AddColumns(NewColumn("c1"), NewColumn("c2"))
+		t2 = NewTable("t2")
+		v1 = NewView("v1", "SELECT 1")
+	)
+	s1.AddTables(t1, t2).AddViews(v1)
+	changes := Changes{
+		&AddTable{T: t2},
+		&AddView{V: v1},
+		&ModifyTable{T: t1, Changes: Changes{
+			&DropColumn{C: t1.Columns[0]},
+			&AddColumn{C: t1.Columns[1]},
+		}},
+		&AddSchema{S: New("s2")},
+	}
+	m, err := NewMatcher([]string{"s2", "*.*[type=view]", "s1.t1.c1"})
+	require.NoError(t, err)
+	changes, err = m.ExcludeChanges(changes)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	require.Equal(t, &AddTable{T: t2}, changes[0])
+	require.Equal(t, []Change{&AddColumn{C: t1.Columns[1]}}, changes[1].(*ModifyTable).Changes)
+
+	// Excluding all nested changes removes the table modification.
+	m, err = NewMatcher([]string{"s1.*.*", "!s1.t2"})
+	require.NoError(t, err)
+	changes, err = m.ExcludeChanges(changes)
+	require.NoError(t, err)
+	require.Equal(t, Changes{&AddTable{T: t2}}, changes)
+
+	var nilm *Matcher
+	match, err := nilm.Match(ResourcePath{}.Append("schema", "s1"))
+	require.NoError(t, err)
+	require.False(t, match)
+}
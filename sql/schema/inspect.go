@@ -85,6 +85,11 @@ type (
 		//	*.* // the last item defines the filtering; all resourced under all tables are excluded.
 		//
 		Exclude []string
+
+		// Concurrency defines the maximum number of schemas that are inspected
+		// concurrently. Zero or one means schemas are inspected sequentially.
+		// Drivers that do not support concurrent inspection ignore this option.
+		Concurrency int
 	}
 
 	// InspectRealmOption describes options for RealmInspector.
@@ -109,6 +114,11 @@ type (
 		//	*.*.* // the last item defines the filtering; all resources are excluded in all tables.
 		//
 		Exclude []string
+
+		// Concurrency defines the maximum number of schemas that are inspected
+		// concurrently. Zero or one means schemas are inspected sequentially.
+		// Drivers that do not support concurrent inspection ignore this option.
+		Concurrency int
 	}
 
 	// Inspector is the interface implemented by the different database
@@ -699,6 +699,20 @@ type (
 		V string // LOCAL, CASCADED, NONE, or driver specific.
 	}
 
+	// DiffIgnore is an attribute that instructs the differ to ignore the given
+	// change kinds when comparing an element to its desired state. For example,
+	// a column whose default value is managed outside the schema definition.
+	DiffIgnore struct {
+		Changes ChangeKind
+	}
+
+	// Labels holds user-defined labels attached to a table or a column in its definition
+	// (e.g., owner or pii). Labels are not stored in the database. Hence, they are available
+	// only on states loaded from schema definitions, and used by reports and lint analyzers.
+	Labels struct {
+		V map[string]string
+	}
+
 	// Materialized is a schema attribute that attached to views to indicates
 	// they are MATERIALIZED VIEWs.
 	Materialized struct {
@@ -758,6 +772,32 @@ func (*Charset) attr()         {}
 func (*Collation) attr()       {}
 func (*GeneratedExpr) attr()   {}
 func (*ViewCheckOption) attr() {}
+func (*DiffIgnore) attr()      {}
+func (*Labels) attr()          {}
+
+// Match reports if the labels contain all labels of the selector. An empty
+// value in the selector matches any value of the label.
+func (l *Labels) Match(selector map[string]string) bool {
+	if l == nil || len(l.V) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if lv, ok := l.V[k]; !ok || v != "" && v != lv {
+			return false
+		}
+	}
+	return true
+}
+
+// LabelsOf returns the labels attribute from the given attributes, if exists.
+func LabelsOf(attrs []Attr) *Labels {
+	for _, a := range attrs {
+		if l, ok := a.(*Labels); ok {
+			return l
+		}
+	}
+	return nil
+}
 
 // SpecType returns the type of the spec.
 func (e *EnumType) SpecType() string { return "enum" }
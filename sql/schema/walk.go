@@ -0,0 +1,153 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema
+
+type (
+	// A Visitor's Visit method is invoked for each change encountered by Walk.
+	// If the result visitor w is not nil, Walk visits each of the nested changes
+	// of c (e.g., the changes of a ModifyTable) with the visitor w, followed by a
+	// call of w.Visit(nil).
+	Visitor interface {
+		Visit(c Change) (w Visitor)
+	}
+
+	// A Cursor describes a change encountered during Rewrite.
+	// Information about the change and its parent is available
+	// from the Change, Parent and Index methods.
+	Cursor struct {
+		parent Change
+		change Change
+		index  int
+	}
+
+	// RewriteFunc is the type of the function called for each change
+	// visited by Rewrite. See Rewrite for more details.
+	RewriteFunc func(*Cursor) bool
+)
+
+// Walk traverses the given changes in depth-first order: it starts by calling
+// v.Visit(c) for each change c. If the visitor w returned by v.Visit(c) is not
+// nil, Walk is invoked recursively with visitor w for each of the nested changes
+// of c, followed by a call of w.Visit(nil).
+func Walk(v Visitor, changes []Change) {
+	for _, c := range changes {
+		if w := v.Visit(c); w != nil {
+			if cs := nestedChanges(c); cs != nil {
+				Walk(w, *cs)
+			}
+			w.Visit(nil)
+		}
+	}
+}
+
+// inspector wraps a function to implement the Visitor interface.
+type inspector func(Change) bool
+
+func (f inspector) Visit(c Change) Visitor {
+	if f(c) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the given changes in depth-first order: it starts by calling
+// f(c) for each change c. If f returns true, Inspect invokes f recursively for each
+// of the nested changes of c, followed by a call of f(nil).
+func Inspect(changes []Change, f func(Change) bool) {
+	Walk(inspector(f), changes)
+}
+
+// Change returns the current change, or nil if it was removed.
+func (c *Cursor) Change() Change { return c.change }
+
+// Parent returns the parent of the current change (e.g., a ModifyTable),
+// or nil in case the change is not nested in another change.
+func (c *Cursor) Parent() Change { return c.parent }
+
+// Index returns the index of the current change in the changes of its parent.
+func (c *Cursor) Index() int { return c.index }
+
+// Replace replaces the current change with x. If the replacement is called
+// before the nested changes are visited, the nested changes of x are visited.
+// Replacing a change with nil is equivalent to removing it.
+func (c *Cursor) Replace(x Change) { c.change = x }
+
+// Remove removes the current change from its parent. The nested changes
+// of a removed change are not visited.
+func (c *Cursor) Remove() { c.change = nil }
+
+// Rewrite traverses the given changes recursively, calling pre and post for each
+// change, and returns the (possibly) rewritten changes. The nested changes of the
+// Modify<T> changes are rewritten in place.
+//
+// If pre is not nil, it is called for each change before its nested changes are
+// traversed (pre-order). If pre returns false, no nested changes are traversed,
+// and post is not called for that change.
+//
+// If post is not nil, and a prior call of pre did not return false, post is called
+// for each change after its nested changes are traversed (post-order). If post
+// returns false, the traversal is terminated and Rewrite returns immediately,
+// keeping the rest of the changes as is.
+//
+// The pre and post functions can use the Cursor to replace or remove the current
+// change. Removed changes are omitted from the result, and ModifyTable changes (or
+// other Modify<T> changes) that are left empty are kept as is, and it is up to the
+// caller to remove them (if needed).
+func Rewrite(changes []Change, pre, post RewriteFunc) []Change {
+	r := &rewriter{pre: pre, post: post}
+	return r.list(nil, changes)
+}
+
+type rewriter struct {
+	pre, post RewriteFunc
+	stop      bool
+}
+
+func (r *rewriter) list(parent Change, changes []Change) []Change {
+	rewritten := make([]Change, 0, len(changes))
+	for i, c := range changes {
+		if r.stop {
+			rewritten = append(rewritten, c)
+			continue
+		}
+		if c = r.apply(&Cursor{parent: parent, change: c, index: i}); c != nil {
+			rewritten = append(rewritten, c)
+		}
+	}
+	return rewritten
+}
+
+func (r *rewriter) apply(c *Cursor) Change {
+	if r.pre != nil && !r.pre(c) || c.change == nil {
+		return c.change
+	}
+	if cs := nestedChanges(c.change); cs != nil {
+		*cs = r.list(c.change, *cs)
+	}
+	if !r.stop && r.post != nil && !r.post(c) {
+		r.stop = true
+	}
+	return c.change
+}
+
+// nestedChanges returns a pointer to the nested changes
+// of the given change, or nil if it has no nested changes.
+func nestedChanges(c Change) *[]Change {
+	switch c := c.(type) {
+	case *ModifySchema:
+		return &c.Changes
+	case *ModifyTable:
+		return &c.Changes
+	case *ModifyView:
+		return &c.Changes
+	case *ModifyFunc:
+		return &c.Changes
+	case *ModifyProc:
+		return &c.Changes
+	case *ModifyTrigger:
+		return &c.Changes
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package schema_test
+
+import (
+	"fmt"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	var (
+		users = schema.NewTable("users")
+		posts = schema.NewTable("posts")
+		c1    = schema.NewIntColumn("c1", "int")
+		c2    = schema.NewIntColumn("c2", "int")
+		idx   = schema.NewIndex("idx")
+	)
+	changes := []schema.Change{
+		&schema.AddTable{T: posts},
+		&schema.ModifyTable{
+			T: users,
+			Changes: []schema.Change{
+				&schema.AddColumn{C: c1},
+				&schema.DropColumn{C: c2},
+				&schema.AddIndex{I: idx},
+			},
+		},
+	}
+	var visited []string
+	schema.Inspect(changes, func(c schema.Change) bool {
+		visited = append(visited, fmt.Sprintf("%T", c))
+		return true
+	})
+	require.Equal(t, []string{"*schema.AddTable", "<nil>", "*schema.ModifyTable", "*schema.AddColumn", "<nil>", "*schema.DropColumn", "<nil>", "*schema.AddIndex", "<nil>", "<nil>"}, visited)
+
+	visited = visited[:0]
+	schema.Inspect(changes, func(c schema.Change) bool {
+		visited = append(visited, fmt.Sprintf("%T", c))
+		return false
+	})
+	require.Equal(t, []string{"*schema.AddTable", "*schema.ModifyTable"}, visited, "nested changes are skipped")
+}
+
+func TestRewrite(t *testing.T) {
+	var (
+		users = schema.NewTable("users")
+		posts = schema.NewTable("posts")
+		c1    = schema.NewIntColumn("c1", "int")
+		c2    = schema.NewIntColumn("c2", "int")
+		idx   = schema.NewIndex("idx")
+	)
+	changes := func() []schema.Change {
+		return []schema.Change{
+			&schema.DropTable{T: posts},
+			&schema.ModifyTable{
+				T: users,
+				Changes: []schema.Change{
+					&schema.AddColumn{C: c1},
+					&schema.DropColumn{C: c2},
+					&schema.DropIndex{I: idx},
+				},
+			},
+		}
+	}
+
+	// Remove all destructive changes.
+	var parents []schema.Change
+	got := schema.Rewrite(changes(), func(c *schema.Cursor) bool {
+		switch c.Change().(type) {
+		case *schema.DropTable, *schema.DropColumn, *schema.DropIndex:
+			parents = append(parents, c.Parent())
+			c.Remove()
+		}
+		return true
+	}, nil)
+	require.Len(t, got, 1)
+	require.Equal(t, []schema.Change{&schema.AddColumn{C: c1}}, got[0].(*schema.ModifyTable).Changes)
+	require.Equal(t, []schema.Change{nil, got[0], got[0]}, parents)
+
+	// Replace changes, and visit the nested changes of the replacement.
+	var visited []string
+	got = schema.Rewrite(changes(), func(c *schema.Cursor) bool {
+		switch x := c.Change().(type) {
+		case *schema.DropTable:
+			c.Replace(&schema.ModifyTable{T: x.T, Changes: []schema.Change{&schema.DropColumn{C: c2}}})
+		case *schema.DropColumn:
+			c.Replace(&schema.ModifyColumn{From: x.C, To: x.C, Change: schema.ChangeNull})
+		}
+		return true
+	}, func(c *schema.Cursor) bool {
+		visited = append(visited, fmt.Sprintf("%T:%d", c.Change(), c.Index()))
+		return true
+	})
+	require.Len(t, got, 2)
+	require.Equal(t, []schema.Change{&schema.ModifyColumn{From: c2, To: c2, Change: schema.ChangeNull}}, got[0].(*schema.ModifyTable).Changes)
+	require.IsType(t, (*schema.ModifyColumn)(nil), got[1].(*schema.ModifyTable).Changes[1])
+	require.Equal(t, []string{"*schema.ModifyColumn:0", "*schema.ModifyTable:0", "*schema.AddColumn:0", "*schema.ModifyColumn:1", "*schema.DropIndex:2", "*schema.ModifyTable:1"}, visited)
+
+	// Terminate the traversal on the first nested change, and keep the rest as is.
+	visited = visited[:0]
+	got = schema.Rewrite(changes(), func(c *schema.Cursor) bool {
+		return c.Parent() == nil
+	}, func(c *schema.Cursor) bool {
+		visited = append(visited, fmt.Sprintf("%T", c.Change()))
+		c.Remove()
+		return false
+	})
+	require.Equal(t, []string{"*schema.DropTable"}, visited)
+	require.Len(t, got, 1)
+	require.Len(t, got[0].(*schema.ModifyTable).Changes, 3)
+}
@@ -0,0 +1,241 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package design
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// Analyzer checks for common schema design issues, such as tables
+// without primary keys or foreign keys that are not covered by an index.
+//
+// Schema states are always analyzed. However, migration files are analyzed
+// only if the analyzer was configured explicitly in the lint configuration
+// (e.g., "design {}"), to keep reports of existing projects unchanged.
+type Analyzer struct {
+	sqlcheck.Options
+	// VarcharMax is the maximum size allowed for VARCHAR columns.
+	VarcharMax int `spec:"varchar_max"`
+	// Reserved holds additional words that are not allowed as identifiers.
+	Reserved []string `spec:"reserved"`
+	reserved map[string]bool
+	files    bool
+}
+
+// DefaultVarcharMax is the default maximum size of VARCHAR columns.
+const DefaultVarcharMax = 4096
+
+// New creates a new schema design Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{VarcharMax: DefaultVarcharMax}
+	if r, ok := r.Resource(az.Name()); ok {
+		az.files = true
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing design check options: %w", err)
+		}
+		if err := r.As(az); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing design check options: %w", err)
+		}
+	}
+	if az.VarcharMax <= 0 {
+		return nil, fmt.Errorf("sql/sqlcheck: design varchar_max must be positive, got %d", az.VarcharMax)
+	}
+	az.reserved = make(map[string]bool, len(reservedWords)+len(az.Reserved))
+	for _, w := range append(reservedWords, az.Reserved...) {
+		az.reserved[strings.ToLower(w)] = true
+	}
+	return az, nil
+}
+
+// List of codes.
+var (
+	codeNoPK       = sqlcheck.Code("SD101")
+	codeUnindexedF = sqlcheck.Code("SD102")
+	codeReserved   = sqlcheck.Code("SD103")
+	codeVarchar    = sqlcheck.Code("SD104")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "design"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	if !a.files {
+		return nil
+	}
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			switch c := c.(type) {
+			case *schema.AddTable:
+				diags = append(diags, a.table(c.T, sc.Stmt.Pos)...)
+			case *schema.ModifyTable:
+				for _, mc := range c.Changes {
+					switch mc := mc.(type) {
+					case *schema.AddColumn:
+						diags = append(diags, a.column(c.T, mc.C, sc.Stmt.Pos)...)
+					case *schema.AddForeignKey:
+						diags = append(diags, a.foreignKey(c.T, mc.F, sc.Stmt.Pos)...)
+					}
+				}
+			}
+		}
+	}
+	return a.report(p.Reporter, diags)
+}
+
+// AnalyzeSchema implements sqlcheck.SchemaAnalyzer.
+func (a *Analyzer) AnalyzeSchema(_ context.Context, p *sqlcheck.SchemaPass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, s := range p.Realm.Schemas {
+		for _, t := range s.Tables {
+			diags = append(diags, a.table(t, sqlcheck.ElementPos(t.Attrs))...)
+		}
+	}
+	return a.report(p.Reporter, diags)
+}
+
+func (a *Analyzer) report(w sqlcheck.ReportWriter, diags []sqlcheck.Diagnostic) error {
+	if len(diags) > 0 {
+		const reportText = "schema design issues detected"
+		w.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// table checks the given table and its columns and foreign keys.
+func (a *Analyzer) table(t *schema.Table, pos int) []sqlcheck.Diagnostic {
+	var diags []sqlcheck.Diagnostic
+	if a.reserved[strings.ToLower(t.Name)] {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeReserved,
+			Pos:  pos,
+			Text: fmt.Sprintf("Table %q is named after a reserved word", t.Name),
+		})
+	}
+	if t.PrimaryKey == nil {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeNoPK,
+			Pos:  pos,
+			Text: fmt.Sprintf("Table %q has no primary key", t.Name),
+		})
+	}
+	for _, c := range t.Columns {
+		diags = append(diags, a.column(t, c, pos)...)
+	}
+	for _, fk := range t.ForeignKeys {
+		diags = append(diags, a.foreignKey(t, fk, pos)...)
+	}
+	return diags
+}
+
+// column checks the given column. The position of the column
+// is used, if known, instead of the given (table) position.
+func (a *Analyzer) column(t *schema.Table, c *schema.Column, pos int) []sqlcheck.Diagnostic {
+	if p := sqlcheck.ElementPos(c.Attrs); p > 0 {
+		pos = p
+	}
+	var diags []sqlcheck.Diagnostic
+	if a.reserved[strings.ToLower(c.Name)] {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeReserved,
+			Pos:  pos,
+			Text: fmt.Sprintf("Column %q of table %q is named after a reserved word", c.Name, t.Name),
+		})
+	}
+	if s, ok := c.Type.Type.(*schema.StringType); ok && isVarchar(s.T) && s.Size > a.VarcharMax {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeVarchar,
+			Pos:  pos,
+			Text: fmt.Sprintf("Column %q of table %q has a size of %d, which exceeds the maximum of %d", c.Name, t.Name, s.Size, a.VarcharMax),
+		})
+	}
+	return diags
+}
+
+// foreignKey checks that the columns of the given foreign key are
+// covered by the prefix of an index (or the primary key) of the table.
+func (a *Analyzer) foreignKey(t *schema.Table, fk *schema.ForeignKey, pos int) []sqlcheck.Diagnostic {
+	if len(fk.Columns) == 0 || covered(t.PrimaryKey, fk.Columns) {
+		return nil
+	}
+	for _, idx := range t.Indexes {
+		if covered(idx, fk.Columns) {
+			return nil
+		}
+	}
+	if p := sqlcheck.ElementPos(fk.Attrs); p > 0 {
+		pos = p
+	}
+	names := make([]string, len(fk.Columns))
+	for i, c := range fk.Columns {
+		names[i] = c.Name
+	}
+	return []sqlcheck.Diagnostic{
+		{
+			Code: codeUnindexedF,
+			Pos:  pos,
+			Text: fmt.Sprintf("Foreign key %q of table %q is not covered by an index on column(s) %s", fk.Symbol, t.Name, strings.Join(names, ", ")),
+		},
+	}
+}
+
+// covered reports if the given columns (in any order) are
+// the prefix of the index columns.
+func covered(idx *schema.Index, columns []*schema.Column) bool {
+	if idx == nil || len(idx.Parts) < len(columns) {
+		return false
+	}
+	prefix := make(map[string]bool, len(columns))
+	for _, p := range idx.Parts[:len(columns)] {
+		if p.C == nil {
+			return false
+		}
+		prefix[p.C.Name] = true
+	}
+	for _, c := range columns {
+		if !prefix[c.Name] {
+			return false
+		}
+	}
+	return true
+}
+
+// isVarchar reports if the given type name is a variable-length character type.
+func isVarchar(t string) bool {
+	switch strings.ToLower(t) {
+	case "varchar", "nvarchar", "character varying", "varchar2", "nvarchar2":
+		return true
+	}
+	return false
+}
+
+// reservedWords holds a list of words that are reserved in the SQL
+// standard and in the major databases, and are therefore error-prone
+// to use as identifiers, as they must be quoted in every statement.
+var reservedWords = []string{
+	"all", "alter", "and", "any", "as", "asc", "between", "by", "case", "check",
+	"column", "constraint", "create", "cross", "current_date", "current_time",
+	"current_timestamp", "current_user", "default", "delete", "desc", "distinct",
+	"drop", "else", "end", "except", "exists", "false", "fetch", "for", "foreign",
+	"from", "full", "grant", "group", "having", "in", "index", "inner", "insert",
+	"intersect", "into", "is", "join", "key", "left", "like", "limit", "natural",
+	"not", "null", "offset", "on", "or", "order", "outer", "primary", "references",
+	"right", "select", "session_user", "table", "then", "to", "true", "union",
+	"unique", "update", "user", "using", "values", "when", "where", "with",
+}
@@ -0,0 +1,116 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package design_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/design"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_AnalyzeSchema(t *testing.T) {
+	var cfg struct {
+		schemahcl.DefaultExtension
+	}
+	// language=hcl
+	err := schemahcl.New().EvalBytes([]byte(`
+design {
+  error       = true
+  varchar_max = 255
+  reserved    = ["status"]
+}
+`), &cfg, nil)
+	require.NoError(t, err)
+	az, err := design.New(cfg.Remain())
+	require.NoError(t, err)
+
+	var (
+		users = schema.NewTable("users").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewStringColumn("bio", "varchar", schema.StringSize(1024)),
+				schema.NewStringColumn("status", "varchar", schema.StringSize(10)),
+			)
+		order = schema.NewTable("order").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("user_id", "int"),
+			)
+		pets = schema.NewTable("pets").
+			AddColumns(
+				schema.NewIntColumn("id", "int"),
+				schema.NewIntColumn("owner_id", "int"),
+			)
+	)
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	order.SetPrimaryKey(schema.NewPrimaryKey(order.Columns[0]))
+	order.AddForeignKeys(schema.NewForeignKey("order_user").AddColumns(order.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	pets.AddForeignKeys(schema.NewForeignKey("pet_owner").AddColumns(pets.Columns[1]).SetRefTable(users).AddRefColumns(users.Columns[0]))
+	pets.AddIndexes(schema.NewIndex("pet_owner").AddColumns(pets.Columns[1], pets.Columns[0]))
+	pets.Columns[0].AddAttrs(&schema.Pos{})
+	users.AddAttrs(&schema.Pos{Start: struct{ Line, Column, Byte int }{Byte: 10}})
+
+	var reports []sqlcheck.Report
+	err = az.AnalyzeSchema(context.Background(), &sqlcheck.SchemaPass{
+		Realm: schema.NewRealm(schema.New("public").AddTables(users, order, pets)),
+		Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+			reports = append(reports, r)
+		}),
+	})
+	require.EqualError(t, err, "schema design issues detected")
+	require.Len(t, reports, 1)
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "SD104", Pos: 10, Text: `Column "bio" of table "users" has a size of 1024, which exceeds the maximum of 255`},
+		{Code: "SD103", Pos: 10, Text: `Column "status" of table "users" is named after a reserved word`},
+		{Code: "SD103", Text: `Table "order" is named after a reserved word`},
+		{Code: "SD102", Text: `Foreign key "order_user" of table "order" is not covered by an index on column(s) user_id`},
+		{Code: "SD101", Text: `Table "pets" has no primary key`},
+	}, reports[0].Diagnostics)
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewStringColumn("name", "varchar", schema.StringSize(8192)))
+	var (
+		report *sqlcheck.Report
+		pass   = &sqlcheck.Pass{
+			File: &sqlcheck.File{
+				Changes: []*sqlcheck.Change{
+					{
+						Changes: schema.Changes{&schema.AddTable{T: users}},
+						Stmt:    &migrate.Stmt{Pos: 5, Text: "CREATE TABLE users (name varchar(8192))"},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = &r
+			}),
+		}
+	)
+	// Migration files are not analyzed by default.
+	az, err := design.New(nil)
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Nil(t, report)
+
+	var cfg struct {
+		schemahcl.DefaultExtension
+	}
+	require.NoError(t, schemahcl.New().EvalBytes([]byte(`design {}`), &cfg, nil))
+	az, err = design.New(cfg.Remain())
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.NotNil(t, report)
+	require.Equal(t, []sqlcheck.Diagnostic{
+		{Code: "SD101", Pos: 5, Text: `Table "users" has no primary key`},
+		{Code: "SD104", Pos: 5, Text: `Column "name" of table "users" has a size of 8192, which exceeds the maximum of 4096`},
+	}, report.Diagnostics)
+}
@@ -0,0 +1,142 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package external provides analyzers that delegate the analysis of migration
+// files to external executables. The command receives an Input document on its
+// standard input, and is expected to write an Output document to its standard
+// output. For example:
+//
+//	lint {
+//	  external "no_uuid" {
+//	    command = ["./bin/lint", "--strict"]
+//	    error   = true
+//	  }
+//	}
+package external
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+type (
+	// Analyzer runs an external command to analyze migration files.
+	Analyzer struct {
+		sqlcheck.Options
+		name    string
+		command []string
+	}
+
+	// Input is the JSON document written to the standard input of the command.
+	Input struct {
+		Driver string `json:"Driver,omitempty"` // Driver name of the dev database.
+		File   File   `json:"File"`             // File to analyze.
+	}
+
+	// File describes the analyzed migration file.
+	File struct {
+		Name    string          `json:"Name"`
+		Version string          `json:"Version,omitempty"`
+		Desc    string          `json:"Desc,omitempty"`
+		Stmts   []*migrate.Stmt `json:"Stmts"`
+	}
+
+	// Output is the JSON document the command is expected to write to its standard output.
+	Output struct {
+		Reports []sqlcheck.Report `json:"Reports"`
+	}
+)
+
+// New creates the external analyzers defined by the "external" blocks of the given resource.
+func New(r *schemahcl.Resource) ([]*Analyzer, error) {
+	var az []*Analyzer
+	for _, b := range r.Resources("external") {
+		a := &Analyzer{name: b.Name}
+		if err := b.As(&a.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing external analyzer %q options: %w", b.Name, err)
+		}
+		cmd, ok := a.Attr("command")
+		if !ok {
+			return nil, fmt.Errorf("sql/sqlcheck: missing command for external analyzer %q", b.Name)
+		}
+		args, err := cmd.Strings()
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing command of external analyzer %q: %w", b.Name, err)
+		}
+		if len(args) == 0 || args[0] == "" {
+			return nil, fmt.Errorf("sql/sqlcheck: empty command for external analyzer %q", b.Name)
+		}
+		a.command = args
+		az = append(az, a)
+	}
+	return az, nil
+}
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (a *Analyzer) Name() string {
+	return a.name
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(ctx context.Context, p *sqlcheck.Pass) error {
+	stmts, err := p.File.StmtDecls()
+	if err != nil {
+		return err
+	}
+	in := Input{
+		File: File{
+			Name:    p.File.Name(),
+			Version: p.File.Version(),
+			Desc:    p.File.Desc(),
+			Stmts:   stmts,
+		},
+	}
+	if p.Dev != nil {
+		in.Driver = p.Dev.Name
+	}
+	b, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	var (
+		stdout, stderr bytes.Buffer
+		cmd            = exec.CommandContext(ctx, a.command[0], a.command[1:]...)
+	)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = bytes.NewReader(b), &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return fmt.Errorf("sql/sqlcheck: running external analyzer %q: %w", a.name, err)
+	}
+	var out Output
+	// An empty output is treated as no reports.
+	if o := bytes.TrimSpace(stdout.Bytes()); len(o) > 0 {
+		if err := json.Unmarshal(o, &out); err != nil {
+			return fmt.Errorf("sql/sqlcheck: decoding output of external analyzer %q: %w", a.name, err)
+		}
+	}
+	var text string
+	for _, r := range out.Reports {
+		p.Reporter.WriteReport(r)
+		if text == "" && len(r.Diagnostics) > 0 {
+			text = cmp.Or(r.Text, fmt.Sprintf("%s: issues detected", a.name))
+		}
+	}
+	if text != "" && sqlx.V(a.Error) {
+		return errors.New(text)
+	}
+	return nil
+}
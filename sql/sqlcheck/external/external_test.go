@@ -0,0 +1,124 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package external_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/external"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	az, err := external.New(&schemahcl.Resource{})
+	require.NoError(t, err)
+	require.Empty(t, az)
+
+	_, err = external.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "external", Name: "ext"},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: missing command for external analyzer "ext"`)
+
+	_, err = external.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "external", Name: "ext", Attrs: []*schemahcl.Attr{schemahcl.StringsAttr("command", "")}},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: empty command for external analyzer "ext"`)
+
+	az, err = external.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "external", Name: "a", Attrs: []*schemahcl.Attr{schemahcl.StringsAttr("command", "a")}},
+			{Type: "external", Name: "b", Attrs: []*schemahcl.Attr{schemahcl.StringsAttr("command", "b", "--flag")}},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, az, 2)
+	require.Equal(t, "a", az[0].Name())
+	require.Equal(t, "b", az[1].Name())
+}
+
+func TestAnalyzer_Analyze(t *testing.T) {
+	var (
+		dir     = t.TempDir()
+		reports []sqlcheck.Report
+		pass    = &sqlcheck.Pass{
+			Dev:  &sqlclient.Client{Name: "mysql"},
+			File: &sqlcheck.File{File: migrate.NewLocalFile("1_init.sql", []byte("-- comment\nCREATE TABLE t(c int);\nDROP TABLE t;\n"))},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				reports = append(reports, r)
+			}),
+		}
+		script = func(body string) string {
+			path := filepath.Join(dir, "lint.sh")
+			require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0700))
+			return path
+		}
+		analyzer = func(fail bool, args ...string) *external.Analyzer {
+			az, err := external.New(&schemahcl.Resource{
+				Children: []*schemahcl.Resource{
+					{
+						Type: "external",
+						Name: "ext",
+						Attrs: []*schemahcl.Attr{
+							schemahcl.StringsAttr("command", args...),
+							schemahcl.BoolAttr("error", fail),
+						},
+					},
+				},
+			})
+			require.NoError(t, err)
+			return az[0]
+		}
+	)
+	az := analyzer(false, script(`cat > `+filepath.Join(dir, "input.json")+`
+echo '{"Reports":[{"Text":"tables dropped","Diagnostics":[{"Pos":34,"Text":"Dropping table t","Code":"EX101"}]}]}'
+`))
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Equal(t, []sqlcheck.Report{
+		{Text: "tables dropped", Diagnostics: []sqlcheck.Diagnostic{{Pos: 34, Text: "Dropping table t", Code: "EX101"}}},
+	}, reports)
+	b, err := os.ReadFile(filepath.Join(dir, "input.json"))
+	require.NoError(t, err)
+	var in external.Input
+	require.NoError(t, json.Unmarshal(b, &in))
+	require.Equal(t, "mysql", in.Driver)
+	require.Equal(t, "1_init.sql", in.File.Name)
+	require.Equal(t, "1", in.File.Version)
+	require.Equal(t, "init", in.File.Desc)
+	require.Equal(t, []*migrate.Stmt{
+		{Pos: 11, Text: "CREATE TABLE t(c int);", Comments: []string{"-- comment\n"}},
+		{Pos: 34, Text: "DROP TABLE t;"},
+	}, in.File.Stmts)
+
+	// Diagnostics fail the analysis in error mode.
+	reports = nil
+	az = analyzer(true, script(`echo '{"Reports":[{"Text":"tables dropped","Diagnostics":[{"Pos":34,"Text":"Dropping table t","Code":"EX101"}]}]}'`))
+	require.EqualError(t, az.Analyze(context.Background(), pass), "tables dropped")
+	require.Len(t, reports, 1)
+
+	// Empty output means no reports.
+	reports = nil
+	az = analyzer(true, script(`exit 0`))
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Empty(t, reports)
+
+	az = analyzer(false, script("echo 'boom' >&2\nexit 2"))
+	require.EqualError(t, az.Analyze(context.Background(), pass), `sql/sqlcheck: running external analyzer "ext": exit status 2: boom`)
+
+	az = analyzer(false, script(`echo 'invalid'`))
+	require.ErrorContains(t, az.Analyze(context.Background(), pass), `sql/sqlcheck: decoding output of external analyzer "ext": `)
+}
@@ -97,48 +97,78 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 		for _, c := range sc.Changes {
 			switch c := c.(type) {
 			case *schema.AddSchema:
-				diags = append(diags, a.match(sc, codeNameS, c.S.Name, "Schema", a.Schema)...)
+				diags = append(diags, a.match(sc.Stmt.Pos, codeNameS, c.S.Name, "Schema", a.Schema)...)
 			case *schema.AddTable:
-				diags = append(diags, a.match(sc, codeNameT, c.T.Name, "Table", a.Table)...)
+				diags = append(diags, a.match(sc.Stmt.Pos, codeNameT, c.T.Name, "Table", a.Table)...)
 				for _, c := range c.T.Columns {
-					diags = append(diags, a.match(sc, codeNameC, c.Name, "Column", a.Column)...)
+					diags = append(diags, a.match(sc.Stmt.Pos, codeNameC, c.Name, "Column", a.Column)...)
 				}
 				for _, i := range c.T.Indexes {
-					diags = append(diags, a.match(sc, codeNameI, i.Name, "Index", a.Index)...)
+					diags = append(diags, a.match(sc.Stmt.Pos, codeNameI, i.Name, "Index", a.Index)...)
 				}
 				for _, f := range c.T.ForeignKeys {
-					diags = append(diags, a.match(sc, codeNameF, f.Symbol, "Foreign-key constraint", a.ForeignKey)...)
+					diags = append(diags, a.match(sc.Stmt.Pos, codeNameF, f.Symbol, "Foreign-key constraint", a.ForeignKey)...)
 				}
 				for _, at := range c.T.Attrs {
 					if k, ok := at.(*schema.Check); ok {
-						diags = append(diags, a.match(sc, codeNameK, k.Name, "Check constraint", a.Check)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameK, k.Name, "Check constraint", a.Check)...)
 					}
 				}
 			case *schema.RenameTable:
-				diags = append(diags, a.match(sc, codeNameT, c.To.Name, "Table", a.Table)...)
+				diags = append(diags, a.match(sc.Stmt.Pos, codeNameT, c.To.Name, "Table", a.Table)...)
 			case *schema.ModifyTable:
 				for i := range c.Changes {
 					switch mc := c.Changes[i].(type) {
 					case *schema.AddColumn:
-						diags = append(diags, a.match(sc, codeNameC, mc.C.Name, "Column", a.Column)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameC, mc.C.Name, "Column", a.Column)...)
 					case *schema.RenameColumn:
-						diags = append(diags, a.match(sc, codeNameC, mc.To.Name, "Column", a.Column)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameC, mc.To.Name, "Column", a.Column)...)
 					case *schema.AddIndex:
-						diags = append(diags, a.match(sc, codeNameI, mc.I.Name, "Index", a.Index)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameI, mc.I.Name, "Index", a.Index)...)
 					case *schema.RenameIndex:
-						diags = append(diags, a.match(sc, codeNameI, mc.To.Name, "Index", a.Index)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameI, mc.To.Name, "Index", a.Index)...)
 					case *schema.AddForeignKey:
-						diags = append(diags, a.match(sc, codeNameF, mc.F.Symbol, "Foreign-key constraint", a.ForeignKey)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameF, mc.F.Symbol, "Foreign-key constraint", a.ForeignKey)...)
 					case *schema.AddCheck:
-						diags = append(diags, a.match(sc, codeNameK, mc.C.Name, "Check constraint", a.Check)...)
+						diags = append(diags, a.match(sc.Stmt.Pos, codeNameK, mc.C.Name, "Check constraint", a.Check)...)
 					}
 				}
 			}
 		}
 	}
+	return a.report(p.Reporter, diags)
+}
+
+// AnalyzeSchema implements sqlcheck.SchemaAnalyzer.
+func (a *Analyzer) AnalyzeSchema(_ context.Context, p *sqlcheck.SchemaPass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, s := range p.Realm.Schemas {
+		diags = append(diags, a.match(sqlcheck.ElementPos(s.Attrs), codeNameS, s.Name, "Schema", a.Schema)...)
+		for _, t := range s.Tables {
+			diags = append(diags, a.match(sqlcheck.ElementPos(t.Attrs), codeNameT, t.Name, "Table", a.Table)...)
+			for _, c := range t.Columns {
+				diags = append(diags, a.match(sqlcheck.ElementPos(c.Attrs), codeNameC, c.Name, "Column", a.Column)...)
+			}
+			for _, i := range t.Indexes {
+				diags = append(diags, a.match(sqlcheck.ElementPos(i.Attrs), codeNameI, i.Name, "Index", a.Index)...)
+			}
+			for _, f := range t.ForeignKeys {
+				diags = append(diags, a.match(sqlcheck.ElementPos(f.Attrs), codeNameF, f.Symbol, "Foreign-key constraint", a.ForeignKey)...)
+			}
+			for _, at := range t.Attrs {
+				if k, ok := at.(*schema.Check); ok {
+					diags = append(diags, a.match(sqlcheck.ElementPos(k.Attrs), codeNameK, k.Name, "Check constraint", a.Check)...)
+				}
+			}
+		}
+	}
+	return a.report(p.Reporter, diags)
+}
+
+func (a *Analyzer) report(w sqlcheck.ReportWriter, diags []sqlcheck.Diagnostic) error {
 	if len(diags) > 0 {
 		const reportText = "naming violations detected"
-		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		w.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
 		if sqlx.V(a.Error) {
 			return errors.New(reportText)
 		}
@@ -146,7 +176,7 @@ func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
 	return nil
 }
 
-func (a *Analyzer) match(c *sqlcheck.Change, code, name, resource string, opts Options) []sqlcheck.Diagnostic {
+func (a *Analyzer) match(pos int, code, name, resource string, opts Options) []sqlcheck.Diagnostic {
 	re, msg := opts.re, opts.Message
 	if re == nil {
 		re, msg = a.All.re, a.All.Message
@@ -155,7 +185,7 @@ func (a *Analyzer) match(c *sqlcheck.Change, code, name, resource string, opts O
 		return nil
 	}
 	d := sqlcheck.Diagnostic{
-		Pos:  c.Stmt.Pos,
+		Pos:  pos,
 		Text: fmt.Sprintf("%s named %q violates the naming policy", resource, name),
 		Code: code,
 	}
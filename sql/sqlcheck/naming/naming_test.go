@@ -76,3 +76,37 @@ naming {
 	require.Equal(t, `Table named "Users" violates the naming policy: must be lowercase`, report.Diagnostics[0].Text)
 	require.Equal(t, `Index named "pet_name" violates the naming policy: must be lowercase and end with _idx`, report.Diagnostics[1].Text)
 }
+
+func TestAnalyzer_AnalyzeSchema(t *testing.T) {
+	var cfg struct {
+		schemahcl.DefaultExtension
+	}
+	// language=hcl
+	err := schemahcl.New().EvalBytes([]byte(`
+naming {
+  column {
+    match   = "^[a-z_]+$"
+    message = "must be snake_case"
+  }
+}
+`), &cfg, nil)
+	require.NoError(t, err)
+	az, err := naming.New(cfg.Remain())
+	require.NoError(t, err)
+	users := schema.NewTable("users").AddColumns(
+		schema.NewIntColumn("id", "int"),
+		schema.NewStringColumn("firstName", "text").AddAttrs(&schema.Pos{Start: struct{ Line, Column, Byte int }{Byte: 42}}),
+	)
+	var report *sqlcheck.Report
+	err = az.AnalyzeSchema(context.Background(), &sqlcheck.SchemaPass{
+		Realm: schema.NewRealm(schema.New("public").AddTables(users)),
+		Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+			report = &r
+		}),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, 42, report.Diagnostics[0].Pos)
+	require.Equal(t, `Column named "firstName" violates the naming policy: must be snake_case`, report.Diagnostics[0].Text)
+}
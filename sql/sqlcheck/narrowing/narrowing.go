@@ -0,0 +1,219 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package narrowing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+)
+
+// Analyzer checks for column type changes that narrow the range or
+// the size of the stored values, and might cause data loss.
+type Analyzer struct {
+	sqlcheck.Options
+	// Allow holds the glob patterns of the columns that are allowed
+	// to be narrowed. A pattern is matched against the column name,
+	// qualified with its table and optionally its schema name. For
+	// example, "users.name", "public.users.name" or "audit_*.*".
+	Allow []string
+}
+
+// New creates a new type-narrowing changes Analyzer with the given options.
+func New(r *schemahcl.Resource) (*Analyzer, error) {
+	az := &Analyzer{}
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing narrowing check options: %w", err)
+		}
+		if err := az.parseAllow(r); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing narrowing check options: %w", err)
+		}
+	}
+	return az, nil
+}
+
+// parseAllow parses the "allow" list of the analyzer, if exists.
+// For example:
+//
+//	narrowing {
+//	  allow = ["users.name", "audit_*.*"]
+//	}
+func (a *Analyzer) parseAllow(r *schemahcl.Resource) error {
+	attr, ok := r.Attr("allow")
+	if !ok {
+		return nil
+	}
+	patterns, err := attr.Strings()
+	if err != nil {
+		return fmt.Errorf("allow: %w", err)
+	}
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("allow: invalid pattern %q: %w", p, err)
+		}
+	}
+	a.Allow = patterns
+	return nil
+}
+
+// List of codes.
+var (
+	codeNarrowSize    = sqlcheck.Code("TN101")
+	codeNarrowInt     = sqlcheck.Code("TN102")
+	codeNarrowDecimal = sqlcheck.Code("TN103")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "narrowing"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			m, ok := c.(*schema.ModifyTable)
+			// Tables that were created in this file contain no data.
+			if !ok || p.File.TableSpan(m.T)&sqlcheck.SpanAdded != 0 {
+				continue
+			}
+			for _, c := range m.Changes {
+				mc, ok := c.(*schema.ModifyColumn)
+				if !ok || !mc.Change.Is(schema.ChangeType) || p.File.ColumnSpan(m.T, mc.From)&sqlcheck.SpanAdded != 0 || a.allowed(m.T, mc.From) {
+					continue
+				}
+				if code, ok := narrowed(mc.From.Type.Type, mc.To.Type.Type); ok {
+					diags = append(diags, sqlcheck.Diagnostic{
+						Code: code,
+						Pos:  sc.Stmt.Pos,
+						Text: fmt.Sprintf(
+							"Narrowing the type of column %q on table %q from %q to %q might truncate or fail on existing data",
+							mc.From.Name, m.T.Name, typeName(mc.From), typeName(mc.To),
+						),
+					})
+				}
+			}
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "type narrowing changes detected"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// allowed reports if the given column matches one of the allow patterns.
+func (a *Analyzer) allowed(t *schema.Table, c *schema.Column) bool {
+	names := []string{t.Name + "." + c.Name}
+	if t.Schema != nil && t.Schema.Name != "" {
+		names = append(names, t.Schema.Name+"."+names[0])
+	}
+	for _, p := range a.Allow {
+		for _, n := range names {
+			if ok, _ := filepath.Match(p, n); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// narrowed reports if changing a column from one type to the
+// other narrows its values, and returns the diagnostic code.
+func narrowed(from, to schema.Type) (string, bool) {
+	switch from := from.(type) {
+	case *schema.StringType:
+		to, ok := to.(*schema.StringType)
+		// An unsized string of the same type (e.g., VARCHAR or VARCHAR(MAX))
+		// is considered unbounded. Other unsized types are not compared, as
+		// their limit depends on the database.
+		if ok && to.Size > 0 && (from.Size > to.Size || from.Size == 0 && strings.EqualFold(from.T, to.T)) {
+			return codeNarrowSize, true
+		}
+	case *schema.BinaryType:
+		to, ok := to.(*schema.BinaryType)
+		if ok && to.Size != nil && (from.Size != nil && *from.Size > *to.Size || from.Size == nil && strings.EqualFold(from.T, to.T)) {
+			return codeNarrowSize, true
+		}
+	case *schema.IntegerType:
+		to, ok := to.(*schema.IntegerType)
+		if !ok {
+			break
+		}
+		s1, s2 := intSize(from.T), intSize(to.T)
+		if s1 > 0 && s2 > 0 && (s1 > s2 || s1 == s2 && from.Unsigned != to.Unsigned) {
+			return codeNarrowInt, true
+		}
+	case *schema.DecimalType:
+		to, ok := to.(*schema.DecimalType)
+		switch {
+		case !ok || to.Precision == 0:
+			// Changing to an unconstrained decimal does not narrow values.
+		case from.Precision == 0,
+			// Dropping precision of the fractional part, or
+			// reducing the number of digits of the integral part.
+			to.Scale < from.Scale, to.Precision-to.Scale < from.Precision-from.Scale:
+			return codeNarrowDecimal, true
+		}
+	}
+	return "", false
+}
+
+// intSize returns the storage size (in bytes) of the given integer
+// type, or 0 in case the type is unknown.
+func intSize(t string) int {
+	switch strings.ToLower(t) {
+	case "tinyint":
+		return 1
+	case "smallint", "int2":
+		return 2
+	case "mediumint":
+		return 3
+	case "int", "integer", "int4":
+		return 4
+	case "bigint", "int8":
+		return 8
+	}
+	return 0
+}
+
+// typeName returns the type name of the column for reporting.
+func typeName(c *schema.Column) string {
+	if c.Type.Raw != "" {
+		return c.Type.Raw
+	}
+	switch t := c.Type.Type.(type) {
+	case *schema.StringType:
+		if t.Size > 0 {
+			return fmt.Sprintf("%s(%d)", t.T, t.Size)
+		}
+		return t.T
+	case *schema.BinaryType:
+		if t.Size != nil {
+			return fmt.Sprintf("%s(%d)", t.T, *t.Size)
+		}
+		return t.T
+	case *schema.IntegerType:
+		if t.Unsigned {
+			return t.T + " unsigned"
+		}
+		return t.T
+	case *schema.DecimalType:
+		return fmt.Sprintf("%s(%d,%d)", t.T, t.Precision, t.Scale)
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package narrowing_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/narrowing"
+	"ariga.io/atlas/sql/sqlclient"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzer_Narrowing(t *testing.T) {
+	var (
+		report sqlcheck.Report
+		users  = schema.NewTable("users").SetSchema(schema.New("test"))
+		modify = func(name string, from, to schema.Type) *schema.ModifyColumn {
+			return &schema.ModifyColumn{
+				From:   schema.NewColumn(name).SetType(from),
+				To:     schema.NewColumn(name).SetType(to),
+				Change: schema.ChangeType,
+			}
+		}
+		size = func(n int) *int { return &n }
+		pass = &sqlcheck.Pass{
+			Dev: &sqlclient.Client{Name: "mysql"},
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  10,
+							Text: "ALTER TABLE `users`",
+						},
+						Changes: []schema.Change{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									modify("a", &schema.StringType{T: "varchar", Size: 255}, &schema.StringType{T: "varchar", Size: 50}),
+									modify("b", &schema.StringType{T: "varchar", Size: 50}, &schema.StringType{T: "varchar", Size: 255}),
+									modify("c", &schema.IntegerType{T: "bigint"}, &schema.IntegerType{T: "int"}),
+									modify("d", &schema.IntegerType{T: "int"}, &schema.IntegerType{T: "int", Unsigned: true}),
+									modify("e", &schema.DecimalType{T: "decimal", Precision: 10, Scale: 4}, &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}),
+									modify("f", &schema.DecimalType{T: "decimal", Precision: 10, Scale: 2}, &schema.DecimalType{T: "decimal", Precision: 12, Scale: 2}),
+									modify("g", &schema.BinaryType{T: "varbinary", Size: size(16)}, &schema.BinaryType{T: "varbinary", Size: size(8)}),
+									modify("h", &schema.StringType{T: "text"}, &schema.StringType{T: "varchar", Size: 10}),
+									modify("allowed", &schema.StringType{T: "varchar", Size: 255}, &schema.StringType{T: "varchar", Size: 10}),
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = r
+			}),
+		}
+	)
+	az, err := narrowing.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "narrowing",
+				Attrs: []*schemahcl.Attr{
+					schemahcl.BoolAttr("error", true),
+					schemahcl.StringsAttr("allow", "test.*.allowed"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test.*.allowed"}, az.Allow)
+	err = az.Analyze(context.Background(), pass)
+	require.EqualError(t, err, "type narrowing changes detected")
+	require.Equal(t, "type narrowing changes detected", report.Text)
+	require.Len(t, report.Diagnostics, 5)
+	require.Equal(t, 10, report.Diagnostics[0].Pos)
+	require.Equal(t, "TN101", report.Diagnostics[0].Code)
+	require.Equal(t, `Narrowing the type of column "a" on table "users" from "varchar(255)" to "varchar(50)" might truncate or fail on existing data`, report.Diagnostics[0].Text)
+	require.Equal(t, "TN102", report.Diagnostics[1].Code)
+	require.Equal(t, `Narrowing the type of column "c" on table "users" from "bigint" to "int" might truncate or fail on existing data`, report.Diagnostics[1].Text)
+	require.Equal(t, "TN102", report.Diagnostics[2].Code)
+	require.Equal(t, `Narrowing the type of column "d" on table "users" from "int" to "int unsigned" might truncate or fail on existing data`, report.Diagnostics[2].Text)
+	require.Equal(t, "TN103", report.Diagnostics[3].Code)
+	require.Equal(t, `Narrowing the type of column "e" on table "users" from "decimal(10,4)" to "decimal(10,2)" might truncate or fail on existing data`, report.Diagnostics[3].Text)
+	require.Equal(t, "TN101", report.Diagnostics[4].Code)
+	require.Equal(t, `Narrowing the type of column "g" on table "users" from "varbinary(16)" to "varbinary(8)" might truncate or fail on existing data`, report.Diagnostics[4].Text)
+
+	// Diagnostics are reported without failing by default.
+	az, err = narrowing.New(&schemahcl.Resource{})
+	require.NoError(t, err)
+	require.NoError(t, az.Analyze(context.Background(), pass))
+
+	_, err = narrowing.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type:  "narrowing",
+				Attrs: []*schemahcl.Attr{schemahcl.StringsAttr("allow", "[")},
+			},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: parsing narrowing check options: allow: invalid pattern "[": syntax error in pattern`)
+}
+
+type testFile struct {
+	name string
+	migrate.File
+}
+
+func (t testFile) Name() string {
+	return t.name
+}
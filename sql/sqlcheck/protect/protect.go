@@ -0,0 +1,167 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package protect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/internal/sqlx"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// Analyzer checks for changes that drop or modify protected resources.
+type Analyzer struct {
+	sqlcheck.Options
+	// Patterns of the protected resources. See migrate.CheckProtected.
+	Patterns []string
+	// Labels selector of the protected tables and columns. An empty
+	// value matches any value of the label. See schema.Labels.Match.
+	Labels map[string]string
+}
+
+// New creates a new protected resources Analyzer with the given options and patterns.
+func New(r *schemahcl.Resource, patterns []string) (*Analyzer, error) {
+	az := &Analyzer{Patterns: patterns}
+	az.Error = sqlx.P(true)
+	if r, ok := r.Resource(az.Name()); ok {
+		if err := r.As(&az.Options); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing protect check options: %w", err)
+		}
+		if err := az.parseLabels(r); err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: parsing protect check options: %w", err)
+		}
+	}
+	return az, nil
+}
+
+// parseLabels parses the "labels" selector of the analyzer, if exists.
+// For example:
+//
+//	protect {
+//	  labels = { pii = "true" }
+//	}
+func (a *Analyzer) parseLabels(r *schemahcl.Resource) error {
+	attr, ok := r.Attr("labels")
+	if !ok {
+		return nil
+	}
+	if t := attr.V.Type(); !t.IsObjectType() && !t.IsMapType() {
+		return fmt.Errorf("labels: expect a map of strings, got %s", t.FriendlyName())
+	}
+	a.Labels = make(map[string]string)
+	for it := attr.V.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		if v.IsNull() || v.Type() != cty.String {
+			return fmt.Errorf("labels: expect string value for label %q", k.AsString())
+		}
+		a.Labels[k.AsString()] = v.AsString()
+	}
+	return nil
+}
+
+// List of codes.
+var (
+	codeProtected = sqlcheck.Code("PR101")
+	codeLabeled   = sqlcheck.Code("PR102")
+)
+
+// Name of the analyzer. Implements the sqlcheck.NamedAnalyzer interface.
+func (*Analyzer) Name() string {
+	return "protect"
+}
+
+// Analyze implements sqlcheck.Analyzer.
+func (a *Analyzer) Analyze(_ context.Context, p *sqlcheck.Pass) error {
+	var diags []sqlcheck.Diagnostic
+	for _, sc := range p.File.Changes {
+		for _, c := range sc.Changes {
+			// Tables that were created in this file are not protected yet.
+			if m, ok := c.(*schema.ModifyTable); ok && p.File.TableSpan(m.T)&sqlcheck.SpanAdded != 0 {
+				continue
+			}
+			diags = append(diags, a.labeled(sc.Stmt.Pos, c)...)
+			err := migrate.CheckProtected([]schema.Change{c}, a.Patterns)
+			if err == nil {
+				continue
+			}
+			perr := &migrate.ProtectedError{}
+			if !errors.As(err, &perr) {
+				return err
+			}
+			diags = append(diags, sqlcheck.Diagnostic{
+				Code: codeProtected,
+				Pos:  sc.Stmt.Pos,
+				Text: fmt.Sprintf("Cannot %s protected %s %q (matched pattern %q)", perr.Action, perr.Type, perr.Name, perr.Pattern),
+			})
+		}
+	}
+	if len(diags) > 0 {
+		const reportText = "protected resources changed"
+		p.Reporter.WriteReport(sqlcheck.Report{Text: reportText, Diagnostics: diags})
+		if sqlx.V(a.Error) {
+			return errors.New(reportText)
+		}
+	}
+	return nil
+}
+
+// labeled returns the diagnostics for changes that drop or modify tables
+// and columns whose labels match the labels selector of the analyzer.
+func (a *Analyzer) labeled(pos int, c schema.Change) []sqlcheck.Diagnostic {
+	if len(a.Labels) == 0 {
+		return nil
+	}
+	var diags []sqlcheck.Diagnostic
+	report := func(action, typ, name string) {
+		diags = append(diags, sqlcheck.Diagnostic{
+			Code: codeLabeled,
+			Pos:  pos,
+			Text: fmt.Sprintf("Cannot %s protected %s %q (matched labels %s)", action, typ, name, a.selector()),
+		})
+	}
+	switch c := c.(type) {
+	case *schema.DropTable:
+		if schema.LabelsOf(c.T.Attrs).Match(a.Labels) {
+			report("drop", "table", c.T.Name)
+		}
+	case *schema.ModifyTable:
+		for _, c1 := range c.Changes {
+			switch c1 := c1.(type) {
+			case *schema.DropColumn:
+				if schema.LabelsOf(c1.C.Attrs).Match(a.Labels) {
+					report("drop", "column", c.T.Name+"."+c1.C.Name)
+				}
+			case *schema.ModifyColumn:
+				if schema.LabelsOf(c1.From.Attrs).Match(a.Labels) || schema.LabelsOf(c1.To.Attrs).Match(a.Labels) {
+					report("modify", "column", c.T.Name+"."+c1.From.Name)
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// selector returns the string representation of the labels selector.
+func (a *Analyzer) selector() string {
+	ls := make([]string, 0, len(a.Labels))
+	for k, v := range a.Labels {
+		if v == "" {
+			ls = append(ls, k)
+		} else {
+			ls = append(ls, k+"="+v)
+		}
+	}
+	sort.Strings(ls)
+	return strings.Join(ls, ",")
+}
@@ -0,0 +1,160 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package protect_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/migrate"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlcheck"
+	"ariga.io/atlas/sql/sqlcheck/protect"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestAnalyzer_Protected(t *testing.T) {
+	var (
+		report sqlcheck.Report
+		public = schema.New("public")
+		pass   = &sqlcheck.Pass{
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  1,
+							Text: "DROP TABLE audit_log",
+						},
+						Changes: []schema.Change{
+							&schema.DropTable{T: schema.NewTable("audit_log").SetSchema(public)},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  2,
+							Text: "ALTER TABLE users ADD COLUMN name text",
+						},
+						Changes: []schema.Change{
+							&schema.ModifyTable{
+								T: schema.NewTable("users").SetSchema(public),
+								Changes: schema.Changes{
+									&schema.AddColumn{C: schema.NewColumn("name")},
+								},
+							},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = r
+			}),
+		}
+	)
+	az, err := protect.New(&schemahcl.Resource{}, []string{"public.audit_*"})
+	require.NoError(t, err)
+	err = az.Analyze(context.Background(), pass)
+	require.EqualError(t, err, "protected resources changed")
+	require.Equal(t, "protected resources changed", report.Text)
+	require.Len(t, report.Diagnostics, 1)
+	require.Equal(t, 1, report.Diagnostics[0].Pos)
+	require.Equal(t, `Cannot drop protected table "public.audit_log" (matched pattern "public.audit_*")`, report.Diagnostics[0].Text)
+
+	report = sqlcheck.Report{}
+	az.Patterns = []string{"private"}
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Empty(t, report.Diagnostics)
+}
+
+func TestAnalyzer_Labels(t *testing.T) {
+	var (
+		report sqlcheck.Report
+		public = schema.New("public")
+		users  = schema.NewTable("users").SetSchema(public)
+		pii    = &schema.Labels{V: map[string]string{"pii": "true"}}
+		pass   = &sqlcheck.Pass{
+			File: &sqlcheck.File{
+				File: testFile{name: "1.sql"},
+				Changes: []*sqlcheck.Change{
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  1,
+							Text: "ALTER TABLE users DROP COLUMN email, DROP COLUMN name",
+						},
+						Changes: []schema.Change{
+							&schema.ModifyTable{
+								T: users,
+								Changes: schema.Changes{
+									&schema.DropColumn{C: schema.NewColumn("email").AddAttrs(pii)},
+									&schema.DropColumn{C: schema.NewColumn("name")},
+								},
+							},
+						},
+					},
+					{
+						Stmt: &migrate.Stmt{
+							Pos:  2,
+							Text: "DROP TABLE cards",
+						},
+						Changes: []schema.Change{
+							&schema.DropTable{T: schema.NewTable("cards").SetSchema(public).AddAttrs(pii)},
+						},
+					},
+				},
+			},
+			Reporter: sqlcheck.ReportWriterFunc(func(r sqlcheck.Report) {
+				report = r
+			}),
+		}
+	)
+	az, err := protect.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "protect",
+				Attrs: []*schemahcl.Attr{
+					{K: "labels", V: cty.ObjectVal(map[string]cty.Value{"pii": cty.StringVal("true")})},
+				},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"pii": "true"}, az.Labels)
+	err = az.Analyze(context.Background(), pass)
+	require.EqualError(t, err, "protected resources changed")
+	require.Len(t, report.Diagnostics, 2)
+	require.Equal(t, 1, report.Diagnostics[0].Pos)
+	require.Equal(t, `Cannot drop protected column "users.email" (matched labels pii=true)`, report.Diagnostics[0].Text)
+	require.Equal(t, 2, report.Diagnostics[1].Pos)
+	require.Equal(t, `Cannot drop protected table "cards" (matched labels pii=true)`, report.Diagnostics[1].Text)
+
+	report = sqlcheck.Report{}
+	az.Labels = map[string]string{"owner": ""}
+	require.NoError(t, az.Analyze(context.Background(), pass))
+	require.Empty(t, report.Diagnostics)
+
+	_, err = protect.New(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{
+				Type: "protect",
+				Attrs: []*schemahcl.Attr{
+					{K: "labels", V: cty.StringVal("pii")},
+				},
+			},
+		},
+	}, nil)
+	require.EqualError(t, err, "sql/sqlcheck: parsing protect check options: labels: expect a map of strings, got string")
+}
+
+type testFile struct {
+	name string
+	migrate.File
+}
+
+func (t testFile) Name() string {
+	return t.name
+}
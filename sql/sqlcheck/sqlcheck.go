@@ -12,6 +12,7 @@ package sqlcheck
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	"ariga.io/atlas/schemahcl"
@@ -35,6 +36,26 @@ type (
 		Name() string
 	}
 
+	// A SchemaAnalyzer describes an analyzer that checks a schema state (e.g.,
+	// an HCL or SQL schema definition), rather than the changes of a migration
+	// file. Analyzers may implement both interfaces.
+	SchemaAnalyzer interface {
+		// AnalyzeSchema executes the analysis function on the schema state.
+		AnalyzeSchema(context.Context, *SchemaPass) error
+	}
+
+	// A SchemaPass provides information to the SchemaAnalyzer.AnalyzeSchema function.
+	SchemaPass struct {
+		// Realm holds the schema state to be analyzed.
+		Realm *schema.Realm
+
+		// Dev is a driver-specific environment used to execute analysis work.
+		Dev *sqlclient.Client
+
+		// Report reports analysis reports.
+		Reporter ReportWriter
+	}
+
 	// A Pass provides information to the Analyzer.Analyze function
 	// that applies a specific analyzer to an SQL file.
 	Pass struct {
@@ -291,6 +312,18 @@ func (f *File) tableSpan(t *schema.Table) *tableSpan {
 	return f.spans[t.Schema.Name].tables[t.Name]
 }
 
+// ElementPos returns the position (byte offset) of a schema element in the file
+// it was loaded from, or 0 if it is unknown. Used by schema analyzers to report
+// the position of diagnostics.
+func ElementPos(attrs []schema.Attr) int {
+	for _, a := range attrs {
+		if p, ok := a.(*schema.Pos); ok {
+			return p.Start.Byte
+		}
+	}
+	return 0
+}
+
 // codes registry
 var codes sync.Map
 
@@ -321,3 +354,42 @@ func AnalyzerFor(name string, r *schemahcl.Resource) ([]Analyzer, error) {
 	}
 	return nil, nil
 }
+
+// custom analyzers registered by users.
+var custom sync.Map
+
+// RegisterAnalyzer registers a constructor function for a custom analyzer under the given
+// name. The analyzer is enabled by an "analyzer" block with the same name in the lint
+// configuration, and its constructor is called with this block. For example:
+//
+//	lint {
+//	  analyzer "no_uuid" {
+//	    error = true
+//	  }
+//	}
+func RegisterAnalyzer(name string, f func(*schemahcl.Resource) (Analyzer, error)) {
+	if f == nil {
+		panic("sqlcheck: RegisterAnalyzer constructor is nil")
+	}
+	if _, loaded := custom.LoadOrStore(name, f); loaded {
+		panic("sqlcheck: RegisterAnalyzer called twice for " + name)
+	}
+}
+
+// CustomAnalyzers instantiates the custom analyzers enabled by the "analyzer"
+// blocks of the given HCL resource, using their registered constructor functions.
+func CustomAnalyzers(r *schemahcl.Resource) ([]Analyzer, error) {
+	var az []Analyzer
+	for _, b := range r.Resources("analyzer") {
+		f, ok := custom.Load(b.Name)
+		if !ok {
+			return nil, fmt.Errorf("sql/sqlcheck: analyzer %q was not registered", b.Name)
+		}
+		a, err := f.(func(*schemahcl.Resource) (Analyzer, error))(b)
+		if err != nil {
+			return nil, fmt.Errorf("sql/sqlcheck: creating analyzer %q: %w", b.Name, err)
+		}
+		az = append(az, a)
+	}
+	return az, nil
+}
@@ -0,0 +1,49 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package sqlcheck_test
+
+import (
+	"context"
+	"testing"
+
+	"ariga.io/atlas/schemahcl"
+	"ariga.io/atlas/sql/sqlcheck"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomAnalyzers(t *testing.T) {
+	var names []string
+	sqlcheck.RegisterAnalyzer("custom", func(r *schemahcl.Resource) (sqlcheck.Analyzer, error) {
+		names = append(names, r.Name)
+		return sqlcheck.AnalyzerFunc(func(context.Context, *sqlcheck.Pass) error { return nil }), nil
+	})
+	require.PanicsWithValue(t, "sqlcheck: RegisterAnalyzer called twice for custom", func() {
+		sqlcheck.RegisterAnalyzer("custom", func(*schemahcl.Resource) (sqlcheck.Analyzer, error) { return nil, nil })
+	})
+	require.PanicsWithValue(t, "sqlcheck: RegisterAnalyzer constructor is nil", func() {
+		sqlcheck.RegisterAnalyzer("nil", nil)
+	})
+
+	az, err := sqlcheck.CustomAnalyzers(nil)
+	require.NoError(t, err)
+	require.Empty(t, az)
+	az, err = sqlcheck.CustomAnalyzers(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "destructive"},
+			{Type: "analyzer", Name: "custom"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, az, 1)
+	require.Equal(t, []string{"custom"}, names)
+
+	_, err = sqlcheck.CustomAnalyzers(&schemahcl.Resource{
+		Children: []*schemahcl.Resource{
+			{Type: "analyzer", Name: "unknown"},
+		},
+	})
+	require.EqualError(t, err, `sql/sqlcheck: analyzer "unknown" was not registered`)
+}
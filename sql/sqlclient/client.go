@@ -7,10 +7,12 @@ package sqlclient
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"regexp"
 	"sync"
 
 	"ariga.io/atlas/schemahcl"
@@ -120,6 +122,15 @@ func (c *Client) Tx(ctx context.Context, opts *sql.TxOptions) (*TxClient, error)
 	return tc, nil
 }
 
+// OpenDriver opens a new migrate.Driver of the client dialect that
+// executes its statements using the given schema.ExecQuerier.
+func (c *Client) OpenDriver(db schema.ExecQuerier) (migrate.Driver, error) {
+	if c.openDriver == nil {
+		return nil, errors.New("sql/sqlclient: unexpected driver opener: <nil>")
+	}
+	return c.openDriver(db)
+}
+
 // Commit the transaction.
 func (c *TxClient) Commit() error {
 	return errors.Join(c.beforeCommit(), c.Tx.Commit())
@@ -274,6 +285,57 @@ func ParseURL(s string) (*url.URL, error) {
 	return u, nil
 }
 
+// redacted is the value used for masking credentials.
+const redacted = "xxxxx"
+
+var (
+	// reURLPassword matches the password part of URLs userinfo (e.g., "scheme://user:pass@").
+	reURLPassword = regexp.MustCompile(`(\b[a-zA-Z][\w+.-]*://[^/\s:@]*):[^/\s@]+@`)
+	// reSecretParam matches secret parameters of URLs and DSNs (e.g., "password=pass").
+	reSecretParam = regexp.MustCompile(`(?i)\b(password|passwd|pwd|sslpassword|secret|token|auth_?token|access_?token|api_?key)=[^&\s"';]+`)
+)
+
+// Redact masks the credentials of connection URLs and DSNs found in the given text,
+// such as URL passwords or secret parameters. It should be used for printing errors
+// and logs that may echo the connection strings used to open clients.
+func Redact(s string) string {
+	s = reURLPassword.ReplaceAllString(s, "${1}:"+redacted+"@")
+	return reSecretParam.ReplaceAllString(s, "${1}="+redacted)
+}
+
+// RedactURL is like url.URL.Redacted, but also masks the secret query parameters of the URL.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	return redactURL(u).String()
+}
+
+// redactURL returns a copy of the URL with its credentials masked.
+func redactURL(u *url.URL) *url.URL {
+	ru := *u
+	if _, has := ru.User.Password(); has {
+		ru.User = url.UserPassword(ru.User.Username(), redacted)
+	}
+	ru.RawQuery = reSecretParam.ReplaceAllString(ru.RawQuery, "${1}="+redacted)
+	return &ru
+}
+
+// Redacted returns the URL string with its credentials masked. See RedactURL for details.
+func (u *URL) Redacted() string {
+	return RedactURL(u.URL)
+}
+
+// MarshalJSON implements json.Marshaler. The URL credentials
+// are masked to avoid leaking them in reports.
+func (u URL) MarshalJSON() ([]byte, error) {
+	type redactedURL URL
+	if u.URL != nil {
+		u.URL = redactURL(u.URL)
+	}
+	return json.Marshal(redactedURL(u))
+}
+
 var drivers sync.Map
 
 type (
@@ -347,6 +409,20 @@ func OpenURL(ctx context.Context, u *url.URL, opts ...OpenOption) (*Client, erro
 	return client, nil
 }
 
+// ChangeSchema returns a copy of the given URL that is bound to the given schema.
+// If the registered driver does not support this, ErrUnsupported is returned instead.
+func ChangeSchema(u *url.URL, s string) (*url.URL, error) {
+	v, ok := drivers.Load(u.Scheme)
+	if !ok {
+		return nil, fmt.Errorf("sql/sqlclient: unknown driver %q. See: https://atlasgo.io/url", u.Scheme)
+	}
+	sc, ok := v.(*driver).parser.(SchemaChanger)
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	return sc.ChangeSchema(u, s), nil
+}
+
 // OpenSchema opens the connection to the given schema.
 // If the registered driver does not support this, ErrUnsupported is returned instead.
 func OpenSchema(s string) OpenOption {
@@ -23,9 +23,26 @@ var DefaultDiff schema.Differ = &sqlx.Diff{DiffDriver: &diff{}}
 type diff struct{}
 
 // SchemaAttrDiff returns a changeset for migrating schema attributes from one state to the other.
-func (*diff) SchemaAttrDiff(_, _ *schema.Schema) []schema.Change {
-	// No special schema attribute diffing for SQLite.
-	return nil
+func (*diff) SchemaAttrDiff(from, to *schema.Schema) []schema.Change {
+	var (
+		fromV, toV UserVersion
+		changes    []schema.Change
+	)
+	// The user_version is changed only if it is defined in the desired
+	// state, as it is commonly managed by the application itself.
+	switch fromHas, toHas := sqlx.Has(from.Attrs, &fromV), sqlx.Has(to.Attrs, &toV); {
+	case !toHas || fromV.V == toV.V:
+	case !fromHas:
+		changes = append(changes, &schema.AddAttr{
+			A: &toV,
+		})
+	default:
+		changes = append(changes, &schema.ModifyAttr{
+			From: &fromV,
+			To:   &toV,
+		})
+	}
+	return changes
 }
 
 // RealmObjectDiff returns a changeset for migrating realm (database) objects
@@ -346,6 +346,51 @@ func TestDiff_SchemaDiff(t *testing.T) {
 	}, changes)
 }
 
+func TestDiff_Checksums(t *testing.T) {
+	from := schema.New("main").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int")),
+	)
+	to := schema.New("main").AddTables(
+		schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "int"), schema.NewIntColumn("age", "int")),
+	)
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+
+	// Tables with identical checksums, computed on inspection, are not compared.
+	from.Tables[0].AddAttrs(&schema.Checksum{V: "c1"})
+	to.Tables[0].AddAttrs(&schema.Checksum{V: "c1"})
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// Checksums of tables that were not inspected are never computed.
+	to.Tables[0].Attrs = nil
+	changes, err = DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+}
+
+func TestDiff_UserVersion(t *testing.T) {
+	from, to := schema.New("main").AddAttrs(&UserVersion{V: 1}), schema.New("main").AddAttrs(&UserVersion{V: 2})
+	changes, err := DefaultDiff.SchemaDiff(from, to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifySchema{S: to, Changes: []schema.Change{&schema.ModifyAttr{From: &UserVersion{V: 1}, To: &UserVersion{V: 2}}}},
+	}, changes)
+
+	changes, err = DefaultDiff.SchemaDiff(schema.New("main"), to)
+	require.NoError(t, err)
+	require.Equal(t, []schema.Change{
+		&schema.ModifySchema{S: to, Changes: []schema.Change{&schema.AddAttr{A: &UserVersion{V: 2}}}},
+	}, changes)
+
+	// Undefined in the desired state.
+	changes, err = DefaultDiff.SchemaDiff(from, schema.New("main"))
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
 func TestDefaultDiff(t *testing.T) {
 	changes, err := DefaultDiff.SchemaDiff(
 		schema.New("main").
@@ -358,3 +403,30 @@ func TestDefaultDiff(t *testing.T) {
 	require.Len(t, changes, 1)
 	require.IsType(t, &schema.DropTable{}, changes[0])
 }
+
+func TestDiff_DiffIgnore(t *testing.T) {
+	from := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int").SetDefault(&schema.Literal{V: "1"}),
+			schema.NewIntColumn("c", "int").SetDefault(&schema.Literal{V: "1"}),
+		)
+	to := schema.NewTable("users").
+		AddColumns(
+			schema.NewIntColumn("id", "int").
+				SetDefault(&schema.Literal{V: "2"}).
+				AddAttrs(&schema.DiffIgnore{Changes: schema.ChangeDefault}),
+			schema.NewNullIntColumn("c", "int").
+				SetDefault(&schema.Literal{V: "2"}).
+				AddAttrs(&schema.DiffIgnore{Changes: schema.ChangeDefault}),
+		)
+	changes, err := DefaultDiff.TableDiff(from, to)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	m, ok := changes[0].(*schema.ModifyColumn)
+	require.True(t, ok)
+	require.Equal(t, "c", m.To.Name)
+	require.Equal(t, schema.ChangeNull, m.Change)
+	require.True(t, m.To.Type.Null)
+	require.Equal(t, &schema.Literal{V: "1"}, m.To.Default, "ignored default is kept from the current state")
+	require.Equal(t, &schema.Literal{V: "2"}, to.Columns[1].Default, "desired state is not modified")
+}
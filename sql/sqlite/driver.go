@@ -7,6 +7,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
 	"hash/adler32"
@@ -21,6 +22,7 @@ import (
 	"ariga.io/atlas/sql/migrate"
 	"ariga.io/atlas/sql/schema"
 	"ariga.io/atlas/sql/sqlclient"
+	"ariga.io/atlas/sql/types"
 )
 
 type (
@@ -44,6 +46,7 @@ var _ interface {
 	migrate.Snapshoter
 	migrate.StmtScanner
 	migrate.CleanChecker
+	migrate.StatsRefresher
 	schema.TypeParseFormatter
 } = (*Driver)(nil)
 
@@ -74,13 +77,20 @@ func init() {
 			return &sqlclient.URL{URL: u, DSN: dsn, Schema: mainFile}
 		})),
 	)
+	types.Register(DriverName, (*Driver)(nil))
 }
 
 type urlparse struct{}
 
 // ParseURL implements the sqlclient.URLParser interface.
 func (urlparse) ParseURL(u *url.URL) *sqlclient.URL {
-	uc := &sqlclient.URL{URL: u, DSN: strings.TrimPrefix(u.String(), u.Scheme+"://"), Schema: mainFile}
+	dsn := *u
+	// Pragmas are executed by Atlas and not passed to the underlying driver.
+	if q := dsn.Query(); q.Has(pragmaParam) {
+		q.Del(pragmaParam)
+		dsn.RawQuery = q.Encode()
+	}
+	uc := &sqlclient.URL{URL: u, DSN: strings.TrimPrefix(dsn.String(), u.Scheme+"://"), Schema: mainFile}
 	if mode := u.Query().Get("mode"); mode == "memory" {
 		// The "file:" prefix is mandatory for memory modes.
 		uc.DSN = "file:" + uc.DSN
@@ -90,10 +100,21 @@ func (urlparse) ParseURL(u *url.URL) *sqlclient.URL {
 
 func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
 	ur := urlparse{}.ParseURL(u)
+	pragmas, err := parsePragmas(u.Query()[pragmaParam])
+	if err != nil {
+		return nil, err
+	}
 	db, err := sql.Open(DriverName, ur.DSN)
 	if err != nil {
 		return nil, err
 	}
+	if len(pragmas) > 0 {
+		drv := db.Driver()
+		if err := db.Close(); err != nil {
+			return nil, err
+		}
+		db = sql.OpenDB(&pragmaConnector{dsn: ur.DSN, drv: drv, pragmas: pragmas})
+	}
 	drv, err := Open(db)
 	if err != nil {
 		if cerr := db.Close(); cerr != nil {
@@ -112,6 +133,83 @@ func opener(_ context.Context, u *url.URL) (*sqlclient.Client, error) {
 	}, nil
 }
 
+// pragmaParam is the URL parameter used for setting pragmas on connection open.
+// For example: "sqlite://file.db?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)".
+const pragmaParam = "_pragma"
+
+// parsePragmas parses the given "name(value)" pragmas into PRAGMA statements.
+func parsePragmas(params []string) ([]string, error) {
+	stmts := make([]string, 0, len(params))
+	for _, p := range params {
+		name, value, ok := strings.Cut(p, "(")
+		if !ok || !strings.HasSuffix(value, ")") || !validPragma(name) || strings.Contains(value, ";") {
+			return nil, fmt.Errorf("sqlite: invalid %s parameter %q, expected format: name(value)", pragmaParam, p)
+		}
+		stmts = append(stmts, fmt.Sprintf("PRAGMA %s = %s", name, strings.TrimSuffix(value, ")")))
+	}
+	return stmts, nil
+}
+
+// validPragma reports if the given string is a valid pragma name.
+func validPragma(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// pragmaConnector is a driver.Connector that executes the configured pragmas
+// on every new connection, as some of them (e.g., foreign_keys) are not persisted
+// in the database file and apply only to the connection they were executed on.
+type pragmaConnector struct {
+	dsn     string
+	drv     driver.Driver
+	pragmas []string
+}
+
+// Connect implements the driver.Connector interface.
+func (c *pragmaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.drv.Open(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range c.pragmas {
+		if err := execConn(ctx, conn, p); err != nil {
+			if cerr := conn.Close(); cerr != nil {
+				err = fmt.Errorf("%w: %v", err, cerr)
+			}
+			return nil, fmt.Errorf("sqlite: executing %q: %w", p, err)
+		}
+	}
+	return conn, nil
+}
+
+// Driver implements the driver.Connector interface.
+func (c *pragmaConnector) Driver() driver.Driver {
+	return c.drv
+}
+
+// execConn executes the given statement on the driver connection.
+func execConn(ctx context.Context, conn driver.Conn, stmt string) error {
+	if e, ok := conn.(driver.ExecerContext); ok {
+		if _, err := e.ExecContext(ctx, stmt, nil); !errors.Is(err, driver.ErrSkip) {
+			return err
+		}
+	}
+	st, err := conn.Prepare(stmt)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	_, err = st.Exec(nil) //nolint:staticcheck
+	return err
+}
+
 // Open opens a new SQLite driver.
 func Open(db schema.ExecQuerier) (migrate.Driver, error) {
 	c := &conn{ExecQuerier: db}
@@ -132,12 +230,17 @@ func (d *Driver) Snapshot(ctx context.Context) (migrate.RestoreFunc, error) {
 	if !(r == nil || (len(r.Schemas) == 1 && r.Schemas[0].Name == mainFile && len(r.Schemas[0].Tables) == 0)) {
 		return nil, &migrate.NotCleanError{State: r, Reason: fmt.Sprintf("found table %q", r.Schemas[0].Tables[0].Name)}
 	}
+	var v UserVersion
+	if r != nil {
+		sqlx.Has(r.Schemas[0].Attrs, &v)
+	}
 	return func(ctx context.Context) error {
 		for _, stmt := range []string{
 			"PRAGMA writable_schema = 1;",
 			"DELETE FROM sqlite_master WHERE type IN ('table', 'view', 'index', 'trigger');",
 			"PRAGMA writable_schema = 0;",
 			"VACUUM;",
+			fmt.Sprintf("PRAGMA user_version = %d;", v.V),
 		} {
 			if _, err := d.ExecContext(ctx, stmt); err != nil {
 				return err
@@ -166,6 +269,17 @@ func (d *Driver) CheckClean(ctx context.Context, revT *migrate.TableIdent) error
 	return nil
 }
 
+// RefreshStats implements migrate.StatsRefresher.
+func (d *Driver) RefreshStats(ctx context.Context, tables []*schema.Table) error {
+	for _, t := range tables {
+		b := d.StmtBuilder(migrate.PlanOptions{}).P("ANALYZE").Table(t)
+		if _, err := d.ExecContext(ctx, b.String()); err != nil {
+			return fmt.Errorf("sql/sqlite: analyze table %q: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
 // Lock implements the schema.Locker interface.
 func (d *Driver) Lock(_ context.Context, name string, timeout time.Duration) (schema.UnlockFunc, error) {
 	// If the URL was set and the database is a file, use its name in the lock file.
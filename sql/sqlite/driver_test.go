@@ -7,6 +7,8 @@ package sqlite
 import (
 	"context"
 	"database/sql/driver"
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -99,3 +101,16 @@ type mockInspector struct {
 func (m *mockInspector) InspectRealm(context.Context, *schema.InspectRealmOption) (*schema.Realm, error) {
 	return m.realm, nil
 }
+
+func TestParsePragmas(t *testing.T) {
+	stmts, err := parsePragmas([]string{"foreign_keys(1)", "journal_mode(WAL)"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"PRAGMA foreign_keys = 1", "PRAGMA journal_mode = WAL"}, stmts)
+	for _, p := range []string{"foreign_keys", "foreign_keys(1", "(1)", "user-version(1)", "foreign_keys(1); DROP TABLE t"} {
+		_, err = parsePragmas([]string{p})
+		require.EqualError(t, err, fmt.Sprintf("sqlite: invalid _pragma parameter %q, expected format: name(value)", p))
+	}
+	u, err := url.Parse("sqlite://file.db?_fk=1&_pragma=journal_mode(WAL)")
+	require.NoError(t, err)
+	require.Equal(t, "file.db?_fk=1", urlparse{}.ParseURL(u).DSN)
+}
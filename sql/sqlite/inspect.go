@@ -62,7 +62,7 @@ func (i *inspect) InspectRealm(ctx context.Context, opts *schema.InspectRealmOpt
 			return nil, err
 		}
 	}
-	return schema.ExcludeRealm(r, opts.Exclude)
+	return sqlx.RealmChecksums(schema.ExcludeRealm(r, opts.Exclude))
 }
 
 // InspectSchema returns schema descriptions of the tables in the given schema.
@@ -112,7 +112,7 @@ func (i *inspect) InspectSchema(ctx context.Context, name string, opts *schema.I
 			return nil, err
 		}
 	}
-	return schema.ExcludeSchema(r.Schemas[0], opts.Exclude)
+	return sqlx.SchemaChecksums(schema.ExcludeSchema(r.Schemas[0], opts.Exclude))
 }
 
 func (i *inspect) inspectTable(ctx context.Context, t *schema.Table) error {
@@ -154,6 +154,11 @@ func (i *inspect) addColumn(t *schema.Table, rows *sql.Rows) error {
 	if err = rows.Scan(&name, &typ, &nullable, &defaults, &primary, &hidden); err != nil {
 		return err
 	}
+	// Older versions of SQLite include the "GENERATED ALWAYS" clause in
+	// the type of generated columns. For example, "INT GENERATED ALWAYS".
+	if hidden.Int64 >= 2 {
+		typ.String = reGenType.ReplaceAllString(typ.String, "")
+	}
 	c := &schema.Column{
 		Name: name.String,
 		Type: &schema.ColumnType{
@@ -456,9 +461,33 @@ func (i *inspect) databases(ctx context.Context, opts *schema.InspectRealmOption
 			Attrs: []schema.Attr{&File{Name: file.String}},
 		})
 	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		if err := i.userVersion(ctx, s); err != nil {
+			return nil, err
+		}
+	}
 	return schemas, nil
 }
 
+// userVersion queries and appends the user_version of the given database, if it was set.
+func (i *inspect) userVersion(ctx context.Context, s *schema.Schema) error {
+	rows, err := i.QueryContext(ctx, fmt.Sprintf(userVersionQuery, s.Name))
+	if err != nil {
+		return fmt.Errorf("sqlite: querying %q user_version: %w", s.Name, err)
+	}
+	var v sql.NullInt64
+	if err := sqlx.ScanOne(rows, &v); err != nil {
+		return fmt.Errorf("sqlite: scanning %q user_version: %w", s.Name, err)
+	}
+	if v.Int64 != 0 {
+		s.AddAttrs(&UserVersion{V: int(v.Int64)})
+	}
+	return nil
+}
+
 type (
 	// File describes a database file.
 	File struct {
@@ -466,6 +495,13 @@ type (
 		Name string
 	}
 
+	// UserVersion describes the `user_version` pragma of a database file.
+	// See: https://www.sqlite.org/pragma.html#pragma_user_version
+	UserVersion struct {
+		schema.Attr
+		V int
+	}
+
 	// CreateStmt describes the SQL statement used to create a resource.
 	CreateStmt struct {
 		schema.Attr
@@ -598,7 +634,10 @@ func setGenExpr(t *schema.Table, c *schema.Column, f int64) error {
 	if !sqlx.Has(t.Attrs, &s) {
 		return fmt.Errorf("missing CREATE statement for table: %q", t.Name)
 	}
-	re, err := regexp.Compile(fmt.Sprintf("(?:[(,]\\s*)[\"`]*(%s)[\"`]*[^,]*(?i:GENERATED\\s+ALWAYS)*\\s*(?i:AS){1}\\s*\\(", c.Name))
+	// The column name is matched as a whole identifier, and its definition may contain
+	// parenthesized commas (e.g., DECIMAL(10,2)) before the generation expression.
+	name := regexp.QuoteMeta(c.Name)
+	re, err := regexp.Compile(fmt.Sprintf("(?:[(,]\\s*)(?:[\"`\\[]%[1]s[\"`\\]]|(?i:%[1]s)\\s)(?:[^,(]|\\([^)]*\\))*?\\b(?i:AS)\\s*\\(", name))
 	if err != nil {
 		return err
 	}
@@ -624,6 +663,8 @@ var (
 	reFKC   = regexp.MustCompile("(?i)(?:[(,]\\s*)[\"`]*(\\w+)[\"`]*[^,]*\\s+CONSTRAINT\\s+[\"`]*(\\w+)[\"`]*\\s+REFERENCES\\s+[\"`]*(\\w+)[\"`]*\\s*\\(([,\"` \\w]+)\\)")
 	reFKT   = regexp.MustCompile("(?i)CONSTRAINT\\s+[\"`]*(\\w+)[\"`]*\\s+FOREIGN\\s+KEY\\s*\\(([,\"` \\w]+)\\)\\s+REFERENCES\\s+[\"`]*(\\w+)[\"`]*\\s*\\(([,\"` \\w]+)\\)")
 	reCheck = regexp.MustCompile("(?i)(?:CONSTRAINT\\s+[\"`]?(\\w+)[\"`]?\\s+)?CHECK\\s*\\(")
+	// reGenType matches the generation clause in the reported type of generated columns.
+	reGenType = regexp.MustCompile("(?i)\\s*GENERATED\\s+ALWAYS\\s*$")
 )
 
 // fillConstName fills foreign-key constrain names from CREATE TABLE statement.
@@ -744,6 +785,8 @@ const (
 	// Query to list attached database files.
 	databasesQuery     = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` <> 'temp'"
 	databasesQueryArgs = "SELECT `name`, `file` FROM pragma_database_list() WHERE `name` IN (%s)"
+	// Query to get the user_version of a database file.
+	userVersionQuery = "PRAGMA `%s`.user_version"
 	// Query to list database tables.
 	tablesQuery = `
 SELECT
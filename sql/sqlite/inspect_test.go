@@ -256,6 +256,12 @@ CREATE TABLE users(
  name |   file
 ------+-----------
  main |
+`))
+				m.ExpectQuery(sqltest.Escape(fmt.Sprintf(userVersionQuery, "main"))).
+					WillReturnRows(sqltest.Rows(`
+ user_version
+--------------
+ 0
 `))
 				rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
 				rows.AddRow("users", "CREATE TABLE users(id INTEGER PRIMARY KEY) without rowid, strict", 1, 1)
@@ -292,7 +298,13 @@ CREATE TABLE users(
 				Mode:   ^schema.InspectViews,
 			})
 			require.NoError(t, err)
-			tt.expect(require.New(t), s.Tables[0], err)
+			// The checksum of the table is cached on inspection.
+			t1 := s.Tables[0]
+			c, ok := t1.Attrs[len(t1.Attrs)-1].(*schema.Checksum)
+			require.True(t, ok)
+			t1.Attrs = t1.Attrs[:len(t1.Attrs)-1]
+			require.Equal(t, schema.ChecksumOf(t1), c.V)
+			tt.expect(require.New(t), t1, err)
 		})
 	}
 }
@@ -485,6 +497,7 @@ func TestRegex_Checks(t *testing.T) {
 		})
 		require.NoError(t, err)
 		table := s.Tables[0]
+		table.Attrs = schema.RemoveAttr[*schema.Checksum](table.Attrs)
 		require.Equal(t, len(table.Attrs[1:]), len(tt.checks))
 		for i := range tt.checks {
 			require.Equal(t, tt.checks[i], table.Attrs[i+1])
@@ -495,6 +508,7 @@ func TestRegex_Checks(t *testing.T) {
 func TestRegex_GeneratedExpr(t *testing.T) {
 	tests := []struct {
 		input  string
+		typ    string
 		column *schema.Column
 	}{
 		{
@@ -527,6 +541,22 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 			column: schema.NewColumn("c0").
 				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(('a', 9) < ('b', c1))", Type: "VIRTUAL"}),
 		},
+		{
+			input: "CREATE TABLE t1(ab INT AS (1) STORED, a DECIMAL(10,2) GENERATED ALWAYS AS (a * 10));",
+			column: schema.NewColumn("a").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a * 10)", Type: "VIRTUAL"}),
+		},
+		{
+			input: "CREATE TABLE t1(a INT, \"c.d\" INT AS (a+1), `e` TEXT AS (a||'x'));",
+			column: schema.NewColumn("c.d").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a+1)", Type: "VIRTUAL"}),
+		},
+		{
+			input: "CREATE TABLE t1(a INT, b INT GENERATED ALWAYS AS (a+1));",
+			typ:   "INT GENERATED ALWAYS",
+			column: schema.NewColumn("b").
+				SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(a+1)", Type: "VIRTUAL"}),
+		},
 	}
 	for _, tt := range tests {
 		const name = "users"
@@ -534,12 +564,12 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 		require.NoError(t, err)
 		mk := mock{m}
 		mk.tableExists(name, true, tt.input)
+		if tt.typ == "" {
+			tt.typ = "int"
+		}
 		m.ExpectQuery(sqltest.Escape(fmt.Sprintf(columnsQuery, name))).
-			WillReturnRows(sqltest.Rows(fmt.Sprintf(`
- name |   type       | nullable | dflt_value  | primary  | hidden
-------+--------------+----------+ ------------+----------+----------
- %s   | int           |  1      |     a       |  0       |  2
-`, tt.column.Name)))
+			WillReturnRows(sqlmock.NewRows([]string{"name", "type", "nullable", "dflt_value", "primary", "hidden"}).
+				AddRow(tt.column.Name, tt.typ, 1, "a", 0, 2))
 		mk.noIndexes(name)
 		mk.noFKs(name)
 		drv, err := Open(db)
@@ -550,6 +580,7 @@ func TestRegex_GeneratedExpr(t *testing.T) {
 		})
 		require.NoError(t, err)
 		require.Equal(t, tt.column.Attrs, s.Tables[0].Columns[0].Attrs)
+		require.Equal(t, &schema.IntegerType{T: "int"}, s.Tables[0].Columns[0].Type.Type)
 	}
 }
 
@@ -564,6 +595,12 @@ func (m mock) tableExists(table string, exists bool, stmt ...string) {
  name |   file    
 ------+-----------
  main |   
+`))
+	m.ExpectQuery(sqltest.Escape(fmt.Sprintf(userVersionQuery, "main"))).
+		WillReturnRows(sqltest.Rows(`
+ user_version
+--------------
+ 0
 `))
 	rows := sqlmock.NewRows([]string{"name", "sql", "wr", "strict"})
 	if exists {
@@ -82,6 +82,8 @@ type state struct {
 func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
 	for _, c := range changes {
 		switch c := c.(type) {
+		case *schema.ModifySchema:
+			err = s.modifySchema(c)
 		case *schema.AddTable:
 			err = s.addTable(ctx, c)
 		case *schema.DropTable:
@@ -112,6 +114,33 @@ func (s *state) plan(ctx context.Context, changes []schema.Change) (err error) {
 	return nil
 }
 
+// modifySchema builds and executes the queries for modifying schema (database) attributes.
+func (s *state) modifySchema(modify *schema.ModifySchema) error {
+	for _, change := range modify.Changes {
+		var from, to schema.Attr
+		switch change := change.(type) {
+		case *schema.AddAttr:
+			from, to = &UserVersion{}, change.A
+		case *schema.ModifyAttr:
+			from, to = change.From, change.To
+		default:
+			return fmt.Errorf("unsupported schema change %T", change)
+		}
+		fromV, ok1 := from.(*UserVersion)
+		toV, ok2 := to.(*UserVersion)
+		if !ok1 || !ok2 {
+			return fmt.Errorf("unsupported schema attribute change from %T to %T", from, to)
+		}
+		s.append(&migrate.Change{
+			Cmd:     fmt.Sprintf("PRAGMA user_version = %d", toV.V),
+			Source:  modify,
+			Reverse: fmt.Sprintf("PRAGMA user_version = %d", fromV.V),
+			Comment: fmt.Sprintf("set %q user_version", modify.S.Name),
+		})
+	}
+	return nil
+}
+
 // addTable builds and executes the query for creating a table in a schema.
 func (s *state) addTable(ctx context.Context, add *schema.AddTable) error {
 	var (
@@ -287,6 +287,49 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		// Modify generation expressions.
+		{
+			changes: []schema.Change{
+				func() schema.Change {
+					users := schema.NewTable("users").
+						AddColumns(
+							schema.NewIntColumn("id", "bigint"),
+							schema.NewIntColumn("total", "bigint").
+								SetGeneratedExpr(&schema.GeneratedExpr{Expr: "id*2", Type: "STORED"}),
+							schema.NewIntColumn("legacy", "bigint"),
+						)
+					return &schema.ModifyTable{
+						T: users,
+						Changes: []schema.Change{
+							&schema.ModifyColumn{
+								From: schema.NewIntColumn("total", "bigint").
+									SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(id*3)", Type: "STORED"}),
+								To:     users.Columns[1],
+								Change: schema.ChangeGenerated,
+							},
+							// Values of columns that are no longer generated are copied.
+							&schema.ModifyColumn{
+								From: schema.NewIntColumn("legacy", "bigint").
+									SetGeneratedExpr(&schema.GeneratedExpr{Expr: "(id)"}),
+								To:     users.Columns[2],
+								Change: schema.ChangeGenerated,
+							},
+						},
+					}
+				}(),
+			},
+			plan: &migrate.Plan{
+				Transactional: true,
+				Changes: []*migrate.Change{
+					{Cmd: "PRAGMA foreign_keys = off"},
+					{Cmd: "CREATE TABLE `new_users` (`id` bigint NOT NULL, `total` bigint NOT NULL AS (id*2) STORED, `legacy` bigint NOT NULL)", Reverse: "DROP TABLE `new_users`"},
+					{Cmd: "INSERT INTO `new_users` (`id`, `legacy`) SELECT `id`, `legacy` FROM `users`"},
+					{Cmd: "DROP TABLE `users`"},
+					{Cmd: "ALTER TABLE `new_users` RENAME TO `users`"},
+					{Cmd: "PRAGMA foreign_keys = on"},
+				},
+			},
+		},
 		{
 			changes: []schema.Change{
 				func() schema.Change {
@@ -478,6 +521,36 @@ func TestPlanChanges(t *testing.T) {
 				},
 			},
 		},
+		{
+			changes: []schema.Change{
+				&schema.ModifySchema{
+					S: schema.New("main"),
+					Changes: []schema.Change{
+						&schema.ModifyAttr{From: &UserVersion{V: 1}, To: &UserVersion{V: 2}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes:       []*migrate.Change{{Cmd: "PRAGMA user_version = 2", Reverse: "PRAGMA user_version = 1"}},
+			},
+		},
+		{
+			changes: []schema.Change{
+				&schema.ModifySchema{
+					S: schema.New("main"),
+					Changes: []schema.Change{
+						&schema.AddAttr{A: &UserVersion{V: 3}},
+					},
+				},
+			},
+			plan: &migrate.Plan{
+				Reversible:    true,
+				Transactional: true,
+				Changes:       []*migrate.Change{{Cmd: "PRAGMA user_version = 3", Reverse: "PRAGMA user_version = 0"}},
+			},
+		},
 	}
 	for i, tt := range tests {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
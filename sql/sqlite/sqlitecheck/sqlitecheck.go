@@ -15,6 +15,7 @@ import (
 	"ariga.io/atlas/sql/sqlcheck"
 	"ariga.io/atlas/sql/sqlcheck/condrop"
 	"ariga.io/atlas/sql/sqlcheck/datadepend"
+	"ariga.io/atlas/sql/sqlcheck/design"
 	"ariga.io/atlas/sql/sqlcheck/destructive"
 	"ariga.io/atlas/sql/sqlcheck/incompatible"
 	"ariga.io/atlas/sql/sqlcheck/naming"
@@ -78,6 +79,10 @@ func init() {
 		if err != nil {
 			return nil, err
 		}
+		sd, err := design.New(r)
+		if err != nil {
+			return nil, err
+		}
 		return []sqlcheck.Analyzer{
 			sqlcheck.AnalyzerFunc(func(_ context.Context, p *sqlcheck.Pass) error {
 				var changes []*sqlcheck.Change
@@ -120,7 +125,7 @@ func init() {
 				p.File.Changes = changes
 				return nil
 			}),
-			ds, dd, cd, bc, nm,
+			ds, dd, cd, bc, nm, sd,
 		}, nil
 	})
 }
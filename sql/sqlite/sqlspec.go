@@ -50,6 +50,15 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		); err != nil {
 			return fmt.Errorf("sqlite: failed converting to *schema.Realm: %w", err)
 		}
+		for _, spec := range d.Schemas {
+			s, ok := v.Schema(spec.Name)
+			if !ok {
+				return fmt.Errorf("could not find schema: %q", spec.Name)
+			}
+			if err := convertUserVersion(spec, &s.Attrs); err != nil {
+				return err
+			}
+		}
 	case *schema.Schema:
 		var d doc
 		if err := c.State.EvalOptions(p, &d, opts); err != nil {
@@ -65,6 +74,9 @@ func (c *Codec) EvalOptions(p *hclparse.Parser, v any, opts *schemahcl.EvalOptio
 		); err != nil {
 			return err
 		}
+		if err := convertUserVersion(d.Schemas[0], &r.Schemas[0].Attrs); err != nil {
+			return err
+		}
 		*v = *r.Schemas[0]
 	case schema.Schema, schema.Realm:
 		return fmt.Errorf("sqlite: Eval expects a pointer: received %[1]T, expected *%[1]T", v)
@@ -111,6 +123,20 @@ func convertTable(spec *sqlspec.Table, parent *schema.Schema) (*schema.Table, er
 	return t, nil
 }
 
+// convertUserVersion converts the schema user_version attribute.
+func convertUserVersion(spec *sqlspec.Schema, attrs *[]schema.Attr) error {
+	attr, ok := spec.Attr("user_version")
+	if !ok {
+		return nil
+	}
+	v, err := attr.Int()
+	if err != nil {
+		return err
+	}
+	*attrs = append(*attrs, &UserVersion{V: v})
+	return nil
+}
+
 // convertView converts a sqlspec.View to a schema.View.
 func convertView(spec *sqlspec.View, parent *schema.Schema) (*schema.View, error) {
 	v, err := specutil.View(
@@ -172,10 +198,17 @@ func convertColumnType(spec *sqlspec.Column) (schema.Type, error) {
 
 // schemaSpec converts from a concrete SQLite schema to Atlas specification.
 func schemaSpec(s *schema.Schema) (*specutil.SchemaSpec, error) {
-	return specutil.FromSchema(s, &specutil.SchemaFuncs{
+	spec, err := specutil.FromSchema(s, &specutil.SchemaFuncs{
 		Table: tableSpec,
 		View:  viewSpec,
 	})
+	if err != nil {
+		return nil, err
+	}
+	if v := (UserVersion{}); sqlx.Has(s.Attrs, &v) {
+		spec.Schema.Extra.Attrs = append(spec.Schema.Extra.Attrs, schemahcl.IntAttr("user_version", v.V))
+	}
+	return spec, nil
 }
 
 // tableSpec converts from a concrete SQLite sqlspec.Table to a schema.Table.
@@ -303,6 +336,7 @@ var (
 			schemahcl.WithScopedEnums("table.column.as.type", stored, virtual),
 			schemahcl.WithScopedEnums("table.foreign_key.on_update", specutil.ReferenceVars...),
 			schemahcl.WithScopedEnums("table.foreign_key.on_delete", specutil.ReferenceVars...),
+			schemahcl.WithScopedEnums("table.column.diff.ignore", specutil.DiffIgnoreVars...),
 		)...),
 	}
 	// MarshalHCL marshals v into an Atlas HCL DDL document.
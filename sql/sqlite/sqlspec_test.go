@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"ariga.io/atlas/sql/internal/spectest"
+	"ariga.io/atlas/sql/internal/sqlx"
 	"ariga.io/atlas/sql/schema"
 	"github.com/stretchr/testify/require"
 )
@@ -523,6 +524,113 @@ schema "test" {
 	require.EqualValues(t, expected, string(buf))
 }
 
+func TestMarshalSpec_UserVersion(t *testing.T) {
+	s := schema.New("main").AddAttrs(&UserVersion{V: 3})
+	buf, err := MarshalHCL(s)
+	require.NoError(t, err)
+	const expected = `schema "main" {
+  user_version = 3
+}
+`
+	require.EqualValues(t, expected, string(buf))
+
+	var got schema.Schema
+	require.NoError(t, EvalHCLBytes(buf, &got, nil))
+	var v UserVersion
+	require.True(t, sqlx.Has(got.Attrs, &v))
+	require.Equal(t, 3, v.V)
+}
+
 func TestInputVars(t *testing.T) {
 	spectest.TestInputVars(t, EvalHCL)
 }
+
+func TestMarshalSpec_DiffIgnore(t *testing.T) {
+	const doc = `table "users" {
+  schema = schema.main
+  column "id" {
+    null = false
+    type = int
+  }
+  column "created_at" {
+    null    = false
+    type    = datetime
+    default = sql("CURRENT_TIMESTAMP")
+    diff {
+      ignore = [default, comment]
+    }
+  }
+}
+schema "main" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(doc), &s, nil))
+	c, ok := s.Tables[0].Column("created_at")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&schema.DiffIgnore{Changes: schema.ChangeDefault | schema.ChangeComment}}, c.Attrs)
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, doc, string(buf))
+
+	err = EvalHCLBytes([]byte(`table "users" {
+  schema = schema.main
+  column "id" {
+    type = int
+    diff {
+      ignore = ["charset"]
+    }
+  }
+}
+schema "main" {
+}`), &s, nil)
+	require.EqualError(t, err, `cannot convert table "users": column "id": unexpected diff.ignore value "charset", expected one of: default, type, nullable, comment`)
+}
+
+func TestMarshalSpec_Labels(t *testing.T) {
+	const doc = `table "users" {
+  schema = schema.main
+  labels = {
+    owner = "payments"
+    tier  = "1"
+  }
+  column "id" {
+    null = false
+    type = int
+  }
+  column "email" {
+    null = false
+    type = text
+    labels = {
+      pii = "true"
+    }
+  }
+}
+schema "main" {
+}
+`
+	var s schema.Schema
+	require.NoError(t, EvalHCLBytes([]byte(doc), &s, nil))
+	require.Equal(t, &schema.Labels{V: map[string]string{"owner": "payments", "tier": "1"}}, schema.LabelsOf(s.Tables[0].Attrs))
+	c, ok := s.Tables[0].Column("email")
+	require.True(t, ok)
+	require.Equal(t, []schema.Attr{&schema.Labels{V: map[string]string{"pii": "true"}}}, c.Attrs)
+	require.True(t, schema.LabelsOf(c.Attrs).Match(map[string]string{"pii": "true"}))
+	require.True(t, schema.LabelsOf(c.Attrs).Match(map[string]string{"pii": ""}))
+	require.False(t, schema.LabelsOf(c.Attrs).Match(map[string]string{"pii": "false"}))
+	require.False(t, schema.LabelsOf(s.Tables[0].Columns[0].Attrs).Match(map[string]string{"pii": ""}))
+	buf, err := MarshalHCL(&s)
+	require.NoError(t, err)
+	require.Equal(t, doc, string(buf))
+
+	err = EvalHCLBytes([]byte(`table "users" {
+  schema = schema.main
+  column "id" {
+    type   = int
+    labels = ["pii"]
+  }
+}
+schema "main" {
+}`), &s, nil)
+	require.EqualError(t, err, `cannot convert table "users": column "id": labels: expect a map of strings, got list of string`)
+}
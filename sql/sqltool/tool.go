@@ -7,6 +7,7 @@ package sqltool
 import (
 	"bufio"
 	"bytes"
+	"encoding/xml"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -425,38 +426,69 @@ func (f FlywayFile) Version() string {
 }
 
 // SetRepeatableVersion iterates over the migration files and assigns repeatable migrations a version number since
-// Atlas does not have the concept of repeatable migrations. Each repeatable migration file gets assigned the version
-// of the preceding migration file (or 0) followed by an 'R'.
+// Atlas does not have the concept of repeatable migrations. Flyway applies repeatable migrations after all versioned
+// migrations, ordered by their description. Hence, each repeatable migration file gets assigned a distinct version
+// that follows the major version of the last versioned migration file (or 0). For example, "V3_1__a.sql",
+// "R__b.sql" and "R__c.sql" are assigned the versions "3_1", "4" and "5".
 func SetRepeatableVersion(ff []migrate.File) {
 	// First find the index of the first repeatable migration file (if any).
-	var (
-		v   string // last versioned migration version
-		idx = func() int {
-			for i, f := range ff {
-				if f.Version() == "" {
-					return i
-				}
-			}
-			return -1
-		}()
-	)
-	switch idx {
-	case -1:
+	idx := slices.IndexFunc(ff, func(f migrate.File) bool {
+		return f.Version() == ""
+	})
+	if idx == -1 {
 		// No repeatable migration does exist.
 		return
-	case 0:
-		// There is no preceding migration. Use Version "0".
-		v = "0"
-	default:
-		v = ff[idx-1].Version()
-	}
-	if v != "" {
-		// Every migration file following the first repeatable found are repeatable as well.
-		for i, f := range ff[idx:] {
-			ff[idx+i] = &FlywayFile{migrate.NewLocalFile(
-				fmt.Sprintf("V%sR__%s", v, f.Desc()),
-				f.Bytes(),
-			)}
+	}
+	// There is no preceding migration in case the
+	// index is 0, and the major version starts at 0.
+	var major int
+	if idx > 0 {
+		major = flywayParseVersion(ff[idx-1].Version())[0]
+	}
+	// Every migration file following the first repeatable found are repeatable as well.
+	for i, f := range ff[idx:] {
+		ff[idx+i] = &FlywayFile{migrate.NewLocalFile(
+			fmt.Sprintf("V%d__%s", major+i+1, f.Desc()),
+			f.Bytes(),
+		)}
+	}
+}
+
+// SetSortableVersion rewrites the versions of the migration files to be sorted lexicographically in the order
+// they are applied by Flyway, since Atlas orders the migration files by their names. The version parts are joined
+// by dots and padded with zeros to the same length. For example, the versions "1", "1_1" and "10" are rewritten to
+// "01.0", "01.1" and "10.0". Note, SetRepeatableVersion should be called first, as unversioned or non-numeric
+// versions are kept as is.
+func SetSortableVersion(ff []migrate.File) {
+	var (
+		widths []int
+		parts  = make([][]string, len(ff))
+	)
+	for i, f := range ff {
+		if parts[i] = flywayVersionParts(f.Version()); len(parts[i]) == 0 {
+			return
+		}
+		for j, p := range parts[i] {
+			if _, err := strconv.Atoi(p); err != nil {
+				return
+			}
+			if j == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[j] = max(widths[j], len(p))
+		}
+	}
+	for i, f := range ff {
+		vs := make([]string, len(widths))
+		for j, w := range widths {
+			p := "0"
+			if j < len(parts[i]) {
+				p = parts[i][j]
+			}
+			vs[j] = strings.Repeat("0", w-len(p)) + p
+		}
+		if v := strings.Join(vs, "."); v != f.Version() {
+			ff[i] = &FlywayFile{migrate.NewLocalFile(fmt.Sprintf("V%s__%s", v, f.Desc()), f.Bytes())}
 		}
 	}
 }
@@ -474,6 +506,53 @@ func NewLiquibaseDir(path string) (*LiquibaseDir, error) {
 	return &LiquibaseDir{d}, nil
 }
 
+// LiquibaseChangelogFileName is the name of the XML changelog generated by the LiquibaseDir.
+const LiquibaseChangelogFileName = "changelog.xml"
+
+// WriteFile implements Dir.WriteFile. Writing a migration file also (re-)generates the XML
+// changelog of the directory, that includes all formatted SQL files in their execution order.
+func (d *LiquibaseDir) WriteFile(name string, b []byte) error {
+	if err := d.LocalDir.WriteFile(name, b); err != nil {
+		return err
+	}
+	if filepath.Ext(name) != ".sql" {
+		return nil
+	}
+	files, err := d.Files()
+	if err != nil {
+		return err
+	}
+	c := liquibaseChangelog{
+		XMLNS:          "http://www.liquibase.org/xml/ns/dbchangelog",
+		XSI:            "http://www.w3.org/2001/XMLSchema-instance",
+		SchemaLocation: "http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd",
+	}
+	for _, f := range files {
+		c.Includes = append(c.Includes, liquibaseInclude{File: f.Name(), Relative: true})
+	}
+	buf, err := xml.MarshalIndent(c, "", "    ")
+	if err != nil {
+		return err
+	}
+	return d.LocalDir.WriteFile(LiquibaseChangelogFileName, append([]byte(xml.Header), append(buf, '\n')...))
+}
+
+type (
+	// liquibaseChangelog is the root element of a Liquibase XML changelog.
+	liquibaseChangelog struct {
+		XMLName        xml.Name           `xml:"databaseChangeLog"`
+		XMLNS          string             `xml:"xmlns,attr"`
+		XSI            string             `xml:"xmlns:xsi,attr"`
+		SchemaLocation string             `xml:"xsi:schemaLocation,attr"`
+		Includes       []liquibaseInclude `xml:"include"`
+	}
+	// liquibaseInclude includes a migration file in the changelog.
+	liquibaseInclude struct {
+		File     string `xml:"file,attr"`
+		Relative bool   `xml:"relativeToChangelogFile,attr"`
+	}
+)
+
 const (
 	none int = iota
 	up
@@ -501,7 +580,7 @@ type flywayFiles struct {
 func (ff *flywayFiles) add(path string) error {
 	switch p := filepath.Base(path)[0]; p {
 	case 'B':
-		if ff.baseline != "" && flywayVersion(path) < flywayVersion(ff.baseline) {
+		if ff.baseline != "" && flywayVersionCompare(flywayVersion(path), flywayVersion(ff.baseline)) < 0 {
 			return nil
 		}
 		ff.baseline = path
@@ -511,7 +590,7 @@ func (ff *flywayFiles) add(path string) error {
 			vs []string
 		)
 		for _, v := range ff.versioned {
-			if v > bv {
+			if flywayVersionCompare(flywayVersion(v), bv) > 0 {
 				vs = append(vs, v)
 			}
 		}
@@ -519,7 +598,7 @@ func (ff *flywayFiles) add(path string) error {
 		return nil
 	case 'V':
 		v := flywayVersion(path)
-		if ff.baseline == "" || flywayVersion(ff.baseline) < v {
+		if ff.baseline == "" || flywayVersionCompare(flywayVersion(ff.baseline), v) < 0 {
 			ff.versioned = append(ff.versioned, path)
 		}
 		return nil
@@ -537,7 +616,10 @@ func (ff *flywayFiles) names() []string {
 		names = append(names, ff.baseline)
 	}
 	flywaySort(ff.versioned)
-	flywaySort(ff.repeatable)
+	// Repeatable migrations are applied in the order of their description.
+	slices.SortStableFunc(ff.repeatable, func(p1, p2 string) int {
+		return strings.Compare(flywayDesc(p1), flywayDesc(p2))
+	})
 	names = append(names, ff.versioned...)
 	names = append(names, ff.repeatable...)
 	return names
@@ -566,17 +648,25 @@ func flywaySort(files []string) {
 }
 
 func flywayVersionCompare(v1, v2 string) int {
-	parse := func(s string) []int {
-		ss := strings.Split(strings.ReplaceAll(s, "_", "."), ".")
-		var ret []int
-		for _, s := range ss {
-			// 0 for non-numeric parts.
-			i, _ := strconv.Atoi(s)
-			ret = append(ret, i)
-		}
-		return ret
+	return slices.Compare(flywayParseVersion(v1), flywayParseVersion(v2))
+}
+
+// flywayParseVersion returns the numeric parts of the version. Non-numeric parts are parsed as 0.
+func flywayParseVersion(v string) []int {
+	ss := strings.Split(strings.ReplaceAll(v, "_", "."), ".")
+	ret := make([]int, 0, len(ss))
+	for _, s := range ss {
+		i, _ := strconv.Atoi(s)
+		ret = append(ret, i)
 	}
-	return slices.Compare(parse(v1), parse(v2))
+	return ret
+}
+
+// flywayVersionParts returns the parts of the version, separated by dots or underscores.
+func flywayVersionParts(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == '.' || r == '_'
+	})
 }
 
 func unexpectedPragmaErr(f migrate.File, line int, pragma string) error {
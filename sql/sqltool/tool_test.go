@@ -385,6 +385,27 @@ func TestChecksum(t *testing.T) {
 				"V11.11.11__.sql",
 			},
 		},
+		{
+			name: "flyway with numeric baseline and repeatable migrations",
+			dir: func() migrate.Dir {
+				fs := fstest.MapFS{
+					"V9__.sql":       &fstest.MapFile{Data: []byte("V9")},
+					"B10__base.sql":  &fstest.MapFile{Data: []byte("B10")},
+					"V10__.sql":      &fstest.MapFile{Data: []byte("V10")},
+					"V11__.sql":      &fstest.MapFile{Data: []byte("V11")},
+					"R__views.sql":   &fstest.MapFile{Data: []byte("R__views")},
+					"R__funcs.sql":   &fstest.MapFile{Data: []byte("R__funcs")},
+					"B2__ignore.sql": &fstest.MapFile{Data: []byte("B2")},
+				}
+				return &sqltool.FlywayDir{&fs}
+			}(),
+			files: []string{
+				"B10__base.sql",
+				"V11__.sql",
+				"R__funcs.sql",
+				"R__views.sql",
+			},
+		},
 		{
 			name: "liquibase",
 			dir: func() migrate.Dir {
@@ -421,6 +442,81 @@ func TestChecksum(t *testing.T) {
 	}
 }
 
+func TestSetVersion(t *testing.T) {
+	fs := fstest.MapFS{
+		"V1__initial.sql":   &fstest.MapFile{Data: []byte("V1")},
+		"V1_1__second.sql":  &fstest.MapFile{Data: []byte("V1_1")},
+		"V2__third.sql":     &fstest.MapFile{Data: []byte("V2")},
+		"V10__fourth.sql":   &fstest.MapFile{Data: []byte("V10")},
+		"R__views.sql":      &fstest.MapFile{Data: []byte("R__views")},
+		"R__functions.sql":  &fstest.MapFile{Data: []byte("R__functions")},
+		"U10__fourth.sql":   &fstest.MapFile{Data: []byte("U10")},
+		"V9.0.1__fifth.sql": &fstest.MapFile{Data: []byte("V9.0.1")},
+	}
+	files, err := (&sqltool.FlywayDir{&fs}).Files()
+	require.NoError(t, err)
+	sqltool.SetRepeatableVersion(files)
+	versions := func() (vs [][2]string) {
+		for _, f := range files {
+			vs = append(vs, [2]string{f.Version(), f.Desc()})
+		}
+		return vs
+	}
+	require.Equal(t, [][2]string{
+		{"1", "initial"},
+		{"1_1", "second"},
+		{"2", "third"},
+		{"9.0.1", "fifth"},
+		{"10", "fourth"},
+		{"11", "functions"},
+		{"12", "views"},
+	}, versions())
+	require.Equal(t, "R__functions", string(files[5].Bytes()))
+
+	sqltool.SetSortableVersion(files)
+	require.Equal(t, [][2]string{
+		{"01.0.0", "initial"},
+		{"01.1.0", "second"},
+		{"02.0.0", "third"},
+		{"09.0.1", "fifth"},
+		{"10.0.0", "fourth"},
+		{"11.0.0", "functions"},
+		{"12.0.0", "views"},
+	}, versions())
+
+	// Versions that are already sortable are kept as is.
+	files = []migrate.File{
+		&sqltool.FlywayFile{LocalFile: migrate.NewLocalFile("V1__a.sql", nil)},
+		&sqltool.FlywayFile{LocalFile: migrate.NewLocalFile("V2__b.sql", nil)},
+	}
+	sqltool.SetRepeatableVersion(files)
+	sqltool.SetSortableVersion(files)
+	require.Equal(t, [][2]string{{"1", "a"}, {"2", "b"}}, versions())
+	require.Equal(t, "V1__a.sql", files[0].Name())
+}
+
+func TestLiquibaseDir_Changelog(t *testing.T) {
+	d, err := sqltool.NewLiquibaseDir(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, d.WriteFile("2_second.sql", []byte("--liquibase formatted sql\n")))
+	require.NoError(t, d.WriteFile("1_first.sql", []byte("--liquibase formatted sql\n")))
+	changelog := `<?xml version="1.0" encoding="UTF-8"?>
+<databaseChangeLog xmlns="http://www.liquibase.org/xml/ns/dbchangelog" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.liquibase.org/xml/ns/dbchangelog http://www.liquibase.org/xml/ns/dbchangelog/dbchangelog-latest.xsd">
+    <include file="1_first.sql" relativeToChangelogFile="true"></include>
+    <include file="2_second.sql" relativeToChangelogFile="true"></include>
+</databaseChangeLog>
+`
+	requireFileEqual(t, d, sqltool.LiquibaseChangelogFileName, changelog)
+
+	// Non-migration files do not affect the changelog, and it is not part of the sum file.
+	sum, err := d.Checksum()
+	require.NoError(t, err)
+	require.Len(t, sum, 2)
+	require.NoError(t, migrate.WriteSumFile(d, sum))
+	requireFileEqual(t, d, sqltool.LiquibaseChangelogFileName, changelog)
+	require.NoError(t, migrate.Validate(d))
+}
+
 func dir(t *testing.T) migrate.Dir {
 	p := t.TempDir()
 	d, err := migrate.NewLocalDir(p)
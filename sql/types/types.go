@@ -0,0 +1,103 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+// Package types provides a stable API for converting between database type
+// strings and their structured schema.Type representation, per dialect.
+//
+// Dialects are registered by their driver packages. Hence, the driver package
+// of the requested dialect must be imported in order to use it. For example:
+//
+//	import (
+//		"ariga.io/atlas/sql/types"
+//		_ "ariga.io/atlas/sql/postgres"
+//	)
+//
+//	t, err := types.Parse("postgres", "character varying(255)")
+//	// &schema.StringType{T: "character varying", Size: 255}
+//	s, err := types.Format("postgres", t)
+//	// "character varying(255)"
+package types
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// dialects registry.
+var dialects sync.Map
+
+// Register allows drivers to register their type parser and formatter under
+// the given dialect name. Registering the same name twice overrides the former.
+func Register(name string, tf schema.TypeParseFormatter) {
+	if tf == nil {
+		panic("types: Register called with nil TypeParseFormatter for " + name)
+	}
+	dialects.Store(name, tf)
+}
+
+// Lookup returns the type parser and formatter registered for the given dialect.
+func Lookup(name string) (schema.TypeParseFormatter, bool) {
+	tf, ok := dialects.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return tf.(schema.TypeParseFormatter), true
+}
+
+// Dialects returns the sorted names of all registered dialects.
+func Dialects() []string {
+	var names []string
+	dialects.Range(func(k, _ any) bool {
+		names = append(names, k.(string))
+		return true
+	})
+	sort.Strings(names)
+	return names
+}
+
+// Parse converts the raw database type to its schema.Type representation
+// using the parser registered for the given dialect.
+func Parse(dialect, typ string) (schema.Type, error) {
+	tf, err := lookup(dialect)
+	if err != nil {
+		return nil, err
+	}
+	return tf.ParseType(typ)
+}
+
+// Format converts the given schema type to its database form using the
+// formatter registered for the given dialect.
+func Format(dialect string, t schema.Type) (string, error) {
+	tf, err := lookup(dialect)
+	if err != nil {
+		return "", err
+	}
+	return tf.FormatType(t)
+}
+
+// Normalize parses the raw database type and formats it back to its
+// canonical form in the given dialect. For example, "INT4" is normalized
+// to "integer" in PostgreSQL.
+func Normalize(dialect, typ string) (string, error) {
+	tf, err := lookup(dialect)
+	if err != nil {
+		return "", err
+	}
+	t, err := tf.ParseType(typ)
+	if err != nil {
+		return "", err
+	}
+	return tf.FormatType(t)
+}
+
+func lookup(dialect string) (schema.TypeParseFormatter, error) {
+	tf, ok := Lookup(dialect)
+	if !ok {
+		return nil, fmt.Errorf("types: unknown dialect %q (forgotten import?)", dialect)
+	}
+	return tf, nil
+}
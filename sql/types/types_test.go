@@ -0,0 +1,41 @@
+// Copyright 2021-present The Atlas Authors. All rights reserved.
+// This source code is licensed under the Apache 2.0 license found
+// in the LICENSE file in the root directory of this source tree.
+
+package types_test
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/mysql"
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"ariga.io/atlas/sql/sqlite"
+	"ariga.io/atlas/sql/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	require.Equal(t, []string{mysql.DriverMaria, mysql.DriverName, postgres.DriverName, sqlite.DriverName}, types.Dialects())
+
+	typ, err := types.Parse(postgres.DriverName, "character varying(255)")
+	require.NoError(t, err)
+	require.Equal(t, &schema.StringType{T: "character varying", Size: 255}, typ)
+	s, err := types.Format(postgres.DriverName, typ)
+	require.NoError(t, err)
+	require.Equal(t, "character varying(255)", s)
+
+	typ, err = types.Parse(mysql.DriverName, "int unsigned")
+	require.NoError(t, err)
+	require.Equal(t, &schema.IntegerType{T: "int", Unsigned: true}, typ)
+
+	s, err = types.Normalize(postgres.DriverName, "int4")
+	require.NoError(t, err)
+	require.Equal(t, "integer", s)
+
+	_, err = types.Parse("unknown", "int")
+	require.EqualError(t, err, `types: unknown dialect "unknown" (forgotten import?)`)
+	_, ok := types.Lookup("unknown")
+	require.False(t, ok)
+}